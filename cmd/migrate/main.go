@@ -12,15 +12,12 @@ import (
 func main() {
 	cfg := config.MustLoad()
 
-	log := logger.New(cfg.Env)
+	log := logger.NewWithLevel(cfg.Log.SlogLevel())
 
 	command := flag.String("command", "up", "Migration command (up/down)")
 	flag.Parse()
 
-	dsn := "postgres://" + cfg.Database.Username + ":" + cfg.Database.Password + "@" +
-		cfg.Database.Host + ":" + cfg.Database.Port + "/" + cfg.Database.DbName + "?sslmode=disable"
-
-	m, err := migrator.NewMigrator(cfg.Database.MigrationsPath, dsn, log)
+	m, err := migrator.NewMigrator(cfg.Database.MigrationsPath, cfg.Database.DSN(), log)
 	if err != nil {
 		log.Error("Failed to create migrator", "error", err)
 		os.Exit(1)
@@ -2,59 +2,96 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	post_service "pinstack-post-service/internal/application/service/post"
+	model "pinstack-post-service/internal/domain/models"
+	post_ports "pinstack-post-service/internal/domain/ports/input/post"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	archive_repository "pinstack-post-service/internal/domain/ports/output/archive"
+	consistency_repository "pinstack-post-service/internal/domain/ports/output/consistency"
+	media_repository "pinstack-post-service/internal/domain/ports/output/media"
+	post_repository "pinstack-post-service/internal/domain/ports/output/post"
+	tag_repository "pinstack-post-service/internal/domain/ports/output/tag"
 	"pinstack-post-service/internal/infrastructure/config"
 	delivery_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc"
+	debug_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/debug"
 	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/inbound/health"
 	metrics_server "pinstack-post-service/internal/infrastructure/inbound/metrics"
 	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/migrator"
+	noop_cache "pinstack-post-service/internal/infrastructure/outbound/cache/noop"
 	redis_cache "pinstack-post-service/internal/infrastructure/outbound/cache/redis"
+	"pinstack-post-service/internal/infrastructure/outbound/cache/swap"
 	user_client "pinstack-post-service/internal/infrastructure/outbound/client/user"
 	prometheus_metrics "pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	archive_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/archive/postgres"
+	consistency_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/consistency/postgres"
 	media_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/media/postgres"
+	repository_memory "pinstack-post-service/internal/infrastructure/outbound/repository/memory"
 	post_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/post/postgres"
 	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
 	tag_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/tag/postgres"
 )
 
+// Set at build time with -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=...".
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
 func main() {
+	migrateOnly := flag.Bool("migrate", false, "apply pending database migrations and exit")
+	reconcileOrphans := flag.Bool("reconcile-orphans", false, "remove post_media and posts_tags rows left without their post or tag once on start")
+	reconcileDryRun := flag.Bool("reconcile-dry-run", false, "with -reconcile-orphans, only count the orphans")
+	flag.Parse()
+
 	cfg := config.MustLoad()
-	dsn := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable",
-		cfg.Database.Username,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.DbName)
 	ctx := context.Background()
-	log := logger.New(cfg.Env)
+	log := logger.NewWithLevel(cfg.Log.SlogLevel())
+	log.Info("Starting post service",
+		slog.String("version", version),
+		slog.String("commit", commit),
+		slog.String("build_date", buildDate))
+	log.Info("Effective config", slog.Any("config", cfg))
 
-	poolConfig, err := pgxpool.ParseConfig(dsn)
-	if err != nil {
-		log.Error("Failed to parse postgres poolConfig", slog.String("error", err.Error()))
-		os.Exit(1)
+	if *migrateOnly || cfg.Database.MigrateOnStart {
+		if cfg.Database.Driver == config.DriverMemory {
+			log.Warn("The in-memory database has no migrations to apply")
+		} else if err := applyMigrations(cfg.Database, log); err != nil {
+			log.Error("Failed to migrate the database, exiting", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		if *migrateOnly {
+			return
+		}
 	}
 
-	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if cfg.UserService.TLS.Insecure {
+		log.Warn("Connecting to the user service without TLS")
+	}
+	userServiceOpts, err := user_client.DialOptions(cfg.UserService)
 	if err != nil {
-		log.Error("Failed to create postgres pool", slog.String("error", err.Error()))
+		log.Error("Invalid user service TLS configuration", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
-	defer pool.Close()
-
 	userServiceConn, err := grpc.NewClient(
 		fmt.Sprintf("%s:%d", cfg.UserService.Address, cfg.UserService.Port),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		userServiceOpts...,
 	)
 	if err != nil {
 		log.Error("Failed to connect to user service", slog.String("error", err.Error()))
@@ -69,47 +106,255 @@ func main() {
 
 	userClient := user_client.NewUserClient(userServiceConn, log)
 
+	// Readiness covers every dependency a request may need; the metrics server reports it.
+	checker := health.NewChecker(health.DefaultCheckTimeout)
+	checker.Add("user_service", health.ConnCheck(userServiceConn))
+
+	metrics := prometheus_metrics.NewPrometheusMetricsProvider()
+
+	metrics.SetServiceHealth(true)
+	poolStats := prometheus_metrics.NewPoolStatsCollector(metrics, cfg.Prometheus.PoolStatsInterval)
+
+	userCache := swap.NewUserCache(noop_cache.NewUserCache())
+	postCache := swap.NewPostCache(noop_cache.NewPostCache())
+	cacheBatcher := swap.NewBatcher(noop_cache.NewBatcher())
+	rateLimiter := swap.NewRateLimiter(noop_cache.NewRateLimiter())
+	eventPublisher := swap.NewEventPublisher(noop_cache.NewEventPublisher())
+	var redisClient atomic.Pointer[redis_cache.Client]
+	poolStats.Add("redis", func() prometheus_metrics.PoolStats {
+		client := redisClient.Load()
+		if client == nil {
+			return prometheus_metrics.PoolStats{}
+		}
+		stats := client.PoolStats()
+		return prometheus_metrics.PoolStats{
+			Acquired: int(stats.TotalConns - stats.IdleConns),
+			Idle:     int(stats.IdleConns),
+			Total:    int(stats.TotalConns),
+		}
+	})
+	// Deferred closes run last, after the gRPC drain and the workers have stopped.
+	defer func() {
+		if client := redisClient.Load(); client != nil {
+			if err := client.Close(); err != nil {
+				log.Error("Failed to close Redis connection", slog.String("error", err.Error()))
+			}
+		}
+	}()
+
+	// Background work shares workersCtx; shutdown cancels it and waits for every worker to
+	// return before the pools close.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	runWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workersCtx)
+		}()
+	}
+
+	// useRedis moves caching and rate limiting from the noop implementations to Redis.
+	useRedis := func(client *redis_cache.Client) {
+		userCache.Swap(redis_cache.NewUserCache(client, cfg.Cache, log, metrics))
+		postCache.Swap(redis_cache.NewPostCache(client, cfg.Cache, log, metrics))
+		cacheBatcher.Swap(redis_cache.NewBatcher(client, cfg.Cache, log, metrics))
+		rateLimiter.Swap(redis_cache.NewRateLimiter(client, cfg.Cache, log, metrics))
+		if cfg.Events.Enabled {
+			eventPublisher.Swap(redis_cache.NewEventPublisher(client, cfg.Events))
+		}
+		redisClient.Store(client)
+		metrics.SetCacheAvailable(true)
+		runWorker(redis_cache.NewKeyCountCollector(client, cfg.Cache, log, metrics).Run)
+	}
+
 	log.Info("Connecting to Redis",
 		slog.String("address", cfg.Redis.Address),
 		slog.Int("port", cfg.Redis.Port),
 		slog.Int("db", cfg.Redis.DB))
-	redisClient, err := redis_cache.NewClient(cfg.Redis, log)
+	client, err := redis_cache.NewClient(cfg.Redis, log, metrics)
 	if err != nil {
-		log.Error("Failed to create Redis client", slog.String("error", err.Error()))
-		os.Exit(1)
+		// Redis is an optimization: keep serving from Postgres with caching and rate limiting
+		// disabled, and switch them on once Redis answers. Invalidations made in between are
+		// lost, so entries Redis already held can be stale for up to their TTL.
+		log.Warn("Redis unavailable, serving without cache and rate limiting until it answers",
+			slog.Duration("retry_interval", cfg.Redis.ReconnectInterval),
+			slog.String("error", err.Error()))
+		metrics.SetCacheAvailable(false)
+		runWorker(func(ctx context.Context) {
+			if client := redis_cache.Reconnect(ctx, cfg.Redis, log, metrics); client != nil {
+				log.Info("Redis available, enabling cache and rate limiting")
+				useRedis(client)
+			}
+		})
+	} else {
+		useRedis(client)
 	}
-	defer func() {
-		if err := redisClient.Close(); err != nil {
-			log.Error("Failed to close Redis connection", slog.String("error", err.Error()))
+	checker.Add("redis", func(ctx context.Context) error {
+		client := redisClient.Load()
+		if client == nil {
+			return errors.New("not connected")
 		}
-	}()
+		return client.Ping(ctx)
+	})
 
-	metrics := prometheus_metrics.NewPrometheusMetricsProvider()
-
-	metrics.SetServiceHealth(true)
+	var (
+		unitOfWork      postgres.UnitOfWork
+		postRepo        post_repository.Repository
+		tagRepo         tag_repository.Repository
+		mediaRepo       media_repository.Repository
+		archiveRepo     archive_repository.Repository
+		consistencyRepo consistency_repository.Repository
+	)
+	// The debug RPC reports the schema version; the in-memory database has none.
+	var schemaVersion debug_grpc.SchemaVersionFunc
+	// The repositories sample their Debug logs and warn about slow queries.
+	queryLog := db.NewQueryLogger(log, cfg.Database.SlowQueryThreshold, cfg.Database.DebugLogSampleRate)
+	if cfg.Database.Driver == config.DriverMemory {
+		log.Warn("Using the in-memory database: data is lost on restart")
+		database := repository_memory.NewDatabase(queryLog)
+		unitOfWork = database.UnitOfWork
+		postRepo = database.Posts
+		tagRepo = database.Tags
+		mediaRepo = database.Media
+		archiveRepo = repository_memory.NewArchiveRepository(queryLog)
+		consistencyRepo = repository_memory.NewConsistencyRepository()
+	} else {
+		log.Info("Connecting to Postgres",
+			slog.String("host", cfg.Database.Host),
+			slog.String("port", cfg.Database.Port),
+			slog.String("db_name", cfg.Database.DbName))
+		pool, err := db.Connect(ctx, cfg.Database, log)
+		if err != nil {
+			log.Error("Postgres unavailable, exiting", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer pool.Close()
+		checker.Add("postgres", pool.Ping)
+		latestSchema, err := migrator.Latest()
+		if err != nil {
+			log.Error("Failed to read embedded migrations", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		schemaVersion = func(ctx context.Context) (migrator.Version, error) {
+			return migrator.ReadVersion(ctx, pool)
+		}
+		// A release must not serve on a schema its migrations have not reached yet.
+		checker.Add("schema", func(ctx context.Context) error {
+			version, err := schemaVersion(ctx)
+			if err != nil {
+				return err
+			}
+			return version.Check(latestSchema)
+		})
+		poolStats.Add("postgres", func() prometheus_metrics.PoolStats {
+			stats := pool.Stat()
+			return prometheus_metrics.PoolStats{
+				Acquired: int(stats.AcquiredConns()),
+				Idle:     int(stats.IdleConns()),
+				Total:    int(stats.TotalConns()),
+			}
+		})
 
-	userCache := redis_cache.NewUserCache(redisClient, log, metrics)
-	postCache := redis_cache.NewPostCache(redisClient, log, metrics)
+		queryDB := db.WithTimeout(pool, cfg.Database.QueryTimeout)
+		batchLimits := db.BatchLimits{
+			MaxTags:   cfg.Database.Batch.MaxTags,
+			MaxMedia:  cfg.Database.Batch.MaxMedia,
+			MaxDetach: cfg.Database.Batch.MaxDetach,
+		}
+		unitOfWork = postgres.NewPostgresUOW(pool, queryLog, metrics, cfg.Database.QueryTimeout, batchLimits, cfg.Database.TagLockTimeout)
+		postRepo = post_postgres.NewPostRepository(queryDB, queryLog, metrics)
+		tagRepo = tag_postgres.NewTagRepository(queryDB, queryLog, metrics, batchLimits, cfg.Database.TagLockTimeout)
+		mediaRepo = media_postgres.NewMediaRepository(queryDB, queryLog, metrics, batchLimits)
+		archiveRepo = archive_postgres.NewArchiveRepository(queryDB, queryLog, metrics)
+		consistencyRepo = consistency_postgres.NewConsistencyRepository(queryDB, queryLog, metrics)
+	}
 
-	unitOfWork := postgres.NewPostgresUOW(pool, log, metrics)
-	postRepo := post_postgres.NewPostRepository(pool, log, metrics)
-	tagRepo := tag_postgres.NewTagRepository(pool, log, metrics)
-	mediaRepo := media_postgres.NewMediaRepository(pool, log, metrics)
+	// The service asks for a requester's blocked authors on every feed page; cache them briefly.
+	blockCachingUserClient := user_client.NewCachedClient(userClient, userCache, log)
+	originalPostService := post_service.NewPostService(postRepo, tagRepo, mediaRepo, unitOfWork, log, blockCachingUserClient, metrics, model.PostLimits{
+		MaxContentLength:     cfg.Post.MaxContentLength,
+		MaxTags:              cfg.Post.MaxTags,
+		MaxFeedAuthors:       cfg.Post.MaxFeedAuthors,
+		HydrationConcurrency: cfg.Post.HydrationConcurrency,
+		DefaultListLimit:     cfg.Post.DefaultListLimit,
+		MaxListLimit:         cfg.Post.MaxListLimit,
+		ExcerptLength:        cfg.Post.ExcerptLength,
+		MediaHosts:           cfg.Post.MediaHostAllowlist(),
+		Languages:            cfg.Post.LanguageAllowlist(),
+		Sources:              cfg.Post.SourceAllowlist(),
+		MaxRevisions:         cfg.Post.MaxRevisions,
+		MaxEmbeddedMedia:     cfg.Post.MaxEmbeddedMedia,
+	})
+	var snapshotRefresher *post_service.AuthorSnapshotRefresher
+	if cfg.AuthorSnapshot.ReadAuthorFromSnapshot {
+		snapshots := post_service.NewAuthorSnapshots(cfg.AuthorSnapshot.StaleAfter)
+		originalPostService.ReadAuthorsFromSnapshots(snapshots)
+		snapshotRefresher = post_service.NewAuthorSnapshotRefresher(postRepo, userClient, snapshots,
+			cfg.AuthorSnapshot.RefreshBatchSize, cfg.AuthorSnapshot.RefreshInterval, log, metrics)
+	}
 
-	originalPostService := post_service.NewPostService(postRepo, tagRepo, mediaRepo, unitOfWork, log, userClient, metrics)
+	var storedPostService post_ports.Service = originalPostService
+	if cfg.Archive.ReadFallback {
+		// Below the cache, so an archived post is read from the archive once and then from Redis.
+		storedPostService = post_service.NewPostServiceArchiveDecorator(originalPostService, archiveRepo, userClient, log, metrics)
+	}
 
-	postService := post_service.NewPostServiceCacheDecorator(
-		originalPostService,
+	cacheDecorator := post_service.NewPostServiceCacheDecorator(
+		storedPostService,
 		userCache,
 		postCache,
+		cacheBatcher,
 		log,
 		metrics,
 	)
+	// The queue runs as a worker, so its flush on shutdown happens before Redis closes.
+	invalidationQueue := post_service.NewInvalidationQueue(postCache, cfg.Cache.Invalidation.QueueSize,
+		cfg.Cache.Invalidation.Retries, cfg.Cache.Invalidation.RetryBackoff, cfg.Cache.Invalidation.FlushTimeout, log, metrics)
+	cacheDecorator.InvalidateInBackground(invalidationQueue)
+	runWorker(invalidationQueue.Run)
+	var postService post_ports.Service = cacheDecorator
+
+	if cfg.Events.Enabled {
+		// Above the cache, so a subscriber reading the post on an event finds no stale entry.
+		postService = post_service.NewPostServiceEventDecorator(postService, eventPublisher, log, metrics)
+	}
+
+	if cfg.RateLimit.Enabled {
+		postService = post_service.NewPostServiceRateLimitDecorator(
+			postService,
+			rateLimiter,
+			post_service.RateLimitRules{
+				CreatePost:      model.RateLimitRule{Limit: cfg.RateLimit.CreatePost.Limit, Window: cfg.RateLimit.CreatePost.Window},
+				BulkCreatePosts: model.RateLimitRule{Limit: cfg.RateLimit.BulkCreatePosts.Limit, Window: cfg.RateLimit.BulkCreatePosts.Window},
+				UpdatePost:      model.RateLimitRule{Limit: cfg.RateLimit.UpdatePost.Limit, Window: cfg.RateLimit.UpdatePost.Window},
+				DeletePost:      model.RateLimitRule{Limit: cfg.RateLimit.DeletePost.Limit, Window: cfg.RateLimit.DeletePost.Window},
+			},
+			log,
+			metrics,
+		)
+	}
+
+	orphanReconciler := post_service.NewOrphanReconciler(consistencyRepo, cfg.Orphans.BatchSize, cfg.Orphans.Pause, log, metrics)
 
 	postGRPCApi := post_grpc.NewPostGRPCService(postService, log)
-	grpcServer := delivery_grpc.NewServer(postGRPCApi, cfg.GRPCServer.Address, cfg.GRPCServer.Port, log, metrics)
+	if cfg.GRPCServer.TLS.Insecure {
+		log.Warn("Serving gRPC without TLS")
+	}
+	serverOpts, err := delivery_grpc.ServerOptions(cfg.GRPCServer)
+	if err != nil {
+		log.Error("Invalid gRPC server TLS configuration", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	grpcServer := delivery_grpc.NewServer(postGRPCApi, cfg.GRPCServer.Address, cfg.GRPCServer.Port, log, metrics, serverOpts...)
+	grpcServer.RegisterDebug(debug_grpc.NewService(cfg, debug_grpc.BuildInfo{Version: version, Commit: commit, BuildDate: buildDate}, schemaVersion, orphanReconciler, log))
+	if cfg.GRPCServer.Reflection {
+		log.Info("Serving gRPC reflection")
+		grpcServer.EnableReflection()
+	}
 
-	metricsServer := metrics_server.NewMetricsServer(cfg.Prometheus.Address, cfg.Prometheus.Port, log)
+	metricsServer := metrics_server.NewMetricsServer(cfg.Prometheus.Address, cfg.Prometheus.Port, checker, log)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -117,6 +362,50 @@ func main() {
 	done := make(chan bool, 1)
 	metricsDone := make(chan bool, 1)
 
+	if cfg.Cache.Warmup.Enabled && redisClient.Load() != nil {
+		warmer := post_service.NewCacheWarmer(originalPostService, cacheBatcher, cfg.Cache.Warmup.Posts, cfg.Cache.Warmup.Timeout, log, metrics)
+		runWorker(func(ctx context.Context) {
+			if _, err := warmer.Warm(ctx); err != nil {
+				log.Warn("Cache warm-up failed", slog.String("error", err.Error()))
+			}
+		})
+	}
+
+	runWorker(poolStats.Run)
+	if *reconcileOrphans {
+		runWorker(func(ctx context.Context) {
+			if _, err := orphanReconciler.ReconcileOrphans(ctx, *reconcileDryRun); err != nil && ctx.Err() == nil {
+				log.Warn("Orphan reconciliation failed", slog.String("error", err.Error()))
+			}
+		})
+	}
+	// SIGHUP logs at debug level for a while, to look into a production instance without a restart.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	runWorker(func(ctx context.Context) {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				log.Level().DebugFor(cfg.Log.DebugDuration)
+				log.Info("Logging at debug level after SIGHUP", slog.Duration("duration", cfg.Log.DebugDuration))
+			}
+		}
+	})
+	if cfg.Archive.Enabled {
+		archiver := post_service.NewPostArchiver(unitOfWork, cfg.Archive.Retention, cfg.Archive.BatchSize, cfg.Archive.Interval, log, metrics)
+		runWorker(archiver.Run)
+	}
+	if cfg.Scheduler.Enabled {
+		scheduler := post_service.NewPostScheduler(unitOfWork, cacheBatcher, cfg.Scheduler.BatchSize, cfg.Scheduler.Interval, log, metrics)
+		runWorker(scheduler.Run)
+	}
+	if snapshotRefresher != nil {
+		runWorker(snapshotRefresher.Run)
+	}
+
 	go func() {
 		if err := grpcServer.Run(); err != nil {
 			log.Error("gRPC server error", slog.String("error", err.Error()))
@@ -132,23 +421,49 @@ func main() {
 	}()
 
 	<-quit
-	log.Info("Shutting down servers...")
+	log.Info("Shutting down servers...", slog.Duration("drain_timeout", cfg.GRPCServer.DrainTimeout))
 
+	// Readiness fails first so traffic moves away, then new requests are refused; the
+	// in-flight ones finish with the pools still open.
+	checker.SetShuttingDown()
 	metrics.SetServiceHealth(false)
-
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer shutdownCancel()
-
-	if err := grpcServer.Shutdown(); err != nil {
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), cfg.GRPCServer.DrainTimeout)
+	defer drainCancel()
+	if err := grpcServer.Shutdown(drainCtx); err != nil {
 		log.Error("gRPC server shutdown error", slog.String("error", err.Error()))
 	}
+	<-done
+
+	// Then the background workers, which may be inside a transaction of their own.
+	stopWorkers()
+	workers.Wait()
 
+	// Metrics go last so the drain can still be scraped.
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
 	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
 		log.Error("Metrics server shutdown error", slog.String("error", err.Error()))
 	}
-
-	<-done
 	<-metricsDone
 
 	log.Info("Server exited")
 }
+
+// applyMigrations brings the database schema up to the latest migration.
+func applyMigrations(cfg config.Database, log ports.Logger) error {
+	m, err := migrator.NewMigrator(cfg.MigrationsPath, cfg.DSN(), log)
+	if err != nil {
+		return fmt.Errorf("creating migrator: %w", err)
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil {
+		return err
+	}
+	version, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	log.Info("Database schema is up to date", slog.Uint64("version", uint64(version.Version)))
+	return nil
+}
@@ -0,0 +1,242 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_service "pinstack-post-service/internal/domain/ports/input/post"
+	output "pinstack-post-service/internal/domain/ports/output"
+	archive_repository "pinstack-post-service/internal/domain/ports/output/archive"
+	user_client "pinstack-post-service/internal/domain/ports/output/user"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// PostServiceArchiveDecorator keeps permalinks of archived posts working: GetPostByID looks
+// in the archive tables when a post is not in the posts table, and ExportAuthorPosts exports
+// archived posts too. Every other method sees only the hot tables.
+type PostServiceArchiveDecorator struct {
+	service    post_service.Service
+	archive    archive_repository.Repository
+	userClient user_client.Client
+	log        output.Logger
+	metrics    output.MetricsProvider
+}
+
+func NewPostServiceArchiveDecorator(
+	service post_service.Service,
+	archive archive_repository.Repository,
+	userClient user_client.Client,
+	log output.Logger,
+	metrics output.MetricsProvider,
+) post_service.Service {
+	return &PostServiceArchiveDecorator{
+		service:    service,
+		archive:    archive,
+		userClient: userClient,
+		log:        log,
+		metrics:    metrics,
+	}
+}
+
+func (d *PostServiceArchiveDecorator) GetPostByID(ctx context.Context, id int64, requesterID *int64) (*model.PostDetailed, error) {
+	post, err := d.service.GetPostByID(ctx, id, requesterID)
+	if !errors.Is(err, custom_errors.ErrPostNotFound) {
+		return post, err
+	}
+	return d.getArchivedPost(ctx, id, requesterID)
+}
+
+// getArchivedPost serves a post the service no longer has from the archive.
+func (d *PostServiceArchiveDecorator) getArchivedPost(ctx context.Context, id int64, requesterID *int64) (_ *model.PostDetailed, err error) {
+	defer observePostOperation(d.metrics, "get_archived", time.Now(), &err)
+	archived, err := d.archive.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrPostNotFound) {
+			return nil, custom_errors.ErrPostNotFound
+		}
+		d.log.Error("Failed to get archived post", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	if err := archived.Post.CheckAccess(requesterID); err != nil {
+		d.log.Debug("Archived post is hidden from requester", slog.Int64("id", id), slog.Any("requesterID", requesterID), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	archived.Author, err = d.userClient.GetUser(ctx, archived.Post.AuthorID)
+	if err != nil {
+		if !errors.Is(err, custom_errors.ErrUserNotFound) {
+			d.log.Error("Failed to get author", slog.Int64("authorID", archived.Post.AuthorID), slog.String("error", err.Error()))
+			return nil, custom_errors.ErrExternalServiceError
+		}
+		archived.Author = nil
+	}
+	if archived.Media == nil {
+		archived.Media = []*model.PostMedia{}
+	}
+	if archived.Tags == nil {
+		archived.Tags = []*model.Tag{}
+	}
+
+	d.log.Debug("Served post from archive", slog.Int64("id", id))
+	return archived.RedactedFor(requesterID), nil
+}
+
+func (d *PostServiceArchiveDecorator) CreatePost(ctx context.Context, post *model.CreatePostDTO) (*model.PostDetailed, error) {
+	return d.service.CreatePost(ctx, post)
+}
+
+func (d *PostServiceArchiveDecorator) BulkCreatePosts(ctx context.Context, actorID int64, posts []*model.CreatePostDTO) (*model.BulkCreateResult, error) {
+	return d.service.BulkCreatePosts(ctx, actorID, posts)
+}
+
+func (d *PostServiceArchiveDecorator) ListPosts(ctx context.Context, filters *model.PostFilters) ([]*model.PostDetailed, int, error) {
+	return d.service.ListPosts(ctx, filters)
+}
+
+// GetPostsByIDs serves hot posts only; batch callers hydrate recent activity.
+func (d *PostServiceArchiveDecorator) GetPostsByIDs(ctx context.Context, ids []int64) ([]*model.PostDetailed, error) {
+	return d.service.GetPostsByIDs(ctx, ids)
+}
+
+func (d *PostServiceArchiveDecorator) UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) (*model.PostDetailed, error) {
+	return d.service.UpdatePost(ctx, userID, id, post)
+}
+
+func (d *PostServiceArchiveDecorator) DeletePost(ctx context.Context, userID int64, id int64) error {
+	return d.service.DeletePost(ctx, userID, id)
+}
+
+func (d *PostServiceArchiveDecorator) ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (*model.ModerationAction, error) {
+	return d.service.ForceDeletePost(ctx, actorID, postID, reason)
+}
+
+func (d *PostServiceArchiveDecorator) SetModerationStatus(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason) (*model.Post, error) {
+	return d.service.SetModerationStatus(ctx, actorID, postID, status, reason)
+}
+
+func (d *PostServiceArchiveDecorator) PublishPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	return d.service.PublishPost(ctx, userID, id)
+}
+
+func (d *PostServiceArchiveDecorator) CancelScheduledPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	return d.service.CancelScheduledPost(ctx, userID, id)
+}
+
+func (d *PostServiceArchiveDecorator) PinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	return d.service.PinPost(ctx, userID, id)
+}
+
+func (d *PostServiceArchiveDecorator) UnpinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	return d.service.UnpinPost(ctx, userID, id)
+}
+
+func (d *PostServiceArchiveDecorator) GetPostRevisions(ctx context.Context, userID int64, postID int64, limit, offset int) ([]*model.PostRevision, int, error) {
+	return d.service.GetPostRevisions(ctx, userID, postID, limit, offset)
+}
+
+func (d *PostServiceArchiveDecorator) GetPostTags(ctx context.Context, postID int64, requesterID *int64, includeCounts bool) ([]*model.Tag, error) {
+	return d.service.GetPostTags(ctx, postID, requesterID, includeCounts)
+}
+
+func (d *PostServiceArchiveDecorator) GetPostMedia(ctx context.Context, postID int64, requesterID *int64, limit, offset int) ([]*model.PostMedia, int, error) {
+	return d.service.GetPostMedia(ctx, postID, requesterID, limit, offset)
+}
+
+// SuggestTags ranks tags by their use on hot posts; archived posts no longer count.
+func (d *PostServiceArchiveDecorator) SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error) {
+	return d.service.SuggestTags(ctx, prefix, limit, authorID)
+}
+
+func (d *PostServiceArchiveDecorator) RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error) {
+	return d.service.RenameTag(ctx, tagID, name)
+}
+
+func (d *PostServiceArchiveDecorator) MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error) {
+	return d.service.MergeTags(ctx, sourceIDs, destID)
+}
+
+// ExportAuthorPosts exports the author's hot posts, then their archived ones, each in ascending
+// id order. Posts only ever move into the archive, so one archived mid-export is sent twice
+// rather than missed.
+func (d *PostServiceArchiveDecorator) ExportAuthorPosts(ctx context.Context, authorID int64, send func(*model.PostDetailed) error) error {
+	if err := d.service.ExportAuthorPosts(ctx, authorID, send); err != nil {
+		return err
+	}
+	return d.exportArchivedPosts(ctx, authorID, send)
+}
+
+// exportArchivedPosts pages through the author's archived posts like PostService does through
+// the hot ones. The author is looked up only if there is an archived post to send.
+func (d *PostServiceArchiveDecorator) exportArchivedPosts(ctx context.Context, authorID int64, send func(*model.PostDetailed) error) (err error) {
+	defer observePostOperation(d.metrics, "export_archived", time.Now(), &err)
+
+	var (
+		author       *model.User
+		authorLoaded bool
+		afterID      int64
+		exported     int
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			d.log.Debug("Archived export stopped", slog.Int64("authorID", authorID), slog.Int("exported", exported), slog.String("reason", err.Error()))
+			return err
+		}
+
+		posts, err := d.archive.GetByAuthorAfter(ctx, authorID, afterID, exportBatchSize)
+		if err != nil {
+			d.log.Error("Failed to get archived posts for export", slog.Int64("authorID", authorID), slog.Int64("afterID", afterID), slog.String("error", err.Error()))
+			return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		if len(posts) == 0 {
+			break
+		}
+		if !authorLoaded {
+			author, err = d.userClient.GetUser(ctx, authorID)
+			if err != nil {
+				if !errors.Is(err, custom_errors.ErrUserNotFound) {
+					d.log.Error("Failed to get author for export", slog.Int64("authorID", authorID), slog.String("error", err.Error()))
+					return custom_errors.ErrExternalServiceError
+				}
+				author = nil
+			}
+			authorLoaded = true
+		}
+
+		sent := 0
+		for _, post := range posts {
+			post.Author = author
+			if err := send(post); err != nil {
+				d.metrics.AddExportedPosts(sent)
+				return err
+			}
+			sent++
+		}
+		d.metrics.AddExportedPosts(sent)
+		exported += sent
+
+		if len(posts) < exportBatchSize {
+			break
+		}
+		afterID = posts[len(posts)-1].Post.ID
+	}
+
+	if exported > 0 {
+		d.log.Info("Exported archived author posts", slog.Int64("authorID", authorID), slog.Int("count", exported))
+	}
+	return nil
+}
+
+// GetAuthorPostCount counts hot posts only, matching what ListPosts returns for the author.
+func (d *PostServiceArchiveDecorator) GetAuthorPostCount(ctx context.Context, authorID int64) (*model.AuthorPostCount, error) {
+	return d.service.GetAuthorPostCount(ctx, authorID)
+}
+
+// HasPostsSince looks at hot posts only; an archived post is never new.
+func (d *PostServiceArchiveDecorator) HasPostsSince(ctx context.Context, tagNames []string, since time.Time) (*model.TagActivity, error) {
+	return d.service.HasPostsSince(ctx, tagNames, since)
+}
@@ -0,0 +1,155 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	archive_repository_mock "pinstack-post-service/mocks/archive"
+	post_repository_mock "pinstack-post-service/mocks/post"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+func TestPostServiceArchiveDecorator_GetPostByID(t *testing.T) {
+	archived := func() *model.PostDetailed {
+		return &model.PostDetailed{Post: &model.Post{ID: 9, AuthorID: 1, Title: "Old", Status: model.PostStatusPublished}}
+	}
+
+	tests := []struct {
+		name        string
+		mocks       func(service *post_repository_mock.Service, archiveRepo *archive_repository_mock.Repository, userClient *user_client_mock.Client)
+		requesterID *int64
+		wantTitle   string
+		wantErr     error
+	}{
+		{
+			name: "hot post is returned without touching the archive",
+			mocks: func(service *post_repository_mock.Service, archiveRepo *archive_repository_mock.Repository, userClient *user_client_mock.Client) {
+				service.On("GetPostByID", mock.Anything, int64(9), (*int64)(nil)).Return(&model.PostDetailed{Post: &model.Post{ID: 9, Title: "Hot"}}, nil)
+			},
+			wantTitle: "Hot",
+		},
+		{
+			name: "archived post is served when not in the hot table",
+			mocks: func(service *post_repository_mock.Service, archiveRepo *archive_repository_mock.Repository, userClient *user_client_mock.Client) {
+				service.On("GetPostByID", mock.Anything, int64(9), (*int64)(nil)).Return(nil, custom_errors.ErrPostNotFound)
+				archiveRepo.On("GetByID", mock.Anything, int64(9)).Return(archived(), nil)
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+			},
+			wantTitle: "Old",
+		},
+		{
+			name: "missing from both tables",
+			mocks: func(service *post_repository_mock.Service, archiveRepo *archive_repository_mock.Repository, userClient *user_client_mock.Client) {
+				service.On("GetPostByID", mock.Anything, int64(9), (*int64)(nil)).Return(nil, custom_errors.ErrPostNotFound)
+				archiveRepo.On("GetByID", mock.Anything, int64(9)).Return(nil, custom_errors.ErrPostNotFound)
+			},
+			wantErr: custom_errors.ErrPostNotFound,
+		},
+		{
+			name: "archived draft stays hidden from others",
+			mocks: func(service *post_repository_mock.Service, archiveRepo *archive_repository_mock.Repository, userClient *user_client_mock.Client) {
+				service.On("GetPostByID", mock.Anything, int64(9), mock.Anything).Return(nil, custom_errors.ErrPostNotFound)
+				draft := archived()
+				draft.Post.Status = model.PostStatusDraft
+				archiveRepo.On("GetByID", mock.Anything, int64(9)).Return(draft, nil)
+			},
+			requesterID: func(i int64) *int64 { return &i }(2),
+			wantErr:     custom_errors.ErrPostNotFound,
+		},
+		{
+			name: "other errors skip the archive",
+			mocks: func(service *post_repository_mock.Service, archiveRepo *archive_repository_mock.Repository, userClient *user_client_mock.Client) {
+				service.On("GetPostByID", mock.Anything, int64(9), (*int64)(nil)).Return(nil, custom_errors.ErrDatabaseQuery)
+			},
+			wantErr: custom_errors.ErrDatabaseQuery,
+		},
+		{
+			name: "archive query fails",
+			mocks: func(service *post_repository_mock.Service, archiveRepo *archive_repository_mock.Repository, userClient *user_client_mock.Client) {
+				service.On("GetPostByID", mock.Anything, int64(9), (*int64)(nil)).Return(nil, custom_errors.ErrPostNotFound)
+				archiveRepo.On("GetByID", mock.Anything, int64(9)).Return(nil, errors.New("connection reset"))
+			},
+			wantErr: custom_errors.ErrDatabaseQuery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_repository_mock.Service)
+			archiveRepo := new(archive_repository_mock.Repository)
+			userClient := new(user_client_mock.Client)
+			tt.mocks(service, archiveRepo, userClient)
+			d := NewPostServiceArchiveDecorator(service, archiveRepo, userClient, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			got, err := d.GetPostByID(context.Background(), 9, tt.requesterID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantTitle, got.Post.Title)
+			}
+			service.AssertExpectations(t)
+			archiveRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostServiceArchiveDecorator_ExportAuthorPosts(t *testing.T) {
+	service := new(post_repository_mock.Service)
+	archiveRepo := new(archive_repository_mock.Repository)
+	userClient := new(user_client_mock.Client)
+	author := &model.User{ID: 1, Username: "author"}
+	service.On("ExportAuthorPosts", mock.Anything, int64(1), mock.Anything).Run(func(args mock.Arguments) {
+		send := args.Get(2).(func(*model.PostDetailed) error)
+		require.NoError(t, send(&model.PostDetailed{Post: &model.Post{ID: 20, AuthorID: 1, Title: "Hot"}, Author: author}))
+	}).Return(nil)
+	archiveRepo.On("GetByAuthorAfter", mock.Anything, int64(1), int64(0), exportBatchSize).Return([]*model.PostDetailed{{
+		Post:  &model.Post{ID: 3, AuthorID: 1, Title: "Archived", Version: 4},
+		Media: []*model.PostMedia{{ID: 7, PostID: 3, URL: "https://example.com/old.jpg"}},
+		Tags:  []*model.Tag{{ID: 2, Name: "go"}},
+	}}, nil)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(author, nil)
+	d := NewPostServiceArchiveDecorator(service, archiveRepo, userClient, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	var exported []*model.PostDetailed
+	err := d.ExportAuthorPosts(context.Background(), 1, func(post *model.PostDetailed) error {
+		exported = append(exported, post)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, exported, 2)
+	assert.Equal(t, "Hot", exported[0].Post.Title)
+	archived := exported[1]
+	assert.Equal(t, "Archived", archived.Post.Title)
+	assert.Equal(t, int64(4), archived.Post.Version)
+	assert.Equal(t, author, archived.Author)
+	require.Len(t, archived.Media, 1)
+	assert.Equal(t, "https://example.com/old.jpg", archived.Media[0].URL)
+	require.Len(t, archived.Tags, 1)
+	assert.Equal(t, "go", archived.Tags[0].Name)
+	service.AssertExpectations(t)
+	archiveRepo.AssertExpectations(t)
+}
+
+func TestPostServiceArchiveDecorator_ExportAuthorPosts_StopsOnHotError(t *testing.T) {
+	service := new(post_repository_mock.Service)
+	archiveRepo := new(archive_repository_mock.Repository)
+	service.On("ExportAuthorPosts", mock.Anything, int64(1), mock.Anything).Return(custom_errors.ErrDatabaseQuery)
+	d := NewPostServiceArchiveDecorator(service, archiveRepo, new(user_client_mock.Client), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	err := d.ExportAuthorPosts(context.Background(), 1, func(*model.PostDetailed) error { return nil })
+
+	assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	archiveRepo.AssertNotCalled(t, "GetByAuthorAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
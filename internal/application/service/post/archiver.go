@@ -0,0 +1,123 @@
+package post_service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// PostArchiver periodically moves posts older than the retention period, with their media
+// and tags, from the hot tables to the archive tables.
+type PostArchiver struct {
+	uow       postgres.UnitOfWork
+	retention time.Duration
+	batchSize int
+	interval  time.Duration
+	log       output.Logger
+	metrics   output.MetricsProvider
+	now       func() time.Time
+}
+
+func NewPostArchiver(
+	uow postgres.UnitOfWork,
+	retention time.Duration,
+	batchSize int,
+	interval time.Duration,
+	log output.Logger,
+	metrics output.MetricsProvider,
+) *PostArchiver {
+	return &PostArchiver{
+		uow:       uow,
+		retention: retention,
+		batchSize: batchSize,
+		interval:  interval,
+		log:       log,
+		metrics:   metrics,
+		now:       time.Now,
+	}
+}
+
+// Run archives once right away and then once per interval until ctx is done. A failed run
+// is logged and retried at the next interval.
+func (a *PostArchiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.ArchiveOnce(ctx); err != nil && ctx.Err() == nil {
+			a.log.Warn("Post archive run failed", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ArchiveOnce moves every post created before now minus the retention period, one batch per
+// transaction, and returns how many it moved. Batches committed before an error stay archived.
+func (a *PostArchiver) ArchiveOnce(ctx context.Context) (int, error) {
+	start := time.Now()
+	defer func() {
+		a.metrics.RecordArchiveRunDuration(time.Since(start))
+	}()
+
+	cutoff := a.now().Add(-a.retention)
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		archived, err := a.archiveBatch(ctx, cutoff)
+		if err != nil {
+			return total, err
+		}
+		a.metrics.AddArchivedPosts(archived)
+		total += archived
+
+		if archived < a.batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		a.log.Info("Archived posts",
+			slog.Int("count", total),
+			slog.Time("cutoff", cutoff),
+			slog.Duration("duration", time.Since(start)))
+	}
+	return total, nil
+}
+
+func (a *PostArchiver) archiveBatch(ctx context.Context, cutoff time.Time) (int, error) {
+	tx, err := a.uow.Begin(ctx)
+	if err != nil {
+		a.log.Error("Failed to start archive transaction", slog.String("error", err.Error()))
+		return 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	var txCommitted bool
+	defer func() {
+		if !txCommitted {
+			rollbackTx(ctx, tx, a.log)
+		}
+	}()
+
+	archived, err := tx.ArchiveRepository().ArchiveOlderThan(ctx, cutoff, a.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	if err := commitTx(ctx, tx, a.log, "Failed to commit archive transaction"); err != nil {
+		return 0, err
+	}
+	txCommitted = true
+	return archived, nil
+}
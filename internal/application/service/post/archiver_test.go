@@ -0,0 +1,133 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	archive_repository_mock "pinstack-post-service/mocks/archive"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+)
+
+func newTestArchiver(batchSize int) (*PostArchiver, *postgres_mock.UnitOfWork, *postgres_mock.Transaction, *archive_repository_mock.Repository) {
+	uow := new(postgres_mock.UnitOfWork)
+	tx := new(postgres_mock.Transaction)
+	archiveRepo := new(archive_repository_mock.Repository)
+	tx.On("ArchiveRepository").Return(archiveRepo)
+
+	a := NewPostArchiver(uow, 30*24*time.Hour, batchSize, time.Hour, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	return a, uow, tx, archiveRepo
+}
+
+func TestPostArchiver_ArchiveOnce(t *testing.T) {
+	a, uow, tx, archiveRepo := newTestArchiver(100)
+	cutoff := time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC)
+	uow.On("Begin", mock.Anything).Return(tx, nil)
+	archiveRepo.On("ArchiveOlderThan", mock.Anything, cutoff, 100).Return(100, nil).Twice()
+	archiveRepo.On("ArchiveOlderThan", mock.Anything, cutoff, 100).Return(42, nil).Once()
+	tx.On("Commit", mock.Anything).Return(nil)
+	tx.On("Rollback", mock.Anything).Return(nil)
+
+	archived, err := a.ArchiveOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 242, archived)
+	uow.AssertNumberOfCalls(t, "Begin", 3)
+	tx.AssertNumberOfCalls(t, "Commit", 3)
+	tx.AssertNotCalled(t, "Rollback", mock.Anything)
+}
+
+func TestPostArchiver_ArchiveOnce_NothingToArchive(t *testing.T) {
+	a, uow, tx, archiveRepo := newTestArchiver(100)
+	uow.On("Begin", mock.Anything).Return(tx, nil)
+	archiveRepo.On("ArchiveOlderThan", mock.Anything, mock.Anything, 100).Return(0, nil)
+	tx.On("Commit", mock.Anything).Return(nil)
+
+	archived, err := a.ArchiveOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+	uow.AssertNumberOfCalls(t, "Begin", 1)
+}
+
+func TestPostArchiver_ArchiveOnce_FailedBatchIsRolledBack(t *testing.T) {
+	a, uow, tx, archiveRepo := newTestArchiver(100)
+	uow.On("Begin", mock.Anything).Return(tx, nil)
+	archiveRepo.On("ArchiveOlderThan", mock.Anything, mock.Anything, 100).Return(100, nil).Once()
+	archiveRepo.On("ArchiveOlderThan", mock.Anything, mock.Anything, 100).Return(0, custom_errors.ErrDatabaseQuery).Once()
+	tx.On("Commit", mock.Anything).Return(nil).Once()
+	tx.On("Rollback", mock.Anything).Return(nil)
+
+	archived, err := a.ArchiveOnce(context.Background())
+
+	assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Equal(t, 100, archived, "the committed first batch stays archived")
+	tx.AssertNumberOfCalls(t, "Commit", 1)
+	tx.AssertNumberOfCalls(t, "Rollback", 1)
+}
+
+func TestPostArchiver_ArchiveOnce_CommitFails(t *testing.T) {
+	a, uow, tx, archiveRepo := newTestArchiver(100)
+	uow.On("Begin", mock.Anything).Return(tx, nil)
+	archiveRepo.On("ArchiveOlderThan", mock.Anything, mock.Anything, 100).Return(5, nil)
+	tx.On("Commit", mock.Anything).Return(errors.New("connection reset"))
+	tx.On("Rollback", mock.Anything).Return(nil)
+
+	archived, err := a.ArchiveOnce(context.Background())
+
+	assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Zero(t, archived)
+}
+
+func TestPostArchiver_ArchiveOnce_StopsWhenCancelled(t *testing.T) {
+	a, uow, tx, archiveRepo := newTestArchiver(100)
+	ctx, cancel := context.WithCancel(context.Background())
+	uow.On("Begin", mock.Anything).Return(tx, nil)
+	archiveRepo.On("ArchiveOlderThan", mock.Anything, mock.Anything, 100).Return(100, nil).Run(func(mock.Arguments) { cancel() })
+	tx.On("Commit", mock.Anything).Return(nil)
+
+	archived, err := a.ArchiveOnce(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 100, archived)
+	uow.AssertNumberOfCalls(t, "Begin", 1)
+}
+
+func TestPostArchiver_Run_StopsWithContext(t *testing.T) {
+	a, uow, tx, archiveRepo := newTestArchiver(100)
+	begun := make(chan struct{})
+	var once sync.Once
+	uow.On("Begin", mock.Anything).Return(tx, nil).Run(func(mock.Arguments) { once.Do(func() { close(begun) }) })
+	archiveRepo.On("ArchiveOlderThan", mock.Anything, mock.Anything, 100).Return(0, nil)
+	tx.On("Commit", mock.Anything).Return(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		a.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-begun:
+	case <-time.After(time.Second):
+		t.Fatal("the first run did not start right away")
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
@@ -0,0 +1,30 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// GetAuthorPostCount counts the published posts of authorID, the number shown on profile
+// pages. Drafts and archived posts are not counted, as they are not listed either.
+func (s *PostService) GetAuthorPostCount(ctx context.Context, authorID int64) (result *model.AuthorPostCount, err error) {
+	defer observePostOperation(s.metrics, "author_post_count", time.Now(), &err)
+
+	if authorID <= 0 {
+		return nil, fmt.Errorf("%w: author id must be positive, got %d", custom_errors.ErrInvalidInput, authorID)
+	}
+
+	count, err := s.postRepo.CountPublishedByAuthor(ctx, authorID)
+	if err != nil {
+		s.log.Error("Failed to count posts by author", slog.Int64("authorID", authorID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return &model.AuthorPostCount{AuthorID: authorID, Count: count}, nil
+}
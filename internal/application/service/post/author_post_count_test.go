@@ -0,0 +1,82 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_service_mock "pinstack-post-service/mocks/post"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+)
+
+func TestPostService_GetAuthorPostCount(t *testing.T) {
+	s, _ := newBatchGetService(t)
+
+	got, err := s.GetAuthorPostCount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, &model.AuthorPostCount{AuthorID: 1, Count: 2}, got, "drafts are not counted")
+
+	got, err = s.GetAuthorPostCount(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), got.Count)
+
+	_, err = s.GetAuthorPostCount(context.Background(), 0)
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+}
+
+func TestPostService_GetAuthorPostCount_DatabaseError(t *testing.T) {
+	postRepo := new(post_service_mock.Repository)
+	postRepo.On("CountPublishedByAuthor", mock.Anything, int64(1)).Return(int64(0), errors.New("connection reset"))
+	s := NewPostService(postRepo, nil, nil, new(postgres_mock.UnitOfWork), logger.New("test"), nil,
+		prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+
+	got, err := s.GetAuthorPostCount(context.Background(), 1)
+
+	assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Nil(t, got)
+}
+
+func TestPostServiceCacheDecorator_GetAuthorPostCount(t *testing.T) {
+	counted := &model.AuthorPostCount{AuthorID: 1, Count: 4}
+
+	tests := []struct {
+		name     string
+		cacheErr error
+		cached   int64
+		want     *model.AuthorPostCount
+		wantSet  bool
+	}{
+		{name: "hit", cached: 7, want: &model.AuthorPostCount{AuthorID: 1, Count: 7, Cached: true}},
+		{name: "miss is counted and cached", cacheErr: custom_errors.ErrCacheMiss, want: counted, wantSet: true},
+		{name: "cache failure falls back to the service", cacheErr: errors.New("redis: connection refused"), want: counted, wantSet: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_service_mock.Service)
+			userCache := new(cache_mock.UserCache)
+			userCache.On("GetUserPostCount", mock.Anything, int64(1)).Return(tt.cached, tt.cacheErr).Once()
+			if tt.wantSet {
+				service.On("GetAuthorPostCount", mock.Anything, int64(1)).Return(counted, nil).Once()
+				userCache.On("SetUserPostCount", mock.Anything, int64(1), int64(4)).Return(nil).Once()
+			}
+			d := NewPostServiceCacheDecorator(service, userCache, new(cache_mock.PostCache), new(cache_mock.CacheBatcher),
+				logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			got, err := d.GetAuthorPostCount(context.Background(), 1)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			service.AssertExpectations(t)
+			userCache.AssertExpectations(t)
+		})
+	}
+}
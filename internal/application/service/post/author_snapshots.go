@@ -0,0 +1,191 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+	post_repository "pinstack-post-service/internal/domain/ports/output/post"
+	user_client "pinstack-post-service/internal/domain/ports/output/user"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// maxQueuedSnapshotAuthors bounds the authors waiting for a snapshot refresh. Reads past it
+// queue nobody; their authors are queued again by a later read.
+const maxQueuedSnapshotAuthors = 10000
+
+// AuthorSnapshots lets reads build a post's author from the snapshot of their display data
+// stored on the post, so the read path never calls the user service for a post that has one.
+// A snapshot older than staleAfter is still served, but its author is queued for the
+// AuthorSnapshotRefresher, and so is the author of a post without a snapshot, whose read
+// falls back to the user service.
+type AuthorSnapshots struct {
+	staleAfter time.Duration
+
+	mu     sync.Mutex
+	queued map[int64]struct{}
+}
+
+func NewAuthorSnapshots(staleAfter time.Duration) *AuthorSnapshots {
+	return &AuthorSnapshots{
+		staleAfter: staleAfter,
+		queued:     make(map[int64]struct{}),
+	}
+}
+
+// ReadAuthorsFromSnapshots makes GetPostByID, ListPosts and GetPostsByIDs build authors from
+// post snapshots as snapshots describes. Without it every read asks the user service.
+func (s *PostService) ReadAuthorsFromSnapshots(snapshots *AuthorSnapshots) {
+	s.snapshots = snapshots
+}
+
+// snapshotAuthor returns the author of post built from its snapshot, or nil when it has none
+// or snapshot reads are off. Either way a read that needs a refresh queues the author.
+func (s *PostService) snapshotAuthor(post *model.Post) *model.User {
+	if s.snapshots == nil {
+		return nil
+	}
+	author := post.AuthorFromSnapshot()
+	if author == nil {
+		s.metrics.IncrementAuthorSnapshotReads("miss")
+		s.snapshots.queue(post.AuthorID)
+		return nil
+	}
+	s.metrics.IncrementAuthorSnapshotReads("hit")
+	if s.now().Sub(post.AuthorSnapshotAt.Time) > s.snapshots.staleAfter {
+		s.snapshots.queue(post.AuthorID)
+	}
+	return author
+}
+
+func (a *AuthorSnapshots) queue(authorID int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.queued) < maxQueuedSnapshotAuthors {
+		a.queued[authorID] = struct{}{}
+	}
+}
+
+// take removes up to limit queued authors and returns them.
+func (a *AuthorSnapshots) take(limit int) []int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	authorIDs := make([]int64, 0, min(limit, len(a.queued)))
+	for authorID := range a.queued {
+		if len(authorIDs) == limit {
+			break
+		}
+		authorIDs = append(authorIDs, authorID)
+		delete(a.queued, authorID)
+	}
+	return authorIDs
+}
+
+// Queued reports how many authors wait for a refresh.
+func (a *AuthorSnapshots) Queued() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.queued)
+}
+
+// AuthorSnapshotRefresher periodically fetches the authors reads queued on AuthorSnapshots
+// and rewrites the snapshots of their posts. Posts already in the post cache keep the old
+// author until their entry expires.
+type AuthorSnapshotRefresher struct {
+	postRepo   post_repository.Repository
+	userClient user_client.Client
+	snapshots  *AuthorSnapshots
+	batchSize  int
+	interval   time.Duration
+	log        output.Logger
+	metrics    output.MetricsProvider
+	now        func() time.Time
+}
+
+func NewAuthorSnapshotRefresher(
+	postRepo post_repository.Repository,
+	userClient user_client.Client,
+	snapshots *AuthorSnapshots,
+	batchSize int,
+	interval time.Duration,
+	log output.Logger,
+	metrics output.MetricsProvider,
+) *AuthorSnapshotRefresher {
+	return &AuthorSnapshotRefresher{
+		postRepo:   postRepo,
+		userClient: userClient,
+		snapshots:  snapshots,
+		batchSize:  batchSize,
+		interval:   interval,
+		log:        log,
+		metrics:    metrics,
+		now:        time.Now,
+	}
+}
+
+// Run refreshes once per interval until ctx is done. A failed run is logged and its authors
+// are retried at the next interval.
+func (r *AuthorSnapshotRefresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := r.Refresh(ctx); err != nil && ctx.Err() == nil {
+			r.log.Warn("Author snapshot refresh failed", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// Refresh refreshes up to batchSize queued authors and returns how many posts it rewrote. An
+// author the user service does not know is dropped: their posts keep their last snapshot. Any
+// other failure puts the authors not yet refreshed back in the queue and is returned.
+func (r *AuthorSnapshotRefresher) Refresh(ctx context.Context) (int, error) {
+	authorIDs := r.snapshots.take(r.batchSize)
+	refreshed := 0
+	for i, authorID := range authorIDs {
+		n, err := r.refreshAuthor(ctx, authorID)
+		if err != nil {
+			for _, rest := range authorIDs[i:] {
+				r.snapshots.queue(rest)
+			}
+			r.metrics.AddAuthorSnapshotsRefreshed(refreshed)
+			return refreshed, err
+		}
+		refreshed += n
+	}
+
+	r.metrics.AddAuthorSnapshotsRefreshed(refreshed)
+	if len(authorIDs) > 0 {
+		r.log.Info("Refreshed author snapshots", slog.Int("authors", len(authorIDs)), slog.Int("posts", refreshed))
+	}
+	return refreshed, nil
+}
+
+func (r *AuthorSnapshotRefresher) refreshAuthor(ctx context.Context, authorID int64) (int, error) {
+	author, err := r.userClient.GetUser(ctx, authorID)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrUserNotFound) {
+			r.log.Debug("Author of snapshot not found, keeping the last snapshot", slog.Int64("author_id", authorID))
+			return 0, nil
+		}
+		r.log.Error("Failed to get author for snapshot refresh", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return 0, custom_errors.ErrExternalServiceError
+	}
+
+	count, err := r.postRepo.RefreshAuthorSnapshot(ctx, author, r.now())
+	if err != nil {
+		r.log.Error("Failed to refresh author snapshot", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return 0, err
+	}
+	return int(count), nil
+}
@@ -0,0 +1,197 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	repository_memory "pinstack-post-service/internal/infrastructure/outbound/repository/memory"
+	"pinstack-post-service/internal/testsupport"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+// newSnapshotService reads authors from snapshots older than an hour without refreshing them.
+// Post 1 by author 1 is created through the service, with the author already verified, and
+// so has a snapshot taken at testsupport.FixedTime; post 2 by author 2 is stored directly and
+// has none.
+func newSnapshotService(t *testing.T) (*PostService, *AuthorSnapshots, *repository_memory.Database, *user_client_mock.Client) {
+	t.Helper()
+	userClient := new(user_client_mock.Client)
+	s, database := newMemoryServiceWith(t, memoryServiceDeps{users: userClient})
+	s.now = func() time.Time { return testsupport.FixedTime }
+	snapshots := NewAuthorSnapshots(time.Hour)
+	s.ReadAuthorsFromSnapshots(snapshots)
+
+	dto := testsupport.NewCreatePostDTOBuilder().WithAuthor(1).Build()
+	dto.VerifiedAuthor = &model.User{ID: 1, Username: "one", Email: "one@example.com", AvatarURL: testsupport.Ptr("https://example.com/one.png")}
+	_, err := s.CreatePost(context.Background(), dto)
+	require.NoError(t, err)
+	_, err = database.Posts.Create(context.Background(), testsupport.NewPostBuilder().WithAuthor(2).WithoutTimestamps().Build())
+	require.NoError(t, err)
+	return s, snapshots, database, userClient
+}
+
+func TestPostService_CreatePost_StoresAuthorSnapshot(t *testing.T) {
+	_, _, database, _ := newSnapshotService(t)
+
+	post, err := database.Posts.GetByID(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.NotNil(t, post.AuthorUsername)
+	assert.Equal(t, "one", *post.AuthorUsername)
+	require.NotNil(t, post.AuthorAvatarURL)
+	assert.Equal(t, "https://example.com/one.png", *post.AuthorAvatarURL)
+	assert.True(t, post.AuthorSnapshotAt.Time.Equal(testsupport.FixedTime))
+}
+
+func TestPostService_GetPostByID_ReadsAuthorFromSnapshot(t *testing.T) {
+	s, snapshots, _, userClient := newSnapshotService(t)
+
+	got, err := s.GetPostByID(context.Background(), 1, nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, got.Author)
+	assert.Equal(t, int64(1), got.Author.ID)
+	assert.Equal(t, "one", got.Author.Username)
+	assert.Equal(t, "https://example.com/one.png", *got.Author.AvatarURL)
+	assert.Empty(t, got.Author.Email, "a snapshot holds display data only")
+	assert.True(t, got.AuthorFromSnapshot)
+	assert.Zero(t, snapshots.Queued(), "a fresh snapshot needs no refresh")
+	userClient.AssertNotCalled(t, "GetUser", mock.Anything, int64(1))
+}
+
+func TestPostService_GetPostByID_QueuesStaleSnapshot(t *testing.T) {
+	s, snapshots, _, userClient := newSnapshotService(t)
+	s.now = func() time.Time { return testsupport.FixedTime.Add(2 * time.Hour) }
+
+	got, err := s.GetPostByID(context.Background(), 1, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "one", got.Author.Username, "a stale snapshot is still served")
+	assert.Equal(t, []int64{1}, snapshots.take(10))
+	userClient.AssertNotCalled(t, "GetUser", mock.Anything, int64(1))
+}
+
+func TestPostService_GetPostByID_FallsBackWithoutSnapshot(t *testing.T) {
+	s, snapshots, _, userClient := newSnapshotService(t)
+	userClient.On("GetUser", mock.Anything, int64(2)).Return(&model.User{ID: 2, Username: "two", Email: "two@example.com"}, nil).Once()
+
+	got, err := s.GetPostByID(context.Background(), 2, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "two@example.com", got.Author.Email)
+	assert.False(t, got.AuthorFromSnapshot)
+	assert.Equal(t, []int64{2}, snapshots.take(10), "the author is queued for a snapshot")
+	userClient.AssertExpectations(t)
+}
+
+func TestPostService_ListPosts_ReadsAuthorsFromSnapshots(t *testing.T) {
+	s, _, _, userClient := newSnapshotService(t)
+	userClient.On("GetUser", mock.Anything, int64(2)).Return(nil, custom_errors.ErrUserNotFound).Once()
+
+	got, _, err := s.ListPosts(context.Background(), &model.PostFilters{})
+
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	byID := map[int64]*model.PostDetailed{got[0].Post.ID: got[0], got[1].Post.ID: got[1]}
+	assert.Equal(t, "one", byID[1].Author.Username)
+	assert.True(t, byID[1].AuthorFromSnapshot)
+	assert.Nil(t, byID[2].Author, "an author without a snapshot is fetched")
+	userClient.AssertExpectations(t)
+	userClient.AssertNotCalled(t, "GetUser", mock.Anything, int64(1))
+}
+
+func TestPostService_GetPostsByIDs_ReadsAuthorsFromSnapshots(t *testing.T) {
+	s, _, _, userClient := newSnapshotService(t)
+	userClient.On("GetUser", mock.Anything, int64(2)).Return(&model.User{ID: 2, Username: "two"}, nil).Once()
+
+	got, err := s.GetPostsByIDs(context.Background(), []int64{2, 1})
+
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "two", got[0].Author.Username)
+	assert.False(t, got[0].AuthorFromSnapshot)
+	assert.Equal(t, "one", got[1].Author.Username)
+	assert.True(t, got[1].AuthorFromSnapshot)
+	userClient.AssertExpectations(t)
+}
+
+func TestAuthorSnapshotRefresher_Refresh(t *testing.T) {
+	s, snapshots, database, userClient := newSnapshotService(t)
+	refresher := NewAuthorSnapshotRefresher(database.Posts, userClient, snapshots, 10, time.Minute, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	refreshedAt := testsupport.FixedTime.Add(2 * time.Hour)
+	refresher.now = func() time.Time { return refreshedAt }
+	snapshots.queue(1)
+	snapshots.queue(2)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "one-renamed"}, nil).Once()
+	userClient.On("GetUser", mock.Anything, int64(2)).Return(&model.User{ID: 2, Username: "two"}, nil).Once()
+
+	refreshed, err := refresher.Refresh(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, refreshed)
+	assert.Zero(t, snapshots.Queued())
+	got, err := s.GetPostByID(context.Background(), 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "one-renamed", got.Author.Username)
+	assert.Nil(t, got.Author.AvatarURL, "a removed avatar is removed from the snapshot")
+	post, err := database.Posts.GetByID(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "two", *post.AuthorUsername, "a post without a snapshot gets one")
+	assert.True(t, post.AuthorSnapshotAt.Time.Equal(refreshedAt))
+	assert.Equal(t, int64(1), post.Version, "a refresh is not an edit")
+	userClient.AssertExpectations(t)
+}
+
+func TestAuthorSnapshotRefresher_Refresh_Failures(t *testing.T) {
+	t.Run("UnknownAuthorIsDropped", func(t *testing.T) {
+		_, snapshots, database, userClient := newSnapshotService(t)
+		refresher := NewAuthorSnapshotRefresher(database.Posts, userClient, snapshots, 10, time.Minute, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+		snapshots.queue(1)
+		userClient.On("GetUser", mock.Anything, int64(1)).Return(nil, custom_errors.ErrUserNotFound).Once()
+
+		refreshed, err := refresher.Refresh(context.Background())
+
+		require.NoError(t, err)
+		assert.Zero(t, refreshed)
+		assert.Zero(t, snapshots.Queued())
+		post, err := database.Posts.GetByID(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "one", *post.AuthorUsername, "the last snapshot is kept")
+	})
+
+	t.Run("UserServiceErrorRequeues", func(t *testing.T) {
+		_, snapshots, database, userClient := newSnapshotService(t)
+		refresher := NewAuthorSnapshotRefresher(database.Posts, userClient, snapshots, 10, time.Minute, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+		snapshots.queue(1)
+		userClient.On("GetUser", mock.Anything, int64(1)).Return(nil, errors.New("connection refused")).Once()
+
+		_, err := refresher.Refresh(context.Background())
+
+		assert.ErrorIs(t, err, custom_errors.ErrExternalServiceError)
+		assert.Equal(t, 1, snapshots.Queued(), "the author is retried at the next run")
+	})
+
+	t.Run("BatchSizeBoundsARun", func(t *testing.T) {
+		_, snapshots, database, userClient := newSnapshotService(t)
+		refresher := NewAuthorSnapshotRefresher(database.Posts, userClient, snapshots, 1, time.Minute, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+		snapshots.queue(1)
+		snapshots.queue(2)
+		userClient.On("GetUser", mock.Anything, mock.Anything).Return(&model.User{Username: "someone"}, nil).Once()
+
+		_, err := refresher.Refresh(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, snapshots.Queued())
+		userClient.AssertExpectations(t)
+	})
+}
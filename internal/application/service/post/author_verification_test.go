@@ -0,0 +1,119 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/cache/noop"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_service_mock "pinstack-post-service/mocks/post"
+)
+
+func TestPostServiceCacheDecorator_CreatePost_VerifiesAuthorFromCache(t *testing.T) {
+	author := &model.User{ID: 1, Username: "alice"}
+
+	tests := []struct {
+		name         string
+		cached       *model.User
+		cacheErr     error
+		wantVerified bool
+	}{
+		{name: "fresh cached author", cached: author, wantVerified: true},
+		{name: "missing or stale author", cacheErr: custom_errors.ErrCacheMiss},
+		{name: "cache failure", cacheErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_service_mock.Service)
+			userCache := new(cache_mock.UserCache)
+			batcher := new(cache_mock.CacheBatcher)
+			batch := new(cache_mock.CacheBatch)
+			created := &model.PostDetailed{Post: &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusDraft}, Author: author}
+
+			userCache.On("GetFreshUser", mock.Anything, int64(1)).Return(tt.cached, tt.cacheErr)
+			service.On("CreatePost", mock.Anything, mock.MatchedBy(func(dto *model.CreatePostDTO) bool {
+				return (dto.VerifiedAuthor != nil) == tt.wantVerified
+			})).Return(created, nil)
+			batcher.On("NewBatch").Return(batch)
+			batch.On("SetPost", created)
+			batch.On("AddRecentPost", int64(1), int64(10))
+			batch.On("SetUser", author)
+			batch.On("Exec", mock.Anything).Return(nil)
+
+			d := NewPostServiceCacheDecorator(service, userCache, new(cache_mock.PostCache), batcher,
+				logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+			dto := &model.CreatePostDTO{AuthorID: 1, Title: "Post"}
+
+			_, err := d.CreatePost(context.Background(), dto)
+
+			require.NoError(t, err)
+			service.AssertExpectations(t)
+			assert.Nil(t, dto.VerifiedAuthor, "the caller's request is not modified")
+			if tt.wantVerified {
+				batch.AssertNotCalled(t, "SetUser", mock.Anything)
+			} else {
+				batch.AssertCalled(t, "SetUser", author)
+			}
+		})
+	}
+}
+
+func TestPostService_CreatePost_VerifiedAuthor(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	users := &countingUsers{}
+	service, _ := newMemoryServiceWith(t, memoryServiceDeps{users: users, metrics: metrics})
+	userCache := new(cache_mock.UserCache)
+	d := NewPostServiceCacheDecorator(service, userCache, noop.NewPostCache(), noop.NewBatcher(), log, metrics)
+	ctx := context.Background()
+
+	t.Run("fresh cached author skips the user service", func(t *testing.T) {
+		userCache.On("GetFreshUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "cached"}, nil).Once()
+
+		created, err := d.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "cached", created.Author.Username)
+		assert.Zero(t, users.calls[1])
+	})
+
+	t.Run("miss asks the user service", func(t *testing.T) {
+		userCache.On("GetFreshUser", mock.Anything, int64(2)).Return(nil, custom_errors.ErrCacheMiss).Once()
+
+		created, err := d.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 2, Title: "Post"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "user2", created.Author.Username)
+		assert.Equal(t, 1, users.calls[2])
+	})
+
+	t.Run("unknown author is confirmed by the user service", func(t *testing.T) {
+		userCache.On("GetFreshUser", mock.Anything, int64(10)).Return(nil, custom_errors.ErrCacheMiss).Once()
+
+		_, err := d.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 10, Title: "Post"})
+
+		assert.ErrorIs(t, err, custom_errors.ErrExternalServiceError)
+		assert.Equal(t, 1, users.calls[10])
+		authorID := int64(10)
+		_, total, err := service.ListPosts(ctx, &model.PostFilters{AuthorID: &authorID})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("a verified author for someone else is ignored", func(t *testing.T) {
+		_, err := service.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 10, Title: "Post", VerifiedAuthor: &model.User{ID: 1}})
+
+		assert.ErrorIs(t, err, custom_errors.ErrExternalServiceError)
+		assert.Equal(t, 2, users.calls[10])
+	})
+}
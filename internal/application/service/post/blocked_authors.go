@@ -0,0 +1,27 @@
+package post_service
+
+import (
+	"context"
+	"log/slog"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// blockedAuthors returns the authors requesterID has blocked, whose posts their lists leave
+// out. A feed is more useful than an error: when the user service fails, the list goes on
+// without hiding anyone and the fallback is counted.
+func (s *PostService) blockedAuthors(ctx context.Context, requesterID int64) []int64 {
+	var blockedIDs []int64
+	err := inStage(ctx, s.metrics, "post_list", model.BudgetStageUserService, func(ctx context.Context) (err error) {
+		blockedIDs, err = s.userClient.GetBlockedUsers(ctx, requesterID)
+		return err
+	})
+	if err != nil {
+		s.metrics.IncrementUserServiceFallbacks("blocked_users")
+		s.log.Warn("Failed to get blocked users, listing without hiding them",
+			slog.Int64("requester_id", requesterID),
+			slog.String("error", err.Error()))
+		return nil
+	}
+	return blockedIDs
+}
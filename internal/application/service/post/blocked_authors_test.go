@@ -0,0 +1,92 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+type fallbackMetrics struct {
+	output.MetricsProvider
+	fallbacks map[string]int
+}
+
+func (m *fallbackMetrics) IncrementUserServiceFallbacks(operation string) {
+	m.fallbacks[operation]++
+}
+
+// newBlockingService stores one post each by authors 1, 2 and 3, with users answering every
+// author lookup.
+func newBlockingService(t *testing.T) (*PostService, *user_client_mock.Client, *fallbackMetrics) {
+	t.Helper()
+	users := user_client_mock.NewClient(t)
+	users.On("GetUser", mock.Anything, mock.Anything).Return(func(ctx context.Context, id int64) (*model.User, error) {
+		return &model.User{ID: id}, nil
+	}).Maybe()
+	metrics := &fallbackMetrics{MetricsProvider: prometheus.NewPrometheusMetricsProvider(), fallbacks: map[string]int{}}
+	s, _ := newMemoryServiceWith(t, memoryServiceDeps{users: users, metrics: metrics})
+	for _, authorID := range []int64{1, 2, 3} {
+		_, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: authorID, Title: "Post"})
+		require.NoError(t, err)
+	}
+	return s, users, metrics
+}
+
+func listedAuthors(posts []*model.PostDetailed) []int64 {
+	authors := make([]int64, len(posts))
+	for i, post := range posts {
+		authors[i] = post.Post.AuthorID
+	}
+	return authors
+}
+
+func TestPostService_ListPosts_HidesBlockedAuthors(t *testing.T) {
+	tests := []struct {
+		name      string
+		blocked   []int64
+		err       error
+		want      []int64
+		fallbacks int
+	}{
+		{name: "blocked authors", blocked: []int64{2, 3}, want: []int64{1}},
+		{name: "empty block list", blocked: []int64{}, want: []int64{3, 2, 1}},
+		{name: "user service down", err: errors.New("unavailable"), want: []int64{3, 2, 1}, fallbacks: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, users, metrics := newBlockingService(t)
+			requester := int64(1)
+			users.On("GetBlockedUsers", mock.Anything, requester).Return(tt.blocked, tt.err).Once()
+
+			got, total, err := s.ListPosts(context.Background(), &model.PostFilters{RequesterID: &requester})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, listedAuthors(got))
+			assert.Equal(t, len(tt.want), total, "the count leaves out what the page does")
+			assert.Equal(t, tt.fallbacks, metrics.fallbacks["blocked_users"])
+		})
+	}
+}
+
+func TestPostService_ListPosts_BlocksNeedARequester(t *testing.T) {
+	s, _, _ := newBlockingService(t)
+	author := int64(2)
+
+	// The mock fails the test on any GetBlockedUsers call.
+	anonymous, _, err := s.ListPosts(context.Background(), &model.PostFilters{})
+	require.NoError(t, err)
+	assert.Len(t, anonymous, 3)
+
+	own, _, err := s.ListPosts(context.Background(), &model.PostFilters{AuthorID: &author, RequesterID: &author})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, listedAuthors(own))
+}
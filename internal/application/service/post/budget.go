@@ -0,0 +1,27 @@
+package post_service
+
+import (
+	"context"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+)
+
+// inStage runs fn within stage's share of the request budget and records how long it took.
+// A spent budget fails with model.ErrDeadlineExceeded without calling fn, so the time left
+// is not wasted on a call that cannot finish; an error fn returns after its share ran out is
+// marked the same way.
+func inStage(ctx context.Context, metrics output.MetricsProvider, operation string, stage model.BudgetStage, fn func(ctx context.Context) error) error {
+	stageCtx, cancel, err := model.StageContext(ctx, stage)
+	if err != nil {
+		metrics.IncrementBudgetExhausted(operation, string(stage))
+		return err
+	}
+	defer cancel()
+
+	start := time.Now()
+	err = fn(stageCtx)
+	metrics.RecordBudgetStageDuration(operation, string(stage), time.Since(start))
+	return model.CallerError(stageCtx, err)
+}
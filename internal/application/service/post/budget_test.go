@@ -0,0 +1,178 @@
+package post_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	cache_mock "pinstack-post-service/mocks/cache"
+	media_repository_mock "pinstack-post-service/mocks/media"
+	post_repository_mock "pinstack-post-service/mocks/post"
+	post_service_mock "pinstack-post-service/mocks/post"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+	tag_repository_mock "pinstack-post-service/mocks/tag"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+var budgetRatios = model.BudgetRatios{Cache: 0.2, Database: 0.4, UserService: 0.2}
+
+func newBudgetTestService(postRepo *post_repository_mock.Repository, userClient *user_client_mock.Client) *PostService {
+	return NewPostService(postRepo, new(tag_repository_mock.Repository), new(media_repository_mock.Repository), new(postgres_mock.UnitOfWork),
+		logger.New("test"), userClient, prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+}
+
+// untilDone blocks a mocked call until the context it was given ends, like a dependency that
+// never answers.
+func untilDone(args mock.Arguments) {
+	<-args.Get(0).(context.Context).Done()
+}
+
+// stageBudget returns how long the stage context passed to a mocked call had left.
+func stageBudget(left *time.Duration) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		deadline, _ := args.Get(0).(context.Context).Deadline()
+		*left = time.Until(deadline)
+	}
+}
+
+func TestGetPostByID_SlowUserServiceFailsWithinItsShare(t *testing.T) {
+	postRepo := new(post_repository_mock.Repository)
+	userClient := new(user_client_mock.Client)
+	postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(&model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: 7}}, nil)
+	userClient.On("GetUser", mock.Anything, int64(7)).Run(untilDone).Return(nil, context.DeadlineExceeded)
+	s := newBudgetTestService(postRepo, userClient)
+
+	ctx := model.WithBudget(context.Background(), 500*time.Millisecond, budgetRatios)
+	start := time.Now()
+	_, err := s.GetPostByID(ctx, 1, nil)
+
+	assert.ErrorIs(t, err, model.ErrDeadlineExceeded)
+	assert.Less(t, time.Since(start), 250*time.Millisecond, "the user service gets a fifth of the budget, not all of it")
+}
+
+func TestGetPostByID_SlowCacheLeavesTheDatabaseItsShare(t *testing.T) {
+	postRepo := new(post_repository_mock.Repository)
+	userClient := new(user_client_mock.Client)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+	post := &model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: 7}}
+	var databaseLeft time.Duration
+	postCache.On("GetPost", mock.Anything, int64(1)).Run(untilDone).Return(nil, context.DeadlineExceeded)
+	postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Run(stageBudget(&databaseLeft)).Return(post, nil)
+	userClient.On("GetUser", mock.Anything, int64(7)).Return(&model.User{ID: 7}, nil)
+	batcher.On("NewBatch").Return(batch)
+	batch.On("SetPost", mock.Anything)
+	batch.On("SetUser", mock.Anything)
+	batch.On("Exec", mock.Anything).Return(nil)
+	d := NewPostServiceCacheDecorator(newBudgetTestService(postRepo, userClient), new(cache_mock.UserCache), postCache, batcher,
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	ctx := model.WithBudget(context.Background(), 500*time.Millisecond, budgetRatios)
+	got, err := d.GetPostByID(ctx, 1, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), got.Author.ID)
+	assert.InDelta(t, 200*time.Millisecond, databaseLeft, float64(30*time.Millisecond), "the database keeps its full share after a slow cache")
+}
+
+func TestGetPostByID_SpentBudgetSkipsTheDatabase(t *testing.T) {
+	postRepo := new(post_repository_mock.Repository)
+	s := newBudgetTestService(postRepo, new(user_client_mock.Client))
+
+	ctx := model.WithBudget(context.Background(), time.Millisecond, budgetRatios)
+	time.Sleep(5 * time.Millisecond)
+	_, err := s.GetPostByID(ctx, 1, nil)
+
+	assert.ErrorIs(t, err, model.ErrDeadlineExceeded)
+	postRepo.AssertNotCalled(t, "GetDetailedByID", mock.Anything, mock.Anything)
+}
+
+func TestCreatePost_SlowUserServiceFailsWithinItsShare(t *testing.T) {
+	postRepo := new(post_repository_mock.Repository)
+	userClient := new(user_client_mock.Client)
+	userClient.On("GetUser", mock.Anything, int64(7)).Run(untilDone).Return(nil, context.DeadlineExceeded)
+	s := newBudgetTestService(postRepo, userClient)
+
+	ctx := model.WithBudget(context.Background(), 500*time.Millisecond, budgetRatios)
+	start := time.Now()
+	_, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 7, Title: "Budgeted"})
+
+	assert.ErrorIs(t, err, model.ErrDeadlineExceeded)
+	assert.Less(t, time.Since(start), 250*time.Millisecond, "the user service gets a fifth of the budget, not all of it")
+}
+
+func TestListPosts_SlowUserServiceFailsWithinItsShare(t *testing.T) {
+	postRepo := new(post_repository_mock.Repository)
+	mediaRepo := new(media_repository_mock.Repository)
+	tagRepo := new(tag_repository_mock.Repository)
+	userClient := new(user_client_mock.Client)
+	var databaseLeft time.Duration
+	postRepo.On("List", mock.Anything, mock.Anything).Run(stageBudget(&databaseLeft)).Return([]*model.Post{{ID: 1, AuthorID: 7}}, 1, nil)
+	mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
+	tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{}, nil)
+	userClient.On("GetUser", mock.Anything, int64(7)).Run(untilDone).Return(nil, context.DeadlineExceeded)
+	s := NewPostService(postRepo, tagRepo, mediaRepo, new(postgres_mock.UnitOfWork),
+		logger.New("test"), userClient, prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+
+	ctx := model.WithBudget(context.Background(), 500*time.Millisecond, budgetRatios)
+	start := time.Now()
+	_, _, err := s.ListPosts(ctx, &model.PostFilters{})
+
+	assert.ErrorIs(t, err, model.ErrDeadlineExceeded)
+	assert.Less(t, time.Since(start), 250*time.Millisecond, "the user service gets a fifth of the budget, not all of it")
+	assert.InDelta(t, 200*time.Millisecond, databaseLeft, float64(30*time.Millisecond), "the list query runs within the database's share")
+}
+
+func TestDeletePost_SpentBudgetSkipsTheDatabase(t *testing.T) {
+	postRepo := new(post_repository_mock.Repository)
+	s := newBudgetTestService(postRepo, new(user_client_mock.Client))
+
+	ctx := model.WithBudget(context.Background(), time.Millisecond, budgetRatios)
+	time.Sleep(5 * time.Millisecond)
+	err := s.DeletePost(ctx, 7, 1)
+
+	assert.ErrorIs(t, err, model.ErrDeadlineExceeded)
+	postRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestGetPostTags_SlowDatabaseFailsWithinItsShare(t *testing.T) {
+	postRepo := new(post_repository_mock.Repository)
+	postRepo.On("GetByID", mock.Anything, int64(1)).Run(untilDone).Return(nil, context.DeadlineExceeded)
+	s := newBudgetTestService(postRepo, new(user_client_mock.Client))
+
+	ctx := model.WithBudget(context.Background(), 500*time.Millisecond, budgetRatios)
+	start := time.Now()
+	_, err := s.GetPostTags(ctx, 1, nil, false)
+
+	assert.ErrorIs(t, err, model.ErrDeadlineExceeded)
+	assert.Less(t, time.Since(start), 300*time.Millisecond, "the database gets two fifths of the budget, not all of it")
+}
+
+func TestPostServiceCacheDecorator_UpdatePost_SlowCacheReturnsWithinItsShare(t *testing.T) {
+	service := new(post_service_mock.Service)
+	postCache := new(cache_mock.PostCache)
+	dto := &model.UpdatePostDTO{UserID: 1}
+	updated := &model.PostDetailed{Post: &model.Post{ID: 3, AuthorID: 1}, Author: &model.User{ID: 1}}
+	service.On("UpdatePost", mock.Anything, int64(1), int64(3), dto).Return(updated, nil)
+	postCache.On("SetPost", mock.Anything, updated).Run(untilDone).Return(context.DeadlineExceeded)
+	postCache.On("DeletePost", mock.Anything, int64(3)).Return(nil).Once()
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	ctx := model.WithBudget(context.Background(), 500*time.Millisecond, budgetRatios)
+	start := time.Now()
+	got, err := d.UpdatePost(ctx, 1, 3, dto)
+
+	require.NoError(t, err)
+	assert.Same(t, updated, got)
+	assert.Less(t, time.Since(start), 250*time.Millisecond, "the cache gets a fifth of the budget, not all of it")
+	postCache.AssertExpectations(t)
+}
@@ -0,0 +1,249 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// bulkPost is a post of a bulk import that passed validation, normalized, with the item its
+// outcome goes to.
+type bulkPost struct {
+	item        *model.BulkCreateItem
+	dto         *model.CreatePostDTO
+	status      model.PostStatus
+	scheduledAt pgtype.Timestamptz
+	author      *model.User
+}
+
+// BulkCreatePosts imports posts on behalf of actorID, for content migrations. Each post is
+// validated as CreatePost validates it and each distinct author is verified once. Valid posts
+// are inserted model.BulkCreateChunkSize at a time, one transaction per chunk, together with
+// their media and tags. A post that fails fails only its own item: a chunk whose transaction
+// fails is imported again one post at a time to find the culprit. The call itself fails only
+// for a bad request. A post without a source is recorded as model.PostSourceImport.
+func (s *PostService) BulkCreatePosts(ctx context.Context, actorID int64, posts []*model.CreatePostDTO) (_ *model.BulkCreateResult, err error) {
+	defer observePostOperation(s.metrics, "bulk_create", time.Now(), &err)
+	started := time.Now()
+	if actorID <= 0 {
+		return nil, fmt.Errorf("%w: actor id must be positive", custom_errors.ErrInvalidInput)
+	}
+	if len(posts) == 0 || len(posts) > model.MaxBulkCreatePosts {
+		return nil, fmt.Errorf("%w: a bulk import takes 1 to %d posts, got %d",
+			custom_errors.ErrInvalidInput, model.MaxBulkCreatePosts, len(posts))
+	}
+
+	result := &model.BulkCreateResult{Items: make([]*model.BulkCreateItem, len(posts))}
+	pending := make([]*bulkPost, 0, len(posts))
+	for i, dto := range posts {
+		item := &model.BulkCreateItem{Index: i}
+		result.Items[i] = item
+		if dto == nil {
+			item.Err = fmt.Errorf("%w: empty post", custom_errors.ErrInvalidInput)
+			continue
+		}
+		if dto.Source == "" {
+			imported := *dto
+			imported.Source = model.PostSourceImport
+			dto = &imported
+		}
+		post := s.normalizeCreate(dto)
+		if err := s.limits.ValidateCreate(post); err != nil {
+			item.Err = err
+			continue
+		}
+		status, scheduledAt, err := s.scheduleOf(post)
+		if err != nil {
+			item.Err = err
+			continue
+		}
+		pending = append(pending, &bulkPost{item: item, dto: post, status: status, scheduledAt: scheduledAt})
+	}
+
+	pending = s.verifyBulkAuthors(ctx, pending)
+
+	for start := 0; start < len(pending); start += model.BulkCreateChunkSize {
+		chunk := pending[start:min(start+model.BulkCreateChunkSize, len(pending))]
+		err := s.insertBulkChunk(ctx, chunk)
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			err = model.CallerError(ctx, err)
+			for _, post := range pending[start:] {
+				post.item.Err = err
+			}
+			break
+		}
+		s.log.Warn("Bulk import chunk failed, importing its posts one by one",
+			slog.Int64("actor_id", actorID),
+			slog.Int("first_index", chunk[0].item.Index),
+			slog.Int("count", len(chunk)),
+			slog.String("error", err.Error()))
+		for _, post := range chunk {
+			post.item.Err = s.insertBulkChunk(ctx, []*bulkPost{post})
+		}
+	}
+
+	for _, item := range result.Items {
+		if item.Err != nil {
+			result.Failed++
+		} else {
+			result.Created++
+		}
+	}
+	s.metrics.RecordBulkCreate(result.Created, result.Failed, time.Since(started))
+	s.log.Info("Bulk import finished",
+		slog.Int64("actor_id", actorID),
+		slog.Int("created", result.Created),
+		slog.Int("failed", result.Failed),
+		slog.Duration("took", time.Since(started)))
+	return result, nil
+}
+
+// verifyBulkAuthors asks the user service about each distinct author of pending once and
+// returns the posts whose author exists, with their author; the posts of the others fail.
+func (s *PostService) verifyBulkAuthors(ctx context.Context, pending []*bulkPost) []*bulkPost {
+	var (
+		mu         sync.Mutex
+		authorErrs = make(map[int64]error)
+		authors    = make(map[int64]*model.User)
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(s.limits.HydrationConcurrency, 1))
+	for _, post := range pending {
+		authorID := post.dto.AuthorID
+		mu.Lock()
+		_, seen := authorErrs[authorID]
+		authorErrs[authorID] = nil
+		mu.Unlock()
+		if seen {
+			continue
+		}
+		g.Go(func() error {
+			author, err := s.userClient.GetUser(gctx, authorID)
+			switch {
+			case err == nil:
+				mu.Lock()
+				authors[authorID] = author
+				mu.Unlock()
+				return nil
+			case errors.Is(err, custom_errors.ErrUserNotFound):
+				s.log.Debug("Author of bulk imported posts not found", slog.Int64("author_id", authorID))
+				err = custom_errors.ErrUserNotFound
+			default:
+				s.log.Error("Failed to get author from user service", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+				err = custom_errors.ErrExternalServiceError
+			}
+			mu.Lock()
+			authorErrs[authorID] = err
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	verified := pending[:0]
+	for _, post := range pending {
+		if err := authorErrs[post.dto.AuthorID]; err != nil {
+			post.item.Err = err
+			continue
+		}
+		post.author = authors[post.dto.AuthorID]
+		verified = append(verified, post)
+	}
+	return verified
+}
+
+// insertBulkChunk creates the posts of chunk with their media and tags in one transaction,
+// each kind of row in a single statement, and fills in their items on success.
+func (s *PostService) insertBulkChunk(ctx context.Context, chunk []*bulkPost) error {
+	var created []*model.Post
+	now := s.now()
+	err := s.runInTx(ctx, "bulk_create", func(ctx context.Context, tx postgres.Transaction) error {
+		newPosts := make([]*model.Post, len(chunk))
+		for i, post := range chunk {
+			newPosts[i] = &model.Post{
+				AuthorID:    post.dto.AuthorID,
+				Title:       post.dto.Title,
+				Content:     post.dto.Content,
+				Status:      post.status,
+				Visibility:  post.dto.Visibility,
+				ScheduledAt: post.scheduledAt,
+				Source:      post.dto.Source,
+			}
+			if post.dto.Language != "" {
+				newPosts[i].Language = &post.dto.Language
+			}
+			if post.author != nil {
+				newPosts[i].SetAuthorSnapshot(post.author, now)
+			}
+		}
+		var err error
+		created, err = tx.PostRepository().CreateMany(ctx, newPosts)
+		if err != nil {
+			s.log.Error("Failed to create bulk imported posts", slog.Int("count", len(chunk)), slog.String("error", err.Error()))
+			return err
+		}
+
+		var media []*model.PostMedia
+		for i, post := range chunk {
+			media = append(media, newPostMedia(created[i].ID, post.dto.MediaItems)...)
+		}
+		if err := tx.MediaRepository().AttachMany(ctx, media); err != nil {
+			s.log.Debug("Failed to attach media to bulk imported posts", slog.Int("count", len(media)), slog.String("error", err.Error()))
+			return err
+		}
+
+		var names []string
+		seen := make(map[string]bool)
+		for _, post := range chunk {
+			for _, name := range post.dto.Tags {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+		if len(names) == 0 {
+			return nil
+		}
+		tagRepo := tx.TagRepository()
+		tags, err := s.createTags(ctx, tagRepo, names)
+		if err != nil {
+			return err
+		}
+		tagIDs := make(map[string]int64, len(tags))
+		for _, tag := range tags {
+			tagIDs[tag.Name] = tag.ID
+		}
+		var links []*model.PostTag
+		for i, post := range chunk {
+			for _, name := range post.dto.Tags {
+				links = append(links, &model.PostTag{PostID: created[i].ID, TagID: tagIDs[name]})
+			}
+		}
+		if err := tagRepo.LinkPosts(ctx, links); err != nil {
+			s.log.Debug("Failed to tag bulk imported posts", slog.Int("count", len(links)), slog.String("error", err.Error()))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, post := range chunk {
+		post.item.Post = created[i]
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+func TestPostService_BulkCreatePosts(t *testing.T) {
+	users := &countingUsers{}
+	s, _ := newMemoryServiceWith(t, memoryServiceDeps{users: users})
+	ctx := context.Background()
+
+	// 250 posts span three chunks. Every 50th post has neither title nor media and two belong to author 10,
+	// whom the user service does not know.
+	posts := make([]*model.CreatePostDTO, 250)
+	for i := range posts {
+		posts[i] = &model.CreatePostDTO{
+			AuthorID: int64(i%5 + 1),
+			Title:    fmt.Sprintf("Imported %d", i),
+			Tags:     []string{"Imported", fmt.Sprintf("batch-%d", i%3)},
+			MediaItems: []*model.PostMediaInput{
+				{URL: fmt.Sprintf("https://example.com/%d.jpg", i), Type: model.MediaTypeImage, Position: 1},
+			},
+		}
+		if i%50 == 49 {
+			posts[i].Title = ""
+			posts[i].MediaItems = nil
+		}
+	}
+	posts[7].AuthorID = 10
+	posts[8].AuthorID = 10
+
+	result, err := s.BulkCreatePosts(ctx, 99, posts)
+
+	require.NoError(t, err)
+	require.Len(t, result.Items, 250)
+	assert.Equal(t, 243, result.Created)
+	assert.Equal(t, 7, result.Failed)
+	for i, item := range result.Items {
+		assert.Equal(t, i, item.Index)
+	}
+	var validation *model.ValidationError
+	assert.ErrorAs(t, result.Items[49].Err, &validation)
+	assert.ErrorIs(t, result.Items[7].Err, custom_errors.ErrUserNotFound)
+	assert.ErrorIs(t, result.Items[8].Err, custom_errors.ErrUserNotFound)
+	for authorID, calls := range users.calls {
+		assert.Equal(t, 1, calls, "author %d is verified once", authorID)
+	}
+
+	created := result.Items[200].Post
+	require.NotNil(t, created)
+	assert.Equal(t, "Imported 200", created.Title)
+	stored, err := s.GetPostByID(ctx, created.ID, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"imported", "batch-2"}, tagNames(stored.Tags))
+	require.Len(t, stored.Media, 1)
+	assert.Equal(t, "https://example.com/200.jpg", stored.Media[0].URL)
+}
+
+func TestPostService_BulkCreatePosts_RejectsBadRequests(t *testing.T) {
+	s := newPinService(t, 0)
+	ctx := context.Background()
+	post := &model.CreatePostDTO{AuthorID: 1, Title: "Imported"}
+
+	_, err := s.BulkCreatePosts(ctx, 0, []*model.CreatePostDTO{post})
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+	_, err = s.BulkCreatePosts(ctx, 1, nil)
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+	tooMany := make([]*model.CreatePostDTO, model.MaxBulkCreatePosts+1)
+	for i := range tooMany {
+		tooMany[i] = post
+	}
+	_, err = s.BulkCreatePosts(ctx, 1, tooMany)
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+}
+
+func TestPostService_BulkCreatePosts_IsolatesAFailingPost(t *testing.T) {
+	d := newTxTestDeps(t)
+	d.service.userClient.(*user_client_mock.Client).On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+	d.tx.On("Commit", mock.Anything).Return(nil)
+	nextID := int64(0)
+	d.postRepo.On("CreateMany", mock.Anything, mock.Anything).Return(func(_ context.Context, posts []*model.Post) ([]*model.Post, error) {
+		created := make([]*model.Post, len(posts))
+		for i, post := range posts {
+			nextID++
+			created[i] = &model.Post{ID: nextID, AuthorID: post.AuthorID, Title: post.Title}
+		}
+		return created, nil
+	})
+	hasBadMedia := func(media []*model.PostMedia) bool {
+		for _, m := range media {
+			if m.URL == "https://example.com/bad.jpg" {
+				return true
+			}
+		}
+		return false
+	}
+	d.mediaRepo.On("AttachMany", mock.Anything, mock.MatchedBy(hasBadMedia)).Return(model.ErrMediaDuplicate)
+	d.mediaRepo.On("AttachMany", mock.Anything, mock.Anything).Return(nil)
+
+	posts := make([]*model.CreatePostDTO, 3)
+	for i := range posts {
+		posts[i] = &model.CreatePostDTO{AuthorID: 1, Title: fmt.Sprintf("Imported %d", i), MediaItems: []*model.PostMediaInput{
+			{URL: fmt.Sprintf("https://example.com/%d.jpg", i), Type: model.MediaTypeImage, Position: 1},
+		}}
+	}
+	posts[1].MediaItems[0].URL = "https://example.com/bad.jpg"
+
+	result, err := d.service.BulkCreatePosts(context.Background(), 1, posts)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.ErrorIs(t, result.Items[1].Err, model.ErrMediaDuplicate)
+	assert.Nil(t, result.Items[1].Post)
+	require.NotNil(t, result.Items[0].Post)
+	require.NotNil(t, result.Items[2].Post)
+	assert.Equal(t, "Imported 2", result.Items[2].Post.Title)
+	d.uow.AssertNumberOfCalls(t, "Begin", 4)
+	d.tx.AssertNumberOfCalls(t, "Commit", 2)
+}
@@ -0,0 +1,94 @@
+package post_service
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	cacheBreakerThreshold = 5
+	cacheBreakerCooldown  = 30 * time.Second
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// cacheBreaker stops cache reads after threshold consecutive cache errors. Once cooldown
+// has passed it lets a single probe through: success closes it, failure opens it again.
+type cacheBreaker struct {
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	threshold int
+	cooldown  time.Duration
+	openedAt  time.Time
+	now       func() time.Time
+	onChange  func(available bool)
+}
+
+func newCacheBreaker(threshold int, cooldown time.Duration, onChange func(available bool)) *cacheBreaker {
+	return &cacheBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		onChange:  onChange,
+	}
+}
+
+func (b *cacheBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *cacheBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	if b.state != breakerClosed {
+		b.state = breakerClosed
+		b.onChange(true)
+	}
+}
+
+func (b *cacheBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerHalfOpen:
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	case breakerClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.state = breakerOpen
+			b.openedAt = b.now()
+			b.onChange(false)
+		}
+	}
+}
+
+func (b *cacheBreaker) State() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,134 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_service_mock "pinstack-post-service/mocks/post"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestBreaker(clock *fakeClock, availability *[]bool) *cacheBreaker {
+	b := newCacheBreaker(3, time.Minute, func(available bool) {
+		*availability = append(*availability, available)
+	})
+	b.now = clock.Now
+	return b
+}
+
+func TestCacheBreaker_Transitions(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var availability []bool
+	b := newTestBreaker(clock, &availability)
+
+	t.Run("Stays closed below threshold", func(t *testing.T) {
+		b.Failure()
+		b.Failure()
+		assert.Equal(t, breakerClosed, b.State())
+		assert.True(t, b.Allow())
+	})
+
+	t.Run("Success resets the failure count", func(t *testing.T) {
+		b.Success()
+		b.Failure()
+		b.Failure()
+		assert.Equal(t, breakerClosed, b.State())
+	})
+
+	t.Run("Opens at threshold", func(t *testing.T) {
+		b.Failure()
+		assert.Equal(t, breakerOpen, b.State())
+		assert.False(t, b.Allow())
+		assert.Equal(t, []bool{false}, availability)
+	})
+
+	t.Run("Half-open after cooldown lets a single probe through", func(t *testing.T) {
+		clock.now = clock.now.Add(time.Minute)
+		assert.True(t, b.Allow())
+		assert.Equal(t, breakerHalfOpen, b.State())
+		assert.False(t, b.Allow(), "only one probe while half-open")
+	})
+
+	t.Run("Failed probe reopens", func(t *testing.T) {
+		b.Failure()
+		assert.Equal(t, breakerOpen, b.State())
+		assert.False(t, b.Allow())
+		assert.Equal(t, []bool{false}, availability, "availability is reported once per outage")
+	})
+
+	t.Run("Successful probe closes", func(t *testing.T) {
+		clock.now = clock.now.Add(time.Minute)
+		require.True(t, b.Allow())
+		b.Success()
+		assert.Equal(t, breakerClosed, b.State())
+		assert.True(t, b.Allow())
+		assert.Equal(t, []bool{false, true}, availability)
+	})
+}
+
+func TestPostServiceCacheDecorator_BreakerSkipsCacheWhileOpen(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	service := new(post_service_mock.Service)
+	userCache := new(cache_mock.UserCache)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+
+	post := &model.PostDetailed{Post: &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusPublished}}
+
+	postCache.On("GetPost", mock.Anything, int64(10)).Return(nil, errors.New("redis: i/o timeout"))
+	service.On("GetPostByID", mock.Anything, int64(10), (*int64)(nil)).Return(post, nil)
+	batcher.On("NewBatch").Return(batch)
+	batch.On("SetPost", post)
+	batch.On("Exec", mock.Anything).Return(errors.New("redis: i/o timeout"))
+
+	d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, log, metrics)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	d.breaker.now = clock.Now
+
+	// Each request fails one read and one refill, so the circuit opens during the third request.
+	for i := 0; i < 3; i++ {
+		got, err := d.GetPostByID(context.Background(), 10, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), got.Post.ID)
+	}
+	require.Equal(t, breakerOpen, d.breaker.State())
+	postCache.AssertNumberOfCalls(t, "GetPost", 3)
+	batch.AssertNumberOfCalls(t, "Exec", 2)
+
+	got, err := d.GetPostByID(context.Background(), 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), got.Post.ID)
+	postCache.AssertNumberOfCalls(t, "GetPost", 3)
+	batch.AssertNumberOfCalls(t, "Exec", 2)
+
+	// After the cooldown a probe succeeds and the cache is used again.
+	postCache.ExpectedCalls = nil
+	postCache.On("GetPost", mock.Anything, int64(10)).Return(nil, custom_errors.ErrCacheMiss)
+	batch.ExpectedCalls = nil
+	batch.On("SetPost", post)
+	batch.On("Exec", mock.Anything).Return(nil)
+	clock.now = clock.now.Add(cacheBreakerCooldown)
+
+	_, err = d.GetPostByID(context.Background(), 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, breakerClosed, d.breaker.State())
+}
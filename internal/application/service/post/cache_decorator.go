@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
+	"strconv"
 	"time"
 
 	model "pinstack-post-service/internal/domain/models"
@@ -13,198 +15,498 @@ import (
 	post_service "pinstack-post-service/internal/domain/ports/input/post"
 
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"golang.org/x/sync/singleflight"
 )
 
+// PostServiceCacheDecorator keeps Redis in step with the service. Post writes touch:
+//   - create: sets the post and, unless a fresh cached user vouched for the author, the
+//     author; adds one to the author's post count when the post is published, and advances
+//     the newest post time of its tags when it is also listed;
+//   - update: sets the post, and advances the newest post time of the tags it gained, or of
+//     all its tags when its visibility changed;
+//   - delete: drops the post and the author's posts metadata;
+//   - publish: drops the post and the author's posts metadata, and advances the newest post
+//     time of its tags;
+//   - cancel schedule: drops the post; scheduled posts are not in the post count;
+//   - tag rename or merge: drops every affected post, in the background when an
+//     invalidation queue is set.
+//
+// Create also records the post among the author's recent posts, which ListPosts hands to the
+// service when the author lists their own timeline: a list that has not seen the post yet
+// still shows it to its author. Lists themselves are not cached, so there is no list entry
+// to bypass; other requesters see the post once the list does.
+//
+// Delete and publish drop the post count instead of adjusting it: the decorator cannot tell
+// whether the deleted post was a draft, or whether the publish changed anything. The cached
+// user itself is refreshed by reads and never deleted for a post write.
 type PostServiceCacheDecorator struct {
 	service   post_service.Service
 	userCache cache.UserCache
 	postCache cache.PostCache
+	batcher   cache.CacheBatcher
 	log       output.Logger
 	metrics   output.MetricsProvider
+
+	// postGroup and userGroup coalesce concurrent cache misses for the same post or author.
+	postGroup singleflight.Group
+	userGroup singleflight.Group
+
+	// breaker skips cache reads and refills while the cache keeps failing. Invalidations of
+	// existing posts (update, delete, publish) are always attempted, so a recovering cache
+	// never serves a post that changed while the circuit was open.
+	breaker *cacheBreaker
+
+	// invalidations, when set, drops the posts of tag renames and merges in the background.
+	invalidations *InvalidationQueue
+
+	now func() time.Time
 }
 
+var errCacheUnavailable = errors.New("cache circuit is open")
+
 func NewPostServiceCacheDecorator(
 	service post_service.Service,
 	userCache cache.UserCache,
 	postCache cache.PostCache,
+	batcher cache.CacheBatcher,
 	log output.Logger,
 	metrics output.MetricsProvider,
-) post_service.Service {
+) *PostServiceCacheDecorator {
 	return &PostServiceCacheDecorator{
 		service:   service,
 		userCache: userCache,
 		postCache: postCache,
+		batcher:   batcher,
 		log:       log,
 		metrics:   metrics,
+		breaker:   newCacheBreaker(cacheBreakerThreshold, cacheBreakerCooldown, metrics.SetCacheAvailable),
+		now:       time.Now,
 	}
 }
 
+// InvalidateInBackground hands the posts of tag renames and merges to queue instead of
+// dropping them before the call returns. A tag change can touch thousands of posts, and the
+// admin making it does not read them back right away.
+func (d *PostServiceCacheDecorator) InvalidateInBackground(queue *InvalidationQueue) {
+	d.invalidations = queue
+}
+
 func (d *PostServiceCacheDecorator) CreatePost(ctx context.Context, post *model.CreatePostDTO) (*model.PostDetailed, error) {
 	d.log.Debug("Creating post with cache decorator", slog.Int64("author_id", post.AuthorID))
 
+	cachedAuthor := d.verifyAuthor(ctx, post.AuthorID)
+	if cachedAuthor != nil {
+		verified := *post
+		verified.VerifiedAuthor = cachedAuthor
+		post = &verified
+	}
+
 	result, err := d.service.CreatePost(ctx, post)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := d.userCache.DeleteUser(ctx, post.AuthorID); err != nil {
-		d.log.Warn("Failed to invalidate user cache after post creation",
-			slog.Int64("user_id", post.AuthorID),
-			slog.String("error", err.Error()))
+	batch := d.batcher.NewBatch()
+	operations := []string{"post_set"}
+	if result.Post.Status == model.PostStatusPublished {
+		batch.AdjustUserPostCount(post.AuthorID, 1)
+		operations = append(operations, "user_post_count_adjust")
 	}
+	if queueTagLatestPosts(batch, result.Post, result.Tags) {
+		operations = append(operations, "tag_latest_post_advance")
+	}
+	batch.SetPost(result)
+	batch.AddRecentPost(post.AuthorID, result.Post.ID)
+	operations = append(operations, "recent_post_add")
+	// Only an author fetched from the user service is written back: rewriting the cached one
+	// would restart its age, and it would never have to be verified again.
+	if result.Author != nil && cachedAuthor == nil {
+		batch.SetUser(result.Author)
+		operations = append(operations, "user_set")
+	}
+	d.execBatch(ctx, "post_create", batch, operations, "Failed to update cache after post creation",
+		slog.Int64("post_id", result.Post.ID),
+		slog.Int64("user_id", post.AuthorID))
 
-	start := time.Now()
-	if err := d.postCache.SetPost(ctx, result); err != nil {
-		d.log.Warn("Failed to cache created post",
-			slog.Int64("post_id", result.Post.ID),
-			slog.String("error", err.Error()))
-		d.metrics.RecordCacheOperationDuration("post_set", time.Since(start))
-	} else {
-		d.metrics.RecordCacheOperationDuration("post_set", time.Since(start))
+	return result, nil
+}
+
+// BulkCreatePosts does not cache the imported posts: a migration would evict the posts
+// readers are reading for ones nobody has asked for yet. Only the cached post counts of their
+// authors are adjusted.
+func (d *PostServiceCacheDecorator) BulkCreatePosts(ctx context.Context, actorID int64, posts []*model.CreatePostDTO) (*model.BulkCreateResult, error) {
+	result, err := d.service.BulkCreatePosts(ctx, actorID, posts)
+	if err != nil {
+		return nil, err
 	}
 
-	if result.Author != nil {
-		userCacheStart := time.Now()
-		if err := d.userCache.SetUser(ctx, result.Author); err != nil {
-			d.log.Warn("Failed to cache author after post creation",
-				slog.Int64("user_id", result.Author.ID),
-				slog.String("error", err.Error()))
-			d.metrics.RecordCacheOperationDuration("user_set", time.Since(userCacheStart))
-		} else {
-			d.metrics.RecordCacheOperationDuration("user_set", time.Since(userCacheStart))
+	published := make(map[int64]int64)
+	for _, item := range result.Items {
+		if item.Post != nil && item.Post.Status == model.PostStatusPublished {
+			published[item.Post.AuthorID]++
 		}
 	}
-
+	if len(published) == 0 {
+		return result, nil
+	}
+	batch := d.batcher.NewBatch()
+	for authorID, count := range published {
+		batch.AdjustUserPostCount(authorID, count)
+	}
+	d.execBatch(ctx, "post_bulk_create", batch, []string{"user_post_count_adjust"}, "Failed to update cache after bulk import",
+		slog.Int64("actor_id", actorID),
+		slog.Int("authors", len(published)))
 	return result, nil
 }
 
-func (d *PostServiceCacheDecorator) GetPostByID(ctx context.Context, id int64) (*model.PostDetailed, error) {
+// GetPostByID counts each read by whether the cache served it; the service times only the
+// reads that reach it.
+func (d *PostServiceCacheDecorator) GetPostByID(ctx context.Context, id int64, requesterID *int64) (*model.PostDetailed, error) {
 	d.log.Debug("Getting post by ID with cache decorator", slog.Int64("post_id", id))
 
+	if cachedPost, ok := d.getCachedPost(ctx, "post_get", id); ok {
+		d.metrics.IncrementPostReads("get", true)
+		model.RecordCacheInfo(ctx, model.CacheInfo{
+			Hit:    true,
+			Source: model.CacheSourceCache,
+			Age:    d.servedEntryAge(cachedPost),
+			TTL:    cachedPost.CacheTTL,
+		})
+		if cachedPost.Post != nil {
+			if err := cachedPost.Post.CheckAccess(requesterID); err != nil {
+				d.log.Debug("Cached post is hidden from requester", slog.Int64("post_id", id), slog.String("error", err.Error()))
+				return nil, err
+			}
+		}
+		return cachedPost.RedactedFor(requesterID), nil
+	}
+
+	d.log.Debug("Post cache miss, fetching from service", slog.Int64("post_id", id))
+	d.metrics.IncrementPostReads("get", false)
+	model.RecordCacheInfo(ctx, model.CacheInfo{Source: model.CacheSourceDatabase})
+
+	// Visibility depends on the requester, so only requests from the same requester share a fetch.
+	key := strconv.FormatInt(id, 10)
+	if requesterID != nil {
+		key += ":" + strconv.FormatInt(*requesterID, 10)
+	}
+	shared, err := d.coalesce(ctx, &d.postGroup, "post_get", key, func(ctx context.Context) (interface{}, error) {
+		post, err := d.service.GetPostByID(ctx, id, requesterID)
+		if err != nil {
+			return nil, err
+		}
+
+		batch := d.batcher.NewBatch()
+		var operations []string
+		// The cache holds full posts only; a post redacted for this requester is cached by
+		// its author's next read.
+		if !post.Redacted {
+			batch.SetPost(post)
+			operations = append(operations, "post_set")
+		}
+		// An author built from the post's snapshot lacks most of the user and is not cached.
+		if post.Author != nil && !post.AuthorFromSnapshot {
+			batch.SetUser(post.Author)
+			operations = append(operations, "user_set")
+		}
+		if len(operations) > 0 {
+			d.execBatch(ctx, "post_get", batch, operations, "Failed to cache post",
+				slog.Int64("post_id", id))
+		}
+
+		return post, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shared.(*model.PostDetailed).Clone(), nil
+}
+
+// getCachedPost reads post id from the cache within the cache's share of the request budget
+// of operation.
+func (d *PostServiceCacheDecorator) getCachedPost(ctx context.Context, operation string, id int64) (*model.PostDetailed, bool) {
+	if !d.breaker.Allow() {
+		return nil, false
+	}
+
 	cacheStart := time.Now()
-	cachedPost, err := d.postCache.GetPost(ctx, id)
+	var cachedPost *model.PostDetailed
+	err := inStage(ctx, d.metrics, operation, model.BudgetStageCache, func(ctx context.Context) (err error) {
+		cachedPost, err = d.postCache.GetPost(ctx, id)
+		return err
+	})
 	if err == nil {
+		d.breaker.Success()
 		d.log.Debug("Post found in cache", slog.Int64("post_id", id))
-		d.metrics.IncrementCacheHits()
+		d.metrics.IncrementCacheHits("post")
 		d.metrics.RecordCacheHitDuration("post_get", time.Since(cacheStart))
-		return cachedPost, nil
+		return cachedPost, true
 	}
 
+	if model.IsCallerError(err) {
+		// Cut short by the request budget: the cache did not fail, and the service is left
+		// to fail fast when nothing remains.
+		d.log.Debug("Ran out of time reading post from cache", slog.Int64("post_id", id), slog.String("error", err.Error()))
+		return nil, false
+	}
 	if !errors.Is(err, custom_errors.ErrCacheMiss) {
+		d.breaker.Failure()
 		d.log.Warn("Failed to get post from cache",
 			slog.Int64("post_id", id),
 			slog.String("error", err.Error()))
 		d.metrics.RecordCacheOperationDuration("post_get", time.Since(cacheStart))
 	} else {
-		d.metrics.IncrementCacheMisses()
+		d.breaker.Success()
+		d.metrics.IncrementCacheMisses("post")
 		d.metrics.RecordCacheMissDuration("post_get", time.Since(cacheStart))
 	}
+	return nil, false
+}
 
-	d.log.Debug("Post cache miss, fetching from service", slog.Int64("post_id", id))
-	post, err := d.service.GetPostByID(ctx, id)
-	if err != nil {
+// GetPostsByIDs answers what it can from one multi-get on the post cache and asks the
+// service only for the rest, caching whatever the service returns. Cached drafts count as
+// misses, so the service decides their visibility as it does for uncached ones.
+func (d *PostServiceCacheDecorator) GetPostsByIDs(ctx context.Context, ids []int64) ([]*model.PostDetailed, error) {
+	if err := model.ValidatePostIDs(ids); err != nil {
 		return nil, err
 	}
+	ids = uniquePostIDs(ids)
+	found := d.getCachedPosts(ctx, ids)
 
-	setCacheStart := time.Now()
-	if err := d.postCache.SetPost(ctx, post); err != nil {
-		d.log.Warn("Failed to cache post",
-			slog.Int64("post_id", id),
+	missing := make([]int64, 0, len(ids)-len(found))
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		d.log.Debug("Post cache batch miss, fetching from service", slog.Int("missing", len(missing)), slog.Int("requested", len(ids)))
+		fetched, err := d.service.GetPostsByIDs(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+
+		batch := d.batcher.NewBatch()
+		operations := make([]string, 0, len(fetched))
+		authors := make(map[int64]bool)
+		for _, post := range fetched {
+			found[post.Post.ID] = post
+			if post.Redacted {
+				continue
+			}
+			batch.SetPost(post)
+			operations = append(operations, "post_set")
+			if post.Author != nil && !post.AuthorFromSnapshot && !authors[post.Author.ID] {
+				authors[post.Author.ID] = true
+				batch.SetUser(post.Author)
+				operations = append(operations, "user_set")
+			}
+		}
+		if batch.Len() > 0 {
+			d.execBatch(ctx, "post_get_by_ids", batch, operations, "Failed to cache posts from batch lookup", slog.Int("posts", len(fetched)))
+		}
+	}
+
+	result := make([]*model.PostDetailed, 0, len(found))
+	for _, id := range ids {
+		if post, ok := found[id]; ok {
+			result = append(result, post)
+		}
+	}
+	return result, nil
+}
+
+// getCachedPosts returns the cached, published posts among ids, rejected ones redacted. Any
+// cache failure is a miss for every id.
+func (d *PostServiceCacheDecorator) getCachedPosts(ctx context.Context, ids []int64) map[int64]*model.PostDetailed {
+	found := make(map[int64]*model.PostDetailed, len(ids))
+	if len(ids) == 0 || !d.breaker.Allow() {
+		return found
+	}
+
+	cacheStart := time.Now()
+	cached, err := d.postCache.GetPosts(ctx, ids)
+	if err != nil {
+		d.breaker.Failure()
+		d.log.Warn("Failed to get posts from cache",
+			slog.Int("posts", len(ids)),
 			slog.String("error", err.Error()))
-		d.metrics.RecordCacheOperationDuration("post_set", time.Since(setCacheStart))
-	} else {
-		d.metrics.RecordCacheOperationDuration("post_set", time.Since(setCacheStart))
+		d.metrics.RecordCacheOperationDuration("post_mget", time.Since(cacheStart))
+		return found
 	}
+	d.breaker.Success()
 
-	if post.Author != nil {
-		userCacheStart := time.Now()
-		if err := d.userCache.SetUser(ctx, post.Author); err != nil {
-			d.log.Warn("Failed to cache author",
-				slog.Int64("user_id", post.Author.ID),
-				slog.String("error", err.Error()))
-			d.metrics.RecordCacheOperationDuration("user_set", time.Since(userCacheStart))
-		} else {
-			d.metrics.RecordCacheOperationDuration("user_set", time.Since(userCacheStart))
+	for id, post := range cached {
+		if post.Post != nil && post.Post.CheckAccess(nil) == nil {
+			d.servedEntryAge(post)
+			found[id] = post.RedactedFor(nil)
+			d.metrics.IncrementCacheHits("post")
 		}
 	}
+	for range len(ids) - len(found) {
+		d.metrics.IncrementCacheMisses("post")
+	}
+	d.metrics.RecordCacheOperationDuration("post_mget", time.Since(cacheStart))
+	return found
+}
 
-	return post, nil
+// servedEntryAge records the age of the cache entry post was read from and returns it; an
+// entry that does not record its write time has age zero and is not recorded.
+func (d *PostServiceCacheDecorator) servedEntryAge(post *model.PostDetailed) time.Duration {
+	if post.CachedAt.IsZero() {
+		return 0
+	}
+	age := max(d.now().Sub(post.CachedAt), 0)
+	d.metrics.RecordCacheEntryAge("post", age)
+	return age
 }
 
+// ListPosts caches the authors of the page. Posts of a list are never written to the post
+// cache, and summaries are cut by the service after hydration, so cached posts stay full
+// whatever view a list asked for.
 func (d *PostServiceCacheDecorator) ListPosts(ctx context.Context, filters *model.PostFilters) ([]*model.PostDetailed, int, error) {
 	d.log.Debug("Listing posts with cache decorator")
 
+	if recent := d.getRecentPosts(ctx, filters); len(recent) > 0 {
+		withRecent := *filters
+		withRecent.RecentPostIDs = recent
+		filters = &withRecent
+	}
 	posts, total, err := d.service.ListPosts(ctx, filters)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	authorIDs := make(map[int64]bool)
+	// The service fetched each distinct author once; look each up in the cache once too.
+	byAuthor := make(map[int64][]*model.PostDetailed)
 	for _, post := range posts {
 		if post.Post != nil {
-			authorIDs[post.Post.AuthorID] = true
+			byAuthor[post.Post.AuthorID] = append(byAuthor[post.Post.AuthorID], post)
 		}
 	}
 
-	for authorID := range authorIDs {
+	batch := d.batcher.NewBatch()
+	cachedAuthors := 0
+	for authorID, authorPosts := range byAuthor {
 		userGetStart := time.Now()
-		if cachedUser, err := d.userCache.GetUser(ctx, authorID); err == nil {
+		if cachedUser, err := d.getCachedAuthor(ctx, authorID); err == nil {
+			cachedAuthors++
 			d.log.Debug("Author found in cache", slog.Int64("author_id", authorID))
-			d.metrics.IncrementCacheHits()
+			d.metrics.IncrementCacheHits("user")
 			d.metrics.RecordCacheHitDuration("user_get", time.Since(userGetStart))
-			for _, post := range posts {
-				if post.Post != nil && post.Post.AuthorID == authorID {
-					post.Author = cachedUser
-				}
+			for _, post := range authorPosts {
+				post.Author = cachedUser
+				post.AuthorFromSnapshot = false
 			}
 		} else {
 			if errors.Is(err, custom_errors.ErrCacheMiss) {
-				d.metrics.IncrementCacheMisses()
+				d.metrics.IncrementCacheMisses("user")
 				d.metrics.RecordCacheMissDuration("user_get", time.Since(userGetStart))
 			} else {
 				d.metrics.RecordCacheOperationDuration("user_get", time.Since(userGetStart))
 			}
-			for _, post := range posts {
-				if post.Post != nil && post.Post.AuthorID == authorID && post.Author != nil {
-					userSetStart := time.Now()
-					if setErr := d.userCache.SetUser(ctx, post.Author); setErr != nil {
-						d.log.Warn("Failed to cache author from list",
-							slog.Int64("author_id", authorID),
-							slog.String("error", setErr.Error()))
-						d.metrics.RecordCacheOperationDuration("user_set", time.Since(userSetStart))
-					} else {
-						d.metrics.RecordCacheOperationDuration("user_set", time.Since(userSetStart))
-					}
-					break
-				}
+			if first := authorPosts[0]; first.Author != nil && !first.AuthorFromSnapshot {
+				batch.SetUser(first.Author)
 			}
 		}
 	}
 
+	if batch.Len() > 0 {
+		operations := make([]string, batch.Len())
+		for i := range operations {
+			operations[i] = "user_set"
+		}
+		d.execBatch(ctx, "post_list", batch, operations, "Failed to cache authors from list")
+	}
+
+	model.RecordCacheInfo(ctx, model.CacheInfo{
+		Hit:    len(byAuthor) > 0 && cachedAuthors == len(byAuthor),
+		Source: model.CacheSourceDatabase,
+	})
 	return posts, total, nil
 }
 
-func (d *PostServiceCacheDecorator) UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) error {
+func (d *PostServiceCacheDecorator) UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) (*model.PostDetailed, error) {
 	d.log.Debug("Updating post with cache decorator",
 		slog.Int64("post_id", id),
 		slog.Int64("user_id", userID))
 
-	err := d.service.UpdatePost(ctx, userID, id, post)
+	result, err := d.service.UpdatePost(ctx, userID, id, post)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	cacheStart := time.Now()
-	if err := d.postCache.DeletePost(ctx, id); err != nil {
-		d.log.Warn("Failed to invalidate post cache after update",
-			slog.Int64("post_id", id),
-			slog.String("error", err.Error()))
+	if result.Author == nil {
+		// Caching a post without its author would serve the degraded response until the entry expires.
+		err := inStage(ctx, d.metrics, "post_update", model.BudgetStageCache, func(ctx context.Context) error {
+			return d.postCache.DeletePost(ctx, id)
+		})
+		if err != nil {
+			d.cacheFailure(err, "Failed to invalidate post cache after update", slog.Int64("post_id", id))
+		} else {
+			d.breaker.Success()
+		}
 		d.metrics.RecordCacheOperationDuration("post_delete", time.Since(cacheStart))
+		d.advanceUpdatedTags(ctx, result)
+		return result, nil
+	}
+	err = inStage(ctx, d.metrics, "post_update", model.BudgetStageCache, func(ctx context.Context) error {
+		return d.postCache.SetPost(ctx, result)
+	})
+	if err != nil {
+		d.cacheFailure(err, "Failed to refresh post cache after update, invalidating", slog.Int64("post_id", id))
+		// Not bounded by the cache's share: the entry the failed write left behind is stale.
+		if delErr := d.postCache.DeletePost(ctx, id); delErr != nil {
+			d.log.Warn("Failed to invalidate post cache after update",
+				slog.Int64("post_id", id),
+				slog.String("error", delErr.Error()))
+		}
 	} else {
-		d.metrics.RecordCacheOperationDuration("post_delete", time.Since(cacheStart))
+		d.breaker.Success()
 	}
+	d.metrics.RecordCacheOperationDuration("post_set", time.Since(cacheStart))
+	d.advanceUpdatedTags(ctx, result)
 
-	return nil
+	return result, nil
+}
+
+// advanceUpdatedTags advances the newest post time of the tags an update made the post show
+// up under: the tags it gained, or all of its tags when its visibility changed.
+func (d *PostServiceCacheDecorator) advanceUpdatedTags(ctx context.Context, result *model.PostDetailed) {
+	if result.Changes == nil {
+		return
+	}
+	tags := result.Changes.TagsAdded
+	if slices.Contains(result.Changes.FieldsChanged, "visibility") {
+		tags = result.Tags
+	}
+	if len(tags) == 0 || !listedAnonymously(result.Post) {
+		return
+	}
+	batch := d.batcher.NewBatch()
+	queueTagLatestPosts(batch, result.Post, tags)
+	d.execBatch(ctx, "post_update", batch, []string{"tag_latest_post_advance"}, "Failed to advance tag latest posts after update",
+		slog.Int64("post_id", result.Post.ID))
+}
+
+// listedAnonymously reports whether an anonymous ListPosts shows post, which is what the newest
+// post time of a tag tracks.
+func listedAnonymously(post *model.Post) bool {
+	return post.Status == model.PostStatusPublished && post.IsListed() && !post.IsRejected()
+}
+
+// queueTagLatestPosts queues advancing the newest post time of tags to when post became
+// visible, if an anonymous ListPosts shows it, and reports whether it queued anything.
+func queueTagLatestPosts(batch cache.CacheBatch, post *model.Post, tags []*model.Tag) bool {
+	if len(tags) == 0 || !listedAnonymously(post) {
+		return false
+	}
+	for _, tag := range tags {
+		batch.AdvanceTagLatestPost(tag.Name, post.VisibleSince())
+	}
+	return true
 }
 
 func (d *PostServiceCacheDecorator) DeletePost(ctx context.Context, userID int64, id int64) error {
@@ -217,15 +519,496 @@ func (d *PostServiceCacheDecorator) DeletePost(ctx context.Context, userID int64
 		return err
 	}
 
+	// Only the author may delete a post, so userID is the author whose post data changed.
+	batch := d.batcher.NewBatch()
+	batch.DeletePost(id)
+	batch.InvalidateUserPostsMeta(userID)
+
 	cacheStart := time.Now()
-	if err := d.postCache.DeletePost(ctx, id); err != nil {
-		d.log.Warn("Failed to invalidate post cache after deletion",
+	err = inStage(ctx, d.metrics, "post_delete", model.BudgetStageCache, batch.Exec)
+	d.metrics.RecordCacheOperationDuration("post_delete", time.Since(cacheStart))
+	if err != nil {
+		d.cacheFailure(err, "Failed to invalidate cache after post deletion",
 			slog.Int64("post_id", id),
+			slog.Int64("user_id", userID))
+		return nil
+	}
+	d.breaker.Success()
+
+	return nil
+}
+
+func (d *PostServiceCacheDecorator) ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (*model.ModerationAction, error) {
+	d.log.Debug("Force deleting post with cache decorator",
+		slog.Int64("post_id", postID),
+		slog.Int64("actor_id", actorID))
+
+	action, err := d.service.ForceDeletePost(ctx, actorID, postID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := d.batcher.NewBatch()
+	batch.DeletePost(postID)
+	batch.InvalidateUserPostsMeta(action.AuthorID)
+
+	cacheStart := time.Now()
+	err = batch.Exec(ctx)
+	d.metrics.RecordCacheOperationDuration("post_delete", time.Since(cacheStart))
+	if err != nil {
+		d.breaker.Failure()
+		d.log.Warn("Failed to invalidate cache after post force deletion",
+			slog.Int64("post_id", postID),
+			slog.Int64("author_id", action.AuthorID),
 			slog.String("error", err.Error()))
-		d.metrics.RecordCacheOperationDuration("post_delete", time.Since(cacheStart))
+		return action, nil
+	}
+	d.breaker.Success()
+
+	return action, nil
+}
+
+// SetModerationStatus drops the cached post. The next read caches it again with its new
+// status, and GetPostByID redacts it per requester after reading it from the cache.
+func (d *PostServiceCacheDecorator) SetModerationStatus(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason) (*model.Post, error) {
+	post, err := d.service.SetModerationStatus(ctx, actorID, postID, status, reason)
+	if err != nil {
+		return nil, err
+	}
+	d.invalidatePosts(ctx, []int64{postID}, "Failed to invalidate post after moderation status change", slog.Int64("post_id", postID))
+	return post, nil
+}
+
+func (d *PostServiceCacheDecorator) PublishPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	d.log.Debug("Publishing post with cache decorator",
+		slog.Int64("post_id", id),
+		slog.Int64("user_id", userID))
+
+	result, err := d.service.PublishPost(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := d.batcher.NewBatch()
+	batch.DeletePost(id)
+	batch.InvalidateUserPostsMeta(userID)
+	queueTagLatestPosts(batch, result.Post, result.Tags)
+
+	cacheStart := time.Now()
+	err = inStage(ctx, d.metrics, "post_publish", model.BudgetStageCache, batch.Exec)
+	d.metrics.RecordCacheOperationDuration("post_delete", time.Since(cacheStart))
+	if err != nil {
+		d.cacheFailure(err, "Failed to invalidate cache after publish",
+			slog.Int64("post_id", id),
+			slog.Int64("user_id", userID))
 	} else {
-		d.metrics.RecordCacheOperationDuration("post_delete", time.Since(cacheStart))
+		d.breaker.Success()
 	}
 
-	return nil
+	return result, nil
+}
+
+func (d *PostServiceCacheDecorator) CancelScheduledPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	d.log.Debug("Cancelling scheduled post with cache decorator",
+		slog.Int64("post_id", id),
+		slog.Int64("user_id", userID))
+
+	result, err := d.service.CancelScheduledPost(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := d.batcher.NewBatch()
+	batch.DeletePost(id)
+
+	cacheStart := time.Now()
+	err = batch.Exec(ctx)
+	d.metrics.RecordCacheOperationDuration("post_delete", time.Since(cacheStart))
+	if err != nil {
+		d.breaker.Failure()
+		d.log.Warn("Failed to invalidate cache after cancelling schedule",
+			slog.Int64("post_id", id),
+			slog.String("error", err.Error()))
+	} else {
+		d.breaker.Success()
+	}
+
+	return result, nil
+}
+
+// PinPost and UnpinPost drop the cached copies of every post whose pinned state changed,
+// the previously pinned post included. Lists are not cached, so they need nothing.
+func (d *PostServiceCacheDecorator) PinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	change, err := d.service.PinPost(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	d.invalidatePosts(ctx, change.AffectedPostIDs, "Failed to invalidate posts after pin", slog.Int64("post_id", id))
+	return change, nil
+}
+
+func (d *PostServiceCacheDecorator) UnpinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	change, err := d.service.UnpinPost(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	d.invalidatePosts(ctx, change.AffectedPostIDs, "Failed to invalidate posts after unpin", slog.Int64("post_id", id))
+	return change, nil
+}
+
+// GetPostRevisions is not cached: only the author reads revisions, and rarely.
+func (d *PostServiceCacheDecorator) GetPostRevisions(ctx context.Context, userID int64, postID int64, limit, offset int) ([]*model.PostRevision, int, error) {
+	return d.service.GetPostRevisions(ctx, userID, postID, limit, offset)
+}
+
+// GetPostTags answers from the cached post when there is one. Tag lists are part of the
+// cached post, so every path that invalidates a post (update, delete, tag rename or merge)
+// also invalidates its tags. Counts change whenever any post is tagged and are never cached.
+func (d *PostServiceCacheDecorator) GetPostTags(ctx context.Context, postID int64, requesterID *int64, includeCounts bool) ([]*model.Tag, error) {
+	if !includeCounts {
+		if cachedPost, ok := d.getCachedPost(ctx, "post_tags", postID); ok && cachedPost.Post != nil {
+			if err := cachedPost.Post.CheckAccess(requesterID); err != nil {
+				d.log.Debug("Cached post is hidden from requester", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+				return nil, err
+			}
+			tags := make([]*model.Tag, len(cachedPost.Tags))
+			for i, tag := range cachedPost.Tags {
+				tags[i] = tag.Clone()
+			}
+			return tags, nil
+		}
+	}
+	return d.service.GetPostTags(ctx, postID, requesterID, includeCounts)
+}
+
+// GetPostMedia is not cached: the cached post holds only the media a response carries, and
+// the pages past them are read rarely.
+func (d *PostServiceCacheDecorator) GetPostMedia(ctx context.Context, postID int64, requesterID *int64, limit, offset int) ([]*model.PostMedia, int, error) {
+	return d.service.GetPostMedia(ctx, postID, requesterID, limit, offset)
+}
+
+func (d *PostServiceCacheDecorator) RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error) {
+	result, err := d.service.RenameTag(ctx, tagID, name)
+	if err != nil {
+		return nil, err
+	}
+	d.invalidateTagPosts(ctx, result.AffectedPostIDs, "Failed to invalidate posts after tag rename", slog.Int64("tag_id", tagID))
+	return result, nil
+}
+
+func (d *PostServiceCacheDecorator) MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error) {
+	result, err := d.service.MergeTags(ctx, sourceIDs, destID)
+	if err != nil {
+		return nil, err
+	}
+	d.invalidateTagPosts(ctx, result.AffectedPostIDs, "Failed to invalidate posts after tag merge", slog.Int64("tag_id", destID))
+	return result, nil
+}
+
+// SuggestTags serves suggestions without an author from the cache. They are the same for
+// every caller, so popular prefixes are computed once per TTL; tag writes do not invalidate
+// them. Author-boosted suggestions differ per author and always go to the service.
+func (d *PostServiceCacheDecorator) SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error) {
+	if authorID != nil {
+		return d.service.SuggestTags(ctx, prefix, limit, authorID)
+	}
+
+	key := model.NormalizeTagName(prefix)
+	if limit == 0 {
+		limit = model.MaxTagSuggestions
+	}
+	if d.breaker.Allow() {
+		tags, err := d.postCache.GetTagSuggestions(ctx, key, limit)
+		switch {
+		case err == nil:
+			d.breaker.Success()
+			return tags, nil
+		case errors.Is(err, custom_errors.ErrCacheMiss):
+			d.breaker.Success()
+		default:
+			d.breaker.Failure()
+			d.log.Warn("Failed to get tag suggestions from cache",
+				slog.String("prefix", key),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	tags, err := d.service.SuggestTags(ctx, prefix, limit, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.breaker.Allow() {
+		if err := d.postCache.SetTagSuggestions(ctx, key, limit, tags); err != nil {
+			d.breaker.Failure()
+			d.log.Warn("Failed to cache tag suggestions",
+				slog.String("prefix", key),
+				slog.String("error", err.Error()))
+		} else {
+			d.breaker.Success()
+		}
+	}
+	return tags, nil
+}
+
+// invalidatePosts drops cached posts whose tags or pinned state changed in one DeletePosts
+// call. Like the other invalidations it is attempted even while the circuit is open.
+func (d *PostServiceCacheDecorator) invalidatePosts(ctx context.Context, ids []int64, failureMsg string, attrs ...any) {
+	if len(ids) == 0 {
+		return
+	}
+
+	start := time.Now()
+	err := d.postCache.DeletePosts(ctx, ids)
+	d.metrics.RecordCacheOperationDuration("post_delete", time.Since(start))
+	if err != nil {
+		d.breaker.Failure()
+		d.log.Warn(failureMsg, append(attrs, slog.Int("posts", len(ids)), slog.String("error", err.Error()))...)
+		return
+	}
+	d.breaker.Success()
+}
+
+// invalidateTagPosts queues the posts of a tag change when there is an invalidation queue,
+// and drops them right away otherwise.
+func (d *PostServiceCacheDecorator) invalidateTagPosts(ctx context.Context, ids []int64, failureMsg string, attrs ...any) {
+	if d.invalidations == nil {
+		d.invalidatePosts(ctx, ids, failureMsg, attrs...)
+		return
+	}
+	d.invalidations.Enqueue(ids...)
+}
+
+// execBatch flushes queued cache writes in one round trip, within the cache's share of the
+// request budget of stageOperation. A failed batch is only logged: the next read falls
+// through to the service. Each queued operation is timed under its own label so dashboards
+// built on the per-operation metrics keep working.
+func (d *PostServiceCacheDecorator) execBatch(ctx context.Context, stageOperation string, batch cache.CacheBatch, operations []string, failureMsg string, attrs ...any) {
+	if !d.breaker.Allow() {
+		d.log.Debug("Cache circuit open, skipping cache batch", attrs...)
+		return
+	}
+
+	start := time.Now()
+	err := inStage(ctx, d.metrics, stageOperation, model.BudgetStageCache, batch.Exec)
+	elapsed := time.Since(start)
+	for _, operation := range operations {
+		d.metrics.RecordCacheOperationDuration(operation, elapsed)
+	}
+	if err != nil {
+		d.cacheFailure(err, failureMsg, attrs...)
+		return
+	}
+	d.breaker.Success()
+}
+
+// cacheFailure counts a failed cache call against the breaker and logs it with msg and
+// attrs. A call cut short by the request budget is only noted at debug level: the cache did
+// not fail.
+func (d *PostServiceCacheDecorator) cacheFailure(err error, msg string, attrs ...any) {
+	attrs = append(attrs, slog.String("error", err.Error()))
+	if model.IsCallerError(err) {
+		d.log.Debug(msg, attrs...)
+		return
+	}
+	d.breaker.Failure()
+	d.log.Warn(msg, attrs...)
+}
+
+// verifyAuthor returns the cached author of a new post when the entry is fresh enough to
+// vouch for them, and nil when the user service has to be asked. An author the user service
+// does not know is never cached, so a rejection is always confirmed by the user service.
+func (d *PostServiceCacheDecorator) verifyAuthor(ctx context.Context, authorID int64) *model.User {
+	if !d.breaker.Allow() {
+		d.metrics.IncrementAuthorVerifications("miss")
+		return nil
+	}
+	var user *model.User
+	err := inStage(ctx, d.metrics, "post_create", model.BudgetStageCache, func(ctx context.Context) (err error) {
+		user, err = d.userCache.GetFreshUser(ctx, authorID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrCacheMiss) {
+			d.breaker.Success()
+		} else {
+			d.cacheFailure(err, "Failed to verify author from cache", slog.Int64("author_id", authorID))
+		}
+		d.metrics.IncrementAuthorVerifications("miss")
+		return nil
+	}
+	d.breaker.Success()
+	d.metrics.IncrementAuthorVerifications("hit")
+	return user
+}
+
+// getRecentPosts returns the posts the requester recently created when filters list their
+// own timeline, and nothing otherwise or when the cache cannot tell.
+func (d *PostServiceCacheDecorator) getRecentPosts(ctx context.Context, filters *model.PostFilters) []int64 {
+	if !filters.ListsOwnTimeline() || !d.breaker.Allow() {
+		return nil
+	}
+	var recent []int64
+	err := inStage(ctx, d.metrics, "post_list", model.BudgetStageCache, func(ctx context.Context) (err error) {
+		recent, err = d.userCache.GetRecentPosts(ctx, *filters.AuthorID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrCacheMiss) {
+			d.breaker.Success()
+			return nil
+		}
+		d.cacheFailure(err, "Failed to get recent posts from cache, listing without them",
+			slog.Int64("user_id", *filters.AuthorID))
+		return nil
+	}
+	d.breaker.Success()
+	return recent
+}
+
+func (d *PostServiceCacheDecorator) getCachedAuthor(ctx context.Context, authorID int64) (*model.User, error) {
+	shared, err := d.coalesce(ctx, &d.userGroup, "user_get", strconv.FormatInt(authorID, 10), func(ctx context.Context) (interface{}, error) {
+		if !d.breaker.Allow() {
+			return nil, errCacheUnavailable
+		}
+		var user *model.User
+		err := inStage(ctx, d.metrics, "post_list", model.BudgetStageCache, func(ctx context.Context) (err error) {
+			user, err = d.userCache.GetUser(ctx, authorID)
+			return err
+		})
+		if err != nil && !errors.Is(err, custom_errors.ErrCacheMiss) {
+			if !model.IsCallerError(err) {
+				d.breaker.Failure()
+			}
+			return nil, err
+		}
+		d.breaker.Success()
+		return user, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shared.(*model.User).Clone(), nil
+}
+
+// coalesce runs fetch once per key for all concurrent callers. The fetch is detached from
+// the leader's cancellation, so a caller that gives up returns ctx.Err() without aborting
+// the work the other callers are waiting for. Shared values must be cloned before use.
+func (d *PostServiceCacheDecorator) coalesce(
+	ctx context.Context,
+	group *singleflight.Group,
+	operation string,
+	key string,
+	fetch func(ctx context.Context) (interface{}, error),
+) (interface{}, error) {
+	led := false
+	ch := group.DoChan(key, func() (interface{}, error) {
+		led = true
+		return fetch(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if !led {
+			d.metrics.IncrementCoalescedRequests(operation)
+		}
+		return res.Val, res.Err
+	}
+}
+
+// ExportAuthorPosts reads straight from the database: an export must be complete and current,
+// and caching every exported post would evict the hot ones.
+func (d *PostServiceCacheDecorator) ExportAuthorPosts(ctx context.Context, authorID int64, send func(*model.PostDetailed) error) error {
+	return d.service.ExportAuthorPosts(ctx, authorID, send)
+}
+
+// GetAuthorPostCount answers from the cached count when there is one and caches what the
+// service counted otherwise. Create keeps a cached count current; deletes and publishes drop
+// it, and writes that bypass the decorator are bounded by the count's TTL.
+func (d *PostServiceCacheDecorator) GetAuthorPostCount(ctx context.Context, authorID int64) (*model.AuthorPostCount, error) {
+	if authorID > 0 && d.breaker.Allow() {
+		count, err := d.userCache.GetUserPostCount(ctx, authorID)
+		switch {
+		case err == nil:
+			d.breaker.Success()
+			return &model.AuthorPostCount{AuthorID: authorID, Count: count, Cached: true}, nil
+		case errors.Is(err, custom_errors.ErrCacheMiss):
+			d.breaker.Success()
+		default:
+			d.breaker.Failure()
+			d.log.Warn("Failed to get author post count from cache",
+				slog.Int64("author_id", authorID),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	result, err := d.service.GetAuthorPostCount(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.breaker.Allow() {
+		if err := d.userCache.SetUserPostCount(ctx, authorID, result.Count); err != nil {
+			d.breaker.Failure()
+			d.log.Warn("Failed to cache author post count",
+				slog.Int64("author_id", authorID),
+				slog.String("error", err.Error()))
+		} else {
+			d.breaker.Success()
+		}
+	}
+	return result, nil
+}
+
+// HasPostsSince answers polled tags from their cached newest post time and asks the service
+// only about the tags without one, caching what it finds. A tag with no post after since has
+// no time to cache, so it is asked about again at the next poll. Posts that bypass the
+// decorator, such as scheduled ones, are missed until the tag's entry expires.
+func (d *PostServiceCacheDecorator) HasPostsSince(ctx context.Context, tagNames []string, since time.Time) (*model.TagActivity, error) {
+	names := model.NormalizeTags(tagNames)
+	if model.ValidateTagPoll(names, since) != nil || !d.breaker.Allow() {
+		return d.service.HasPostsSince(ctx, tagNames, since)
+	}
+
+	cached, err := d.postCache.GetTagLatestPosts(ctx, names)
+	if err != nil {
+		d.breaker.Failure()
+		d.log.Warn("Failed to get tag latest posts from cache",
+			slog.Int("tags", len(names)),
+			slog.String("error", err.Error()))
+		return d.service.HasPostsSince(ctx, tagNames, since)
+	}
+	d.breaker.Success()
+
+	activity := model.NewTagActivity(names, cached, since)
+	var missing []string
+	for _, name := range names {
+		if _, ok := cached[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		activity.Cached = true
+		return activity, nil
+	}
+
+	fetched, err := d.service.HasPostsSince(ctx, missing, since)
+	if err != nil {
+		return nil, err
+	}
+	activity.Merge(fetched)
+
+	if len(fetched.Latest) > 0 && d.breaker.Allow() {
+		if err := d.postCache.AdvanceTagLatestPosts(ctx, fetched.Latest); err != nil {
+			d.breaker.Failure()
+			d.log.Warn("Failed to cache tag latest posts",
+				slog.Int("tags", len(fetched.Latest)),
+				slog.String("error", err.Error()))
+		} else {
+			d.breaker.Success()
+		}
+	}
+	return activity, nil
 }
@@ -0,0 +1,915 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/testsupport"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_service_mock "pinstack-post-service/mocks/post"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+func TestPostServiceCacheDecorator_GetPostByID_CacheMissUsesOneBatch(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	author := &model.User{ID: 1, Username: "author"}
+	post := &model.PostDetailed{Post: &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusPublished}, Author: author}
+
+	tests := []struct {
+		name    string
+		execErr error
+	}{
+		{name: "batch succeeds", execErr: nil},
+		{name: "batch failure is not fatal", execErr: errors.New("redis: connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_service_mock.Service)
+			userCache := new(cache_mock.UserCache)
+			postCache := new(cache_mock.PostCache)
+			batcher := new(cache_mock.CacheBatcher)
+			batch := new(cache_mock.CacheBatch)
+
+			postCache.On("GetPost", mock.Anything, int64(10)).Return(nil, custom_errors.ErrCacheMiss)
+			service.On("GetPostByID", mock.Anything, int64(10), (*int64)(nil)).Return(post, nil)
+			batcher.On("NewBatch").Return(batch)
+			batch.On("SetPost", post).Once()
+			batch.On("SetUser", author).Once()
+			batch.On("Exec", mock.Anything).Return(tt.execErr).Once()
+
+			d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, log, metrics)
+
+			got, err := d.GetPostByID(context.Background(), 10, nil)
+			require.NoError(t, err)
+			assert.Equal(t, post, got)
+
+			batch.AssertExpectations(t)
+			userCache.AssertNotCalled(t, "SetUser", mock.Anything, mock.Anything)
+			postCache.AssertNotCalled(t, "SetPost", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPostServiceCacheDecorator_CreatePost_BatchesInvalidationAndRefill(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	tests := []struct {
+		name       string
+		status     model.PostStatus
+		wantAdjust bool
+	}{
+		{name: "published post counts", status: model.PostStatusPublished, wantAdjust: true},
+		{name: "draft is not counted", status: model.PostStatusDraft, wantAdjust: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_service_mock.Service)
+			userCache := new(cache_mock.UserCache)
+			postCache := new(cache_mock.PostCache)
+			batcher := new(cache_mock.CacheBatcher)
+			batch := new(cache_mock.CacheBatch)
+
+			dto := &model.CreatePostDTO{AuthorID: 1, Title: "Test Post"}
+			created := &model.PostDetailed{Post: &model.Post{ID: 10, AuthorID: 1, Status: tt.status}, Author: &model.User{ID: 1}}
+
+			userCache.On("GetFreshUser", mock.Anything, int64(1)).Return(nil, custom_errors.ErrCacheMiss)
+			service.On("CreatePost", mock.Anything, dto).Return(created, nil)
+			batcher.On("NewBatch").Return(batch)
+			if tt.wantAdjust {
+				batch.On("AdjustUserPostCount", int64(1), int64(1)).Once()
+			}
+			batch.On("SetPost", created).Once()
+			batch.On("AddRecentPost", int64(1), int64(10)).Once()
+			batch.On("SetUser", created.Author).Once()
+			batch.On("Exec", mock.Anything).Return(nil).Once()
+
+			d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, log, metrics)
+
+			got, err := d.CreatePost(context.Background(), dto)
+			require.NoError(t, err)
+			assert.Equal(t, created, got)
+
+			batch.AssertExpectations(t)
+			if !tt.wantAdjust {
+				batch.AssertNotCalled(t, "AdjustUserPostCount", mock.Anything, mock.Anything)
+			}
+			batch.AssertNotCalled(t, "DeleteUser", mock.Anything)
+			userCache.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPostServiceCacheDecorator_BulkCreatePosts_OnlyAdjustsPostCounts(t *testing.T) {
+	service := new(post_service_mock.Service)
+	userCache := new(cache_mock.UserCache)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+
+	posts := []*model.CreatePostDTO{{AuthorID: 1}, {AuthorID: 1}, {AuthorID: 2}, {AuthorID: 2}, {AuthorID: 3}}
+	result := &model.BulkCreateResult{Items: []*model.BulkCreateItem{
+		{Index: 0, Post: &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusPublished}},
+		{Index: 1, Post: &model.Post{ID: 11, AuthorID: 1, Status: model.PostStatusPublished}},
+		{Index: 2, Post: &model.Post{ID: 12, AuthorID: 2, Status: model.PostStatusDraft}},
+		{Index: 3, Post: &model.Post{ID: 13, AuthorID: 2, Status: model.PostStatusPublished}},
+		{Index: 4, Err: custom_errors.ErrUserNotFound},
+	}, Created: 4, Failed: 1}
+	service.On("BulkCreatePosts", mock.Anything, int64(99), posts).Return(result, nil)
+	batcher.On("NewBatch").Return(batch)
+	batch.On("AdjustUserPostCount", int64(1), int64(2)).Once()
+	batch.On("AdjustUserPostCount", int64(2), int64(1)).Once()
+	batch.On("Exec", mock.Anything).Return(nil).Once()
+
+	d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	got, err := d.BulkCreatePosts(context.Background(), 99, posts)
+	require.NoError(t, err)
+	assert.Equal(t, result, got)
+
+	batch.AssertExpectations(t)
+	batch.AssertNotCalled(t, "SetPost", mock.Anything)
+	userCache.AssertNotCalled(t, "GetFreshUser", mock.Anything, mock.Anything)
+}
+
+func TestPostServiceCacheDecorator_ListPosts_SkipsEmptyBatch(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	service := new(post_service_mock.Service)
+	userCache := new(cache_mock.UserCache)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+
+	author := &model.User{ID: 1}
+	posts := []*model.PostDetailed{{Post: &model.Post{ID: 10, AuthorID: 1}, Author: author}}
+	filters := &model.PostFilters{}
+
+	service.On("ListPosts", mock.Anything, filters).Return(posts, 1, nil)
+	batcher.On("NewBatch").Return(batch)
+	userCache.On("GetUser", mock.Anything, int64(1)).Return(author, nil)
+	batch.On("Len").Return(0)
+
+	d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, log, metrics)
+
+	got, total, err := d.ListPosts(context.Background(), filters)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, got, 1)
+	batch.AssertNotCalled(t, "Exec", mock.Anything)
+}
+
+func TestPostServiceCacheDecorator_ListPosts_PassesRecentPostsToTheirAuthor(t *testing.T) {
+	author, other := int64(1), int64(2)
+	tests := []struct {
+		name       string
+		filters    model.PostFilters
+		recent     []int64
+		cacheErr   error
+		wantRecent []int64
+	}{
+		{name: "own timeline", filters: model.PostFilters{AuthorID: &author, RequesterID: &author}, recent: []int64{11, 10}, wantRecent: []int64{11, 10}},
+		{name: "nothing recent", filters: model.PostFilters{AuthorID: &author, RequesterID: &author}, cacheErr: custom_errors.ErrCacheMiss},
+		{name: "cache failure", filters: model.PostFilters{AuthorID: &author, RequesterID: &author}, cacheErr: errors.New("connection refused")},
+		{name: "another requester", filters: model.PostFilters{AuthorID: &author, RequesterID: &other}},
+		{name: "filtered timeline", filters: model.PostFilters{AuthorID: &author, RequesterID: &author, TagNames: []string{"go"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_service_mock.Service)
+			userCache := new(cache_mock.UserCache)
+			batcher := new(cache_mock.CacheBatcher)
+			batch := new(cache_mock.CacheBatch)
+			userCache.On("GetRecentPosts", mock.Anything, author).Return(tt.recent, tt.cacheErr).Maybe()
+			service.On("ListPosts", mock.Anything, mock.MatchedBy(func(f *model.PostFilters) bool {
+				return slices.Equal(f.RecentPostIDs, tt.wantRecent)
+			})).Return([]*model.PostDetailed{}, 0, nil).Once()
+			batcher.On("NewBatch").Return(batch)
+			batch.On("Len").Return(0)
+
+			d := NewPostServiceCacheDecorator(service, userCache, new(cache_mock.PostCache), batcher,
+				logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+			filters := tt.filters
+
+			_, _, err := d.ListPosts(context.Background(), &filters)
+
+			require.NoError(t, err)
+			service.AssertExpectations(t)
+			assert.Nil(t, filters.RecentPostIDs, "the caller's filters are not modified")
+			if !tt.filters.ListsOwnTimeline() {
+				userCache.AssertNotCalled(t, "GetRecentPosts", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestPostServiceCacheDecorator_ListPosts_LooksUpEachAuthorOnce(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	service := new(post_service_mock.Service)
+	userCache := new(cache_mock.UserCache)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+
+	fetched, cached, missing := &model.User{ID: 1}, &model.User{ID: 1, Username: "cached"}, &model.User{ID: 2}
+	posts := []*model.PostDetailed{
+		{Post: &model.Post{ID: 10, AuthorID: 1}, Author: fetched},
+		{Post: &model.Post{ID: 11, AuthorID: 2}, Author: missing},
+		{Post: &model.Post{ID: 12, AuthorID: 1}, Author: fetched},
+		{Post: &model.Post{ID: 13, AuthorID: 2}, Author: missing},
+	}
+	filters := &model.PostFilters{}
+
+	service.On("ListPosts", mock.Anything, filters).Return(posts, 4, nil)
+	batcher.On("NewBatch").Return(batch)
+	userCache.On("GetUser", mock.Anything, int64(1)).Return(cached, nil)
+	userCache.On("GetUser", mock.Anything, int64(2)).Return(nil, custom_errors.ErrCacheMiss)
+	batch.On("SetUser", missing).Return()
+	batch.On("Len").Return(1)
+	batch.On("Exec", mock.Anything).Return(nil)
+
+	d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, log, metrics)
+
+	got, _, err := d.ListPosts(context.Background(), filters)
+	require.NoError(t, err)
+	userCache.AssertNumberOfCalls(t, "GetUser", 2)
+	batch.AssertNumberOfCalls(t, "SetUser", 1)
+	assert.Equal(t, "cached", got[0].Author.Username)
+	assert.Same(t, got[0].Author, got[2].Author)
+	assert.Same(t, missing, got[3].Author)
+}
+
+func TestPostServiceCacheDecorator_RecordsCacheInfo(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	published := &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusPublished}
+
+	newDecorator := func(postCache *cache_mock.PostCache, service *post_service_mock.Service, userCache *cache_mock.UserCache) *PostServiceCacheDecorator {
+		batcher := new(cache_mock.CacheBatcher)
+		batch := new(cache_mock.CacheBatch)
+		batcher.On("NewBatch").Return(batch)
+		batch.On("SetPost", mock.Anything).Maybe()
+		batch.On("Len").Return(0).Maybe()
+		batch.On("Exec", mock.Anything).Return(nil).Maybe()
+		d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, log, metrics)
+		d.now = func() time.Time { return testsupport.FixedTime }
+		return d
+	}
+
+	t.Run("HitAgeComesFromTheEntry", func(t *testing.T) {
+		postCache := new(cache_mock.PostCache)
+		postCache.On("GetPost", mock.Anything, int64(10)).Return(&model.PostDetailed{
+			Post:     published,
+			CachedAt: testsupport.FixedTime.Add(-90 * time.Second),
+			CacheTTL: 4 * time.Minute,
+		}, nil)
+		d := newDecorator(postCache, new(post_service_mock.Service), new(cache_mock.UserCache))
+		ctx, info := model.WithCacheInfo(context.Background())
+
+		_, err := d.GetPostByID(ctx, 10, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.CacheInfo{Hit: true, Source: model.CacheSourceCache, Age: 90 * time.Second, TTL: 4 * time.Minute}, *info)
+	})
+
+	t.Run("EntryWithoutWriteTimeHasNoAge", func(t *testing.T) {
+		postCache := new(cache_mock.PostCache)
+		postCache.On("GetPost", mock.Anything, int64(10)).Return(&model.PostDetailed{Post: published, CacheTTL: time.Minute}, nil)
+		d := newDecorator(postCache, new(post_service_mock.Service), new(cache_mock.UserCache))
+		ctx, info := model.WithCacheInfo(context.Background())
+
+		_, err := d.GetPostByID(ctx, 10, nil)
+
+		require.NoError(t, err)
+		assert.True(t, info.Hit)
+		assert.Zero(t, info.Age)
+	})
+
+	t.Run("MissIsServedByTheDatabase", func(t *testing.T) {
+		postCache := new(cache_mock.PostCache)
+		service := new(post_service_mock.Service)
+		postCache.On("GetPost", mock.Anything, int64(10)).Return(nil, custom_errors.ErrCacheMiss)
+		service.On("GetPostByID", mock.Anything, int64(10), (*int64)(nil)).Return(&model.PostDetailed{Post: published}, nil)
+		d := newDecorator(postCache, service, new(cache_mock.UserCache))
+		ctx, info := model.WithCacheInfo(context.Background())
+
+		_, err := d.GetPostByID(ctx, 10, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.CacheInfo{Source: model.CacheSourceDatabase}, *info)
+	})
+
+	t.Run("ListPostsReportsCachedAuthors", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		userCache := new(cache_mock.UserCache)
+		filters := &model.PostFilters{}
+		service.On("ListPosts", mock.Anything, filters).Return([]*model.PostDetailed{{Post: published, Author: &model.User{ID: 1}}}, 1, nil)
+		userCache.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+		d := newDecorator(new(cache_mock.PostCache), service, userCache)
+		ctx, info := model.WithCacheInfo(context.Background())
+
+		_, _, err := d.ListPosts(ctx, filters)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.CacheInfo{Hit: true, Source: model.CacheSourceDatabase}, *info)
+	})
+}
+
+func TestPostServiceCacheDecorator_GetPostByID_CoalescesConcurrentMisses(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	const callers = 50
+
+	service := new(post_service_mock.Service)
+	userCache := new(cache_mock.UserCache)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+
+	content := "hot"
+	post := &model.PostDetailed{
+		Post:   &model.Post{ID: 10, AuthorID: 1, Content: &content, Status: model.PostStatusPublished},
+		Author: &model.User{ID: 1},
+		Tags:   []*model.Tag{{ID: 1, Name: "go"}},
+	}
+
+	var misses sync.WaitGroup
+	misses.Add(callers)
+	release := make(chan struct{})
+
+	postCache.On("GetPost", mock.Anything, int64(10)).Run(func(mock.Arguments) { misses.Done() }).Return(nil, custom_errors.ErrCacheMiss)
+	service.On("GetPostByID", mock.Anything, int64(10), (*int64)(nil)).Run(func(mock.Arguments) { <-release }).Return(post, nil)
+	batcher.On("NewBatch").Return(batch)
+	batch.On("SetPost", post)
+	batch.On("SetUser", post.Author)
+	batch.On("Exec", mock.Anything).Return(nil)
+
+	d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, log, metrics)
+
+	results := make([]*model.PostDetailed, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got, err := d.GetPostByID(context.Background(), 10, nil)
+			assert.NoError(t, err)
+			results[i] = got
+		}(i)
+	}
+
+	misses.Wait()
+	// Give the callers that already missed the cache time to join the in-flight fetch.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	service.AssertNumberOfCalls(t, "GetPostByID", 1)
+	batch.AssertNumberOfCalls(t, "Exec", 1)
+
+	// Every caller owns its copy: mutating one result must not leak into the others.
+	*results[0].Post.Content = "changed"
+	results[0].Tags[0].Name = "changed"
+	for _, got := range results[1:] {
+		require.NotNil(t, got)
+		assert.Equal(t, "hot", *got.Post.Content)
+		assert.Equal(t, "go", got.Tags[0].Name)
+	}
+}
+
+func TestPostServiceCacheDecorator_GetPostByID_WaiterCancellationDoesNotCancelLeader(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	service := new(post_service_mock.Service)
+	userCache := new(cache_mock.UserCache)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+
+	post := &model.PostDetailed{Post: &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusPublished}}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var leaderCtxErr atomic.Value
+
+	postCache.On("GetPost", mock.Anything, int64(10)).Return(nil, custom_errors.ErrCacheMiss)
+	service.On("GetPostByID", mock.Anything, int64(10), (*int64)(nil)).Run(func(args mock.Arguments) {
+		close(started)
+		<-release
+		leaderCtxErr.Store(fmt.Sprint(args.Get(0).(context.Context).Err()))
+	}).Return(post, nil)
+	batcher.On("NewBatch").Return(batch)
+	batch.On("SetPost", post)
+	batch.On("Exec", mock.Anything).Return(nil)
+
+	d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, log, metrics)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := d.GetPostByID(ctx, 10, nil)
+		errCh <- err
+	}()
+
+	<-started
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+
+	got, err := func() (*model.PostDetailed, error) {
+		done := make(chan struct{})
+		var got *model.PostDetailed
+		var err error
+		go func() {
+			got, err = d.GetPostByID(context.Background(), 10, nil)
+			close(done)
+		}()
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		<-done
+		return got, err
+	}()
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), got.Post.ID)
+	assert.Equal(t, "<nil>", leaderCtxErr.Load())
+	service.AssertNumberOfCalls(t, "GetPostByID", 1)
+}
+
+func TestPostServiceCacheDecorator_TagChangesInvalidateAffectedPosts(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	service := new(post_service_mock.Service)
+	userCache := new(cache_mock.UserCache)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+
+	renamed := &model.TagChange{Tag: &model.Tag{ID: 5, Name: "golang"}, AffectedPostIDs: []int64{1, 2}}
+	merged := &model.TagChange{Tag: &model.Tag{ID: 7, Name: "golang"}}
+
+	service.On("RenameTag", mock.Anything, int64(5), "golang").Return(renamed, nil)
+	service.On("MergeTags", mock.Anything, []int64{8}, int64(7)).Return(merged, nil)
+	postCache.On("DeletePosts", mock.Anything, []int64{1, 2}).Return(nil).Once()
+
+	d := NewPostServiceCacheDecorator(service, userCache, postCache, batcher, log, metrics)
+	d.breaker.state = breakerOpen
+
+	got, err := d.RenameTag(context.Background(), 5, "golang")
+	require.NoError(t, err)
+	assert.Equal(t, renamed, got)
+
+	// A merge that touched no posts has nothing to invalidate.
+	got, err = d.MergeTags(context.Background(), []int64{8}, 7)
+	require.NoError(t, err)
+	assert.Equal(t, merged, got)
+
+	postCache.AssertExpectations(t)
+	batcher.AssertNotCalled(t, "NewBatch")
+}
+
+func TestPostServiceCacheDecorator_TagChangesInvalidateInBackground(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	service := new(post_service_mock.Service)
+	postCache := new(cache_mock.PostCache)
+	merged := &model.TagChange{Tag: &model.Tag{ID: 7, Name: "golang"}, AffectedPostIDs: []int64{3, 1, 2}}
+
+	service.On("MergeTags", mock.Anything, []int64{8}, int64(7)).Return(merged, nil)
+	postCache.On("DeletePosts", mock.Anything, []int64{1, 2, 3}).Return(nil).Once()
+
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher), log, metrics)
+	queue := NewInvalidationQueue(postCache, 10, 0, time.Millisecond, time.Second, log, metrics)
+	d.InvalidateInBackground(queue)
+
+	_, err := d.MergeTags(context.Background(), []int64{8}, 7)
+	require.NoError(t, err)
+	postCache.AssertNotCalled(t, "DeletePosts", mock.Anything, mock.Anything)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	queue.Run(ctx)
+	postCache.AssertExpectations(t)
+}
+
+func TestPostServiceCacheDecorator_CancelScheduledPost_DropsThePost(t *testing.T) {
+	service := new(post_service_mock.Service)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+	draft := &model.PostDetailed{Post: &model.Post{ID: 3, AuthorID: 1, Status: model.PostStatusDraft}}
+
+	service.On("CancelScheduledPost", mock.Anything, int64(1), int64(3)).Return(draft, nil)
+	batcher.On("NewBatch").Return(batch).Once()
+	batch.On("DeletePost", int64(3)).Once()
+	batch.On("Exec", mock.Anything).Return(nil).Once()
+
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), new(cache_mock.PostCache), batcher,
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	got, err := d.CancelScheduledPost(context.Background(), 1, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, draft, got)
+	batch.AssertExpectations(t)
+	batch.AssertNotCalled(t, "InvalidateUserPostsMeta", mock.Anything)
+}
+
+func TestPostServiceCacheDecorator_PinPost_DropsThePreviouslyPinnedPostToo(t *testing.T) {
+	service := new(post_service_mock.Service)
+	postCache := new(cache_mock.PostCache)
+	pinned := &model.PinChange{Post: &model.PostDetailed{Post: &model.Post{ID: 3, AuthorID: 1}}, AffectedPostIDs: []int64{2, 3}}
+	unchanged := &model.PinChange{Post: &model.PostDetailed{Post: &model.Post{ID: 4, AuthorID: 1}}}
+
+	service.On("PinPost", mock.Anything, int64(1), int64(3)).Return(pinned, nil)
+	service.On("UnpinPost", mock.Anything, int64(1), int64(4)).Return(unchanged, nil)
+	postCache.On("DeletePosts", mock.Anything, []int64{2, 3}).Return(nil).Once()
+
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	got, err := d.PinPost(context.Background(), 1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, pinned, got)
+
+	// Unpinning a post that was not pinned has nothing to invalidate.
+	got, err = d.UnpinPost(context.Background(), 1, 4)
+	require.NoError(t, err)
+	assert.Equal(t, unchanged, got)
+
+	postCache.AssertExpectations(t)
+}
+
+func TestPostServiceCacheDecorator_UpdatePost_PassesTheChangeSummary(t *testing.T) {
+	service := new(post_service_mock.Service)
+	postCache := new(cache_mock.PostCache)
+	dto := &model.UpdatePostDTO{UserID: 1, Tags: []string{"go"}}
+	changes := &model.PostChangeSummary{TagsAdded: []*model.Tag{{ID: 4, Name: "go"}}}
+	updated := &model.PostDetailed{Post: &model.Post{ID: 3, AuthorID: 1}, Author: &model.User{ID: 1}, Tags: changes.TagsAdded, Changes: changes}
+
+	service.On("UpdatePost", mock.Anything, int64(1), int64(3), dto).Return(updated, nil)
+	postCache.On("SetPost", mock.Anything, updated).Return(nil).Once()
+
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	got, err := d.UpdatePost(context.Background(), 1, 3, dto)
+
+	require.NoError(t, err)
+	assert.Same(t, changes, got.Changes)
+	postCache.AssertExpectations(t)
+}
+
+func TestPostServiceCacheDecorator_UpdatePost_DropsThePostWhenTheAuthorIsUnavailable(t *testing.T) {
+	log := logger.New("test")
+	userClient := new(user_client_mock.Client)
+	postCache := new(cache_mock.PostCache)
+	service, _ := newMemoryServiceWith(t, memoryServiceDeps{users: userClient})
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+		log, prometheus.NewPrometheusMetricsProvider())
+
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "author"}, nil).Once()
+	created, err := service.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Before"})
+	require.NoError(t, err)
+
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(nil, errors.New("user service unavailable"))
+	postCache.On("DeletePost", mock.Anything, created.Post.ID).Return(nil).Once()
+	title := "After"
+
+	got, err := d.UpdatePost(context.Background(), 1, created.Post.ID, &model.UpdatePostDTO{UserID: 1, Title: &title})
+
+	require.NoError(t, err)
+	assert.Nil(t, got.Author)
+	assert.Equal(t, "After", got.Post.Title)
+	postCache.AssertExpectations(t)
+	postCache.AssertNotCalled(t, "SetPost", mock.Anything, mock.Anything)
+}
+
+func TestPostServiceCacheDecorator_UpdatePost_EmptyUpdateLeavesTheCache(t *testing.T) {
+	service := new(post_service_mock.Service)
+	postCache := new(cache_mock.PostCache)
+	dto := &model.UpdatePostDTO{UserID: 1}
+
+	service.On("UpdatePost", mock.Anything, int64(1), int64(3), dto).Return(nil, model.ErrEmptyUpdate)
+
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	_, err := d.UpdatePost(context.Background(), 1, 3, dto)
+
+	assert.ErrorIs(t, err, model.ErrEmptyUpdate)
+	postCache.AssertNotCalled(t, "SetPost", mock.Anything, mock.Anything)
+	postCache.AssertNotCalled(t, "DeletePost", mock.Anything, mock.Anything)
+}
+
+func TestPostServiceCacheDecorator_ForceDeletePost_InvalidatesTheAuthor(t *testing.T) {
+	service := new(post_service_mock.Service)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+	action := &model.ModerationAction{PostID: 3, AuthorID: 1, ActorID: 99, Reason: "spam links"}
+
+	service.On("ForceDeletePost", mock.Anything, int64(99), int64(3), "spam links").Return(action, nil)
+	batcher.On("NewBatch").Return(batch).Once()
+	batch.On("DeletePost", int64(3)).Once()
+	batch.On("InvalidateUserPostsMeta", int64(1)).Once()
+	batch.On("Exec", mock.Anything).Return(nil).Once()
+
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), new(cache_mock.PostCache), batcher,
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	got, err := d.ForceDeletePost(context.Background(), 99, 3, "spam links")
+
+	require.NoError(t, err)
+	assert.Equal(t, action, got)
+	batch.AssertExpectations(t)
+}
+
+func TestPostServiceCacheDecorator_SetModerationStatus_DropsThePost(t *testing.T) {
+	service := new(post_service_mock.Service)
+	postCache := new(cache_mock.PostCache)
+	reason := model.RejectionReasonSpam
+	rejected := &model.Post{ID: 3, AuthorID: 1, ModerationStatus: model.ModerationStatusRejected, RejectionReason: &reason}
+
+	service.On("SetModerationStatus", mock.Anything, int64(99), int64(3), model.ModerationStatusRejected, reason).Return(rejected, nil)
+	postCache.On("DeletePosts", mock.Anything, []int64{3}).Return(nil).Once()
+
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	got, err := d.SetModerationStatus(context.Background(), 99, 3, model.ModerationStatusRejected, reason)
+
+	require.NoError(t, err)
+	assert.Same(t, rejected, got)
+	postCache.AssertExpectations(t)
+}
+
+func TestPostServiceCacheDecorator_GetPostByID_RedactsRejectedPostPerRequester(t *testing.T) {
+	author, other := int64(1), int64(2)
+	cached := testsupport.NewPostDetailedBuilder().
+		WithID(10).
+		WithAuthor(author).
+		WithTitle("Rejected").
+		WithContent("Reported").
+		Post(func(b *testsupport.PostBuilder) { b.Rejected(model.RejectionReasonHarassment) }).
+		WithMedia("https://example.com/1.jpg").
+		Build()
+
+	tests := []struct {
+		name         string
+		requesterID  *int64
+		wantRedacted bool
+	}{
+		{name: "anonymous", wantRedacted: true},
+		{name: "another user", requesterID: &other, wantRedacted: true},
+		{name: "author", requesterID: &author},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postCache := new(cache_mock.PostCache)
+			postCache.On("GetPost", mock.Anything, int64(10)).Return(cached, nil)
+
+			d := NewPostServiceCacheDecorator(new(post_service_mock.Service), new(cache_mock.UserCache), postCache,
+				new(cache_mock.CacheBatcher), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			got, err := d.GetPostByID(context.Background(), 10, tt.requesterID)
+
+			require.NoError(t, err)
+			if tt.wantRedacted {
+				assert.True(t, got.Redacted)
+				assert.Empty(t, got.Post.Title)
+				assert.Nil(t, got.Post.Content)
+				assert.Empty(t, got.Media)
+				assert.Equal(t, model.ModerationStatusRejected, got.Post.ModerationStatus)
+				assert.Equal(t, "Rejected", cached.Post.Title, "the cached post is left whole")
+			} else {
+				assert.Same(t, cached, got)
+			}
+		})
+	}
+}
+
+func TestPostServiceCacheDecorator_GetPostByID_DoesNotCacheRedactedPost(t *testing.T) {
+	service := new(post_service_mock.Service)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+	redacted := testsupport.NewPostDetailedBuilder().
+		WithID(10).
+		Post(func(b *testsupport.PostBuilder) { b.Rejected(model.RejectionReasonSpam) }).
+		WithoutAuthor().
+		Build().
+		RedactedFor(nil)
+
+	postCache.On("GetPost", mock.Anything, int64(10)).Return(nil, custom_errors.ErrCacheMiss)
+	service.On("GetPostByID", mock.Anything, int64(10), (*int64)(nil)).Return(redacted, nil)
+	batcher.On("NewBatch").Return(batch)
+
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, batcher,
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	got, err := d.GetPostByID(context.Background(), 10, nil)
+
+	require.NoError(t, err)
+	assert.True(t, got.Redacted)
+	batch.AssertNotCalled(t, "SetPost", mock.Anything)
+	batch.AssertNotCalled(t, "Exec", mock.Anything)
+}
+
+func TestPostServiceCacheDecorator_GetPostByID_DoesNotCacheSnapshotAuthor(t *testing.T) {
+	service := new(post_service_mock.Service)
+	postCache := new(cache_mock.PostCache)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+	post := testsupport.NewPostDetailedBuilder().WithID(10).Build()
+	post.AuthorFromSnapshot = true
+
+	postCache.On("GetPost", mock.Anything, int64(10)).Return(nil, custom_errors.ErrCacheMiss)
+	service.On("GetPostByID", mock.Anything, int64(10), (*int64)(nil)).Return(post, nil)
+	batcher.On("NewBatch").Return(batch)
+	batch.On("SetPost", post).Once()
+	batch.On("Exec", mock.Anything).Return(nil).Once()
+
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, batcher,
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	got, err := d.GetPostByID(context.Background(), 10, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, post.Author, got.Author)
+	batch.AssertExpectations(t)
+	batch.AssertNotCalled(t, "SetUser", mock.Anything)
+}
+
+func TestPostServiceCacheDecorator_GetPostTags(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	authorID := int64(1)
+
+	cached := &model.PostDetailed{
+		Post: &model.Post{ID: 10, AuthorID: authorID, Status: model.PostStatusDraft},
+		Tags: []*model.Tag{{ID: 1, Name: "go"}},
+	}
+
+	t.Run("served from cached post", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		postCache.On("GetPost", mock.Anything, int64(10)).Return(cached, nil)
+
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher), log, metrics)
+
+		got, err := d.GetPostTags(context.Background(), 10, &authorID, false)
+		require.NoError(t, err)
+		assert.Equal(t, []*model.Tag{{ID: 1, Name: "go"}}, got)
+
+		got[0].Name = "mutated"
+		assert.Equal(t, "go", cached.Tags[0].Name)
+		service.AssertNotCalled(t, "GetPostTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("cached draft hidden from other users", func(t *testing.T) {
+		postCache := new(cache_mock.PostCache)
+		postCache.On("GetPost", mock.Anything, int64(10)).Return(cached, nil)
+
+		d := NewPostServiceCacheDecorator(new(post_service_mock.Service), new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher), log, metrics)
+
+		_, err := d.GetPostTags(context.Background(), 10, nil, false)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	})
+
+	t.Run("cache miss falls through to service", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		postCache.On("GetPost", mock.Anything, int64(10)).Return(nil, custom_errors.ErrCacheMiss)
+		service.On("GetPostTags", mock.Anything, int64(10), (*int64)(nil), false).Return([]*model.Tag{}, nil)
+
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher), log, metrics)
+
+		got, err := d.GetPostTags(context.Background(), 10, nil, false)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("counts bypass the cache", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		service.On("GetPostTags", mock.Anything, int64(10), &authorID, true).Return(cached.Tags, nil)
+
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher), log, metrics)
+
+		_, err := d.GetPostTags(context.Background(), 10, &authorID, true)
+		require.NoError(t, err)
+		postCache.AssertNotCalled(t, "GetPost", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPostServiceCacheDecorator_GetPostsByIDs(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	author := &model.User{ID: 1}
+	published := func(id int64) *model.PostDetailed {
+		return &model.PostDetailed{Post: &model.Post{ID: id, AuthorID: 1, Status: model.PostStatusPublished}, Author: author}
+	}
+	draft := &model.PostDetailed{Post: &model.Post{ID: 3, AuthorID: 1, Status: model.PostStatusDraft}}
+
+	t.Run("fetches only cache misses and backfills them", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		batcher := new(cache_mock.CacheBatcher)
+		batch := new(cache_mock.CacheBatch)
+
+		// 1 is cached, 3 is a cached draft and is asked for again, 2 and 4 are not cached.
+		postCache.On("GetPosts", mock.Anything, []int64{4, 1, 3, 2}).
+			Return(map[int64]*model.PostDetailed{1: published(1), 3: draft}, nil).Once()
+		fetched := []*model.PostDetailed{published(4), published(2)}
+		service.On("GetPostsByIDs", mock.Anything, []int64{4, 3, 2}).Return(fetched, nil).Once()
+		batcher.On("NewBatch").Return(batch)
+		batch.On("SetPost", fetched[0]).Once()
+		batch.On("SetPost", fetched[1]).Once()
+		batch.On("SetUser", author).Once()
+		batch.On("Len").Return(3)
+		batch.On("Exec", mock.Anything).Return(nil).Once()
+
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, batcher, log, metrics)
+
+		got, err := d.GetPostsByIDs(context.Background(), []int64{4, 1, 3, 2, 1})
+		require.NoError(t, err)
+		ids := make([]int64, len(got))
+		for i, post := range got {
+			ids[i] = post.Post.ID
+		}
+		assert.Equal(t, []int64{4, 1, 2}, ids)
+		service.AssertExpectations(t)
+		batch.AssertExpectations(t)
+	})
+
+	t.Run("all cached skips the service", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		postCache.On("GetPosts", mock.Anything, []int64{1, 2}).
+			Return(map[int64]*model.PostDetailed{1: published(1), 2: published(2)}, nil)
+
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher), log, metrics)
+
+		got, err := d.GetPostsByIDs(context.Background(), []int64{1, 2})
+		require.NoError(t, err)
+		assert.Len(t, got, 2)
+		service.AssertNotCalled(t, "GetPostsByIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("cache failure falls back to the service", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		batcher := new(cache_mock.CacheBatcher)
+		batch := new(cache_mock.CacheBatch)
+
+		postCache.On("GetPosts", mock.Anything, []int64{1}).Return(nil, errors.New("redis: connection refused"))
+		service.On("GetPostsByIDs", mock.Anything, []int64{1}).Return([]*model.PostDetailed{published(1)}, nil)
+		batcher.On("NewBatch").Return(batch)
+		batch.On("SetPost", mock.Anything)
+		batch.On("SetUser", mock.Anything)
+		batch.On("Len").Return(2)
+		batch.On("Exec", mock.Anything).Return(nil)
+
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, batcher, log, metrics)
+
+		got, err := d.GetPostsByIDs(context.Background(), []int64{1})
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+	})
+
+	t.Run("service error is returned", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		postCache.On("GetPosts", mock.Anything, []int64{1}).Return(map[int64]*model.PostDetailed{}, nil)
+		service.On("GetPostsByIDs", mock.Anything, []int64{1}).Return(nil, custom_errors.ErrDatabaseQuery)
+
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher), log, metrics)
+
+		got, err := d.GetPostsByIDs(context.Background(), []int64{1})
+		assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,82 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_service "pinstack-post-service/internal/domain/ports/input/post"
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/domain/ports/output/cache"
+)
+
+// CacheWarmer preloads the most recent published posts into the post cache after a deploy.
+type CacheWarmer struct {
+	service post_service.Service
+	batcher cache.CacheBatcher
+	limit   int
+	timeout time.Duration
+	log     output.Logger
+	metrics output.MetricsProvider
+}
+
+func NewCacheWarmer(
+	service post_service.Service,
+	batcher cache.CacheBatcher,
+	limit int,
+	timeout time.Duration,
+	log output.Logger,
+	metrics output.MetricsProvider,
+) *CacheWarmer {
+	return &CacheWarmer{
+		service: service,
+		batcher: batcher,
+		limit:   limit,
+		timeout: timeout,
+		log:     log,
+		metrics: metrics,
+	}
+}
+
+// Warm returns the number of cached posts. It gives up once the timeout elapses; a failed
+// warm-up only leaves the cache cold, so callers should log the error and keep serving.
+func (w *CacheWarmer) Warm(ctx context.Context) (int, error) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	limit := w.limit
+	posts, _, err := w.service.ListPosts(ctx, &model.PostFilters{Limit: &limit})
+	if err != nil {
+		w.metrics.RecordCacheOperationDuration("warmup", time.Since(start))
+		return 0, fmt.Errorf("failed to list posts for cache warm-up: %w", err)
+	}
+
+	batch := w.batcher.NewBatch()
+	authors := make(map[int64]bool)
+	for _, post := range posts {
+		if post.Post == nil {
+			continue
+		}
+		batch.SetPost(post)
+		if post.Author != nil && !authors[post.Author.ID] {
+			authors[post.Author.ID] = true
+			batch.SetUser(post.Author)
+		}
+	}
+
+	if err := batch.Exec(ctx); err != nil {
+		w.metrics.RecordCacheOperationDuration("warmup", time.Since(start))
+		return 0, fmt.Errorf("failed to write warm-up cache batch: %w", err)
+	}
+
+	w.metrics.RecordCacheOperationDuration("warmup", time.Since(start))
+	w.metrics.SetCacheWarmedEntries(len(posts))
+	w.log.Info("Post cache warmed",
+		slog.Int("posts", len(posts)),
+		slog.Int("authors", len(authors)),
+		slog.Duration("duration", time.Since(start)))
+	return len(posts), nil
+}
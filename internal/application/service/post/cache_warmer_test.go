@@ -0,0 +1,109 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_service_mock "pinstack-post-service/mocks/post"
+)
+
+func TestCacheWarmer_Warm(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	author := &model.User{ID: 1}
+	posts := []*model.PostDetailed{
+		{Post: &model.Post{ID: 2, AuthorID: 1}, Author: author},
+		{Post: &model.Post{ID: 1, AuthorID: 1}, Author: author},
+	}
+	limitIs := func(n int) interface{} {
+		return mock.MatchedBy(func(f *model.PostFilters) bool { return f.Limit != nil && *f.Limit == n })
+	}
+
+	tests := []struct {
+		name      string
+		mocks     func(service *post_service_mock.Service, batcher *cache_mock.CacheBatcher, batch *cache_mock.CacheBatch)
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name: "Success",
+			mocks: func(service *post_service_mock.Service, batcher *cache_mock.CacheBatcher, batch *cache_mock.CacheBatch) {
+				service.On("ListPosts", mock.Anything, limitIs(50)).Return(posts, 2, nil)
+				batcher.On("NewBatch").Return(batch)
+				batch.On("SetPost", posts[0]).Once()
+				batch.On("SetPost", posts[1]).Once()
+				batch.On("SetUser", author).Once()
+				batch.On("Exec", mock.Anything).Return(nil)
+			},
+			wantCount: 2,
+		},
+		{
+			name: "Error listing posts",
+			mocks: func(service *post_service_mock.Service, batcher *cache_mock.CacheBatcher, batch *cache_mock.CacheBatch) {
+				service.On("ListPosts", mock.Anything, limitIs(50)).Return(nil, 0, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error writing cache",
+			mocks: func(service *post_service_mock.Service, batcher *cache_mock.CacheBatcher, batch *cache_mock.CacheBatch) {
+				service.On("ListPosts", mock.Anything, limitIs(50)).Return(posts, 2, nil)
+				batcher.On("NewBatch").Return(batch)
+				batch.On("SetPost", mock.Anything)
+				batch.On("SetUser", mock.Anything)
+				batch.On("Exec", mock.Anything).Return(errors.New("redis: connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_service_mock.Service)
+			batcher := new(cache_mock.CacheBatcher)
+			batch := new(cache_mock.CacheBatch)
+			tt.mocks(service, batcher, batch)
+
+			warmer := NewCacheWarmer(service, batcher, 50, time.Second, log, metrics)
+			count, err := warmer.Warm(context.Background())
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCount, count)
+			service.AssertExpectations(t)
+			batch.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCacheWarmer_Warm_Timeout(t *testing.T) {
+	service := new(post_service_mock.Service)
+	batcher := new(cache_mock.CacheBatcher)
+
+	service.On("ListPosts", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, _ *model.PostFilters) ([]*model.PostDetailed, int, error) {
+			<-ctx.Done()
+			return nil, 0, ctx.Err()
+		})
+
+	warmer := NewCacheWarmer(service, batcher, 10, 20*time.Millisecond, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	start := time.Now()
+	_, err := warmer.Warm(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,158 @@
+package post_service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/testsupport"
+)
+
+func tagNames(tags []*model.Tag) []string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+func TestPostService_UpdatePost_ChangeSummary(t *testing.T) {
+	content := "Body"
+	media := func(urls ...string) []*model.PostMediaInput {
+		items := make([]*model.PostMediaInput, len(urls))
+		for i, url := range urls {
+			items[i] = &model.PostMediaInput{URL: url, Type: model.MediaTypeImage, Position: int32(i + 1)}
+		}
+		return items
+	}
+	private := model.PostVisibilityPrivate
+	public := model.PostVisibilityPublic
+
+	tests := []struct {
+		name        string
+		create      *model.CreatePostDTO
+		update      *model.UpdatePostDTO
+		wantFields  []string
+		wantAdded   []string
+		wantCreated []string
+		wantRemoved []string
+		// wantDetached counts the media rows replaced; wantAttached the new ones.
+		wantDetached int
+		wantAttached int
+	}{
+		{
+			name:       "title",
+			create:     &model.CreatePostDTO{Title: "Post"},
+			update:     &model.UpdatePostDTO{Title: testsupport.Ptr("Renamed")},
+			wantFields: []string{"title"},
+		},
+		{
+			name:       "content and visibility",
+			create:     &model.CreatePostDTO{Title: "Post"},
+			update:     &model.UpdatePostDTO{Content: &content, Visibility: &private},
+			wantFields: []string{"content", "visibility"},
+		},
+		{
+			name:       "language set",
+			create:     &model.CreatePostDTO{Title: "Post"},
+			update:     &model.UpdatePostDTO{Language: testsupport.Ptr("ru")},
+			wantFields: []string{"language"},
+		},
+		{
+			name:   "same language in another case",
+			create: &model.CreatePostDTO{Title: "Post", Language: "en"},
+			update: &model.UpdatePostDTO{Language: testsupport.Ptr("EN")},
+		},
+		{
+			name:   "same values",
+			create: &model.CreatePostDTO{Title: "Post", Content: &content},
+			update: &model.UpdatePostDTO{Title: testsupport.Ptr("Post"), Content: &content, Visibility: &public},
+		},
+		{
+			name:        "tags created, reused and removed",
+			create:      &model.CreatePostDTO{Title: "Post", Tags: []string{"go", "old"}},
+			update:      &model.UpdatePostDTO{Tags: []string{"go", "old-news", "new"}},
+			wantAdded:   []string{"old-news", "new"},
+			wantCreated: []string{"old-news", "new"},
+			wantRemoved: []string{"old"},
+		},
+		{
+			name:      "existing tag added",
+			create:    &model.CreatePostDTO{Title: "Post"},
+			update:    &model.UpdatePostDTO{Tags: []string{"seed"}},
+			wantAdded: []string{"seed"},
+		},
+		{
+			name:         "media replaced",
+			create:       &model.CreatePostDTO{Title: "Post", MediaItems: media("https://example.com/1.jpg", "https://example.com/2.jpg")},
+			update:       &model.UpdatePostDTO{MediaItems: media("https://example.com/2.jpg")},
+			wantDetached: 2,
+			wantAttached: 1,
+		},
+		{
+			name:         "media on a post without media",
+			create:       &model.CreatePostDTO{Title: "Post"},
+			update:       &model.UpdatePostDTO{MediaItems: media("https://example.com/1.jpg")},
+			wantAttached: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newScheduleService(t)
+			ctx := context.Background()
+			// "seed" exists before the update through another post.
+			_, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 2, Title: "Seed", Tags: []string{"seed"}})
+			require.NoError(t, err)
+			tt.create.AuthorID = 1
+			created, err := s.CreatePost(ctx, tt.create)
+			require.NoError(t, err)
+			tt.update.UserID = 1
+
+			updated, err := s.UpdatePost(ctx, 1, created.Post.ID, tt.update)
+
+			require.NoError(t, err)
+			changes := updated.Changes
+			require.NotNil(t, changes)
+			assert.Equal(t, tt.wantFields, changes.FieldsChanged)
+			assert.ElementsMatch(t, tt.wantAdded, tagNames(changes.TagsAdded))
+			assert.ElementsMatch(t, tt.wantCreated, tagNames(changes.TagsCreated))
+			assert.ElementsMatch(t, tt.wantRemoved, tagNames(changes.TagsRemoved))
+			for _, tag := range append(changes.TagsAdded, changes.TagsRemoved...) {
+				assert.NotZero(t, tag.ID)
+			}
+
+			require.Len(t, changes.MediaDetached, tt.wantDetached)
+			for i, m := range created.Media {
+				assert.Equal(t, m.ID, changes.MediaDetached[i])
+			}
+			require.Len(t, changes.MediaAttached, tt.wantAttached)
+			if tt.wantAttached > 0 {
+				assert.Equal(t, updated.Media, changes.MediaAttached, "the attached media are the post's media, with their new ids")
+			}
+			for _, m := range changes.MediaAttached {
+				assert.NotContains(t, changes.MediaDetached, m.ID)
+			}
+
+			isEmpty := len(tt.wantFields)+len(tt.wantAdded)+len(tt.wantRemoved)+tt.wantDetached+tt.wantAttached == 0
+			assert.Equal(t, isEmpty, changes.IsEmpty())
+		})
+	}
+}
+
+func TestPostService_UpdatePost_ChangeSummaryIsNotCached(t *testing.T) {
+	s, _ := newScheduleService(t)
+	ctx := context.Background()
+	created, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+	require.NoError(t, err)
+	title := "Renamed"
+	_, err = s.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{UserID: 1, Title: &title})
+	require.NoError(t, err)
+
+	got, err := s.GetPostByID(ctx, created.Post.ID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, got.Changes, "a read describes no update")
+}
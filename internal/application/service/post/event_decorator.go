@@ -0,0 +1,193 @@
+package post_service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_service "pinstack-post-service/internal/domain/ports/input/post"
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/domain/ports/output/events"
+)
+
+// PostServiceEventDecorator publishes a post event after each create, update and delete the
+// wrapped service has committed. A failed call publishes nothing. Publishing is best effort:
+// a failure is logged and counted but does not fail the call.
+type PostServiceEventDecorator struct {
+	service   post_service.Service
+	publisher events.Publisher
+	log       output.Logger
+	metrics   output.MetricsProvider
+	now       func() time.Time
+}
+
+func NewPostServiceEventDecorator(
+	service post_service.Service,
+	publisher events.Publisher,
+	log output.Logger,
+	metrics output.MetricsProvider,
+) post_service.Service {
+	return &PostServiceEventDecorator{
+		service:   service,
+		publisher: publisher,
+		log:       log,
+		metrics:   metrics,
+		now:       time.Now,
+	}
+}
+
+func (d *PostServiceEventDecorator) CreatePost(ctx context.Context, post *model.CreatePostDTO) (*model.PostDetailed, error) {
+	created, err := d.service.CreatePost(ctx, post)
+	if err == nil {
+		d.publish(ctx, model.PostEventCreated, created.Post.ID, created.Post.AuthorID)
+	}
+	return created, err
+}
+
+func (d *PostServiceEventDecorator) BulkCreatePosts(ctx context.Context, actorID int64, posts []*model.CreatePostDTO) (*model.BulkCreateResult, error) {
+	result, err := d.service.BulkCreatePosts(ctx, actorID, posts)
+	if err == nil {
+		for _, item := range result.Items {
+			if item.Post != nil {
+				d.publish(ctx, model.PostEventCreated, item.Post.ID, item.Post.AuthorID)
+			}
+		}
+	}
+	return result, err
+}
+
+func (d *PostServiceEventDecorator) GetPostByID(ctx context.Context, id int64, requesterID *int64) (*model.PostDetailed, error) {
+	return d.service.GetPostByID(ctx, id, requesterID)
+}
+
+func (d *PostServiceEventDecorator) ListPosts(ctx context.Context, filters *model.PostFilters) ([]*model.PostDetailed, int, error) {
+	return d.service.ListPosts(ctx, filters)
+}
+
+func (d *PostServiceEventDecorator) GetPostsByIDs(ctx context.Context, ids []int64) ([]*model.PostDetailed, error) {
+	return d.service.GetPostsByIDs(ctx, ids)
+}
+
+func (d *PostServiceEventDecorator) UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) (*model.PostDetailed, error) {
+	updated, err := d.service.UpdatePost(ctx, userID, id, post)
+	if err == nil {
+		d.publish(ctx, model.PostEventUpdated, id, updated.Post.AuthorID)
+	}
+	return updated, err
+}
+
+// DeletePost publishes userID as the author: only the author may delete a post this way.
+func (d *PostServiceEventDecorator) DeletePost(ctx context.Context, userID int64, id int64) error {
+	err := d.service.DeletePost(ctx, userID, id)
+	if err == nil {
+		d.publish(ctx, model.PostEventDeleted, id, userID)
+	}
+	return err
+}
+
+func (d *PostServiceEventDecorator) ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (*model.ModerationAction, error) {
+	action, err := d.service.ForceDeletePost(ctx, actorID, postID, reason)
+	if err == nil {
+		d.publish(ctx, model.PostEventDeleted, postID, action.AuthorID)
+	}
+	return action, err
+}
+
+func (d *PostServiceEventDecorator) SetModerationStatus(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason) (*model.Post, error) {
+	post, err := d.service.SetModerationStatus(ctx, actorID, postID, status, reason)
+	if err == nil {
+		d.publish(ctx, model.PostEventUpdated, postID, post.AuthorID)
+	}
+	return post, err
+}
+
+func (d *PostServiceEventDecorator) PublishPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	published, err := d.service.PublishPost(ctx, userID, id)
+	if err == nil {
+		d.publish(ctx, model.PostEventUpdated, id, published.Post.AuthorID)
+	}
+	return published, err
+}
+
+func (d *PostServiceEventDecorator) CancelScheduledPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	canceled, err := d.service.CancelScheduledPost(ctx, userID, id)
+	if err == nil {
+		d.publish(ctx, model.PostEventUpdated, id, canceled.Post.AuthorID)
+	}
+	return canceled, err
+}
+
+// PinPost and UnpinPost announce every post whose pinned state changed; pinning a post
+// also unpins the author's previous one.
+func (d *PostServiceEventDecorator) PinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	change, err := d.service.PinPost(ctx, userID, id)
+	if err == nil {
+		d.publishPinChange(ctx, change)
+	}
+	return change, err
+}
+
+func (d *PostServiceEventDecorator) UnpinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	change, err := d.service.UnpinPost(ctx, userID, id)
+	if err == nil {
+		d.publishPinChange(ctx, change)
+	}
+	return change, err
+}
+
+func (d *PostServiceEventDecorator) publishPinChange(ctx context.Context, change *model.PinChange) {
+	for _, postID := range change.AffectedPostIDs {
+		d.publish(ctx, model.PostEventUpdated, postID, change.Post.Post.AuthorID)
+	}
+}
+
+func (d *PostServiceEventDecorator) GetPostRevisions(ctx context.Context, userID int64, postID int64, limit, offset int) ([]*model.PostRevision, int, error) {
+	return d.service.GetPostRevisions(ctx, userID, postID, limit, offset)
+}
+
+func (d *PostServiceEventDecorator) GetPostTags(ctx context.Context, postID int64, requesterID *int64, includeCounts bool) ([]*model.Tag, error) {
+	return d.service.GetPostTags(ctx, postID, requesterID, includeCounts)
+}
+
+func (d *PostServiceEventDecorator) GetPostMedia(ctx context.Context, postID int64, requesterID *int64, limit, offset int) ([]*model.PostMedia, int, error) {
+	return d.service.GetPostMedia(ctx, postID, requesterID, limit, offset)
+}
+
+func (d *PostServiceEventDecorator) RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error) {
+	return d.service.RenameTag(ctx, tagID, name)
+}
+
+func (d *PostServiceEventDecorator) MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error) {
+	return d.service.MergeTags(ctx, sourceIDs, destID)
+}
+
+func (d *PostServiceEventDecorator) SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error) {
+	return d.service.SuggestTags(ctx, prefix, limit, authorID)
+}
+
+func (d *PostServiceEventDecorator) ExportAuthorPosts(ctx context.Context, authorID int64, send func(*model.PostDetailed) error) error {
+	return d.service.ExportAuthorPosts(ctx, authorID, send)
+}
+
+func (d *PostServiceEventDecorator) GetAuthorPostCount(ctx context.Context, authorID int64) (*model.AuthorPostCount, error) {
+	return d.service.GetAuthorPostCount(ctx, authorID)
+}
+
+func (d *PostServiceEventDecorator) HasPostsSince(ctx context.Context, tagNames []string, since time.Time) (*model.TagActivity, error) {
+	return d.service.HasPostsSince(ctx, tagNames, since)
+}
+
+// publish runs once the change is committed, so it does not stop when the caller hangs up.
+func (d *PostServiceEventDecorator) publish(ctx context.Context, eventType model.PostEventType, postID, authorID int64) {
+	event := model.PostEvent{Type: eventType, PostID: postID, AuthorID: authorID, OccurredAt: d.now().UTC()}
+	if err := d.publisher.Publish(context.WithoutCancel(ctx), event); err != nil {
+		d.metrics.IncrementEventPublishes(string(eventType), false)
+		d.log.Warn("Failed to publish post event",
+			slog.String("event", string(eventType)),
+			slog.Int64("post_id", postID),
+			slog.String("error", err.Error()))
+		return
+	}
+	d.metrics.IncrementEventPublishes(string(eventType), true)
+}
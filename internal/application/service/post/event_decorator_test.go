@@ -0,0 +1,152 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+)
+
+type recordingPublisher struct {
+	events []model.PostEvent
+	err    error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event model.PostEvent) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	p.events = append(p.events, event)
+	return p.err
+}
+
+type eventMetrics struct {
+	output.MetricsProvider
+	publishes map[string][]bool
+}
+
+func (m *eventMetrics) IncrementEventPublishes(event string, success bool) {
+	m.publishes[event] = append(m.publishes[event], success)
+}
+
+func newEventService(t *testing.T, publisher *recordingPublisher) (*PostServiceEventDecorator, *PostService, *eventMetrics) {
+	t.Helper()
+	s, _ := newScheduleService(t)
+	metrics := &eventMetrics{MetricsProvider: prometheus.NewPrometheusMetricsProvider(), publishes: map[string][]bool{}}
+	d := NewPostServiceEventDecorator(s, publisher, logger.New("test"), metrics).(*PostServiceEventDecorator)
+	d.now = func() time.Time { return scheduleNow }
+	return d, s, metrics
+}
+
+func TestPostServiceEventDecorator_PublishesCommittedChanges(t *testing.T) {
+	publisher := &recordingPublisher{}
+	d, _, metrics := newEventService(t, publisher)
+	ctx := context.Background()
+	title := "Renamed"
+	later := scheduleNow.Add(time.Hour)
+
+	created, err := d.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+	require.NoError(t, err)
+	_, err = d.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{UserID: 1, Title: &title})
+	require.NoError(t, err)
+	draft, err := d.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft})
+	require.NoError(t, err)
+	_, err = d.PublishPost(ctx, 1, draft.Post.ID)
+	require.NoError(t, err)
+	scheduled, err := d.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 2, Title: "Scheduled", ScheduledAt: &later})
+	require.NoError(t, err)
+	_, err = d.CancelScheduledPost(ctx, 2, scheduled.Post.ID)
+	require.NoError(t, err)
+	require.NoError(t, d.DeletePost(ctx, 1, created.Post.ID))
+	_, err = d.ForceDeletePost(ctx, 99, scheduled.Post.ID, "spam links")
+	require.NoError(t, err)
+
+	event := func(eventType model.PostEventType, post *model.PostDetailed) model.PostEvent {
+		return model.PostEvent{Type: eventType, PostID: post.Post.ID, AuthorID: post.Post.AuthorID, OccurredAt: scheduleNow.UTC()}
+	}
+	assert.Equal(t, []model.PostEvent{
+		event(model.PostEventCreated, created),
+		event(model.PostEventUpdated, created),
+		event(model.PostEventCreated, draft),
+		event(model.PostEventUpdated, draft),
+		event(model.PostEventCreated, scheduled),
+		event(model.PostEventUpdated, scheduled),
+		event(model.PostEventDeleted, created),
+		event(model.PostEventDeleted, scheduled),
+	}, publisher.events, "one event per change, in order")
+	assert.Equal(t, map[string][]bool{
+		"post_created": {true, true, true},
+		"post_updated": {true, true, true},
+		"post_deleted": {true, true},
+	}, metrics.publishes)
+}
+
+func TestPostServiceEventDecorator_FailedChangesPublishNothing(t *testing.T) {
+	publisher := &recordingPublisher{}
+	d, s, _ := newEventService(t, publisher)
+	ctx := context.Background()
+	created, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+	require.NoError(t, err)
+	title := "Renamed"
+	stale := created.Post.Version + 1
+
+	_, err = d.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "No"})
+	assert.ErrorIs(t, err, custom_errors.ErrPostValidation)
+	_, err = d.UpdatePost(ctx, 2, created.Post.ID, &model.UpdatePostDTO{UserID: 2, Title: &title})
+	assert.ErrorIs(t, err, custom_errors.ErrForbidden)
+	// The version is checked inside the transaction, which is rolled back.
+	_, err = d.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{UserID: 1, Title: &title, ExpectedVersion: &stale})
+	assert.ErrorIs(t, err, model.ErrVersionConflict)
+	assert.ErrorIs(t, d.DeletePost(ctx, 1, created.Post.ID+100), custom_errors.ErrPostNotFound)
+	_, err = d.CancelScheduledPost(ctx, 1, created.Post.ID)
+	assert.Error(t, err, "the post is not scheduled")
+
+	assert.Empty(t, publisher.events)
+}
+
+func TestPostServiceEventDecorator_PublishFailureIsNotFatal(t *testing.T) {
+	publisher := &recordingPublisher{err: errors.New("redis down")}
+	d, s, metrics := newEventService(t, publisher)
+
+	created, err := d.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+
+	require.NoError(t, err)
+	got, err := s.GetPostByID(context.Background(), created.Post.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Post", got.Post.Title)
+	assert.Equal(t, map[string][]bool{"post_created": {false}}, metrics.publishes)
+}
+
+func TestPostServiceEventDecorator_PublishesAfterTheCallerHangsUp(t *testing.T) {
+	publisher := &recordingPublisher{}
+	d, s, _ := newEventService(t, publisher)
+	created, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	d.service = &cancelingService{PostService: s, cancel: cancel}
+
+	require.NoError(t, d.DeletePost(ctx, 1, created.Post.ID))
+
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, model.PostEventDeleted, publisher.events[0].Type)
+}
+
+// cancelingService cancels the caller's context once the wrapped call has returned.
+type cancelingService struct {
+	*PostService
+	cancel context.CancelFunc
+}
+
+func (c *cancelingService) DeletePost(ctx context.Context, userID int64, id int64) error {
+	defer c.cancel()
+	return c.PostService.DeletePost(ctx, userID, id)
+}
@@ -0,0 +1,94 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// exportBatchSize is how many posts an export reads and hydrates at a time.
+const exportBatchSize = 200
+
+// ExportAuthorPosts passes every post of authorID, drafts included, to send in ascending id
+// order. Posts are read a batch at a time by keyset pagination, so memory use does not grow
+// with the number of posts, and media and tags are loaded with one query per batch.
+//
+// The context is checked before every batch: a cancelled or expired export stops with the
+// context's error. An error from send stops the export and is returned unchanged.
+func (s *PostService) ExportAuthorPosts(ctx context.Context, authorID int64, send func(*model.PostDetailed) error) (err error) {
+	defer observePostOperation(s.metrics, "export", time.Now(), &err)
+
+	author, err := s.userClient.GetUser(ctx, authorID)
+	if err != nil {
+		if !errors.Is(err, custom_errors.ErrUserNotFound) {
+			s.log.Error("Failed to get author for export", slog.Int64("authorID", authorID), slog.String("error", err.Error()))
+			return custom_errors.ErrExternalServiceError
+		}
+		s.log.Debug("Author not found, exporting posts without author", slog.Int64("authorID", authorID))
+		author = nil
+	}
+
+	var afterID int64
+	exported := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			s.log.Debug("Export stopped", slog.Int64("authorID", authorID), slog.Int("exported", exported), slog.String("reason", err.Error()))
+			return err
+		}
+
+		posts, err := s.postRepo.GetByAuthorAfter(ctx, authorID, afterID, exportBatchSize)
+		if err != nil {
+			s.log.Error("Failed to get posts for export", slog.Int64("authorID", authorID), slog.Int64("afterID", afterID), slog.String("error", err.Error()))
+			return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		if len(posts) == 0 {
+			break
+		}
+
+		ids := make([]int64, len(posts))
+		for i, post := range posts {
+			ids[i] = post.ID
+		}
+		media, err := s.mediaRepo.GetByPosts(ctx, ids)
+		if err != nil {
+			s.log.Error("Failed to get media for export", slog.Int64("authorID", authorID), slog.String("error", err.Error()))
+			return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		tags, err := s.tagRepo.FindByPosts(ctx, ids)
+		if err != nil {
+			s.log.Error("Failed to get tags for export", slog.Int64("authorID", authorID), slog.String("error", err.Error()))
+			return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+
+		sent := 0
+		for _, post := range posts {
+			err := send(&model.PostDetailed{
+				Post:   post,
+				Author: author,
+				Media:  media[post.ID],
+				Tags:   tags[post.ID],
+			})
+			if err != nil {
+				s.metrics.AddExportedPosts(sent)
+				return err
+			}
+			sent++
+		}
+		s.metrics.AddExportedPosts(sent)
+		exported += sent
+
+		if len(posts) < exportBatchSize {
+			break
+		}
+		afterID = posts[len(posts)-1].ID
+	}
+
+	s.log.Info("Exported author posts", slog.Int64("authorID", authorID), slog.Int("count", exported))
+	return nil
+}
@@ -0,0 +1,188 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	media_memory "pinstack-post-service/internal/infrastructure/outbound/repository/media/memory"
+	post_memory "pinstack-post-service/internal/infrastructure/outbound/repository/post/memory"
+	tag_memory "pinstack-post-service/internal/infrastructure/outbound/repository/tag/memory"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+// newExportService backs the service with the in-memory repositories and stores count posts
+// by author 1, interleaved with posts by author 2. Every third post of author 1 has an image
+// and every fifth is tagged.
+func newExportService(t *testing.T, count int) (*PostService, *user_client_mock.Client) {
+	t.Helper()
+	ctx := context.Background()
+	log := logger.New("test")
+	postRepo := post_memory.NewPostRepository(log)
+	mediaRepo := media_memory.NewMediaRepository(log)
+	tagRepo := tag_memory.NewTagRepository(log)
+	for _, name := range []string{"travel", "go"} {
+		_, err := tagRepo.Create(ctx, name)
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < count; i++ {
+		if i%4 == 0 {
+			_, err := postRepo.Create(ctx, &model.Post{AuthorID: 2, Title: "Other author"})
+			require.NoError(t, err)
+		}
+		post, err := postRepo.Create(ctx, &model.Post{AuthorID: 1, Title: fmt.Sprintf("Post %d", i)})
+		require.NoError(t, err)
+		mediaRepo.SimulatePostExists(post.ID, true)
+		tagRepo.SimulatePostExists(post.ID, true)
+		if i%3 == 0 {
+			require.NoError(t, mediaRepo.Attach(ctx, post.ID, []*model.PostMedia{
+				{URL: fmt.Sprintf("https://example.com/%d.jpg", post.ID), Type: model.MediaTypeImage, Position: 1},
+			}))
+		}
+		if i%5 == 0 {
+			require.NoError(t, tagRepo.TagPost(ctx, post.ID, []string{"travel", "go"}))
+		}
+	}
+
+	userClient := new(user_client_mock.Client)
+	s := NewPostService(postRepo, tagRepo, mediaRepo, new(postgres_mock.UnitOfWork), log, userClient,
+		prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+	return s, userClient
+}
+
+func TestPostService_ExportAuthorPosts(t *testing.T) {
+	const count = 450
+	s, userClient := newExportService(t, count)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "alice"}, nil).Once()
+
+	var exported []*model.PostDetailed
+	err := s.ExportAuthorPosts(context.Background(), 1, func(post *model.PostDetailed) error {
+		exported = append(exported, post)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, exported, count)
+	for i, post := range exported {
+		assert.Equal(t, int64(1), post.Post.AuthorID)
+		assert.Equal(t, fmt.Sprintf("Post %d", i), post.Post.Title)
+		assert.Equal(t, "alice", post.Author.Username)
+		if i > 0 {
+			assert.Greater(t, post.Post.ID, exported[i-1].Post.ID)
+		}
+		if i%3 == 0 {
+			require.Len(t, post.Media, 1)
+			assert.Equal(t, fmt.Sprintf("https://example.com/%d.jpg", post.Post.ID), post.Media[0].URL)
+		} else {
+			assert.Empty(t, post.Media)
+		}
+		if i%5 == 0 {
+			require.Len(t, post.Tags, 2)
+			assert.Equal(t, "go", post.Tags[0].Name)
+			assert.Equal(t, "travel", post.Tags[1].Name)
+		} else {
+			assert.Empty(t, post.Tags)
+		}
+	}
+	userClient.AssertExpectations(t)
+}
+
+func TestPostService_ExportAuthorPosts_ExactBatch(t *testing.T) {
+	s, userClient := newExportService(t, exportBatchSize)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+
+	exported := 0
+	err := s.ExportAuthorPosts(context.Background(), 1, func(*model.PostDetailed) error {
+		exported++
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, exportBatchSize, exported)
+}
+
+func TestPostService_ExportAuthorPosts_StopsBetweenBatchesWhenCancelled(t *testing.T) {
+	s, userClient := newExportService(t, 450)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	exported := 0
+	err := s.ExportAuthorPosts(ctx, 1, func(*model.PostDetailed) error {
+		exported++
+		cancel()
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, exportBatchSize, exported, "the batch in flight is finished, the next one is not read")
+}
+
+func TestPostService_ExportAuthorPosts_DeadlinePassed(t *testing.T) {
+	s, userClient := newExportService(t, 10)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	err := s.ExportAuthorPosts(ctx, 1, func(*model.PostDetailed) error {
+		t.Fatal("nothing may be sent after the deadline")
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPostService_ExportAuthorPosts_SendError(t *testing.T) {
+	s, userClient := newExportService(t, 10)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+	sendErr := errors.New("stream closed")
+
+	exported := 0
+	err := s.ExportAuthorPosts(context.Background(), 1, func(*model.PostDetailed) error {
+		exported++
+		if exported == 3 {
+			return sendErr
+		}
+		return nil
+	})
+
+	assert.Same(t, sendErr, err)
+	assert.Equal(t, 3, exported)
+}
+
+func TestPostService_ExportAuthorPosts_UserService(t *testing.T) {
+	t.Run("missing author still exports", func(t *testing.T) {
+		s, userClient := newExportService(t, 3)
+		userClient.On("GetUser", mock.Anything, int64(1)).Return(nil, custom_errors.ErrUserNotFound)
+
+		exported := 0
+		err := s.ExportAuthorPosts(context.Background(), 1, func(post *model.PostDetailed) error {
+			assert.Nil(t, post.Author)
+			exported++
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, exported)
+	})
+
+	t.Run("user service down", func(t *testing.T) {
+		s, userClient := newExportService(t, 3)
+		userClient.On("GetUser", mock.Anything, int64(1)).Return(nil, errors.New("connection refused"))
+
+		err := s.ExportAuthorPosts(context.Background(), 1, func(*model.PostDetailed) error { return nil })
+
+		assert.Equal(t, custom_errors.ErrExternalServiceError, err)
+	})
+}
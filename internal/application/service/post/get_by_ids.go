@@ -0,0 +1,110 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// GetPostsByIDs returns the published posts among ids in the order they were asked for.
+// Duplicated ids are answered once; ids of missing posts and drafts are left out and rejected
+// posts are redacted. Posts are
+// read with one query, and media, tags and each distinct author are loaded once per call.
+func (s *PostService) GetPostsByIDs(ctx context.Context, ids []int64) (result []*model.PostDetailed, err error) {
+	defer observePostOperation(s.metrics, "get_batch", time.Now(), &err)
+
+	if err := model.ValidatePostIDs(ids); err != nil {
+		s.log.Debug("Invalid post ids", slog.String("error", err.Error()))
+		return nil, err
+	}
+	ids = uniquePostIDs(ids)
+	if len(ids) == 0 {
+		return []*model.PostDetailed{}, nil
+	}
+
+	posts, err := s.postRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		s.log.Error("Failed to get posts by ids", slog.Int("count", len(ids)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	byID := make(map[int64]*model.Post, len(posts))
+	found := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		if post.CheckAccess(nil) != nil {
+			continue
+		}
+		byID[post.ID] = post
+		found = append(found, post.ID)
+	}
+	if len(found) == 0 {
+		return []*model.PostDetailed{}, nil
+	}
+
+	media, err := s.mediaRepo.GetByPosts(ctx, found)
+	if err != nil {
+		s.log.Error("Failed to get media by posts", slog.Int("count", len(found)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	tags, err := s.tagRepo.FindByPosts(ctx, found)
+	if err != nil {
+		s.log.Error("Failed to get tags by posts", slog.Int("count", len(found)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	authors := make(map[int64]*model.User)
+	result = make([]*model.PostDetailed, 0, len(found))
+	for _, id := range ids {
+		post, ok := byID[id]
+		if !ok {
+			continue
+		}
+
+		author, fetched := authors[post.AuthorID]
+		fromSnapshot := false
+		if snapshot := s.snapshotAuthor(post); snapshot != nil {
+			author, fromSnapshot = snapshot, true
+		} else if !fetched {
+			author, err = s.userClient.GetUser(ctx, post.AuthorID)
+			if err != nil {
+				if !errors.Is(err, custom_errors.ErrUserNotFound) {
+					s.log.Error("Failed to get author", slog.String("error", err.Error()), slog.Int64("authorID", post.AuthorID))
+					return nil, custom_errors.ErrExternalServiceError
+				}
+				s.log.Debug("Author not found, returning post without author", slog.Int64("authorID", post.AuthorID), slog.Int64("postID", post.ID))
+				author = nil
+			}
+			authors[post.AuthorID] = author
+		}
+
+		detailed := &model.PostDetailed{
+			Post:               post,
+			Author:             author,
+			Media:              media[post.ID],
+			Tags:               tags[post.ID],
+			AuthorFromSnapshot: fromSnapshot,
+		}
+		detailed.CapMedia(s.limits.MaxEmbeddedMedia)
+		result = append(result, detailed.RedactedFor(nil))
+	}
+	return result, nil
+}
+
+// uniquePostIDs drops repeated ids, keeping the first occurrence of each.
+func uniquePostIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,135 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	media_memory "pinstack-post-service/internal/infrastructure/outbound/repository/media/memory"
+	post_memory "pinstack-post-service/internal/infrastructure/outbound/repository/post/memory"
+	tag_memory "pinstack-post-service/internal/infrastructure/outbound/repository/tag/memory"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+// newBatchGetService stores posts 1-4: 1 and 3 by author 1, 2 by author 2, and 4 a draft
+// by author 1. Post 1 has an image and a tag.
+func newBatchGetService(t *testing.T) (*PostService, *user_client_mock.Client) {
+	t.Helper()
+	ctx := context.Background()
+	log := logger.New("test")
+	postRepo := post_memory.NewPostRepository(log)
+	mediaRepo := media_memory.NewMediaRepository(log)
+	tagRepo := tag_memory.NewTagRepository(log)
+
+	for _, p := range []*model.Post{
+		{AuthorID: 1, Title: "First"},
+		{AuthorID: 2, Title: "Second"},
+		{AuthorID: 1, Title: "Third"},
+		{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft},
+	} {
+		_, err := postRepo.Create(ctx, p)
+		require.NoError(t, err)
+	}
+	mediaRepo.SimulatePostExists(1, true)
+	tagRepo.SimulatePostExists(1, true)
+	require.NoError(t, mediaRepo.Attach(ctx, 1, []*model.PostMedia{{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1}}))
+	_, err := tagRepo.Create(ctx, "travel")
+	require.NoError(t, err)
+	require.NoError(t, tagRepo.TagPost(ctx, 1, []string{"travel"}))
+
+	userClient := new(user_client_mock.Client)
+	s := NewPostService(postRepo, tagRepo, mediaRepo, new(postgres_mock.UnitOfWork), log, userClient,
+		prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+	return s, userClient
+}
+
+func TestPostService_GetPostsByIDs(t *testing.T) {
+	s, userClient := newBatchGetService(t)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "one"}, nil).Once()
+	userClient.On("GetUser", mock.Anything, int64(2)).Return(nil, custom_errors.ErrUserNotFound).Once()
+
+	got, err := s.GetPostsByIDs(context.Background(), []int64{3, 99, 1, 4, 2, 3})
+
+	require.NoError(t, err)
+	ids := make([]int64, len(got))
+	for i, post := range got {
+		ids[i] = post.Post.ID
+	}
+	assert.Equal(t, []int64{3, 1, 2}, ids, "request order, duplicates once, missing posts and drafts left out")
+
+	assert.Equal(t, "one", got[0].Author.Username)
+	assert.Same(t, got[0].Author, got[1].Author, "each author is fetched once")
+	assert.Nil(t, got[2].Author)
+	require.Len(t, got[1].Media, 1)
+	assert.Equal(t, "https://example.com/1.jpg", got[1].Media[0].URL)
+	require.Len(t, got[1].Tags, 1)
+	assert.Equal(t, "travel", got[1].Tags[0].Name)
+	assert.Empty(t, got[0].Media)
+	userClient.AssertExpectations(t)
+}
+
+func TestPostService_GetPostsByIDs_Errors(t *testing.T) {
+	tooMany := make([]int64, model.MaxPostsByIDs+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+
+	tests := []struct {
+		name    string
+		ids     []int64
+		mocks   func(userClient *user_client_mock.Client)
+		wantErr error
+	}{
+		{
+			name:    "too many ids",
+			ids:     tooMany,
+			wantErr: custom_errors.ErrInvalidInput,
+		},
+		{
+			name:    "non-positive id",
+			ids:     []int64{1, 0},
+			wantErr: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "user service unavailable",
+			ids:  []int64{1},
+			mocks: func(userClient *user_client_mock.Client) {
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(nil, errors.New("connection refused"))
+			},
+			wantErr: custom_errors.ErrExternalServiceError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, userClient := newBatchGetService(t)
+			if tt.mocks != nil {
+				tt.mocks(userClient)
+			}
+
+			got, err := s.GetPostsByIDs(context.Background(), tt.ids)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestPostService_GetPostsByIDs_NothingFound(t *testing.T) {
+	s, userClient := newBatchGetService(t)
+
+	got, err := s.GetPostsByIDs(context.Background(), []int64{4, 50})
+
+	require.NoError(t, err)
+	assert.NotNil(t, got)
+	assert.Empty(t, got)
+	userClient.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,167 @@
+package post_service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/domain/ports/output/cache"
+)
+
+// invalidationBatchSize caps how many queued posts one DeletePosts call drops.
+const invalidationBatchSize = 500
+
+// InvalidationQueue drops cached posts in the background, for callers that change many posts
+// and should not wait on the cache. Posts queued together are coalesced into one DeletePosts
+// call, and a failed call is retried with a doubling pause before the posts are given up on.
+// A post that is dropped or given up on stays cached until its TTL.
+type InvalidationQueue struct {
+	postCache    cache.PostCache
+	ids          chan int64
+	retries      int
+	backoff      time.Duration
+	flushTimeout time.Duration
+	log          output.Logger
+	metrics      output.MetricsProvider
+}
+
+func NewInvalidationQueue(
+	postCache cache.PostCache,
+	size int,
+	retries int,
+	backoff time.Duration,
+	flushTimeout time.Duration,
+	log output.Logger,
+	metrics output.MetricsProvider,
+) *InvalidationQueue {
+	return &InvalidationQueue{
+		postCache:    postCache,
+		ids:          make(chan int64, size),
+		retries:      retries,
+		backoff:      backoff,
+		flushTimeout: flushTimeout,
+		log:          log,
+		metrics:      metrics,
+	}
+}
+
+// Enqueue hands postIDs to the worker without blocking and returns how many it queued. Once
+// the queue is full the remaining posts are dropped.
+func (q *InvalidationQueue) Enqueue(postIDs ...int64) int {
+	queued := 0
+enqueue:
+	for _, id := range postIDs {
+		select {
+		case q.ids <- id:
+			queued++
+		default:
+			break enqueue
+		}
+	}
+	q.metrics.AddCacheInvalidations("queued", queued)
+	if dropped := len(postIDs) - queued; dropped > 0 {
+		q.metrics.AddCacheInvalidations("dropped", dropped)
+		q.log.Warn("Cache invalidation queue is full, dropping invalidations",
+			slog.Int("queued", queued),
+			slog.Int("dropped", dropped))
+	}
+	return queued
+}
+
+// Run drops queued posts until ctx is done, then flushes whatever is still queued within the
+// flush timeout. It must stop before the cache client closes and after the last Enqueue, so
+// cmd/server runs it as a worker: workers stop after the gRPC drain and before Redis closes.
+func (q *InvalidationQueue) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			q.flush(nil)
+			return
+		case id := <-q.ids:
+			batch := q.collect(id)
+			if err := q.invalidate(ctx, batch); err != nil {
+				if ctx.Err() != nil {
+					// Shutdown interrupted the retries; the flush has another go.
+					q.flush(batch)
+					return
+				}
+				q.giveUp(batch, err)
+			}
+		}
+	}
+}
+
+// collect coalesces first with the posts queued behind it, up to a batch, without duplicates.
+func (q *InvalidationQueue) collect(first int64) []int64 {
+	batch := []int64{first}
+collect:
+	for len(batch) < invalidationBatchSize {
+		select {
+		case id := <-q.ids:
+			batch = append(batch, id)
+		default:
+			break collect
+		}
+	}
+	slices.Sort(batch)
+	return slices.Compact(batch)
+}
+
+// flush drops pending and everything still queued, in batches, within the flush timeout. It
+// does not use the worker context, which is already done.
+func (q *InvalidationQueue) flush(pending []int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), q.flushTimeout)
+	defer cancel()
+
+drain:
+	for {
+		select {
+		case id := <-q.ids:
+			pending = append(pending, id)
+		default:
+			break drain
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+	slices.Sort(pending)
+	pending = slices.Compact(pending)
+	q.log.Info("Flushing queued cache invalidations", slog.Int("posts", len(pending)))
+	for batch := range slices.Chunk(pending, invalidationBatchSize) {
+		if err := q.invalidate(ctx, batch); err != nil {
+			q.giveUp(batch, err)
+		}
+	}
+}
+
+// invalidate drops batch, retrying up to the configured number of times while ctx lasts, and
+// returns the error of the last attempt.
+func (q *InvalidationQueue) invalidate(ctx context.Context, batch []int64) error {
+	pause := q.backoff
+	for attempt := 0; ; attempt++ {
+		err := q.postCache.DeletePosts(ctx, batch)
+		if err == nil || attempt == q.retries || ctx.Err() != nil {
+			return err
+		}
+		q.log.Debug("Retrying cache invalidation",
+			slog.Int("posts", len(batch)),
+			slog.Int("attempt", attempt+1),
+			slog.String("error", err.Error()))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(pause):
+		}
+		pause *= 2
+	}
+}
+
+func (q *InvalidationQueue) giveUp(batch []int64, err error) {
+	q.metrics.AddCacheInvalidations("failed", len(batch))
+	q.log.Warn("Giving up on cache invalidation, posts stay cached until their TTL",
+		slog.Int("posts", len(batch)),
+		slog.String("error", err.Error()))
+}
@@ -0,0 +1,183 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/cache/noop"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+)
+
+// deletingCache records every DeletePosts call and fails the first failures of them.
+type deletingCache struct {
+	noop.PostCache
+	mu       sync.Mutex
+	calls    [][]int64
+	failures int
+	// deleted is signalled after every call.
+	deleted chan struct{}
+}
+
+func newDeletingCache(failures int) *deletingCache {
+	return &deletingCache{failures: failures, deleted: make(chan struct{}, 100)}
+}
+
+func (c *deletingCache) DeletePosts(ctx context.Context, postIDs []int64) error {
+	c.mu.Lock()
+	defer func() {
+		c.mu.Unlock()
+		c.deleted <- struct{}{}
+	}()
+	c.calls = append(c.calls, postIDs)
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+func (c *deletingCache) recorded() [][]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.calls)
+}
+
+// invalidationMetrics records AddCacheInvalidations and passes everything else on.
+type invalidationMetrics struct {
+	output.MetricsProvider
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newInvalidationMetrics() *invalidationMetrics {
+	return &invalidationMetrics{MetricsProvider: prometheus.NewPrometheusMetricsProvider(), counts: map[string]int{}}
+}
+
+func (m *invalidationMetrics) AddCacheInvalidations(outcome string, count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[outcome] += count
+}
+
+func (m *invalidationMetrics) recorded() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.counts)
+}
+
+func TestInvalidationQueue_CoalescesQueuedPosts(t *testing.T) {
+	postCache := newDeletingCache(0)
+	metrics := newInvalidationMetrics()
+	q := NewInvalidationQueue(postCache, 10, 0, time.Millisecond, time.Second, logger.New("test"), metrics)
+
+	assert.Equal(t, 4, q.Enqueue(3, 1, 2, 1))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.Run(ctx)
+		close(done)
+	}()
+	<-postCache.deleted
+	cancel()
+	<-done
+
+	assert.Equal(t, [][]int64{{1, 2, 3}}, postCache.recorded(), "one call per batch, without duplicates")
+	assert.Equal(t, map[string]int{"queued": 4}, metrics.recorded())
+}
+
+func TestInvalidationQueue_RetriesFailedDeletes(t *testing.T) {
+	postCache := newDeletingCache(2)
+	metrics := newInvalidationMetrics()
+	q := NewInvalidationQueue(postCache, 10, 2, time.Millisecond, time.Second, logger.New("test"), metrics)
+
+	q.Enqueue(7)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.Run(ctx)
+		close(done)
+	}()
+	for range 3 {
+		<-postCache.deleted
+	}
+	cancel()
+	<-done
+
+	assert.Equal(t, [][]int64{{7}, {7}, {7}}, postCache.recorded())
+	assert.Zero(t, metrics.recorded()["failed"])
+}
+
+func TestInvalidationQueue_GivesUpAfterRetries(t *testing.T) {
+	postCache := newDeletingCache(10)
+	metrics := newInvalidationMetrics()
+	q := NewInvalidationQueue(postCache, 10, 1, time.Millisecond, time.Second, logger.New("test"), metrics)
+
+	q.Enqueue(7, 8)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return metrics.recorded()["failed"] == 2 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Len(t, postCache.recorded(), 2, "one try and one retry")
+}
+
+func TestInvalidationQueue_DropsWhenFull(t *testing.T) {
+	metrics := newInvalidationMetrics()
+	q := NewInvalidationQueue(newDeletingCache(0), 2, 0, time.Millisecond, time.Second, logger.New("test"), metrics)
+
+	assert.Equal(t, 2, q.Enqueue(1, 2, 3))
+	assert.Equal(t, 0, q.Enqueue(4))
+	assert.Equal(t, map[string]int{"queued": 2, "dropped": 2}, metrics.recorded())
+}
+
+func TestInvalidationQueue_FlushesOnShutdown(t *testing.T) {
+	postCache := newDeletingCache(1)
+	metrics := newInvalidationMetrics()
+	q := NewInvalidationQueue(postCache, 1000, 1, time.Millisecond, time.Second, logger.New("test"), metrics)
+	ids := make([]int64, 600)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	q.Enqueue(ids...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	q.Run(ctx)
+
+	calls := postCache.recorded()
+	require.Len(t, calls, 3, "a failed first batch is retried, then the rest follows")
+	assert.Len(t, calls[0], invalidationBatchSize)
+	assert.Equal(t, calls[0], calls[1])
+	assert.Len(t, calls[2], 100)
+	assert.Zero(t, metrics.recorded()["failed"])
+}
+
+func TestInvalidationQueue_FlushIsBoundedByItsTimeout(t *testing.T) {
+	postCache := newDeletingCache(1000)
+	metrics := newInvalidationMetrics()
+	q := NewInvalidationQueue(postCache, 10, 100, time.Hour, 20*time.Millisecond, logger.New("test"), metrics)
+	q.Enqueue(1, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	q.Run(ctx)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 2, metrics.recorded()["failed"], "what the flush could not drop is counted as failed")
+}
@@ -0,0 +1,117 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+func langPtr(lang string) *string {
+	return &lang
+}
+
+func TestPostService_CreatePost_Language(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    string
+		want    *string
+		wantErr string
+	}{
+		{name: "unset"},
+		{name: "allowed", lang: "ru", want: langPtr("ru")},
+		{name: "canonicalized", lang: " EN ", want: langPtr("en")},
+		{name: "not allowed", lang: "de", wantErr: "must be one of en, ru, got de"},
+		{name: "region of an allowed language", lang: "en-us", wantErr: "must be one of en, ru, got en-US"},
+		{name: "malformed", lang: "english!", wantErr: "must be a BCP-47 language tag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newScheduleService(t)
+
+			created, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Post", Language: tt.lang})
+
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, custom_errors.ErrPostValidation)
+				var verr *model.ValidationError
+				require.True(t, errors.As(err, &verr))
+				require.Len(t, verr.Violations, 1)
+				assert.Equal(t, model.FieldViolation{Field: "language", Description: tt.wantErr}, verr.Violations[0])
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, created.Post.Language)
+		})
+	}
+}
+
+func TestPostService_UpdatePost_Language(t *testing.T) {
+	s, _ := newScheduleService(t)
+	created, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+	require.NoError(t, err)
+
+	_, err = s.UpdatePost(context.Background(), 1, created.Post.ID, &model.UpdatePostDTO{UserID: 1, Language: langPtr("xx")})
+	assert.ErrorIs(t, err, custom_errors.ErrPostValidation)
+
+	updated, err := s.UpdatePost(context.Background(), 1, created.Post.ID, &model.UpdatePostDTO{UserID: 1, Language: langPtr("RU")})
+	require.NoError(t, err)
+	assert.Equal(t, "ru", *updated.Post.Language)
+
+	// A title change leaves the language alone.
+	updated, err = s.UpdatePost(context.Background(), 1, created.Post.ID, &model.UpdatePostDTO{UserID: 1, Title: langPtr("Renamed")})
+	require.NoError(t, err)
+	assert.Equal(t, "ru", *updated.Post.Language)
+}
+
+func TestPostService_ListPosts_Language(t *testing.T) {
+	s, _ := newScheduleService(t)
+	for _, lang := range []string{"en", "ru", "", "en"} {
+		_, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Post", Language: lang})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name    string
+		lang    *string
+		want    []int64
+		wantErr error
+	}{
+		{name: "no filter lists posts without a language", want: []int64{4, 3, 2, 1}},
+		{name: "en", lang: langPtr("en"), want: []int64{4, 1}},
+		{name: "canonicalized", lang: langPtr("RU"), want: []int64{2}},
+		{name: "not allowed", lang: langPtr("de"), wantErr: custom_errors.ErrInvalidInput},
+		{name: "malformed", lang: langPtr("english!"), wantErr: custom_errors.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested string
+			if tt.lang != nil {
+				requested = *tt.lang
+			}
+			filters := &model.PostFilters{Language: tt.lang}
+			got, total, err := s.ListPosts(context.Background(), filters)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]int64, len(got))
+			for i, post := range got {
+				ids[i] = post.Post.ID
+			}
+			assert.Equal(t, tt.want, ids)
+			assert.Equal(t, len(tt.want), total)
+			if tt.lang != nil {
+				assert.Equal(t, requested, *filters.Language, "the caller's filters are not rewritten")
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	media_repository "pinstack-post-service/internal/domain/ports/output/media"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	media_memory "pinstack-post-service/internal/infrastructure/outbound/repository/media/memory"
+	post_memory "pinstack-post-service/internal/infrastructure/outbound/repository/post/memory"
+	tag_memory "pinstack-post-service/internal/infrastructure/outbound/repository/tag/memory"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+// countingUsers is a user client that answers after latency and records how many lookups
+// were made per user and how many were in flight at once.
+type countingUsers struct {
+	latency     time.Duration
+	mu          sync.Mutex
+	calls       map[int64]int
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (c *countingUsers) GetUser(ctx context.Context, id int64) (*model.User, error) {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+	for {
+		peak := c.maxInFlight.Load()
+		if n <= peak || c.maxInFlight.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	if c.calls == nil {
+		c.calls = map[int64]int{}
+	}
+	c.calls[id]++
+	c.mu.Unlock()
+
+	time.Sleep(c.latency)
+	if id%10 == 0 {
+		return nil, custom_errors.ErrUserNotFound
+	}
+	return &model.User{ID: id, Username: fmt.Sprintf("user%d", id)}, nil
+}
+
+func (c *countingUsers) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	return nil, custom_errors.ErrUserNotFound
+}
+
+func (c *countingUsers) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return nil, custom_errors.ErrUserNotFound
+}
+
+func (c *countingUsers) GetBlockedUsers(ctx context.Context, userID int64) ([]int64, error) {
+	return nil, nil
+}
+
+// slowMedia delays every GetByPost by latency, like a database round trip, and fails for failID.
+type slowMedia struct {
+	media_repository.Repository
+	latency time.Duration
+	failID  int64
+}
+
+func (m *slowMedia) GetByPost(ctx context.Context, postID int64) ([]*model.PostMedia, error) {
+	if postID == m.failID {
+		return nil, errors.New("connection reset")
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(m.latency):
+	}
+	return m.Repository.GetByPost(ctx, postID)
+}
+
+// newListService stores count posts spread over authors 1 to 15, each with one image and
+// every other one tagged, behind a media repository answering after latency.
+func newListService(tb testing.TB, count int, latency time.Duration) (*PostService, *countingUsers, *slowMedia) {
+	tb.Helper()
+	ctx := context.Background()
+	log := logger.New("test")
+	postRepo := post_memory.NewPostRepository(log)
+	mediaRepo := media_memory.NewMediaRepository(log)
+	tagRepo := tag_memory.NewTagRepository(log)
+	_, err := tagRepo.Create(ctx, "go")
+	require.NoError(tb, err)
+
+	for i := 0; i < count; i++ {
+		post, err := postRepo.Create(ctx, &model.Post{AuthorID: int64(i%15 + 1), Title: fmt.Sprintf("Post %d", i)})
+		require.NoError(tb, err)
+		mediaRepo.SimulatePostExists(post.ID, true)
+		tagRepo.SimulatePostExists(post.ID, true)
+		require.NoError(tb, mediaRepo.Attach(ctx, post.ID, []*model.PostMedia{
+			{URL: fmt.Sprintf("https://example.com/%d.jpg", post.ID), Type: model.MediaTypeImage, Position: 1},
+		}))
+		if i%2 == 0 {
+			require.NoError(tb, tagRepo.TagPost(ctx, post.ID, []string{"go"}))
+		}
+	}
+
+	users := &countingUsers{latency: latency}
+	media := &slowMedia{Repository: mediaRepo, latency: latency}
+	s := NewPostService(postRepo, tagRepo, media, new(postgres_mock.UnitOfWork), log, users,
+		prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+	return s, users, media
+}
+
+func TestPostService_ListPosts_HydratesConcurrently(t *testing.T) {
+	s, users, _ := newListService(t, 100, time.Millisecond)
+	limit := 100
+	want, total, err := s.postRepo.List(context.Background(), model.PostFilters{Limit: &limit})
+	require.NoError(t, err)
+
+	got, gotTotal, err := s.ListPosts(context.Background(), &model.PostFilters{Limit: &limit})
+
+	require.NoError(t, err)
+	assert.Equal(t, total, gotTotal)
+	require.Len(t, got, len(want))
+	for i, post := range got {
+		assert.Equal(t, want[i].ID, post.Post.ID, "page order must be kept")
+		require.Len(t, post.Media, 1)
+		assert.Equal(t, post.Post.ID, post.Media[0].PostID)
+		if post.Post.AuthorID%10 == 0 {
+			assert.Nil(t, post.Author)
+		} else {
+			require.NotNil(t, post.Author)
+			assert.Equal(t, post.Post.AuthorID, post.Author.ID)
+		}
+	}
+	for authorID, calls := range users.calls {
+		assert.Equal(t, 1, calls, "author %d fetched more than once", authorID)
+	}
+	assert.Len(t, users.calls, 15)
+	assert.LessOrEqual(t, int(users.maxInFlight.Load()), model.DefaultHydrationConcurrency)
+}
+
+func TestPostService_ListPosts_FirstHardErrorCancelsTheRest(t *testing.T) {
+	s, users, media := newListService(t, 100, time.Minute)
+	limit := 100
+	page, _, err := s.postRepo.List(context.Background(), model.PostFilters{Limit: &limit})
+	require.NoError(t, err)
+	media.failID = page[3].ID
+
+	start := time.Now()
+	got, _, err := s.ListPosts(context.Background(), &model.PostFilters{Limit: &limit})
+
+	require.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Nil(t, got)
+	assert.Less(t, time.Since(start), 5*time.Second, "remaining fetches must be cancelled")
+	assert.Empty(t, users.calls, "authors are not fetched after a failed page")
+}
+
+func TestPostService_ListPosts_FetchesEachAuthorOnce(t *testing.T) {
+	ctx := context.Background()
+	log := logger.New("test")
+	postRepo := post_memory.NewPostRepository(log)
+	// Author 1 wrote 30 posts of the page, author 2 fifteen and author 3, deleted, five.
+	for i := 0; i < 50; i++ {
+		authorID := int64(1)
+		switch {
+		case i%10 == 9:
+			authorID = 3
+		case i%10 >= 6:
+			authorID = 2
+		}
+		_, err := postRepo.Create(ctx, &model.Post{AuthorID: authorID, Title: fmt.Sprintf("Post %d", i)})
+		require.NoError(t, err)
+	}
+	userClient := new(user_client_mock.Client)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "prolific"}, nil)
+	userClient.On("GetUser", mock.Anything, int64(2)).Return(&model.User{ID: 2, Username: "regular"}, nil)
+	userClient.On("GetUser", mock.Anything, int64(3)).Return(nil, custom_errors.ErrUserNotFound)
+	s := NewPostService(postRepo, tag_memory.NewTagRepository(log), media_memory.NewMediaRepository(log), new(postgres_mock.UnitOfWork),
+		log, userClient, prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+	limit := 50
+
+	got, _, err := s.ListPosts(ctx, &model.PostFilters{Limit: &limit})
+
+	require.NoError(t, err)
+	require.Len(t, got, 50)
+	userClient.AssertNumberOfCalls(t, "GetUser", 3)
+	authors := map[int64]*model.User{}
+	for _, post := range got {
+		if post.Post.AuthorID == 3 {
+			assert.Nil(t, post.Author, "a deleted author leaves the others' posts hydrated")
+			continue
+		}
+		require.NotNil(t, post.Author)
+		assert.Equal(t, post.Post.AuthorID, post.Author.ID)
+		if first, ok := authors[post.Post.AuthorID]; ok {
+			assert.Same(t, first, post.Author, "posts by one author share their author")
+		}
+		authors[post.Post.AuthorID] = post.Author
+	}
+	assert.Len(t, authors, 2)
+}
+
+func BenchmarkPostService_ListPosts(b *testing.B) {
+	s, _, _ := newListService(b, 50, 3*time.Millisecond)
+	limit := 50
+	filters := &model.PostFilters{Limit: &limit}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := s.ListPosts(context.Background(), filters); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPostService_ListPosts_SummaryView(t *testing.T) {
+	s, _ := newScheduleService(t)
+	s.limits.ExcerptLength = 12
+	ctx := context.Background()
+
+	long := "Привет мир, это длинный пост"
+	short := "Коротко"
+	exact := "ровно десять"
+	longPost, err := s.CreatePost(ctx, &model.CreatePostDTO{
+		AuthorID: 1,
+		Title:    "Long",
+		Content:  &long,
+		MediaItems: []*model.PostMediaInput{
+			{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+			{URL: "https://example.com/2.jpg", Type: model.MediaTypeImage, Position: 2},
+		},
+	})
+	require.NoError(t, err)
+	for _, content := range []*string{&short, &exact} {
+		_, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Short", Content: content})
+		require.NoError(t, err)
+	}
+	_, err = s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "No content"})
+	require.NoError(t, err)
+
+	posts, total, err := s.ListPosts(ctx, &model.PostFilters{View: model.PostViewSummary, SortOrder: model.SortAsc})
+	require.NoError(t, err)
+	require.Equal(t, 4, total)
+
+	assert.Equal(t, "Привет мир,", *posts[0].Post.Content, "cut on the last space within 12 runes")
+	assert.True(t, posts[0].HasMoreContent)
+	require.Len(t, posts[0].Media, 1, "only the first media item is kept")
+	assert.Equal(t, "https://example.com/1.jpg", posts[0].Media[0].URL)
+
+	assert.Equal(t, short, *posts[1].Post.Content)
+	assert.False(t, posts[1].HasMoreContent, "a post shorter than the excerpt is whole")
+	assert.Equal(t, exact, *posts[2].Post.Content)
+	assert.False(t, posts[2].HasMoreContent, "a post of exactly the excerpt length is whole")
+	assert.Nil(t, posts[3].Post.Content)
+	assert.False(t, posts[3].HasMoreContent)
+
+	full, err := s.GetPostByID(ctx, longPost.Post.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, long, *full.Post.Content, "summaries do not change the stored post")
+	assert.Len(t, full.Media, 2)
+
+	posts, _, err = s.ListPosts(ctx, &model.PostFilters{View: model.PostViewFull, SortOrder: model.SortAsc})
+	require.NoError(t, err)
+	assert.Equal(t, long, *posts[0].Post.Content)
+	assert.False(t, posts[0].HasMoreContent)
+
+	_, _, err = s.ListPosts(ctx, &model.PostFilters{View: "compact"})
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		maxRunes int
+		want     string
+		wantCut  bool
+	}{
+		{name: "shorter than the limit", content: "short", maxRunes: 10, want: "short"},
+		{name: "exactly the limit", content: "0123456789", maxRunes: 10, want: "0123456789"},
+		{name: "cut on a word boundary", content: "hello brave new world", maxRunes: 13, want: "hello brave", wantCut: true},
+		{name: "limit ends at a space", content: "hello brave new world", maxRunes: 11, want: "hello brave", wantCut: true},
+		{name: "one long word is cut inside", content: "supercalifragilistic", maxRunes: 5, want: "super", wantCut: true},
+		{name: "multi-byte runes are not split", content: "日本語のテキストです", maxRunes: 4, want: "日本語の", wantCut: true},
+		{name: "emoji", content: "🙂🙂🙂 🙂🙂", maxRunes: 5, want: "🙂🙂🙂", wantCut: true},
+		{name: "newlines count as spaces", content: "first line\nsecond line", maxRunes: 14, want: "first line", wantCut: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cut := model.Excerpt(tt.content, tt.maxRunes)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantCut, cut)
+			assert.True(t, utf8.ValidString(got))
+		})
+	}
+}
+
+func TestPostService_ListPosts_CanonicalizesTagNames(t *testing.T) {
+	s, _ := newScheduleService(t)
+	ctx := context.Background()
+	for _, tag := range []string{"golang", "gos", "go_lang"} {
+		_, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: tag, Tags: []string{tag}})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr error
+	}{
+		{name: "differently cased and padded", tags: []string{"  GoLang "}, want: []string{"golang"}},
+		{name: "underscore is no wildcard", tags: []string{"go_"}, want: []string{}},
+		{name: "percent is no wildcard", tags: []string{"go%"}, want: []string{}},
+		{name: "underscore matches itself", tags: []string{"GO_LANG"}, want: []string{"go_lang"}},
+		{name: "empty after normalization", tags: []string{"golang", "   "}, wantErr: custom_errors.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := s.ListPosts(ctx, &model.PostFilters{TagNames: tt.tags})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			titles := make([]string, len(got))
+			for i, post := range got {
+				titles[i] = post.Post.Title
+			}
+			assert.Equal(t, tt.want, titles)
+			assert.Equal(t, len(tt.want), total)
+		})
+	}
+}
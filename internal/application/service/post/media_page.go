@@ -0,0 +1,59 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// GetPostMedia returns a page of a post's media ordered by position and how many media the
+// post has, for a post with more than a response carries (see
+// model.PostDetailed.HasMoreMedia). A zero limit means limits.MaxEmbeddedMedia. The post must
+// be visible to requesterID; a rejected post has no media for anyone but its author, as
+// model.PostDetailed.RedactedFor has it.
+func (s *PostService) GetPostMedia(ctx context.Context, postID int64, requesterID *int64, limit, offset int) (_ []*model.PostMedia, _ int, err error) {
+	defer observePostOperation(s.metrics, "media", time.Now(), &err)
+	if limit == 0 {
+		limit = s.limits.MaxEmbeddedMedia
+		if limit <= 0 {
+			limit = model.DefaultMaxEmbeddedMedia
+		}
+	}
+	if limit < 0 || limit > model.MaxMediaPageSize {
+		return nil, 0, fmt.Errorf("%w: limit must be between 1 and %d, got %d", custom_errors.ErrInvalidInput, model.MaxMediaPageSize, limit)
+	}
+	if offset < 0 {
+		return nil, 0, fmt.Errorf("%w: offset must not be negative, got %d", custom_errors.ErrInvalidInput, offset)
+	}
+
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrPostNotFound) {
+			s.log.Debug("Post not found", slog.Int64("id", postID))
+			return nil, 0, custom_errors.ErrPostNotFound
+		}
+		s.log.Error("Failed to get post", slog.String("error", err.Error()), slog.Int64("id", postID))
+		return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	if err := post.CheckAccess(requesterID); err != nil {
+		s.log.Debug("Post is hidden from requester", slog.Int64("id", postID), slog.Any("requesterID", requesterID), slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+	if post.IsRejected() && (requesterID == nil || *requesterID != post.AuthorID) {
+		return []*model.PostMedia{}, 0, nil
+	}
+
+	media, total, err := s.mediaRepo.GetPageByPost(ctx, postID, limit, offset)
+	if err != nil {
+		s.log.Error("Failed to get media page of post", slog.Int64("id", postID), slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+	return media, total, nil
+}
@@ -0,0 +1,98 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+func createPostWithMedia(t *testing.T, s *PostService, count int) *model.PostDetailed {
+	t.Helper()
+	media := make([]*model.PostMediaInput, count)
+	for i := range media {
+		media[i] = &model.PostMediaInput{URL: fmt.Sprintf("https://example.com/%d.jpg", i+1), Type: model.MediaTypeImage, Position: int32(i + 1)}
+	}
+	created, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Album", MediaItems: media})
+	require.NoError(t, err)
+	return created
+}
+
+func TestPostService_GetPostByID_CapsMedia(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("under the cap", func(t *testing.T) {
+		s, _ := newScheduleService(t)
+		s.limits.MaxEmbeddedMedia = 5
+		created := createPostWithMedia(t, s, 3)
+
+		post, err := s.GetPostByID(ctx, created.Post.ID, nil)
+
+		require.NoError(t, err)
+		assert.Len(t, post.Media, 3)
+		assert.Equal(t, 3, post.MediaTotal)
+		assert.False(t, post.HasMoreMedia())
+	})
+
+	t.Run("over the cap", func(t *testing.T) {
+		s, _ := newScheduleService(t)
+		s.limits.MaxEmbeddedMedia = 3
+		created := createPostWithMedia(t, s, 7)
+		assert.True(t, created.HasMoreMedia())
+
+		post, err := s.GetPostByID(ctx, created.Post.ID, nil)
+		require.NoError(t, err)
+		require.Len(t, post.Media, 3)
+		assert.Equal(t, []int32{1, 2, 3}, mediaPositions(post.Media))
+		assert.Equal(t, 7, post.MediaTotal)
+		assert.True(t, post.HasMoreMedia())
+
+		page, total, err := s.GetPostMedia(ctx, created.Post.ID, nil, 0, len(post.Media))
+		require.NoError(t, err)
+		assert.Equal(t, 7, total)
+		assert.Equal(t, []int32{4, 5, 6}, mediaPositions(page))
+
+		page, _, err = s.GetPostMedia(ctx, created.Post.ID, nil, 3, 6)
+		require.NoError(t, err)
+		assert.Equal(t, []int32{7}, mediaPositions(page))
+	})
+}
+
+func TestPostService_GetPostMedia_Rejected(t *testing.T) {
+	s, _ := newScheduleService(t)
+	ctx := context.Background()
+	created := createPostWithMedia(t, s, 2)
+
+	tests := []struct {
+		name    string
+		postID  int64
+		limit   int
+		offset  int
+		wantErr error
+	}{
+		{name: "limit above page size", postID: created.Post.ID, limit: model.MaxMediaPageSize + 1, wantErr: custom_errors.ErrInvalidInput},
+		{name: "negative limit", postID: created.Post.ID, limit: -1, wantErr: custom_errors.ErrInvalidInput},
+		{name: "negative offset", postID: created.Post.ID, limit: 10, offset: -1, wantErr: custom_errors.ErrInvalidInput},
+		{name: "missing post", postID: created.Post.ID + 100, limit: 10, wantErr: custom_errors.ErrPostNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.GetPostMedia(ctx, tt.postID, nil, tt.limit, tt.offset)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func mediaPositions(media []*model.PostMedia) []int32 {
+	positions := make([]int32, len(media))
+	for i, m := range media {
+		positions[i] = m.Position
+	}
+	return positions
+}
@@ -0,0 +1,170 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/testsupport"
+)
+
+func TestPostService_CreatePost_RejectsDuplicateMedia(t *testing.T) {
+	tests := []struct {
+		name      string
+		media     []*model.PostMediaInput
+		wantField string
+	}{
+		{
+			name: "same position",
+			media: []*model.PostMediaInput{
+				{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+				{URL: "https://example.com/2.jpg", Type: model.MediaTypeImage, Position: 1},
+			},
+			wantField: "media[1].position",
+		},
+		{
+			name: "same url",
+			media: []*model.PostMediaInput{
+				{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+				{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 2},
+			},
+			wantField: "media[1].url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newScheduleService(t)
+			ctx := context.Background()
+
+			_, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Dupes", MediaItems: tt.media})
+
+			require.ErrorIs(t, err, custom_errors.ErrPostValidation)
+			var verr *model.ValidationError
+			require.True(t, errors.As(err, &verr))
+			require.Len(t, verr.Violations, 1)
+			assert.Equal(t, tt.wantField, verr.Violations[0].Field)
+
+			_, total, err := s.ListPosts(ctx, &model.PostFilters{})
+			require.NoError(t, err)
+			assert.Zero(t, total, "nothing is written for a rejected post")
+		})
+	}
+}
+
+func TestPostService_CreatePost_RejectsUnknownMediaType(t *testing.T) {
+	s, _ := newScheduleService(t)
+	ctx := context.Background()
+
+	_, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Typo", MediaItems: []*model.PostMediaInput{
+		{URL: "https://example.com/1.jpg", Type: "img", Position: 1},
+	}})
+
+	require.ErrorIs(t, err, custom_errors.ErrPostValidation)
+	var verr *model.ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Len(t, verr.Violations, 1)
+	assert.Equal(t, "media[0].type", verr.Violations[0].Field)
+	assert.Contains(t, verr.Violations[0].Description, `"img"`)
+}
+
+func TestPostService_UpdatePost_ReplacesMediaReusingPositionsAndURLs(t *testing.T) {
+	s, _ := newScheduleService(t)
+	ctx := context.Background()
+
+	created, err := s.CreatePost(ctx, &model.CreatePostDTO{
+		AuthorID: 1,
+		Title:    "Gallery",
+		MediaItems: []*model.PostMediaInput{
+			{URL: "https://example.com/a.jpg", Type: model.MediaTypeImage, Position: 1},
+			{URL: "https://example.com/b.jpg", Type: model.MediaTypeImage, Position: 2},
+		},
+	})
+	require.NoError(t, err)
+
+	updated, err := s.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{
+		MediaItems: []*model.PostMediaInput{
+			{URL: "https://example.com/b.jpg", Type: model.MediaTypeImage, Position: 1},
+			{URL: "https://example.com/a.jpg", Type: model.MediaTypeImage, Position: 2},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, updated.Media, 2)
+	assert.Equal(t, "https://example.com/b.jpg", updated.Media[0].URL)
+	assert.Equal(t, "https://example.com/a.jpg", updated.Media[1].URL)
+
+	_, err = s.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{
+		MediaItems: []*model.PostMediaInput{
+			{URL: "https://example.com/c.jpg", Type: model.MediaTypeImage, Position: 3},
+			{URL: "https://example.com/d.jpg", Type: model.MediaTypeImage, Position: 3},
+		},
+	})
+	assert.ErrorIs(t, err, custom_errors.ErrPostValidation)
+}
+
+func TestPostService_UpdatePost_AddsMediaToAPostWithoutMedia(t *testing.T) {
+	s, _ := newScheduleService(t)
+	ctx := context.Background()
+
+	created, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Text only"})
+	require.NoError(t, err)
+	require.Empty(t, created.Media)
+
+	updated, err := s.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{
+		MediaItems: []*model.PostMediaInput{
+			{URL: "https://example.com/a.jpg", Type: model.MediaTypeImage, Position: 1},
+			{URL: "https://example.com/b.jpg", Type: model.MediaTypeImage, Position: 2},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, updated.Media, 2)
+	assert.Equal(t, "https://example.com/a.jpg", updated.Media[0].URL)
+	assert.Equal(t, "https://example.com/b.jpg", updated.Media[1].URL)
+
+	got, err := s.GetPostByID(ctx, created.Post.ID, nil)
+	require.NoError(t, err)
+	assert.Len(t, got.Media, 2, "the new media are attached, not only returned")
+}
+
+func TestPostService_MediaOnlyPost(t *testing.T) {
+	s, _ := newScheduleService(t)
+	ctx := context.Background()
+
+	created, err := s.CreatePost(ctx, &model.CreatePostDTO{
+		AuthorID:   1,
+		Title:      "  ",
+		MediaItems: []*model.PostMediaInput{{URL: "https://example.com/a.jpg", Type: model.MediaTypeImage, Position: 1}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, created.Post.Title, "a blank title is stored as none")
+	require.Len(t, created.Media, 1)
+
+	listed, total, err := s.ListPosts(ctx, &model.PostFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, listed, 1)
+	assert.Empty(t, listed[0].Post.Title)
+	assert.Len(t, listed[0].Media, 1)
+
+	updated, err := s.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{Title: testsupport.Ptr("Sunset")})
+	require.NoError(t, err)
+	assert.Equal(t, "Sunset", updated.Post.Title)
+	assert.Len(t, updated.Media, 1, "adding a title keeps the media")
+
+	_, err = s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Content: testsupport.Ptr(" ")})
+	require.ErrorIs(t, err, custom_errors.ErrPostValidation)
+	var verr *model.ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, "title", verr.Violations[0].Field)
+
+	captioned, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Content: testsupport.Ptr("Just a caption")})
+	require.NoError(t, err, "content alone is enough")
+	assert.Empty(t, captioned.Post.Title)
+}
@@ -0,0 +1,46 @@
+package post_service
+
+import (
+	"testing"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+	user_client "pinstack-post-service/internal/domain/ports/output/user"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	repository_memory "pinstack-post-service/internal/infrastructure/outbound/repository/memory"
+)
+
+// memoryServiceDeps overrides what newMemoryServiceWith builds the service from. Zero fields keep
+// the defaults: a countingUsers client, Prometheus metrics and the default limits.
+type memoryServiceDeps struct {
+	users   user_client.Client
+	metrics output.MetricsProvider
+	limits  *model.PostLimits
+}
+
+// newMemoryService returns a service over a fresh in-memory database, together with the database.
+func newMemoryService(t testing.TB) (*PostService, *repository_memory.Database) {
+	t.Helper()
+	return newMemoryServiceWith(t, memoryServiceDeps{})
+}
+
+// newMemoryServiceWith is newMemoryService with deps in place of the defaults they set.
+func newMemoryServiceWith(t testing.TB, deps memoryServiceDeps) (*PostService, *repository_memory.Database) {
+	t.Helper()
+	if deps.users == nil {
+		deps.users = &countingUsers{}
+	}
+	if deps.metrics == nil {
+		deps.metrics = prometheus.NewPrometheusMetricsProvider()
+	}
+	limits := model.DefaultPostLimits()
+	if deps.limits != nil {
+		limits = *deps.limits
+	}
+	log := logger.New("test")
+	database := repository_memory.NewDatabase(log)
+	s := NewPostService(database.Posts, database.Tags, database.Media, database.UnitOfWork, log, deps.users,
+		deps.metrics, limits)
+	return s, database
+}
@@ -0,0 +1,108 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// ForceDeletePost deletes a post on behalf of moderator actorID whoever its author is. The
+// cleanup is the one of DeletePost, and the actor and reason go to the moderation log in the
+// same transaction. The recorded action names the author, whose cached data callers refresh.
+func (s *PostService) ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (_ *model.ModerationAction, err error) {
+	defer observePostOperation(s.metrics, "force_delete", time.Now(), &err)
+	reason = strings.TrimSpace(reason)
+	if err := model.ValidateModerationReason(reason); err != nil {
+		s.log.Debug("Force delete validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, err
+	}
+	if actorID <= 0 {
+		return nil, custom_errors.ErrInvalidInput
+	}
+
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrPostNotFound) {
+			s.log.Debug("Post not found for force delete", slog.Int64("post_id", postID))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		s.log.Error("Failed to get post for force delete", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	var recorded *model.ModerationAction
+	err = s.runInTx(ctx, "force_delete", func(ctx context.Context, tx postgres.Transaction) error {
+		if err := s.removePost(ctx, tx, "force_delete", postID); err != nil {
+			return err
+		}
+		var err error
+		recorded, err = tx.ModerationRepository().Record(ctx, &model.ModerationAction{
+			Action:   model.ModerationActionForceDelete,
+			PostID:   postID,
+			AuthorID: post.AuthorID,
+			ActorID:  actorID,
+			Reason:   reason,
+		})
+		if err != nil {
+			s.log.Error("Failed to record force delete", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+			return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Post force deleted",
+		slog.Int64("post_id", postID),
+		slog.Int64("author_id", post.AuthorID),
+		slog.Int64("actor_id", actorID),
+		slog.String("reason", reason))
+	return recorded, nil
+}
+
+// SetModerationStatus moves a post to status on behalf of moderator actorID; reason is
+// required for a rejection and empty otherwise. A rejected post stays readable by its
+// author and is redacted for everyone else; see model.PostDetailed.RedactedFor. The post is
+// returned as stored, unredacted.
+func (s *PostService) SetModerationStatus(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason) (_ *model.Post, err error) {
+	defer observePostOperation(s.metrics, "set_moderation_status", time.Now(), &err)
+	if err := model.ValidateModerationStatus(status, reason); err != nil {
+		s.log.Debug("Moderation status validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, err
+	}
+	if actorID <= 0 || postID <= 0 {
+		return nil, fmt.Errorf("%w: actor and post ids must be positive", custom_errors.ErrInvalidInput)
+	}
+
+	var stored *model.RejectionReason
+	if reason != "" {
+		stored = &reason
+	}
+	post, err := s.postRepo.SetModerationStatus(ctx, postID, status, stored, s.now())
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrPostNotFound) {
+			s.log.Debug("Post not found for moderation", slog.Int64("post_id", postID))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		s.log.Error("Failed to set moderation status", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	s.log.Info("Post moderation status set",
+		slog.Int64("post_id", postID),
+		slog.Int64("author_id", post.AuthorID),
+		slog.Int64("actor_id", actorID),
+		slog.String("status", string(status)),
+		slog.String("reason", string(reason)))
+	return post, nil
+}
@@ -0,0 +1,270 @@
+package post_service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/testsupport"
+)
+
+func TestPostService_ForceDeletePost(t *testing.T) {
+	s, database := newScheduleService(t)
+	ctx := context.Background()
+	created, err := s.CreatePost(ctx, &model.CreatePostDTO{
+		AuthorID: 1,
+		Title:    "Spam",
+		Tags:     []string{"deals"},
+		MediaItems: []*model.PostMediaInput{
+			{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+		},
+	})
+	require.NoError(t, err)
+	postID := created.Post.ID
+
+	action, err := s.ForceDeletePost(ctx, 99, postID, "  spam links  ")
+	require.NoError(t, err)
+
+	assert.Equal(t, model.ModerationActionForceDelete, action.Action)
+	assert.Equal(t, postID, action.PostID)
+	assert.Equal(t, int64(1), action.AuthorID)
+	assert.Equal(t, int64(99), action.ActorID)
+	assert.Equal(t, "spam links", action.Reason)
+	assert.NotZero(t, action.ID)
+
+	_, err = s.GetPostByID(ctx, postID, nil)
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	media, err := database.Media.GetByPost(ctx, postID)
+	require.NoError(t, err)
+	assert.Empty(t, media)
+	posts, err := database.Tags.FindPostIDsByTags(ctx, []int64{created.Tags[0].ID})
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+	assert.Equal(t, []model.ModerationAction{*action}, database.Moderation.Actions())
+}
+
+func TestPostService_ForceDeletePost_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int64
+		postID  func(created int64) int64
+		reason  string
+		wantErr error
+	}{
+		{name: "short reason", actorID: 99, reason: "bad", wantErr: custom_errors.ErrPostValidation},
+		{name: "blank reason", actorID: 99, reason: "       ", wantErr: custom_errors.ErrPostValidation},
+		{name: "long reason", actorID: 99, reason: strings.Repeat("a", model.ModerationReasonMaxLength+1), wantErr: custom_errors.ErrPostValidation},
+		{name: "no actor", reason: "spam links", wantErr: custom_errors.ErrInvalidInput},
+		{name: "missing post", actorID: 99, postID: func(created int64) int64 { return created + 1 }, reason: "spam links", wantErr: custom_errors.ErrPostNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, database := newScheduleService(t)
+			ctx := context.Background()
+			created, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Kept"})
+			require.NoError(t, err)
+			postID := created.Post.ID
+			if tt.postID != nil {
+				postID = tt.postID(postID)
+			}
+
+			_, err = s.ForceDeletePost(ctx, tt.actorID, postID, tt.reason)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			_, err = s.GetPostByID(ctx, created.Post.ID, nil)
+			assert.NoError(t, err, "the post is kept")
+			assert.Empty(t, database.Moderation.Actions())
+		})
+	}
+}
+
+func TestPostService_DeletePost_StillRequiresAuthor(t *testing.T) {
+	s, database := newScheduleService(t)
+	ctx := context.Background()
+	created, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Mine"})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, s.DeletePost(ctx, 2, created.Post.ID), custom_errors.ErrForbidden)
+	require.NoError(t, s.DeletePost(ctx, 1, created.Post.ID))
+	assert.Empty(t, database.Moderation.Actions(), "an author deleting their own post is not moderation")
+}
+
+// newModeratedPost stores a post with media by author 1 and gives it status.
+func newModeratedPost(t *testing.T, s *PostService, moderationStatus model.ModerationStatus, reason model.RejectionReason) int64 {
+	t.Helper()
+	created, err := s.CreatePost(context.Background(), testsupport.NewCreatePostDTOBuilder().
+		WithTitle("Deals").
+		WithContent("Buy now").
+		WithTags("deals").
+		WithMedia("https://example.com/1.jpg").
+		Build())
+	require.NoError(t, err)
+	_, err = s.SetModerationStatus(context.Background(), 99, created.Post.ID, moderationStatus, reason)
+	require.NoError(t, err)
+	return created.Post.ID
+}
+
+func TestPostService_SetModerationStatus_GetPostByID(t *testing.T) {
+	author, other := int64(1), int64(2)
+
+	tests := []struct {
+		name         string
+		status       model.ModerationStatus
+		reason       model.RejectionReason
+		requesterID  *int64
+		wantRedacted bool
+	}{
+		{name: "approved to anonymous", status: model.ModerationStatusApproved},
+		{name: "approved to another user", status: model.ModerationStatusApproved, requesterID: &other},
+		{name: "approved to author", status: model.ModerationStatusApproved, requesterID: &author},
+		{name: "under review to anonymous", status: model.ModerationStatusUnderReview},
+		{name: "under review to another user", status: model.ModerationStatusUnderReview, requesterID: &other},
+		{name: "under review to author", status: model.ModerationStatusUnderReview, requesterID: &author},
+		{name: "rejected to anonymous", status: model.ModerationStatusRejected, reason: model.RejectionReasonSpam, wantRedacted: true},
+		{name: "rejected to another user", status: model.ModerationStatusRejected, reason: model.RejectionReasonSpam, requesterID: &other, wantRedacted: true},
+		{name: "rejected to author", status: model.ModerationStatusRejected, reason: model.RejectionReasonSpam, requesterID: &author},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newScheduleService(t)
+			postID := newModeratedPost(t, s, tt.status, tt.reason)
+
+			got, err := s.GetPostByID(context.Background(), postID, tt.requesterID)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, got.Post.ModerationStatus)
+			assert.Equal(t, tt.wantRedacted, got.Redacted)
+			if tt.reason != "" {
+				require.NotNil(t, got.Post.RejectionReason)
+				assert.Equal(t, tt.reason, *got.Post.RejectionReason)
+			} else {
+				assert.Nil(t, got.Post.RejectionReason)
+			}
+			if tt.wantRedacted {
+				assert.Empty(t, got.Post.Title)
+				assert.Nil(t, got.Post.Content)
+				assert.Empty(t, got.Media, "media urls are not handed out")
+			} else {
+				assert.Equal(t, "Deals", got.Post.Title)
+				require.NotNil(t, got.Post.Content)
+				require.Len(t, got.Media, 1)
+				assert.Equal(t, "https://example.com/1.jpg", got.Media[0].URL)
+			}
+			assert.Len(t, got.Tags, 1)
+		})
+	}
+}
+
+func TestPostService_SetModerationStatus_ListPostsLeavesOutRejected(t *testing.T) {
+	s, _ := newScheduleService(t)
+	ctx := context.Background()
+	approved := newModeratedPost(t, s, model.ModerationStatusApproved, "")
+	underReview := newModeratedPost(t, s, model.ModerationStatusUnderReview, "")
+	rejected := newModeratedPost(t, s, model.ModerationStatusRejected, model.RejectionReasonCopyright)
+	author, other := int64(1), int64(2)
+
+	tests := []struct {
+		name    string
+		filters model.PostFilters
+		want    []int64
+	}{
+		{name: "anonymous", want: []int64{approved, underReview}},
+		{name: "another user", filters: model.PostFilters{RequesterID: &other}, want: []int64{approved, underReview}},
+		{name: "author", filters: model.PostFilters{AuthorID: &author, RequesterID: &author}, want: []int64{approved, underReview, rejected}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := tt.filters
+			got, total, err := s.ListPosts(ctx, &filters)
+
+			require.NoError(t, err)
+			ids := make([]int64, len(got))
+			for i, post := range got {
+				ids[i] = post.Post.ID
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+			assert.Equal(t, len(tt.want), total)
+		})
+	}
+}
+
+func TestPostService_SetModerationStatus_GetPostsByIDsRedacts(t *testing.T) {
+	s, _ := newScheduleService(t)
+	approved := newModeratedPost(t, s, model.ModerationStatusApproved, "")
+	rejected := newModeratedPost(t, s, model.ModerationStatusRejected, model.RejectionReasonViolence)
+
+	got, err := s.GetPostsByIDs(context.Background(), []int64{approved, rejected})
+
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.False(t, got[0].Redacted)
+	assert.True(t, got[1].Redacted)
+	assert.Empty(t, got[1].Post.Title)
+	assert.Empty(t, got[1].Media)
+	assert.Equal(t, model.ModerationStatusRejected, got[1].Post.ModerationStatus)
+}
+
+func TestPostService_SetModerationStatus_Reinstates(t *testing.T) {
+	s, _ := newScheduleService(t)
+	postID := newModeratedPost(t, s, model.ModerationStatusRejected, model.RejectionReasonOther)
+
+	post, err := s.SetModerationStatus(context.Background(), 99, postID, model.ModerationStatusApproved, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, model.ModerationStatusApproved, post.ModerationStatus)
+	assert.Nil(t, post.RejectionReason)
+	got, err := s.GetPostByID(context.Background(), postID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Deals", got.Post.Title)
+}
+
+func TestPostService_SetModerationStatus_Rejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		postID    func(int64) int64
+		status    model.ModerationStatus
+		reason    model.RejectionReason
+		wantErr   error
+		wantField string
+	}{
+		{name: "unknown status", status: "hidden", wantErr: custom_errors.ErrPostValidation, wantField: "moderation_status"},
+		{name: "rejection without reason", status: model.ModerationStatusRejected, wantErr: custom_errors.ErrPostValidation, wantField: "rejection_reason"},
+		{name: "unknown reason", status: model.ModerationStatusRejected, reason: "boring", wantErr: custom_errors.ErrPostValidation, wantField: "rejection_reason"},
+		{name: "reason without rejection", status: model.ModerationStatusUnderReview, reason: model.RejectionReasonSpam, wantErr: custom_errors.ErrPostValidation, wantField: "rejection_reason"},
+		{name: "missing post", postID: func(id int64) int64 { return id + 100 }, status: model.ModerationStatusUnderReview, wantErr: custom_errors.ErrPostNotFound},
+		{name: "invalid post id", postID: func(int64) int64 { return 0 }, status: model.ModerationStatusUnderReview, wantErr: custom_errors.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newScheduleService(t)
+			ctx := context.Background()
+			created, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Kept"})
+			require.NoError(t, err)
+			postID := created.Post.ID
+			if tt.postID != nil {
+				postID = tt.postID(postID)
+			}
+
+			_, err = s.SetModerationStatus(ctx, 99, postID, tt.status, tt.reason)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantField != "" {
+				var validation *model.ValidationError
+				require.ErrorAs(t, err, &validation)
+				assert.Equal(t, tt.wantField, validation.Violations[0].Field)
+			}
+			got, err := s.GetPostByID(ctx, created.Post.ID, nil)
+			require.NoError(t, err)
+			assert.Equal(t, model.ModerationStatusApproved, got.Post.ModerationStatus, "the status is kept")
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package post_service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+)
+
+// Outcomes of a post operation, as IncrementPostOperation labels them.
+const (
+	outcomeOK         = "ok"
+	outcomeNotFound   = "not_found"
+	outcomeForbidden  = "forbidden"
+	outcomeValidation = "validation"
+	outcomeDBError    = "db_error"
+	outcomeError      = "error"
+)
+
+// operationOutcome maps the error an operation returned to its outcome label. Errors that are
+// none of the others, such as a failed user service call or a timeout, are "error".
+func operationOutcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeOK
+	case errors.Is(err, custom_errors.ErrPostNotFound):
+		return outcomeNotFound
+	case errors.Is(err, custom_errors.ErrForbidden):
+		return outcomeForbidden
+	case errors.Is(err, custom_errors.ErrPostValidation),
+		errors.Is(err, custom_errors.ErrInvalidInput),
+		errors.Is(err, model.ErrMediaDuplicate),
+		errors.Is(err, model.ErrEmptyUpdate):
+		return outcomeValidation
+	case errors.Is(err, custom_errors.ErrDatabaseQuery),
+		errors.Is(err, custom_errors.ErrDatabaseTransaction),
+		errors.Is(err, custom_errors.ErrDatabaseConnection),
+		errors.Is(err, custom_errors.ErrMediaAttachFailed),
+		errors.Is(err, custom_errors.ErrMediaQueryFailed),
+		errors.Is(err, custom_errors.ErrTagQueryFailed):
+		return outcomeDBError
+	}
+	return outcomeError
+}
+
+// observePostOperation counts operation by the outcome of *err and records how long it took
+// since start. Deferred at the top of an operation, it sees the error the caller gets.
+func observePostOperation(metrics output.MetricsProvider, operation string, start time.Time, err *error) {
+	metrics.IncrementPostOperation(operation, operationOutcome(*err))
+	metrics.RecordPostOperationDuration(operation, time.Since(start))
+}
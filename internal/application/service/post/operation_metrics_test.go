@@ -0,0 +1,156 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/cache/noop"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/testsupport"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_repository_mock "pinstack-post-service/mocks/post"
+)
+
+// operationMetrics records the business operation metrics and passes everything else on.
+type operationMetrics struct {
+	output.MetricsProvider
+	mu        sync.Mutex
+	outcomes  map[string][]string
+	durations map[string]int
+	reads     map[string][]bool
+}
+
+func newOperationMetrics() *operationMetrics {
+	return &operationMetrics{
+		MetricsProvider: prometheus.NewPrometheusMetricsProvider(),
+		outcomes:        map[string][]string{},
+		durations:       map[string]int{},
+		reads:           map[string][]bool{},
+	}
+}
+
+func (m *operationMetrics) IncrementPostOperation(operation, outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outcomes[operation] = append(m.outcomes[operation], outcome)
+}
+
+func (m *operationMetrics) RecordPostOperationDuration(operation string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations[operation]++
+}
+
+func (m *operationMetrics) IncrementPostReads(operation string, cached bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reads[operation] = append(m.reads[operation], cached)
+}
+
+func TestPostService_RecordsOperationOutcomes(t *testing.T) {
+	metrics := newOperationMetrics()
+	s, _ := newMemoryServiceWith(t, memoryServiceDeps{metrics: metrics})
+	ctx := context.Background()
+
+	created, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Counted"})
+	require.NoError(t, err)
+	_, err = s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1})
+	require.Error(t, err)
+
+	_, err = s.GetPostByID(ctx, created.Post.ID, nil)
+	require.NoError(t, err)
+	_, err = s.GetPostByID(ctx, 999, nil)
+	require.Error(t, err)
+
+	_, _, err = s.ListPosts(ctx, &model.PostFilters{})
+	require.NoError(t, err)
+	_, _, err = s.ListPosts(ctx, &model.PostFilters{Limit: testsupport.Ptr(-1)})
+	require.Error(t, err)
+
+	_, err = s.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{Title: testsupport.Ptr("Counted again")})
+	require.NoError(t, err)
+	_, err = s.UpdatePost(ctx, 2, created.Post.ID, &model.UpdatePostDTO{Title: testsupport.Ptr("Not theirs")})
+	require.Error(t, err)
+	_, err = s.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{})
+	require.Error(t, err)
+
+	require.NoError(t, s.DeletePost(ctx, 1, created.Post.ID))
+	require.Error(t, s.DeletePost(ctx, 1, created.Post.ID))
+
+	assert.Equal(t, map[string][]string{
+		"create": {"ok", "validation"},
+		"get":    {"ok", "not_found"},
+		"list":   {"ok", "validation"},
+		"update": {"ok", "forbidden", "validation"},
+		"delete": {"ok", "not_found"},
+	}, metrics.outcomes)
+	assert.Equal(t, map[string]int{"create": 2, "get": 2, "list": 2, "update": 3, "delete": 2}, metrics.durations)
+}
+
+func TestPostService_RecordsOutcomesOfTheOtherOperations(t *testing.T) {
+	metrics := newOperationMetrics()
+	s, _ := newMemoryServiceWith(t, memoryServiceDeps{metrics: metrics})
+	ctx := context.Background()
+
+	draft, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft})
+	require.NoError(t, err)
+	_, err = s.PublishPost(ctx, 2, draft.Post.ID)
+	require.Error(t, err)
+	_, err = s.PublishPost(ctx, 1, draft.Post.ID)
+	require.NoError(t, err)
+	_, err = s.PinPost(ctx, 1, draft.Post.ID)
+	require.NoError(t, err)
+	_, err = s.UnpinPost(ctx, 1, 999)
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"forbidden", "ok"}, metrics.outcomes["publish"])
+	assert.Equal(t, []string{"ok"}, metrics.outcomes["pin"])
+	assert.Equal(t, []string{"not_found"}, metrics.outcomes["unpin"])
+	assert.Equal(t, 2, metrics.durations["publish"])
+}
+
+func TestPostService_RecordsDatabaseErrorOutcome(t *testing.T) {
+	postRepo := new(post_repository_mock.Repository)
+	postRepo.On("List", mock.Anything, mock.Anything).Return(nil, 0, errors.New("connection reset"))
+	metrics := newOperationMetrics()
+	s := NewPostService(postRepo, nil, nil, nil, logger.New("test"), &countingUsers{}, metrics, model.DefaultPostLimits())
+
+	_, _, err := s.ListPosts(context.Background(), &model.PostFilters{})
+
+	require.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Equal(t, map[string][]string{"list": {"db_error"}}, metrics.outcomes)
+}
+
+func TestPostServiceCacheDecorator_GetPostByID_RecordsCachedReads(t *testing.T) {
+	metrics := newOperationMetrics()
+	service, _ := newMemoryServiceWith(t, memoryServiceDeps{metrics: metrics})
+	ctx := context.Background()
+	created, err := service.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Cached"})
+	require.NoError(t, err)
+	metrics.outcomes = map[string][]string{}
+
+	postCache := new(cache_mock.PostCache)
+	postCache.On("GetPost", mock.Anything, created.Post.ID).Return(nil, custom_errors.ErrCacheMiss).Once()
+	postCache.On("GetPost", mock.Anything, created.Post.ID).Return(created, nil).Once()
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, noop.NewBatcher(), logger.New("test"), metrics)
+
+	_, err = d.GetPostByID(ctx, created.Post.ID, nil)
+	require.NoError(t, err)
+	_, err = d.GetPostByID(ctx, created.Post.ID, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]bool{"get": {false, true}}, metrics.reads)
+	assert.Equal(t, map[string][]string{"get": {"ok"}}, metrics.outcomes, "a cache hit is not counted as a service read")
+	postCache.AssertExpectations(t)
+}
@@ -0,0 +1,98 @@
+package post_service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+	consistency_repository "pinstack-post-service/internal/domain/ports/output/consistency"
+)
+
+// OrphanReconciler removes post_media and posts_tags rows whose post or tag no longer
+// exists. It deletes batchSize rows per statement and waits pause between statements, so a
+// large cleanup does not saturate the database.
+type OrphanReconciler struct {
+	repo      consistency_repository.Repository
+	batchSize int
+	pause     time.Duration
+	log       output.Logger
+	metrics   output.MetricsProvider
+	// mu keeps an admin call and the startup run from reconciling at the same time.
+	mu sync.Mutex
+}
+
+func NewOrphanReconciler(
+	repo consistency_repository.Repository,
+	batchSize int,
+	pause time.Duration,
+	log output.Logger,
+	metrics output.MetricsProvider,
+) *OrphanReconciler {
+	return &OrphanReconciler{
+		repo:      repo,
+		batchSize: batchSize,
+		pause:     pause,
+		log:       log,
+		metrics:   metrics,
+	}
+}
+
+// ReconcileOrphans counts the orphans of every kind and, unless dryRun, deletes them. On an
+// error the report has what was done so far; batches deleted before it stay deleted.
+func (r *OrphanReconciler) ReconcileOrphans(ctx context.Context, dryRun bool) (*model.OrphanReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := time.Now()
+	report := &model.OrphanReport{
+		DryRun:  dryRun,
+		Found:   make(map[model.OrphanKind]int, len(model.OrphanKinds)),
+		Removed: make(map[model.OrphanKind]int, len(model.OrphanKinds)),
+	}
+	for _, kind := range model.OrphanKinds {
+		found, err := r.repo.CountOrphans(ctx, kind)
+		if err != nil {
+			return report, err
+		}
+		report.Found[kind] = found
+		r.metrics.AddOrphansFound(string(kind), found)
+		if dryRun || found == 0 {
+			continue
+		}
+		if err := r.removeOrphans(ctx, kind, report); err != nil {
+			return report, err
+		}
+	}
+
+	r.log.Info("Reconciled orphans",
+		slog.Bool("dry_run", dryRun),
+		slog.Any("found", report.Found),
+		slog.Any("removed", report.Removed),
+		slog.Duration("duration", time.Since(start)))
+	return report, nil
+}
+
+func (r *OrphanReconciler) removeOrphans(ctx context.Context, kind model.OrphanKind, report *model.OrphanReport) error {
+	for {
+		deleted, err := r.repo.DeleteOrphans(ctx, kind, r.batchSize)
+		if err != nil {
+			return err
+		}
+		report.Removed[kind] += deleted
+		r.metrics.AddOrphansRemoved(string(kind), deleted)
+		if deleted < r.batchSize {
+			return nil
+		}
+
+		timer := time.NewTimer(r.pause)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
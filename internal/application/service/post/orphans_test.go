@@ -0,0 +1,77 @@
+package post_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	consistency_repository_mock "pinstack-post-service/mocks/consistency"
+)
+
+func newTestReconciler(t *testing.T, batchSize int) (*OrphanReconciler, *consistency_repository_mock.Repository) {
+	repo := consistency_repository_mock.NewRepository(t)
+	return NewOrphanReconciler(repo, batchSize, time.Millisecond, logger.New("test"), prometheus.NewPrometheusMetricsProvider()), repo
+}
+
+func TestOrphanReconciler_RemovesInBatches(t *testing.T) {
+	r, repo := newTestReconciler(t, 100)
+	repo.On("CountOrphans", mock.Anything, model.OrphanMedia).Return(242, nil)
+	repo.On("CountOrphans", mock.Anything, model.OrphanPostTagsWithoutPost).Return(0, nil)
+	repo.On("CountOrphans", mock.Anything, model.OrphanPostTagsWithoutTag).Return(3, nil)
+	repo.On("DeleteOrphans", mock.Anything, model.OrphanMedia, 100).Return(100, nil).Twice()
+	repo.On("DeleteOrphans", mock.Anything, model.OrphanMedia, 100).Return(42, nil).Once()
+	repo.On("DeleteOrphans", mock.Anything, model.OrphanPostTagsWithoutTag, 100).Return(3, nil).Once()
+
+	report, err := r.ReconcileOrphans(context.Background(), false)
+
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, map[model.OrphanKind]int{model.OrphanMedia: 242, model.OrphanPostTagsWithoutPost: 0, model.OrphanPostTagsWithoutTag: 3}, report.Found)
+	assert.Equal(t, map[model.OrphanKind]int{model.OrphanMedia: 242, model.OrphanPostTagsWithoutTag: 3}, report.Removed)
+	repo.AssertNotCalled(t, "DeleteOrphans", mock.Anything, model.OrphanPostTagsWithoutPost, mock.Anything)
+}
+
+func TestOrphanReconciler_DryRunDeletesNothing(t *testing.T) {
+	r, repo := newTestReconciler(t, 100)
+	repo.On("CountOrphans", mock.Anything, mock.Anything).Return(5, nil)
+
+	report, err := r.ReconcileOrphans(context.Background(), true)
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 5, report.Found[model.OrphanMedia])
+	assert.Empty(t, report.Removed)
+	repo.AssertNotCalled(t, "DeleteOrphans", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrphanReconciler_ErrorKeepsProgress(t *testing.T) {
+	r, repo := newTestReconciler(t, 10)
+	repo.On("CountOrphans", mock.Anything, model.OrphanMedia).Return(25, nil)
+	repo.On("DeleteOrphans", mock.Anything, model.OrphanMedia, 10).Return(10, nil).Once()
+	repo.On("DeleteOrphans", mock.Anything, model.OrphanMedia, 10).Return(0, custom_errors.ErrDatabaseQuery).Once()
+
+	report, err := r.ReconcileOrphans(context.Background(), false)
+
+	assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Equal(t, 10, report.Removed[model.OrphanMedia])
+}
+
+func TestOrphanReconciler_StopsWhenCancelledBetweenBatches(t *testing.T) {
+	r, repo := newTestReconciler(t, 10)
+	r.pause = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	repo.On("CountOrphans", mock.Anything, model.OrphanMedia).Return(25, nil)
+	repo.On("DeleteOrphans", mock.Anything, model.OrphanMedia, 10).Run(func(mock.Arguments) { cancel() }).Return(10, nil).Once()
+
+	_, err := r.ReconcileOrphans(ctx, false)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,96 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// PinPost pins a published post of userID to the top of their profile, unpinning the post
+// pinned before it in the same transaction. The transaction is serializable, so two pins by
+// one author racing each other end with one of them retried rather than two pinned posts.
+// Pinning the pinned post changes nothing.
+func (s *PostService) PinPost(ctx context.Context, userID int64, id int64) (_ *model.PinChange, err error) {
+	defer observePostOperation(s.metrics, "pin", time.Now(), &err)
+	post, err := s.checkOwnership(ctx, "pin", userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != model.PostStatusPublished {
+		return nil, fmt.Errorf("%w: only a published post can be pinned, post %d is %s", custom_errors.ErrInvalidInput, id, post.Status)
+	}
+
+	var affected []int64
+	if post.IsPinned() {
+		s.log.Debug("Post already pinned", slog.Int64("id", id))
+	} else {
+		err = s.runInTxWithOptions(ctx, "pin", postgres.TxOptions{Isolation: postgres.Serializable}, func(ctx context.Context, tx postgres.Transaction) error {
+			postRepo := tx.PostRepository()
+			if _, err := s.lockPost(ctx, postRepo, "pin", id); err != nil {
+				return err
+			}
+			unpinned, err := postRepo.UnpinAuthor(ctx, userID)
+			if err != nil {
+				s.log.Error("Failed to unpin posts of author", slog.Int64("author_id", userID), slog.String("error", err.Error()))
+				return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+			}
+			if _, err := postRepo.Pin(ctx, id, s.now()); err != nil {
+				if errors.Is(err, custom_errors.ErrPostNotFound) {
+					s.log.Debug("Post deleted before it was pinned", slog.Int64("id", id))
+					return custom_errors.ErrPostNotFound
+				}
+				s.log.Error("Failed to pin post", slog.Int64("id", id), slog.String("error", err.Error()))
+				return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+			}
+			affected = append(unpinned, id)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.GetPostByID(ctx, id, &userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.PinChange{Post: result, AffectedPostIDs: affected}, nil
+}
+
+// UnpinPost unpins a post of userID. Unpinning a post that is not pinned changes nothing.
+func (s *PostService) UnpinPost(ctx context.Context, userID int64, id int64) (_ *model.PinChange, err error) {
+	defer observePostOperation(s.metrics, "unpin", time.Now(), &err)
+	post, err := s.checkOwnership(ctx, "unpin", userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var affected []int64
+	if !post.IsPinned() {
+		s.log.Debug("Post not pinned", slog.Int64("id", id))
+	} else {
+		if _, err := s.postRepo.Unpin(ctx, id); err != nil {
+			if errors.Is(err, custom_errors.ErrPostNotFound) {
+				s.log.Debug("Post not found for unpin", slog.Int64("id", id))
+				return nil, custom_errors.ErrPostNotFound
+			}
+			s.log.Error("Failed to unpin post", slog.Int64("id", id), slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		affected = []int64{id}
+	}
+
+	result, err := s.GetPostByID(ctx, id, &userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.PinChange{Post: result, AffectedPostIDs: affected}, nil
+}
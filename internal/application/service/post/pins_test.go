@@ -0,0 +1,115 @@
+package post_service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// newPinService stores posts 1 to n by author 1, oldest first, and post n+1 by author 2.
+func newPinService(t *testing.T, n int) *PostService {
+	t.Helper()
+	s, _ := newMemoryService(t)
+	for i := 0; i < n; i++ {
+		_, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+		require.NoError(t, err)
+	}
+	_, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 2, Title: "Other"})
+	require.NoError(t, err)
+	return s
+}
+
+func listedIDs(posts []*model.PostDetailed) []int64 {
+	ids := make([]int64, len(posts))
+	for i, post := range posts {
+		ids[i] = post.Post.ID
+	}
+	return ids
+}
+
+func TestPostService_PinPost_SwapsThePinnedPost(t *testing.T) {
+	s := newPinService(t, 3)
+	ctx := context.Background()
+
+	first, err := s.PinPost(ctx, 1, 1)
+	require.NoError(t, err)
+	assert.True(t, first.Post.Post.IsPinned())
+	assert.Equal(t, []int64{1}, first.AffectedPostIDs)
+
+	second, err := s.PinPost(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.True(t, second.Post.Post.IsPinned())
+	assert.ElementsMatch(t, []int64{1, 2}, second.AffectedPostIDs, "the previously pinned post changed too")
+
+	previous, err := s.GetPostByID(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.False(t, previous.Post.IsPinned())
+
+	again, err := s.PinPost(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.True(t, again.Post.Post.IsPinned())
+	assert.Empty(t, again.AffectedPostIDs, "pinning the pinned post changes nothing")
+}
+
+func TestPostService_PinPost_Refused(t *testing.T) {
+	s := newPinService(t, 1)
+	ctx := context.Background()
+	draft, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft})
+	require.NoError(t, err)
+
+	_, err = s.PinPost(ctx, 2, 1)
+	assert.ErrorIs(t, err, custom_errors.ErrForbidden)
+	_, err = s.PinPost(ctx, 1, 99)
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	_, err = s.PinPost(ctx, 1, draft.Post.ID)
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+}
+
+func TestPostService_UnpinPost(t *testing.T) {
+	s := newPinService(t, 2)
+	ctx := context.Background()
+	_, err := s.PinPost(ctx, 1, 1)
+	require.NoError(t, err)
+
+	notPinned, err := s.UnpinPost(ctx, 1, 2)
+	require.NoError(t, err)
+	assert.False(t, notPinned.Post.Post.IsPinned())
+	assert.Empty(t, notPinned.AffectedPostIDs, "unpinning a post that is not pinned changes nothing")
+	stillPinned, err := s.GetPostByID(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.True(t, stillPinned.Post.IsPinned(), "the author's pinned post stays pinned")
+
+	_, err = s.UnpinPost(ctx, 2, 1)
+	assert.ErrorIs(t, err, custom_errors.ErrForbidden)
+
+	unpinned, err := s.UnpinPost(ctx, 1, 1)
+	require.NoError(t, err)
+	assert.False(t, unpinned.Post.Post.IsPinned())
+	assert.Equal(t, []int64{1}, unpinned.AffectedPostIDs)
+}
+
+func TestPostService_ListPosts_PinnedFirstForAnAuthor(t *testing.T) {
+	s := newPinService(t, 4)
+	ctx := context.Background()
+	_, err := s.PinPost(ctx, 1, 2)
+	require.NoError(t, err)
+	author, limit := int64(1), 2
+
+	var pages [][]int64
+	for offset := 0; offset < 4; offset += limit {
+		posts, total, err := s.ListPosts(ctx, &model.PostFilters{AuthorID: &author, Limit: &limit, Offset: &offset})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		pages = append(pages, listedIDs(posts))
+	}
+	assert.Equal(t, [][]int64{{2, 4}, {3, 1}}, pages, "the pinned post leads the first page and does not repeat")
+
+	all, _, err := s.ListPosts(ctx, &model.PostFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5, 4, 3, 2, 1}, listedIDs(all), "lists of every author ignore pinning")
+}
@@ -0,0 +1,197 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_service "pinstack-post-service/internal/domain/ports/input/post"
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/domain/ports/output/rate_limiter"
+)
+
+const (
+	rateLimitOperationCreate     = "create_post"
+	rateLimitOperationBulkCreate = "bulk_create_posts"
+	rateLimitOperationUpdate     = "update_post"
+	rateLimitOperationDelete     = "delete_post"
+)
+
+type RateLimitRules struct {
+	CreatePost      model.RateLimitRule
+	BulkCreatePosts model.RateLimitRule
+	UpdatePost      model.RateLimitRule
+	DeletePost      model.RateLimitRule
+}
+
+type PostServiceRateLimitDecorator struct {
+	service post_service.Service
+	limiter rate_limiter.RateLimiter
+	rules   RateLimitRules
+	log     output.Logger
+	metrics output.MetricsProvider
+}
+
+func NewPostServiceRateLimitDecorator(
+	service post_service.Service,
+	limiter rate_limiter.RateLimiter,
+	rules RateLimitRules,
+	log output.Logger,
+	metrics output.MetricsProvider,
+) post_service.Service {
+	return &PostServiceRateLimitDecorator{
+		service: service,
+		limiter: limiter,
+		rules:   rules,
+		log:     log,
+		metrics: metrics,
+	}
+}
+
+func (d *PostServiceRateLimitDecorator) CreatePost(ctx context.Context, post *model.CreatePostDTO) (*model.PostDetailed, error) {
+	if err := d.allow(ctx, rateLimitOperationCreate, post.AuthorID, d.rules.CreatePost); err != nil {
+		return nil, err
+	}
+	return d.service.CreatePost(ctx, post)
+}
+
+// BulkCreatePosts counts calls of actorID against their own rule: a migration creates far more
+// posts per author than CreatePost allows.
+func (d *PostServiceRateLimitDecorator) BulkCreatePosts(ctx context.Context, actorID int64, posts []*model.CreatePostDTO) (*model.BulkCreateResult, error) {
+	if err := d.allow(ctx, rateLimitOperationBulkCreate, actorID, d.rules.BulkCreatePosts); err != nil {
+		return nil, err
+	}
+	return d.service.BulkCreatePosts(ctx, actorID, posts)
+}
+
+func (d *PostServiceRateLimitDecorator) GetPostByID(ctx context.Context, id int64, requesterID *int64) (*model.PostDetailed, error) {
+	return d.service.GetPostByID(ctx, id, requesterID)
+}
+
+func (d *PostServiceRateLimitDecorator) ListPosts(ctx context.Context, filters *model.PostFilters) ([]*model.PostDetailed, int, error) {
+	return d.service.ListPosts(ctx, filters)
+}
+
+func (d *PostServiceRateLimitDecorator) GetPostsByIDs(ctx context.Context, ids []int64) ([]*model.PostDetailed, error) {
+	return d.service.GetPostsByIDs(ctx, ids)
+}
+
+func (d *PostServiceRateLimitDecorator) UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) (*model.PostDetailed, error) {
+	if err := d.allow(ctx, rateLimitOperationUpdate, userID, d.rules.UpdatePost); err != nil {
+		return nil, err
+	}
+	return d.service.UpdatePost(ctx, userID, id, post)
+}
+
+func (d *PostServiceRateLimitDecorator) DeletePost(ctx context.Context, userID int64, id int64) error {
+	if err := d.allow(ctx, rateLimitOperationDelete, userID, d.rules.DeletePost); err != nil {
+		return err
+	}
+	return d.service.DeletePost(ctx, userID, id)
+}
+
+// ForceDeletePost is not limited: it is only reachable from the moderation tools.
+func (d *PostServiceRateLimitDecorator) ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (*model.ModerationAction, error) {
+	return d.service.ForceDeletePost(ctx, actorID, postID, reason)
+}
+
+// SetModerationStatus is not limited either, for the same reason.
+func (d *PostServiceRateLimitDecorator) SetModerationStatus(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason) (*model.Post, error) {
+	return d.service.SetModerationStatus(ctx, actorID, postID, status, reason)
+}
+
+func (d *PostServiceRateLimitDecorator) PublishPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	if err := d.allow(ctx, rateLimitOperationUpdate, userID, d.rules.UpdatePost); err != nil {
+		return nil, err
+	}
+	return d.service.PublishPost(ctx, userID, id)
+}
+
+func (d *PostServiceRateLimitDecorator) CancelScheduledPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	if err := d.allow(ctx, rateLimitOperationUpdate, userID, d.rules.UpdatePost); err != nil {
+		return nil, err
+	}
+	return d.service.CancelScheduledPost(ctx, userID, id)
+}
+
+func (d *PostServiceRateLimitDecorator) PinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	if err := d.allow(ctx, rateLimitOperationUpdate, userID, d.rules.UpdatePost); err != nil {
+		return nil, err
+	}
+	return d.service.PinPost(ctx, userID, id)
+}
+
+func (d *PostServiceRateLimitDecorator) UnpinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	if err := d.allow(ctx, rateLimitOperationUpdate, userID, d.rules.UpdatePost); err != nil {
+		return nil, err
+	}
+	return d.service.UnpinPost(ctx, userID, id)
+}
+
+func (d *PostServiceRateLimitDecorator) GetPostRevisions(ctx context.Context, userID int64, postID int64, limit, offset int) ([]*model.PostRevision, int, error) {
+	return d.service.GetPostRevisions(ctx, userID, postID, limit, offset)
+}
+
+func (d *PostServiceRateLimitDecorator) GetPostTags(ctx context.Context, postID int64, requesterID *int64, includeCounts bool) ([]*model.Tag, error) {
+	return d.service.GetPostTags(ctx, postID, requesterID, includeCounts)
+}
+
+func (d *PostServiceRateLimitDecorator) GetPostMedia(ctx context.Context, postID int64, requesterID *int64, limit, offset int) ([]*model.PostMedia, int, error) {
+	return d.service.GetPostMedia(ctx, postID, requesterID, limit, offset)
+}
+
+func (d *PostServiceRateLimitDecorator) SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error) {
+	return d.service.SuggestTags(ctx, prefix, limit, authorID)
+}
+
+// RenameTag and MergeTags are admin operations and are not rate limited.
+func (d *PostServiceRateLimitDecorator) RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error) {
+	return d.service.RenameTag(ctx, tagID, name)
+}
+
+func (d *PostServiceRateLimitDecorator) MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error) {
+	return d.service.MergeTags(ctx, sourceIDs, destID)
+}
+
+func (d *PostServiceRateLimitDecorator) ExportAuthorPosts(ctx context.Context, authorID int64, send func(*model.PostDetailed) error) error {
+	return d.service.ExportAuthorPosts(ctx, authorID, send)
+}
+
+func (d *PostServiceRateLimitDecorator) GetAuthorPostCount(ctx context.Context, authorID int64) (*model.AuthorPostCount, error) {
+	return d.service.GetAuthorPostCount(ctx, authorID)
+}
+
+func (d *PostServiceRateLimitDecorator) HasPostsSince(ctx context.Context, tagNames []string, since time.Time) (*model.TagActivity, error) {
+	return d.service.HasPostsSince(ctx, tagNames, since)
+}
+
+// allow fails open: limiter errors are logged and counted but never block the request.
+func (d *PostServiceRateLimitDecorator) allow(ctx context.Context, operation string, authorID int64, rule model.RateLimitRule) error {
+	if rule.Limit <= 0 || rule.Window <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s:%d", operation, authorID)
+	allowed, retryAfter, err := d.limiter.Allow(ctx, key, rule.Limit, rule.Window)
+	if err != nil {
+		d.log.Warn("Rate limiter unavailable, allowing request",
+			slog.String("operation", operation),
+			slog.Int64("author_id", authorID),
+			slog.String("error", err.Error()))
+		d.metrics.IncrementRateLimitChecks(operation, "error")
+		return nil
+	}
+	if !allowed {
+		d.log.Debug("Rate limit exceeded",
+			slog.String("operation", operation),
+			slog.Int64("author_id", authorID),
+			slog.Duration("retry_after", retryAfter))
+		d.metrics.IncrementRateLimitChecks(operation, "rejected")
+		return &model.RateLimitExceededError{Operation: operation, RetryAfter: retryAfter}
+	}
+
+	d.metrics.IncrementRateLimitChecks(operation, "allowed")
+	return nil
+}
@@ -0,0 +1,131 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	post_service_mock "pinstack-post-service/mocks/post"
+	rate_limiter_mock "pinstack-post-service/mocks/rate_limiter"
+)
+
+// countingLimiter emulates a fixed window: the first limit calls per key pass, the rest are rejected.
+func countingLimiter(limiter *rate_limiter_mock.RateLimiter) {
+	counts := make(map[string]int)
+	limiter.On("Allow", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("time.Duration")).
+		Return(func(_ context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+			counts[key]++
+			if counts[key] > limit {
+				return false, window, nil
+			}
+			return true, 0, nil
+		})
+}
+
+func TestPostServiceRateLimitDecorator_CreatePost(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	rules := RateLimitRules{
+		CreatePost: model.RateLimitRule{Limit: 3, Window: time.Minute},
+	}
+
+	t.Run("Nth request passes and N+1th is rejected", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		limiter := new(rate_limiter_mock.RateLimiter)
+		countingLimiter(limiter)
+
+		dto := &model.CreatePostDTO{AuthorID: 1, Title: "Test Post"}
+		service.On("CreatePost", mock.Anything, dto).Return(&model.PostDetailed{Post: &model.Post{ID: 1}}, nil).Times(3)
+
+		d := NewPostServiceRateLimitDecorator(service, limiter, rules, log, metrics)
+
+		for i := 0; i < rules.CreatePost.Limit; i++ {
+			got, err := d.CreatePost(context.Background(), dto)
+			require.NoError(t, err, "request %d should pass", i+1)
+			assert.NotNil(t, got)
+		}
+
+		got, err := d.CreatePost(context.Background(), dto)
+		assert.Nil(t, got)
+		assert.True(t, errors.Is(err, custom_errors.ErrRateLimitExceeded))
+		var limitErr *model.RateLimitExceededError
+		require.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, time.Minute, limitErr.RetryAfter)
+
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Limits are tracked per author", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		limiter := new(rate_limiter_mock.RateLimiter)
+		countingLimiter(limiter)
+
+		service.On("CreatePost", mock.Anything, mock.AnythingOfType("*model.CreatePostDTO")).Return(&model.PostDetailed{}, nil)
+
+		d := NewPostServiceRateLimitDecorator(service, limiter, rules, log, metrics)
+
+		for i := 0; i < rules.CreatePost.Limit; i++ {
+			_, err := d.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1})
+			require.NoError(t, err)
+		}
+
+		_, err := d.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 2})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fails open when limiter is unavailable", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		limiter := new(rate_limiter_mock.RateLimiter)
+
+		dto := &model.CreatePostDTO{AuthorID: 1}
+		limiter.On("Allow", mock.Anything, "create_post:1", 3, time.Minute).Return(false, time.Duration(0), errors.New("redis: connection refused"))
+		service.On("CreatePost", mock.Anything, dto).Return(&model.PostDetailed{}, nil)
+
+		d := NewPostServiceRateLimitDecorator(service, limiter, rules, log, metrics)
+
+		_, err := d.CreatePost(context.Background(), dto)
+		assert.NoError(t, err)
+		service.AssertExpectations(t)
+		limiter.AssertExpectations(t)
+	})
+}
+
+func TestPostServiceRateLimitDecorator_UpdateAndDelete(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	rules := RateLimitRules{
+		UpdatePost: model.RateLimitRule{Limit: 1, Window: time.Minute},
+		DeletePost: model.RateLimitRule{Limit: 1, Window: time.Minute},
+	}
+
+	service := new(post_service_mock.Service)
+	limiter := new(rate_limiter_mock.RateLimiter)
+	countingLimiter(limiter)
+
+	dto := &model.UpdatePostDTO{UserID: 1}
+	changes := &model.PostChangeSummary{FieldsChanged: []string{"title"}}
+	service.On("UpdatePost", mock.Anything, int64(1), int64(10), dto).Return(&model.PostDetailed{Changes: changes}, nil).Once()
+	service.On("DeletePost", mock.Anything, int64(1), int64(10)).Return(nil).Once()
+
+	d := NewPostServiceRateLimitDecorator(service, limiter, rules, log, metrics)
+
+	updated, err := d.UpdatePost(context.Background(), 1, 10, dto)
+	assert.NoError(t, err)
+	assert.Same(t, changes, updated.Changes, "the change summary is passed through")
+	_, err = d.UpdatePost(context.Background(), 1, 10, dto)
+	assert.ErrorIs(t, err, custom_errors.ErrRateLimitExceeded)
+
+	assert.NoError(t, d.DeletePost(context.Background(), 1, 10))
+	assert.ErrorIs(t, d.DeletePost(context.Background(), 1, 10), custom_errors.ErrRateLimitExceeded)
+
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,60 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	model "pinstack-post-service/internal/domain/models"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// mergeRecentPosts adds to page the posts of filters.RecentPostIDs that the repository missed
+// but that sort into it, and counts each in total. filters must list the requester's own
+// timeline with a limit, so every post of theirs matches it. A recent post that is gone, or
+// that cannot be read, is left out: the page is then what it would have been without it.
+func (s *PostService) mergeRecentPosts(ctx context.Context, filters *model.PostFilters, page []*model.Post, total int) ([]*model.Post, int) {
+	offset := 0
+	if filters.Offset != nil {
+		offset = *filters.Offset
+	}
+	limit := *filters.Limit
+
+	for _, id := range filters.RecentPostIDs {
+		if slices.ContainsFunc(page, func(p *model.Post) bool { return p.ID == id }) {
+			continue
+		}
+		var post *model.Post
+		err := inStage(ctx, s.metrics, "post_list", model.BudgetStageDatabase, func(ctx context.Context) (err error) {
+			post, err = s.postRepo.GetByID(ctx, id)
+			return err
+		})
+		if err != nil {
+			if !errors.Is(err, custom_errors.ErrPostNotFound) {
+				s.log.Warn("Failed to get recent post, listing without it",
+					slog.Int64("post_id", id),
+					slog.String("error", err.Error()))
+			}
+			continue
+		}
+		if post.AuthorID != *filters.AuthorID {
+			continue
+		}
+
+		at, _ := slices.BinarySearchFunc(page, post, filters.ComparePosts)
+		// A post sorting before the first one of a later page belongs to an earlier page, and
+		// one sorting after the last one of a full page to a later page.
+		if (at == 0 && offset > 0) || (at == len(page) && len(page) >= limit) {
+			continue
+		}
+		s.log.Debug("Adding recent post the list missed", slog.Int64("post_id", id))
+		page = slices.Insert(page, at, post)
+		total++
+		if len(page) > limit {
+			page = page[:limit]
+		}
+	}
+	return page, total
+}
@@ -0,0 +1,175 @@
+package post_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	media_memory "pinstack-post-service/internal/infrastructure/outbound/repository/media/memory"
+	post_memory "pinstack-post-service/internal/infrastructure/outbound/repository/post/memory"
+	tag_memory "pinstack-post-service/internal/infrastructure/outbound/repository/tag/memory"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+)
+
+// staleList lists posts as a lagging replica would: the posts in hidden are left out of every
+// list and its total, while GetByID still finds them.
+type staleList struct {
+	*post_memory.PostRepository
+	hidden map[int64]bool
+}
+
+func (s *staleList) List(ctx context.Context, filters model.PostFilters) ([]*model.Post, int, error) {
+	all := filters
+	all.Limit, all.Offset = nil, nil
+	posts, _, err := s.PostRepository.List(ctx, all)
+	if err != nil {
+		return nil, 0, err
+	}
+	var visible []*model.Post
+	for _, post := range posts {
+		if !s.hidden[post.ID] {
+			visible = append(visible, post)
+		}
+	}
+	total := len(visible)
+	if filters.Offset != nil {
+		visible = visible[min(*filters.Offset, len(visible)):]
+	}
+	if filters.Limit != nil {
+		visible = visible[:min(*filters.Limit, len(visible))]
+	}
+	return visible, total, nil
+}
+
+// newStaleListService stores count posts of author 1, one a millisecond, and a post of
+// author 2, behind a list that misses the posts in hidden.
+func newStaleListService(t *testing.T, count int) (*PostService, *staleList) {
+	t.Helper()
+	log := logger.New("test")
+	repo := &staleList{PostRepository: post_memory.NewPostRepository(log), hidden: map[int64]bool{}}
+	for i := 0; i < count; i++ {
+		_, err := repo.Create(context.Background(), &model.Post{AuthorID: 1, Title: "Post"})
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+	}
+	_, err := repo.Create(context.Background(), &model.Post{AuthorID: 2, Title: "Someone else's"})
+	require.NoError(t, err)
+
+	s := NewPostService(repo, tag_memory.NewTagRepository(log), media_memory.NewMediaRepository(log),
+		new(postgres_mock.UnitOfWork), log, &countingUsers{}, prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+	return s, repo
+}
+
+func TestPostService_ListPosts_MergesRecentPosts(t *testing.T) {
+	author, other := int64(1), int64(3)
+	limit, offset := 3, 3
+
+	tests := []struct {
+		name      string
+		filters   model.PostFilters
+		hidden    []int64
+		recent    []int64
+		pin       int64
+		wantIDs   []int64
+		wantTotal int
+	}{
+		{
+			name:      "newest post missed by the list comes first",
+			filters:   model.PostFilters{AuthorID: &author, RequesterID: &author, Limit: &limit},
+			hidden:    []int64{5},
+			recent:    []int64{5},
+			wantIDs:   []int64{5, 4, 3},
+			wantTotal: 5,
+		},
+		{
+			name:      "post already listed is not repeated",
+			filters:   model.PostFilters{AuthorID: &author, RequesterID: &author, Limit: &limit},
+			recent:    []int64{5, 4},
+			wantIDs:   []int64{5, 4, 3},
+			wantTotal: 5,
+		},
+		{
+			name:      "older recent post takes its place in the page",
+			filters:   model.PostFilters{AuthorID: &author, RequesterID: &author, Limit: &limit},
+			hidden:    []int64{4, 5},
+			recent:    []int64{5, 4},
+			wantIDs:   []int64{5, 4, 3},
+			wantTotal: 5,
+		},
+		{
+			name:      "pinned post stays first",
+			filters:   model.PostFilters{AuthorID: &author, RequesterID: &author, Limit: &limit},
+			hidden:    []int64{5},
+			recent:    []int64{5},
+			pin:       1,
+			wantIDs:   []int64{1, 5, 4},
+			wantTotal: 5,
+		},
+		{
+			name:      "ascending order puts it after a page that is not full",
+			filters:   model.PostFilters{AuthorID: &author, RequesterID: &author, Limit: &limit, Offset: &offset, SortOrder: model.SortAsc},
+			hidden:    []int64{5},
+			recent:    []int64{5},
+			wantIDs:   []int64{4, 5},
+			wantTotal: 5,
+		},
+		{
+			name:      "post of an earlier page stays out of a later one",
+			filters:   model.PostFilters{AuthorID: &author, RequesterID: &author, Limit: &limit, Offset: &offset},
+			hidden:    []int64{5},
+			recent:    []int64{5},
+			wantIDs:   []int64{1},
+			wantTotal: 4,
+		},
+		{
+			name:      "another requester sees the list as it is",
+			filters:   model.PostFilters{AuthorID: &author, RequesterID: &other, Limit: &limit},
+			hidden:    []int64{5},
+			recent:    []int64{5},
+			wantIDs:   []int64{4, 3, 2},
+			wantTotal: 4,
+		},
+		{
+			name:      "filtered list is left alone",
+			filters:   model.PostFilters{AuthorID: &author, RequesterID: &author, Limit: &limit, TagNames: []string{"go"}},
+			hidden:    []int64{5},
+			recent:    []int64{5},
+			wantIDs:   []int64{},
+			wantTotal: 0,
+		},
+		{
+			name:      "deleted and foreign posts are skipped",
+			filters:   model.PostFilters{AuthorID: &author, RequesterID: &author, Limit: &limit},
+			recent:    []int64{99, 6},
+			wantIDs:   []int64{5, 4, 3},
+			wantTotal: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newStaleListService(t, 5)
+			for _, id := range tt.hidden {
+				repo.hidden[id] = true
+			}
+			if tt.pin != 0 {
+				_, err := repo.Pin(context.Background(), tt.pin, time.Now())
+				require.NoError(t, err)
+			}
+			filters := tt.filters
+			filters.RecentPostIDs = tt.recent
+
+			posts, total, err := s.ListPosts(context.Background(), &filters)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIDs, listedIDs(posts))
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
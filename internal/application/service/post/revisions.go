@@ -0,0 +1,87 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	revision_repository "pinstack-post-service/internal/domain/ports/output/revision"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// GetPostRevisions returns a page of a post's revisions, newest first, and how many are kept.
+// Only the author may read them. A zero limit means limits.DefaultListLimit.
+func (s *PostService) GetPostRevisions(ctx context.Context, userID int64, postID int64, limit, offset int) (_ []*model.PostRevision, _ int, err error) {
+	defer observePostOperation(s.metrics, "revisions", time.Now(), &err)
+	if limit == 0 {
+		limit = s.limits.DefaultListLimit
+	}
+	if limit < 0 || limit > s.limits.MaxListLimit {
+		return nil, 0, fmt.Errorf("%w: limit must be between 1 and %d, got %d", custom_errors.ErrInvalidInput, s.limits.MaxListLimit, limit)
+	}
+	if offset < 0 {
+		return nil, 0, fmt.Errorf("%w: offset must not be negative, got %d", custom_errors.ErrInvalidInput, offset)
+	}
+
+	if _, err := s.checkOwnership(ctx, "revisions", userID, postID); err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		revisions []*model.PostRevision
+		total     int
+	)
+	err = s.runInTxWithOptions(ctx, "revisions", postgres.TxOptions{ReadOnly: true}, func(ctx context.Context, tx postgres.Transaction) error {
+		var err error
+		revisions, total, err = tx.RevisionRepository().ListByPost(ctx, postID, limit, offset)
+		if err != nil {
+			s.log.Error("Failed to list post revisions", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+			return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return revisions, total, nil
+}
+
+// recordRevision keeps the title and content before had as the post's next revision, sets
+// the post's new edit count on after and prunes the revisions past limits.MaxRevisions. It
+// runs in the transaction of the edit, after the post was locked.
+func (s *PostService) recordRevision(ctx context.Context, revisionRepo revision_repository.Repository, editorID int64, before, after *model.Post) error {
+	revision, err := revisionRepo.Record(ctx, &model.PostRevision{
+		PostID:   before.ID,
+		Title:    before.Title,
+		Content:  before.Content,
+		EditorID: editorID,
+	})
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrPostNotFound) {
+			s.log.Debug("Post not found when recording revision", slog.Int64("id", before.ID))
+			return custom_errors.ErrPostNotFound
+		}
+		s.log.Error("Failed to record post revision", slog.Int64("id", before.ID), slog.String("error", err.Error()))
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	after.EditCount = revision.Revision
+
+	if s.limits.MaxRevisions <= 0 {
+		return nil
+	}
+	pruned, err := revisionRepo.Prune(ctx, before.ID, s.limits.MaxRevisions)
+	if err != nil {
+		s.log.Error("Failed to prune post revisions", slog.Int64("id", before.ID), slog.String("error", err.Error()))
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	if pruned > 0 {
+		s.log.Debug("Pruned post revisions", slog.Int64("id", before.ID), slog.Int("pruned", pruned))
+	}
+	return nil
+}
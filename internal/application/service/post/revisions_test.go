@@ -0,0 +1,124 @@
+package post_service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// newRevisionService stores post 1 by author 1, titled "Title v0" with content "c0", and keeps
+// maxRevisions revisions per post.
+func newRevisionService(t *testing.T, maxRevisions int) *PostService {
+	t.Helper()
+	limits := model.DefaultPostLimits()
+	limits.MaxRevisions = maxRevisions
+	s, _ := newMemoryServiceWith(t, memoryServiceDeps{limits: &limits})
+	content := "c0"
+	_, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Title v0", Content: &content})
+	require.NoError(t, err)
+	return s
+}
+
+func revisionTitles(revisions []*model.PostRevision) []string {
+	titles := make([]string, len(revisions))
+	for i, revision := range revisions {
+		titles[i] = revision.Title
+	}
+	return titles
+}
+
+func TestPostService_UpdatePost_RecordsRevision(t *testing.T) {
+	s := newRevisionService(t, model.DefaultMaxRevisionsPerPost)
+	ctx := context.Background()
+	title, content := "Title v1", "c1"
+
+	updated, err := s.UpdatePost(ctx, 1, 1, &model.UpdatePostDTO{Title: &title, Content: &content})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated.Post.EditCount)
+	revisions, total, err := s.GetPostRevisions(ctx, 1, 1, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, int64(1), revisions[0].Revision)
+	assert.Equal(t, "Title v0", revisions[0].Title, "a revision keeps what the edit replaced")
+	assert.Equal(t, "c0", *revisions[0].Content)
+	assert.Equal(t, int64(1), revisions[0].EditorID)
+
+	got, err := s.GetPostByID(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.True(t, got.Post.Edited())
+}
+
+func TestPostService_UpdatePost_NoRevisionWithoutTextChange(t *testing.T) {
+	sameTitle, sameContent := "Title v0", "c0"
+	visibility := model.PostVisibilityUnlisted
+	tests := []struct {
+		name   string
+		update *model.UpdatePostDTO
+	}{
+		{name: "same title and content", update: &model.UpdatePostDTO{Title: &sameTitle, Content: &sameContent}},
+		{name: "tags only", update: &model.UpdatePostDTO{Tags: []string{"go"}}},
+		{name: "media only", update: &model.UpdatePostDTO{MediaItems: []*model.PostMediaInput{{URL: "https://example.com/a.jpg", Type: model.MediaTypeImage, Position: 1}}}},
+		{name: "visibility only", update: &model.UpdatePostDTO{Visibility: &visibility}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newRevisionService(t, model.DefaultMaxRevisionsPerPost)
+			ctx := context.Background()
+
+			updated, err := s.UpdatePost(ctx, 1, 1, tt.update)
+
+			require.NoError(t, err)
+			assert.Zero(t, updated.Post.EditCount)
+			assert.False(t, updated.Post.Edited())
+			revisions, total, err := s.GetPostRevisions(ctx, 1, 1, 0, 0)
+			require.NoError(t, err)
+			assert.Zero(t, total)
+			assert.Empty(t, revisions)
+		})
+	}
+}
+
+func TestPostService_UpdatePost_PrunesOldestRevisions(t *testing.T) {
+	s := newRevisionService(t, 2)
+	ctx := context.Background()
+
+	for _, title := range []string{"Title v1", "Title v2", "Title v3", "Title v4"} {
+		_, err := s.UpdatePost(ctx, 1, 1, &model.UpdatePostDTO{Title: &title})
+		require.NoError(t, err)
+	}
+
+	revisions, total, err := s.GetPostRevisions(ctx, 1, 1, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []string{"Title v3", "Title v2"}, revisionTitles(revisions), "newest first, older ones pruned")
+	assert.Equal(t, int64(4), revisions[0].Revision, "numbers keep counting past pruned revisions")
+	got, err := s.GetPostByID(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), got.Post.EditCount)
+
+	page, total, err := s.GetPostRevisions(ctx, 1, 1, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []string{"Title v2"}, revisionTitles(page))
+}
+
+func TestPostService_GetPostRevisions_Errors(t *testing.T) {
+	s := newRevisionService(t, model.DefaultMaxRevisionsPerPost)
+	ctx := context.Background()
+
+	_, _, err := s.GetPostRevisions(ctx, 2, 1, 0, 0)
+	assert.ErrorIs(t, err, custom_errors.ErrForbidden, "only the author reads revisions")
+	_, _, err = s.GetPostRevisions(ctx, 1, 99, 0, 0)
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	_, _, err = s.GetPostRevisions(ctx, 1, 1, model.DefaultMaxListLimit+1, 0)
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+	_, _, err = s.GetPostRevisions(ctx, 1, 1, 0, -1)
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+}
@@ -0,0 +1,63 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// scheduleOf returns the status and schedule time a new post is stored with. A post with a
+// ScheduledAt is scheduled, which only makes sense for a time in the future and is
+// incompatible with asking for any other status.
+func (s *PostService) scheduleOf(post *model.CreatePostDTO) (model.PostStatus, pgtype.Timestamptz, error) {
+	if post.ScheduledAt == nil {
+		if post.Status == model.PostStatusScheduled {
+			return "", pgtype.Timestamptz{}, fmt.Errorf("%w: a scheduled post needs scheduled_at", custom_errors.ErrInvalidInput)
+		}
+		return post.Status, pgtype.Timestamptz{}, nil
+	}
+	if post.Status != "" && post.Status != model.PostStatusScheduled {
+		return "", pgtype.Timestamptz{}, fmt.Errorf("%w: a post with scheduled_at cannot be created %s", custom_errors.ErrInvalidInput, post.Status)
+	}
+	if now := s.now(); !post.ScheduledAt.After(now) {
+		return "", pgtype.Timestamptz{}, fmt.Errorf("%w: scheduled_at must be in the future, got %s",
+			custom_errors.ErrInvalidInput, post.ScheduledAt.UTC().Format(time.RFC3339))
+	}
+	return model.PostStatusScheduled, pgtype.Timestamptz{Time: *post.ScheduledAt, Valid: true}, nil
+}
+
+// CancelScheduledPost turns a scheduled post of userID back into a draft. A post that is not
+// scheduled, including one the scheduler published in the meantime, fails with ErrInvalidInput.
+func (s *PostService) CancelScheduledPost(ctx context.Context, userID int64, id int64) (_ *model.PostDetailed, err error) {
+	defer observePostOperation(s.metrics, "cancel_schedule", time.Now(), &err)
+	post, err := s.checkOwnership(ctx, "cancel_schedule", userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != model.PostStatusScheduled {
+		return nil, fmt.Errorf("%w: post %d is not scheduled", custom_errors.ErrInvalidInput, id)
+	}
+
+	if _, err := s.postRepo.CancelSchedule(ctx, id); err != nil {
+		if errors.Is(err, custom_errors.ErrPostNotFound) {
+			s.log.Debug("Post published or deleted before its schedule was cancelled", slog.Int64("id", id))
+			return nil, fmt.Errorf("%w: post %d is not scheduled", custom_errors.ErrInvalidInput, id)
+		}
+		s.log.Error("Failed to cancel scheduled post", slog.String("error", err.Error()), slog.Int64("id", id))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	result, err := s.GetPostByID(ctx, id, &userID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,202 @@
+package post_service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	repository_memory "pinstack-post-service/internal/infrastructure/outbound/repository/memory"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_service_mock "pinstack-post-service/mocks/post"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+)
+
+var scheduleNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newScheduleService returns a service over an in-memory database whose clock reads scheduleNow.
+func newScheduleService(t *testing.T) (*PostService, *repository_memory.Database) {
+	t.Helper()
+	s, database := newMemoryService(t)
+	s.now = func() time.Time { return scheduleNow }
+	return s, database
+}
+
+func schedulePost(t *testing.T, s *PostService, authorID int64, at time.Time) *model.Post {
+	t.Helper()
+	created, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: authorID, Title: "Scheduled", ScheduledAt: &at})
+	require.NoError(t, err)
+	return created.Post
+}
+
+func TestPostService_CreatePost_Schedule(t *testing.T) {
+	past := scheduleNow.Add(-time.Minute)
+	future := scheduleNow.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		status      model.PostStatus
+		scheduledAt *time.Time
+		wantErr     error
+		wantStatus  model.PostStatus
+	}{
+		{name: "future time schedules the post", scheduledAt: &future, wantStatus: model.PostStatusScheduled},
+		{name: "explicit scheduled status", status: model.PostStatusScheduled, scheduledAt: &future, wantStatus: model.PostStatusScheduled},
+		{name: "past time", scheduledAt: &past, wantErr: custom_errors.ErrInvalidInput},
+		{name: "current time", scheduledAt: &scheduleNow, wantErr: custom_errors.ErrInvalidInput},
+		{name: "draft with a schedule", status: model.PostStatusDraft, scheduledAt: &future, wantErr: custom_errors.ErrInvalidInput},
+		{name: "scheduled without a time", status: model.PostStatusScheduled, wantErr: custom_errors.ErrInvalidInput},
+		{name: "no schedule is published", wantStatus: model.PostStatusPublished},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newScheduleService(t)
+
+			created, err := s.CreatePost(context.Background(), &model.CreatePostDTO{
+				AuthorID:    1,
+				Title:       "Scheduled",
+				Status:      tt.status,
+				ScheduledAt: tt.scheduledAt,
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, created.Post.Status)
+			if tt.scheduledAt != nil {
+				assert.True(t, created.Post.ScheduledAt.Time.Equal(*tt.scheduledAt))
+			}
+		})
+	}
+}
+
+func TestPostService_ScheduledPostIsHiddenFromOthers(t *testing.T) {
+	s, _ := newScheduleService(t)
+	post := schedulePost(t, s, 1, scheduleNow.Add(time.Hour))
+	author, other := int64(1), int64(2)
+
+	_, err := s.GetPostByID(context.Background(), post.ID, nil)
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	_, err = s.GetPostByID(context.Background(), post.ID, &other)
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	got, err := s.GetPostByID(context.Background(), post.ID, &author)
+	require.NoError(t, err)
+	assert.Equal(t, model.PostStatusScheduled, got.Post.Status)
+
+	_, total, err := s.ListPosts(context.Background(), &model.PostFilters{})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	_, total, err = s.ListPosts(context.Background(), &model.PostFilters{RequesterID: &author})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+}
+
+func TestPostService_CancelScheduledPost(t *testing.T) {
+	s, _ := newScheduleService(t)
+	post := schedulePost(t, s, 1, scheduleNow.Add(time.Hour))
+
+	_, err := s.CancelScheduledPost(context.Background(), 2, post.ID)
+	assert.ErrorIs(t, err, custom_errors.ErrForbidden)
+
+	draft, err := s.CancelScheduledPost(context.Background(), 1, post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.PostStatusDraft, draft.Post.Status)
+	assert.False(t, draft.Post.ScheduledAt.Valid)
+
+	_, err = s.CancelScheduledPost(context.Background(), 1, post.ID)
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput, "a draft is not scheduled")
+	_, err = s.CancelScheduledPost(context.Background(), 1, post.ID+100)
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+}
+
+func TestPostScheduler_PublishDue(t *testing.T) {
+	s, database := newScheduleService(t)
+	first := schedulePost(t, s, 1, scheduleNow.Add(time.Hour))
+	second := schedulePost(t, s, 2, scheduleNow.Add(2*time.Hour))
+	third := schedulePost(t, s, 3, scheduleNow.Add(3*time.Hour))
+
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+	batcher.On("NewBatch").Return(batch)
+	batch.On("DeletePost", mock.Anything).Return()
+	batch.On("AdjustUserPostCount", mock.Anything, int64(1)).Return()
+	batch.On("Exec", mock.Anything).Return(nil)
+
+	scheduler := NewPostScheduler(database.UnitOfWork, batcher, 1, time.Minute, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	clock := scheduleNow.Add(90 * time.Minute)
+	scheduler.now = func() time.Time { return clock }
+
+	published, err := scheduler.PublishDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	got, err := s.GetPostByID(context.Background(), first.ID, nil)
+	require.NoError(t, err, "a published post is visible to everyone")
+	assert.Equal(t, model.PostStatusPublished, got.Post.Status)
+	assert.True(t, got.Post.PublishedAt.Time.Equal(scheduleNow.Add(time.Hour)), "published at its scheduled time")
+	assert.False(t, got.Post.ScheduledAt.Valid)
+	batch.AssertCalled(t, "DeletePost", first.ID)
+	batch.AssertCalled(t, "AdjustUserPostCount", int64(1), int64(1))
+
+	clock = scheduleNow.Add(4 * time.Hour)
+	published, err = scheduler.PublishDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published, "due posts beyond one batch are published in further batches")
+	for _, post := range []*model.Post{second, third} {
+		_, err := s.GetPostByID(context.Background(), post.ID, nil)
+		assert.NoError(t, err)
+		batch.AssertCalled(t, "DeletePost", post.ID)
+	}
+
+	published, err = scheduler.PublishDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, published)
+	batcher.AssertNumberOfCalls(t, "NewBatch", 3)
+}
+
+func TestPostScheduler_CancelledPostIsNotPublished(t *testing.T) {
+	s, database := newScheduleService(t)
+	post := schedulePost(t, s, 1, scheduleNow.Add(time.Hour))
+	_, err := s.CancelScheduledPost(context.Background(), 1, post.ID)
+	require.NoError(t, err)
+
+	batcher := new(cache_mock.CacheBatcher)
+	scheduler := NewPostScheduler(database.UnitOfWork, batcher, 10, time.Minute, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	scheduler.now = func() time.Time { return scheduleNow.Add(2 * time.Hour) }
+
+	published, err := scheduler.PublishDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, published)
+	batcher.AssertNotCalled(t, "NewBatch")
+}
+
+func TestPostScheduler_PublishDue_FailedBatchIsRolledBack(t *testing.T) {
+	uow := new(postgres_mock.UnitOfWork)
+	tx := new(postgres_mock.Transaction)
+	postRepo := new(post_service_mock.Repository)
+	batcher := new(cache_mock.CacheBatcher)
+	uow.On("Begin", mock.Anything).Return(tx, nil)
+	tx.On("PostRepository").Return(postRepo)
+	tx.On("Rollback", mock.Anything).Return(nil)
+	postRepo.On("PublishDue", mock.Anything, scheduleNow, 10).Return(nil, custom_errors.ErrDatabaseQuery)
+
+	scheduler := NewPostScheduler(uow, batcher, 10, time.Minute, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	scheduler.now = func() time.Time { return scheduleNow }
+
+	published, err := scheduler.PublishDue(context.Background())
+
+	assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Zero(t, published)
+	tx.AssertNumberOfCalls(t, "Rollback", 1)
+	tx.AssertNotCalled(t, "Commit", mock.Anything)
+	batcher.AssertNotCalled(t, "NewBatch")
+}
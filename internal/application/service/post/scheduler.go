@@ -0,0 +1,142 @@
+package post_service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/domain/ports/output/cache"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// PostScheduler periodically publishes scheduled posts whose time has come. Due rows are
+// picked with FOR UPDATE SKIP LOCKED, so every replica can run a scheduler without two of
+// them publishing the same post.
+type PostScheduler struct {
+	uow       postgres.UnitOfWork
+	batcher   cache.CacheBatcher
+	batchSize int
+	interval  time.Duration
+	log       output.Logger
+	metrics   output.MetricsProvider
+	now       func() time.Time
+}
+
+func NewPostScheduler(
+	uow postgres.UnitOfWork,
+	batcher cache.CacheBatcher,
+	batchSize int,
+	interval time.Duration,
+	log output.Logger,
+	metrics output.MetricsProvider,
+) *PostScheduler {
+	return &PostScheduler{
+		uow:       uow,
+		batcher:   batcher,
+		batchSize: batchSize,
+		interval:  interval,
+		log:       log,
+		metrics:   metrics,
+		now:       time.Now,
+	}
+}
+
+// Run publishes once right away and then once per interval until ctx is done. A failed run
+// is logged and retried at the next interval.
+func (s *PostScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PublishDue(ctx); err != nil && ctx.Err() == nil {
+			s.log.Warn("Scheduled publish run failed", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue publishes every post scheduled at or before now, one batch per transaction, and
+// returns how many it published. Batches committed before an error stay published.
+func (s *PostScheduler) PublishDue(ctx context.Context) (int, error) {
+	now := s.now()
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		published, err := s.publishBatch(ctx, now)
+		if err != nil {
+			return total, err
+		}
+		s.metrics.AddScheduledPostsPublished(len(published))
+		total += len(published)
+		s.invalidate(ctx, published)
+
+		if len(published) < s.batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.log.Info("Published scheduled posts", slog.Int("count", total), slog.Time("now", now))
+	}
+	return total, nil
+}
+
+func (s *PostScheduler) publishBatch(ctx context.Context, now time.Time) ([]*model.Post, error) {
+	tx, err := s.uow.Begin(ctx)
+	if err != nil {
+		s.log.Error("Failed to start scheduler transaction", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	var txCommitted bool
+	defer func() {
+		if !txCommitted {
+			rollbackTx(ctx, tx, s.log)
+		}
+	}()
+
+	published, err := tx.PostRepository().PublishDue(ctx, now, s.batchSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := commitTx(ctx, tx, s.log, "Failed to commit scheduler transaction"); err != nil {
+		return nil, err
+	}
+	txCommitted = true
+	return published, nil
+}
+
+// invalidate drops the cached posts, which were cached as hidden from everyone but their
+// author, and counts them in their authors' post counts. A failure only leaves stale entries
+// until they expire, so it is logged and not returned.
+func (s *PostScheduler) invalidate(ctx context.Context, published []*model.Post) {
+	if len(published) == 0 {
+		return
+	}
+	batch := s.batcher.NewBatch()
+	for _, post := range published {
+		batch.DeletePost(post.ID)
+		batch.AdjustUserPostCount(post.AuthorID, 1)
+	}
+
+	cacheStart := time.Now()
+	err := batch.Exec(ctx)
+	s.metrics.RecordCacheOperationDuration("post_delete", time.Since(cacheStart))
+	if err != nil {
+		s.log.Warn("Failed to invalidate cache after scheduled publish",
+			slog.Int("count", len(published)),
+			slog.String("error", err.Error()))
+	}
+}
@@ -11,9 +11,13 @@ import (
 	tag_repository "pinstack-post-service/internal/domain/ports/output/tag"
 	user_client "pinstack-post-service/internal/domain/ports/output/user"
 	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"golang.org/x/sync/errgroup"
 )
 
 type PostService struct {
@@ -24,6 +28,9 @@ type PostService struct {
 	log        output.Logger
 	userClient user_client.Client
 	metrics    output.MetricsProvider
+	limits     model.PostLimits
+	snapshots  *AuthorSnapshots
+	now        func() time.Time
 }
 
 func NewPostService(
@@ -34,6 +41,7 @@ func NewPostService(
 	log output.Logger,
 	userClient user_client.Client,
 	metrics output.MetricsProvider,
+	limits model.PostLimits,
 ) *PostService {
 	return &PostService{
 		postRepo:   postRepo,
@@ -43,186 +51,307 @@ func NewPostService(
 		log:        log,
 		userClient: userClient,
 		metrics:    metrics,
+		limits:     limits,
+		now:        time.Now,
 	}
 }
 
 func (s *PostService) CreatePost(ctx context.Context, post *model.CreatePostDTO) (result *model.PostDetailed, err error) {
-	author, err := s.userClient.GetUser(ctx, post.AuthorID)
+	defer observePostOperation(s.metrics, "create", time.Now(), &err)
+	post = s.normalizeCreate(post)
+	if err := s.limits.ValidateCreate(post); err != nil {
+		s.log.Debug("Post validation failed", slog.String("error", err.Error()))
+		return nil, err
+	}
+	status, scheduledAt, err := s.scheduleOf(post)
 	if err != nil {
-		s.metrics.IncrementPostOperations("create", false)
-		s.log.Error("Failed to get author from user service", slog.String("error", err.Error()))
-		return nil, custom_errors.ErrExternalServiceError
+		s.log.Debug("Post schedule rejected", slog.String("error", err.Error()))
+		return nil, err
 	}
 
-	tx, err := s.uow.Begin(ctx)
-	if err != nil {
-		s.log.Error("Failed to start transaction", slog.String("error", err.Error()))
-		return nil, custom_errors.ErrDatabaseQuery
-	}
-
-	var txCommitted bool
-	defer func() {
-		if !txCommitted && tx != nil {
-			rollbackErr := tx.Rollback(ctx)
-			if rollbackErr != nil {
-				if !strings.Contains(rollbackErr.Error(), "tx is closed") && !strings.Contains(rollbackErr.Error(), "commit unexpectedly resulted in rollback") {
-					s.log.Error("Failed to rollback transaction", slog.String("error", rollbackErr.Error()))
-				} else {
-					s.log.Debug("Transaction already closed during rollback", slog.String("error", rollbackErr.Error()))
-				}
+	author := post.VerifiedAuthor
+	if author == nil || author.ID != post.AuthorID {
+		err = inStage(ctx, s.metrics, "post_create", model.BudgetStageUserService, func(ctx context.Context) (err error) {
+			author, err = s.userClient.GetUser(ctx, post.AuthorID)
+			return err
+		})
+		if err != nil {
+			if model.IsCallerError(err) {
+				s.log.Warn("Ran out of time getting author", slog.Int64("authorID", post.AuthorID), slog.String("error", err.Error()))
+				return nil, err
 			}
+			s.log.Error("Failed to get author from user service", slog.String("error", err.Error()))
+			return nil, custom_errors.ErrExternalServiceError
 		}
-	}()
+	}
 
-	postRepo := tx.PostRepository()
-	mediaRepo := tx.MediaRepository()
-	tagRepo := tx.TagRepository()
+	var (
+		createdPost  *model.Post
+		createdTags  []*model.Tag
+		failedTags   []string
+		createdMedia []*model.PostMedia
+	)
+	err = s.runInTx(ctx, "create", func(ctx context.Context, tx postgres.Transaction) error {
+		createdTags = make([]*model.Tag, 0, len(post.Tags))
+		createdMedia = make([]*model.PostMedia, 0, len(post.MediaItems))
+
+		newPost := &model.Post{
+			AuthorID:    post.AuthorID,
+			Title:       post.Title,
+			Content:     post.Content,
+			Status:      status,
+			Visibility:  post.Visibility,
+			ScheduledAt: scheduledAt,
+		}
+		if post.Language != "" {
+			newPost.Language = &post.Language
+		}
+		newPost.Source = post.Source
+		newPost.SetAuthorSnapshot(author, s.now())
+		var err error
+		createdPost, err = tx.PostRepository().Create(ctx, newPost)
+		if err != nil {
+			if errors.Is(err, custom_errors.ErrDatabaseQuery) {
+				s.log.Error("Database error in create post", slog.String("error", err.Error()))
+				return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+			}
+			s.log.Error("Failed to create post", slog.String("error", err.Error()))
+			return err
+		}
 
-	createdTags := make([]*model.Tag, 0, len(post.Tags))
-	createdMedia := make([]*model.PostMedia, 0, len(post.MediaItems))
+		if len(post.MediaItems) > 0 {
+			mediaRepo := tx.MediaRepository()
+			err = mediaRepo.Attach(ctx, createdPost.ID, newPostMedia(createdPost.ID, post.MediaItems))
+			if err != nil {
+				if errors.Is(err, model.ErrMediaDuplicate) {
+					s.log.Debug("Duplicate media in create post", slog.String("error", err.Error()))
+					return model.ErrMediaDuplicate
+				}
+				s.log.Error("Failed to attach media to post", slog.String("error", err.Error()))
+				return db.WithCause(custom_errors.ErrMediaAttachFailed, err)
+			}
+			createdMedia, err = mediaRepo.GetByPost(ctx, createdPost.ID)
+			if err != nil {
+				s.log.Error("Failed to get media by post", slog.String("error", err.Error()))
+				return db.WithCause(custom_errors.ErrMediaQueryFailed, err)
+			}
+		}
 
-	newPost := &model.Post{
-		AuthorID: post.AuthorID,
-		Title:    post.Title,
-		Content:  post.Content,
-	}
-	createdPost, err := postRepo.Create(ctx, newPost)
-	if err != nil {
-		s.metrics.IncrementPostOperations("create", false)
-		if errors.Is(err, custom_errors.ErrDatabaseQuery) {
-			s.log.Error("Database error in create post", slog.String("error", err.Error()))
-			return nil, custom_errors.ErrDatabaseQuery
+		if len(post.Tags) > 0 {
+			createdTags, failedTags, err = s.attachTags(ctx, tx.TagRepository(), createdPost.ID, post.Tags)
+			if err != nil {
+				return err
+			}
 		}
-		s.log.Error("Failed to create post", slog.String("error", err.Error()))
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	if len(post.MediaItems) > 0 {
-		media := make([]*model.PostMedia, 0, len(post.MediaItems))
-		for _, m := range post.MediaItems {
-			media = append(media, &model.PostMedia{
-				PostID:   createdPost.ID,
-				URL:      m.URL,
-				Type:     m.Type,
-				Position: m.Position,
-			})
-		}
-		err = mediaRepo.Attach(ctx, createdPost.ID, media)
-		if err != nil {
-			s.metrics.IncrementPostOperations("create", false)
-			s.log.Error("Failed to attach media to post", slog.String("error", err.Error()))
-			return nil, custom_errors.ErrMediaAttachFailed
-		}
-		createdMedia, err = mediaRepo.GetByPost(ctx, createdPost.ID)
-		if err != nil {
-			s.metrics.IncrementPostOperations("create", false)
-			s.log.Error("Failed to get media by post", slog.String("error", err.Error()))
-			return nil, custom_errors.ErrMediaQueryFailed
+	postDetailed := &model.PostDetailed{
+		Post:       createdPost,
+		Author:     author,
+		Media:      createdMedia,
+		Tags:       createdTags,
+		FailedTags: failedTags,
+	}
+	postDetailed.CapMedia(s.limits.MaxEmbeddedMedia)
+	return postDetailed, nil
+}
+
+// normalizeCreate returns a copy of post as it is stored. Tags are stored normalized (see
+// model.NormalizeTags): "Go", " go " and "GO" are one tag. Media URLs too, so the duplicate
+// checks compare canonical URLs, and the language tag, so the allowlist and the language
+// filter see one spelling. A source off the allowlist is recorded as unknown.
+func (s *PostService) normalizeCreate(post *model.CreatePostDTO) *model.CreatePostDTO {
+	normalized := *post
+	// A blank title is no title.
+	if strings.TrimSpace(post.Title) == "" {
+		normalized.Title = ""
+	}
+	normalized.Tags = model.NormalizeTags(post.Tags)
+	normalized.MediaItems = model.NormalizeMedia(post.MediaItems)
+	if post.Language != "" {
+		normalized.Language = model.NormalizeLanguage(post.Language)
+	}
+	normalized.Source = s.limits.Sources.Resolve(string(post.Source))
+	return &normalized
+}
+
+func newPostMedia(postID int64, items []*model.PostMediaInput) []*model.PostMedia {
+	media := make([]*model.PostMedia, 0, len(items))
+	for _, m := range items {
+		media = append(media, &model.PostMedia{
+			PostID:    postID,
+			URL:       m.URL,
+			Type:      m.Type,
+			Position:  m.Position,
+			Width:     m.Width,
+			Height:    m.Height,
+			SizeBytes: m.SizeBytes,
+			AltText:   m.AltText,
+		})
+	}
+	return media
+}
+
+// attachTags creates the missing tags among names and tags postID with them, best effort: a
+// tag whose row vanishes before it is attached (a concurrent DeleteUnused, say) is created
+// and attached once more, and if that fails too its name is returned in failed instead of
+// aborting the post. Database errors still abort.
+func (s *PostService) attachTags(ctx context.Context, tagRepo tag_repository.Repository, postID int64, names []string) (attached []*model.Tag, failed []string, err error) {
+	existingTags, err := tagRepo.FindByNames(ctx, names)
+	if err != nil {
+		s.log.Error("Failed to find existing tags", slog.String("error", err.Error()))
+		return nil, nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	tags := make([]*model.Tag, 0, len(names))
+	found := make(map[string]bool, len(existingTags))
+	for _, tag := range existingTags {
+		found[tag.Name] = true
+		tags = append(tags, tag)
+	}
+	missing := make([]string, 0)
+	for _, name := range names {
+		if !found[name] {
+			missing = append(missing, name)
 		}
 	}
+	created, err := s.createTags(ctx, tagRepo, missing)
+	if err != nil {
+		return nil, nil, err
+	}
+	tags = append(tags, created...)
 
-	if len(post.Tags) > 0 {
-		existingTags, err := tagRepo.FindByNames(ctx, post.Tags)
+	notTagged, err := s.tagPostExisting(ctx, tagRepo, postID, names)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(notTagged) > 0 {
+		s.log.Debug("Tags vanished before tagging, retrying once", slog.Int64("post_id", postID), slog.Any("tags", notTagged))
+		recreated, err := s.createTags(ctx, tagRepo, notTagged)
 		if err != nil {
-			s.metrics.IncrementPostOperations("create", false)
-			s.log.Error("Failed to find existing tags", slog.String("error", err.Error()))
-			return nil, custom_errors.ErrTagQueryFailed
-		}
-		existingTagNames := make(map[string]*model.Tag)
-		for _, tag := range existingTags {
-			existingTagNames[tag.Name] = tag
-			createdTags = append(createdTags, tag)
-		}
-		missingTags := make([]string, 0)
-		for _, name := range post.Tags {
-			if _, found := existingTagNames[name]; !found {
-				missingTags = append(missingTags, name)
-			}
+			return nil, nil, err
 		}
-
-		for _, name := range missingTags {
-			createdTag, tagErr := tagRepo.Create(ctx, name)
-			if tagErr != nil {
-				s.metrics.IncrementPostOperations("create", false)
-				if errors.Is(tagErr, custom_errors.ErrTagCreateFailed) {
-					s.log.Error("Failed to create tag", slog.String("error", tagErr.Error()))
-					return nil, custom_errors.ErrTagCreateFailed
-				}
-				s.log.Error("Unknown error while creating tag", slog.String("error", tagErr.Error()))
-				return nil, custom_errors.ErrUnknownTagError
+		if failed, err = s.tagPostExisting(ctx, tagRepo, postID, notTagged); err != nil {
+			return nil, nil, err
+		}
+		byName := make(map[string]*model.Tag, len(recreated))
+		for _, tag := range recreated {
+			byName[tag.Name] = tag
+		}
+		for i, tag := range tags {
+			if fresh, ok := byName[tag.Name]; ok {
+				tags[i] = fresh
 			}
-			createdTags = append(createdTags, createdTag)
 		}
+	}
+	if len(failed) == 0 {
+		return tags, nil, nil
+	}
 
-		tagErr := tagRepo.TagPost(ctx, createdPost.ID, post.Tags)
-		if tagErr != nil {
-			s.metrics.IncrementPostOperations("create", false)
-			if errors.Is(tagErr, custom_errors.ErrPostNotFound) {
-				s.log.Debug("Post not found when adding tags", slog.String("error", tagErr.Error()))
-				return nil, custom_errors.ErrPostNotFound
-			}
-			if errors.Is(tagErr, custom_errors.ErrTagNotFound) {
-				s.log.Debug("Tag not found when adding to post", slog.String("error", tagErr.Error()))
-				return nil, custom_errors.ErrTagNotFound
-			}
-			if errors.Is(tagErr, custom_errors.ErrTagVerifyPostFailed) {
-				s.log.Error("Tag verification failed when adding tags to post", slog.String("error", tagErr.Error()))
-				return nil, custom_errors.ErrTagVerifyPostFailed
-			}
-			if errors.Is(tagErr, custom_errors.ErrTagPost) {
-				s.log.Error("Failed to add tags to post", slog.String("error", tagErr.Error()))
-				return nil, custom_errors.ErrTagPost
-			}
-			s.log.Error("Unknown error while adding tags to post", slog.String("error", tagErr.Error()))
-			return nil, custom_errors.ErrUnknownTagError
+	s.log.Warn("Created post without some of its tags", slog.Int64("post_id", postID), slog.Any("failed_tags", failed))
+	attached = make([]*model.Tag, 0, len(tags))
+	for _, tag := range tags {
+		if !slices.Contains(failed, tag.Name) {
+			attached = append(attached, tag)
 		}
 	}
+	return attached, failed, nil
+}
 
-	err = tx.Commit(ctx)
+// createTags creates names in one repository call, however many there are, and returns
+// their rows. No names make no call.
+func (s *PostService) createTags(ctx context.Context, tagRepo tag_repository.Repository, names []string) ([]*model.Tag, error) {
+	if len(names) == 0 {
+		return []*model.Tag{}, nil
+	}
+	created, err := tagRepo.CreateMany(ctx, names)
 	if err != nil {
-		s.metrics.IncrementPostOperations("create", false)
-		if strings.Contains(err.Error(), "commit unexpectedly resulted in rollback") {
-			s.log.Warn("Transaction commit resulted in rollback", slog.String("error", err.Error()))
-			return nil, custom_errors.ErrDatabaseQuery
+		if errors.Is(err, custom_errors.ErrTagCreateFailed) {
+			s.log.Error("Failed to create tags", slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrTagCreateFailed, err)
 		}
-		s.log.Error("Failed to commit transaction", slog.String("error", err.Error()))
-		return nil, custom_errors.ErrDatabaseQuery
+		s.log.Error("Unknown error while creating tags", slog.String("error", err.Error()))
+		return nil, custom_errors.ErrUnknownTagError
 	}
-	txCommitted = true
+	return created, nil
+}
 
-	postDetailed := &model.PostDetailed{
-		Post:   createdPost,
-		Author: author,
-		Media:  createdMedia,
-		Tags:   createdTags,
+func (s *PostService) tagPostExisting(ctx context.Context, tagRepo tag_repository.Repository, postID int64, names []string) ([]string, error) {
+	missing, err := tagRepo.TagPostExisting(ctx, postID, names)
+	if err != nil {
+		switch {
+		case errors.Is(err, custom_errors.ErrPostNotFound):
+			s.log.Debug("Post not found when adding tags", slog.String("error", err.Error()))
+			return nil, custom_errors.ErrPostNotFound
+		case errors.Is(err, custom_errors.ErrTagVerifyPostFailed):
+			s.log.Error("Tag verification failed when adding tags to post", slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrTagVerifyPostFailed, err)
+		case errors.Is(err, custom_errors.ErrTagPost):
+			s.log.Error("Failed to add tags to post", slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrTagPost, err)
+		default:
+			s.log.Error("Unknown error while adding tags to post", slog.String("error", err.Error()))
+			return nil, custom_errors.ErrUnknownTagError
+		}
 	}
-	s.metrics.IncrementPostOperations("create", true)
-	return postDetailed, nil
+	return missing, nil
 }
 
-func (s *PostService) GetPostByID(ctx context.Context, id int64) (*model.PostDetailed, error) {
-	post, err := s.postRepo.GetByID(ctx, id)
+func (s *PostService) GetPostByID(ctx context.Context, id int64, requesterID *int64) (_ *model.PostDetailed, err error) {
+	defer observePostOperation(s.metrics, "get", time.Now(), &err)
+	// The post, its media and its tags come back in one round trip; only the author is
+	// fetched separately. Each has its own share of the request budget.
+	var postDetailed *model.PostDetailed
+	err = inStage(ctx, s.metrics, "post_get", model.BudgetStageDatabase, func(ctx context.Context) (err error) {
+		postDetailed, err = s.postRepo.GetDetailedByID(ctx, id)
+		return err
+	})
 	if err != nil {
-		s.metrics.IncrementPostOperations("get", false)
 		switch {
 		case errors.Is(err, custom_errors.ErrPostNotFound):
 			s.log.Debug("Post not found", slog.Int64("id", id))
 			return nil, custom_errors.ErrPostNotFound
+		case model.IsCallerError(err):
+			s.log.Warn("Ran out of time getting post", slog.Int64("id", id), slog.String("error", err.Error()))
+			return nil, err
 		default:
+			// The repository already tells a failed post, media or tag query apart.
 			s.log.Error("Failed to get post by id",
 				slog.String("error", err.Error()),
 				slog.Int64("id", id))
-			return nil, custom_errors.ErrDatabaseQuery
+			return nil, err
 		}
 	}
+	postDetailed.CapMedia(s.limits.MaxEmbeddedMedia)
+	post := postDetailed.Post
+	if err := post.CheckAccess(requesterID); err != nil {
+		s.log.Debug("Post is hidden from requester", slog.Int64("id", id), slog.Any("requesterID", requesterID), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if author := s.snapshotAuthor(post); author != nil {
+		postDetailed.Author = author
+		postDetailed.AuthorFromSnapshot = true
+		return postDetailed.RedactedFor(requesterID), nil
+	}
 
-	author, err := s.userClient.GetUser(ctx, post.AuthorID)
+	var author *model.User
+	err = inStage(ctx, s.metrics, "post_get", model.BudgetStageUserService, func(ctx context.Context) (err error) {
+		author, err = s.userClient.GetUser(ctx, post.AuthorID)
+		return err
+	})
 	if err != nil {
-		s.metrics.IncrementPostOperations("get", false)
 		switch {
 		case errors.Is(err, custom_errors.ErrUserNotFound):
-			s.log.Debug("Author not found", slog.Int64("authorID", post.AuthorID))
-			return nil, custom_errors.ErrUserNotFound
+			// The author account was deleted; the post itself is still readable.
+			s.log.Debug("Author not found, returning post without author", slog.Int64("authorID", post.AuthorID))
+			author = nil
+		case model.IsCallerError(err):
+			s.log.Warn("Ran out of time getting author", slog.Int64("authorID", post.AuthorID), slog.String("error", err.Error()))
+			return nil, err
 		default:
 			s.log.Error("Failed to get author",
 				slog.String("error", err.Error()),
@@ -231,319 +360,455 @@ func (s *PostService) GetPostByID(ctx context.Context, id int64) (*model.PostDet
 		}
 	}
 
-	var media []*model.PostMedia
-	mediaResult, err := s.mediaRepo.GetByPost(ctx, id)
-	if err != nil {
-		if errors.Is(err, custom_errors.ErrMediaNotFound) {
-			s.log.Debug("Media not found for post", slog.Int64("id", id))
-			media = []*model.PostMedia{}
-		} else {
-			s.log.Error("Failed to get media by post",
-				slog.String("error", err.Error()),
-				slog.Int64("id", id))
-			return nil, custom_errors.ErrMediaQueryFailed
+	postDetailed.Author = author
+	return postDetailed.RedactedFor(requesterID), nil
+}
+
+func (s *PostService) ListPosts(ctx context.Context, filters *model.PostFilters) (_ []*model.PostDetailed, _ int, err error) {
+	defer observePostOperation(s.metrics, "list", time.Now(), &err)
+	// Tag names are canonicalized as they are stored, so "  GoLang " finds posts tagged
+	// "golang"; a name that normalizes to nothing fails validation.
+	normalized := *filters
+	normalized.TagNames = model.NormalizeTags(filters.TagNames)
+	if filters.Language != nil {
+		language := model.NormalizeLanguage(*filters.Language)
+		normalized.Language = &language
+	}
+	if filters.Source != nil {
+		if source, err := model.ParsePostSource(string(*filters.Source)); err == nil {
+			normalized.Source = &source
 		}
-	} else {
-		media = mediaResult
+	}
+	filters = &normalized
+	if err := s.limits.ValidateFilters(filters); err != nil {
+		s.log.Debug("Invalid list filters", slog.String("error", err.Error()))
+		return nil, 0, err
 	}
 
-	var tags []*model.Tag
-	tagsResult, err := s.tagRepo.FindByPost(ctx, id)
+	bounded := *filters
+	if bounded.Limit == nil {
+		limit := s.limits.DefaultListLimit
+		bounded.Limit = &limit
+	}
+	if bounded.RequesterID != nil && !bounded.ListsOwnPosts() {
+		bounded.ExcludedAuthorIDs = append(slices.Clip(bounded.ExcludedAuthorIDs), s.blockedAuthors(ctx, *bounded.RequesterID)...)
+	}
+
+	var (
+		posts []*model.Post
+		total int
+	)
+	err = inStage(ctx, s.metrics, "post_list", model.BudgetStageDatabase, func(ctx context.Context) (err error) {
+		posts, total, err = s.postRepo.List(ctx, bounded)
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, custom_errors.ErrTagsNotFound) {
-			s.log.Debug("Tags not found for post", slog.Int64("id", id))
-			tags = []*model.Tag{}
-		} else {
-			s.log.Error("Failed to find tags by post",
-				slog.String("error", err.Error()),
-				slog.Int64("id", id))
-			return nil, custom_errors.ErrTagQueryFailed
-		}
-	} else {
-		tags = tagsResult
+		s.log.Error("Failed to list posts", slog.String("error", err.Error()))
+		return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	if len(bounded.RecentPostIDs) > 0 && bounded.ListsOwnTimeline() {
+		posts, total = s.mergeRecentPosts(ctx, &bounded, posts, total)
 	}
 
-	postDetailed := &model.PostDetailed{
-		Post:   post,
-		Author: author,
-		Media:  media,
-		Tags:   tags,
+	result, err := s.hydratePosts(ctx, posts)
+	if err != nil {
+		return nil, 0, err
 	}
-	s.metrics.IncrementPostOperations("get", true)
-	return postDetailed, nil
+	if filters.View == model.PostViewSummary {
+		for i, post := range result {
+			result[i] = post.Summary(s.limits.ExcerptLength)
+		}
+	}
+	return result, total, nil
 }
 
-func (s *PostService) ListPosts(ctx context.Context, filters *model.PostFilters) ([]*model.PostDetailed, int, error) {
-	posts, total, err := s.postRepo.List(ctx, *filters)
+// hydratePosts fetches the media, tags and author of each post, keeping the order of posts,
+// and caps the media of each (see model.PostDetailed.CapMedia).
+// Up to limits.HydrationConcurrency posts are fetched at once, then the authors of the posts
+// without a usable snapshot (see snapshotAuthor) as fetchAuthors does. The first hard error cancels the remaining fetches and is returned.
+// The media and tags share the database's part of the request budget, the authors the user service's.
+func (s *PostService) hydratePosts(ctx context.Context, posts []*model.Post) ([]*model.PostDetailed, error) {
+	result := make([]*model.PostDetailed, len(posts))
+	err := inStage(ctx, s.metrics, "post_list", model.BudgetStageDatabase, func(ctx context.Context) error {
+		return s.fetchMediaAndTags(ctx, posts, result)
+	})
 	if err != nil {
-		s.metrics.IncrementPostOperations("list", false)
-		s.log.Error("Failed to list posts", slog.String("error", err.Error()))
-		return nil, 0, custom_errors.ErrDatabaseQuery
+		return nil, err
 	}
 
-	result := make([]*model.PostDetailed, 0, len(posts))
-	for _, post := range posts {
-		media, err := s.mediaRepo.GetByPost(ctx, post.ID)
-		if err != nil {
-			switch {
-			case errors.Is(err, custom_errors.ErrMediaNotFound):
-				s.log.Debug("Media not found for post", slog.Int64("id", post.ID))
-				media = nil
-			default:
-				s.metrics.IncrementPostOperations("list", false)
-				s.log.Error("Failed to get media by post", slog.String("error", err.Error()), slog.Int64("id", post.ID))
-				return nil, 0, custom_errors.ErrDatabaseQuery
-			}
+	var unsnapshotted []*model.Post
+	for _, postDetailed := range result {
+		if author := s.snapshotAuthor(postDetailed.Post); author != nil {
+			postDetailed.Author = author
+			postDetailed.AuthorFromSnapshot = true
+		} else {
+			unsnapshotted = append(unsnapshotted, postDetailed.Post)
+		}
+	}
+	var authors map[int64]*model.User
+	err = inStage(ctx, s.metrics, "post_list", model.BudgetStageUserService, func(ctx context.Context) (err error) {
+		authors, err = s.fetchAuthors(ctx, unsnapshotted)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, postDetailed := range result {
+		if !postDetailed.AuthorFromSnapshot {
+			postDetailed.Author = authors[postDetailed.Post.AuthorID]
 		}
+	}
+	return result, nil
+}
 
-		tags, err := s.tagRepo.FindByPost(ctx, post.ID)
-		if err != nil {
-			switch {
-			case errors.Is(err, custom_errors.ErrTagsNotFound):
-				s.log.Debug("Tags not found for post", slog.Int64("id", post.ID))
-				tags = nil
-			default:
-				s.metrics.IncrementPostOperations("list", false)
-				s.log.Error("Failed to find tags by post", slog.String("error", err.Error()), slog.Int64("id", post.ID))
-				return nil, 0, custom_errors.ErrDatabaseQuery
+// fetchMediaAndTags fills result[i] with posts[i] and its media and tags.
+func (s *PostService) fetchMediaAndTags(ctx context.Context, posts []*model.Post, result []*model.PostDetailed) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(s.limits.HydrationConcurrency, 1))
+	for i, post := range posts {
+		g.Go(func() error {
+			media, err := s.mediaRepo.GetByPost(gctx, post.ID)
+			if err != nil {
+				s.log.Error("Failed to get media by post", slog.String("error", err.Error()), slog.Int64("id", post.ID))
+				return db.WithCause(custom_errors.ErrDatabaseQuery, err)
 			}
-		}
 
-		author, err := s.userClient.GetUser(ctx, post.AuthorID)
-		if err != nil {
-			switch {
-			case errors.Is(err, custom_errors.ErrUserNotFound):
-				s.metrics.IncrementPostOperations("list", false)
-				s.log.Debug("Author not found", slog.Int64("authorID", post.AuthorID), slog.Any("post", post))
-				return nil, 0, custom_errors.ErrUserNotFound
-			default:
-				s.metrics.IncrementPostOperations("list", false)
-				s.log.Error("Failed to get author", slog.String("error", err.Error()), slog.Int64("authorID", post.AuthorID))
-				return nil, 0, custom_errors.ErrDatabaseQuery
+			tags, err := s.tagRepo.FindByPost(gctx, post.ID)
+			if err != nil {
+				switch {
+				case errors.Is(err, custom_errors.ErrTagsNotFound):
+					s.log.Debug("Tags not found for post", slog.Int64("id", post.ID))
+					tags = nil
+				default:
+					s.log.Error("Failed to find tags by post", slog.String("error", err.Error()), slog.Int64("id", post.ID))
+					return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+				}
 			}
-		}
 
-		postDetailed := &model.PostDetailed{
-			Post:   post,
-			Author: author,
-			Media:  media,
-			Tags:   tags,
-		}
-		result = append(result, postDetailed)
+			result[i] = &model.PostDetailed{Post: post, Media: media, Tags: tags}
+			result[i].CapMedia(s.limits.MaxEmbeddedMedia)
+			return nil
+		})
 	}
-	s.metrics.IncrementPostOperations("list", true)
-	return result, total, nil
+	return g.Wait()
 }
 
-func (s *PostService) UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) (err error) {
-	tx, err := s.uow.Begin(ctx)
-	if err != nil {
-		s.metrics.IncrementPostOperations("update", false)
-		s.log.Error("Failed to start transaction", slog.String("error", err.Error()))
-		return custom_errors.ErrDatabaseQuery
-	}
-
-	var txCommitted bool
-	defer func() {
-		if !txCommitted && tx != nil {
-			rollbackErr := tx.Rollback(ctx)
-			if rollbackErr != nil {
-				if !strings.Contains(rollbackErr.Error(), "tx is closed") && !strings.Contains(rollbackErr.Error(), "commit unexpectedly resulted in rollback") {
-					s.log.Error("Failed to rollback transaction", slog.String("error", rollbackErr.Error()))
-				} else {
-					s.log.Debug("Transaction already closed during rollback", slog.String("error", rollbackErr.Error()))
+// fetchAuthors fetches each distinct author of posts once, up to
+// limits.HydrationConcurrency at a time: a feed page usually holds several posts per author.
+// An author the user service does not know is left out, so their posts list without one;
+// any other error cancels the remaining fetches and is returned.
+//
+// Every post by an author is given the same *model.User, so a caller must treat the authors
+// of a list as read-only.
+func (s *PostService) fetchAuthors(ctx context.Context, posts []*model.Post) (map[int64]*model.User, error) {
+	var authorIDs []int64
+	seen := make(map[int64]bool)
+	for _, post := range posts {
+		if !seen[post.AuthorID] {
+			seen[post.AuthorID] = true
+			authorIDs = append(authorIDs, post.AuthorID)
+		}
+	}
+	fetched := make([]*model.User, len(authorIDs))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(s.limits.HydrationConcurrency, 1))
+	for i, authorID := range authorIDs {
+		g.Go(func() error {
+			author, err := s.userClient.GetUser(gctx, authorID)
+			if err != nil {
+				switch {
+				case errors.Is(err, custom_errors.ErrUserNotFound):
+					s.log.Debug("Author not found, listing posts without author", slog.Int64("authorID", authorID))
+					return nil
+				default:
+					s.log.Error("Failed to get author", slog.String("error", err.Error()), slog.Int64("authorID", authorID))
+					return custom_errors.ErrExternalServiceError
 				}
 			}
+			fetched[i] = author
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	authors := make(map[int64]*model.User, len(authorIDs))
+	for i, authorID := range authorIDs {
+		if fetched[i] != nil {
+			authors[authorID] = fetched[i]
 		}
-	}()
+	}
+	return authors, nil
+}
 
-	postRepo := tx.PostRepository()
-	mediaRepo := tx.MediaRepository()
-	tagRepo := tx.TagRepository()
+func (s *PostService) UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) (result *model.PostDetailed, err error) {
+	defer observePostOperation(s.metrics, "update", time.Now(), &err)
+	normalized := *post
+	// A blank title clears the title, as it means no title on create.
+	if post.Title != nil && strings.TrimSpace(*post.Title) == "" {
+		normalized.Title = new(string)
+	}
+	normalized.Tags = model.NormalizeTags(post.Tags)
+	normalized.MediaItems = model.NormalizeMedia(post.MediaItems)
+	if post.Language != nil && *post.Language != "" {
+		language := model.NormalizeLanguage(*post.Language)
+		normalized.Language = &language
+	}
+	post = &normalized
 
-	existingPost, err := postRepo.GetByID(ctx, id)
-	if err != nil {
-		if errors.Is(err, custom_errors.ErrPostNotFound) {
-			s.metrics.IncrementPostOperations("update", false)
-			s.log.Debug("Post not found for update", slog.Int64("id", id))
-			return custom_errors.ErrPostNotFound
-		}
-		s.metrics.IncrementPostOperations("update", false)
-		s.log.Error("Failed to get post for update", slog.String("error", err.Error()), slog.Int64("id", id))
-		return custom_errors.ErrDatabaseQuery
+	if err = s.limits.ValidateUpdate(post); err != nil {
+		s.log.Debug("Post update validation failed", slog.Int64("post_id", id), slog.String("error", err.Error()))
+		return nil, err
 	}
-	if existingPost.AuthorID != userID {
-		s.metrics.IncrementPostOperations("update", false)
-		s.log.Debug("User is not author of post", slog.Int64("userID", userID), slog.Int64("authorID", existingPost.AuthorID))
-		return custom_errors.ErrInvalidInput
+	// Refused before any transaction: an update of nothing must not bump updated_at.
+	if post.IsEmpty() {
+		s.log.Debug("Post update provides nothing to change", slog.Int64("post_id", id))
+		return nil, model.ErrEmptyUpdate
 	}
 
-	_, err = postRepo.Update(ctx, id, post)
-	if err != nil {
-		if errors.Is(err, custom_errors.ErrPostNotFound) {
-			s.metrics.IncrementPostOperations("update", false)
-			s.log.Debug("Post not found for update", slog.Int64("id", id))
-			return custom_errors.ErrPostNotFound
-		}
-		s.metrics.IncrementPostOperations("update", false)
-		s.log.Error("Failed to update post", slog.String("error", err.Error()), slog.Int64("id", id))
-		return custom_errors.ErrDatabaseQuery
+	if _, err = s.checkOwnership(ctx, "update", userID, id); err != nil {
+		return nil, err
 	}
 
-	if len(post.MediaItems) > 0 {
-		media, err := mediaRepo.GetByPost(ctx, id)
+	// Tag replacement reads the current tags and rewrites them; repeatable read keeps that
+	// read consistent with the write for the whole transaction.
+	var (
+		updatedPost  *model.Post
+		updatedMedia []*model.PostMedia
+		updatedTags  []*model.Tag
+		changes      *model.PostChangeSummary
+	)
+	err = s.runInTxWithOptions(ctx, "update", postgres.TxOptions{Isolation: postgres.RepeatableRead}, func(ctx context.Context, tx postgres.Transaction) error {
+		postRepo := tx.PostRepository()
+		// A retried attempt starts its summary over.
+		changes = &model.PostChangeSummary{}
+
+		before, err := s.lockPost(ctx, postRepo, "update", id)
 		if err != nil {
-			if errors.Is(err, custom_errors.ErrMediaNotFound) {
-				s.metrics.IncrementPostOperations("update", false)
-				s.log.Debug("Media not found for update", slog.Int64("id", id))
-				return custom_errors.ErrMediaNotFound
+			return err
+		}
+		// The row lock makes this check hold until commit, tags-only and media-only updates included.
+		if post.ExpectedVersion != nil && before.Version != *post.ExpectedVersion {
+			s.log.Debug("Post version conflict", slog.Int64("id", id),
+				slog.Int64("expected_version", *post.ExpectedVersion), slog.Int64("current_version", before.Version))
+			return &model.VersionConflictError{PostID: id, CurrentVersion: before.Version}
+		}
+		// Clearing the title is the only way an update can leave a post with nothing to show.
+		if post.Title != nil && len(post.MediaItems) == 0 && post.LeavesNoText(before) {
+			media, err := tx.MediaRepository().GetByPost(ctx, id)
+			if err != nil {
+				s.log.Error("Failed to get post media", slog.String("error", err.Error()), slog.Int64("id", id))
+				return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+			}
+			if err := model.ValidateUpdated(before, post, len(media) > 0); err != nil {
+				s.log.Debug("Post update would leave the post empty", slog.Int64("id", id))
+				return err
 			}
-			s.metrics.IncrementPostOperations("update", false)
-			s.log.Error("Failed to get post media", slog.String("error", err.Error()), slog.Int64("id", id))
-			return custom_errors.ErrDatabaseQuery
 		}
-		mediaIds := make([]int64, 0, len(media))
-		for _, mediaItem := range media {
-			mediaIds = append(mediaIds, mediaItem.ID)
+
+		// A tags-only or media-only update leaves the row alone apart from updated_at. A title
+		// the post already has is no change, so with nothing else the update is empty.
+		switch {
+		case post.ChangesFieldsOf(before):
+			updatedPost, err = postRepo.Update(ctx, id, post)
+		case len(post.Tags) > 0 || len(post.MediaItems) > 0:
+			updatedPost, err = postRepo.Touch(ctx, id)
+		default:
+			s.log.Debug("Post update changes nothing", slog.Int64("id", id))
+			return model.ErrEmptyUpdate
 		}
-		err = mediaRepo.Detach(ctx, mediaIds)
 		if err != nil {
-			s.metrics.IncrementPostOperations("update", false)
-			s.log.Error("Failed to clear media for post", slog.String("error", err.Error()), slog.Int64("id", id))
-			return custom_errors.ErrMediaAttachFailed
+			if errors.Is(err, custom_errors.ErrPostNotFound) {
+				s.log.Debug("Post not found for update", slog.Int64("id", id))
+				return custom_errors.ErrPostNotFound
+			}
+			if errors.Is(err, model.ErrVersionConflict) {
+				return err
+			}
+			s.log.Error("Failed to update post", slog.String("error", err.Error()), slog.Int64("id", id))
+			return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		changes.FieldsChanged = model.ChangedPostFields(before, updatedPost)
+		// Only a title or content that actually changed makes a revision.
+		if model.ChangesText(before, updatedPost) {
+			if err := s.recordRevision(ctx, tx.RevisionRepository(), userID, before, updatedPost); err != nil {
+				return err
+			}
 		}
+
 		if len(post.MediaItems) > 0 {
-			media := make([]*model.PostMedia, 0, len(post.MediaItems))
+			mediaRepo := tx.MediaRepository()
+			// A post without media has an empty list here, never an error, so only a failed
+			// query aborts the update.
+			media, err := mediaRepo.GetByPost(ctx, id)
+			if err != nil {
+				s.log.Error("Failed to get post media", slog.String("error", err.Error()), slog.Int64("id", id))
+				return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+			}
+			// The old media go first: the new ones may reuse their positions and urls.
+			if len(media) > 0 {
+				mediaIds := make([]int64, 0, len(media))
+				for _, mediaItem := range media {
+					mediaIds = append(mediaIds, mediaItem.ID)
+				}
+				if err := mediaRepo.Detach(ctx, mediaIds); err != nil {
+					s.log.Error("Failed to clear media for post", slog.String("error", err.Error()), slog.Int64("id", id))
+					return db.WithCause(custom_errors.ErrMediaAttachFailed, err)
+				}
+				changes.MediaDetached = mediaIds
+			}
+			newMedia := make([]*model.PostMedia, 0, len(post.MediaItems))
 			for _, m := range post.MediaItems {
-				media = append(media, &model.PostMedia{
-					PostID:   id,
-					URL:      m.URL,
-					Type:     m.Type,
-					Position: m.Position,
+				newMedia = append(newMedia, &model.PostMedia{
+					PostID:    id,
+					URL:       m.URL,
+					Type:      m.Type,
+					Position:  m.Position,
+					Width:     m.Width,
+					Height:    m.Height,
+					SizeBytes: m.SizeBytes,
+					AltText:   m.AltText,
 				})
 			}
-			err = mediaRepo.Attach(ctx, id, media)
+			err = mediaRepo.Attach(ctx, id, newMedia)
 			if err != nil {
-				s.metrics.IncrementPostOperations("update", false)
+				if errors.Is(err, model.ErrMediaDuplicate) {
+					s.log.Debug("Duplicate media in update post", slog.String("error", err.Error()), slog.Int64("id", id))
+					return model.ErrMediaDuplicate
+				}
 				s.log.Error("Failed to attach media to post", slog.String("error", err.Error()), slog.Int64("id", id))
-				return custom_errors.ErrMediaAttachFailed
+				return db.WithCause(custom_errors.ErrMediaAttachFailed, err)
 			}
 		}
-	}
 
-	if len(post.Tags) > 0 {
-		for _, name := range post.Tags {
-			_, tagErr := tagRepo.Create(ctx, name)
-			if tagErr != nil && !errors.Is(tagErr, custom_errors.ErrTagAlreadyExists) {
-				if errors.Is(tagErr, custom_errors.ErrTagCreateFailed) {
-					s.metrics.IncrementPostOperations("update", false)
-					s.log.Error("Failed to create tag", slog.String("error", tagErr.Error()))
-					return custom_errors.ErrTagCreateFailed
-				}
-				s.metrics.IncrementPostOperations("update", false)
-				s.log.Error("Unknown error creating tag", slog.String("error", tagErr.Error()))
-				return custom_errors.ErrUnknownTagError
+		var previousTags []*model.Tag
+		if len(post.Tags) > 0 {
+			tagRepo := tx.TagRepository()
+			previousTags, err = tagRepo.FindByPost(ctx, id)
+			if err != nil && !errors.Is(err, custom_errors.ErrTagsNotFound) {
+				s.log.Error("Failed to get post tags before update", slog.String("error", err.Error()), slog.Int64("id", id))
+				return db.WithCause(custom_errors.ErrTagQueryFailed, err)
 			}
-		}
-		err = tagRepo.ReplacePostTags(ctx, id, post.Tags)
-		if err != nil {
-			if errors.Is(err, custom_errors.ErrPostNotFound) {
-				s.metrics.IncrementPostOperations("update", false)
-				s.log.Debug("Post not found when tagging", slog.String("error", err.Error()))
-				return custom_errors.ErrPostNotFound
+			existingTags, err := tagRepo.FindByNames(ctx, post.Tags)
+			if err != nil {
+				s.log.Error("Failed to find existing tags", slog.String("error", err.Error()), slog.Int64("id", id))
+				return db.WithCause(custom_errors.ErrTagQueryFailed, err)
 			}
-			if errors.Is(err, custom_errors.ErrTagNotFound) {
-				s.metrics.IncrementPostOperations("update", false)
-				s.log.Debug("Tag not found when tagging post", slog.String("error", err.Error()))
-				return custom_errors.ErrTagNotFound
+			found := make(map[string]bool, len(existingTags))
+			for _, tag := range existingTags {
+				found[tag.Name] = true
 			}
-			if errors.Is(err, custom_errors.ErrTagVerifyPostFailed) {
-				s.metrics.IncrementPostOperations("update", false)
-				s.log.Error("Tag verify post failed", slog.String("error", err.Error()))
-				return custom_errors.ErrTagVerifyPostFailed
+			var missing []string
+			for _, name := range post.Tags {
+				if !found[name] {
+					missing = append(missing, name)
+				}
 			}
-			if errors.Is(err, custom_errors.ErrTagPost) {
-				s.metrics.IncrementPostOperations("update", false)
-				s.log.Error("Failed to tag post", slog.String("error", err.Error()))
-				return custom_errors.ErrTagPost
+			created, err := s.createTags(ctx, tagRepo, missing)
+			if err != nil {
+				return err
 			}
-			s.metrics.IncrementPostOperations("update", false)
-			s.log.Error("Unknown error tagging post", slog.String("error", err.Error()))
-			return err
+			changes.TagsCreated = append(changes.TagsCreated, created...)
+			err = tagRepo.ReplacePostTags(ctx, id, post.Tags)
+			if err != nil {
+				if errors.Is(err, custom_errors.ErrPostNotFound) {
+					s.log.Debug("Post not found when tagging", slog.String("error", err.Error()))
+					return custom_errors.ErrPostNotFound
+				}
+				if errors.Is(err, custom_errors.ErrTagNotFound) {
+					s.log.Debug("Tag not found when tagging post", slog.String("error", err.Error()))
+					return custom_errors.ErrTagNotFound
+				}
+				if errors.Is(err, custom_errors.ErrTagVerifyPostFailed) {
+					s.log.Error("Tag verify post failed", slog.String("error", err.Error()))
+					return db.WithCause(custom_errors.ErrTagVerifyPostFailed, err)
+				}
+				if errors.Is(err, custom_errors.ErrTagPost) {
+					s.log.Error("Failed to tag post", slog.String("error", err.Error()))
+					return db.WithCause(custom_errors.ErrTagPost, err)
+				}
+				s.log.Error("Unknown error tagging post", slog.String("error", err.Error()))
+				return err
+			}
+		}
+
+		updatedMedia, err = tx.MediaRepository().GetByPost(ctx, id)
+		if err != nil {
+			s.log.Error("Failed to get updated post media", slog.String("error", err.Error()), slog.Int64("id", id))
+			return db.WithCause(custom_errors.ErrMediaQueryFailed, err)
+		}
+		updatedTags, err = tx.TagRepository().FindByPost(ctx, id)
+		if err != nil && !errors.Is(err, custom_errors.ErrTagsNotFound) {
+			s.log.Error("Failed to get updated post tags", slog.String("error", err.Error()), slog.Int64("id", id))
+			return db.WithCause(custom_errors.ErrTagQueryFailed, err)
 		}
+		if len(post.MediaItems) > 0 {
+			changes.MediaAttached = updatedMedia
+		}
+		if len(post.Tags) > 0 {
+			changes.TagsAdded, changes.TagsRemoved = model.DiffTags(previousTags, updatedTags)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = tx.Commit(ctx)
+	// The update is already committed, so an unavailable author only degrades the response.
+	var author *model.User
+	err = inStage(ctx, s.metrics, "post_update", model.BudgetStageUserService, func(ctx context.Context) (err error) {
+		author, err = s.userClient.GetUser(ctx, updatedPost.AuthorID)
+		return err
+	})
 	if err != nil {
-		if strings.Contains(err.Error(), "commit unexpectedly resulted in rollback") {
-			s.metrics.IncrementPostOperations("update", false)
-			s.log.Warn("Transaction commit resulted in rollback", slog.String("error", err.Error()))
-			return custom_errors.ErrDatabaseQuery
+		if errors.Is(err, custom_errors.ErrUserNotFound) {
+			s.log.Debug("Author not found, returning updated post without author", slog.Int64("authorID", updatedPost.AuthorID))
+		} else {
+			s.log.Warn("Failed to get author for updated post", slog.String("error", err.Error()), slog.Int64("authorID", updatedPost.AuthorID))
 		}
-		s.metrics.IncrementPostOperations("update", false)
-		s.log.Error("Failed to commit transaction", slog.String("error", err.Error()))
-		return custom_errors.ErrDatabaseQuery
+		author = nil
 	}
-	txCommitted = true
 
-	s.metrics.IncrementPostOperations("update", true)
-	return nil
+	result = &model.PostDetailed{
+		Post:    updatedPost,
+		Author:  author,
+		Media:   updatedMedia,
+		Tags:    updatedTags,
+		Changes: changes,
+	}
+	result.CapMedia(s.limits.MaxEmbeddedMedia)
+	return result, nil
 }
 
 func (s *PostService) DeletePost(ctx context.Context, userID int64, id int64) (err error) {
-	tx, err := s.uow.Begin(ctx)
-	if err != nil {
-		s.metrics.IncrementPostOperations("delete", false)
-		s.log.Error("Failed to start transaction", slog.String("error", err.Error()))
-		return custom_errors.ErrDatabaseQuery
-	}
-
-	var txCommitted bool
-	defer func() {
-		if !txCommitted && tx != nil {
-			rollbackErr := tx.Rollback(ctx)
-			if rollbackErr != nil {
-				if !strings.Contains(rollbackErr.Error(), "tx is closed") && !strings.Contains(rollbackErr.Error(), "commit unexpectedly resulted in rollback") {
-					s.log.Error("Failed to rollback transaction", slog.String("error", rollbackErr.Error()))
-				} else {
-					s.log.Debug("Transaction already closed during rollback", slog.String("error", rollbackErr.Error()))
-				}
-			}
-		}
-	}()
-
-	postRepo := tx.PostRepository()
-	mediaRepo := tx.MediaRepository()
-	tagRepo := tx.TagRepository()
+	defer observePostOperation(s.metrics, "delete", time.Now(), &err)
+	if _, err = s.checkOwnership(ctx, "delete", userID, id); err != nil {
+		return err
+	}
 
-	post, err := postRepo.GetByID(ctx, id)
+	err = s.runInTx(ctx, "delete", func(ctx context.Context, tx postgres.Transaction) error {
+		return s.removePost(ctx, tx, "delete", id)
+	})
 	if err != nil {
-		if errors.Is(err, custom_errors.ErrPostNotFound) {
-			s.metrics.IncrementPostOperations("delete", false)
-			s.log.Debug("Post not found when deleting post", slog.String("error", err.Error()))
-			return custom_errors.ErrPostNotFound
-		} else {
-			s.metrics.IncrementPostOperations("delete", false)
-			s.log.Error("Failed to get post", slog.String("error", err.Error()), slog.Int64("id", id))
-			return custom_errors.ErrDatabaseQuery
-		}
+		return err
 	}
-	if post.AuthorID != userID {
-		s.metrics.IncrementPostOperations("delete", false)
-		s.log.Debug("User is not author of post", slog.Int64("userID", userID), slog.Int64("authorID", post.AuthorID))
-		return custom_errors.ErrForbidden
+	return nil
+}
+
+// removePost deletes post id inside tx after detaching its media and untagging it.
+func (s *PostService) removePost(ctx context.Context, tx postgres.Transaction, operation string, id int64) error {
+	postRepo := tx.PostRepository()
+	if _, err := s.lockPost(ctx, postRepo, operation, id); err != nil {
+		return err
 	}
 
+	mediaRepo := tx.MediaRepository()
 	media, err := mediaRepo.GetByPost(ctx, id)
 	if err != nil {
-		if errors.Is(err, custom_errors.ErrMediaNotFound) {
-			s.log.Debug("Media not found for post during delete", slog.Int64("id", id))
-			media = nil
-		} else {
-			s.metrics.IncrementPostOperations("delete", false)
-			s.log.Error("Failed to get media for post during delete", slog.String("error", err.Error()), slog.Int64("id", id))
-			return custom_errors.ErrMediaQueryFailed
-		}
+		s.log.Error("Failed to get media for post during delete", slog.String("error", err.Error()), slog.Int64("id", id))
+		return db.WithCause(custom_errors.ErrMediaQueryFailed, err)
 	}
 	mediaIds := make([]int64, 0, len(media))
 	for _, mediaItem := range media {
@@ -552,25 +817,20 @@ func (s *PostService) DeletePost(ctx context.Context, userID int64, id int64) (e
 	if len(mediaIds) > 0 {
 		err = mediaRepo.Detach(ctx, mediaIds)
 		if err != nil {
-			if errors.Is(err, custom_errors.ErrMediaNotFound) {
-				s.log.Debug("Media not found for post during detach", slog.Int64("id", id))
-			} else {
-				s.metrics.IncrementPostOperations("delete", false)
-				s.log.Error("Failed to detach media for post", slog.String("error", err.Error()), slog.Int64("id", id))
-				return custom_errors.ErrMediaDetachFailed
-			}
+			s.log.Error("Failed to detach media for post", slog.String("error", err.Error()), slog.Int64("id", id))
+			return db.WithCause(custom_errors.ErrMediaDetachFailed, err)
 		}
 	}
 
+	tagRepo := tx.TagRepository()
 	tags, err := tagRepo.FindByPost(ctx, id)
 	if err != nil {
 		if errors.Is(err, custom_errors.ErrTagsNotFound) {
 			s.log.Debug("Tags not found for post during delete", slog.Int64("id", id))
 			tags = nil
 		} else {
-			s.metrics.IncrementPostOperations("delete", false)
 			s.log.Error("Failed to get tags for post during delete", slog.String("error", err.Error()), slog.Int64("id", id))
-			return custom_errors.ErrTagQueryFailed
+			return db.WithCause(custom_errors.ErrTagQueryFailed, err)
 		}
 	}
 	tagNames := make([]string, 0, len(tags))
@@ -583,35 +843,149 @@ func (s *PostService) DeletePost(ctx context.Context, userID int64, id int64) (e
 			if errors.Is(err, custom_errors.ErrTagNotFound) {
 				s.log.Debug("Tags not found for post during untag", slog.Int64("id", id))
 			} else {
-				s.metrics.IncrementPostOperations("delete", false)
 				s.log.Error("Failed to untag post", slog.String("error", err.Error()), slog.Int64("id", id))
-				return custom_errors.ErrTagDeleteFailed
+				return db.WithCause(custom_errors.ErrTagDeleteFailed, err)
 			}
 		}
 	}
 	err = postRepo.Delete(ctx, id)
 	if err != nil {
 		if errors.Is(err, custom_errors.ErrPostNotFound) {
-			s.metrics.IncrementPostOperations("delete", false)
 			s.log.Debug("Post not found for delete", slog.Int64("id", id))
 			return custom_errors.ErrPostNotFound
 		}
-		s.metrics.IncrementPostOperations("delete", false)
 		s.log.Error("Failed to delete post", slog.String("error", err.Error()), slog.Int64("id", id))
-		return custom_errors.ErrDatabaseQuery
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
-	err = tx.Commit(ctx)
+	return nil
+}
+
+func (s *PostService) PublishPost(ctx context.Context, userID int64, id int64) (_ *model.PostDetailed, err error) {
+	defer observePostOperation(s.metrics, "publish", time.Now(), &err)
+	post, err := s.checkOwnership(ctx, "publish", userID, id)
 	if err != nil {
-		if strings.Contains(err.Error(), "commit unexpectedly resulted in rollback") {
-			s.metrics.IncrementPostOperations("delete", false)
-			s.log.Warn("Transaction commit resulted in rollback", slog.String("error", err.Error()))
-			return custom_errors.ErrDatabaseQuery
+		return nil, err
+	}
+
+	if post.Status == model.PostStatusPublished {
+		s.log.Debug("Post already published", slog.Int64("id", id))
+	} else {
+		err = inStage(ctx, s.metrics, "post_publish", model.BudgetStageDatabase, func(ctx context.Context) error {
+			_, err := s.postRepo.Publish(ctx, id)
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, custom_errors.ErrPostNotFound) {
+				s.log.Debug("Post not found for publish", slog.Int64("id", id))
+				return nil, custom_errors.ErrPostNotFound
+			}
+			s.log.Error("Failed to publish post", slog.String("error", err.Error()), slog.Int64("id", id))
+			return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 		}
-		s.metrics.IncrementPostOperations("delete", false)
-		s.log.Error("Failed to commit transaction", slog.String("error", err.Error()))
-		return custom_errors.ErrDatabaseQuery
 	}
-	txCommitted = true
-	s.metrics.IncrementPostOperations("delete", true)
-	return nil
+
+	result, err := s.GetPostByID(ctx, id, &userID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetPostTags returns only the tags of a post. With includeCounts every tag also carries
+// the number of posts using it, loaded in a single grouped query.
+func (s *PostService) GetPostTags(ctx context.Context, postID int64, requesterID *int64, includeCounts bool) ([]*model.Tag, error) {
+	var post *model.Post
+	err := inStage(ctx, s.metrics, "post_tags", model.BudgetStageDatabase, func(ctx context.Context) (err error) {
+		post, err = s.postRepo.GetByID(ctx, postID)
+		return err
+	})
+	if err != nil {
+		s.metrics.IncrementTagOperations("get_post_tags", false)
+		if errors.Is(err, custom_errors.ErrPostNotFound) {
+			s.log.Debug("Post not found", slog.Int64("id", postID))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		s.log.Error("Failed to get post", slog.String("error", err.Error()), slog.Int64("id", postID))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	if err := post.CheckAccess(requesterID); err != nil {
+		s.metrics.IncrementTagOperations("get_post_tags", false)
+		s.log.Debug("Post is hidden from requester", slog.Int64("id", postID), slog.Any("requesterID", requesterID), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var tags []*model.Tag
+	err = inStage(ctx, s.metrics, "post_tags", model.BudgetStageDatabase, func(ctx context.Context) (err error) {
+		tags, err = s.tagRepo.FindByPost(ctx, postID)
+		return err
+	})
+	if err != nil && !errors.Is(err, custom_errors.ErrTagsNotFound) {
+		s.metrics.IncrementTagOperations("get_post_tags", false)
+		s.log.Error("Failed to find tags by post", slog.String("error", err.Error()), slog.Int64("id", postID))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	if tags == nil {
+		tags = []*model.Tag{}
+	}
+
+	if includeCounts && len(tags) > 0 {
+		ids := make([]int64, len(tags))
+		for i, tag := range tags {
+			ids[i] = tag.ID
+		}
+		var counts map[int64]int64
+		err = inStage(ctx, s.metrics, "post_tags", model.BudgetStageDatabase, func(ctx context.Context) (err error) {
+			counts, err = s.tagRepo.CountPosts(ctx, ids)
+			return err
+		})
+		if err != nil {
+			s.metrics.IncrementTagOperations("get_post_tags", false)
+			s.log.Error("Failed to count posts by tags", slog.String("error", err.Error()), slog.Int64("id", postID))
+			return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+		}
+		for _, tag := range tags {
+			count := counts[tag.ID]
+			tag.PostCount = &count
+		}
+	}
+
+	s.metrics.IncrementTagOperations("get_post_tags", true)
+	return tags, nil
+}
+
+// checkOwnership loads the post outside of any transaction and verifies that userID is its author.
+func (s *PostService) checkOwnership(ctx context.Context, operation string, userID int64, id int64) (*model.Post, error) {
+	var post *model.Post
+	err := inStage(ctx, s.metrics, "post_"+operation, model.BudgetStageDatabase, func(ctx context.Context) (err error) {
+		post, err = s.postRepo.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrPostNotFound) {
+			s.log.Debug("Post not found", slog.String("operation", operation), slog.Int64("id", id))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		s.log.Error("Failed to get post", slog.String("operation", operation), slog.String("error", err.Error()), slog.Int64("id", id))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	if post.AuthorID != userID {
+		s.log.Debug("User is not author of post", slog.String("operation", operation), slog.Int64("userID", userID), slog.Int64("authorID", post.AuthorID))
+		return nil, custom_errors.ErrForbidden
+	}
+	return post, nil
+}
+
+// lockPost re-reads the post with a row lock inside the transaction, guarding against a concurrent delete,
+// and returns it as it was before the operation.
+func (s *PostService) lockPost(ctx context.Context, postRepo post_repository.Repository, operation string, id int64) (*model.Post, error) {
+	locked, err := postRepo.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrPostNotFound) {
+			s.log.Debug("Post deleted concurrently", slog.String("operation", operation), slog.Int64("id", id))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		s.log.Error("Failed to lock post", slog.String("operation", operation), slog.String("error", err.Error()), slog.Int64("id", id))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return locked, nil
 }
@@ -3,8 +3,11 @@ package post_service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 
@@ -12,12 +15,16 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	model "pinstack-post-service/internal/domain/models"
 	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/testsupport"
 	media_repository_mock "pinstack-post-service/mocks/media"
 	post_repository_mock "pinstack-post-service/mocks/post"
 	postgres_mock "pinstack-post-service/mocks/postgres"
+	revision_repository_mock "pinstack-post-service/mocks/revision"
 	tag_repository_mock "pinstack-post-service/mocks/tag"
 )
 
@@ -47,8 +54,136 @@ func TestPostService_CreatePost(t *testing.T) {
 				mediaRepo.On("Attach", mock.Anything, int64(1), mock.AnythingOfType("[]*model.PostMedia")).Return(nil)
 				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 1, PostID: 1, URL: "http://example.com/image.jpg", Type: model.MediaTypeImage, Position: 1}}, nil)
 				tagRepo.On("FindByNames", mock.Anything, []string{"tag1", "tag2"}).Return([]*model.Tag{{ID: 1, Name: "tag1"}}, nil)
-				tagRepo.On("Create", mock.Anything, "tag2").Return(&model.Tag{ID: 2, Name: "tag2"}, nil)
-				tagRepo.On("TagPost", mock.Anything, int64(1), []string{"tag1", "tag2"}).Return(nil)
+				tagRepo.On("CreateMany", mock.Anything, []string{"tag2"}).Return([]*model.Tag{{ID: 2, Name: "tag2"}}, nil)
+				tagRepo.On("TagPostExisting", mock.Anything, int64(1), []string{"tag1", "tag2"}).Return([]string{}, nil)
+				tx.On("Commit", mock.Anything).Return(nil)
+			},
+			args: args{
+				ctx: context.Background(),
+				post: testsupport.NewCreatePostDTOBuilder().
+					WithTitle("Test Post").
+					WithTags("tag1", "tag2").
+					WithMedia("http://example.com/image.jpg").
+					Build(),
+			},
+			want: &model.PostDetailed{
+				Post:       &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+				Author:     &model.User{ID: 1, Username: "testuser"},
+				Media:      []*model.PostMedia{{ID: 1, PostID: 1, URL: "http://example.com/image.jpg", Type: model.MediaTypeImage, Position: 1}},
+				MediaTotal: 1,
+				Tags:       []*model.Tag{{ID: 1, Name: "tag1"}, {ID: 2, Name: "tag2"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Success normalizes and deduplicates tags",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, userClient *user_client_mock.Client, tx *postgres_mock.Transaction) {
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("PostRepository").Return(postRepo)
+				tx.On("TagRepository").Return(tagRepo)
+				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
+				tagRepo.On("FindByNames", mock.Anything, []string{"go", "web-dev"}).Return([]*model.Tag{{ID: 1, Name: "go"}}, nil)
+				tagRepo.On("CreateMany", mock.Anything, []string{"web-dev"}).Return([]*model.Tag{{ID: 2, Name: "web-dev"}}, nil)
+				tagRepo.On("TagPostExisting", mock.Anything, int64(1), []string{"go", "web-dev"}).Return([]string{}, nil)
+				tx.On("Commit", mock.Anything).Return(nil)
+			},
+			args: args{
+				ctx: context.Background(),
+				post: &model.CreatePostDTO{
+					AuthorID: 1,
+					Title:    "Test Post",
+					Tags:     []string{"Go", "go", " GO ", "Web  Dev"},
+				},
+			},
+			want: &model.PostDetailed{
+				Post:   &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+				Author: &model.User{ID: 1, Username: "testuser"},
+				Media:  []*model.PostMedia{},
+				Tags:   []*model.Tag{{ID: 1, Name: "go"}, {ID: 2, Name: "web-dev"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Success after retrying a vanished tag",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, userClient *user_client_mock.Client, tx *postgres_mock.Transaction) {
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("PostRepository").Return(postRepo)
+				tx.On("TagRepository").Return(tagRepo)
+				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
+				tagRepo.On("FindByNames", mock.Anything, []string{"go", "rust"}).Return([]*model.Tag{{ID: 1, Name: "go"}, {ID: 2, Name: "rust"}}, nil)
+				tagRepo.On("TagPostExisting", mock.Anything, int64(1), []string{"go", "rust"}).Return([]string{"rust"}, nil).Once()
+				tagRepo.On("CreateMany", mock.Anything, []string{"rust"}).Return([]*model.Tag{{ID: 3, Name: "rust"}}, nil).Once()
+				tagRepo.On("TagPostExisting", mock.Anything, int64(1), []string{"rust"}).Return([]string{}, nil).Once()
+				tx.On("Commit", mock.Anything).Return(nil)
+			},
+			args: args{
+				ctx:  context.Background(),
+				post: &model.CreatePostDTO{AuthorID: 1, Title: "Test Post", Tags: []string{"go", "rust"}},
+			},
+			want: &model.PostDetailed{
+				Post:   &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+				Author: &model.User{ID: 1, Username: "testuser"},
+				Media:  []*model.PostMedia{},
+				Tags:   []*model.Tag{{ID: 1, Name: "go"}, {ID: 3, Name: "rust"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Success without the tags that failed twice",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, userClient *user_client_mock.Client, tx *postgres_mock.Transaction) {
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("PostRepository").Return(postRepo)
+				tx.On("TagRepository").Return(tagRepo)
+				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
+				tagRepo.On("FindByNames", mock.Anything, []string{"go", "rust"}).Return([]*model.Tag{{ID: 1, Name: "go"}, {ID: 2, Name: "rust"}}, nil)
+				tagRepo.On("TagPostExisting", mock.Anything, int64(1), []string{"go", "rust"}).Return([]string{"rust"}, nil).Once()
+				tagRepo.On("CreateMany", mock.Anything, []string{"rust"}).Return([]*model.Tag{{ID: 3, Name: "rust"}}, nil).Once()
+				tagRepo.On("TagPostExisting", mock.Anything, int64(1), []string{"rust"}).Return([]string{"rust"}, nil).Once()
+				tx.On("Commit", mock.Anything).Return(nil)
+			},
+			args: args{
+				ctx:  context.Background(),
+				post: &model.CreatePostDTO{AuthorID: 1, Title: "Test Post", Tags: []string{"go", "rust"}},
+			},
+			want: &model.PostDetailed{
+				Post:       &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+				Author:     &model.User{ID: 1, Username: "testuser"},
+				Media:      []*model.PostMedia{},
+				Tags:       []*model.Tag{{ID: 1, Name: "go"}},
+				FailedTags: []string{"rust"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Error validation tag empty after normalization",
+			args: args{
+				ctx: context.Background(),
+				post: &model.CreatePostDTO{
+					AuthorID: 1,
+					Title:    "Test Post",
+					Tags:     []string{"go", "   "},
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrType: custom_errors.ErrPostValidation,
+		},
+		{
+			name: "Success passes media metadata to repository",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, userClient *user_client_mock.Client, tx *postgres_mock.Transaction) {
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("PostRepository").Return(postRepo)
+				tx.On("MediaRepository").Return(mediaRepo)
+				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
+				mediaRepo.On("Attach", mock.Anything, int64(1), mock.MatchedBy(func(media []*model.PostMedia) bool {
+					return len(media) == 1 && media[0].Width != nil && *media[0].Width == 640 &&
+						media[0].AltText != nil && *media[0].AltText == "A bridge" && media[0].Height == nil
+				})).Return(nil)
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 1, PostID: 1, URL: "http://example.com/image.jpg", Type: model.MediaTypeImage, Position: 1}}, nil)
 				tx.On("Commit", mock.Anything).Return(nil)
 			},
 			args: args{
@@ -56,25 +191,48 @@ func TestPostService_CreatePost(t *testing.T) {
 				post: &model.CreatePostDTO{
 					AuthorID: 1,
 					Title:    "Test Post",
-					Content:  func() *string { s := "Test content"; return &s }(),
-					Tags:     []string{"tag1", "tag2"},
 					MediaItems: []*model.PostMediaInput{
 						{
 							URL:      "http://example.com/image.jpg",
 							Type:     model.MediaTypeImage,
 							Position: 1,
+							Width:    testsupport.Ptr(int32(640)),
+							AltText:  testsupport.Ptr("A bridge"),
 						},
 					},
 				},
 			},
 			want: &model.PostDetailed{
-				Post:   &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
-				Author: &model.User{ID: 1, Username: "testuser"},
-				Media:  []*model.PostMedia{{ID: 1, PostID: 1, URL: "http://example.com/image.jpg", Type: model.MediaTypeImage, Position: 1}},
-				Tags:   []*model.Tag{{ID: 1, Name: "tag1"}, {ID: 2, Name: "tag2"}},
+				Post:       &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+				Author:     &model.User{ID: 1, Username: "testuser"},
+				Media:      []*model.PostMedia{{ID: 1, PostID: 1, URL: "http://example.com/image.jpg", Type: model.MediaTypeImage, Position: 1}},
+				MediaTotal: 1,
+				Tags:       []*model.Tag{},
 			},
 			wantErr: false,
 		},
+		{
+			name: "Error validation media metadata",
+			args: args{
+				ctx: context.Background(),
+				post: &model.CreatePostDTO{
+					AuthorID: 1,
+					Title:    "Test Post",
+					MediaItems: []*model.PostMediaInput{
+						{
+							URL:      "http://example.com/image.jpg",
+							Type:     model.MediaTypeImage,
+							Position: 1,
+							Width:    testsupport.Ptr(int32(-1)),
+							AltText:  testsupport.Ptr(strings.Repeat("a", 501)),
+						},
+					},
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrType: custom_errors.ErrPostValidation,
+		},
 		{
 			name: "Error getting user",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, userClient *user_client_mock.Client, tx *postgres_mock.Transaction) {
@@ -82,12 +240,8 @@ func TestPostService_CreatePost(t *testing.T) {
 				// With the fix in service.go, if GetUser fails, the function should return before starting a transaction.
 			},
 			args: args{
-				ctx: context.Background(),
-				post: &model.CreatePostDTO{
-					AuthorID: 1, // Match the GetUser mock
-					Title:    "Test Post for GetUser Error",
-					Content:  func() *string { s := "Test content"; return &s }(),
-				},
+				ctx:  context.Background(),
+				post: testsupport.NewCreatePostDTOBuilder().WithTitle("Test Post for GetUser Error").Build(),
 			},
 			wantErr:     true,
 			wantErrType: custom_errors.ErrExternalServiceError,
@@ -109,14 +263,53 @@ func TestPostService_CreatePost(t *testing.T) {
 			wantErr:     true,
 			wantErrType: custom_errors.ErrDatabaseQuery,
 		},
+		{
+			name: "Error validation title too short",
+			args: args{
+				ctx: context.Background(),
+				post: &model.CreatePostDTO{
+					AuthorID: 1,
+					Title:    "ab",
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrType: custom_errors.ErrPostValidation,
+		},
+		{
+			name: "Error validation content too long",
+			args: args{
+				ctx: context.Background(),
+				post: &model.CreatePostDTO{
+					AuthorID: 1,
+					Title:    "Test Post",
+					Content:  testsupport.Ptr(strings.Repeat("a", model.DefaultMaxContentLength+1)),
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrType: custom_errors.ErrPostValidation,
+		},
+		{
+			name: "Error validation too many tags",
+			args: args{
+				ctx: context.Background(),
+				post: &model.CreatePostDTO{
+					AuthorID: 1,
+					Title:    "Test Post",
+					Tags:     []string{"t1", "t2", "t3", "t4", "t5", "t6", "t7", "t8", "t9", "t10", "t11"},
+				},
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrType: custom_errors.ErrPostValidation,
+		},
 		{
 			name: "Error creating post in repository",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, userClient *user_client_mock.Client, tx *postgres_mock.Transaction) {
 				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo) // Needed for defer
-				tx.On("TagRepository").Return(tagRepo)     // Needed for defer
 				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(nil, custom_errors.ErrDatabaseQuery)
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
@@ -138,7 +331,6 @@ func TestPostService_CreatePost(t *testing.T) {
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
 				tx.On("MediaRepository").Return(mediaRepo)
-				tx.On("TagRepository").Return(tagRepo) // Needed for defer
 				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
 				mediaRepo.On("Attach", mock.Anything, int64(1), mock.AnythingOfType("[]*model.PostMedia")).Return(custom_errors.ErrMediaAttachFailed)
 				tx.On("Rollback", mock.Anything).Return(nil)
@@ -148,7 +340,7 @@ func TestPostService_CreatePost(t *testing.T) {
 				post: &model.CreatePostDTO{
 					AuthorID:   1,
 					Title:      "Test Post",
-					MediaItems: []*model.PostMediaInput{{URL: "url", Type: "image", Position: 1}},
+					MediaItems: []*model.PostMediaInput{{URL: "https://example.com/image.jpg", Type: "image", Position: 1}},
 				},
 			},
 			want:        nil,
@@ -162,7 +354,6 @@ func TestPostService_CreatePost(t *testing.T) {
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
 				tx.On("MediaRepository").Return(mediaRepo)
-				tx.On("TagRepository").Return(tagRepo) // Needed for defer
 				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
 				mediaRepo.On("Attach", mock.Anything, int64(1), mock.AnythingOfType("[]*model.PostMedia")).Return(nil)
 				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrMediaQueryFailed)
@@ -173,7 +364,7 @@ func TestPostService_CreatePost(t *testing.T) {
 				post: &model.CreatePostDTO{
 					AuthorID:   1,
 					Title:      "Test Post",
-					MediaItems: []*model.PostMediaInput{{URL: "url", Type: "image", Position: 1}},
+					MediaItems: []*model.PostMediaInput{{URL: "https://example.com/image.jpg", Type: "image", Position: 1}},
 				},
 			},
 			want:        nil,
@@ -186,7 +377,6 @@ func TestPostService_CreatePost(t *testing.T) {
 				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo)
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
 				// No media for simplicity in this tag-focused error case
@@ -211,11 +401,10 @@ func TestPostService_CreatePost(t *testing.T) {
 				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo)
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
 				tagRepo.On("FindByNames", mock.Anything, []string{"newtag"}).Return([]*model.Tag{}, nil) // No existing tags found
-				tagRepo.On("Create", mock.Anything, "newtag").Return(nil, custom_errors.ErrTagCreateFailed)
+				tagRepo.On("CreateMany", mock.Anything, []string{"newtag"}).Return(nil, custom_errors.ErrTagCreateFailed)
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
 			args: args{
@@ -236,11 +425,10 @@ func TestPostService_CreatePost(t *testing.T) {
 				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo)
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
 				tagRepo.On("FindByNames", mock.Anything, []string{"tag1"}).Return([]*model.Tag{{ID: 1, Name: "tag1"}}, nil)
-				tagRepo.On("TagPost", mock.Anything, int64(1), []string{"tag1"}).Return(custom_errors.ErrTagPost)
+				tagRepo.On("TagPostExisting", mock.Anything, int64(1), []string{"tag1"}).Return(nil, custom_errors.ErrTagPost)
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
 			args: args{
@@ -261,11 +449,9 @@ func TestPostService_CreatePost(t *testing.T) {
 				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo)
-				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
 				// Assuming no media and no new tags for simplicity in this commit-focused error case
-				// FindByNames and TagPost are not called if post.Tags is nil
+				// FindByNames and TagPostExisting are not called if post.Tags is nil
 				tx.On("Commit", mock.Anything).Return(errors.New("commit error"))
 				tx.On("Rollback", mock.Anything).Return(nil) // Rollback should still be called by defer if commit fails
 			},
@@ -295,7 +481,7 @@ func TestPostService_CreatePost(t *testing.T) {
 				tt.mocks(postRepo, tagRepo, mediaRepo, uow, userClient, tx)
 			}
 
-			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics)
+			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics, model.DefaultPostLimits())
 			got, err := s.CreatePost(tt.args.ctx, tt.args.post)
 
 			if tt.wantErr {
@@ -318,11 +504,78 @@ func TestPostService_CreatePost(t *testing.T) {
 	}
 }
 
+func TestPostService_CreatePost_CreatesMissingTagsInOneCall(t *testing.T) {
+	names := func(n int) []string {
+		tags := make([]string, n)
+		for i := range tags {
+			tags[i] = fmt.Sprintf("tag%02d", i)
+		}
+		return tags
+	}
+	tagRows := func(names []string) []*model.Tag {
+		tags := make([]*model.Tag, len(names))
+		for i, name := range names {
+			tags[i] = &model.Tag{ID: int64(i + 1), Name: name}
+		}
+		return tags
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		existing []string
+		missing  []string
+	}{
+		{name: "one new tag", tags: names(1), missing: names(1)},
+		{name: "ten new tags", tags: names(10), missing: names(10)},
+		{name: "some new tags", tags: names(10), existing: names(10)[:4], missing: names(10)[4:]},
+		{name: "every tag exists", tags: names(10), existing: names(10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postRepo := new(post_repository_mock.Repository)
+			tagRepo := new(tag_repository_mock.Repository)
+			uow := new(postgres_mock.UnitOfWork)
+			tx := new(postgres_mock.Transaction)
+			userClient := new(user_client_mock.Client)
+			userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
+			uow.On("Begin", mock.Anything).Return(tx, nil)
+			tx.On("PostRepository").Return(postRepo)
+			tx.On("MediaRepository").Return(new(media_repository_mock.Repository))
+			tx.On("TagRepository").Return(tagRepo)
+			tx.On("Commit", mock.Anything).Return(nil)
+			postRepo.On("Create", mock.Anything, mock.AnythingOfType("*model.Post")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Tags"}, nil)
+			tagRepo.On("FindByNames", mock.Anything, tt.tags).Return(tagRows(tt.existing), nil)
+			if len(tt.missing) > 0 {
+				tagRepo.On("CreateMany", mock.Anything, tt.missing).Return(tagRows(tt.missing), nil)
+			}
+			tagRepo.On("TagPostExisting", mock.Anything, int64(1), tt.tags).Return([]string{}, nil)
+
+			s := NewPostService(postRepo, tagRepo, new(media_repository_mock.Repository), uow, logger.New("test"), userClient,
+				prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+			got, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Tags", Tags: tt.tags})
+
+			require.NoError(t, err)
+			assert.Len(t, got.Tags, len(tt.tags))
+			wantCalls := 0
+			if len(tt.missing) > 0 {
+				wantCalls = 1
+			}
+			tagRepo.AssertNumberOfCalls(t, "CreateMany", wantCalls)
+			tagRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestPostService_GetPostByID(t *testing.T) {
 	log := logger.New("test")
+	authorID := int64(1)
+	otherUserID := int64(2)
 	type args struct {
-		ctx    context.Context
-		postID int64
+		ctx         context.Context
+		postID      int64
+		requesterID *int64
 	}
 	tests := []struct {
 		name        string
@@ -335,27 +588,30 @@ func TestPostService_GetPostByID(t *testing.T) {
 		{
 			name: "Success",
 			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
-				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(&model.PostDetailed{
+					Post:  &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+					Media: []*model.PostMedia{{ID: 1, PostID: 1, URL: "url", Type: "image"}},
+					Tags:  []*model.Tag{{ID: 1, Name: "tag1"}},
+				}, nil)
 				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 1, PostID: 1, URL: "url", Type: "image"}}, nil)
-				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{{ID: 1, Name: "tag1"}}, nil)
 			},
 			args: args{
 				ctx:    context.Background(),
 				postID: 1,
 			},
 			want: &model.PostDetailed{
-				Post:   &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
-				Author: &model.User{ID: 1, Username: "testuser"},
-				Media:  []*model.PostMedia{{ID: 1, PostID: 1, URL: "url", Type: "image"}},
-				Tags:   []*model.Tag{{ID: 1, Name: "tag1"}},
+				Post:       &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+				Author:     &model.User{ID: 1, Username: "testuser"},
+				Media:      []*model.PostMedia{{ID: 1, PostID: 1, URL: "url", Type: "image"}},
+				MediaTotal: 1,
+				Tags:       []*model.Tag{{ID: 1, Name: "tag1"}},
 			},
 			wantErr: false,
 		},
 		{
 			name: "Error post not found",
 			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
-				postRepo.On("GetByID", mock.Anything, int64(1)).Return(nil, custom_errors.ErrPostNotFound)
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(nil, custom_errors.ErrPostNotFound)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -365,10 +621,71 @@ func TestPostService_GetPostByID(t *testing.T) {
 			wantErr:     true,
 			wantErrType: custom_errors.ErrPostNotFound,
 		},
+		{
+			name: "Draft hidden from other users",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(&model.PostDetailed{
+					Post:  &model.Post{ID: 1, AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft},
+					Media: []*model.PostMedia{},
+				}, nil)
+			},
+			args: args{
+				ctx:         context.Background(),
+				postID:      1,
+				requesterID: &otherUserID,
+			},
+			want:        nil,
+			wantErr:     true,
+			wantErrType: custom_errors.ErrPostNotFound,
+		},
+		{
+			name: "Draft visible to author",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(&model.PostDetailed{
+					Post:  &model.Post{ID: 1, AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft},
+					Media: []*model.PostMedia{},
+				}, nil)
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
+			},
+			args: args{
+				ctx:         context.Background(),
+				postID:      1,
+				requesterID: &authorID,
+			},
+			want: &model.PostDetailed{
+				Post:   &model.Post{ID: 1, AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft},
+				Author: &model.User{ID: 1, Username: "testuser"},
+				Media:  []*model.PostMedia{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Author deleted returns post without author",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(&model.PostDetailed{
+					Post:  &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+					Media: []*model.PostMedia{},
+				}, nil)
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(nil, custom_errors.ErrUserNotFound)
+			},
+			args: args{
+				ctx:    context.Background(),
+				postID: 1,
+			},
+			want: &model.PostDetailed{
+				Post:   &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+				Author: nil,
+				Media:  []*model.PostMedia{},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Error getting user",
 			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
-				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(&model.PostDetailed{
+					Post:  &model.Post{ID: 1, AuthorID: 1, Title: "Test Post"},
+					Media: []*model.PostMedia{},
+				}, nil)
 				userClient.On("GetUser", mock.Anything, int64(1)).Return(nil, errors.New("user service error"))
 			},
 			args: args{
@@ -382,9 +699,7 @@ func TestPostService_GetPostByID(t *testing.T) {
 		{
 			name: "Error getting media",
 			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
-				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
-				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrMediaQueryFailed)
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(nil, custom_errors.ErrMediaQueryFailed)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -397,10 +712,7 @@ func TestPostService_GetPostByID(t *testing.T) {
 		{
 			name: "Error getting tags",
 			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
-				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Test Post"}, nil)
-				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
-				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagQueryFailed)
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagQueryFailed)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -425,8 +737,8 @@ func TestPostService_GetPostByID(t *testing.T) {
 				tt.mocks(postRepo, mediaRepo, tagRepo, userClient)
 			}
 
-			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics)
-			got, err := s.GetPostByID(tt.args.ctx, tt.args.postID)
+			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics, model.DefaultPostLimits())
+			got, err := s.GetPostByID(tt.args.ctx, tt.args.postID, tt.args.requesterID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -484,16 +796,18 @@ func TestPostService_ListPosts(t *testing.T) {
 			},
 			want: []*model.PostDetailed{
 				{
-					Post:   &model.Post{ID: 1, AuthorID: 1, Title: "Post 1"},
-					Author: &model.User{ID: 1, Username: "user1"},
-					Media:  []*model.PostMedia{{ID: 1, PostID: 1, URL: "url1", Type: "image"}},
-					Tags:   []*model.Tag{{ID: 1, Name: "tag1"}},
+					Post:       &model.Post{ID: 1, AuthorID: 1, Title: "Post 1"},
+					Author:     &model.User{ID: 1, Username: "user1"},
+					Media:      []*model.PostMedia{{ID: 1, PostID: 1, URL: "url1", Type: "image"}},
+					MediaTotal: 1,
+					Tags:       []*model.Tag{{ID: 1, Name: "tag1"}},
 				},
 				{
-					Post:   &model.Post{ID: 2, AuthorID: 2, Title: "Post 2"},
-					Author: &model.User{ID: 2, Username: "user2"},
-					Media:  []*model.PostMedia{{ID: 2, PostID: 2, URL: "url2", Type: "video"}},
-					Tags:   []*model.Tag{{ID: 2, Name: "tag2"}},
+					Post:       &model.Post{ID: 2, AuthorID: 2, Title: "Post 2"},
+					Author:     &model.User{ID: 2, Username: "user2"},
+					Media:      []*model.PostMedia{{ID: 2, PostID: 2, URL: "url2", Type: "video"}},
+					MediaTotal: 1,
+					Tags:       []*model.Tag{{ID: 2, Name: "tag2"}},
 				},
 			},
 			wantErr: false,
@@ -529,12 +843,12 @@ func TestPostService_ListPosts(t *testing.T) {
 			wantErrType: custom_errors.ErrDatabaseQuery,
 		},
 		{
-			name: "Media not found for a post (should be nil in result)",
+			name: "Post without media",
 			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
 				filters := model.PostFilters{Limit: func(i int) *int { return &i }(10), Offset: func(i int) *int { return &i }(0)}
 				posts := []*model.Post{{ID: 1, AuthorID: 1, Title: "Post 1"}}
 				postRepo.On("List", mock.Anything, filters).Return(posts, len(posts), nil)
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrMediaNotFound)
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
 				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{{ID: 1, Name: "tag1"}}, nil)
 				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "user1"}, nil)
 			},
@@ -546,7 +860,7 @@ func TestPostService_ListPosts(t *testing.T) {
 				{
 					Post:   &model.Post{ID: 1, AuthorID: 1, Title: "Post 1"},
 					Author: &model.User{ID: 1, Username: "user1"},
-					Media:  nil,
+					Media:  []*model.PostMedia{},
 					Tags:   []*model.Tag{{ID: 1, Name: "tag1"}},
 				},
 			},
@@ -609,25 +923,181 @@ func TestPostService_ListPosts(t *testing.T) {
 			},
 			want:        nil,
 			wantErr:     true,
-			wantErrType: custom_errors.ErrDatabaseQuery, // As per current service logic
+			wantErrType: custom_errors.ErrExternalServiceError,
 		},
 		{
-			name: "User not found for a post",
+			name: "User not found for a post returns post without author",
 			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
 				filters := model.PostFilters{Limit: func(i int) *int { return &i }(10), Offset: func(i int) *int { return &i }(0)}
-				posts := []*model.Post{{ID: 1, AuthorID: 1, Title: "Post 1"}}
+				posts := []*model.Post{{ID: 1, AuthorID: 1, Title: "Post 1"}, {ID: 2, AuthorID: 2, Title: "Post 2"}}
 				postRepo.On("List", mock.Anything, filters).Return(posts, len(posts), nil)
 				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
+				mediaRepo.On("GetByPost", mock.Anything, int64(2)).Return([]*model.PostMedia{}, nil)
 				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{}, nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(2)).Return([]*model.Tag{}, nil)
 				userClient.On("GetUser", mock.Anything, int64(1)).Return(nil, custom_errors.ErrUserNotFound)
+				userClient.On("GetUser", mock.Anything, int64(2)).Return(&model.User{ID: 2, Username: "user2"}, nil)
+			},
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{Limit: func(i int) *int { return &i }(10), Offset: func(i int) *int { return &i }(0)},
+			},
+			want: []*model.PostDetailed{
+				{
+					Post:   &model.Post{ID: 1, AuthorID: 1, Title: "Post 1"},
+					Author: nil,
+					Media:  []*model.PostMedia{},
+					Tags:   []*model.Tag{},
+				},
+				{
+					Post:   &model.Post{ID: 2, AuthorID: 2, Title: "Post 2"},
+					Author: &model.User{ID: 2, Username: "user2"},
+					Media:  []*model.PostMedia{},
+					Tags:   []*model.Tag{},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Feed of several authors fetches each author once",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				filters := model.PostFilters{AuthorIDs: []int64{1, 2}, Limit: func(i int) *int { return &i }(model.DefaultListLimit)}
+				posts := []*model.Post{{ID: 3, AuthorID: 1, Title: "Post 3"}, {ID: 2, AuthorID: 2, Title: "Post 2"}, {ID: 1, AuthorID: 1, Title: "Post 1"}}
+				postRepo.On("List", mock.Anything, filters).Return(posts, len(posts), nil)
+				for _, id := range []int64{1, 2, 3} {
+					mediaRepo.On("GetByPost", mock.Anything, id).Return([]*model.PostMedia{}, nil)
+					tagRepo.On("FindByPost", mock.Anything, id).Return([]*model.Tag{}, nil)
+				}
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "user1"}, nil).Once()
+				userClient.On("GetUser", mock.Anything, int64(2)).Return(nil, custom_errors.ErrUserNotFound).Once()
+			},
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{AuthorIDs: []int64{1, 2}},
+			},
+			want: []*model.PostDetailed{
+				{Post: &model.Post{ID: 3, AuthorID: 1, Title: "Post 3"}, Author: &model.User{ID: 1, Username: "user1"}, Media: []*model.PostMedia{}, Tags: []*model.Tag{}},
+				{Post: &model.Post{ID: 2, AuthorID: 2, Title: "Post 2"}, Author: nil, Media: []*model.PostMedia{}, Tags: []*model.Tag{}},
+				{Post: &model.Post{ID: 1, AuthorID: 1, Title: "Post 1"}, Author: &model.User{ID: 1, Username: "user1"}, Media: []*model.PostMedia{}, Tags: []*model.Tag{}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Too many feed authors",
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{AuthorIDs: make([]int64, model.DefaultMaxFeedAuthors+1)},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "AuthorID and AuthorIDs together",
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{AuthorID: func(i int64) *int64 { return &i }(1), AuthorIDs: []int64{2}},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Unsortable column",
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{SortBy: "views"},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Unknown sort order",
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{SortBy: model.SortByUpdatedAt, SortOrder: "random"},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Missing limit defaults to the default page size",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				filters := model.PostFilters{Limit: func(i int) *int { return &i }(model.DefaultListLimit)}
+				postRepo.On("List", mock.Anything, filters).Return([]*model.Post{}, 0, nil)
+			},
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{},
+			},
+			want: []*model.PostDetailed{},
+		},
+		{
+			name: "Limit above the maximum",
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{Limit: func(i int) *int { return &i }(model.DefaultMaxListLimit + 1)},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Zero limit",
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{Limit: func(i int) *int { return &i }(0)},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Negative offset",
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{Offset: func(i int) *int { return &i }(-1)},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Created after is not before created before",
+			args: args{
+				ctx: context.Background(),
+				filters: &model.PostFilters{
+					CreatedAfter:  testsupport.TimestamptzPtr(time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC)),
+					CreatedBefore: testsupport.TimestamptzPtr(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)),
+				},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Empty tag name",
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{TagNames: []string{"go", " "}},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Unknown media type",
+			args: args{
+				ctx:     context.Background(),
+				filters: &model.PostFilters{MediaType: func(t model.MediaType) *model.MediaType { return &t }("audio")},
 			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Media type of posts without media",
 			args: args{
-				ctx:     context.Background(),
-				filters: &model.PostFilters{Limit: func(i int) *int { return &i }(10), Offset: func(i int) *int { return &i }(0)},
+				ctx: context.Background(),
+				filters: &model.PostFilters{
+					HasMedia:  func(b bool) *bool { return &b }(false),
+					MediaType: func(t model.MediaType) *model.MediaType { return &t }(model.MediaTypeImage),
+				},
 			},
-			want:        nil,
 			wantErr:     true,
-			wantErrType: custom_errors.ErrUserNotFound,
+			wantErrType: custom_errors.ErrInvalidInput,
 		},
 	}
 	for _, tt := range tests {
@@ -643,7 +1113,7 @@ func TestPostService_ListPosts(t *testing.T) {
 				tt.mocks(postRepo, mediaRepo, tagRepo, userClient)
 			}
 
-			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics)
+			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics, model.DefaultPostLimits())
 			got, total, err := s.ListPosts(tt.args.ctx, tt.args.filters)
 
 			if tt.wantErr {
@@ -685,20 +1155,29 @@ func TestPostService_UpdatePost(t *testing.T) {
 		{
 			name: "Success",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
+				uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
 				tx.On("MediaRepository").Return(mediaRepo)
 				tx.On("TagRepository").Return(tagRepo)
 
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				postRepo.On("Update", mock.Anything, int64(1), mock.AnythingOfType("*model.UpdatePostDTO")).Return(&model.Post{}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("Update", mock.Anything, int64(1), mock.AnythingOfType("*model.UpdatePostDTO")).Return(&model.Post{ID: 1, AuthorID: 1, Title: "Updated Title"}, nil)
+				revisionRepo := new(revision_repository_mock.Repository)
+				tx.On("RevisionRepository").Return(revisionRepo)
+				revisionRepo.On("Record", mock.Anything, mock.AnythingOfType("*model.PostRevision")).Return(&model.PostRevision{PostID: 1, Revision: 1}, nil)
+				revisionRepo.On("Prune", mock.Anything, int64(1), model.DefaultMaxRevisionsPerPost).Return(0, nil)
 
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 10}}, nil)
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 10}}, nil).Once()
 				mediaRepo.On("Detach", mock.Anything, []int64{10}).Return(nil)
 				mediaRepo.On("Attach", mock.Anything, int64(1), mock.AnythingOfType("[]*model.PostMedia")).Return(nil)
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 11, PostID: 1, URL: "https://example.com/new.jpg", Type: "image", Position: 1}}, nil).Once()
 
-				tagRepo.On("Create", mock.Anything, "newtag").Return(&model.Tag{ID: 1, Name: "newtag"}, nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{{ID: 2, Name: "oldtag"}}, nil).Once()
+				tagRepo.On("FindByNames", mock.Anything, []string{"newtag"}).Return([]*model.Tag{}, nil)
+				tagRepo.On("CreateMany", mock.Anything, []string{"newtag"}).Return([]*model.Tag{{ID: 1, Name: "newtag"}}, nil)
 				tagRepo.On("ReplacePostTags", mock.Anything, int64(1), []string{"newtag"}).Return(nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{{ID: 1, Name: "newtag"}}, nil).Once()
 
 				tx.On("Commit", mock.Anything).Return(nil)
 			},
@@ -706,24 +1185,38 @@ func TestPostService_UpdatePost(t *testing.T) {
 				ctx:    context.Background(),
 				userID: 1,
 				postID: 1,
-				post: &model.UpdatePostDTO{
-					Title:      func() *string { s := "Updated Title"; return &s }(),
-					MediaItems: []*model.PostMediaInput{{URL: "new_url", Type: "image", Position: 1}},
-					Tags:       []string{"newtag"},
-				},
+				post: testsupport.NewUpdatePostDTOBuilder().
+					WithTitle("Updated Title").
+					WithMedia("https://example.com/new.jpg").
+					WithTags("newtag").
+					Build(),
 			},
 			wantErr: false,
 		},
+		{
+			name: "Error empty update",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 1,
+				postID: 1,
+				post:   testsupport.NewUpdatePostDTOBuilder().WithContent("").ExpectingVersion(3).Build(),
+			},
+			wantErr:     true,
+			wantErrType: model.ErrEmptyUpdate,
+		},
 		{
 			name: "Error begin transaction",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(nil, errors.New("db error"))
+				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(nil, errors.New("db error"))
 			},
 			args: args{
 				ctx:    context.Background(),
 				userID: 1,
 				postID: 1,
-				post:   &model.UpdatePostDTO{},
+				post:   testsupport.NewUpdatePostDTOBuilder().WithTitle("Updated Title").Build(),
 			},
 			wantErr:     true,
 			wantErrType: custom_errors.ErrDatabaseQuery,
@@ -731,18 +1224,13 @@ func TestPostService_UpdatePost(t *testing.T) {
 		{
 			name: "Error GetByID post not found",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
-				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo) // For defer
-				tx.On("TagRepository").Return(tagRepo)     // For defer
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(nil, custom_errors.ErrPostNotFound)
-				tx.On("Rollback", mock.Anything).Return(nil)
 			},
 			args: args{
 				ctx:    context.Background(),
 				userID: 1,
 				postID: 1,
-				post:   &model.UpdatePostDTO{},
+				post:   testsupport.NewUpdatePostDTOBuilder().WithTitle("Updated Title").Build(),
 			},
 			wantErr:     true,
 			wantErrType: custom_errors.ErrPostNotFound,
@@ -750,30 +1238,24 @@ func TestPostService_UpdatePost(t *testing.T) {
 		{
 			name: "Error user is not author",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
-				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo)                                                   // For defer
-				tx.On("TagRepository").Return(tagRepo)                                                       // For defer
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 2}, nil) // Different AuthorID
-				tx.On("Rollback", mock.Anything).Return(nil)
 			},
 			args: args{
 				ctx:    context.Background(),
 				userID: 1, // UserID is 1
 				postID: 1,
-				post:   &model.UpdatePostDTO{},
+				post:   testsupport.NewUpdatePostDTOBuilder().WithTitle("Updated Title").Build(),
 			},
 			wantErr:     true,
-			wantErrType: custom_errors.ErrInvalidInput,
+			wantErrType: custom_errors.ErrForbidden,
 		},
 		{
 			name: "Error updating post in repo",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
+				uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo) // For defer
-				tx.On("TagRepository").Return(tagRepo)     // For defer
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
 				postRepo.On("Update", mock.Anything, int64(1), mock.AnythingOfType("*model.UpdatePostDTO")).Return(nil, custom_errors.ErrDatabaseQuery)
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
@@ -781,7 +1263,7 @@ func TestPostService_UpdatePost(t *testing.T) {
 				ctx:    context.Background(),
 				userID: 1,
 				postID: 1,
-				post:   &model.UpdatePostDTO{Title: func() *string { s := "t"; return &s }()},
+				post:   &model.UpdatePostDTO{Title: testsupport.Ptr("Updated title")},
 			},
 			wantErr:     true,
 			wantErrType: custom_errors.ErrDatabaseQuery,
@@ -789,12 +1271,12 @@ func TestPostService_UpdatePost(t *testing.T) {
 		{
 			name: "Error detaching media",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
+				uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
 				tx.On("MediaRepository").Return(mediaRepo)
-				tx.On("TagRepository").Return(tagRepo) // For defer
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				postRepo.On("Update", mock.Anything, int64(1), mock.AnythingOfType("*model.UpdatePostDTO")).Return(&model.Post{}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("Touch", mock.Anything, int64(1)).Return(&model.Post{}, nil)
 				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 10}}, nil)
 				mediaRepo.On("Detach", mock.Anything, []int64{10}).Return(errors.New("detach error"))
 				tx.On("Rollback", mock.Anything).Return(nil)
@@ -804,7 +1286,7 @@ func TestPostService_UpdatePost(t *testing.T) {
 				userID: 1,
 				postID: 1,
 				post: &model.UpdatePostDTO{
-					MediaItems: []*model.PostMediaInput{{URL: "new_url", Type: "image", Position: 1}},
+					MediaItems: []*model.PostMediaInput{{URL: "https://example.com/new.jpg", Type: "image", Position: 1}},
 				},
 			},
 			wantErr:     true,
@@ -813,12 +1295,12 @@ func TestPostService_UpdatePost(t *testing.T) {
 		{
 			name: "Error attaching media",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
+				uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
 				tx.On("MediaRepository").Return(mediaRepo)
-				tx.On("TagRepository").Return(tagRepo) // For defer
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				postRepo.On("Update", mock.Anything, int64(1), mock.AnythingOfType("*model.UpdatePostDTO")).Return(&model.Post{}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("Touch", mock.Anything, int64(1)).Return(&model.Post{}, nil)
 				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 10}}, nil)
 				mediaRepo.On("Detach", mock.Anything, []int64{10}).Return(nil)
 				mediaRepo.On("Attach", mock.Anything, int64(1), mock.AnythingOfType("[]*model.PostMedia")).Return(errors.New("attach error"))
@@ -829,7 +1311,7 @@ func TestPostService_UpdatePost(t *testing.T) {
 				userID: 1,
 				postID: 1,
 				post: &model.UpdatePostDTO{
-					MediaItems: []*model.PostMediaInput{{URL: "new_url", Type: "image", Position: 1}},
+					MediaItems: []*model.PostMediaInput{{URL: "https://example.com/new.jpg", Type: "image", Position: 1}},
 				},
 			},
 			wantErr:     true,
@@ -838,14 +1320,16 @@ func TestPostService_UpdatePost(t *testing.T) {
 		{
 			name: "Error creating tag",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
+				uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo) // For defer
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				postRepo.On("Update", mock.Anything, int64(1), mock.AnythingOfType("*model.UpdatePostDTO")).Return(&model.Post{}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("Touch", mock.Anything, int64(1)).Return(&model.Post{}, nil)
 				// No media items for this test case
-				tagRepo.On("Create", mock.Anything, "newtag").Return(nil, custom_errors.ErrTagCreateFailed)
+				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagsNotFound)
+				tagRepo.On("FindByNames", mock.Anything, []string{"newtag"}).Return([]*model.Tag{}, nil)
+				tagRepo.On("CreateMany", mock.Anything, []string{"newtag"}).Return(nil, custom_errors.ErrTagCreateFailed)
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
 			args: args{
@@ -862,13 +1346,15 @@ func TestPostService_UpdatePost(t *testing.T) {
 		{
 			name: "Error replacing post tags",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
+				uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo) // For defer
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				postRepo.On("Update", mock.Anything, int64(1), mock.AnythingOfType("*model.UpdatePostDTO")).Return(&model.Post{}, nil)
-				tagRepo.On("Create", mock.Anything, "newtag").Return(&model.Tag{ID: 1, Name: "newtag"}, nil) // Or ErrTagAlreadyExists
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("Touch", mock.Anything, int64(1)).Return(&model.Post{}, nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagsNotFound)
+				tagRepo.On("FindByNames", mock.Anything, []string{"newtag"}).Return([]*model.Tag{}, nil)
+				tagRepo.On("CreateMany", mock.Anything, []string{"newtag"}).Return([]*model.Tag{{ID: 1, Name: "newtag"}}, nil)
 				tagRepo.On("ReplacePostTags", mock.Anything, int64(1), []string{"newtag"}).Return(custom_errors.ErrTagPost)
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
@@ -883,16 +1369,29 @@ func TestPostService_UpdatePost(t *testing.T) {
 			wantErr:     true,
 			wantErrType: custom_errors.ErrTagPost,
 		},
+		{
+			name: "Error validation tag is not a slug",
+			args: args{
+				ctx:    context.Background(),
+				userID: 1,
+				postID: 1,
+				post:   &model.UpdatePostDTO{Tags: []string{"c++"}},
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrPostValidation,
+		},
 		{
 			name: "Error committing transaction",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
+				uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
 				tx.On("MediaRepository").Return(mediaRepo)
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
 				postRepo.On("Update", mock.Anything, int64(1), mock.AnythingOfType("*model.UpdatePostDTO")).Return(&model.Post{}, nil)
-				// No media or tags for simplicity
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagsNotFound)
 				tx.On("Commit", mock.Anything).Return(errors.New("commit error"))
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
@@ -900,7 +1399,7 @@ func TestPostService_UpdatePost(t *testing.T) {
 				ctx:    context.Background(),
 				userID: 1,
 				postID: 1,
-				post:   &model.UpdatePostDTO{Title: func() *string { s := "t"; return &s }()},
+				post:   &model.UpdatePostDTO{Title: testsupport.Ptr("Updated title")},
 			},
 			wantErr:     true,
 			wantErrType: custom_errors.ErrDatabaseQuery,
@@ -912,7 +1411,8 @@ func TestPostService_UpdatePost(t *testing.T) {
 			tagRepo := new(tag_repository_mock.Repository)
 			mediaRepo := new(media_repository_mock.Repository)
 			uow := new(postgres_mock.UnitOfWork)
-			userClient := new(user_client_mock.Client) // Not used in UpdatePost but part of service
+			userClient := new(user_client_mock.Client)
+			userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil).Maybe()
 			tx := new(postgres_mock.Transaction)
 			metrics := prometheus.NewPrometheusMetricsProvider()
 
@@ -920,16 +1420,30 @@ func TestPostService_UpdatePost(t *testing.T) {
 				tt.mocks(postRepo, tagRepo, mediaRepo, uow, tx)
 			}
 
-			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics)
-			err := s.UpdatePost(tt.args.ctx, tt.args.userID, tt.args.postID, tt.args.post)
+			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics, model.DefaultPostLimits())
+			got, err := s.UpdatePost(tt.args.ctx, tt.args.userID, tt.args.postID, tt.args.post)
 
 			if tt.wantErr {
 				assert.Error(t, err)
+				assert.Nil(t, got)
 				if tt.wantErrType != nil {
 					assert.True(t, errors.Is(err, tt.wantErrType), "expected error type %T, got %T", tt.wantErrType, err)
 				}
 			} else {
 				assert.NoError(t, err)
+				require.NotNil(t, got)
+				assert.Equal(t, "Updated Title", got.Post.Title)
+				assert.Equal(t, &model.User{ID: 1, Username: "testuser"}, got.Author)
+				assert.Len(t, got.Media, 1)
+				assert.Equal(t, []*model.Tag{{ID: 1, Name: "newtag"}}, got.Tags)
+				assert.Equal(t, &model.PostChangeSummary{
+					FieldsChanged: []string{"title"},
+					TagsAdded:     []*model.Tag{{ID: 1, Name: "newtag"}},
+					TagsCreated:   []*model.Tag{{ID: 1, Name: "newtag"}},
+					TagsRemoved:   []*model.Tag{{ID: 2, Name: "oldtag"}},
+					MediaAttached: got.Media,
+					MediaDetached: []int64{10},
+				}, got.Changes)
 			}
 
 			postRepo.AssertExpectations(t)
@@ -941,6 +1455,141 @@ func TestPostService_UpdatePost(t *testing.T) {
 	}
 }
 
+func TestPostService_UpdatePost_SkipsDetachForAPostWithoutMedia(t *testing.T) {
+	postRepo := new(post_repository_mock.Repository)
+	tagRepo := new(tag_repository_mock.Repository)
+	mediaRepo := new(media_repository_mock.Repository)
+	uow := new(postgres_mock.UnitOfWork)
+	tx := new(postgres_mock.Transaction)
+	userClient := new(user_client_mock.Client)
+	userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil).Maybe()
+
+	uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(tx, nil)
+	tx.On("PostRepository").Return(postRepo)
+	tx.On("MediaRepository").Return(mediaRepo)
+	tx.On("TagRepository").Return(tagRepo)
+	tx.On("Commit", mock.Anything).Return(nil)
+	postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+	postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+	postRepo.On("Touch", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+	mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil).Once()
+	mediaRepo.On("Attach", mock.Anything, int64(1), mock.MatchedBy(func(media []*model.PostMedia) bool {
+		return len(media) == 2
+	})).Return(nil)
+	mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{
+		{ID: 11, PostID: 1, URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+		{ID: 12, PostID: 1, URL: "https://example.com/2.jpg", Type: model.MediaTypeImage, Position: 2},
+	}, nil).Once()
+	tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{}, nil)
+
+	s := NewPostService(postRepo, tagRepo, mediaRepo, uow, logger.New("test"), userClient, prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+	got, err := s.UpdatePost(context.Background(), 1, 1, &model.UpdatePostDTO{
+		MediaItems: []*model.PostMediaInput{
+			{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+			{URL: "https://example.com/2.jpg", Type: model.MediaTypeImage, Position: 2},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, got.Media, 2)
+	assert.Equal(t, got.Media, got.Changes.MediaAttached)
+	assert.Empty(t, got.Changes.MediaDetached)
+	mediaRepo.AssertNotCalled(t, "Detach", mock.Anything, mock.Anything)
+	mediaRepo.AssertExpectations(t)
+}
+
+func TestPostService_UpdatePost_TouchesWithoutFieldChanges(t *testing.T) {
+	title := "Updated Title"
+	media := []*model.PostMediaInput{{URL: "https://example.com/new.jpg", Type: model.MediaTypeImage, Position: 1}}
+	tests := []struct {
+		name       string
+		update     *model.UpdatePostDTO
+		wantUpdate bool
+	}{
+		{name: "tags only", update: &model.UpdatePostDTO{Tags: []string{"go"}}},
+		{name: "media only", update: &model.UpdatePostDTO{MediaItems: media}},
+		{name: "cleared title and tags", update: &model.UpdatePostDTO{Title: new(string), Tags: []string{"go"}}, wantUpdate: true},
+		{name: "same title and tags", update: &model.UpdatePostDTO{Title: testsupport.Ptr("Title"), Tags: []string{"go"}}},
+		{name: "title, tags and media", update: &model.UpdatePostDTO{Title: &title, Tags: []string{"go"}, MediaItems: media}, wantUpdate: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTxTestDeps(t)
+			post := &model.Post{ID: 1, AuthorID: 1, Title: "Title", Content: testsupport.Ptr("Content")}
+			d.uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(d.tx, nil)
+			d.postRepo.On("GetByID", mock.Anything, int64(1)).Return(post, nil)
+			d.postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(post, nil)
+			if tt.wantUpdate {
+				d.postRepo.On("Update", mock.Anything, int64(1), tt.update).Return(&model.Post{ID: 1, AuthorID: 1, Title: title}, nil)
+				d.revisions.On("Record", mock.Anything, mock.Anything).Return(&model.PostRevision{PostID: 1, Revision: 1}, nil)
+				d.revisions.On("Prune", mock.Anything, int64(1), mock.Anything).Return(0, nil)
+			} else {
+				d.postRepo.On("Touch", mock.Anything, int64(1)).Return(post, nil)
+			}
+			if len(tt.update.MediaItems) > 0 {
+				d.mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 10}}, nil).Once()
+				d.mediaRepo.On("Detach", mock.Anything, []int64{10}).Return(nil)
+				d.mediaRepo.On("Attach", mock.Anything, int64(1), mock.Anything).Return(nil)
+			}
+			if len(tt.update.Tags) > 0 {
+				d.tagRepo.On("FindByNames", mock.Anything, []string{"go"}).Return([]*model.Tag{}, nil)
+				d.tagRepo.On("CreateMany", mock.Anything, []string{"go"}).Return([]*model.Tag{{ID: 1, Name: "go"}}, nil)
+				d.tagRepo.On("ReplacePostTags", mock.Anything, int64(1), []string{"go"}).Return(nil)
+			}
+			d.mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
+			d.tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{{ID: 1, Name: "go"}}, nil)
+			d.tx.On("Commit", mock.Anything).Return(nil)
+			d.tx.On("Rollback", mock.Anything).Return(nil).Maybe()
+			d.service.userClient.(*user_client_mock.Client).On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+
+			got, err := d.service.UpdatePost(context.Background(), 1, 1, tt.update)
+
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			d.postRepo.AssertExpectations(t)
+			d.mediaRepo.AssertExpectations(t)
+			d.tagRepo.AssertExpectations(t)
+			if tt.wantUpdate {
+				assert.Equal(t, title, got.Post.Title)
+				assert.Equal(t, int64(1), got.Post.EditCount)
+				d.postRepo.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything)
+			} else {
+				d.postRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+				d.revisions.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestPostService_UpdatePost_KeepsSomethingToShow(t *testing.T) {
+	s, _ := newMemoryService(t)
+	ctx := context.Background()
+	titleOnly, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Title only"})
+	require.NoError(t, err)
+	withMedia, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "With media",
+		MediaItems: []*model.PostMediaInput{{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1}}})
+	require.NoError(t, err)
+
+	_, err = s.UpdatePost(ctx, 1, titleOnly.Post.ID, &model.UpdatePostDTO{Title: testsupport.Ptr("")})
+	assert.ErrorIs(t, err, custom_errors.ErrPostValidation, "a post without a title, content or media is refused")
+	got, err := s.GetPostByID(ctx, titleOnly.Post.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Title only", got.Post.Title)
+
+	_, err = s.UpdatePost(ctx, 1, titleOnly.Post.ID, &model.UpdatePostDTO{Title: testsupport.Ptr(""), Content: testsupport.Ptr("Content")})
+	require.NoError(t, err, "content keeps the post worth showing")
+
+	cleared, err := s.UpdatePost(ctx, 1, withMedia.Post.ID, &model.UpdatePostDTO{Title: testsupport.Ptr("")})
+	require.NoError(t, err, "media keep the post worth showing")
+	assert.Empty(t, cleared.Post.Title)
+
+	_, err = s.UpdatePost(ctx, 1, withMedia.Post.ID, &model.UpdatePostDTO{Title: testsupport.Ptr("")})
+	assert.ErrorIs(t, err, model.ErrEmptyUpdate, "clearing a title the post does not have changes nothing")
+	got, err = s.GetPostByID(ctx, withMedia.Post.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, cleared.Post.Version, got.Post.Version)
+}
+
 func TestPostService_DeletePost(t *testing.T) {
 	log := logger.New("test")
 	type args struct {
@@ -964,6 +1613,7 @@ func TestPostService_DeletePost(t *testing.T) {
 				tx.On("TagRepository").Return(tagRepo)
 
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
 				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 10}}, nil)
 				mediaRepo.On("Detach", mock.Anything, []int64{10}).Return(nil)
 				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{{ID: 1, Name: "tag1"}}, nil)
@@ -987,7 +1637,8 @@ func TestPostService_DeletePost(t *testing.T) {
 				tx.On("TagRepository").Return(tagRepo)
 
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrMediaNotFound) // No media
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil) // No media
 				// Detach should not be called
 				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{{ID: 1, Name: "tag1"}}, nil)
 				tagRepo.On("UntagPost", mock.Anything, int64(1), []string{"tag1"}).Return(nil)
@@ -1010,6 +1661,7 @@ func TestPostService_DeletePost(t *testing.T) {
 				tx.On("TagRepository").Return(tagRepo)
 
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
 				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 10}}, nil)
 				mediaRepo.On("Detach", mock.Anything, []int64{10}).Return(nil)
 				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagsNotFound) // No tags
@@ -1027,6 +1679,7 @@ func TestPostService_DeletePost(t *testing.T) {
 		{
 			name: "Error begin transaction",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
 				uow.On("Begin", mock.Anything).Return(nil, errors.New("db error"))
 			},
 			args: args{
@@ -1040,11 +1693,23 @@ func TestPostService_DeletePost(t *testing.T) {
 		{
 			name: "Error GetByID post not found",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				postRepo.On("GetByID", mock.Anything, int64(1)).Return(nil, custom_errors.ErrPostNotFound)
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 1,
+				postID: 1,
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrPostNotFound,
+		},
+		{
+			name: "Error post deleted concurrently",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
-				tx.On("MediaRepository").Return(mediaRepo) // For defer
-				tx.On("TagRepository").Return(tagRepo)     // For defer
-				postRepo.On("GetByID", mock.Anything, int64(1)).Return(nil, custom_errors.ErrPostNotFound)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(nil, custom_errors.ErrPostNotFound)
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
 			args: args{
@@ -1058,13 +1723,7 @@ func TestPostService_DeletePost(t *testing.T) {
 		{
 			name: "Error user is not author",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
-				uow.On("Begin", mock.Anything).Return(tx, nil)
-				tx.On("PostRepository").Return(postRepo)
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 2}, nil) // Different AuthorID
-				// Важно: эти методы должны быть доступны до их вызова в defer
-				tx.On("MediaRepository").Return(mediaRepo) // Для defer
-				tx.On("TagRepository").Return(tagRepo)     // Для defer
-				tx.On("Rollback", mock.Anything).Return(nil)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -1075,13 +1734,13 @@ func TestPostService_DeletePost(t *testing.T) {
 			wantErrType: custom_errors.ErrForbidden,
 		},
 		{
-			name: "Error getting media for post (not ErrMediaNotFound)",
+			name: "Error getting media for post",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
 				tx.On("MediaRepository").Return(mediaRepo)
-				tx.On("TagRepository").Return(tagRepo) // For defer
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
 				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return(nil, errors.New("db error"))
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
@@ -1094,13 +1753,13 @@ func TestPostService_DeletePost(t *testing.T) {
 			wantErrType: custom_errors.ErrMediaQueryFailed,
 		},
 		{
-			name: "Error detaching media (not ErrMediaNotFound)",
+			name: "Error detaching media",
 			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
 				uow.On("Begin", mock.Anything).Return(tx, nil)
 				tx.On("PostRepository").Return(postRepo)
 				tx.On("MediaRepository").Return(mediaRepo)
-				tx.On("TagRepository").Return(tagRepo) // For defer
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
 				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{{ID: 10}}, nil)
 				mediaRepo.On("Detach", mock.Anything, []int64{10}).Return(errors.New("detach error"))
 				tx.On("Rollback", mock.Anything).Return(nil)
@@ -1121,7 +1780,8 @@ func TestPostService_DeletePost(t *testing.T) {
 				tx.On("MediaRepository").Return(mediaRepo)
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrMediaNotFound) // No media, proceed
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil) // No media, proceed
 				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, errors.New("db error"))
 				tx.On("Rollback", mock.Anything).Return(nil)
 			},
@@ -1141,7 +1801,8 @@ func TestPostService_DeletePost(t *testing.T) {
 				tx.On("MediaRepository").Return(mediaRepo)
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrMediaNotFound) // No media
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil) // No media
 				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return([]*model.Tag{{ID: 1, Name: "tag1"}}, nil)
 				tagRepo.On("UntagPost", mock.Anything, int64(1), []string{"tag1"}).Return(errors.New("untag error"))
 				tx.On("Rollback", mock.Anything).Return(nil)
@@ -1162,7 +1823,8 @@ func TestPostService_DeletePost(t *testing.T) {
 				tx.On("MediaRepository").Return(mediaRepo)
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrMediaNotFound)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
 				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagsNotFound)
 				postRepo.On("Delete", mock.Anything, int64(1)).Return(custom_errors.ErrDatabaseQuery)
 				tx.On("Rollback", mock.Anything).Return(nil)
@@ -1183,7 +1845,8 @@ func TestPostService_DeletePost(t *testing.T) {
 				tx.On("MediaRepository").Return(mediaRepo)
 				tx.On("TagRepository").Return(tagRepo)
 				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
-				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrMediaNotFound)
+				postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+				mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
 				tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagsNotFound)
 				postRepo.On("Delete", mock.Anything, int64(1)).Return(nil)
 				tx.On("Commit", mock.Anything).Return(errors.New("commit error"))
@@ -1212,7 +1875,7 @@ func TestPostService_DeletePost(t *testing.T) {
 				tt.mocks(postRepo, tagRepo, mediaRepo, uow, tx)
 			}
 
-			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics)
+			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics, model.DefaultPostLimits())
 			err := s.DeletePost(tt.args.ctx, tt.args.userID, tt.args.postID)
 
 			if tt.wantErr {
@@ -1232,3 +1895,221 @@ func TestPostService_DeletePost(t *testing.T) {
 		})
 	}
 }
+
+func TestPostService_PublishPost(t *testing.T) {
+	log := logger.New("test")
+	type args struct {
+		ctx    context.Context
+		userID int64
+		postID int64
+	}
+	tests := []struct {
+		name        string
+		mocks       func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client)
+		args        args
+		wantErr     bool
+		wantErrType error
+	}{
+		{
+			name: "Success publishes draft",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1, Status: model.PostStatusDraft}, nil).Once()
+				postRepo.On("Publish", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1, Status: model.PostStatusPublished}, nil).Once()
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(&model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: 1, Status: model.PostStatusPublished}}, nil).Once()
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 1,
+				postID: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Already published is a no-op",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1, Status: model.PostStatusPublished}, nil)
+				postRepo.On("GetDetailedByID", mock.Anything, int64(1)).Return(&model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: 1, Status: model.PostStatusPublished}}, nil)
+				userClient.On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1, Username: "testuser"}, nil)
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 1,
+				postID: 1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Error forbidden for other user",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1, Status: model.PostStatusDraft}, nil)
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 2,
+				postID: 1,
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrForbidden,
+		},
+		{
+			name: "Error post not found",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				postRepo.On("GetByID", mock.Anything, int64(1)).Return(nil, custom_errors.ErrPostNotFound)
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 1,
+				postID: 1,
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrPostNotFound,
+		},
+		{
+			name: "Error publishing post",
+			mocks: func(postRepo *post_repository_mock.Repository, mediaRepo *media_repository_mock.Repository, tagRepo *tag_repository_mock.Repository, userClient *user_client_mock.Client) {
+				postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1, Status: model.PostStatusDraft}, nil)
+				postRepo.On("Publish", mock.Anything, int64(1)).Return(nil, errors.New("db error"))
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 1,
+				postID: 1,
+			},
+			wantErr:     true,
+			wantErrType: custom_errors.ErrDatabaseQuery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postRepo := new(post_repository_mock.Repository)
+			tagRepo := new(tag_repository_mock.Repository)
+			mediaRepo := new(media_repository_mock.Repository)
+			userClient := new(user_client_mock.Client)
+			uow := new(postgres_mock.UnitOfWork)
+			metrics := prometheus.NewPrometheusMetricsProvider()
+
+			if tt.mocks != nil {
+				tt.mocks(postRepo, mediaRepo, tagRepo, userClient)
+			}
+
+			s := NewPostService(postRepo, tagRepo, mediaRepo, uow, log, userClient, metrics, model.DefaultPostLimits())
+			got, err := s.PublishPost(tt.args.ctx, tt.args.userID, tt.args.postID)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				if tt.wantErrType != nil {
+					assert.True(t, errors.Is(err, tt.wantErrType), "expected error type %T, got %T", tt.wantErrType, err)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, model.PostStatusPublished, got.Post.Status)
+			}
+
+			postRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostService_GetPostTags(t *testing.T) {
+	log := logger.New("test")
+	authorID := int64(1)
+	stranger := int64(2)
+	count := func(n int64) *int64 { return &n }
+
+	tests := []struct {
+		name          string
+		mocks         func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository)
+		requesterID   *int64
+		includeCounts bool
+		want          []*model.Tag
+		wantErrType   error
+	}{
+		{
+			name: "Success without counts",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository) {
+				postRepo.On("GetByID", mock.Anything, int64(10)).Return(&model.Post{ID: 10, AuthorID: authorID, Status: model.PostStatusPublished}, nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(10)).Return([]*model.Tag{{ID: 1, Name: "go"}, {ID: 2, Name: "grpc"}}, nil)
+			},
+			want: []*model.Tag{{ID: 1, Name: "go"}, {ID: 2, Name: "grpc"}},
+		},
+		{
+			name: "Success with counts uses one grouped query",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository) {
+				postRepo.On("GetByID", mock.Anything, int64(10)).Return(&model.Post{ID: 10, AuthorID: authorID, Status: model.PostStatusPublished}, nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(10)).Return([]*model.Tag{{ID: 1, Name: "go"}, {ID: 2, Name: "grpc"}}, nil)
+				tagRepo.On("CountPosts", mock.Anything, []int64{1, 2}).Return(map[int64]int64{1: 42, 2: 1}, nil).Once()
+			},
+			includeCounts: true,
+			want:          []*model.Tag{{ID: 1, Name: "go", PostCount: count(42)}, {ID: 2, Name: "grpc", PostCount: count(1)}},
+		},
+		{
+			name: "Success post without tags returns empty list",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository) {
+				postRepo.On("GetByID", mock.Anything, int64(10)).Return(&model.Post{ID: 10, AuthorID: authorID, Status: model.PostStatusPublished}, nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(10)).Return(nil, nil)
+			},
+			includeCounts: true,
+			want:          []*model.Tag{},
+		},
+		{
+			name: "Success draft visible to author",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository) {
+				postRepo.On("GetByID", mock.Anything, int64(10)).Return(&model.Post{ID: 10, AuthorID: authorID, Status: model.PostStatusDraft}, nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(10)).Return([]*model.Tag{{ID: 1, Name: "go"}}, nil)
+			},
+			requesterID: &authorID,
+			want:        []*model.Tag{{ID: 1, Name: "go"}},
+		},
+		{
+			name: "Error draft hidden from other users",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository) {
+				postRepo.On("GetByID", mock.Anything, int64(10)).Return(&model.Post{ID: 10, AuthorID: authorID, Status: model.PostStatusDraft}, nil)
+			},
+			requesterID: &stranger,
+			wantErrType: custom_errors.ErrPostNotFound,
+		},
+		{
+			name: "Error post not found",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository) {
+				postRepo.On("GetByID", mock.Anything, int64(10)).Return(nil, custom_errors.ErrPostNotFound)
+			},
+			wantErrType: custom_errors.ErrPostNotFound,
+		},
+		{
+			name: "Error counting posts",
+			mocks: func(postRepo *post_repository_mock.Repository, tagRepo *tag_repository_mock.Repository) {
+				postRepo.On("GetByID", mock.Anything, int64(10)).Return(&model.Post{ID: 10, AuthorID: authorID, Status: model.PostStatusPublished}, nil)
+				tagRepo.On("FindByPost", mock.Anything, int64(10)).Return([]*model.Tag{{ID: 1, Name: "go"}}, nil)
+				tagRepo.On("CountPosts", mock.Anything, []int64{1}).Return(nil, custom_errors.ErrTagQueryFailed)
+			},
+			includeCounts: true,
+			wantErrType:   custom_errors.ErrTagQueryFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postRepo := new(post_repository_mock.Repository)
+			tagRepo := new(tag_repository_mock.Repository)
+			metrics := prometheus.NewPrometheusMetricsProvider()
+
+			if tt.mocks != nil {
+				tt.mocks(postRepo, tagRepo)
+			}
+
+			s := NewPostService(postRepo, tagRepo, new(media_repository_mock.Repository), new(postgres_mock.UnitOfWork), log, new(user_client_mock.Client), metrics, model.DefaultPostLimits())
+			got, err := s.GetPostTags(context.Background(), 10, tt.requesterID, tt.includeCounts)
+
+			if tt.wantErrType != nil {
+				assert.True(t, errors.Is(err, tt.wantErrType), "expected %v, got %v", tt.wantErrType, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			postRepo.AssertExpectations(t)
+			tagRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package post_service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+func TestPostService_CreatePost_Source(t *testing.T) {
+	tests := []struct {
+		name   string
+		source model.PostSource
+		want   model.PostSource
+	}{
+		{name: "unset", want: model.PostSourceUnknown},
+		{name: "allowed", source: "ios", want: model.PostSourceIOS},
+		{name: "canonicalized", source: " Android ", want: model.PostSourceAndroid},
+		{name: "not allowed", source: "smart-tv", want: model.PostSourceUnknown},
+		{name: "malformed", source: "smart tv!", want: model.PostSourceUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, database := newScheduleService(t)
+
+			created, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Post", Source: tt.source})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, created.Post.Source)
+			stored, err := database.Posts.GetByID(context.Background(), created.Post.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, stored.Source)
+		})
+	}
+}
+
+func TestPostService_BulkCreatePosts_Source(t *testing.T) {
+	s, _ := newScheduleService(t)
+
+	result, err := s.BulkCreatePosts(context.Background(), 99, []*model.CreatePostDTO{
+		{AuthorID: 1, Title: "Imported"},
+		{AuthorID: 1, Title: "From the web", Source: model.PostSourceWeb},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Items, 2)
+	require.NoError(t, result.Items[0].Err)
+	require.NoError(t, result.Items[1].Err)
+	assert.Equal(t, model.PostSourceImport, result.Items[0].Post.Source)
+	assert.Equal(t, model.PostSourceWeb, result.Items[1].Post.Source)
+}
+
+func TestPostService_ListPosts_Source(t *testing.T) {
+	s, _ := newScheduleService(t)
+	for _, source := range []model.PostSource{"web", "ios", "", "web"} {
+		_, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Post", Source: source})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name    string
+		source  model.PostSource
+		want    []int64
+		wantErr error
+	}{
+		{name: "web", source: "web", want: []int64{4, 1}},
+		{name: "canonicalized", source: "IOS", want: []int64{2}},
+		{name: "unknown", source: "unknown", want: []int64{3}},
+		{name: "not allowed", source: "smart-tv", wantErr: custom_errors.ErrInvalidInput},
+		{name: "malformed", source: "smart tv!", wantErr: custom_errors.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source
+			got, _, err := s.ListPosts(context.Background(), &model.PostFilters{Source: &source})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]int64, len(got))
+			for i, post := range got {
+				ids[i] = post.Post.ID
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
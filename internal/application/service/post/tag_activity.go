@@ -0,0 +1,31 @@
+package post_service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// HasPostsSince reports which of tagNames have a post ListPosts would show anonymously that
+// became visible after since, for clients polling the tags a user follows. Names are normalized
+// first and the answer is keyed by the normalized names.
+func (s *PostService) HasPostsSince(ctx context.Context, tagNames []string, since time.Time) (result *model.TagActivity, err error) {
+	defer observePostOperation(s.metrics, "has_posts_since", time.Now(), &err)
+
+	tagNames = model.NormalizeTags(tagNames)
+	if err := model.ValidateTagPoll(tagNames, since); err != nil {
+		return nil, err
+	}
+
+	newest, err := s.postRepo.NewestByTagsSince(ctx, tagNames, since)
+	if err != nil {
+		s.log.Error("Failed to get newest posts by tags", slog.Int("tags", len(tagNames)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return model.NewTagActivity(tagNames, newest, since), nil
+}
@@ -0,0 +1,292 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/testsupport"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_service_mock "pinstack-post-service/mocks/post"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+)
+
+func TestPostService_HasPostsSince(t *testing.T) {
+	ctx := context.Background()
+	s, database := newMemoryService(t)
+	_, err := database.Tags.CreateMany(ctx, []string{"go", "rust", "quiet"})
+	require.NoError(t, err)
+	create := func(post *model.Post, tags ...string) *model.Post {
+		created, err := database.Posts.Create(ctx, post)
+		require.NoError(t, err)
+		require.NoError(t, database.Tags.TagPost(ctx, created.ID, tags))
+		return created
+	}
+	old := create(testsupport.NewPostBuilder().Build(), "go", "quiet")
+	time.Sleep(time.Millisecond)
+	newest := create(testsupport.NewPostBuilder().Build(), "go", "rust")
+	create(testsupport.NewPostBuilder().WithStatus(model.PostStatusDraft).Build(), "quiet")
+
+	got, err := s.HasPostsSince(ctx, []string{"Go", " RUST ", "quiet", "go"}, old.CreatedAt.Time)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"go": true, "rust": true, "quiet": false}, got.HasPosts, "names are normalized and drafts are not new posts")
+	assert.True(t, got.Newest.Equal(newest.CreatedAt.Time))
+	assert.False(t, got.Cached)
+
+	got, err = s.HasPostsSince(ctx, []string{"go"}, newest.CreatedAt.Time)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"go": false}, got.HasPosts)
+	assert.True(t, got.Newest.IsZero())
+}
+
+func TestPostService_HasPostsSince_Validation(t *testing.T) {
+	s := NewPostService(new(post_service_mock.Repository), nil, nil, new(postgres_mock.UnitOfWork), logger.New("test"), nil,
+		prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+	tooMany := make([]string, model.MaxPolledTags+1)
+	for i := range tooMany {
+		tooMany[i] = "tag-" + strings.Repeat("x", i+1)
+	}
+
+	tests := []struct {
+		name     string
+		tagNames []string
+		since    time.Time
+	}{
+		{name: "no tags", since: testsupport.FixedTime},
+		{name: "too many tags", tagNames: tooMany, since: testsupport.FixedTime},
+		{name: "blank tag", tagNames: []string{"go", "  "}, since: testsupport.FixedTime},
+		{name: "no since", tagNames: []string{"go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.HasPostsSince(context.Background(), tt.tagNames, tt.since)
+			assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+		})
+	}
+}
+
+func TestPostService_HasPostsSince_DatabaseError(t *testing.T) {
+	postRepo := new(post_service_mock.Repository)
+	postRepo.On("NewestByTagsSince", mock.Anything, []string{"go"}, testsupport.FixedTime).Return(nil, errors.New("connection reset"))
+	s := NewPostService(postRepo, nil, nil, new(postgres_mock.UnitOfWork), logger.New("test"), nil,
+		prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+
+	got, err := s.HasPostsSince(context.Background(), []string{"go"}, testsupport.FixedTime)
+
+	assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Nil(t, got)
+}
+
+func TestPostServiceCacheDecorator_HasPostsSince(t *testing.T) {
+	since := testsupport.FixedTime
+	after := since.Add(time.Minute)
+	before := since.Add(-time.Minute)
+
+	t.Run("AnsweredFromCache", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		postCache.On("GetTagLatestPosts", mock.Anything, []string{"go", "rust"}).
+			Return(map[string]time.Time{"go": after, "rust": before}, nil).Once()
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+			logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+		got, err := d.HasPostsSince(context.Background(), []string{"Go", "rust"}, since)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"go": true, "rust": false}, got.HasPosts)
+		assert.True(t, got.Newest.Equal(after))
+		assert.True(t, got.Cached)
+		service.AssertNotCalled(t, "HasPostsSince", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ColdCacheAsksOnlyForMissingTags", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		later := after.Add(time.Minute)
+		postCache.On("GetTagLatestPosts", mock.Anything, []string{"go", "rust", "quiet"}).
+			Return(map[string]time.Time{"go": after}, nil).Once()
+		service.On("HasPostsSince", mock.Anything, []string{"rust", "quiet"}, since).Return(&model.TagActivity{
+			HasPosts: map[string]bool{"rust": true, "quiet": false},
+			Newest:   later,
+			Latest:   map[string]time.Time{"rust": later},
+		}, nil).Once()
+		postCache.On("AdvanceTagLatestPosts", mock.Anything, map[string]time.Time{"rust": later}).Return(nil).Once()
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+			logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+		got, err := d.HasPostsSince(context.Background(), []string{"go", "rust", "quiet"}, since)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"go": true, "rust": true, "quiet": false}, got.HasPosts)
+		assert.True(t, got.Newest.Equal(later))
+		assert.False(t, got.Cached)
+		service.AssertExpectations(t)
+		postCache.AssertExpectations(t)
+	})
+
+	t.Run("CacheFailureFallsBackToTheService", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		postCache.On("GetTagLatestPosts", mock.Anything, []string{"go"}).Return(nil, errors.New("redis: connection refused")).Once()
+		answer := &model.TagActivity{HasPosts: map[string]bool{"go": false}}
+		service.On("HasPostsSince", mock.Anything, []string{"go"}, since).Return(answer, nil).Once()
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+			logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+		got, err := d.HasPostsSince(context.Background(), []string{"go"}, since)
+
+		require.NoError(t, err)
+		assert.Equal(t, answer, got)
+		postCache.AssertNotCalled(t, "AdvanceTagLatestPosts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidPollSkipsTheCache", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		service.On("HasPostsSince", mock.Anything, []string(nil), since).Return(nil, custom_errors.ErrInvalidInput).Once()
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+			logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+		_, err := d.HasPostsSince(context.Background(), nil, since)
+
+		assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+		postCache.AssertNotCalled(t, "GetTagLatestPosts", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPostServiceCacheDecorator_CreatePost_AdvancesTagLatestPosts(t *testing.T) {
+	createdAt := testsupport.FixedTime
+	tests := []struct {
+		name        string
+		post        *model.Post
+		wantAdvance bool
+	}{
+		{name: "listed post", post: &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusPublished, Visibility: model.PostVisibilityPublic}, wantAdvance: true},
+		{name: "private post", post: &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusPublished, Visibility: model.PostVisibilityPrivate}},
+		{name: "draft", post: &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusDraft}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_service_mock.Service)
+			batcher := new(cache_mock.CacheBatcher)
+			batch := new(cache_mock.CacheBatch)
+			tt.post.CreatedAt = testsupport.Timestamptz(createdAt)
+			userCache := new(cache_mock.UserCache)
+			userCache.On("GetFreshUser", mock.Anything, int64(1)).Return(nil, custom_errors.ErrCacheMiss)
+			dto := &model.CreatePostDTO{AuthorID: 1, Title: "Tagged"}
+			created := &model.PostDetailed{Post: tt.post, Tags: []*model.Tag{{ID: 1, Name: "go"}, {ID: 2, Name: "rust"}}}
+			service.On("CreatePost", mock.Anything, mock.Anything).Return(created, nil)
+			batcher.On("NewBatch").Return(batch)
+			batch.On("AdjustUserPostCount", mock.Anything, mock.Anything).Maybe()
+			batch.On("SetPost", created).Once()
+			batch.On("AddRecentPost", int64(1), int64(10)).Once()
+			if tt.wantAdvance {
+				batch.On("AdvanceTagLatestPost", "go", createdAt).Once()
+				batch.On("AdvanceTagLatestPost", "rust", createdAt).Once()
+			}
+			batch.On("Exec", mock.Anything).Return(nil).Once()
+			d := NewPostServiceCacheDecorator(service, userCache, new(cache_mock.PostCache), batcher,
+				logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			_, err := d.CreatePost(context.Background(), dto)
+
+			require.NoError(t, err)
+			batch.AssertExpectations(t)
+			if !tt.wantAdvance {
+				batch.AssertNotCalled(t, "AdvanceTagLatestPost", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestPostServiceCacheDecorator_PublishPost_AdvancesTagLatestPosts(t *testing.T) {
+	createdAt := testsupport.FixedTime
+	publishedAt := createdAt.Add(time.Hour)
+	service := new(post_service_mock.Service)
+	batcher := new(cache_mock.CacheBatcher)
+	batch := new(cache_mock.CacheBatch)
+	published := &model.PostDetailed{
+		Post: &model.Post{ID: 10, AuthorID: 1, Status: model.PostStatusPublished, Visibility: model.PostVisibilityPublic,
+			CreatedAt: testsupport.Timestamptz(createdAt), PublishedAt: testsupport.Timestamptz(publishedAt)},
+		Tags: []*model.Tag{{ID: 1, Name: "go"}},
+	}
+	service.On("PublishPost", mock.Anything, int64(1), int64(10)).Return(published, nil)
+	batcher.On("NewBatch").Return(batch)
+	batch.On("DeletePost", int64(10)).Once()
+	batch.On("InvalidateUserPostsMeta", int64(1)).Once()
+	batch.On("AdvanceTagLatestPost", "go", publishedAt).Once()
+	batch.On("Exec", mock.Anything).Return(nil).Once()
+	d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), new(cache_mock.PostCache), batcher,
+		logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	_, err := d.PublishPost(context.Background(), 1, 10)
+
+	require.NoError(t, err)
+	batch.AssertExpectations(t)
+}
+
+func TestPostServiceCacheDecorator_UpdatePost_AdvancesTagLatestPosts(t *testing.T) {
+	publishedAt := testsupport.FixedTime
+	goTag, rustTag := &model.Tag{ID: 1, Name: "go"}, &model.Tag{ID: 2, Name: "rust"}
+	tests := []struct {
+		name        string
+		visibility  model.PostVisibility
+		changes     *model.PostChangeSummary
+		wantAdvance []string
+	}{
+		{name: "gained tags", visibility: model.PostVisibilityPublic,
+			changes: &model.PostChangeSummary{TagsAdded: []*model.Tag{rustTag}}, wantAdvance: []string{"rust"}},
+		{name: "made public", visibility: model.PostVisibilityPublic,
+			changes: &model.PostChangeSummary{FieldsChanged: []string{"visibility"}}, wantAdvance: []string{"go", "rust"}},
+		{name: "gained tags while private", visibility: model.PostVisibilityPrivate,
+			changes: &model.PostChangeSummary{TagsAdded: []*model.Tag{rustTag}}},
+		{name: "title only", visibility: model.PostVisibilityPublic,
+			changes: &model.PostChangeSummary{FieldsChanged: []string{"title"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_service_mock.Service)
+			postCache := new(cache_mock.PostCache)
+			batcher := new(cache_mock.CacheBatcher)
+			dto := &model.UpdatePostDTO{UserID: 1}
+			updated := &model.PostDetailed{
+				Post: &model.Post{ID: 3, AuthorID: 1, Status: model.PostStatusPublished, Visibility: tt.visibility,
+					PublishedAt: testsupport.Timestamptz(publishedAt)},
+				Author:  &model.User{ID: 1},
+				Tags:    []*model.Tag{goTag, rustTag},
+				Changes: tt.changes,
+			}
+			service.On("UpdatePost", mock.Anything, int64(1), int64(3), dto).Return(updated, nil)
+			postCache.On("SetPost", mock.Anything, updated).Return(nil).Once()
+			if len(tt.wantAdvance) > 0 {
+				batch := new(cache_mock.CacheBatch)
+				batcher.On("NewBatch").Return(batch).Once()
+				for _, name := range tt.wantAdvance {
+					batch.On("AdvanceTagLatestPost", name, publishedAt).Once()
+				}
+				batch.On("Exec", mock.Anything).Return(nil).Once()
+				defer batch.AssertExpectations(t)
+			}
+			d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, batcher,
+				logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			_, err := d.UpdatePost(context.Background(), 1, 3, dto)
+
+			require.NoError(t, err)
+			if len(tt.wantAdvance) == 0 {
+				batcher.AssertNotCalled(t, "NewBatch")
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	model "pinstack-post-service/internal/domain/models"
+	tag_repository "pinstack-post-service/internal/domain/ports/output/tag"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// RenameTag gives a tag a new name. When another tag already has that name, the tag is
+// merged into it instead, so "golnag" renamed to "golang" joins the existing "golang".
+func (s *PostService) RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error) {
+	name = model.NormalizeTagName(name)
+	if err := model.ValidateTagName(name); err != nil {
+		s.metrics.IncrementTagOperations("rename_tag", false)
+		s.log.Debug("Tag rename validation failed", slog.Int64("tag_id", tagID), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return s.changeTags(ctx, "rename_tag", func(tagRepo tag_repository.Repository) (*model.TagChange, error) {
+		existing, err := tagRepo.FindByNames(ctx, []string{name})
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 && existing[0].ID == tagID {
+			s.log.Debug("Tag already has the requested name", slog.Int64("tag_id", tagID))
+			return &model.TagChange{Tag: existing[0]}, nil
+		}
+
+		affected, err := tagRepo.FindPostIDsByTags(ctx, []int64{tagID})
+		if err != nil {
+			return nil, err
+		}
+
+		var tag *model.Tag
+		if len(existing) > 0 {
+			s.log.Info("Rename target exists, merging tags", slog.Int64("tag_id", tagID), slog.Int64("dest_id", existing[0].ID))
+			tag, err = tagRepo.Merge(ctx, []int64{tagID}, existing[0].ID)
+		} else {
+			tag, err = tagRepo.Rename(ctx, tagID, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &model.TagChange{Tag: tag, AffectedPostIDs: affected}, nil
+	})
+}
+
+// MergeTags moves every post from the source tags to destID and deletes the source tags.
+func (s *PostService) MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error) {
+	sources := make([]int64, 0, len(sourceIDs))
+	seen := make(map[int64]bool, len(sourceIDs))
+	for _, id := range sourceIDs {
+		if id == destID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		sources = append(sources, id)
+	}
+	if destID <= 0 || len(sources) == 0 {
+		s.metrics.IncrementTagOperations("merge_tags", false)
+		s.log.Debug("Nothing to merge", slog.Int64("dest_id", destID), slog.Any("source_ids", sourceIDs))
+		return nil, custom_errors.ErrInvalidInput
+	}
+
+	return s.changeTags(ctx, "merge_tags", func(tagRepo tag_repository.Repository) (*model.TagChange, error) {
+		affected, err := tagRepo.FindPostIDsByTags(ctx, sources)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := tagRepo.Merge(ctx, sources, destID)
+		if err != nil {
+			return nil, err
+		}
+		return &model.TagChange{Tag: tag, AffectedPostIDs: affected}, nil
+	})
+}
+
+// changeTags runs a tag admin operation in its own transaction and maps repository errors.
+func (s *PostService) changeTags(
+	ctx context.Context,
+	operation string,
+	change func(tagRepo tag_repository.Repository) (*model.TagChange, error),
+) (*model.TagChange, error) {
+	tx, err := s.uow.Begin(ctx)
+	if err != nil {
+		s.metrics.IncrementTagOperations(operation, false)
+		logTxError(ctx, s.log, "Failed to start transaction", err)
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	var txCommitted bool
+	defer func() {
+		if !txCommitted && tx != nil {
+			rollbackTx(ctx, tx, s.log)
+		}
+	}()
+
+	result, err := change(tx.TagRepository())
+	if err != nil {
+		s.metrics.IncrementTagOperations(operation, false)
+		switch {
+		case errors.Is(err, custom_errors.ErrTagNotFound):
+			s.log.Debug("Tag not found", slog.String("operation", operation))
+			return nil, custom_errors.ErrTagNotFound
+		case errors.Is(err, custom_errors.ErrTagAlreadyExists):
+			s.log.Debug("Tag name taken concurrently", slog.String("operation", operation))
+			return nil, custom_errors.ErrTagAlreadyExists
+		default:
+			s.log.Error("Failed to change tags", slog.String("operation", operation), slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+	}
+
+	if err = commitTx(ctx, tx, s.log, "Failed to commit transaction", slog.String("operation", operation)); err != nil {
+		s.metrics.IncrementTagOperations(operation, false)
+		return nil, err
+	}
+	txCommitted = true
+
+	s.metrics.IncrementTagOperations(operation, true)
+	s.log.Info("Tags changed",
+		slog.String("operation", operation),
+		slog.Int64("tag_id", result.Tag.ID),
+		slog.Int("affected_posts", len(result.AffectedPostIDs)))
+	return result, nil
+}
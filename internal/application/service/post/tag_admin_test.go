@@ -0,0 +1,214 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	media_repository_mock "pinstack-post-service/mocks/media"
+	post_repository_mock "pinstack-post-service/mocks/post"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+	tag_repository_mock "pinstack-post-service/mocks/tag"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+func TestPostService_RenameTag(t *testing.T) {
+	log := logger.New("test")
+	tests := []struct {
+		name        string
+		mocks       func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction)
+		tagID       int64
+		newName     string
+		want        *model.TagChange
+		wantErrType error
+	}{
+		{
+			name: "Success renames tag",
+			mocks: func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("TagRepository").Return(tagRepo)
+				tagRepo.On("FindByNames", mock.Anything, []string{"golang"}).Return(nil, nil)
+				tagRepo.On("FindPostIDsByTags", mock.Anything, []int64{5}).Return([]int64{1, 2}, nil)
+				tagRepo.On("Rename", mock.Anything, int64(5), "golang").Return(&model.Tag{ID: 5, Name: "golang"}, nil)
+				tx.On("Commit", mock.Anything).Return(nil)
+			},
+			tagID:   5,
+			newName: " GoLang ",
+			want:    &model.TagChange{Tag: &model.Tag{ID: 5, Name: "golang"}, AffectedPostIDs: []int64{1, 2}},
+		},
+		{
+			name: "Success merges into existing tag",
+			mocks: func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("TagRepository").Return(tagRepo)
+				tagRepo.On("FindByNames", mock.Anything, []string{"golang"}).Return([]*model.Tag{{ID: 7, Name: "golang"}}, nil)
+				tagRepo.On("FindPostIDsByTags", mock.Anything, []int64{5}).Return([]int64{3}, nil)
+				tagRepo.On("Merge", mock.Anything, []int64{5}, int64(7)).Return(&model.Tag{ID: 7, Name: "golang"}, nil)
+				tx.On("Commit", mock.Anything).Return(nil)
+			},
+			tagID:   5,
+			newName: "golang",
+			want:    &model.TagChange{Tag: &model.Tag{ID: 7, Name: "golang"}, AffectedPostIDs: []int64{3}},
+		},
+		{
+			name: "Success same name is a no-op",
+			mocks: func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("TagRepository").Return(tagRepo)
+				tagRepo.On("FindByNames", mock.Anything, []string{"golang"}).Return([]*model.Tag{{ID: 5, Name: "golang"}}, nil)
+				tx.On("Commit", mock.Anything).Return(nil)
+			},
+			tagID:   5,
+			newName: "golang",
+			want:    &model.TagChange{Tag: &model.Tag{ID: 5, Name: "golang"}},
+		},
+		{
+			name:        "Error validation invalid name",
+			tagID:       5,
+			newName:     "c++",
+			wantErrType: custom_errors.ErrPostValidation,
+		},
+		{
+			name: "Error tag not found",
+			mocks: func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("TagRepository").Return(tagRepo)
+				tagRepo.On("FindByNames", mock.Anything, []string{"golang"}).Return(nil, nil)
+				tagRepo.On("FindPostIDsByTags", mock.Anything, []int64{5}).Return(nil, nil)
+				tagRepo.On("Rename", mock.Anything, int64(5), "golang").Return(nil, custom_errors.ErrTagNotFound)
+				tx.On("Rollback", mock.Anything).Return(nil)
+			},
+			tagID:       5,
+			newName:     "golang",
+			wantErrType: custom_errors.ErrTagNotFound,
+		},
+		{
+			name: "Error commit failed",
+			mocks: func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("TagRepository").Return(tagRepo)
+				tagRepo.On("FindByNames", mock.Anything, []string{"golang"}).Return(nil, nil)
+				tagRepo.On("FindPostIDsByTags", mock.Anything, []int64{5}).Return([]int64{1}, nil)
+				tagRepo.On("Rename", mock.Anything, int64(5), "golang").Return(&model.Tag{ID: 5, Name: "golang"}, nil)
+				tx.On("Commit", mock.Anything).Return(errors.New("connection reset"))
+				tx.On("Rollback", mock.Anything).Return(nil)
+			},
+			tagID:       5,
+			newName:     "golang",
+			wantErrType: custom_errors.ErrDatabaseQuery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagRepo := new(tag_repository_mock.Repository)
+			uow := new(postgres_mock.UnitOfWork)
+			tx := new(postgres_mock.Transaction)
+			metrics := prometheus.NewPrometheusMetricsProvider()
+
+			if tt.mocks != nil {
+				tt.mocks(tagRepo, uow, tx)
+			}
+
+			s := NewPostService(new(post_repository_mock.Repository), tagRepo, new(media_repository_mock.Repository), uow, log, new(user_client_mock.Client), metrics, model.DefaultPostLimits())
+			got, err := s.RenameTag(context.Background(), tt.tagID, tt.newName)
+
+			if tt.wantErrType != nil {
+				assert.True(t, errors.Is(err, tt.wantErrType), "expected %v, got %v", tt.wantErrType, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			tagRepo.AssertExpectations(t)
+			uow.AssertExpectations(t)
+			tx.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostService_MergeTags(t *testing.T) {
+	log := logger.New("test")
+	tests := []struct {
+		name        string
+		mocks       func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction)
+		sourceIDs   []int64
+		destID      int64
+		want        *model.TagChange
+		wantErrType error
+	}{
+		{
+			name: "Success drops destination and duplicates from sources",
+			mocks: func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("TagRepository").Return(tagRepo)
+				tagRepo.On("FindPostIDsByTags", mock.Anything, []int64{2, 3}).Return([]int64{10, 11}, nil)
+				tagRepo.On("Merge", mock.Anything, []int64{2, 3}, int64(1)).Return(&model.Tag{ID: 1, Name: "golang"}, nil)
+				tx.On("Commit", mock.Anything).Return(nil)
+			},
+			sourceIDs: []int64{2, 1, 3, 2},
+			destID:    1,
+			want:      &model.TagChange{Tag: &model.Tag{ID: 1, Name: "golang"}, AffectedPostIDs: []int64{10, 11}},
+		},
+		{
+			name:        "Error only destination given",
+			sourceIDs:   []int64{1},
+			destID:      1,
+			wantErrType: custom_errors.ErrInvalidInput,
+		},
+		{
+			name: "Error destination not found",
+			mocks: func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				uow.On("Begin", mock.Anything).Return(tx, nil)
+				tx.On("TagRepository").Return(tagRepo)
+				tagRepo.On("FindPostIDsByTags", mock.Anything, []int64{2}).Return([]int64{10}, nil)
+				tagRepo.On("Merge", mock.Anything, []int64{2}, int64(99)).Return(nil, custom_errors.ErrTagNotFound)
+				tx.On("Rollback", mock.Anything).Return(nil)
+			},
+			sourceIDs:   []int64{2},
+			destID:      99,
+			wantErrType: custom_errors.ErrTagNotFound,
+		},
+		{
+			name: "Error begin transaction",
+			mocks: func(tagRepo *tag_repository_mock.Repository, uow *postgres_mock.UnitOfWork, tx *postgres_mock.Transaction) {
+				uow.On("Begin", mock.Anything).Return(nil, errors.New("pool exhausted"))
+			},
+			sourceIDs:   []int64{2},
+			destID:      1,
+			wantErrType: custom_errors.ErrDatabaseQuery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagRepo := new(tag_repository_mock.Repository)
+			uow := new(postgres_mock.UnitOfWork)
+			tx := new(postgres_mock.Transaction)
+			metrics := prometheus.NewPrometheusMetricsProvider()
+
+			if tt.mocks != nil {
+				tt.mocks(tagRepo, uow, tx)
+			}
+
+			s := NewPostService(new(post_repository_mock.Repository), tagRepo, new(media_repository_mock.Repository), uow, log, new(user_client_mock.Client), metrics, model.DefaultPostLimits())
+			got, err := s.MergeTags(context.Background(), tt.sourceIDs, tt.destID)
+
+			if tt.wantErrType != nil {
+				assert.True(t, errors.Is(err, tt.wantErrType), "expected %v, got %v", tt.wantErrType, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+
+			tagRepo.AssertExpectations(t)
+			uow.AssertExpectations(t)
+			tx.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package post_service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// SuggestTags autocompletes a tag name: it returns up to limit tags starting with prefix, most
+// used first. With authorID, tags the author has used before come first. A limit of 0 means
+// model.MaxTagSuggestions. No match is an empty result, not an error.
+func (s *PostService) SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) (result []*model.Tag, err error) {
+	defer func() {
+		s.metrics.IncrementTagOperations("suggest_tags", err == nil)
+	}()
+
+	prefix = model.NormalizeTagName(prefix)
+	if prefix == "" || utf8.RuneCountInString(prefix) > model.MaxTagPrefixLength {
+		return nil, fmt.Errorf("%w: prefix must be between 1 and %d characters", custom_errors.ErrInvalidInput, model.MaxTagPrefixLength)
+	}
+	if limit == 0 {
+		limit = model.MaxTagSuggestions
+	}
+	if limit < 0 || limit > model.MaxTagSuggestions {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d, got %d", custom_errors.ErrInvalidInput, model.MaxTagSuggestions, limit)
+	}
+
+	tags, err := s.tagRepo.SearchTags(ctx, prefix, limit, authorID)
+	if err != nil {
+		s.log.Error("Failed to search tags", slog.String("prefix", prefix), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return tags, nil
+}
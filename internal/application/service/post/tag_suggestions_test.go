@@ -0,0 +1,127 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_service_mock "pinstack-post-service/mocks/post"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+	tag_mock "pinstack-post-service/mocks/tag"
+)
+
+func newSuggestService(tagRepo *tag_mock.Repository) *PostService {
+	return NewPostService(new(post_service_mock.Repository), tagRepo, nil, new(postgres_mock.UnitOfWork), logger.New("test"), nil,
+		prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+}
+
+func TestPostService_SuggestTags(t *testing.T) {
+	authorID := int64(3)
+	found := []*model.Tag{{ID: 1, Name: "golang"}}
+
+	tests := []struct {
+		name       string
+		prefix     string
+		limit      int
+		wantPrefix string
+		wantLimit  int
+		wantErr    error
+	}{
+		{name: "prefix is normalized", prefix: "  GoL ", limit: 5, wantPrefix: "gol", wantLimit: 5},
+		{name: "zero limit is the default", prefix: "go", wantPrefix: "go", wantLimit: model.MaxTagSuggestions},
+		{name: "blank prefix", prefix: "   ", wantErr: custom_errors.ErrInvalidInput},
+		{name: "prefix too long", prefix: strings.Repeat("a", model.MaxTagPrefixLength+1), wantErr: custom_errors.ErrInvalidInput},
+		{name: "limit too large", prefix: "go", limit: model.MaxTagSuggestions + 1, wantErr: custom_errors.ErrInvalidInput},
+		{name: "negative limit", prefix: "go", limit: -1, wantErr: custom_errors.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagRepo := new(tag_mock.Repository)
+			if tt.wantErr == nil {
+				tagRepo.On("SearchTags", mock.Anything, tt.wantPrefix, tt.wantLimit, &authorID).Return(found, nil)
+			}
+
+			got, err := newSuggestService(tagRepo).SuggestTags(context.Background(), tt.prefix, tt.limit, &authorID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				tagRepo.AssertNotCalled(t, "SearchTags")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, found, got)
+			tagRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPostService_SuggestTags_DatabaseError(t *testing.T) {
+	tagRepo := new(tag_mock.Repository)
+	tagRepo.On("SearchTags", mock.Anything, "go", model.MaxTagSuggestions, (*int64)(nil)).Return(nil, errors.New("connection reset"))
+
+	got, err := newSuggestService(tagRepo).SuggestTags(context.Background(), "go", 0, nil)
+
+	assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Nil(t, got)
+}
+
+func TestPostServiceCacheDecorator_SuggestTags(t *testing.T) {
+	found := []*model.Tag{{ID: 1, Name: "golang"}}
+
+	tests := []struct {
+		name     string
+		cacheErr error
+		cached   []*model.Tag
+		wantSet  bool
+	}{
+		{name: "hit", cached: found},
+		{name: "miss is fetched and cached", cacheErr: custom_errors.ErrCacheMiss, wantSet: true},
+		{name: "cache failure falls back to the service", cacheErr: errors.New("redis: connection refused"), wantSet: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(post_service_mock.Service)
+			postCache := new(cache_mock.PostCache)
+			postCache.On("GetTagSuggestions", mock.Anything, "go", model.MaxTagSuggestions).Return(tt.cached, tt.cacheErr).Once()
+			if tt.wantSet {
+				service.On("SuggestTags", mock.Anything, " Go", model.MaxTagSuggestions, (*int64)(nil)).Return(found, nil).Once()
+				postCache.On("SetTagSuggestions", mock.Anything, "go", model.MaxTagSuggestions, found).Return(nil).Once()
+			}
+			d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+				logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			got, err := d.SuggestTags(context.Background(), " Go", 0, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, found, got)
+			service.AssertExpectations(t)
+			postCache.AssertExpectations(t)
+		})
+	}
+
+	t.Run("author suggestions are not cached", func(t *testing.T) {
+		authorID := int64(3)
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		service.On("SuggestTags", mock.Anything, "go", 5, &authorID).Return(found, nil).Once()
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher),
+			logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+		got, err := d.SuggestTags(context.Background(), "go", 5, &authorID)
+
+		require.NoError(t, err)
+		assert.Equal(t, found, got)
+		postCache.AssertNotCalled(t, "GetTagSuggestions", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,171 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	output "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+const (
+	txMaxAttempts = 3
+	txBaseBackoff = 10 * time.Millisecond
+	// txRollbackTimeout bounds a rollback, which runs even after the caller has gone away.
+	txRollbackTimeout = 5 * time.Second
+)
+
+// txBackoff is how long to wait before the next attempt; it doubles per attempt and adds up
+// to the same amount again at random, so conflicting transactions do not retry in lockstep.
+var txBackoff = func(attempt int) time.Duration {
+	d := txBaseBackoff << (attempt - 1)
+	return d + rand.N(d)
+}
+
+// runInTx runs fn in a transaction and commits it. An attempt that fails with a serialization
+// failure, a deadlock or a lock timeout is run again from the start in a fresh transaction, up
+// to txMaxAttempts times in total, so fn must not keep state from a previous attempt. All
+// attempts together get the database's share of the request budget (see inStage), and fn is
+// given the context bounded by it for its queries. An error after that context ended is
+// marked with model.ErrDeadlineExceeded or model.ErrCanceled; any other error from fn is
+// returned unchanged.
+func (s *PostService) runInTx(ctx context.Context, operation string, fn func(ctx context.Context, tx postgres.Transaction) error) error {
+	return s.retryTx(ctx, operation, s.uow.Begin, fn)
+}
+
+// runInTxWithOptions is runInTx for transactions that need a specific isolation level or access mode.
+func (s *PostService) runInTxWithOptions(
+	ctx context.Context,
+	operation string,
+	opts postgres.TxOptions,
+	fn func(ctx context.Context, tx postgres.Transaction) error,
+) error {
+	begin := func(ctx context.Context) (postgres.Transaction, error) {
+		return s.uow.BeginWithOptions(ctx, opts)
+	}
+	return s.retryTx(ctx, operation, begin, fn)
+}
+
+func (s *PostService) retryTx(
+	ctx context.Context,
+	operation string,
+	begin func(ctx context.Context) (postgres.Transaction, error),
+	fn func(ctx context.Context, tx postgres.Transaction) error,
+) error {
+	return inStage(ctx, s.metrics, "post_"+operation, model.BudgetStageDatabase, func(ctx context.Context) error {
+		return s.retryAttempts(ctx, operation, begin, fn)
+	})
+}
+
+func (s *PostService) retryAttempts(
+	ctx context.Context,
+	operation string,
+	begin func(ctx context.Context) (postgres.Transaction, error),
+	fn func(ctx context.Context, tx postgres.Transaction) error,
+) error {
+	for attempt := 1; ; attempt++ {
+		err := s.attemptTx(ctx, begin, fn)
+		if err != nil && ctx.Err() != nil {
+			return model.CallerError(ctx, err)
+		}
+		if err == nil || !db.IsRetryable(err) || attempt == txMaxAttempts {
+			return err
+		}
+
+		s.metrics.IncrementTransactionRetries(operation)
+		s.log.Warn("Retrying transaction after conflict",
+			slog.String("operation", operation),
+			slog.Int("attempt", attempt),
+			slog.String("error", err.Error()))
+
+		timer := time.NewTimer(txBackoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return model.CallerError(ctx, err)
+		case <-timer.C:
+		}
+	}
+}
+
+func (s *PostService) attemptTx(
+	ctx context.Context,
+	begin func(ctx context.Context) (postgres.Transaction, error),
+	fn func(ctx context.Context, tx postgres.Transaction) error,
+) error {
+	tx, err := begin(ctx)
+	if err != nil {
+		logTxError(ctx, s.log, "Failed to start transaction", err)
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	// Anything but a successful commit, a panic in fn included, rolls back.
+	committed := false
+	defer func() {
+		if !committed {
+			rollbackTx(ctx, tx, s.log)
+		}
+	}()
+
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	if err := commitTx(ctx, tx, s.log, "Failed to commit transaction"); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// commitTx commits a transaction and maps a failure to custom_errors.ErrDatabaseQuery, logged
+// with msg and args. A commit the database turned into a rollback is only a warning: the
+// statement that failed inside the transaction has already been handled.
+func commitTx(ctx context.Context, tx postgres.Transaction, log output.Logger, msg string, args ...any) error {
+	err := tx.Commit(ctx)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, postgres.ErrTxCommitRollback):
+		log.Warn("Transaction commit resulted in rollback", append(args, slog.String("error", err.Error()))...)
+		return custom_errors.ErrDatabaseQuery
+	default:
+		logTxError(ctx, log, msg, err, args...)
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+}
+
+// rollbackTx rolls back a transaction that was not committed. It runs detached from the
+// caller's cancellation so an abandoned request still releases its transaction, and a
+// transaction the driver already closed, typically because the caller went away mid-query,
+// is only noted at debug level.
+func rollbackTx(ctx context.Context, tx postgres.Transaction, log output.Logger) {
+	rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), txRollbackTimeout)
+	defer cancel()
+
+	err := tx.Rollback(rollbackCtx)
+	switch {
+	case err == nil:
+	case ctx.Err() != nil, errors.Is(err, postgres.ErrTxClosed), errors.Is(err, postgres.ErrTxCommitRollback):
+		log.Debug("Transaction already closed during rollback", slog.String("error", err.Error()))
+	default:
+		log.Error("Failed to rollback transaction", slog.String("error", err.Error()))
+	}
+}
+
+// logTxError logs a failure to start or commit a transaction, at debug level when the
+// caller's context has ended, since the database is not at fault then.
+func logTxError(ctx context.Context, log output.Logger, msg string, err error, args ...any) {
+	args = append(args, slog.String("error", err.Error()))
+	if ctx.Err() != nil {
+		log.Debug(msg, args...)
+		return
+	}
+	log.Error(msg, args...)
+}
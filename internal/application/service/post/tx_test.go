@@ -0,0 +1,272 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	media_repository_mock "pinstack-post-service/mocks/media"
+	post_repository_mock "pinstack-post-service/mocks/post"
+	postgres_mock "pinstack-post-service/mocks/postgres"
+	revision_repository_mock "pinstack-post-service/mocks/revision"
+	tag_repository_mock "pinstack-post-service/mocks/tag"
+	user_client_mock "pinstack-post-service/mocks/user"
+)
+
+// serializationFailure is what the postgres repositories return when a statement loses a
+// serialization conflict.
+var serializationFailure = db.WithCause(custom_errors.ErrDatabaseQuery, &pgconn.PgError{Code: "40001"})
+
+func noTxBackoff(t *testing.T) {
+	t.Helper()
+	prev := txBackoff
+	txBackoff = func(int) time.Duration { return 0 }
+	t.Cleanup(func() { txBackoff = prev })
+}
+
+type txTestDeps struct {
+	postRepo  *post_repository_mock.Repository
+	tagRepo   *tag_repository_mock.Repository
+	mediaRepo *media_repository_mock.Repository
+	revisions *revision_repository_mock.Repository
+	uow       *postgres_mock.UnitOfWork
+	tx        *postgres_mock.Transaction
+	service   *PostService
+}
+
+func newTxTestDeps(t *testing.T) *txTestDeps {
+	t.Helper()
+	noTxBackoff(t)
+	d := &txTestDeps{
+		postRepo:  new(post_repository_mock.Repository),
+		tagRepo:   new(tag_repository_mock.Repository),
+		mediaRepo: new(media_repository_mock.Repository),
+		revisions: new(revision_repository_mock.Repository),
+		uow:       new(postgres_mock.UnitOfWork),
+		tx:        new(postgres_mock.Transaction),
+	}
+	d.tx.On("PostRepository").Return(d.postRepo)
+	d.tx.On("MediaRepository").Return(d.mediaRepo)
+	d.tx.On("TagRepository").Return(d.tagRepo)
+	d.tx.On("RevisionRepository").Return(d.revisions)
+	d.service = NewPostService(d.postRepo, d.tagRepo, d.mediaRepo, d.uow, logger.New("test"),
+		new(user_client_mock.Client), prometheus.NewPrometheusMetricsProvider(), model.DefaultPostLimits())
+	return d
+}
+
+// expectDelete sets up a delete of post 1, which has no media and no tags.
+func (d *txTestDeps) expectDelete() {
+	d.postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+	d.postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+	d.mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
+	d.tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagsNotFound)
+}
+
+func TestPostService_DeletePost_RetriesSerializationFailure(t *testing.T) {
+	d := newTxTestDeps(t)
+	d.expectDelete()
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.postRepo.On("Delete", mock.Anything, int64(1)).Return(serializationFailure).Once()
+	d.postRepo.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+	d.tx.On("Commit", mock.Anything).Return(nil)
+
+	err := d.service.DeletePost(context.Background(), 1, 1)
+
+	require.NoError(t, err)
+	d.uow.AssertNumberOfCalls(t, "Begin", 2)
+	d.tx.AssertNumberOfCalls(t, "Rollback", 1)
+	d.tx.AssertNumberOfCalls(t, "Commit", 1)
+}
+
+func TestPostService_DeletePost_RetriesSerializationFailureOnCommit(t *testing.T) {
+	d := newTxTestDeps(t)
+	d.expectDelete()
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.postRepo.On("Delete", mock.Anything, int64(1)).Return(nil)
+	d.tx.On("Commit", mock.Anything).Return(&pgconn.PgError{Code: "40001"}).Once()
+	d.tx.On("Commit", mock.Anything).Return(nil).Once()
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+
+	err := d.service.DeletePost(context.Background(), 1, 1)
+
+	require.NoError(t, err)
+	d.uow.AssertNumberOfCalls(t, "Begin", 2)
+}
+
+func TestPostService_DeletePost_DoesNotRetryOtherErrors(t *testing.T) {
+	d := newTxTestDeps(t)
+	d.expectDelete()
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.postRepo.On("Delete", mock.Anything, int64(1)).Return(custom_errors.ErrDatabaseQuery)
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+
+	err := d.service.DeletePost(context.Background(), 1, 1)
+
+	assert.Equal(t, custom_errors.ErrDatabaseQuery, err)
+	d.uow.AssertNumberOfCalls(t, "Begin", 1)
+}
+
+func TestPostService_DeletePost_GivesUpAfterMaxAttempts(t *testing.T) {
+	d := newTxTestDeps(t)
+	d.expectDelete()
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.postRepo.On("Delete", mock.Anything, int64(1)).Return(serializationFailure)
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+
+	err := d.service.DeletePost(context.Background(), 1, 1)
+
+	require.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	assert.Equal(t, custom_errors.ErrDatabaseQuery.Error(), err.Error())
+	d.uow.AssertNumberOfCalls(t, "Begin", txMaxAttempts)
+	d.tx.AssertNotCalled(t, "Commit", mock.Anything)
+}
+
+func TestPostService_DeletePost_StopsRetryingWhenContextDone(t *testing.T) {
+	d := newTxTestDeps(t)
+	d.expectDelete()
+	ctx, cancel := context.WithCancel(context.Background())
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.postRepo.On("Delete", mock.Anything, int64(1)).Return(serializationFailure).Run(func(mock.Arguments) { cancel() })
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+	txBackoff = func(int) time.Duration { return time.Hour }
+
+	err := d.service.DeletePost(ctx, 1, 1)
+
+	require.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+	d.uow.AssertNumberOfCalls(t, "Begin", 1)
+}
+
+func TestPostService_CreatePost_CallerCancelledBetweenRepositoryCalls(t *testing.T) {
+	d := newTxTestDeps(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.service.userClient.(*user_client_mock.Client).On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.postRepo.On("Create", mock.Anything, mock.Anything).
+		Return(&model.Post{ID: 5, AuthorID: 1, Title: "Title"}, nil).
+		Run(func(mock.Arguments) { cancel() })
+	d.tagRepo.On("FindByNames", mock.Anything, []string{"go"}).
+		Return(nil, db.WithCause(custom_errors.ErrTagQueryFailed, context.Canceled))
+	rollbackCtxErr := errors.New("rollback not called")
+	d.tx.On("Rollback", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		rollbackCtxErr = args.Get(0).(context.Context).Err()
+	})
+
+	_, err := d.service.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Title", Tags: []string{"go"}})
+
+	require.ErrorIs(t, err, model.ErrCanceled)
+	assert.False(t, db.IsRetryable(err))
+	d.uow.AssertNumberOfCalls(t, "Begin", 1)
+	d.tx.AssertNotCalled(t, "Commit", mock.Anything)
+	assert.NoError(t, rollbackCtxErr, "the rollback must not inherit the caller's cancellation")
+}
+
+func TestPostService_DeletePost_CallerDeadlineIsNotRetried(t *testing.T) {
+	d := newTxTestDeps(t)
+	d.expectDelete()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.postRepo.On("Delete", mock.Anything, int64(1)).Return(serializationFailure).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	})
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+
+	err := d.service.DeletePost(ctx, 1, 1)
+
+	require.ErrorIs(t, err, model.ErrDeadlineExceeded)
+	d.uow.AssertNumberOfCalls(t, "Begin", 1)
+}
+
+func TestPostService_UpdatePost_RetriesDeadlock(t *testing.T) {
+	d := newTxTestDeps(t)
+	title := "New title"
+	deadlock := db.WithCause(custom_errors.ErrDatabaseQuery, &pgconn.PgError{Code: "40P01"})
+
+	d.uow.On("BeginWithOptions", mock.Anything, postgres.TxOptions{Isolation: postgres.RepeatableRead}).Return(d.tx, nil)
+	d.postRepo.On("GetByID", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil)
+	d.postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(nil, deadlock).Once()
+	d.postRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(&model.Post{ID: 1, AuthorID: 1}, nil).Once()
+	d.postRepo.On("Update", mock.Anything, int64(1), mock.Anything).Return(&model.Post{ID: 1, AuthorID: 1, Title: title}, nil)
+	d.revisions.On("Record", mock.Anything, mock.Anything).Return(&model.PostRevision{PostID: 1, Revision: 1}, nil)
+	d.revisions.On("Prune", mock.Anything, int64(1), mock.Anything).Return(0, nil)
+	d.mediaRepo.On("GetByPost", mock.Anything, int64(1)).Return([]*model.PostMedia{}, nil)
+	d.tagRepo.On("FindByPost", mock.Anything, int64(1)).Return(nil, custom_errors.ErrTagsNotFound)
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+	d.tx.On("Commit", mock.Anything).Return(nil)
+	d.service.userClient.(*user_client_mock.Client).On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+
+	got, err := d.service.UpdatePost(context.Background(), 1, 1, &model.UpdatePostDTO{Title: &title})
+
+	require.NoError(t, err)
+	assert.Equal(t, title, got.Post.Title)
+	d.uow.AssertNumberOfCalls(t, "BeginWithOptions", 2)
+}
+
+func TestPostService_CreatePost_RetriesWithFreshState(t *testing.T) {
+	d := newTxTestDeps(t)
+	d.service.userClient.(*user_client_mock.Client).On("GetUser", mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.postRepo.On("Create", mock.Anything, mock.Anything).Return(&model.Post{ID: 5, AuthorID: 1, Title: "Title"}, nil)
+	d.tagRepo.On("FindByNames", mock.Anything, []string{"go"}).Return([]*model.Tag{{ID: 1, Name: "go"}}, nil)
+	d.tagRepo.On("TagPostExisting", mock.Anything, int64(5), []string{"go"}).
+		Return(nil, db.WithCause(custom_errors.ErrTagPost, &pgconn.PgError{Code: "40001"})).Once()
+	d.tagRepo.On("TagPostExisting", mock.Anything, int64(5), []string{"go"}).Return([]string{}, nil).Once()
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+	d.tx.On("Commit", mock.Anything).Return(nil)
+
+	got, err := d.service.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: 1, Title: "Title", Tags: []string{"go"}})
+
+	require.NoError(t, err)
+	d.uow.AssertNumberOfCalls(t, "Begin", 2)
+	assert.Len(t, got.Tags, 1, "tags found in the failed attempt must not be kept")
+}
+
+func TestRunInTx_NonRetryableErrorIsUnchanged(t *testing.T) {
+	d := newTxTestDeps(t)
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	d.tx.On("Rollback", mock.Anything).Return(nil)
+	sentinel := errors.New("boom")
+
+	err := d.service.runInTx(context.Background(), "test", func(context.Context, postgres.Transaction) error { return sentinel })
+
+	assert.Same(t, sentinel, err)
+	d.uow.AssertNumberOfCalls(t, "Begin", 1)
+}
+
+func TestRunInTx_CommitRolledBack(t *testing.T) {
+	d := newTxTestDeps(t)
+	log, recorder := logger.NewTest()
+	d.service.log = log
+	d.uow.On("Begin", mock.Anything).Return(d.tx, nil)
+	// What the postgres transaction returns for pgx.ErrTxCommitRollback and pgx.ErrTxClosed.
+	d.tx.On("Commit", mock.Anything).Return(fmt.Errorf("%w: %w", postgres.ErrTxCommitRollback, pgx.ErrTxCommitRollback))
+	d.tx.On("Rollback", mock.Anything).Return(fmt.Errorf("%w: %w", postgres.ErrTxClosed, pgx.ErrTxClosed))
+
+	err := d.service.runInTx(context.Background(), "test", func(context.Context, postgres.Transaction) error { return nil })
+
+	assert.Same(t, custom_errors.ErrDatabaseQuery, err)
+	d.uow.AssertNumberOfCalls(t, "Begin", 1)
+	d.tx.AssertNumberOfCalls(t, "Commit", 1)
+	d.tx.AssertNumberOfCalls(t, "Rollback", 1)
+	assert.Empty(t, recorder.Messages(slog.LevelError))
+	assert.Equal(t, []string{"Transaction commit resulted in rollback"}, recorder.Messages(slog.LevelWarn))
+	assert.Equal(t, []string{"Transaction already closed during rollback"}, recorder.Messages(slog.LevelDebug),
+		"the rollback after a rolled back commit is only noise")
+}
@@ -0,0 +1,88 @@
+package post_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+func TestPostService_UpdatePost_ExpectedVersion(t *testing.T) {
+	title := func(s string) *string { return &s }
+	version := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name        string
+		update      *model.UpdatePostDTO
+		wantVersion int64
+		wantCurrent int64
+	}{
+		{name: "matching version", update: &model.UpdatePostDTO{Title: title("Renamed"), ExpectedVersion: version(2)}, wantVersion: 3},
+		{name: "stale version", update: &model.UpdatePostDTO{Title: title("Renamed"), ExpectedVersion: version(1)}, wantCurrent: 2},
+		{name: "future version", update: &model.UpdatePostDTO{Title: title("Renamed"), ExpectedVersion: version(7)}, wantCurrent: 2},
+		{name: "stale version on a tags-only update", update: &model.UpdatePostDTO{Tags: []string{"go"}, ExpectedVersion: version(1)}, wantCurrent: 2},
+		{name: "no expected version", update: &model.UpdatePostDTO{Title: title("Renamed")}, wantVersion: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newScheduleService(t)
+			ctx := context.Background()
+			created, err := s.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), created.Post.Version)
+			// A first update moves the post to version 2.
+			updated, err := s.UpdatePost(ctx, 1, created.Post.ID, &model.UpdatePostDTO{UserID: 1, Title: title("First")})
+			require.NoError(t, err)
+			require.Equal(t, int64(2), updated.Post.Version)
+			tt.update.UserID = 1
+
+			got, err := s.UpdatePost(ctx, 1, created.Post.ID, tt.update)
+
+			if tt.wantCurrent != 0 {
+				require.ErrorIs(t, err, model.ErrVersionConflict)
+				var conflictErr *model.VersionConflictError
+				require.True(t, errors.As(err, &conflictErr))
+				assert.Equal(t, created.Post.ID, conflictErr.PostID)
+				assert.Equal(t, tt.wantCurrent, conflictErr.CurrentVersion)
+
+				stored, err := s.GetPostByID(ctx, created.Post.ID, nil)
+				require.NoError(t, err)
+				assert.Equal(t, "First", stored.Post.Title, "a rejected update writes nothing")
+				assert.Empty(t, stored.Tags)
+				assert.Equal(t, int64(2), stored.Post.Version)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Renamed", got.Post.Title)
+			assert.Equal(t, tt.wantVersion, got.Post.Version)
+		})
+	}
+}
+
+func TestPostService_UpdatePost_ExpectedVersionOfAMissingPost(t *testing.T) {
+	s, _ := newScheduleService(t)
+	title, expected := "Renamed", int64(1)
+
+	_, err := s.UpdatePost(context.Background(), 1, 404, &model.UpdatePostDTO{UserID: 1, Title: &title, ExpectedVersion: &expected})
+
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	assert.NotErrorIs(t, err, model.ErrVersionConflict)
+}
+
+func TestPostService_UpdatePost_ExpectedVersionMustBePositive(t *testing.T) {
+	s, _ := newScheduleService(t)
+	title, expected := "Renamed", int64(0)
+
+	_, err := s.UpdatePost(context.Background(), 1, 1, &model.UpdatePostDTO{UserID: 1, Title: &title, ExpectedVersion: &expected})
+
+	require.ErrorIs(t, err, custom_errors.ErrPostValidation)
+	var verr *model.ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, "expected_version", verr.Violations[0].Field)
+}
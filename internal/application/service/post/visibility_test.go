@@ -0,0 +1,224 @@
+package post_service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	cache_mock "pinstack-post-service/mocks/cache"
+	post_service_mock "pinstack-post-service/mocks/post"
+)
+
+var (
+	visibilityAuthor = int64(1)
+	visibilityOther  = int64(2)
+)
+
+// newVisibilityService stores one public, one unlisted and one private post by
+// visibilityAuthor, with ids 1, 2 and 3.
+func newVisibilityService(t *testing.T) *PostService {
+	t.Helper()
+	s, _ := newMemoryService(t)
+	for _, visibility := range []model.PostVisibility{"", model.PostVisibilityUnlisted, model.PostVisibilityPrivate} {
+		_, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: visibilityAuthor, Title: "Post", Visibility: visibility})
+		require.NoError(t, err)
+	}
+	return s
+}
+
+// visibilityCases is every visibility and requester combination of a read by id.
+var visibilityCases = []struct {
+	name        string
+	visibility  model.PostVisibility
+	requesterID *int64
+	wantErr     error
+}{
+	{name: "public to anonymous", visibility: model.PostVisibilityPublic},
+	{name: "public to another user", visibility: model.PostVisibilityPublic, requesterID: &visibilityOther},
+	{name: "public to author", visibility: model.PostVisibilityPublic, requesterID: &visibilityAuthor},
+	{name: "unlisted to anonymous", visibility: model.PostVisibilityUnlisted},
+	{name: "unlisted to another user", visibility: model.PostVisibilityUnlisted, requesterID: &visibilityOther},
+	{name: "unlisted to author", visibility: model.PostVisibilityUnlisted, requesterID: &visibilityAuthor},
+	{name: "private to anonymous", visibility: model.PostVisibilityPrivate, wantErr: custom_errors.ErrForbidden},
+	{name: "private to another user", visibility: model.PostVisibilityPrivate, requesterID: &visibilityOther, wantErr: custom_errors.ErrForbidden},
+	{name: "private to author", visibility: model.PostVisibilityPrivate, requesterID: &visibilityAuthor},
+}
+
+func visibilityPostID(visibility model.PostVisibility) int64 {
+	switch visibility {
+	case model.PostVisibilityUnlisted:
+		return 2
+	case model.PostVisibilityPrivate:
+		return 3
+	}
+	return 1
+}
+
+func TestPostService_Visibility_GetPostByID(t *testing.T) {
+	s := newVisibilityService(t)
+
+	for _, tt := range visibilityCases {
+		t.Run(tt.name, func(t *testing.T) {
+			id := visibilityPostID(tt.visibility)
+
+			got, err := s.GetPostByID(context.Background(), id, tt.requesterID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.visibility, got.Post.Visibility)
+			}
+
+			_, err = s.GetPostTags(context.Background(), id, tt.requesterID, false)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPostService_Visibility_PrivateDraftIsNotFound(t *testing.T) {
+	s := newVisibilityService(t)
+	created, err := s.CreatePost(context.Background(), &model.CreatePostDTO{
+		AuthorID: visibilityAuthor, Title: "Draft", Status: model.PostStatusDraft, Visibility: model.PostVisibilityPrivate,
+	})
+	require.NoError(t, err)
+
+	_, err = s.GetPostByID(context.Background(), created.Post.ID, &visibilityOther)
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound, "a draft stays hidden, whatever its visibility")
+}
+
+func TestPostService_Visibility_ListPosts(t *testing.T) {
+	s := newVisibilityService(t)
+
+	tests := []struct {
+		name    string
+		filters model.PostFilters
+		want    []int64
+	}{
+		{name: "anonymous", want: []int64{1}},
+		{name: "anonymous listing the author", filters: model.PostFilters{AuthorID: &visibilityAuthor}, want: []int64{1}},
+		{name: "another user listing the author", filters: model.PostFilters{AuthorID: &visibilityAuthor, RequesterID: &visibilityOther}, want: []int64{1}},
+		{name: "author without author filter", filters: model.PostFilters{RequesterID: &visibilityAuthor}, want: []int64{1}},
+		{name: "author listing their own posts", filters: model.PostFilters{AuthorID: &visibilityAuthor, RequesterID: &visibilityAuthor}, want: []int64{3, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := tt.filters
+			got, total, err := s.ListPosts(context.Background(), &filters)
+
+			require.NoError(t, err)
+			ids := make([]int64, len(got))
+			for i, post := range got {
+				ids[i] = post.Post.ID
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+			assert.Equal(t, len(tt.want), total)
+		})
+	}
+}
+
+func TestPostService_Visibility_GetPostsByIDsLeavesOutPrivate(t *testing.T) {
+	s := newVisibilityService(t)
+
+	got, err := s.GetPostsByIDs(context.Background(), []int64{1, 2, 3})
+
+	require.NoError(t, err)
+	ids := make([]int64, len(got))
+	for i, post := range got {
+		ids[i] = post.Post.ID
+	}
+	assert.Equal(t, []int64{1, 2}, ids)
+}
+
+func TestPostService_Visibility_Update(t *testing.T) {
+	s := newVisibilityService(t)
+	private := model.PostVisibilityPrivate
+
+	updated, err := s.UpdatePost(context.Background(), visibilityAuthor, 1, &model.UpdatePostDTO{UserID: visibilityAuthor, Visibility: &private})
+	require.NoError(t, err)
+	assert.Equal(t, model.PostVisibilityPrivate, updated.Post.Visibility)
+
+	_, err = s.GetPostByID(context.Background(), 1, &visibilityOther)
+	assert.ErrorIs(t, err, custom_errors.ErrForbidden)
+}
+
+func TestPostService_Visibility_Validation(t *testing.T) {
+	s := newVisibilityService(t)
+	hidden := model.PostVisibility("hidden")
+
+	_, err := s.CreatePost(context.Background(), &model.CreatePostDTO{AuthorID: visibilityAuthor, Title: "Post", Visibility: hidden})
+	assert.ErrorIs(t, err, custom_errors.ErrPostValidation)
+
+	_, err = s.UpdatePost(context.Background(), visibilityAuthor, 1, &model.UpdatePostDTO{UserID: visibilityAuthor, Visibility: &hidden})
+	assert.ErrorIs(t, err, custom_errors.ErrPostValidation)
+}
+
+func TestPostServiceCacheDecorator_Visibility(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	for _, tt := range visibilityCases {
+		t.Run(tt.name, func(t *testing.T) {
+			cached := &model.PostDetailed{
+				Post: &model.Post{ID: 10, AuthorID: visibilityAuthor, Status: model.PostStatusPublished, Visibility: tt.visibility},
+				Tags: []*model.Tag{{ID: 1, Name: "go"}},
+			}
+			service := new(post_service_mock.Service)
+			postCache := new(cache_mock.PostCache)
+			postCache.On("GetPost", mock.Anything, int64(10)).Return(cached, nil)
+
+			d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, new(cache_mock.CacheBatcher), log, metrics)
+
+			got, err := d.GetPostByID(context.Background(), 10, tt.requesterID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				assert.Same(t, cached, got)
+			}
+
+			_, err = d.GetPostTags(context.Background(), 10, tt.requesterID, false)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			service.AssertNotCalled(t, "GetPostByID", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("cached private posts are left out of batch reads", func(t *testing.T) {
+		service := new(post_service_mock.Service)
+		postCache := new(cache_mock.PostCache)
+		public := &model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: visibilityAuthor, Visibility: model.PostVisibilityPublic}}
+		private := &model.PostDetailed{Post: &model.Post{ID: 2, AuthorID: visibilityAuthor, Visibility: model.PostVisibilityPrivate}}
+		postCache.On("GetPosts", mock.Anything, []int64{1, 2}).
+			Return(map[int64]*model.PostDetailed{1: public, 2: private}, nil)
+		service.On("GetPostsByIDs", mock.Anything, []int64{2}).Return([]*model.PostDetailed{}, nil)
+		batcher := new(cache_mock.CacheBatcher)
+		batch := new(cache_mock.CacheBatch)
+		batcher.On("NewBatch").Return(batch)
+		batch.On("Len").Return(0)
+
+		d := NewPostServiceCacheDecorator(service, new(cache_mock.UserCache), postCache, batcher, log, metrics)
+
+		got, err := d.GetPostsByIDs(context.Background(), []int64{1, 2})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Same(t, public, got[0])
+		service.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,9 @@
+package model
+
+// AuthorPostCount is the number of published posts of an author. Cached reports whether it
+// was answered from the cache, in which case it may lag behind by up to the count's TTL.
+type AuthorPostCount struct {
+	AuthorID int64 `json:"author_id"`
+	Count    int64 `json:"count"`
+	Cached   bool  `json:"cached"`
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BudgetStage is a part of a request that gets its own share of the request's time budget,
+// so one slow dependency cannot use up the time the others need.
+type BudgetStage string
+
+const (
+	BudgetStageCache       BudgetStage = "cache"
+	BudgetStageDatabase    BudgetStage = "database"
+	BudgetStageUserService BudgetStage = "user_service"
+)
+
+// BudgetRatios is the share of a request's total budget each stage may spend, between 0 and
+// 1. A stage with a zero ratio is bounded by the request's deadline only.
+type BudgetRatios struct {
+	Cache       float64
+	Database    float64
+	UserService float64
+}
+
+func DefaultBudgetRatios() BudgetRatios {
+	return BudgetRatios{Cache: 0.1, Database: 0.5, UserService: 0.3}
+}
+
+func (r BudgetRatios) of(stage BudgetStage) float64 {
+	switch stage {
+	case BudgetStageCache:
+		return r.Cache
+	case BudgetStageDatabase:
+		return r.Database
+	case BudgetStageUserService:
+		return r.UserService
+	default:
+		return 0
+	}
+}
+
+// budget is the time a request may spend, carried on its context by WithBudget.
+type budget struct {
+	total    time.Duration
+	deadline time.Time
+	ratios   BudgetRatios
+}
+
+type budgetKey struct{}
+
+// WithBudget returns a copy of ctx whose stages share total by ratios. The budget ends at
+// ctx's deadline when that comes first.
+func WithBudget(ctx context.Context, total time.Duration, ratios BudgetRatios) context.Context {
+	deadline := time.Now().Add(total)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	return context.WithValue(ctx, budgetKey{}, budget{total: total, deadline: deadline, ratios: ratios})
+}
+
+// StageContext returns ctx bounded by stage's share of the budget WithBudget put on ctx, or
+// by what is left of the budget when that is less. Once the budget is spent it returns an
+// error wrapping ErrDeadlineExceeded instead, so the stage is not attempted at all. Without
+// a budget on ctx, the stage is bounded by ctx alone.
+func StageContext(ctx context.Context, stage BudgetStage) (context.Context, context.CancelFunc, error) {
+	b, ok := ctx.Value(budgetKey{}).(budget)
+	if !ok {
+		stageCtx, cancel := context.WithCancel(ctx)
+		return stageCtx, cancel, nil
+	}
+	remaining := time.Until(b.deadline)
+	if remaining <= 0 {
+		return nil, nil, fmt.Errorf("%w: budget spent before %s", ErrDeadlineExceeded, stage)
+	}
+	slice := remaining
+	if ratio := b.ratios.of(stage); ratio > 0 {
+		slice = min(time.Duration(float64(b.total)*ratio), remaining)
+	}
+	stageCtx, cancel := context.WithTimeout(ctx, slice)
+	return stageCtx, cancel, nil
+}
@@ -0,0 +1,24 @@
+package model
+
+const (
+	// MaxBulkCreatePosts caps the posts of one bulk import call.
+	MaxBulkCreatePosts = 500
+	// BulkCreateChunkSize is how many posts of a bulk import one transaction inserts.
+	BulkCreateChunkSize = 100
+)
+
+// BulkCreateItem is the outcome of one post of a bulk import, at Index in the request: the
+// created post, or the error that kept it out. Exactly one of Post and Err is set.
+type BulkCreateItem struct {
+	Index int
+	Post  *Post
+	Err   error
+}
+
+// BulkCreateResult holds an item per requested post, in request order. One bad post fails
+// only its own item.
+type BulkCreateResult struct {
+	Items   []*BulkCreateItem
+	Created int
+	Failed  int
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// CacheSource is where a read was served from.
+type CacheSource string
+
+const (
+	CacheSourceCache    CacheSource = "cache"
+	CacheSourceDatabase CacheSource = "database"
+)
+
+// CacheInfo describes how the cache served a read, for support investigating reports of
+// stale data. For GetPostByID it describes the post; for ListPosts, whose posts always come
+// from the database, Hit reports whether every author of the page came from the cache.
+type CacheInfo struct {
+	Hit    bool
+	Source CacheSource
+	// Age is how long ago the served entry was written and TTL how long it has left; both
+	// are zero on a miss, and Age for an entry written before entries recorded it.
+	Age time.Duration
+	TTL time.Duration
+}
+
+type cacheInfoKey struct{}
+
+// WithCacheInfo returns a copy of ctx on which the read records how it was served, and the
+// CacheInfo it records into. Reads on a context without it record nothing.
+func WithCacheInfo(ctx context.Context) (context.Context, *CacheInfo) {
+	info := &CacheInfo{}
+	return context.WithValue(ctx, cacheInfoKey{}, info), info
+}
+
+// RecordCacheInfo stores info in the CacheInfo WithCacheInfo put on ctx, if any.
+func RecordCacheInfo(ctx context.Context, info CacheInfo) {
+	if dest, ok := ctx.Value(cacheInfoKey{}).(*CacheInfo); ok {
+		*dest = info
+	}
+}
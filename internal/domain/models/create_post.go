@@ -1,9 +1,27 @@
 package model
 
+import "time"
+
 type CreatePostDTO struct {
-	AuthorID   int64             `json:"author_id"`
-	Title      string            `json:"title"`
-	Content    *string           `json:"content,omitempty"`
-	Tags       []string          `json:"tags,omitempty"`
-	MediaItems []*PostMediaInput `json:"media_items,omitempty"`
+	AuthorID int64 `json:"author_id"`
+	// Title and Content are optional, but a post needs at least one of them or a media item.
+	Title   string     `json:"title"`
+	Content *string    `json:"content,omitempty"`
+	Status  PostStatus `json:"status,omitempty"`
+	// Visibility defaults to public.
+	Visibility PostVisibility `json:"visibility,omitempty"`
+	// Language is a BCP-47 tag from the configured allowlist; empty leaves it unset.
+	Language string `json:"language,omitempty"`
+	// Source is the client creating the post; one off the configured allowlist is recorded
+	// as PostSourceUnknown.
+	Source PostSource `json:"source,omitempty"`
+	// ScheduledAt, when set, must be in the future; the post is created scheduled and the
+	// scheduler publishes it at that time.
+	ScheduledAt *time.Time        `json:"scheduled_at,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	MediaItems  []*PostMediaInput `json:"media_items,omitempty"`
+	// VerifiedAuthor is set by callers that already know the author exists, such as the cache
+	// decorator with a fresh cached user. The service then skips the user-service lookup.
+	// Clients never set it.
+	VerifiedAuthor *User `json:"-"`
 }
@@ -1,7 +1,13 @@
 package model
 
+// PostMediaInput describes media to attach. Width, Height, SizeBytes and AltText are
+// optional; nil means the client did not provide them.
 type PostMediaInput struct {
-	URL      string    `json:"url"`
-	Type     MediaType `json:"type"`
-	Position int32     `json:"position"`
+	URL       string    `json:"url"`
+	Type      MediaType `json:"type"`
+	Position  int32     `json:"position"`
+	Width     *int32    `json:"width,omitempty"`
+	Height    *int32    `json:"height,omitempty"`
+	SizeBytes *int64    `json:"size_bytes,omitempty"`
+	AltText   *string   `json:"alt_text,omitempty"`
 }
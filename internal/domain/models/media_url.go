@@ -0,0 +1,124 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// MediaHosts is the allowlist of hosts media may be served from. A "*.example.com" entry
+// matches every subdomain of example.com but not example.com itself. AllowAll accepts any
+// host and is meant for local development.
+type MediaHosts struct {
+	AllowAll bool
+	Hosts    []string
+}
+
+// Allows reports whether host, as returned by NormalizeMediaURL, is on the allowlist.
+func (m MediaHosts) Allows(host string) bool {
+	if m.AllowAll {
+		return true
+	}
+	for _, entry := range m.Hosts {
+		if suffix, ok := strings.CutPrefix(entry, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeMediaHost turns an allowlist entry into the form Allows compares against:
+// lowercase ASCII, with the leading "*." of a wildcard kept.
+func NormalizeMediaHost(entry string) (string, error) {
+	wildcard, host := "", entry
+	if rest, ok := strings.CutPrefix(entry, "*."); ok {
+		wildcard, host = "*.", rest
+	}
+	if strings.Contains(host, "*") {
+		return "", fmt.Errorf("%q: only a leading \"*.\" wildcard is allowed", entry)
+	}
+	ascii, err := asciiHost(host)
+	if err != nil {
+		return "", fmt.Errorf("%q: %w", entry, err)
+	}
+	return wildcard + ascii, nil
+}
+
+// NormalizeMediaURL returns the canonical form of a media URL and its host: lowercase scheme
+// and ASCII host, no default port. URLs that are not absolute http(s), carry credentials or
+// a fragment are rejected.
+func NormalizeMediaURL(raw string) (normalized string, host string, err error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", errors.New("must be a valid url")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", errors.New("must be an http or https url")
+	}
+	if u.User != nil {
+		return "", "", errors.New("must not contain credentials")
+	}
+	if strings.Contains(raw, "#") {
+		return "", "", errors.New("must not contain a fragment")
+	}
+	host, err = asciiHost(u.Hostname())
+	if err != nil {
+		return "", "", errors.New("must have a valid host")
+	}
+
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	u.Host = host
+	if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	}
+	if port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	}
+	return u.String(), host, nil
+}
+
+// NormalizeMedia returns copies of media whose URLs are in canonical form, so duplicate
+// checks and stored URLs agree. A URL that does not normalize is kept as is for validation
+// to report.
+func NormalizeMedia(media []*PostMediaInput) []*PostMediaInput {
+	if media == nil {
+		return nil
+	}
+	normalized := make([]*PostMediaInput, len(media))
+	for i, m := range media {
+		if m == nil {
+			continue
+		}
+		clone := *m
+		if u, _, err := NormalizeMediaURL(m.URL); err == nil {
+			clone.URL = u
+		}
+		normalized[i] = &clone
+	}
+	return normalized
+}
+
+func asciiHost(host string) (string, error) {
+	if host == "" {
+		return "", errors.New("empty host")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(ascii), nil
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ModerationReasonMinLength = 5
+	ModerationReasonMaxLength = 500
+)
+
+// ModerationActionForceDelete is a post deleted by a moderator rather than its author.
+const ModerationActionForceDelete = "force_delete"
+
+// ModerationAction records who moderated a post and why. AuthorID is the author of the post
+// at the time, kept because the post itself may be gone.
+type ModerationAction struct {
+	ID        int64
+	Action    string
+	PostID    int64
+	AuthorID  int64
+	ActorID   int64
+	Reason    string
+	CreatedAt time.Time
+}
+
+// ValidateModerationReason checks the length of a moderation reason in characters, ignoring
+// surrounding whitespace.
+func ValidateModerationReason(reason string) error {
+	n := utf8.RuneCountInString(strings.TrimSpace(reason))
+	if n < ModerationReasonMinLength || n > ModerationReasonMaxLength {
+		return &ValidationError{Violations: []FieldViolation{{
+			Field:       "reason",
+			Description: fmt.Sprintf("must be between %d and %d characters, got %d", ModerationReasonMinLength, ModerationReasonMaxLength, n),
+		}}}
+	}
+	return nil
+}
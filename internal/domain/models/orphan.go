@@ -0,0 +1,26 @@
+package model
+
+// OrphanKind names rows left behind by a post or tag that no longer exists. Foreign keys
+// with ON DELETE CASCADE keep new rows from being orphaned; older crashes and manual edits
+// left some behind.
+type OrphanKind string
+
+const (
+	// OrphanMedia are post_media rows whose post is gone.
+	OrphanMedia OrphanKind = "post_media"
+	// OrphanPostTagsWithoutPost are posts_tags rows whose post is gone.
+	OrphanPostTagsWithoutPost OrphanKind = "posts_tags_post"
+	// OrphanPostTagsWithoutTag are posts_tags rows whose tag is gone.
+	OrphanPostTagsWithoutTag OrphanKind = "posts_tags_tag"
+)
+
+// OrphanKinds lists every kind, in the order the reconciler removes them.
+var OrphanKinds = []OrphanKind{OrphanMedia, OrphanPostTagsWithoutPost, OrphanPostTagsWithoutTag}
+
+// OrphanReport is the outcome of a reconciliation: the orphans found and removed per kind. A
+// dry run finds them and removes none.
+type OrphanReport struct {
+	DryRun  bool
+	Found   map[OrphanKind]int
+	Removed map[OrphanKind]int
+}
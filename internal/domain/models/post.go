@@ -1,12 +1,151 @@
 package model
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
 
 type Post struct {
-	ID        int64            `json:"id"`
-	AuthorID  int64            `json:"author_id"`
-	Title     string           `json:"title"`
-	Content   *string          `json:"content,omitempty"`
-	CreatedAt pgtype.Timestamp `json:"created_at"`
-	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	ID       int64 `json:"id"`
+	AuthorID int64 `json:"author_id"`
+	// Title is empty for a post without one, such as a media-only post.
+	Title   string     `json:"title"`
+	Content *string    `json:"content,omitempty"`
+	Status  PostStatus `json:"status,omitempty"`
+	// Visibility is empty for posts read before the column existed; that means public.
+	Visibility PostVisibility `json:"visibility,omitempty"`
+	// Version starts at 1 and goes up with every write to the post; see
+	// UpdatePostDTO.ExpectedVersion.
+	Version int64 `json:"version,omitempty"`
+	// EditCount is how many edits changed the title or content; see PostRevision.
+	EditCount int64 `json:"edit_count,omitempty"`
+	// Language is the BCP-47 tag of the language the post is written in; nil when unset.
+	Language *string `json:"language,omitempty"`
+	// Source is the client the post was created from; PostSourceUnknown for posts created
+	// before sources were recorded.
+	Source      PostSource         `json:"source,omitempty"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	PublishedAt pgtype.Timestamptz `json:"published_at"`
+	ScheduledAt pgtype.Timestamptz `json:"scheduled_at"`
+	// PinnedAt is when the author pinned the post to the top of their profile; an author has
+	// at most one pinned post.
+	PinnedAt pgtype.Timestamptz `json:"pinned_at"`
+	// ModerationStatus is where the post stands with trust & safety; see RedactedFor.
+	// RejectionReason is set only for a rejected post.
+	ModerationStatus ModerationStatus `json:"moderation_status,omitempty"`
+	RejectionReason  *RejectionReason `json:"rejection_reason,omitempty"`
+	// AuthorUsername and AuthorAvatarURL are a copy of the author's display data taken at
+	// AuthorSnapshotAt, so reads can skip the user service; see AuthorFromSnapshot. They are
+	// nil for posts created before snapshots existed.
+	AuthorUsername   *string            `json:"author_username,omitempty"`
+	AuthorAvatarURL  *string            `json:"author_avatar_url,omitempty"`
+	AuthorSnapshotAt pgtype.Timestamptz `json:"author_snapshot_at"`
+}
+
+// IsVisibleTo reports whether the requester may see the post. Drafts and scheduled posts are
+// visible only to their author.
+func (p *Post) IsVisibleTo(requesterID *int64) bool {
+	if p.Status != PostStatusDraft && p.Status != PostStatusScheduled {
+		return true
+	}
+	return requesterID != nil && *requesterID == p.AuthorID
+}
+
+// CheckAccess reports whether the requester may read the post by its id. A draft or scheduled
+// post of another author is ErrPostNotFound, another author's private post ErrForbidden.
+func (p *Post) CheckAccess(requesterID *int64) error {
+	if !p.IsVisibleTo(requesterID) {
+		return custom_errors.ErrPostNotFound
+	}
+	if p.Visibility == PostVisibilityPrivate && (requesterID == nil || *requesterID != p.AuthorID) {
+		return custom_errors.ErrForbidden
+	}
+	return nil
+}
+
+// Edited reports whether the title or content changed since the post was created.
+func (p *Post) Edited() bool {
+	return p.EditCount > 0
+}
+
+// VisibleSince is when the post started showing to everyone: when it was published, or when
+// it was created for a post without a publication time.
+func (p *Post) VisibleSince() time.Time {
+	if p.PublishedAt.Valid {
+		return p.PublishedAt.Time
+	}
+	return p.CreatedAt.Time
+}
+
+// IsPinned reports whether the post is pinned to the top of its author's profile.
+func (p *Post) IsPinned() bool {
+	return p.PinnedAt.Valid
+}
+
+// IsRejected reports whether trust & safety rejected the post, which hides it from lists
+// and redacts it for everyone but its author.
+func (p *Post) IsRejected() bool {
+	return p.ModerationStatus == ModerationStatusRejected
+}
+
+// IsListed reports whether the post shows up in lists other than its author's own.
+func (p *Post) IsListed() bool {
+	return p.Visibility == "" || p.Visibility == PostVisibilityPublic
+}
+
+// SetAuthorSnapshot copies the author's display data onto the post.
+func (p *Post) SetAuthorSnapshot(author *User, at time.Time) {
+	username := author.Username
+	p.AuthorUsername = &username
+	p.AuthorAvatarURL = nil
+	if author.AvatarURL != nil {
+		avatar := *author.AvatarURL
+		p.AuthorAvatarURL = &avatar
+	}
+	p.AuthorSnapshotAt = pgtype.Timestamptz{Time: at, Valid: true}
+}
+
+// AuthorFromSnapshot builds the author from the post's snapshot of their display data, or
+// returns nil when the post has none. Only the id, username and avatar are set.
+func (p *Post) AuthorFromSnapshot() *User {
+	if p.AuthorUsername == nil {
+		return nil
+	}
+	author := &User{ID: p.AuthorID, Username: *p.AuthorUsername}
+	if p.AuthorAvatarURL != nil {
+		avatar := *p.AuthorAvatarURL
+		author.AvatarURL = &avatar
+	}
+	return author
+}
+
+func (p *Post) Clone() *Post {
+	if p == nil {
+		return nil
+	}
+	clone := *p
+	if p.Content != nil {
+		content := *p.Content
+		clone.Content = &content
+	}
+	if p.Language != nil {
+		lang := *p.Language
+		clone.Language = &lang
+	}
+	if p.RejectionReason != nil {
+		reason := *p.RejectionReason
+		clone.RejectionReason = &reason
+	}
+	if p.AuthorUsername != nil {
+		username := *p.AuthorUsername
+		clone.AuthorUsername = &username
+	}
+	if p.AuthorAvatarURL != nil {
+		avatar := *p.AuthorAvatarURL
+		clone.AuthorAvatarURL = &avatar
+	}
+	return &clone
 }
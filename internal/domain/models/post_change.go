@@ -0,0 +1,120 @@
+package model
+
+// PostChangeSummary records what one UpdatePost call changed, collected inside its
+// transaction, so callers need not diff the post before and after.
+type PostChangeSummary struct {
+	// FieldsChanged names the post fields whose value changed: "title", "content",
+	// "visibility" and "language", in that order.
+	FieldsChanged []string
+	// TagsAdded are the tags the post gained; TagsCreated is the part of them that did not
+	// exist before the update. TagsRemoved are the tags the post lost.
+	TagsAdded   []*Tag
+	TagsCreated []*Tag
+	TagsRemoved []*Tag
+	// MediaAttached are the media rows that replaced MediaDetached, with their new ids.
+	MediaAttached []*PostMedia
+	MediaDetached []int64
+}
+
+// IsEmpty reports whether the update left the post as it was, apart from updated_at.
+func (c *PostChangeSummary) IsEmpty() bool {
+	return len(c.FieldsChanged) == 0 && len(c.TagsAdded) == 0 && len(c.TagsRemoved) == 0 &&
+		len(c.MediaAttached) == 0 && len(c.MediaDetached) == 0
+}
+
+func (c *PostChangeSummary) Clone() *PostChangeSummary {
+	if c == nil {
+		return nil
+	}
+	clone := &PostChangeSummary{
+		TagsAdded:   cloneTags(c.TagsAdded),
+		TagsCreated: cloneTags(c.TagsCreated),
+		TagsRemoved: cloneTags(c.TagsRemoved),
+	}
+	if c.FieldsChanged != nil {
+		clone.FieldsChanged = append([]string(nil), c.FieldsChanged...)
+	}
+	if c.MediaAttached != nil {
+		clone.MediaAttached = make([]*PostMedia, len(c.MediaAttached))
+		for i, m := range c.MediaAttached {
+			clone.MediaAttached[i] = m.Clone()
+		}
+	}
+	if c.MediaDetached != nil {
+		clone.MediaDetached = append([]int64(nil), c.MediaDetached...)
+	}
+	return clone
+}
+
+// ChangedPostFields names the fields that differ between two versions of a post, in the order
+// of PostChangeSummary.FieldsChanged. An empty visibility reads as public.
+func ChangedPostFields(before, after *Post) []string {
+	var fields []string
+	if before.Title != after.Title {
+		fields = append(fields, "title")
+	}
+	if contentOf(before) != contentOf(after) {
+		fields = append(fields, "content")
+	}
+	if visibilityOf(before) != visibilityOf(after) {
+		fields = append(fields, "visibility")
+	}
+	if languageOf(before) != languageOf(after) {
+		fields = append(fields, "language")
+	}
+	return fields
+}
+
+// DiffTags returns the tags of after that are not in before and the tags of before that are
+// not in after, compared by id.
+func DiffTags(before, after []*Tag) (added, removed []*Tag) {
+	beforeIDs := make(map[int64]struct{}, len(before))
+	for _, t := range before {
+		beforeIDs[t.ID] = struct{}{}
+	}
+	afterIDs := make(map[int64]struct{}, len(after))
+	for _, t := range after {
+		afterIDs[t.ID] = struct{}{}
+		if _, ok := beforeIDs[t.ID]; !ok {
+			added = append(added, t)
+		}
+	}
+	for _, t := range before {
+		if _, ok := afterIDs[t.ID]; !ok {
+			removed = append(removed, t)
+		}
+	}
+	return added, removed
+}
+
+func contentOf(p *Post) string {
+	if p.Content == nil {
+		return ""
+	}
+	return *p.Content
+}
+
+func languageOf(p *Post) string {
+	if p.Language == nil {
+		return ""
+	}
+	return *p.Language
+}
+
+func visibilityOf(p *Post) PostVisibility {
+	if p.Visibility == "" {
+		return PostVisibilityPublic
+	}
+	return p.Visibility
+}
+
+func cloneTags(tags []*Tag) []*Tag {
+	if tags == nil {
+		return nil
+	}
+	clone := make([]*Tag, len(tags))
+	for i, t := range tags {
+		clone[i] = t.Clone()
+	}
+	return clone
+}
@@ -1,8 +1,107 @@
 package model
 
+import "time"
+
+// PostDetailed is cached as JSON. A change that breaks decoding of existing entries (a renamed
+// or retyped field) must bump postPayloadVersion in the Redis cache.
 type PostDetailed struct {
-	Post   *Post        `json:"post,omitempty"`
+	Post *Post `json:"post,omitempty"`
+	// Author is shared by every post of a list with the same author; treat it as read-only.
 	Author *User        `json:"author,omitempty"`
 	Media  []*PostMedia `json:"media,omitempty"`
-	Tags   []*Tag       `json:"tags,omitempty"`
+	// MediaTotal is how many media the post has. Media holds only the first of them by
+	// position, up to limits.MaxEmbeddedMedia (see CapMedia); GetPostMedia pages through the
+	// rest.
+	MediaTotal int    `json:"media_total,omitempty"`
+	Tags       []*Tag `json:"tags,omitempty"`
+	// FailedTags lists requested tags that could not be attached when the post was created.
+	// It describes one CreatePost call and is never cached.
+	FailedTags []string `json:"-"`
+	// Changes describes what the UpdatePost call that returned the post changed. It is never
+	// cached.
+	Changes *PostChangeSummary `json:"-"`
+	// HasMoreContent reports that Post.Content is an excerpt; see Summary. Cached posts are
+	// always full, so it is never cached.
+	HasMoreContent bool `json:"-"`
+	// Redacted reports that the post was stripped by RedactedFor. A redacted post is never
+	// cached: the cache holds the full post and redacts it per requester.
+	Redacted bool `json:"-"`
+	// AuthorFromSnapshot reports that Author was built from the post's snapshot of its
+	// author's display data rather than fetched from the user service, so it holds only the
+	// id, username and avatar and must not stand in for the user elsewhere.
+	AuthorFromSnapshot bool `json:"-"`
+	// CachedAt is when the cache entry the post was read from was written, and CacheTTL how
+	// long the entry had left when it was read. Both are zero for a post read from the
+	// database, and CachedAt for an entry written before entries recorded it.
+	CachedAt time.Time     `json:"-"`
+	CacheTTL time.Duration `json:"-"`
+}
+
+// Clone returns a deep copy, so a result shared between callers can be modified by each of them independently.
+func (p *PostDetailed) Clone() *PostDetailed {
+	if p == nil {
+		return nil
+	}
+	clone := &PostDetailed{
+		Post:   p.Post.Clone(),
+		Author: p.Author.Clone(),
+	}
+	if p.Media != nil {
+		clone.Media = make([]*PostMedia, len(p.Media))
+		for i, m := range p.Media {
+			clone.Media[i] = m.Clone()
+		}
+	}
+	if p.Tags != nil {
+		clone.Tags = make([]*Tag, len(p.Tags))
+		for i, t := range p.Tags {
+			clone.Tags[i] = t.Clone()
+		}
+	}
+	if p.FailedTags != nil {
+		clone.FailedTags = append([]string(nil), p.FailedTags...)
+	}
+	clone.MediaTotal = p.MediaTotal
+	clone.Changes = p.Changes.Clone()
+	clone.HasMoreContent = p.HasMoreContent
+	clone.Redacted = p.Redacted
+	clone.AuthorFromSnapshot = p.AuthorFromSnapshot
+	clone.CachedAt = p.CachedAt
+	clone.CacheTTL = p.CacheTTL
+	return clone
+}
+
+// CapMedia records how many media p has in MediaTotal and keeps only the first limit of
+// them; a limit below 1 keeps them all. p must hold all of its media.
+func (p *PostDetailed) CapMedia(limit int) {
+	p.MediaTotal = len(p.Media)
+	if limit > 0 && len(p.Media) > limit {
+		p.Media = p.Media[:limit:limit]
+	}
+}
+
+// HasMoreMedia reports that Media holds fewer media than the post has.
+func (p *PostDetailed) HasMoreMedia() bool {
+	return p.MediaTotal > len(p.Media)
+}
+
+// RedactedFor returns the post as requesterID may see it. A rejected post keeps its ids,
+// statuses, rejection reason, author and tags for everyone but its author, so clients can
+// render a placeholder, but loses its title, content and media. Any other post, and a
+// rejected post read by its author, is returned as is.
+func (p *PostDetailed) RedactedFor(requesterID *int64) *PostDetailed {
+	if p == nil || p.Post == nil || !p.Post.IsRejected() {
+		return p
+	}
+	if requesterID != nil && *requesterID == p.Post.AuthorID {
+		return p
+	}
+	redacted := p.Clone()
+	redacted.Post.Title = ""
+	redacted.Post.Content = nil
+	redacted.Media = []*PostMedia{}
+	redacted.MediaTotal = 0
+	redacted.HasMoreContent = false
+	redacted.Redacted = true
+	return redacted
 }
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// PostEventType names what happened to a post.
+type PostEventType string
+
+const (
+	PostEventCreated PostEventType = "post_created"
+	PostEventUpdated PostEventType = "post_updated"
+	PostEventDeleted PostEventType = "post_deleted"
+)
+
+// PostEvent tells subscribers that a post changed. It carries ids only: a subscriber reads
+// the post itself, and so sees only what it may.
+type PostEvent struct {
+	Type       PostEventType `json:"type"`
+	PostID     int64         `json:"post_id"`
+	AuthorID   int64         `json:"author_id"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
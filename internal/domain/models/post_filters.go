@@ -1,12 +1,83 @@
 package model
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"cmp"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 type PostFilters struct {
-	AuthorID      *int64
+	AuthorID *int64
+	// AuthorIDs matches posts by any of these authors; empty means no author filter.
+	// It cannot be combined with AuthorID.
+	AuthorIDs     []int64
 	TagNames      []string
 	CreatedAfter  *pgtype.Timestamptz
 	CreatedBefore *pgtype.Timestamptz
-	Limit         *int
-	Offset        *int
+	// UpdatedAfter keeps posts changed strictly after this instant, for incremental sync.
+	// Every update bumps updated_at, including tags-only and media-only ones.
+	UpdatedAfter *pgtype.Timestamptz
+	// HasMedia keeps posts with at least one media item when true and posts without media
+	// when false. MediaType keeps posts with at least one item of that type, so a post with
+	// both images and videos matches either type.
+	HasMedia  *bool
+	MediaType *MediaType
+	// Language keeps posts written in exactly that BCP-47 language; posts without a language
+	// match only when it is nil.
+	Language *string
+	// Source keeps posts created from that client; see PostSource.
+	Source *PostSource
+	Limit  *int
+	Offset *int
+	// RequesterID sees their own drafts and scheduled posts; when it equals AuthorID the list
+	// also keeps their unlisted and private posts.
+	RequesterID *int64
+	// ExcludedAuthorIDs drops the posts of these authors. ListPosts fills it with the authors
+	// the requester has blocked.
+	ExcludedAuthorIDs []int64
+	// SortBy and SortOrder default to created_at and desc when empty. Posts that tie on
+	// the sort column are ordered by id in the same direction.
+	SortBy    PostSortField
+	SortOrder SortOrder
+	// View selects full posts or summaries; empty means PostViewFull. It only shapes the
+	// result and never reaches the repository.
+	View PostView
+	// RecentPostIDs are posts the requester wrote moments ago. On their own timeline ListPosts
+	// adds those the repository missed to the page they sort into. Set by the cache decorator
+	// only; it never reaches the repository.
+	RecentPostIDs []int64
+}
+
+// ListsOwnPosts reports whether the requester lists their own posts, the only list that keeps
+// unlisted and private ones.
+func (f PostFilters) ListsOwnPosts() bool {
+	return f.RequesterID != nil && f.AuthorID != nil && *f.RequesterID == *f.AuthorID
+}
+
+// ListsOwnTimeline reports whether the requester lists their own posts with no other filter
+// than paging, sorting and the view: every post they write belongs to such a list.
+func (f PostFilters) ListsOwnTimeline() bool {
+	return f.ListsOwnPosts() && len(f.TagNames) == 0 && f.CreatedAfter == nil && f.CreatedBefore == nil &&
+		f.UpdatedAfter == nil && f.HasMedia == nil && f.MediaType == nil && f.Language == nil &&
+		f.Source == nil
+}
+
+// ComparePosts orders two posts as a list with these filters does: an author's pinned post
+// first, then by the sort field in the sort order, ties by id in the same direction.
+func (f PostFilters) ComparePosts(a, b *Post) int {
+	if f.AuthorID != nil && a.IsPinned() != b.IsPinned() {
+		if a.IsPinned() {
+			return -1
+		}
+		return 1
+	}
+	ka, kb := a.CreatedAt.Time, b.CreatedAt.Time
+	if f.SortBy == SortByUpdatedAt {
+		ka, kb = a.UpdatedAt.Time, b.UpdatedAt.Time
+	}
+	c := cmp.Or(kb.Compare(ka), cmp.Compare(b.ID, a.ID))
+	if f.SortOrder == SortAsc {
+		return -c
+	}
+	return c
 }
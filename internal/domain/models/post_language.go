@@ -0,0 +1,55 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguages are the languages a post may be written in unless configured otherwise.
+var DefaultLanguages = []string{"en", "ru"}
+
+// ParseLanguage returns the canonical form of a BCP-47 language tag: "EN" becomes "en" and
+// "en-us" becomes "en-US".
+func ParseLanguage(raw string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", errors.New("must be a BCP-47 language tag")
+	}
+	return tag.String(), nil
+}
+
+// NormalizeLanguage returns the canonical form of raw. A tag that does not parse is kept as
+// is for validation to report.
+func NormalizeLanguage(raw string) string {
+	if canonical, err := ParseLanguage(raw); err == nil {
+		return canonical
+	}
+	return raw
+}
+
+// Languages is the allowlist of languages, in canonical form, a post may be written in.
+type Languages []string
+
+// Allows reports whether lang, as returned by ParseLanguage, is on the allowlist. A tag is
+// compared whole: "en" does not allow "en-US".
+func (l Languages) Allows(lang string) bool {
+	return slices.Contains(l, lang)
+}
+
+// Check describes why lang may not be used, or returns "" if it may.
+func (l Languages) Check(lang string) string {
+	canonical, err := ParseLanguage(lang)
+	if err != nil {
+		return err.Error()
+	}
+	if !l.Allows(canonical) {
+		if len(l) == 0 {
+			return "no languages are allowed"
+		}
+		return "must be one of " + strings.Join(l, ", ") + ", got " + canonical
+	}
+	return ""
+}
@@ -1,19 +1,53 @@
 package model
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 )
 
+// ErrMediaDuplicate is returned when media would share a position or a URL with another item
+// of the same post. custom_errors has no equivalent in proto v0.1.22.
+var ErrMediaDuplicate = errors.New("media position and url must be unique within a post")
+
 type PostMedia struct {
 	ID        int64              `json:"id"`
 	PostID    int64              `json:"post_id"`
 	URL       string             `json:"url"`
 	Type      MediaType          `json:"type"`
 	Position  int32              `json:"position"`
+	Width     *int32             `json:"width,omitempty"`
+	Height    *int32             `json:"height,omitempty"`
+	SizeBytes *int64             `json:"size_bytes,omitempty"`
+	AltText   *string            `json:"alt_text,omitempty"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+// Clone returns a copy that shares no optional metadata with the original.
+func (m *PostMedia) Clone() *PostMedia {
+	if m == nil {
+		return nil
+	}
+	clone := *m
+	clone.Width = clonePtr(m.Width)
+	clone.Height = clonePtr(m.Height)
+	clone.SizeBytes = clonePtr(m.SizeBytes)
+	clone.AltText = clonePtr(m.AltText)
+	return &clone
+}
+
+func clonePtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// MediaType is the kind of a media item.
 type MediaType string
 
 const (
@@ -21,12 +55,36 @@ const (
 	MediaTypeVideo MediaType = "video"
 )
 
+// MediaTypes lists every valid MediaType. A new type is added here and to the CHECK
+// constraint on post_media.type in a new migration.
+var MediaTypes = []MediaType{MediaTypeImage, MediaTypeVideo}
+
 func (t MediaType) IsValid() error {
-	switch t {
-	case MediaTypeImage, MediaTypeVideo:
+	if slices.Contains(MediaTypes, t) {
 		return nil
 	}
-	return fmt.Errorf("invalid media type: %s", t)
+	return fmt.Errorf("invalid media type: %q", string(t))
+}
+
+// ParseMediaType returns raw as a MediaType. A value that is not one fails with
+// ErrInvalidInput naming it.
+func ParseMediaType(raw string) (MediaType, error) {
+	t := MediaType(raw)
+	if err := t.IsValid(); err != nil {
+		return "", fmt.Errorf("%w: %w", custom_errors.ErrInvalidInput, err)
+	}
+	return t, nil
+}
+
+// ValidateMediaTypes fails with ErrInvalidInput on the first item of media whose type is
+// not valid. Repositories check it before writing.
+func ValidateMediaTypes(media []*PostMedia) error {
+	for _, md := range media {
+		if _, err := ParseMediaType(string(md.Type)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (t *MediaType) UnmarshalText(text []byte) error {
@@ -0,0 +1,26 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+func TestParseMediaType(t *testing.T) {
+	for _, want := range model.MediaTypes {
+		got, err := model.ParseMediaType(string(want))
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	for _, raw := range []string{"", "img", "Image", " video"} {
+		got, err := model.ParseMediaType(raw)
+		assert.ErrorIs(t, err, custom_errors.ErrInvalidInput, raw)
+		assert.Contains(t, err.Error(), `"`+raw+`"`, "the error names the offending value")
+		assert.Empty(t, got)
+	}
+}
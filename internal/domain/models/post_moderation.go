@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ModerationStatus is where a post stands with trust & safety. Posts start approved; an
+// empty status, read before the column existed, means approved too.
+type ModerationStatus string
+
+const (
+	ModerationStatusApproved    ModerationStatus = "approved"
+	ModerationStatusUnderReview ModerationStatus = "under_review"
+	ModerationStatusRejected    ModerationStatus = "rejected"
+)
+
+// ModerationStatuses lists every status, for validation.
+var ModerationStatuses = []ModerationStatus{ModerationStatusApproved, ModerationStatusUnderReview, ModerationStatusRejected}
+
+// RejectionReason is the machine-readable reason a post was rejected for, which clients map
+// to the text of their "content unavailable" placeholder.
+type RejectionReason string
+
+const (
+	RejectionReasonSpam           RejectionReason = "spam"
+	RejectionReasonHarassment     RejectionReason = "harassment"
+	RejectionReasonHateSpeech     RejectionReason = "hate_speech"
+	RejectionReasonViolence       RejectionReason = "violence"
+	RejectionReasonAdultContent   RejectionReason = "adult_content"
+	RejectionReasonMisinformation RejectionReason = "misinformation"
+	RejectionReasonCopyright      RejectionReason = "copyright"
+	RejectionReasonOther          RejectionReason = "other"
+)
+
+// RejectionReasons lists every reason, for validation.
+var RejectionReasons = []RejectionReason{
+	RejectionReasonSpam,
+	RejectionReasonHarassment,
+	RejectionReasonHateSpeech,
+	RejectionReasonViolence,
+	RejectionReasonAdultContent,
+	RejectionReasonMisinformation,
+	RejectionReasonCopyright,
+	RejectionReasonOther,
+}
+
+// ValidateModerationStatus checks a status change: a rejection needs one of RejectionReasons,
+// and the other statuses take no reason.
+func ValidateModerationStatus(status ModerationStatus, reason RejectionReason) error {
+	var violations []FieldViolation
+	if !slices.Contains(ModerationStatuses, status) {
+		violations = append(violations, FieldViolation{
+			Field:       "moderation_status",
+			Description: fmt.Sprintf("must be one of %v, got %q", ModerationStatuses, status),
+		})
+	}
+	switch {
+	case status == ModerationStatusRejected && !slices.Contains(RejectionReasons, reason):
+		violations = append(violations, FieldViolation{
+			Field:       "rejection_reason",
+			Description: fmt.Sprintf("a rejection must give one of %v, got %q", RejectionReasons, reason),
+		})
+	case status != ModerationStatusRejected && reason != "":
+		violations = append(violations, FieldViolation{
+			Field:       "rejection_reason",
+			Description: fmt.Sprintf("only a rejection takes a reason, the status is %q", status),
+		})
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
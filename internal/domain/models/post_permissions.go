@@ -0,0 +1,18 @@
+package model
+
+// PostPermissions is what a requester may do with a post. It depends on the requester, so it
+// is computed per request and never cached with the post.
+type PostPermissions struct {
+	IsAuthor  bool
+	CanEdit   bool
+	CanDelete bool
+}
+
+// PermissionsFor returns the permissions of requesterID on post; a nil requester is anonymous
+// and may do nothing. Only the author may edit or delete for now.
+func PermissionsFor(post *Post, requesterID *int64) PostPermissions {
+	if post == nil || requesterID == nil || post.AuthorID != *requesterID {
+		return PostPermissions{}
+	}
+	return PostPermissions{IsAuthor: true, CanEdit: true, CanDelete: true}
+}
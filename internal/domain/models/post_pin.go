@@ -0,0 +1,9 @@
+package model
+
+// PinChange is the outcome of pinning or unpinning a post: the post as it is now and the
+// posts whose pinned state changed, which callers use to invalidate cached copies. Pinning
+// a post unpins the one pinned before it, so that one is affected too.
+type PinChange struct {
+	Post            *PostDetailed
+	AffectedPostIDs []int64
+}
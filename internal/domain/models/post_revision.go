@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// DefaultMaxRevisionsPerPost is how many revisions of a post are kept unless configured otherwise.
+const DefaultMaxRevisionsPerPost = 20
+
+// PostRevision is the title and content a post had before an edit that changed either.
+// Revision numbers a post's edits from 1 and is never reused, even after older revisions are
+// pruned.
+type PostRevision struct {
+	ID        int64
+	PostID    int64
+	Revision  int64
+	Title     string
+	Content   *string
+	EditorID  int64
+	CreatedAt time.Time
+}
+
+// ChangesText reports whether an edit that turned before into after changed the title or
+// the content, the fields a revision keeps.
+func ChangesText(before, after *Post) bool {
+	return before.Title != after.Title || contentOf(before) != contentOf(after)
+}
@@ -0,0 +1,35 @@
+package model
+
+import "fmt"
+
+// PostSortField names a column ListPosts can order by. Repositories map it to a column
+// themselves; the value is never put into SQL as is.
+type PostSortField string
+
+const (
+	SortByCreatedAt PostSortField = "created_at"
+	SortByUpdatedAt PostSortField = "updated_at"
+)
+
+func (f PostSortField) IsValid() error {
+	switch f {
+	case SortByCreatedAt, SortByUpdatedAt:
+		return nil
+	}
+	return fmt.Errorf("invalid sort field: %s", f)
+}
+
+type SortOrder string
+
+const (
+	SortDesc SortOrder = "desc"
+	SortAsc  SortOrder = "asc"
+)
+
+func (o SortOrder) IsValid() error {
+	switch o {
+	case SortDesc, SortAsc:
+		return nil
+	}
+	return fmt.Errorf("invalid sort order: %s", o)
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// PostSource is the client or application a post was created from, for analytics.
+type PostSource string
+
+const (
+	PostSourceWeb     PostSource = "web"
+	PostSourceIOS     PostSource = "ios"
+	PostSourceAndroid PostSource = "android"
+	// PostSourceImport posts come from a bulk import.
+	PostSourceImport PostSource = "import"
+	// PostSourceUnknown is the source of a post whose client sent none or one not on the
+	// allowlist, and of posts created before sources were recorded.
+	PostSourceUnknown PostSource = "unknown"
+)
+
+// MaxPostSourceLength caps a source name, as the posts.source column does.
+const MaxPostSourceLength = 32
+
+// DefaultPostSources are the sources recorded unless configured otherwise.
+var DefaultPostSources = PostSources{PostSourceWeb, PostSourceIOS, PostSourceAndroid, PostSourceImport}
+
+var postSourcePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ParsePostSource returns raw trimmed and lowercased if it is a well-formed source name:
+// lowercase letters, digits, "-" and "_", up to MaxPostSourceLength.
+func ParsePostSource(raw string) (PostSource, error) {
+	source := strings.ToLower(strings.TrimSpace(raw))
+	if len(source) > MaxPostSourceLength || !postSourcePattern.MatchString(source) {
+		return "", fmt.Errorf("must be at most %d lowercase letters, digits, - or _", MaxPostSourceLength)
+	}
+	return PostSource(source), nil
+}
+
+// PostSources is the allowlist of sources a post may record. PostSourceUnknown is always
+// allowed.
+type PostSources []PostSource
+
+// Allows reports whether source, as returned by ParsePostSource, is on the allowlist.
+func (s PostSources) Allows(source PostSource) bool {
+	return source == PostSourceUnknown || slices.Contains(s, source)
+}
+
+// Resolve returns the source a post sent as raw is recorded with. A source that is empty,
+// malformed or not on the allowlist is PostSourceUnknown rather than an error, so clients
+// that send none or a new one keep working.
+func (s PostSources) Resolve(raw string) PostSource {
+	source, err := ParsePostSource(raw)
+	if err != nil || !s.Allows(source) {
+		return PostSourceUnknown
+	}
+	return source
+}
+
+// Check describes why source may not be filtered by, or returns "" if it may.
+func (s PostSources) Check(source PostSource) string {
+	if !s.Allows(source) {
+		names := append(s.Strings(), string(PostSourceUnknown))
+		return "must be one of " + strings.Join(names, ", ") + ", got " + string(source)
+	}
+	return ""
+}
+
+// Strings returns the sources as strings.
+func (s PostSources) Strings() []string {
+	names := make([]string, len(s))
+	for i, source := range s {
+		names[i] = string(source)
+	}
+	return names
+}
@@ -0,0 +1,20 @@
+package model
+
+import "fmt"
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusPublished PostStatus = "published"
+	// PostStatusScheduled is a post waiting for its ScheduledAt time; the scheduler publishes it.
+	PostStatusScheduled PostStatus = "scheduled"
+)
+
+func (s PostStatus) IsValid() error {
+	switch s {
+	case PostStatusDraft, PostStatusPublished, PostStatusScheduled:
+		return nil
+	}
+	return fmt.Errorf("invalid post status: %s", s)
+}
@@ -0,0 +1,396 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+const (
+	DefaultMaxContentLength = 50000
+	DefaultMaxTags          = 10
+	DefaultMaxFeedAuthors   = 500
+	// DefaultHydrationConcurrency is how many posts of a list page are hydrated at once.
+	DefaultHydrationConcurrency = 8
+	DefaultListLimit            = 20
+	DefaultMaxListLimit         = 100
+	// DefaultExcerptLength is how many runes of content a post summary keeps.
+	DefaultExcerptLength = 280
+	// DefaultMaxEmbeddedMedia is how many media a post carries in a response; GetPostMedia
+	// returns the rest.
+	DefaultMaxEmbeddedMedia = 20
+	// MaxMediaPageSize caps the media one GetPostMedia call may ask for.
+	MaxMediaPageSize = 100
+
+	// MaxPostsByIDs caps how many posts one GetPostsByIDs call may ask for.
+	MaxPostsByIDs = 100
+
+	// MaxTagSuggestions caps, and is the default of, the tags one SuggestTags call returns.
+	MaxTagSuggestions = 10
+	// MaxTagPrefixLength is the longest prefix SuggestTags accepts; no longer tag exists.
+	MaxTagPrefixLength = maxTagLength
+
+	minTitleLength = 3
+	maxTitleLength = 200
+	maxTagLength   = 50
+	maxAltText     = 500
+	// maxMediaItems matches the media limit of CreatePost requests.
+	maxMediaItems = 9
+)
+
+var tagSlugPattern = regexp.MustCompile(`^[a-z0-9]+(?:[-_][a-z0-9]+)*$`)
+
+// PostLimits bounds what the service accepts for a post, whatever the caller.
+type PostLimits struct {
+	MaxContentLength int
+	MaxTags          int
+	// MaxFeedAuthors caps PostFilters.AuthorIDs, which ends up as one array parameter.
+	MaxFeedAuthors int
+	// HydrationConcurrency bounds the posts of a ListPosts page whose media and tags are
+	// fetched at the same time; anything below 1 means one at a time.
+	HydrationConcurrency int
+	// DefaultListLimit is the page size of a list without a limit; MaxListLimit is the
+	// largest page a list may ask for.
+	DefaultListLimit int
+	MaxListLimit     int
+	// ExcerptLength is how many runes of content PostViewSummary keeps.
+	ExcerptLength int
+	// MaxEmbeddedMedia is how many media, the first by position, a post carries in a
+	// response and in the cache; anything below 1 means all of them.
+	MaxEmbeddedMedia int
+	// MediaHosts lists the hosts media URLs may point at.
+	MediaHosts MediaHosts
+	// Languages lists the languages a post may be written in.
+	Languages Languages
+	// Sources lists the clients a post may record as its source.
+	Sources PostSources
+	// MaxRevisions is how many revisions of a post are kept; an edit past it prunes the
+	// oldest. Zero keeps them all.
+	MaxRevisions int
+}
+
+func DefaultPostLimits() PostLimits {
+	return PostLimits{
+		MaxContentLength:     DefaultMaxContentLength,
+		MaxTags:              DefaultMaxTags,
+		MaxFeedAuthors:       DefaultMaxFeedAuthors,
+		HydrationConcurrency: DefaultHydrationConcurrency,
+		DefaultListLimit:     DefaultListLimit,
+		MaxListLimit:         DefaultMaxListLimit,
+		ExcerptLength:        DefaultExcerptLength,
+		MaxEmbeddedMedia:     DefaultMaxEmbeddedMedia,
+		// The server passes its configured allowlist; without one any host is accepted.
+		MediaHosts:   MediaHosts{AllowAll: true},
+		Languages:    DefaultLanguages,
+		Sources:      DefaultPostSources,
+		MaxRevisions: DefaultMaxRevisionsPerPost,
+	}
+}
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError lists every invalid field of a post. It unwraps to ErrPostValidation.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.Field + ": " + v.Description
+	}
+	return fmt.Sprintf("%s: %s", custom_errors.ErrPostValidation.Error(), strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return custom_errors.ErrPostValidation
+}
+
+var titleRequired = FieldViolation{Field: "title", Description: "is required for a post without content or media"}
+
+// ValidateCreate checks a new post. The title is optional, but a post without a title,
+// content or media has nothing to show and is rejected.
+func (l PostLimits) ValidateCreate(post *CreatePostDTO) error {
+	var violations []FieldViolation
+	if strings.TrimSpace(post.Title) != "" {
+		violations = l.checkTitle(violations, post.Title)
+	} else if (post.Content == nil || strings.TrimSpace(*post.Content) == "") && len(post.MediaItems) == 0 {
+		violations = append(violations, titleRequired)
+	}
+	if post.Content != nil {
+		violations = l.checkContent(violations, *post.Content)
+	}
+	if post.Status != "" {
+		if err := post.Status.IsValid(); err != nil {
+			violations = append(violations, FieldViolation{Field: "status", Description: err.Error()})
+		}
+	}
+	violations = checkVisibility(violations, post.Visibility)
+	violations = l.checkLanguage(violations, post.Language)
+	violations = l.checkTags(violations, post.Tags)
+	violations = l.checkMedia(violations, post.MediaItems)
+	return toValidationError(violations)
+}
+
+// ValidateUpdate checks only the fields present in the update. A blank title is not checked:
+// it clears the stored one.
+func (l PostLimits) ValidateUpdate(post *UpdatePostDTO) error {
+	var violations []FieldViolation
+	if post.Title != nil && strings.TrimSpace(*post.Title) != "" {
+		violations = l.checkTitle(violations, *post.Title)
+	}
+	if post.Content != nil {
+		violations = l.checkContent(violations, *post.Content)
+	}
+	if post.Visibility != nil {
+		violations = checkVisibility(violations, *post.Visibility)
+	}
+	if post.Language != nil {
+		violations = l.checkLanguage(violations, *post.Language)
+	}
+	if post.ExpectedVersion != nil && *post.ExpectedVersion < 1 {
+		violations = append(violations, FieldViolation{Field: "expected_version", Description: "must be positive"})
+	}
+	violations = l.checkTags(violations, post.Tags)
+	violations = l.checkMedia(violations, post.MediaItems)
+	return toValidationError(violations)
+}
+
+// ValidateUpdated checks the post an update leaves behind, which ValidateUpdate cannot see:
+// as on create, a post without a title, content or media is rejected. hasMedia reports
+// whether the post has media once updated.
+func ValidateUpdated(post *Post, update *UpdatePostDTO, hasMedia bool) error {
+	if hasMedia || !update.LeavesNoText(post) {
+		return nil
+	}
+	return toValidationError([]FieldViolation{titleRequired})
+}
+
+// ValidatePostIDs rejects a batch lookup with more than MaxPostsByIDs ids or a non-positive id.
+func ValidatePostIDs(ids []int64) error {
+	if len(ids) > MaxPostsByIDs {
+		return fmt.Errorf("%w: at most %d post ids, got %d", custom_errors.ErrInvalidInput, MaxPostsByIDs, len(ids))
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return fmt.Errorf("%w: post id must be positive, got %d", custom_errors.ErrInvalidInput, id)
+		}
+	}
+	return nil
+}
+
+// ValidateFilters rejects list filters that name the author both ways, too many authors,
+// a limit outside 1..MaxListLimit, a negative offset, an empty creation window, an empty
+// tag name or an unknown sort.
+func (l PostLimits) ValidateFilters(filters *PostFilters) error {
+	if filters.AuthorID != nil && len(filters.AuthorIDs) > 0 {
+		return fmt.Errorf("%w: author_id and author_ids are mutually exclusive", custom_errors.ErrInvalidInput)
+	}
+	if len(filters.AuthorIDs) > l.MaxFeedAuthors {
+		return fmt.Errorf("%w: at most %d author ids, got %d", custom_errors.ErrInvalidInput, l.MaxFeedAuthors, len(filters.AuthorIDs))
+	}
+	if filters.Limit != nil && (*filters.Limit <= 0 || *filters.Limit > l.MaxListLimit) {
+		return fmt.Errorf("%w: limit must be between 1 and %d, got %d", custom_errors.ErrInvalidInput, l.MaxListLimit, *filters.Limit)
+	}
+	if filters.Offset != nil && *filters.Offset < 0 {
+		return fmt.Errorf("%w: offset must not be negative, got %d", custom_errors.ErrInvalidInput, *filters.Offset)
+	}
+	if after, before := filters.CreatedAfter, filters.CreatedBefore; after != nil && after.Valid && before != nil && before.Valid &&
+		!after.Time.Before(before.Time) {
+		return fmt.Errorf("%w: created_after must be before created_before", custom_errors.ErrInvalidInput)
+	}
+	for _, name := range filters.TagNames {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%w: tag names must not be empty", custom_errors.ErrInvalidInput)
+		}
+	}
+	if filters.MediaType != nil {
+		if err := filters.MediaType.IsValid(); err != nil {
+			return fmt.Errorf("%w: %w", custom_errors.ErrInvalidInput, err)
+		}
+		if filters.HasMedia != nil && !*filters.HasMedia {
+			return fmt.Errorf("%w: media_type needs posts with media", custom_errors.ErrInvalidInput)
+		}
+	}
+	if filters.Language != nil {
+		if problem := l.Languages.Check(*filters.Language); problem != "" {
+			return fmt.Errorf("%w: language %s", custom_errors.ErrInvalidInput, problem)
+		}
+	}
+	if filters.Source != nil {
+		if problem := l.Sources.Check(*filters.Source); problem != "" {
+			return fmt.Errorf("%w: source %s", custom_errors.ErrInvalidInput, problem)
+		}
+	}
+	if filters.SortBy != "" {
+		if err := filters.SortBy.IsValid(); err != nil {
+			return fmt.Errorf("%w: %w", custom_errors.ErrInvalidInput, err)
+		}
+	}
+	if filters.SortOrder != "" {
+		if err := filters.SortOrder.IsValid(); err != nil {
+			return fmt.Errorf("%w: %w", custom_errors.ErrInvalidInput, err)
+		}
+	}
+	if filters.View != "" {
+		if err := filters.View.IsValid(); err != nil {
+			return fmt.Errorf("%w: %w", custom_errors.ErrInvalidInput, err)
+		}
+	}
+	return nil
+}
+
+func (l PostLimits) checkTitle(violations []FieldViolation, title string) []FieldViolation {
+	if n := utf8.RuneCountInString(strings.TrimSpace(title)); n < minTitleLength || n > maxTitleLength {
+		violations = append(violations, FieldViolation{
+			Field:       "title",
+			Description: fmt.Sprintf("must be between %d and %d characters, got %d", minTitleLength, maxTitleLength, n),
+		})
+	}
+	return violations
+}
+
+func (l PostLimits) checkContent(violations []FieldViolation, content string) []FieldViolation {
+	if n := utf8.RuneCountInString(content); n > l.MaxContentLength {
+		violations = append(violations, FieldViolation{
+			Field:       "content",
+			Description: fmt.Sprintf("must be at most %d characters, got %d", l.MaxContentLength, n),
+		})
+	}
+	return violations
+}
+
+// checkVisibility accepts an empty visibility, which keeps the default or the stored value.
+func checkVisibility(violations []FieldViolation, visibility PostVisibility) []FieldViolation {
+	if visibility == "" {
+		return violations
+	}
+	if err := visibility.IsValid(); err != nil {
+		violations = append(violations, FieldViolation{Field: "visibility", Description: err.Error()})
+	}
+	return violations
+}
+
+// checkLanguage accepts an empty language, which leaves the post without one or keeps the
+// stored value.
+func (l PostLimits) checkLanguage(violations []FieldViolation, language string) []FieldViolation {
+	if language == "" {
+		return violations
+	}
+	if problem := l.Languages.Check(language); problem != "" {
+		violations = append(violations, FieldViolation{Field: "language", Description: problem})
+	}
+	return violations
+}
+
+func (l PostLimits) checkTags(violations []FieldViolation, tags []string) []FieldViolation {
+	if len(tags) > l.MaxTags {
+		violations = append(violations, FieldViolation{
+			Field:       "tags",
+			Description: fmt.Sprintf("must contain at most %d tags, got %d", l.MaxTags, len(tags)),
+		})
+	}
+	for i, tag := range tags {
+		if problem := tagNameProblem(tag); problem != "" {
+			violations = append(violations, FieldViolation{Field: fmt.Sprintf("tags[%d]", i), Description: problem})
+		}
+	}
+	return violations
+}
+
+// ValidateTagName checks a single normalized tag name, such as the target of a rename.
+func ValidateTagName(name string) error {
+	if problem := tagNameProblem(name); problem != "" {
+		return toValidationError([]FieldViolation{{Field: "name", Description: problem}})
+	}
+	return nil
+}
+
+func tagNameProblem(name string) string {
+	switch {
+	case name == "":
+		return "must not be empty"
+	case utf8.RuneCountInString(name) > maxTagLength:
+		return fmt.Sprintf("must be at most %d characters", maxTagLength)
+	case !tagSlugPattern.MatchString(name):
+		return "must be lowercase letters and digits separated by '-' or '_'"
+	}
+	return ""
+}
+
+func (l PostLimits) checkMedia(violations []FieldViolation, media []*PostMediaInput) []FieldViolation {
+	if len(media) > maxMediaItems {
+		violations = append(violations, FieldViolation{
+			Field:       "media",
+			Description: fmt.Sprintf("must contain at most %d media, got %d", maxMediaItems, len(media)),
+		})
+	}
+	positions := make(map[int32]int, len(media))
+	urls := make(map[string]int, len(media))
+	for i, m := range media {
+		if m == nil {
+			continue
+		}
+		field := fmt.Sprintf("media[%d]", i)
+		violations = l.checkMediaURL(violations, field+".url", m.URL)
+		if err := m.Type.IsValid(); err != nil {
+			violations = append(violations, FieldViolation{Field: field + ".type", Description: err.Error()})
+		}
+		if first, ok := positions[m.Position]; ok {
+			violations = append(violations, FieldViolation{
+				Field:       field + ".position",
+				Description: fmt.Sprintf("duplicates the position of media[%d]", first),
+			})
+		} else {
+			positions[m.Position] = i
+		}
+		if first, ok := urls[m.URL]; ok {
+			violations = append(violations, FieldViolation{
+				Field:       field + ".url",
+				Description: fmt.Sprintf("duplicates the url of media[%d]", first),
+			})
+		} else {
+			urls[m.URL] = i
+		}
+		if m.Width != nil && *m.Width < 0 {
+			violations = append(violations, FieldViolation{Field: field + ".width", Description: "must not be negative"})
+		}
+		if m.Height != nil && *m.Height < 0 {
+			violations = append(violations, FieldViolation{Field: field + ".height", Description: "must not be negative"})
+		}
+		if m.SizeBytes != nil && *m.SizeBytes < 0 {
+			violations = append(violations, FieldViolation{Field: field + ".size_bytes", Description: "must not be negative"})
+		}
+		if m.AltText != nil && utf8.RuneCountInString(*m.AltText) > maxAltText {
+			violations = append(violations, FieldViolation{
+				Field:       field + ".alt_text",
+				Description: fmt.Sprintf("must be at most %d characters", maxAltText),
+			})
+		}
+	}
+	return violations
+}
+
+func (l PostLimits) checkMediaURL(violations []FieldViolation, field, raw string) []FieldViolation {
+	_, host, err := NormalizeMediaURL(raw)
+	if err != nil {
+		return append(violations, FieldViolation{Field: field, Description: err.Error()})
+	}
+	if !l.MediaHosts.Allows(host) {
+		return append(violations, FieldViolation{Field: field, Description: fmt.Sprintf("host %s is not an allowed media host", host)})
+	}
+	return violations
+}
+
+func toValidationError(violations []FieldViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PostView selects how much of each post ListPosts returns. The empty view is PostViewFull.
+type PostView string
+
+const (
+	PostViewFull PostView = "full"
+	// PostViewSummary cuts content to an excerpt and keeps only the first media item, for
+	// list pages that link to the full post.
+	PostViewSummary PostView = "summary"
+)
+
+func (v PostView) IsValid() error {
+	switch v {
+	case PostViewFull, PostViewSummary:
+		return nil
+	}
+	return fmt.Errorf("invalid post view: %s", v)
+}
+
+// Excerpt returns content cut to at most maxRunes runes and whether anything was cut. The cut
+// falls on the last whitespace within the limit, so no word is split, unless the first word
+// alone is longer than the limit. Runes are never split, so the excerpt stays valid UTF-8.
+func Excerpt(content string, maxRunes int) (string, bool) {
+	if utf8.RuneCountInString(content) <= maxRunes {
+		return content, false
+	}
+
+	end := 0
+	for i := 0; i < maxRunes; i++ {
+		_, size := utf8.DecodeRuneInString(content[end:])
+		end += size
+	}
+	excerpt := content[:end]
+
+	// The cut is on a word boundary when the next rune is a space.
+	if next, _ := utf8.DecodeRuneInString(content[end:]); !unicode.IsSpace(next) {
+		if space := strings.LastIndexFunc(excerpt, unicode.IsSpace); space > 0 {
+			excerpt = excerpt[:space]
+		}
+	}
+	return strings.TrimRightFunc(excerpt, unicode.IsSpace), true
+}
+
+// Summary returns a copy of p in PostViewSummary: content cut with Excerpt and at most the
+// first media item. HasMoreContent reports whether content was cut. p itself is not modified,
+// so a cached post can be summarized.
+func (p *PostDetailed) Summary(excerptLength int) *PostDetailed {
+	summary := p.Clone()
+	if summary == nil {
+		return nil
+	}
+	if summary.Post != nil && summary.Post.Content != nil {
+		excerpt, cut := Excerpt(*summary.Post.Content, excerptLength)
+		summary.Post.Content = &excerpt
+		summary.HasMoreContent = cut
+	}
+	if len(summary.Media) > 1 {
+		summary.Media = summary.Media[:1]
+	}
+	return summary
+}
@@ -0,0 +1,23 @@
+package model
+
+import "fmt"
+
+// PostVisibility is who may see a post, independent of its status.
+type PostVisibility string
+
+const (
+	// PostVisibilityPublic posts are listed and readable by anyone.
+	PostVisibilityPublic PostVisibility = "public"
+	// PostVisibilityUnlisted posts are readable by id but left out of lists.
+	PostVisibilityUnlisted PostVisibility = "unlisted"
+	// PostVisibilityPrivate posts are readable and listed only for their author.
+	PostVisibilityPrivate PostVisibility = "private"
+)
+
+func (v PostVisibility) IsValid() error {
+	switch v {
+	case PostVisibilityPublic, PostVisibilityUnlisted, PostVisibilityPrivate:
+		return nil
+	}
+	return fmt.Errorf("invalid post visibility: %s", v)
+}
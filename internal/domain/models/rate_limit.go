@@ -0,0 +1,26 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+type RateLimitRule struct {
+	Limit  int           `json:"limit"`
+	Window time.Duration `json:"window"`
+}
+
+type RateLimitExceededError struct {
+	Operation  string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitExceededError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", custom_errors.ErrRateLimitExceeded.Error(), e.Operation, e.RetryAfter)
+}
+
+func (e *RateLimitExceededError) Unwrap() error {
+	return custom_errors.ErrRateLimitExceeded
+}
@@ -1,6 +1,52 @@
 package model
 
+import "strings"
+
 type Tag struct {
 	ID   int64  `json:"id"`
 	Name string `json:"name"`
+	// PostCount is how many posts carry the tag. It is only filled when the caller asks for counts.
+	PostCount *int64 `json:"post_count,omitempty"`
+}
+
+func (t *Tag) Clone() *Tag {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	clone.PostCount = clonePtr(t.PostCount)
+	return &clone
+}
+
+// NormalizeTagName trims the name, lowercases it and replaces runs of inner whitespace
+// with a single '-', so "  Go  Lang " is stored as "go-lang".
+func NormalizeTagName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
+
+// NormalizeTags normalizes every name and drops repeats, keeping the first occurrence.
+// Names that normalize to "" are kept so validation can reject them. A nil slice stays nil:
+// for updates it means "leave tags unchanged".
+func NormalizeTags(names []string) []string {
+	if names == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		n := NormalizeTagName(name)
+		if n != "" && seen[n] {
+			continue
+		}
+		seen[n] = true
+		normalized = append(normalized, n)
+	}
+	return normalized
+}
+
+// TagChange is the outcome of a tag rename or merge: the tag that remains and the posts
+// whose tag lists changed, which callers use to invalidate cached copies.
+type TagChange struct {
+	Tag             *Tag
+	AffectedPostIDs []int64
 }
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+// MaxPolledTags caps the tags one HasPostsSince poll may ask about.
+const MaxPolledTags = 50
+
+// TagActivity answers whether the polled tags have new posts. HasPosts holds every polled
+// tag, by normalized name, and is true for a tag with a listed post that became visible after
+// the poll's since (see Post.VisibleSince). Newest is when the newest such post did, zero when
+// no tag has one.
+// Cached reports whether the answer came from the cache without a database query.
+type TagActivity struct {
+	HasPosts map[string]bool `json:"has_posts"`
+	Newest   time.Time       `json:"newest"`
+	Cached   bool            `json:"cached"`
+	// Latest holds the newest post time of each tag with new posts, for the cache to keep.
+	Latest map[string]time.Time `json:"-"`
+}
+
+// ValidateTagPoll rejects a poll of no tags, of more than MaxPolledTags, of a name that
+// normalizes to nothing, or without a since time. tagNames must already be normalized.
+func ValidateTagPoll(tagNames []string, since time.Time) error {
+	if len(tagNames) == 0 {
+		return fmt.Errorf("%w: at least one tag name is required", custom_errors.ErrInvalidInput)
+	}
+	if len(tagNames) > MaxPolledTags {
+		return fmt.Errorf("%w: at most %d tag names, got %d", custom_errors.ErrInvalidInput, MaxPolledTags, len(tagNames))
+	}
+	if slices.Contains(tagNames, "") {
+		return fmt.Errorf("%w: tag names must not be empty", custom_errors.ErrInvalidInput)
+	}
+	if since.IsZero() {
+		return fmt.Errorf("%w: since is required", custom_errors.ErrInvalidInput)
+	}
+	return nil
+}
+
+// NewTagActivity answers a poll of tagNames from the newest post time of each tag. A tag
+// missing from newest, or whose newest post is not after since, has no new posts.
+func NewTagActivity(tagNames []string, newest map[string]time.Time, since time.Time) *TagActivity {
+	activity := &TagActivity{HasPosts: make(map[string]bool, len(tagNames)), Latest: make(map[string]time.Time)}
+	for _, name := range tagNames {
+		latest, ok := newest[name]
+		activity.HasPosts[name] = ok && latest.After(since)
+		if !activity.HasPosts[name] {
+			continue
+		}
+		activity.Latest[name] = latest
+		if latest.After(activity.Newest) {
+			activity.Newest = latest
+		}
+	}
+	return activity
+}
+
+// Merge adds the answer for other tags of the same poll.
+func (a *TagActivity) Merge(other *TagActivity) {
+	for name, has := range other.HasPosts {
+		a.HasPosts[name] = has
+	}
+	for name, latest := range other.Latest {
+		a.Latest[name] = latest
+	}
+	if other.Newest.After(a.Newest) {
+		a.Newest = other.Newest
+	}
+}
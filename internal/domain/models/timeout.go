@@ -0,0 +1,40 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrTimeout is returned when a database or cache operation runs past its own deadline
+// (database.query_timeout, redis.op_timeout), as opposed to failing outright.
+var ErrTimeout = errors.New("operation timed out")
+
+// ErrDeadlineExceeded and ErrCanceled are returned when the caller's context ends while an
+// operation is in flight: its deadline passed or it went away. Neither is a failure of the
+// database or the cache.
+var (
+	ErrDeadlineExceeded = errors.New("request deadline exceeded")
+	ErrCanceled         = errors.New("request canceled")
+)
+
+// CallerError marks err with ErrDeadlineExceeded or ErrCanceled when ctx has ended, and
+// returns it unchanged while ctx is live or when err is nil or already marked.
+func CallerError(ctx context.Context, err error) error {
+	if err == nil || IsCallerError(err) {
+		return err
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrDeadlineExceeded, err)
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%w: %w", ErrCanceled, err)
+	default:
+		return err
+	}
+}
+
+// IsCallerError reports whether err was marked by CallerError.
+func IsCallerError(err error) bool {
+	return errors.Is(err, ErrDeadlineExceeded) || errors.Is(err, ErrCanceled)
+}
@@ -1,9 +1,84 @@
 package model
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
 type UpdatePostDTO struct {
-	UserID     int64             `json:"user_id"`
-	Title      *string           `json:"title,omitempty"`
-	Content    *string           `json:"content,omitempty"`
+	UserID int64 `json:"user_id"`
+	// Title sets the title of the post. Unlike the other fields an empty value is applied, so
+	// a title can be cleared.
+	Title      *string         `json:"title,omitempty"`
+	Content    *string         `json:"content,omitempty"`
+	Visibility *PostVisibility `json:"visibility,omitempty"`
+	// Language sets the BCP-47 language tag of the post. Like the other fields, an empty value
+	// leaves it unchanged, so a language cannot be removed once set.
+	Language   *string           `json:"language,omitempty"`
 	Tags       []string          `json:"tags,omitempty"`
 	MediaItems []*PostMediaInput `json:"media_items,omitempty"`
+	// ExpectedVersion makes the update fail with a VersionConflictError unless the post is
+	// still at that version. Without it the last write wins.
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
+}
+
+// ChangesFields reports whether the update sets the title, the content, the visibility or the
+// language of the post. An empty string leaves the field unchanged, as in the repositories,
+// except for the title, which it clears.
+func (u *UpdatePostDTO) ChangesFields() bool {
+	return u.Title != nil || (u.Content != nil && *u.Content != "") ||
+		(u.Visibility != nil && *u.Visibility != "") || (u.Language != nil && *u.Language != "")
+}
+
+// ChangesFieldsOf is ChangesFields against the stored post: a title equal to the stored one
+// is no change, so clearing a title the post does not have changes nothing.
+func (u *UpdatePostDTO) ChangesFieldsOf(post *Post) bool {
+	if u.Title != nil && *u.Title != post.Title {
+		return true
+	}
+	return (u.Content != nil && *u.Content != "") ||
+		(u.Visibility != nil && *u.Visibility != "") || (u.Language != nil && *u.Language != "")
+}
+
+// LeavesNoText reports whether post would have neither a title nor content after the update.
+func (u *UpdatePostDTO) LeavesNoText(post *Post) bool {
+	title := post.Title
+	if u.Title != nil {
+		title = *u.Title
+	}
+	content := post.Content
+	if u.Content != nil && *u.Content != "" {
+		content = u.Content
+	}
+	return strings.TrimSpace(title) == "" && (content == nil || strings.TrimSpace(*content) == "")
+}
+
+// IsEmpty reports whether the update asks for nothing: no field it changes, no tags and no
+// media. An expected version alone is not a change.
+func (u *UpdatePostDTO) IsEmpty() bool {
+	return !u.ChangesFields() && len(u.Tags) == 0 && len(u.MediaItems) == 0
+}
+
+// ErrEmptyUpdate is returned for an update that IsEmpty, which is refused rather than left to
+// bump the post's updated_at. custom_errors has no equivalent in proto v0.1.22.
+var ErrEmptyUpdate = errors.New("update provides no title, content, visibility, language, tags or media")
+
+// ErrVersionConflict is returned when an update names a version the post is no longer at.
+// custom_errors has no equivalent in proto v0.1.22.
+var ErrVersionConflict = errors.New("post was modified since the expected version")
+
+// VersionConflictError is ErrVersionConflict with the version the post is at, so the client
+// can refetch and retry.
+type VersionConflictError struct {
+	PostID         int64
+	CurrentVersion int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s: post %d is at version %d", ErrVersionConflict.Error(), e.PostID, e.CurrentVersion)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
 }
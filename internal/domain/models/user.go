@@ -5,6 +5,7 @@ import (
 	"time"
 )
 
+// User is cached as JSON; see PostDetailed about bumping the payload version.
 type User struct {
 	ID        int64   `json:"id"`
 	Username  string  `json:"username"`
@@ -31,3 +32,14 @@ func UserFromProto(u *pb.User) *User {
 		UpdatedAt: u.UpdatedAt.AsTime(),
 	}
 }
+
+func (u *User) Clone() *User {
+	if u == nil {
+		return nil
+	}
+	clone := *u
+	clone.FullName = clonePtr(u.FullName)
+	clone.Bio = clonePtr(u.Bio)
+	clone.AvatarURL = clonePtr(u.AvatarURL)
+	return &clone
+}
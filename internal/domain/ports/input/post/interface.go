@@ -3,13 +3,31 @@ package post_service
 import (
 	"context"
 	"pinstack-post-service/internal/domain/models"
+	"time"
 )
 
 //go:generate mockery --name Service --dir . --output ../../../mocks/post --outpkg mocks --with-expecter --filename PostService.go
 type Service interface {
 	CreatePost(ctx context.Context, post *model.CreatePostDTO) (*model.PostDetailed, error)
-	GetPostByID(ctx context.Context, id int64) (*model.PostDetailed, error)
+	BulkCreatePosts(ctx context.Context, actorID int64, posts []*model.CreatePostDTO) (*model.BulkCreateResult, error)
+	GetPostByID(ctx context.Context, id int64, requesterID *int64) (*model.PostDetailed, error)
 	ListPosts(ctx context.Context, filters *model.PostFilters) ([]*model.PostDetailed, int, error)
-	UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) error
+	GetPostsByIDs(ctx context.Context, ids []int64) ([]*model.PostDetailed, error)
+	UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) (*model.PostDetailed, error)
 	DeletePost(ctx context.Context, userID int64, id int64) error
+	ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (*model.ModerationAction, error)
+	SetModerationStatus(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason) (*model.Post, error)
+	PublishPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error)
+	CancelScheduledPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error)
+	PinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error)
+	UnpinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error)
+	GetPostRevisions(ctx context.Context, userID int64, postID int64, limit, offset int) ([]*model.PostRevision, int, error)
+	GetPostTags(ctx context.Context, postID int64, requesterID *int64, includeCounts bool) ([]*model.Tag, error)
+	GetPostMedia(ctx context.Context, postID int64, requesterID *int64, limit, offset int) ([]*model.PostMedia, int, error)
+	RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error)
+	MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error)
+	SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error)
+	ExportAuthorPosts(ctx context.Context, authorID int64, send func(*model.PostDetailed) error) error
+	GetAuthorPostCount(ctx context.Context, authorID int64) (*model.AuthorPostCount, error)
+	HasPostsSince(ctx context.Context, tagNames []string, since time.Time) (*model.TagActivity, error)
 }
@@ -0,0 +1,20 @@
+package archive_repository
+
+import (
+	"context"
+	"pinstack-post-service/internal/domain/models"
+	"time"
+)
+
+//go:generate mockery --name Repository --dir . --output ../../../mocks/archive --outpkg mocks --with-expecter --filename ArchiveRepository.go
+type Repository interface {
+	// ArchiveOlderThan moves up to batchSize posts created before cutoff, oldest first, with
+	// their media, tags and revisions into the archive tables and returns how many it moved.
+	// Callers run it inside a transaction so a post never exists half in each place.
+	ArchiveOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (int, error)
+	// GetByID returns an archived post with its media and tags; Author is left nil.
+	GetByID(ctx context.Context, id int64) (*model.PostDetailed, error)
+	// GetByAuthorAfter returns up to limit archived posts of authorID, drafts included, with
+	// their media and tags, like post_repository.Repository.GetByAuthorAfter; Author is left nil.
+	GetByAuthorAfter(ctx context.Context, authorID int64, afterID int64, limit int) ([]*model.PostDetailed, error)
+}
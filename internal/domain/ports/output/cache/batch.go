@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	model "pinstack-post-service/internal/domain/models"
+	"time"
+)
+
+// CacheBatch queues post and user cache writes and sends them to the cache in a single round trip.
+//
+//go:generate mockery --name CacheBatch --dir . --output ../../../../mocks/cache --outpkg mocks --with-expecter --filename CacheBatch.go
+type CacheBatch interface {
+	SetPost(post *model.PostDetailed)
+	SetUser(user *model.User)
+	DeletePost(postID int64)
+	DeleteUser(userID int64)
+	InvalidateUserPostsMeta(userID int64)
+	// AdjustUserPostCount adds delta to the user's cached post count when there is one. A
+	// missing count is left missing, and a count that cannot be adjusted is dropped.
+	AdjustUserPostCount(userID int64, delta int64)
+	// AdvanceTagLatestPost caches at as the newest post time of the tag unless the cache
+	// already holds a later one. See PostCache.AdvanceTagLatestPosts.
+	AdvanceTagLatestPost(tagName string, at time.Time)
+	// AddRecentPost records postID among the posts userID wrote moments ago. See
+	// UserCache.GetRecentPosts.
+	AddRecentPost(userID int64, postID int64)
+	Len() int
+	Exec(ctx context.Context) error
+}
+
+//go:generate mockery --name CacheBatcher --dir . --output ../../../../mocks/cache --outpkg mocks --with-expecter --filename CacheBatcher.go
+type CacheBatcher interface {
+	NewBatch() CacheBatch
+}
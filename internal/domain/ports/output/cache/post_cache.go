@@ -3,11 +3,28 @@ package cache
 import (
 	"context"
 	model "pinstack-post-service/internal/domain/models"
+	"time"
 )
 
 //go:generate mockery --name PostCache --dir . --output ../../../../mocks/cache --outpkg mocks --with-expecter --filename PostCache.go
 type PostCache interface {
 	GetPost(ctx context.Context, postID int64) (*model.PostDetailed, error)
+	// GetPosts fetches several posts in one round trip. Posts that are not cached have no
+	// entry in the map; a miss is not an error.
+	GetPosts(ctx context.Context, postIDs []int64) (map[int64]*model.PostDetailed, error)
 	SetPost(ctx context.Context, post *model.PostDetailed) error
 	DeletePost(ctx context.Context, postID int64) error
+	// DeletePosts drops several posts in as few round trips as the cache allows. Posts that
+	// are not cached are skipped; an error means some of them may still be cached.
+	DeletePosts(ctx context.Context, postIDs []int64) error
+	// GetTagSuggestions returns the cached suggestions for a normalized prefix and limit, or
+	// ErrCacheMiss. Suggestions only expire; tag writes do not invalidate them.
+	GetTagSuggestions(ctx context.Context, prefix string, limit int) ([]*model.Tag, error)
+	SetTagSuggestions(ctx context.Context, prefix string, limit int, tags []*model.Tag) error
+	// GetTagLatestPosts returns the cached time the newest post of each of tagNames became
+	// visible (see model.Post.VisibleSince). Tags that are not cached have no entry in the map; a miss is not an error.
+	GetTagLatestPosts(ctx context.Context, tagNames []string) (map[string]time.Time, error)
+	// AdvanceTagLatestPosts caches the newest post time of each tag in latest, except where
+	// the cache already holds a later one.
+	AdvanceTagLatestPosts(ctx context.Context, latest map[string]time.Time) error
 }
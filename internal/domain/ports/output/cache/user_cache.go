@@ -8,6 +8,24 @@ import (
 //go:generate mockery --name UserCache --dir . --output ../../../../mocks/cache --outpkg mocks --with-expecter --filename UserCache.go
 type UserCache interface {
 	GetUser(ctx context.Context, userID int64) (*model.User, error)
+	// GetFreshUser returns the cached user only while the entry is younger than the configured
+	// maximum author age, and ErrCacheMiss otherwise. A fresh entry is trusted to prove that
+	// the user exists. Users that do not exist are never cached.
+	GetFreshUser(ctx context.Context, userID int64) (*model.User, error)
 	SetUser(ctx context.Context, user *model.User) error
 	DeleteUser(ctx context.Context, userID int64) error
+	// InvalidateUserPostsMeta drops post-related data derived for the user, such as a post
+	// count. It lives under its own key, so the cached user itself is left alone.
+	InvalidateUserPostsMeta(ctx context.Context, userID int64) error
+	// GetUserPostCount returns the cached number of published posts of the user, or
+	// ErrCacheMiss. The count is part of the user's posts metadata.
+	GetUserPostCount(ctx context.Context, userID int64) (int64, error)
+	SetUserPostCount(ctx context.Context, userID int64, count int64) error
+	// GetBlockedUsers returns the cached ids of the users userID has blocked, or ErrCacheMiss.
+	// An empty list is cached too, so a user who blocked nobody is not looked up every time.
+	GetBlockedUsers(ctx context.Context, userID int64) ([]int64, error)
+	SetBlockedUsers(ctx context.Context, userID int64, blockedIDs []int64) error
+	// GetRecentPosts returns the posts userID wrote within the configured window, newest
+	// first, or ErrCacheMiss when there are none. Each new post restarts the window.
+	GetRecentPosts(ctx context.Context, userID int64) ([]int64, error)
 }
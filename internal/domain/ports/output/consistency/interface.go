@@ -0,0 +1,16 @@
+package consistency_repository
+
+import (
+	"context"
+	"pinstack-post-service/internal/domain/models"
+)
+
+//go:generate mockery --name Repository --dir . --output ../../../mocks/consistency --outpkg mocks --with-expecter --filename ConsistencyRepository.go
+type Repository interface {
+	// CountOrphans counts the rows of kind left without their post or tag.
+	CountOrphans(ctx context.Context, kind model.OrphanKind) (int, error)
+	// DeleteOrphans deletes up to limit rows of kind and returns how many it deleted. Callers
+	// repeat it until it deletes fewer than limit, so no single statement holds locks on many
+	// rows.
+	DeleteOrphans(ctx context.Context, kind model.OrphanKind, limit int) (int, error)
+}
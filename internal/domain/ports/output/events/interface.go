@@ -0,0 +1,12 @@
+package events
+
+import (
+	"context"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// Publisher delivers post events to whoever listens, at most once.
+type Publisher interface {
+	Publish(ctx context.Context, event model.PostEvent) error
+}
@@ -1,9 +1,14 @@
 package ports
 
+import "context"
+
 type Logger interface {
 	Info(msg string, args ...any)
 	Debug(msg string, args ...any)
 	Warn(msg string, args ...any)
 	Error(msg string, args ...any)
 	With(args ...any) Logger
+	// WithContext returns a logger adding the request-scoped fields ctx carries, such as the
+	// request ID, to every entry.
+	WithContext(ctx context.Context) Logger
 }
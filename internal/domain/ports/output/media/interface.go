@@ -5,11 +5,31 @@ import (
 	"pinstack-post-service/internal/domain/models"
 )
 
+// Repository stores the media attached to posts. It must be safe for concurrent use: the post
+// service hydrates the posts of a list page in parallel. The conformance package tests
+// implementations against this contract.
+//
 //go:generate mockery --name Repository --dir . --output ../../../mocks --outpkg mocks --with-expecter --filename MediaRepository.go
 type Repository interface {
+	// Attach adds media to postID. A missing post fails with ErrPostNotFound; a position or URL
+	// used twice on the post fails with ErrMediaDuplicate and an invalid MediaType with
+	// ErrInvalidInput, both attaching nothing.
 	Attach(ctx context.Context, postID int64, media []*model.PostMedia) error
+	// AttachMany adds media to the posts named by their PostID in one round trip, with the
+	// errors of Attach. Bulk imports use it; it has no batch size cap, so callers bound it.
+	AttachMany(ctx context.Context, media []*model.PostMedia) error
+	// Reorder moves the media of postID to the positions in newPositions, keyed by media id.
+	// Positions are checked after the whole move, so media can swap places; a clash fails with
+	// ErrMediaDuplicate. Ids that are not media of postID are skipped.
 	Reorder(ctx context.Context, postID int64, newPositions map[int64]int) error
+	// Detach deletes the media of mediaIDs; unknown ids are ignored.
 	Detach(ctx context.Context, mediaIDs []int64) error
+	// GetByPost returns the post's media ordered by position. A post without media, or one
+	// that does not exist, yields an empty slice and a nil error, never ErrMediaNotFound.
 	GetByPost(ctx context.Context, postID int64) ([]*model.PostMedia, error)
+	// GetPageByPost returns limit of the post's media ordered by position, skipping the first
+	// offset, and how many media the post has. A page past the end is empty.
+	GetPageByPost(ctx context.Context, postID int64, limit, offset int) ([]*model.PostMedia, int, error)
+	// GetByPosts groups media by post id; posts without media have no entry in the map.
 	GetByPosts(ctx context.Context, postIDs []int64) (map[int64][]*model.PostMedia, error)
 }
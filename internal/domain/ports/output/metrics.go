@@ -5,20 +5,82 @@ import "time"
 type MetricsProvider interface {
 	IncrementGRPCRequests(method, status string)
 	RecordGRPCRequestDuration(method, status string, duration time.Duration)
+	IncrementPanics(method string)
 
 	IncrementDatabaseQueries(queryType string, success bool)
 	RecordDatabaseQueryDuration(queryType string, duration time.Duration)
+	IncrementTransactionRetries(operation string)
+	// RecordLockWaitDuration times the wait for the named lock: "post_tags". A wait cut off by
+	// the lock timeout is recorded too.
+	RecordLockWaitDuration(lock string, duration time.Duration)
+	IncrementOperationTimeouts(component, operation string)
+	IncrementCallerAborts(component, operation string, deadlineExceeded bool)
+	// RecordBudgetStageDuration times a stage of an operation run within its share of the
+	// request budget: "cache", "database" or "user_service". IncrementBudgetExhausted counts
+	// stages skipped because the budget was already spent.
+	RecordBudgetStageDuration(operation, stage string, duration time.Duration)
+	IncrementBudgetExhausted(operation, stage string)
 
-	IncrementCacheHits()
-	IncrementCacheMisses()
+	// IncrementCacheHits and IncrementCacheMisses count lookups of the named cache: "post",
+	// "user", "user_posts_meta", "user_blocks" or "tag_suggestions".
+	IncrementCacheHits(cache string)
+	IncrementCacheMisses(cache string)
+	// SetCacheKeys reports how many keys of the named cache the last sample found.
+	SetCacheKeys(cache string, count int)
 	RecordCacheOperationDuration(operation string, duration time.Duration)
 	RecordCacheHitDuration(operation string, duration time.Duration)
 	RecordCacheMissDuration(operation string, duration time.Duration)
+	// RecordCacheEntryAge records how long ago an entry of the named cache served to a caller
+	// was written; entries that do not record their write time are left out.
+	RecordCacheEntryAge(cache string, age time.Duration)
+	IncrementCoalescedRequests(operation string)
+	// IncrementAuthorVerifications counts how the author of a new post was verified: "hit"
+	// when a fresh cached user vouched for them, "miss" when the user service was asked.
+	IncrementAuthorVerifications(result string)
+	// IncrementAuthorSnapshotReads counts how the author of a read post was built: "hit" from
+	// the post's snapshot of their display data, "miss" from the user service because the post
+	// had none. AddAuthorSnapshotsRefreshed counts posts whose snapshot a refresh rewrote.
+	IncrementAuthorSnapshotReads(result string)
+	AddAuthorSnapshotsRefreshed(count int)
+	IncrementCacheCorruption(operation string)
+	SetCacheWarmedEntries(count int)
+	// AddCacheInvalidations counts posts handed to the background invalidation queue by
+	// outcome: "queued", "dropped" when the queue was full, "failed" once retries ran out.
+	AddCacheInvalidations(outcome string, count int)
+	SetCacheAvailable(available bool)
 
-	IncrementPostOperations(operation string, success bool)
+	// IncrementPostOperation and RecordPostOperationDuration count and time the business
+	// operations of the post service ("create", "get", "list", "update", "delete", "publish"
+	// and the rest) by outcome: "ok", "not_found", "forbidden", "validation", "db_error" or
+	// "error".
+	// IncrementPostReads counts reads by whether the cache served them.
+	IncrementPostOperation(operation, outcome string)
+	RecordPostOperationDuration(operation string, duration time.Duration)
+	IncrementPostReads(operation string, cached bool)
 	IncrementTagOperations(operation string, success bool)
 	IncrementMediaOperations(operation string, success bool)
+	AddExportedPosts(count int)
+	// RecordBulkCreate counts the posts a bulk import created and failed and records how many
+	// posts per second it went through.
+	RecordBulkCreate(created, failed int, duration time.Duration)
+	AddArchivedPosts(count int)
+	RecordArchiveRunDuration(duration time.Duration)
+	AddScheduledPostsPublished(count int)
+	// AddOrphansFound and AddOrphansRemoved count post_media and posts_tags rows left without
+	// their post or tag, by model.OrphanKind, that a reconciliation found and removed.
+	AddOrphansFound(kind string, count int)
+	AddOrphansRemoved(kind string, count int)
 	SetActiveConnections(count int)
+	// SetConnectionPoolStats reports the connections of the named pool: acquired are in use,
+	// idle are open and free, total is both.
+	SetConnectionPoolStats(pool string, acquired, idle, total int)
+
+	IncrementRateLimitChecks(operation, result string)
+	// IncrementUserServiceFallbacks counts requests served without an answer the user service
+	// failed to give: "blocked_users" when a feed lists posts without hiding blocked authors.
+	IncrementUserServiceFallbacks(operation string)
+	// IncrementEventPublishes counts post events by type and whether publishing succeeded.
+	IncrementEventPublishes(event string, success bool)
 
 	SetServiceHealth(healthy bool)
 }
@@ -0,0 +1,14 @@
+package moderation_repository
+
+import (
+	"context"
+	"pinstack-post-service/internal/domain/models"
+)
+
+//go:generate mockery --name Repository --dir . --output ../../../mocks/moderation --outpkg mocks --with-expecter --filename ModerationRepository.go
+type Repository interface {
+	// Record appends action to the moderation log and returns it with its ID and time set.
+	// Callers record inside the transaction of the action so the log never lists an action
+	// that was rolled back.
+	Record(ctx context.Context, action *model.ModerationAction) (*model.ModerationAction, error)
+}
@@ -2,15 +2,76 @@ package post_repository
 
 import (
 	"context"
+	"time"
+
 	"pinstack-post-service/internal/domain/models"
 )
 
+// Repository stores posts. Reads that find nothing return an empty result and a nil error; a
+// single post that does not exist fails with ErrPostNotFound. The conformance package tests
+// implementations against this contract.
+//
 //go:generate mockery --name Repository --dir . --output ../../../mocks/post --outpkg mocks --with-expecter --filename PostRepository.go
 type Repository interface {
 	Create(ctx context.Context, post *model.Post) (*model.Post, error)
+	// CreateMany creates posts in one round trip and returns their rows in the order of posts.
+	CreateMany(ctx context.Context, posts []*model.Post) ([]*model.Post, error)
 	GetByID(ctx context.Context, id int64) (*model.Post, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*model.Post, error)
+	// GetDetailedByID returns the post with its media, ordered by position, and its tags,
+	// ordered by name, in one round trip. Author is left nil. Media is empty rather than nil
+	// and Tags is nil when the post has none, as GetByPost and FindByPost return them.
+	GetDetailedByID(ctx context.Context, id int64) (*model.PostDetailed, error)
+	// GetByIDs returns the posts among ids, in no particular order. Ids without a post are skipped.
+	GetByIDs(ctx context.Context, ids []int64) ([]*model.Post, error)
+	// GetByAuthor returns every post of authorID, drafts included, newest first. Posts created
+	// at the same instant come in descending id order.
 	GetByAuthor(ctx context.Context, authorID int64) ([]*model.Post, error)
+	// CountPublishedByAuthor counts the published posts of authorID; an author without posts has 0.
+	CountPublishedByAuthor(ctx context.Context, authorID int64) (int64, error)
+	// NewestByTagsSince returns, for each of tagNames with a post ListPosts would show
+	// anonymously that became visible after since (see model.Post.VisibleSince), the time the
+	// newest one did. Tags without such a post have no entry. Names are matched as given;
+	// normalize them first.
+	NewestByTagsSince(ctx context.Context, tagNames []string, since time.Time) (map[string]time.Time, error)
+	// GetByAuthorAfter returns up to limit posts of authorID, drafts included, with id greater
+	// than afterID in ascending id order. Passing the last id of a page fetches the next one.
+	GetByAuthorAfter(ctx context.Context, authorID int64, afterID int64, limit int) ([]*model.Post, error)
+	// Update sets the title and content given in update and bumps updated_at. An update that
+	// changes neither (see UpdatePostDTO.ChangesFields) fails with ErrNoUpdateRows.
 	Update(ctx context.Context, id int64, update *model.UpdatePostDTO) (*model.Post, error)
+	// Touch only bumps updated_at, so that tags-only and media-only updates are seen by
+	// ListPosts with UpdatedAfter.
+	Touch(ctx context.Context, id int64) (*model.Post, error)
 	Delete(ctx context.Context, id int64) error
+	Publish(ctx context.Context, id int64) (*model.Post, error)
+	// PublishDue publishes up to limit scheduled posts due at now and returns them. It must run
+	// inside a transaction; rows locked by a concurrent run are skipped.
+	PublishDue(ctx context.Context, now time.Time, limit int) ([]*model.Post, error)
+	// CancelSchedule turns a scheduled post back into a draft; a post that is not scheduled
+	// fails with ErrPostNotFound.
+	CancelSchedule(ctx context.Context, id int64) (*model.Post, error)
+	// Pin pins the post at now and Unpin unpins it, neither bumping its version or updated_at.
+	// A post that does not exist fails with ErrPostNotFound. An author has at most one pinned
+	// post, so Pin must follow UnpinAuthor in the same transaction.
+	Pin(ctx context.Context, id int64, now time.Time) (*model.Post, error)
+	Unpin(ctx context.Context, id int64) (*model.Post, error)
+	// UnpinAuthor unpins every pinned post of authorID and returns their ids; an author without
+	// a pinned post has none.
+	UnpinAuthor(ctx context.Context, authorID int64) ([]int64, error)
+	// SetModerationStatus sets the moderation status and rejection reason of the post, which
+	// must be nil unless status is rejected. It bumps updated_at to now, so incremental sync
+	// sees the change, but not version: moderation does not conflict with the author's edits.
+	// A post that does not exist fails with ErrPostNotFound.
+	SetModerationStatus(ctx context.Context, id int64, status model.ModerationStatus, reason *model.RejectionReason, now time.Time) (*model.Post, error)
+	// RefreshAuthorSnapshot copies the author's username and avatar onto every post of theirs
+	// whose snapshot is missing or older than at, and returns how many it updated. Like
+	// pinning, it bumps neither version nor updated_at: the post itself did not change.
+	RefreshAuthorSnapshot(ctx context.Context, author *model.User, at time.Time) (int64, error)
+	// List returns a page of the posts matching filters and the number of matches across all
+	// pages. Tag names are matched exactly, as NormalizeTagName leaves them, and a post
+	// matches if it has any of them.
+	// A list filtered by AuthorID starts with the author's pinned post; other lists ignore
+	// pinning. Rejected posts are listed only for their author.
 	List(ctx context.Context, filters model.PostFilters) ([]*model.Post, int, error)
 }
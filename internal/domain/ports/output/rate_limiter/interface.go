@@ -0,0 +1,11 @@
+package rate_limiter
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockery --name RateLimiter --dir . --output ../../../../mocks/rate_limiter --outpkg mocks --with-expecter --filename RateLimiter.go
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+}
@@ -0,0 +1,19 @@
+package revision_repository
+
+import (
+	"context"
+	"pinstack-post-service/internal/domain/models"
+)
+
+//go:generate mockery --name Repository --dir . --output ../../../mocks/revision --outpkg mocks --with-expecter --filename RevisionRepository.go
+type Repository interface {
+	// Record stores revision as the post's next one, bumps the post's edit count to match and
+	// returns it with its ID, number and time set. Callers record inside the transaction of
+	// the edit, with the post locked, so two edits never take the same number.
+	Record(ctx context.Context, revision *model.PostRevision) (*model.PostRevision, error)
+	// Prune deletes all but the keep newest revisions of the post and returns how many it deleted.
+	Prune(ctx context.Context, postID int64, keep int) (int, error)
+	// ListByPost returns a page of the post's revisions, newest first, and how many it has
+	// in total. A post without revisions, or that does not exist, has none.
+	ListByPost(ctx context.Context, postID int64, limit, offset int) ([]*model.PostRevision, int, error)
+}
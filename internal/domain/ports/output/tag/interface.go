@@ -5,13 +5,58 @@ import (
 	"pinstack-post-service/internal/domain/models"
 )
 
+// Repository stores tags and their links to posts. It must be safe for concurrent use: the
+// post service hydrates the posts of a list page in parallel. Reads that find nothing return
+// an empty result and a nil error. The conformance package tests implementations against this
+// contract.
+//
 //go:generate mockery --name Repository --dir . --output ../../../mocks --outpkg mocks --with-expecter --filename TagRepository.go
 type Repository interface {
+	// FindByNames returns the tags among names, in no particular order. Names without a tag
+	// are skipped.
 	FindByNames(ctx context.Context, names []string) ([]*model.Tag, error)
+	// FindByPost returns the tags of postID ordered by name.
 	FindByPost(ctx context.Context, postID int64) ([]*model.Tag, error)
+	// FindByPosts returns the tags of each of postIDs, ordered by name. Posts without tags have no entry.
+	FindByPosts(ctx context.Context, postIDs []int64) (map[int64][]*model.Tag, error)
+	// Create creates the tag name, or returns the existing one if name is taken.
 	Create(ctx context.Context, name string) (*model.Tag, error)
+	// CreateMany creates the tags of names that do not exist yet in one round trip and
+	// returns the rows of all of names, created or not, in no particular order.
+	CreateMany(ctx context.Context, names []string) ([]*model.Tag, error)
+	// DeleteUnused deletes the tags no post carries.
 	DeleteUnused(ctx context.Context) error
+	// TagPost links postID to the tags of tagNames; links the post already has are kept. A
+	// missing post fails with ErrPostNotFound and a name without a tag with ErrTagNotFound.
 	TagPost(ctx context.Context, postID int64, tagNames []string) error
+	// LinkPosts adds links between posts and tags by id in one round trip; links that exist are
+	// kept. A missing post fails with ErrPostNotFound and a missing tag with ErrTagNotFound.
+	LinkPosts(ctx context.Context, links []*model.PostTag) error
+	// TagPostExisting tags postID with those of tagNames that have a tag row and returns the
+	// names that have none. A missing tag is not an error, so the surrounding transaction
+	// stays usable and the caller can create the tag and try again.
+	TagPostExisting(ctx context.Context, postID int64, tagNames []string) ([]string, error)
+	// UntagPost removes the links of postID to tagNames. Names the post does not carry are
+	// ignored; a missing post fails with ErrPostNotFound.
 	UntagPost(ctx context.Context, postID int64, tagNames []string) error
+	// ReplacePostTags makes newTags the only tags of postID, with the errors of TagPost. Run it
+	// inside a transaction: an implementation may have removed the old links when it fails.
+	// Concurrent changes to the tags of one post, by it, TagPost, TagPostExisting or
+	// UntagPost, take effect one after the other, never interleaved.
 	ReplacePostTags(ctx context.Context, postID int64, newTags []string) error
+	// FindPostIDsByTags returns the ids of the posts carrying any of tagIDs, each once, in
+	// ascending order.
+	FindPostIDsByTags(ctx context.Context, tagIDs []int64) ([]int64, error)
+	// CountPosts counts the posts of each of tagIDs; tags without posts have no entry.
+	CountPosts(ctx context.Context, tagIDs []int64) (map[int64]int64, error)
+	// SearchTags returns up to limit tags whose name starts with the normalized prefix, with
+	// PostCount filled, most used first. With authorID, tags the author has used come first.
+	SearchTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error)
+	// Rename fails with ErrTagNotFound for a missing tag and ErrTagAlreadyExists when another
+	// tag has name.
+	Rename(ctx context.Context, id int64, name string) (*model.Tag, error)
+	// Merge moves the posts of sourceIDs to destID, deletes the source tags and returns the
+	// destination. sourceIDs must be distinct and exclude destID. A missing tag fails with
+	// ErrTagNotFound; as with ReplacePostTags, run it inside a transaction.
+	Merge(ctx context.Context, sourceIDs []int64, destID int64) (*model.Tag, error)
 }
@@ -5,9 +5,14 @@ import (
 	"pinstack-post-service/internal/domain/models"
 )
 
+// Client looks up users in the user service. It must be safe for concurrent use: the post
+// service fetches the authors of a list page in parallel.
+//
 //go:generate mockery --name Client --dir . --output ../../../mocks --outpkg mocks --with-expecter --filename UserClient.go
 type Client interface {
 	GetUser(ctx context.Context, id int64) (*model.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	// GetBlockedUsers returns the ids of the users userID has blocked, in no particular order.
+	GetBlockedUsers(ctx context.Context, userID int64) ([]int64, error)
 }
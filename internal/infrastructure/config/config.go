@@ -1,117 +1,1210 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+
+	model "pinstack-post-service/internal/domain/models"
 )
 
 type Config struct {
 	Env         string
+	Log         Log
 	GRPCServer  GRPCServer
 	Database    Database
 	UserService UserService
 	Prometheus  Prometheus
 	Redis       Redis
+	Cache       Cache
+	Post        Post
+	RateLimit   RateLimit
+	Archive     Archive
+	Scheduler   Scheduler
+	// AuthorSnapshot is read_author_from_snapshot and its refresher.
+	AuthorSnapshot AuthorSnapshot
+	Events         Events
+	Orphans        Orphans
 }
 
 type GRPCServer struct {
 	Address string
 	Port    int
+	// MaxRecvMsgSize and MaxSendMsgSize bound a single message, in bytes.
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+	TLS            ServerTLS
+	Keepalive      GRPCKeepalive
+	// DrainTimeout bounds how long shutdown waits for in-flight requests before cancelling them.
+	DrainTimeout time.Duration
+	// Reflection serves the gRPC reflection service so tools like grpcurl can list and describe
+	// methods. Unless set, it is on in every env but prod.
+	Reflection bool
+	Budget     RequestBudget
+}
+
+// RequestBudget splits the time of a call between its stages, so a slow user service cannot
+// leave the database no time or the other way round. The budget is what is left of the
+// caller's deadline, or Default without one. Each ratio is the share of the budget its
+// stage may spend; zero leaves the stage bounded by the deadline only.
+type RequestBudget struct {
+	Default          time.Duration
+	CacheRatio       float64
+	DatabaseRatio    float64
+	UserServiceRatio float64
+}
+
+func (b RequestBudget) Validate() error {
+	var errs problems
+	if b.Default <= 0 {
+		errs.addf("grpc_server.budget.default must be positive, got %s", b.Default)
+	}
+	ratios := []struct {
+		name  string
+		ratio float64
+	}{
+		{"grpc_server.budget.cache_ratio", b.CacheRatio},
+		{"grpc_server.budget.database_ratio", b.DatabaseRatio},
+		{"grpc_server.budget.user_service_ratio", b.UserServiceRatio},
+	}
+	for _, r := range ratios {
+		if r.ratio < 0 || r.ratio > 1 {
+			errs.addf("%s must be between 0 and 1, got %g", r.name, r.ratio)
+		}
+	}
+	return errs.err()
 }
 
+// ServerTLS holds the server certificate. With ClientCAFile set, clients must present a
+// certificate signed by it. Insecure serves plaintext and is meant for local development.
+type ServerTLS struct {
+	Insecure     bool
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+// GRPCKeepalive configures server keepalive. Clients that ping more often than MinTime, or
+// without an active stream unless PermitWithoutStream, are disconnected. The server pings an
+// idle connection after Time and closes it when no answer arrives within Timeout.
+type GRPCKeepalive struct {
+	MinTime             time.Duration
+	PermitWithoutStream bool
+	Time                time.Duration
+	Timeout             time.Duration
+}
+
+func (g GRPCServer) Validate() error {
+	var errs problems
+	errs.checkPort("grpc_server.port", g.Port)
+	if g.MaxRecvMsgSize <= 0 {
+		errs.addf("grpc_server.max_recv_msg_size must be positive, got %d", g.MaxRecvMsgSize)
+	}
+	if g.MaxSendMsgSize <= 0 {
+		errs.addf("grpc_server.max_send_msg_size must be positive, got %d", g.MaxSendMsgSize)
+	}
+	durations := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"grpc_server.keepalive.min_time", g.Keepalive.MinTime},
+		{"grpc_server.keepalive.time", g.Keepalive.Time},
+		{"grpc_server.keepalive.timeout", g.Keepalive.Timeout},
+		{"grpc_server.drain_timeout", g.DrainTimeout},
+	}
+	for _, d := range durations {
+		if d.d <= 0 {
+			errs.addf("%s must be positive, got %s", d.name, d.d)
+		}
+	}
+	errs.add(g.TLS.Validate())
+	errs.add(g.Budget.Validate())
+	return errs.err()
+}
+
+func (t ServerTLS) Validate() error {
+	if t.Insecure {
+		return nil
+	}
+	if t.CertFile == "" || t.KeyFile == "" {
+		return fmt.Errorf("grpc_server.tls.cert_file and grpc_server.tls.key_file are required unless grpc_server.tls.insecure is set")
+	}
+	var errs problems
+	errs.add(checkReadable("grpc_server.tls.cert_file", t.CertFile))
+	errs.add(checkReadable("grpc_server.tls.key_file", t.KeyFile))
+	if t.ClientCAFile != "" {
+		errs.add(checkReadable("grpc_server.tls.client_ca_file", t.ClientCAFile))
+	}
+	return errs.err()
+}
+
+// checkReadable fails when the file at path cannot be opened for reading.
+func checkReadable(name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s %q is not readable: %w", name, path, err)
+	}
+	return f.Close()
+}
+
+// Database drivers. DriverMemory keeps all data in process and loses it on restart; it is
+// meant for running the service locally without Postgres.
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
 type Database struct {
+	// Driver is DriverPostgres or DriverMemory; empty means DriverPostgres.
+	Driver         string
 	Username       string
-	Password       string
+	Password       string `config:"secret"`
 	Host           string
 	Port           string
 	DbName         string
-	MigrationsPath string
+	MigrationsPath string // empty uses the migrations built into the binary
+	// MigrateOnStart applies pending migrations before the server connects. It is refused in
+	// env "prod", where migrations run as a separate step with -migrate.
+	MigrateOnStart bool
+	// QueryTimeout bounds a single query; batches get a multiple of it. Zero disables it.
+	QueryTimeout time.Duration
+	// ConnectTimeout bounds the ping that checks the database at startup.
+	ConnectTimeout time.Duration
+	// TagLockTimeout bounds the wait for the lock on the tags of a post, and any other lock
+	// wait later in the same transaction; a transaction that runs out is retried. Zero leaves
+	// the wait bounded by QueryTimeout only.
+	TagLockTimeout time.Duration
+	// SlowQueryThreshold is the duration above which a repository call is logged at Warn.
+	// Zero disables slow query logging.
+	SlowQueryThreshold time.Duration
+	// DebugLogSampleRate keeps 1 in DebugLogSampleRate repository Debug logs; 0 and 1 keep all.
+	DebugLogSampleRate int
+	Pool               DatabasePool
+	Batch              DatabaseBatch
+}
+
+// DatabasePool sizes the pgx connection pool. Connections are recycled after MaxConnLifetime
+// or MaxConnIdleTime, and idle ones are checked every HealthCheckPeriod.
+type DatabasePool struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+}
+
+// DatabaseBatch caps how many tags, media and media ids one repository call may write; the
+// service's own post limits are lower, so only a misbehaving caller reaches these.
+type DatabaseBatch struct {
+	MaxTags   int
+	MaxMedia  int
+	MaxDetach int
+}
+
+// DSN is the connection URL of the database, shared by the pool and the migrator.
+func (d Database) DSN() string {
+	return fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable", d.Username, d.Password, d.Host, d.Port, d.DbName)
+}
+
+func (d Database) Validate() error {
+	var errs problems
+	if d.Driver != "" && d.Driver != DriverPostgres && d.Driver != DriverMemory {
+		errs.addf("database.driver must be %q or %q, got %q", DriverPostgres, DriverMemory, d.Driver)
+	}
+	if d.QueryTimeout < 0 {
+		errs.addf("database.query_timeout must not be negative, got %s", d.QueryTimeout)
+	}
+	if d.TagLockTimeout < 0 {
+		errs.addf("database.tag_lock_timeout must not be negative, got %s", d.TagLockTimeout)
+	}
+	if d.SlowQueryThreshold < 0 {
+		errs.addf("database.slow_query_threshold must not be negative, got %s", d.SlowQueryThreshold)
+	}
+	if d.DebugLogSampleRate < 0 {
+		errs.addf("database.debug_log_sample_rate must not be negative, got %d", d.DebugLogSampleRate)
+	}
+	if d.Driver == DriverMemory {
+		return errs.err()
+	}
+	errs.checkNotEmpty("database.host", d.Host)
+	errs.checkNotEmpty("database.username", d.Username)
+	errs.checkNotEmpty("database.db_name", d.DbName)
+	if port, err := strconv.Atoi(d.Port); err != nil {
+		errs.addf("database.port must be a number, got %q", d.Port)
+	} else {
+		errs.checkPort("database.port", port)
+	}
+	if d.ConnectTimeout <= 0 {
+		errs.addf("database.connect_timeout must be positive, got %s", d.ConnectTimeout)
+	}
+	errs.add(d.Batch.Validate())
+	errs.add(d.Pool.Validate())
+	return errs.err()
+}
+
+func (b DatabaseBatch) Validate() error {
+	caps := []struct {
+		name string
+		n    int
+	}{
+		{"database.batch.max_tags", b.MaxTags},
+		{"database.batch.max_media", b.MaxMedia},
+		{"database.batch.max_detach", b.MaxDetach},
+	}
+	var errs problems
+	for _, c := range caps {
+		if c.n <= 0 {
+			errs.addf("%s must be positive, got %d", c.name, c.n)
+		}
+	}
+	return errs.err()
+}
+
+func (p DatabasePool) Validate() error {
+	var errs problems
+	if p.MaxConns <= 0 {
+		errs.addf("database.pool.max_conns must be positive, got %d", p.MaxConns)
+	}
+	if p.MinConns < 0 || p.MinConns > p.MaxConns {
+		errs.addf("database.pool.min_conns must be between 0 and database.pool.max_conns (%d), got %d", p.MaxConns, p.MinConns)
+	}
+	durations := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"database.pool.max_conn_lifetime", p.MaxConnLifetime},
+		{"database.pool.max_conn_idle_time", p.MaxConnIdleTime},
+		{"database.pool.health_check_period", p.HealthCheckPeriod},
+	}
+	for _, d := range durations {
+		if d.d <= 0 {
+			errs.addf("%s must be positive, got %s", d.name, d.d)
+		}
+	}
+	return errs.err()
 }
 
 type UserService struct {
 	Address string
 	Port    int
+	TLS     ClientTLS
+}
+
+// ClientTLS verifies the user service against the CA in CAFile, or the system roots when it
+// is empty. ServerName overrides the name checked in the certificate, which is the address
+// by default. Insecure connects in plaintext and is meant for local development.
+type ClientTLS struct {
+	Insecure   bool
+	CAFile     string
+	ServerName string
+}
+
+func (u UserService) Validate() error {
+	var errs problems
+	errs.checkNotEmpty("user_service.address", u.Address)
+	errs.checkPort("user_service.port", u.Port)
+	if !u.TLS.Insecure && u.TLS.CAFile != "" {
+		errs.add(checkReadable("user_service.tls.ca_file", u.TLS.CAFile))
+	}
+	return errs.err()
 }
 
 type Prometheus struct {
 	Address string
 	Port    int
+	// PoolStatsInterval is how often the connection pool gauges are refreshed.
+	PoolStatsInterval time.Duration
+}
+
+func (p Prometheus) Validate() error {
+	var errs problems
+	errs.checkPort("prometheus.port", p.Port)
+	if p.PoolStatsInterval <= 0 {
+		errs.addf("prometheus.pool_stats_interval must be positive, got %s", p.PoolStatsInterval)
+	}
+	return errs.err()
 }
 
 type Redis struct {
 	Address  string
 	Port     int
-	Password string
+	Password string `config:"secret"`
 	DB       int
 	PoolSize int
+	// OpTimeout bounds a single command; pipelines get a multiple of it. Zero disables it.
+	OpTimeout time.Duration
+	// DialTimeout, ReadTimeout and WriteTimeout bound a connection attempt and a socket read or
+	// write; ConnectTimeout bounds the PING that checks Redis at startup.
+	DialTimeout    time.Duration
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	ConnectTimeout time.Duration
+	// ReconnectInterval is how often the service retries Redis when it did not answer at
+	// startup. Until it does, caching and rate limiting are disabled.
+	ReconnectInterval time.Duration
+}
+
+func (r Redis) Validate() error {
+	var errs problems
+	errs.checkNotEmpty("redis.address", r.Address)
+	errs.checkPort("redis.port", r.Port)
+	if r.DB < 0 {
+		errs.addf("redis.db must not be negative, got %d", r.DB)
+	}
+	if r.OpTimeout < 0 {
+		errs.addf("redis.op_timeout must not be negative, got %s", r.OpTimeout)
+	}
+	if r.PoolSize <= 0 {
+		errs.addf("redis.pool_size must be positive, got %d", r.PoolSize)
+	}
+	timeouts := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"redis.dial_timeout", r.DialTimeout},
+		{"redis.read_timeout", r.ReadTimeout},
+		{"redis.write_timeout", r.WriteTimeout},
+		{"redis.connect_timeout", r.ConnectTimeout},
+		{"redis.reconnect_interval", r.ReconnectInterval},
+	}
+	for _, t := range timeouts {
+		if t.d <= 0 {
+			errs.addf("%s must be positive, got %s", t.name, t.d)
+		}
+	}
+	return errs.err()
+}
+
+type Cache struct {
+	KeyPrefix string
+	PostTTL   time.Duration
+	UserTTL   time.Duration
+	ListTTL   time.Duration
+	// PostCountTTL bounds how long a cached author post count may lag behind writes that
+	// bypass the cache decorator, such as archiving.
+	PostCountTTL time.Duration
+	// TagSuggestionTTL is how long tag suggestions for a prefix are served from the cache;
+	// new tags and usage only show up once it expires.
+	TagSuggestionTTL time.Duration
+	// TagLatestTTL bounds how long the cached newest post time of a tag may miss posts that
+	// bypass the cache decorator, such as scheduled posts published by the scheduler.
+	TagLatestTTL time.Duration
+	// BlockedUsersTTL is how long the authors a user blocked are served from the cache; a new
+	// block takes up to this long to hide the blocked author's posts.
+	BlockedUsersTTL time.Duration
+	// AuthorMaxAge is how old a cached user may be and still vouch for the author of a new
+	// post without asking the user service. It is measured from when the user was cached.
+	AuthorMaxAge time.Duration
+	// RecentPostsTTL is how long the posts an author just wrote are added to their own
+	// timeline when the list misses them. Each new post restarts it.
+	RecentPostsTTL time.Duration
+	Warmup         CacheWarmup
+	KeyCount       CacheKeyCount
+	Invalidation   CacheInvalidation
+}
+
+type CacheWarmup struct {
+	Enabled bool
+	Posts   int
+	Timeout time.Duration
+}
+
+// CacheKeyCount bounds the periodic sample of how many keys each cache holds.
+type CacheKeyCount struct {
+	Interval time.Duration
+	// ScanCount is the COUNT hint of each SCAN, roughly how many keys one call looks at.
+	ScanCount int
+	// Limit is how many keys one sample visits at most before reporting what it found.
+	Limit int
+}
+
+// CacheInvalidation sizes the queue that drops the cached posts of tag renames and merges in
+// the background.
+type CacheInvalidation struct {
+	// QueueSize is how many posts may wait; posts queued past it are dropped and stay cached
+	// until their TTL.
+	QueueSize int
+	// Retries is how many times a failed invalidation is retried, RetryBackoff the pause
+	// before the first retry, doubled for each next one.
+	Retries      int
+	RetryBackoff time.Duration
+	// FlushTimeout bounds how long shutdown waits for the queued invalidations.
+	FlushTimeout time.Duration
+}
+
+// Validate rejects TTLs that would make Redis store keys without expiry or drop them immediately.
+func (c Cache) Validate() error {
+	ttls := []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{"cache.post_ttl", c.PostTTL},
+		{"cache.user_ttl", c.UserTTL},
+		{"cache.list_ttl", c.ListTTL},
+		{"cache.post_count_ttl", c.PostCountTTL},
+		{"cache.tag_suggestion_ttl", c.TagSuggestionTTL},
+		{"cache.tag_latest_ttl", c.TagLatestTTL},
+		{"cache.blocked_users_ttl", c.BlockedUsersTTL},
+		{"cache.author_max_age", c.AuthorMaxAge},
+		{"cache.recent_posts_ttl", c.RecentPostsTTL},
+	}
+	var errs problems
+	for _, t := range ttls {
+		if t.ttl <= 0 {
+			errs.addf("%s must be positive, got %s", t.name, t.ttl)
+		}
+	}
+	if c.Warmup.Enabled {
+		if c.Warmup.Posts <= 0 {
+			errs.addf("cache.warmup.posts must be positive, got %d", c.Warmup.Posts)
+		}
+		if c.Warmup.Timeout <= 0 {
+			errs.addf("cache.warmup.timeout must be positive, got %s", c.Warmup.Timeout)
+		}
+	}
+	if c.KeyCount.Interval <= 0 {
+		errs.addf("cache.key_count.interval must be positive, got %s", c.KeyCount.Interval)
+	}
+	if c.KeyCount.ScanCount <= 0 {
+		errs.addf("cache.key_count.scan_count must be positive, got %d", c.KeyCount.ScanCount)
+	}
+	if c.KeyCount.Limit <= 0 {
+		errs.addf("cache.key_count.limit must be positive, got %d", c.KeyCount.Limit)
+	}
+	if c.Invalidation.QueueSize <= 0 {
+		errs.addf("cache.invalidation.queue_size must be positive, got %d", c.Invalidation.QueueSize)
+	}
+	if c.Invalidation.Retries < 0 {
+		errs.addf("cache.invalidation.retries must not be negative, got %d", c.Invalidation.Retries)
+	}
+	if c.Invalidation.RetryBackoff <= 0 {
+		errs.addf("cache.invalidation.retry_backoff must be positive, got %s", c.Invalidation.RetryBackoff)
+	}
+	if c.Invalidation.FlushTimeout <= 0 {
+		errs.addf("cache.invalidation.flush_timeout must be positive, got %s", c.Invalidation.FlushTimeout)
+	}
+	return errs.err()
+}
+
+type Post struct {
+	MaxContentLength int
+	MaxTags          int
+	MaxFeedAuthors   int
+	// HydrationConcurrency bounds how many posts of a ListPosts page are hydrated at once.
+	HydrationConcurrency int
+	// DefaultListLimit is the page size of a list request without a limit; MaxListLimit is
+	// the largest page a list request may ask for.
+	DefaultListLimit int
+	MaxListLimit     int
+	// ExcerptLength is how many runes of content a post summary in a list keeps.
+	ExcerptLength int
+	// MediaHosts lists the hosts media URLs may point at; "*.cdn.example.com" matches its
+	// subdomains. MediaAllowAllHosts accepts any host and is meant for local development.
+	MediaHosts         []string
+	MediaAllowAllHosts bool
+	// Languages lists the BCP-47 language tags a post may be written in.
+	Languages []string
+	// Sources lists the clients a post may record as its source; any other is recorded as
+	// unknown.
+	Sources []string
+	// MaxRevisions is how many revisions of a post are kept; older ones are pruned on edit.
+	MaxRevisions int
+	// MaxEmbeddedMedia is how many media a post response carries; the rest are paged through
+	// GetPostMedia.
+	MaxEmbeddedMedia int
+}
+
+func (p Post) Validate() error {
+	var errs problems
+	if p.MaxContentLength <= 0 {
+		errs.addf("post.max_content_length must be positive, got %d", p.MaxContentLength)
+	}
+	if p.MaxTags <= 0 {
+		errs.addf("post.max_tags must be positive, got %d", p.MaxTags)
+	}
+	if p.MaxFeedAuthors <= 0 {
+		errs.addf("post.max_feed_authors must be positive, got %d", p.MaxFeedAuthors)
+	}
+	if p.HydrationConcurrency <= 0 {
+		errs.addf("post.hydration_concurrency must be positive, got %d", p.HydrationConcurrency)
+	}
+	if p.MaxListLimit <= 0 {
+		errs.addf("post.max_list_limit must be positive, got %d", p.MaxListLimit)
+	} else if p.DefaultListLimit <= 0 || p.DefaultListLimit > p.MaxListLimit {
+		errs.addf("post.default_list_limit must be between 1 and post.max_list_limit (%d), got %d", p.MaxListLimit, p.DefaultListLimit)
+	}
+	if p.ExcerptLength <= 0 {
+		errs.addf("post.excerpt_length must be positive, got %d", p.ExcerptLength)
+	}
+	if p.MaxRevisions <= 0 {
+		errs.addf("post.max_revisions must be positive, got %d", p.MaxRevisions)
+	}
+	if p.MaxEmbeddedMedia <= 0 || p.MaxEmbeddedMedia > model.MaxMediaPageSize {
+		errs.addf("post.max_embedded_media must be between 1 and %d, got %d", model.MaxMediaPageSize, p.MaxEmbeddedMedia)
+	}
+	if !p.MediaAllowAllHosts && len(p.MediaHosts) == 0 {
+		errs.addf("post.media_hosts must not be empty unless post.media_allow_all_hosts is set")
+	}
+	for _, host := range p.MediaHosts {
+		if _, err := model.NormalizeMediaHost(host); err != nil {
+			errs.addf("post.media_hosts: %w", err)
+		}
+	}
+	for _, lang := range p.Languages {
+		if _, err := model.ParseLanguage(lang); err != nil {
+			errs.addf("post.languages: %q %w", lang, err)
+		}
+	}
+	for _, source := range p.Sources {
+		if _, err := model.ParsePostSource(source); err != nil {
+			errs.addf("post.sources: %q %w", source, err)
+		}
+	}
+	return errs.err()
+}
+
+// MediaHostAllowlist returns the media hosts in the form the post limits compare against.
+// Validate must have passed.
+func (p Post) MediaHostAllowlist() model.MediaHosts {
+	hosts := make([]string, 0, len(p.MediaHosts))
+	for _, host := range p.MediaHosts {
+		normalized, _ := model.NormalizeMediaHost(host)
+		hosts = append(hosts, normalized)
+	}
+	return model.MediaHosts{AllowAll: p.MediaAllowAllHosts, Hosts: hosts}
+}
+
+// LanguageAllowlist returns the languages in the canonical form posts are stored with.
+// Validate must have passed.
+func (p Post) LanguageAllowlist() model.Languages {
+	langs := make(model.Languages, 0, len(p.Languages))
+	for _, lang := range p.Languages {
+		canonical, _ := model.ParseLanguage(lang)
+		langs = append(langs, canonical)
+	}
+	return langs
+}
+
+// SourceAllowlist returns the sources in the form posts are stored with. Validate must have
+// passed.
+func (p Post) SourceAllowlist() model.PostSources {
+	sources := make(model.PostSources, 0, len(p.Sources))
+	for _, raw := range p.Sources {
+		source, _ := model.ParsePostSource(raw)
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+// Archive moves posts older than Retention, with their media and tags, out of the hot tables.
+type Archive struct {
+	Enabled   bool
+	Retention time.Duration
+	BatchSize int
+	Interval  time.Duration
+	// ReadFallback makes GetPost look in the archive when a post is not in the hot table.
+	ReadFallback bool
 }
 
+func (a Archive) Validate() error {
+	if !a.Enabled {
+		return nil
+	}
+	var errs problems
+	if a.Retention <= 0 {
+		errs.addf("archive.retention must be positive, got %s", a.Retention)
+	}
+	if a.BatchSize <= 0 {
+		errs.addf("archive.batch_size must be positive, got %d", a.BatchSize)
+	}
+	if a.Interval <= 0 {
+		errs.addf("archive.interval must be positive, got %s", a.Interval)
+	}
+	return errs.err()
+}
+
+// Scheduler publishes scheduled posts once their time has come, up to BatchSize per transaction.
+type Scheduler struct {
+	Enabled   bool
+	BatchSize int
+	Interval  time.Duration
+}
+
+func (s Scheduler) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	var errs problems
+	if s.BatchSize <= 0 {
+		errs.addf("scheduler.batch_size must be positive, got %d", s.BatchSize)
+	}
+	if s.Interval <= 0 {
+		errs.addf("scheduler.interval must be positive, got %s", s.Interval)
+	}
+	return errs.err()
+}
+
+// AuthorSnapshot, with ReadAuthorFromSnapshot, builds the author of a read post from the copy of
+// their username and avatar stored on the post instead of asking the user service. A copy
+// older than StaleAfter is still served; its author is refreshed in the background, up to
+// RefreshBatchSize authors every RefreshInterval.
+type AuthorSnapshot struct {
+	ReadAuthorFromSnapshot bool
+	StaleAfter             time.Duration
+	RefreshInterval        time.Duration
+	RefreshBatchSize       int
+}
+
+func (a AuthorSnapshot) Validate() error {
+	if !a.ReadAuthorFromSnapshot {
+		return nil
+	}
+	var errs problems
+	if a.StaleAfter <= 0 {
+		errs.addf("author_snapshot.stale_after must be positive, got %s", a.StaleAfter)
+	}
+	if a.RefreshInterval <= 0 {
+		errs.addf("author_snapshot.refresh_interval must be positive, got %s", a.RefreshInterval)
+	}
+	if a.RefreshBatchSize <= 0 {
+		errs.addf("author_snapshot.refresh_batch_size must be positive, got %d", a.RefreshBatchSize)
+	}
+	return errs.err()
+}
+
+// Orphans paces the removal of post_media and posts_tags rows left without their post or
+// tag: BatchSize rows per statement and Pause between statements.
+type Orphans struct {
+	BatchSize int
+	Pause     time.Duration
+}
+
+func (o Orphans) Validate() error {
+	var errs problems
+	if o.BatchSize <= 0 {
+		errs.addf("orphans.batch_size must be positive, got %d", o.BatchSize)
+	}
+	if o.Pause < 0 {
+		errs.addf("orphans.pause must not be negative, got %s", o.Pause)
+	}
+	return errs.err()
+}
+
+// Log sets the logger level: debug, info, warn or error. Unless set, it is debug in env dev
+// and info elsewhere. A SIGHUP switches the level to debug for DebugDuration, to look into a
+// running instance without a restart.
+type Log struct {
+	Level         string
+	DebugDuration time.Duration
+}
+
+func (l Log) Validate() error {
+	var errs problems
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		errs.addf("log.level must be debug, info, warn or error, got %q", l.Level)
+	}
+	if l.DebugDuration <= 0 {
+		errs.addf("log.debug_duration must be positive, got %s", l.DebugDuration)
+	}
+	return errs.err()
+}
+
+// SlogLevel is Level as a slog.Level; it is info when Level is invalid.
+func (l Log) SlogLevel() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// Events publishes a JSON event on the Redis channel Channel after each post is created,
+// updated or deleted. Events are dropped while Redis is unavailable.
+type Events struct {
+	Enabled bool
+	Channel string
+}
+
+func (e Events) Validate() error {
+	if e.Enabled && e.Channel == "" {
+		return errors.New("events.channel must not be empty")
+	}
+	return nil
+}
+
+type RateLimit struct {
+	Enabled    bool
+	CreatePost RateLimitRule
+	// BulkCreatePosts limits bulk import calls per actor, however many posts each carries.
+	BulkCreatePosts RateLimitRule
+	UpdatePost      RateLimitRule
+	DeletePost      RateLimitRule
+}
+
+type RateLimitRule struct {
+	Limit  int
+	Window time.Duration
+}
+
+func (r RateLimit) Validate() error {
+	if !r.Enabled {
+		return nil
+	}
+	rules := []struct {
+		name string
+		rule RateLimitRule
+	}{
+		{"rate_limit.create_post", r.CreatePost},
+		{"rate_limit.bulk_create_posts", r.BulkCreatePosts},
+		{"rate_limit.update_post", r.UpdatePost},
+		{"rate_limit.delete_post", r.DeletePost},
+	}
+	var errs problems
+	for _, rule := range rules {
+		if rule.rule.Limit <= 0 {
+			errs.addf("%s.limit must be positive, got %d", rule.name, rule.rule.Limit)
+		}
+		if rule.rule.Window <= 0 {
+			errs.addf("%s.window must be positive, got %s", rule.name, rule.rule.Window)
+		}
+	}
+	return errs.err()
+}
+
+// Validate checks every section and reports all invalid settings in one error, one per line,
+// so they can be fixed in a single pass.
+func (c *Config) Validate() error {
+	var errs problems
+	for _, section := range []interface{ Validate() error }{
+		c.Log, c.GRPCServer, c.Database, c.UserService, c.Prometheus, c.Redis, c.Cache, c.Post,
+		c.RateLimit, c.Archive, c.Scheduler, c.AuthorSnapshot, c.Events, c.Orphans,
+	} {
+		errs.add(section.Validate())
+	}
+	if c.Prometheus.Port == c.GRPCServer.Port {
+		errs.addf("prometheus.port must differ from grpc_server.port (%d)", c.GRPCServer.Port)
+	}
+	if c.Database.MigrateOnStart && c.Env == envProd {
+		errs.addf("database.migrate_on_start is refused in env %q; run the server with -migrate before rolling out", envProd)
+	}
+	// The warmer lists its posts in a single page.
+	if c.Cache.Warmup.Enabled && c.Cache.Warmup.Posts > c.Post.MaxListLimit {
+		errs.addf("cache.warmup.posts (%d) must not exceed post.max_list_limit (%d)", c.Cache.Warmup.Posts, c.Post.MaxListLimit)
+	}
+	return errs.err()
+}
+
+// problems collects invalid settings; the section validators keep going after the first one.
+type problems []error
+
+func (errs *problems) addf(format string, args ...any) {
+	*errs = append(*errs, fmt.Errorf(format, args...))
+}
+
+// add keeps err, which may be a joined error of several problems; nil is ignored.
+func (errs *problems) add(err error) {
+	if err != nil {
+		*errs = append(*errs, err)
+	}
+}
+
+func (errs *problems) checkPort(name string, port int) {
+	if port < 1 || port > 65535 {
+		errs.addf("%s must be between 1 and 65535, got %d", name, port)
+	}
+}
+
+func (errs *problems) checkNotEmpty(name, value string) {
+	if strings.TrimSpace(value) == "" {
+		errs.addf("%s must not be empty", name)
+	}
+}
+
+func (errs problems) err() error {
+	return errors.Join(errs...)
+}
+
+const (
+	envDev  = "dev"
+	envProd = "prod"
+)
+
+// EnvPrefix prefixes the environment variables that override the config file: the key
+// database.pool.max_conns is read from POST_SERVICE_DATABASE_POOL_MAX_CONNS. Environment
+// variables win over the file, which wins over the defaults.
+const EnvPrefix = "POST_SERVICE"
+
+// MustLoad loads and validates the config, and exits listing every invalid setting if it
+// cannot.
 func MustLoad() *Config {
+	config, err := Load()
+	if err != nil {
+		log.Printf("Invalid config:\n%s", err)
+		os.Exit(1)
+	}
+	return config
+}
+
+// Load reads config/config.yml, applies the environment overrides and the defaults of unset
+// keys, and validates the result.
+func Load() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("./config")
 
-	viper.SetDefault("env", "dev")
+	setDefaults()
+	bindEnv()
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	config := fromViper()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func bindEnv() {
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+}
+
+func setDefaults() {
+	viper.SetDefault("env", envDev)
+
+	viper.SetDefault("log.debug_duration", 15*time.Minute)
 
 	viper.SetDefault("grpc_server.address", "0.0.0.0")
 	viper.SetDefault("grpc_server.port", 50053)
+	viper.SetDefault("grpc_server.max_recv_msg_size", 16<<20)
+	viper.SetDefault("grpc_server.max_send_msg_size", 16<<20)
+	viper.SetDefault("grpc_server.tls.insecure", false)
+	viper.SetDefault("grpc_server.keepalive.min_time", 30*time.Second)
+	viper.SetDefault("grpc_server.keepalive.permit_without_stream", false)
+	viper.SetDefault("grpc_server.keepalive.time", 2*time.Hour)
+	viper.SetDefault("grpc_server.keepalive.timeout", 20*time.Second)
+	viper.SetDefault("grpc_server.drain_timeout", 20*time.Second)
+	budgetRatios := model.DefaultBudgetRatios()
+	viper.SetDefault("grpc_server.budget.default", 10*time.Second)
+	viper.SetDefault("grpc_server.budget.cache_ratio", budgetRatios.Cache)
+	viper.SetDefault("grpc_server.budget.database_ratio", budgetRatios.Database)
+	viper.SetDefault("grpc_server.budget.user_service_ratio", budgetRatios.UserService)
 
+	viper.SetDefault("database.driver", DriverPostgres)
 	viper.SetDefault("database.username", "postgres")
 	viper.SetDefault("database.password", "admin")
 	viper.SetDefault("database.host", "post-db")
 	viper.SetDefault("database.port", "5434")
 	viper.SetDefault("database.db_name", "postservice")
-	viper.SetDefault("database.migrations_path", "migrations")
+	viper.SetDefault("database.migrations_path", "")
+	viper.SetDefault("database.migrate_on_start", false)
+	viper.SetDefault("database.query_timeout", 5*time.Second)
+	viper.SetDefault("database.connect_timeout", 5*time.Second)
+	viper.SetDefault("database.tag_lock_timeout", 2*time.Second)
+	viper.SetDefault("database.slow_query_threshold", 200*time.Millisecond)
+	viper.SetDefault("database.debug_log_sample_rate", 1)
+	viper.SetDefault("database.batch.max_tags", 100)
+	viper.SetDefault("database.batch.max_media", 50)
+	viper.SetDefault("database.batch.max_detach", 500)
+	viper.SetDefault("database.pool.max_conns", 20)
+	viper.SetDefault("database.pool.min_conns", 2)
+	viper.SetDefault("database.pool.max_conn_lifetime", time.Hour)
+	viper.SetDefault("database.pool.max_conn_idle_time", 30*time.Minute)
+	viper.SetDefault("database.pool.health_check_period", time.Minute)
 
 	viper.SetDefault("user_service.address", "user-service")
 	viper.SetDefault("user_service.port", 50051)
+	viper.SetDefault("user_service.tls.insecure", false)
 
 	viper.SetDefault("prometheus.address", "0.0.0.0")
 	viper.SetDefault("prometheus.port", 9103)
+	viper.SetDefault("prometheus.pool_stats_interval", 15*time.Second)
 
 	viper.SetDefault("redis.address", "redis")
 	viper.SetDefault("redis.port", 6379)
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("redis.pool_size", 10)
+	viper.SetDefault("redis.op_timeout", 500*time.Millisecond)
+	viper.SetDefault("redis.dial_timeout", 5*time.Second)
+	viper.SetDefault("redis.read_timeout", 3*time.Second)
+	viper.SetDefault("redis.write_timeout", 3*time.Second)
+	viper.SetDefault("redis.connect_timeout", 5*time.Second)
+	viper.SetDefault("redis.reconnect_interval", 10*time.Second)
 
-	if err := viper.ReadInConfig(); err != nil {
-		log.Printf("Error reading config file: %s", err)
-		os.Exit(1)
+	viper.SetDefault("cache.key_prefix", "")
+	viper.SetDefault("cache.post_ttl", 30*time.Minute)
+	viper.SetDefault("cache.user_ttl", 15*time.Minute)
+	viper.SetDefault("cache.list_ttl", 5*time.Minute)
+	viper.SetDefault("cache.post_count_ttl", time.Minute)
+	viper.SetDefault("cache.tag_suggestion_ttl", time.Minute)
+	viper.SetDefault("cache.tag_latest_ttl", 5*time.Minute)
+	viper.SetDefault("cache.blocked_users_ttl", 30*time.Second)
+	viper.SetDefault("cache.author_max_age", time.Minute)
+	viper.SetDefault("cache.recent_posts_ttl", 30*time.Second)
+	viper.SetDefault("cache.warmup.enabled", false)
+	viper.SetDefault("cache.warmup.posts", 100)
+	viper.SetDefault("cache.warmup.timeout", 10*time.Second)
+	viper.SetDefault("cache.key_count.interval", time.Minute)
+	viper.SetDefault("cache.key_count.scan_count", 1000)
+	viper.SetDefault("cache.key_count.limit", 100000)
+	viper.SetDefault("cache.invalidation.queue_size", 10000)
+	viper.SetDefault("cache.invalidation.retries", 3)
+	viper.SetDefault("cache.invalidation.retry_backoff", 100*time.Millisecond)
+	viper.SetDefault("cache.invalidation.flush_timeout", 5*time.Second)
+
+	viper.SetDefault("post.max_content_length", 50000)
+	viper.SetDefault("post.max_tags", 10)
+	viper.SetDefault("post.max_feed_authors", 500)
+	viper.SetDefault("post.hydration_concurrency", 8)
+	viper.SetDefault("post.default_list_limit", 20)
+	viper.SetDefault("post.max_list_limit", 100)
+	viper.SetDefault("post.excerpt_length", 280)
+	viper.SetDefault("post.media_hosts", []string{})
+	viper.SetDefault("post.media_allow_all_hosts", false)
+	viper.SetDefault("post.languages", model.DefaultLanguages)
+	viper.SetDefault("post.sources", model.DefaultPostSources.Strings())
+	viper.SetDefault("post.max_revisions", model.DefaultMaxRevisionsPerPost)
+	viper.SetDefault("post.max_embedded_media", model.DefaultMaxEmbeddedMedia)
+
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.create_post.limit", 10)
+	viper.SetDefault("rate_limit.create_post.window", time.Minute)
+	viper.SetDefault("rate_limit.bulk_create_posts.limit", 20)
+	viper.SetDefault("rate_limit.bulk_create_posts.window", time.Minute)
+	viper.SetDefault("rate_limit.update_post.limit", 30)
+	viper.SetDefault("rate_limit.update_post.window", time.Minute)
+	viper.SetDefault("rate_limit.delete_post.limit", 30)
+	viper.SetDefault("rate_limit.delete_post.window", time.Minute)
+
+	viper.SetDefault("archive.enabled", false)
+	viper.SetDefault("archive.retention", 2*365*24*time.Hour)
+	viper.SetDefault("archive.batch_size", 500)
+	viper.SetDefault("archive.interval", time.Hour)
+	viper.SetDefault("archive.read_fallback", false)
+
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.batch_size", 100)
+	viper.SetDefault("scheduler.interval", 30*time.Second)
+
+	viper.SetDefault("author_snapshot.read_author_from_snapshot", false)
+	viper.SetDefault("author_snapshot.stale_after", time.Hour)
+	viper.SetDefault("author_snapshot.refresh_interval", time.Minute)
+	viper.SetDefault("author_snapshot.refresh_batch_size", 200)
+
+	viper.SetDefault("events.enabled", false)
+	viper.SetDefault("events.channel", "pinstack:post-events")
+
+	viper.SetDefault("orphans.batch_size", 1000)
+	viper.SetDefault("orphans.pause", 100*time.Millisecond)
+}
+
+// reflectionEnabled is grpc_server.reflection when it is set, and otherwise whether the env is
+// not prod.
+func reflectionEnabled() bool {
+	if viper.IsSet("grpc_server.reflection") {
+		return viper.GetBool("grpc_server.reflection")
+	}
+	return viper.GetString("env") != envProd
+}
+
+// logLevel is log.level when it is set, and otherwise debug in env dev and info elsewhere.
+func logLevel() string {
+	if viper.IsSet("log.level") {
+		return viper.GetString("log.level")
+	}
+	if viper.GetString("env") == envDev {
+		return "debug"
 	}
+	return "info"
+}
 
-	config := &Config{
+// fromViper builds the config from the loaded file and the defaults.
+func fromViper() *Config {
+	return &Config{
 		Env: viper.GetString("env"),
+		Log: Log{
+			Level:         logLevel(),
+			DebugDuration: viper.GetDuration("log.debug_duration"),
+		},
 		GRPCServer: GRPCServer{
-			Address: viper.GetString("grpc_server.address"),
-			Port:    viper.GetInt("grpc_server.port"),
+			Address:        viper.GetString("grpc_server.address"),
+			Port:           viper.GetInt("grpc_server.port"),
+			MaxRecvMsgSize: viper.GetInt("grpc_server.max_recv_msg_size"),
+			MaxSendMsgSize: viper.GetInt("grpc_server.max_send_msg_size"),
+			TLS: ServerTLS{
+				Insecure:     viper.GetBool("grpc_server.tls.insecure"),
+				CertFile:     viper.GetString("grpc_server.tls.cert_file"),
+				KeyFile:      viper.GetString("grpc_server.tls.key_file"),
+				ClientCAFile: viper.GetString("grpc_server.tls.client_ca_file"),
+			},
+			Keepalive: GRPCKeepalive{
+				MinTime:             viper.GetDuration("grpc_server.keepalive.min_time"),
+				PermitWithoutStream: viper.GetBool("grpc_server.keepalive.permit_without_stream"),
+				Time:                viper.GetDuration("grpc_server.keepalive.time"),
+				Timeout:             viper.GetDuration("grpc_server.keepalive.timeout"),
+			},
+			DrainTimeout: viper.GetDuration("grpc_server.drain_timeout"),
+			Reflection:   reflectionEnabled(),
+			Budget: RequestBudget{
+				Default:          viper.GetDuration("grpc_server.budget.default"),
+				CacheRatio:       viper.GetFloat64("grpc_server.budget.cache_ratio"),
+				DatabaseRatio:    viper.GetFloat64("grpc_server.budget.database_ratio"),
+				UserServiceRatio: viper.GetFloat64("grpc_server.budget.user_service_ratio"),
+			},
 		},
 		Database: Database{
-			Username:       viper.GetString("database.username"),
-			Password:       viper.GetString("database.password"),
-			Host:           viper.GetString("database.host"),
-			Port:           viper.GetString("database.port"),
-			DbName:         viper.GetString("database.db_name"),
-			MigrationsPath: viper.GetString("database.migrations_path"),
+			Driver:             viper.GetString("database.driver"),
+			Username:           viper.GetString("database.username"),
+			Password:           viper.GetString("database.password"),
+			Host:               viper.GetString("database.host"),
+			Port:               viper.GetString("database.port"),
+			DbName:             viper.GetString("database.db_name"),
+			MigrationsPath:     viper.GetString("database.migrations_path"),
+			MigrateOnStart:     viper.GetBool("database.migrate_on_start"),
+			QueryTimeout:       viper.GetDuration("database.query_timeout"),
+			ConnectTimeout:     viper.GetDuration("database.connect_timeout"),
+			TagLockTimeout:     viper.GetDuration("database.tag_lock_timeout"),
+			SlowQueryThreshold: viper.GetDuration("database.slow_query_threshold"),
+			DebugLogSampleRate: viper.GetInt("database.debug_log_sample_rate"),
+			Pool: DatabasePool{
+				MaxConns:          viper.GetInt32("database.pool.max_conns"),
+				MinConns:          viper.GetInt32("database.pool.min_conns"),
+				MaxConnLifetime:   viper.GetDuration("database.pool.max_conn_lifetime"),
+				MaxConnIdleTime:   viper.GetDuration("database.pool.max_conn_idle_time"),
+				HealthCheckPeriod: viper.GetDuration("database.pool.health_check_period"),
+			},
+			Batch: DatabaseBatch{
+				MaxTags:   viper.GetInt("database.batch.max_tags"),
+				MaxMedia:  viper.GetInt("database.batch.max_media"),
+				MaxDetach: viper.GetInt("database.batch.max_detach"),
+			},
 		},
 		UserService: UserService{
 			Address: viper.GetString("user_service.address"),
 			Port:    viper.GetInt("user_service.port"),
+			TLS: ClientTLS{
+				Insecure:   viper.GetBool("user_service.tls.insecure"),
+				CAFile:     viper.GetString("user_service.tls.ca_file"),
+				ServerName: viper.GetString("user_service.tls.server_name"),
+			},
 		},
 		Prometheus: Prometheus{
-			Address: viper.GetString("prometheus.address"),
-			Port:    viper.GetInt("prometheus.port"),
+			Address:           viper.GetString("prometheus.address"),
+			Port:              viper.GetInt("prometheus.port"),
+			PoolStatsInterval: viper.GetDuration("prometheus.pool_stats_interval"),
 		},
 		Redis: Redis{
-			Address:  viper.GetString("redis.address"),
-			Port:     viper.GetInt("redis.port"),
-			Password: viper.GetString("redis.password"),
-			DB:       viper.GetInt("redis.db"),
-			PoolSize: viper.GetInt("redis.pool_size"),
+			Address:           viper.GetString("redis.address"),
+			Port:              viper.GetInt("redis.port"),
+			Password:          viper.GetString("redis.password"),
+			DB:                viper.GetInt("redis.db"),
+			PoolSize:          viper.GetInt("redis.pool_size"),
+			OpTimeout:         viper.GetDuration("redis.op_timeout"),
+			DialTimeout:       viper.GetDuration("redis.dial_timeout"),
+			ReadTimeout:       viper.GetDuration("redis.read_timeout"),
+			WriteTimeout:      viper.GetDuration("redis.write_timeout"),
+			ConnectTimeout:    viper.GetDuration("redis.connect_timeout"),
+			ReconnectInterval: viper.GetDuration("redis.reconnect_interval"),
+		},
+		Cache: Cache{
+			KeyPrefix:        viper.GetString("cache.key_prefix"),
+			PostTTL:          viper.GetDuration("cache.post_ttl"),
+			UserTTL:          viper.GetDuration("cache.user_ttl"),
+			ListTTL:          viper.GetDuration("cache.list_ttl"),
+			PostCountTTL:     viper.GetDuration("cache.post_count_ttl"),
+			TagSuggestionTTL: viper.GetDuration("cache.tag_suggestion_ttl"),
+			TagLatestTTL:     viper.GetDuration("cache.tag_latest_ttl"),
+			BlockedUsersTTL:  viper.GetDuration("cache.blocked_users_ttl"),
+			AuthorMaxAge:     viper.GetDuration("cache.author_max_age"),
+			RecentPostsTTL:   viper.GetDuration("cache.recent_posts_ttl"),
+			Warmup: CacheWarmup{
+				Enabled: viper.GetBool("cache.warmup.enabled"),
+				Posts:   viper.GetInt("cache.warmup.posts"),
+				Timeout: viper.GetDuration("cache.warmup.timeout"),
+			},
+			KeyCount: CacheKeyCount{
+				Interval:  viper.GetDuration("cache.key_count.interval"),
+				ScanCount: viper.GetInt("cache.key_count.scan_count"),
+				Limit:     viper.GetInt("cache.key_count.limit"),
+			},
+			Invalidation: CacheInvalidation{
+				QueueSize:    viper.GetInt("cache.invalidation.queue_size"),
+				Retries:      viper.GetInt("cache.invalidation.retries"),
+				RetryBackoff: viper.GetDuration("cache.invalidation.retry_backoff"),
+				FlushTimeout: viper.GetDuration("cache.invalidation.flush_timeout"),
+			},
+		},
+		Post: Post{
+			MaxContentLength:     viper.GetInt("post.max_content_length"),
+			MaxTags:              viper.GetInt("post.max_tags"),
+			MaxFeedAuthors:       viper.GetInt("post.max_feed_authors"),
+			HydrationConcurrency: viper.GetInt("post.hydration_concurrency"),
+			DefaultListLimit:     viper.GetInt("post.default_list_limit"),
+			MaxListLimit:         viper.GetInt("post.max_list_limit"),
+			ExcerptLength:        viper.GetInt("post.excerpt_length"),
+			MediaHosts:           viper.GetStringSlice("post.media_hosts"),
+			MediaAllowAllHosts:   viper.GetBool("post.media_allow_all_hosts"),
+			Languages:            viper.GetStringSlice("post.languages"),
+			Sources:              viper.GetStringSlice("post.sources"),
+			MaxRevisions:         viper.GetInt("post.max_revisions"),
+			MaxEmbeddedMedia:     viper.GetInt("post.max_embedded_media"),
+		},
+		RateLimit: RateLimit{
+			Enabled: viper.GetBool("rate_limit.enabled"),
+			CreatePost: RateLimitRule{
+				Limit:  viper.GetInt("rate_limit.create_post.limit"),
+				Window: viper.GetDuration("rate_limit.create_post.window"),
+			},
+			BulkCreatePosts: RateLimitRule{
+				Limit:  viper.GetInt("rate_limit.bulk_create_posts.limit"),
+				Window: viper.GetDuration("rate_limit.bulk_create_posts.window"),
+			},
+			UpdatePost: RateLimitRule{
+				Limit:  viper.GetInt("rate_limit.update_post.limit"),
+				Window: viper.GetDuration("rate_limit.update_post.window"),
+			},
+			DeletePost: RateLimitRule{
+				Limit:  viper.GetInt("rate_limit.delete_post.limit"),
+				Window: viper.GetDuration("rate_limit.delete_post.window"),
+			},
+		},
+		Archive: Archive{
+			Enabled:      viper.GetBool("archive.enabled"),
+			Retention:    viper.GetDuration("archive.retention"),
+			BatchSize:    viper.GetInt("archive.batch_size"),
+			Interval:     viper.GetDuration("archive.interval"),
+			ReadFallback: viper.GetBool("archive.read_fallback"),
+		},
+		Scheduler: Scheduler{
+			Enabled:   viper.GetBool("scheduler.enabled"),
+			BatchSize: viper.GetInt("scheduler.batch_size"),
+			Interval:  viper.GetDuration("scheduler.interval"),
+		},
+		AuthorSnapshot: AuthorSnapshot{
+			ReadAuthorFromSnapshot: viper.GetBool("author_snapshot.read_author_from_snapshot"),
+			StaleAfter:             viper.GetDuration("author_snapshot.stale_after"),
+			RefreshInterval:        viper.GetDuration("author_snapshot.refresh_interval"),
+			RefreshBatchSize:       viper.GetInt("author_snapshot.refresh_batch_size"),
+		},
+		Events: Events{
+			Enabled: viper.GetBool("events.enabled"),
+			Channel: viper.GetString("events.channel"),
+		},
+		Orphans: Orphans{
+			BatchSize: viper.GetInt("orphans.batch_size"),
+			Pause:     viper.GetDuration("orphans.pause"),
 		},
 	}
-
-	return config
 }
@@ -0,0 +1,585 @@
+package config
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+func TestCache_Validate(t *testing.T) {
+	valid := Cache{PostTTL: 30 * time.Minute, UserTTL: 15 * time.Minute, ListTTL: 5 * time.Minute, PostCountTTL: time.Minute, TagSuggestionTTL: time.Minute, BlockedUsersTTL: 30 * time.Second,
+		TagLatestTTL: 5 * time.Minute, AuthorMaxAge: time.Minute, RecentPostsTTL: 30 * time.Second, KeyCount: CacheKeyCount{Interval: time.Minute, ScanCount: 1000, Limit: 100000},
+		Invalidation: CacheInvalidation{QueueSize: 10000, Retries: 3, RetryBackoff: 100 * time.Millisecond, FlushTimeout: 5 * time.Second}}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(c *Cache)
+	}{
+		{"zero post ttl", func(c *Cache) { c.PostTTL = 0 }},
+		{"negative user ttl", func(c *Cache) { c.UserTTL = -time.Second }},
+		{"zero list ttl", func(c *Cache) { c.ListTTL = 0 }},
+		{"zero post count ttl", func(c *Cache) { c.PostCountTTL = 0 }},
+		{"zero tag suggestion ttl", func(c *Cache) { c.TagSuggestionTTL = 0 }},
+		{"zero tag latest ttl", func(c *Cache) { c.TagLatestTTL = 0 }},
+		{"zero blocked users ttl", func(c *Cache) { c.BlockedUsersTTL = 0 }},
+		{"zero author max age", func(c *Cache) { c.AuthorMaxAge = 0 }},
+		{"zero recent posts ttl", func(c *Cache) { c.RecentPostsTTL = 0 }},
+		{"warmup without posts", func(c *Cache) { c.Warmup = CacheWarmup{Enabled: true, Timeout: time.Second} }},
+		{"warmup without timeout", func(c *Cache) { c.Warmup = CacheWarmup{Enabled: true, Posts: 10} }},
+		{"zero key count interval", func(c *Cache) { c.KeyCount.Interval = 0 }},
+		{"zero key count scan count", func(c *Cache) { c.KeyCount.ScanCount = 0 }},
+		{"zero key count limit", func(c *Cache) { c.KeyCount.Limit = 0 }},
+		{"zero invalidation queue size", func(c *Cache) { c.Invalidation.QueueSize = 0 }},
+		{"negative invalidation retries", func(c *Cache) { c.Invalidation.Retries = -1 }},
+		{"zero invalidation retry backoff", func(c *Cache) { c.Invalidation.RetryBackoff = 0 }},
+		{"zero invalidation flush timeout", func(c *Cache) { c.Invalidation.FlushTimeout = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			tt.mutate(&c)
+			assert.Error(t, c.Validate())
+		})
+	}
+}
+
+func TestPost_Validate(t *testing.T) {
+	assert.NoError(t, Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 280, MaxRevisions: 20, MaxEmbeddedMedia: 20, MediaAllowAllHosts: true}.Validate())
+	assert.Error(t, Post{MaxContentLength: 0, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 280, MaxRevisions: 20}.Validate())
+	assert.Error(t, Post{MaxContentLength: 50000, MaxTags: -1, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 280, MaxRevisions: 20}.Validate())
+	assert.Error(t, Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 0, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 280, MaxRevisions: 20}.Validate())
+	assert.Error(t, Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 0}.Validate())
+	assert.Error(t, Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 200, MaxListLimit: 100}.Validate())
+	assert.Error(t, Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 0}.Validate())
+	assert.Error(t, Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 280, MediaAllowAllHosts: true}.Validate(), "max_revisions is required")
+	assert.Error(t, Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 280, MaxRevisions: 20, MaxEmbeddedMedia: 101, MediaAllowAllHosts: true}.Validate())
+	assert.Error(t, Post{MaxContentLength: 50000, MaxTags: 10}.Validate())
+}
+
+func TestPost_ValidateMediaHosts(t *testing.T) {
+	valid := Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 280, MaxRevisions: 20, MaxEmbeddedMedia: 20}
+
+	tests := []struct {
+		name     string
+		hosts    []string
+		allowAll bool
+		wantErr  bool
+	}{
+		{name: "no hosts", wantErr: true},
+		{name: "allow all", allowAll: true},
+		{name: "exact and wildcard hosts", hosts: []string{"media.pinstack.io", "*.cdn.pinstack.io"}},
+		{name: "wildcard in the middle", hosts: []string{"cdn.*.pinstack.io"}, wantErr: true},
+		{name: "bare wildcard", hosts: []string{"*"}, wantErr: true},
+		{name: "empty host", hosts: []string{""}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			p.MediaHosts, p.MediaAllowAllHosts = tt.hosts, tt.allowAll
+			if tt.wantErr {
+				assert.Error(t, p.Validate())
+			} else {
+				assert.NoError(t, p.Validate())
+			}
+		})
+	}
+
+	p := valid
+	p.MediaHosts = []string{"Media.Pinstack.IO", "*.CDN.pinstack.io", "bücher.example"}
+	allowlist := p.MediaHostAllowlist()
+	assert.False(t, allowlist.AllowAll)
+	assert.Equal(t, []string{"media.pinstack.io", "*.cdn.pinstack.io", "xn--bcher-kva.example"}, allowlist.Hosts)
+}
+
+func TestPost_ValidateLanguages(t *testing.T) {
+	p := Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 280, MaxRevisions: 20, MaxEmbeddedMedia: 20, MediaAllowAllHosts: true}
+
+	p.Languages = []string{"EN", "ru", "pt-br"}
+	require.NoError(t, p.Validate())
+	assert.Equal(t, model.Languages{"en", "ru", "pt-BR"}, p.LanguageAllowlist())
+
+	p.Languages = []string{"en", "english!"}
+	assert.ErrorContains(t, p.Validate(), `post.languages: "english!" must be a BCP-47 language tag`)
+}
+
+func TestPost_ValidateSources(t *testing.T) {
+	p := Post{MaxContentLength: 50000, MaxTags: 10, MaxFeedAuthors: 500, HydrationConcurrency: 8, DefaultListLimit: 20, MaxListLimit: 100, ExcerptLength: 280, MaxRevisions: 20, MaxEmbeddedMedia: 20, MediaAllowAllHosts: true}
+
+	p.Sources = []string{"Web", " ios ", "android", "partner_api"}
+	require.NoError(t, p.Validate())
+	assert.Equal(t, model.PostSources{"web", "ios", "android", "partner_api"}, p.SourceAllowlist())
+
+	p.Sources = []string{"web", "smart tv"}
+	assert.ErrorContains(t, p.Validate(), `post.sources: "smart tv" must be`)
+}
+
+var (
+	validPool     = DatabasePool{MaxConns: 20, MinConns: 2, MaxConnLifetime: time.Hour, MaxConnIdleTime: 30 * time.Minute, HealthCheckPeriod: time.Minute}
+	validDatabase = Database{Host: "post-db", Port: "5434", Username: "postgres", DbName: "postservice", QueryTimeout: 5 * time.Second, ConnectTimeout: 5 * time.Second, Pool: validPool, Batch: DatabaseBatch{MaxTags: 100, MaxMedia: 50, MaxDetach: 500}}
+	validRedis    = Redis{Address: "redis", Port: 6379, PoolSize: 10, OpTimeout: 500 * time.Millisecond, DialTimeout: 5 * time.Second, ReadTimeout: 3 * time.Second, WriteTimeout: 3 * time.Second, ConnectTimeout: 5 * time.Second, ReconnectInterval: 10 * time.Second}
+)
+
+func TestTimeouts_Validate(t *testing.T) {
+	assert.NoError(t, validDatabase.Validate())
+	noTimeout := validDatabase
+	noTimeout.QueryTimeout = 0
+	assert.NoError(t, noTimeout.Validate(), "zero disables the timeout")
+	negative := validDatabase
+	negative.QueryTimeout = -time.Second
+	assert.Error(t, negative.Validate())
+
+	negative = validDatabase
+	negative.SlowQueryThreshold = -time.Millisecond
+	assert.Error(t, negative.Validate())
+	negative = validDatabase
+	negative.DebugLogSampleRate = -1
+	assert.Error(t, negative.Validate())
+}
+
+func TestDatabase_ValidateDriver(t *testing.T) {
+	postgres := validDatabase
+	postgres.Driver = DriverPostgres
+	assert.NoError(t, postgres.Validate())
+	assert.NoError(t, Database{Driver: DriverMemory}.Validate(), "the memory driver needs no pool")
+	assert.Error(t, Database{Driver: "sqlite"}.Validate())
+
+	assert.NoError(t, validRedis.Validate())
+	negative := validRedis
+	negative.OpTimeout = -time.Millisecond
+	assert.Error(t, negative.Validate())
+}
+
+func TestDatabase_ValidatePool(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(d *Database)
+	}{
+		{"zero connect timeout", func(d *Database) { d.ConnectTimeout = 0 }},
+		{"negative tag lock timeout", func(d *Database) { d.TagLockTimeout = -time.Second }},
+		{"zero max conns", func(d *Database) { d.Pool.MaxConns = 0 }},
+		{"negative min conns", func(d *Database) { d.Pool.MinConns = -1 }},
+		{"min conns above max conns", func(d *Database) { d.Pool.MinConns = 21 }},
+		{"zero max conn lifetime", func(d *Database) { d.Pool.MaxConnLifetime = 0 }},
+		{"zero max conn idle time", func(d *Database) { d.Pool.MaxConnIdleTime = 0 }},
+		{"negative health check period", func(d *Database) { d.Pool.HealthCheckPeriod = -time.Second }},
+		{"zero batch max tags", func(d *Database) { d.Batch.MaxTags = 0 }},
+		{"negative batch max detach", func(d *Database) { d.Batch.MaxDetach = -1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := validDatabase
+			tt.mutate(&d)
+			assert.Error(t, d.Validate())
+		})
+	}
+}
+
+func TestRedis_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(r *Redis)
+	}{
+		{"zero pool size", func(r *Redis) { r.PoolSize = 0 }},
+		{"zero dial timeout", func(r *Redis) { r.DialTimeout = 0 }},
+		{"negative read timeout", func(r *Redis) { r.ReadTimeout = -time.Second }},
+		{"zero write timeout", func(r *Redis) { r.WriteTimeout = 0 }},
+		{"zero connect timeout", func(r *Redis) { r.ConnectTimeout = 0 }},
+		{"zero reconnect interval", func(r *Redis) { r.ReconnectInterval = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validRedis
+			tt.mutate(&r)
+			assert.Error(t, r.Validate())
+		})
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	setDefaults()
+
+	cfg := fromViper()
+
+	assert.Equal(t, validPool, cfg.Database.Pool)
+	assert.Equal(t, 5*time.Second, cfg.Database.ConnectTimeout)
+	assert.Equal(t, 2*time.Second, cfg.Database.TagLockTimeout)
+	assert.Equal(t, validRedis, Redis{
+		Address:           cfg.Redis.Address,
+		Port:              cfg.Redis.Port,
+		PoolSize:          cfg.Redis.PoolSize,
+		OpTimeout:         cfg.Redis.OpTimeout,
+		DialTimeout:       cfg.Redis.DialTimeout,
+		ReadTimeout:       cfg.Redis.ReadTimeout,
+		WriteTimeout:      cfg.Redis.WriteTimeout,
+		ConnectTimeout:    cfg.Redis.ConnectTimeout,
+		ReconnectInterval: cfg.Redis.ReconnectInterval,
+	})
+	assert.Equal(t, 15*time.Second, cfg.Prometheus.PoolStatsInterval)
+	assert.Equal(t, 16<<20, cfg.GRPCServer.MaxRecvMsgSize)
+	assert.Equal(t, 30*time.Second, cfg.GRPCServer.Keepalive.MinTime)
+	assert.Equal(t, 20*time.Second, cfg.GRPCServer.DrainTimeout)
+	assert.False(t, cfg.GRPCServer.TLS.Insecure, "TLS is the default")
+	assert.False(t, cfg.UserService.TLS.Insecure)
+	assert.Error(t, cfg.GRPCServer.Validate(), "TLS without a certificate")
+	assert.NoError(t, cfg.UserService.Validate(), "the user service is verified against the system roots")
+	assert.Error(t, cfg.Post.Validate(), "no media hosts are allowed until they are configured")
+
+	for name, v := range map[string]interface{ Validate() error }{
+		"database": cfg.Database, "redis": cfg.Redis, "prometheus": cfg.Prometheus, "cache": cfg.Cache,
+		"archive": cfg.Archive, "scheduler": cfg.Scheduler, "author_snapshot": cfg.AuthorSnapshot,
+	} {
+		assert.NoError(t, v.Validate(), "default %s config", name)
+	}
+}
+
+func TestDefaults_FileOverrides(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	setDefaults()
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(`
+database:
+  pool:
+    max_conns: 50
+    max_conn_lifetime: "15m"
+redis:
+  read_timeout: "250ms"
+`)))
+
+	cfg := fromViper()
+
+	assert.Equal(t, int32(50), cfg.Database.Pool.MaxConns)
+	assert.Equal(t, 15*time.Minute, cfg.Database.Pool.MaxConnLifetime)
+	assert.Equal(t, int32(2), cfg.Database.Pool.MinConns, "unset keys keep their defaults")
+	assert.Equal(t, 250*time.Millisecond, cfg.Redis.ReadTimeout)
+}
+
+func TestGRPCServer_Validate(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(cert, []byte("cert"), 0o600))
+	require.NoError(t, os.WriteFile(key, []byte("key"), 0o600))
+	missing := filepath.Join(dir, "missing.pem")
+
+	valid := GRPCServer{
+		Port:           50053,
+		MaxRecvMsgSize: 16 << 20,
+		MaxSendMsgSize: 16 << 20,
+		TLS:            ServerTLS{CertFile: cert, KeyFile: key},
+		Keepalive:      GRPCKeepalive{MinTime: 30 * time.Second, Time: 2 * time.Hour, Timeout: 20 * time.Second},
+		DrainTimeout:   20 * time.Second,
+		Budget:         RequestBudget{Default: 10 * time.Second, CacheRatio: 0.1, DatabaseRatio: 0.5, UserServiceRatio: 0.3},
+	}
+	require.NoError(t, valid.Validate())
+
+	insecure := valid
+	insecure.TLS = ServerTLS{Insecure: true}
+	assert.NoError(t, insecure.Validate(), "insecure mode needs no files")
+
+	tests := []struct {
+		name    string
+		mutate  func(g *GRPCServer)
+		wantErr string
+	}{
+		{"zero port", func(g *GRPCServer) { g.Port = 0 }, "grpc_server.port"},
+		{"port out of range", func(g *GRPCServer) { g.Port = 70000 }, "grpc_server.port"},
+		{"zero max recv size", func(g *GRPCServer) { g.MaxRecvMsgSize = 0 }, "grpc_server.max_recv_msg_size"},
+		{"negative max send size", func(g *GRPCServer) { g.MaxSendMsgSize = -1 }, "grpc_server.max_send_msg_size"},
+		{"zero keepalive min time", func(g *GRPCServer) { g.Keepalive.MinTime = 0 }, "grpc_server.keepalive.min_time"},
+		{"zero keepalive timeout", func(g *GRPCServer) { g.Keepalive.Timeout = 0 }, "grpc_server.keepalive.timeout"},
+		{"zero drain timeout", func(g *GRPCServer) { g.DrainTimeout = 0 }, "grpc_server.drain_timeout"},
+		{"zero default budget", func(g *GRPCServer) { g.Budget.Default = 0 }, "grpc_server.budget.default"},
+		{"ratio above one", func(g *GRPCServer) { g.Budget.DatabaseRatio = 1.5 }, "grpc_server.budget.database_ratio"},
+		{"negative ratio", func(g *GRPCServer) { g.Budget.CacheRatio = -0.1 }, "grpc_server.budget.cache_ratio"},
+		{"no certificate", func(g *GRPCServer) { g.TLS = ServerTLS{} }, "required unless grpc_server.tls.insecure"},
+		{"missing certificate", func(g *GRPCServer) { g.TLS.CertFile = missing }, "grpc_server.tls.cert_file"},
+		{"missing key", func(g *GRPCServer) { g.TLS.KeyFile = missing }, "grpc_server.tls.key_file"},
+		{"missing client CA", func(g *GRPCServer) { g.TLS.ClientCAFile = missing }, "grpc_server.tls.client_ca_file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := valid
+			tt.mutate(&g)
+			assert.ErrorContains(t, g.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestUserService_Validate(t *testing.T) {
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(ca, []byte("ca"), 0o600))
+
+	assert.NoError(t, UserService{Address: "user-service", Port: 50051, TLS: ClientTLS{CAFile: ca, ServerName: "users.internal"}}.Validate())
+	assert.NoError(t, UserService{Address: "user-service", Port: 50051, TLS: ClientTLS{Insecure: true, CAFile: "/does/not/exist"}}.Validate(), "insecure mode ignores the CA")
+	assert.ErrorContains(t, UserService{Address: "user-service", Port: 50051, TLS: ClientTLS{CAFile: ca + ".missing"}}.Validate(), "user_service.tls.ca_file")
+	assert.ErrorContains(t, UserService{Port: 50051, TLS: ClientTLS{Insecure: true}}.Validate(), "user_service.address")
+}
+
+func TestArchive_Validate(t *testing.T) {
+	valid := Archive{Enabled: true, Retention: 24 * time.Hour, BatchSize: 500, Interval: time.Hour}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, Archive{}.Validate(), "a disabled archive needs no settings")
+
+	tests := []struct {
+		name   string
+		mutate func(a *Archive)
+	}{
+		{"zero retention", func(a *Archive) { a.Retention = 0 }},
+		{"negative batch size", func(a *Archive) { a.BatchSize = -1 }},
+		{"zero interval", func(a *Archive) { a.Interval = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid
+			tt.mutate(&a)
+			assert.Error(t, a.Validate())
+		})
+	}
+}
+
+func TestScheduler_Validate(t *testing.T) {
+	valid := Scheduler{Enabled: true, BatchSize: 100, Interval: 30 * time.Second}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, Scheduler{}.Validate(), "a disabled scheduler needs no settings")
+
+	tests := []struct {
+		name   string
+		mutate func(s *Scheduler)
+	}{
+		{"zero batch size", func(s *Scheduler) { s.BatchSize = 0 }},
+		{"negative interval", func(s *Scheduler) { s.Interval = -time.Second }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid
+			tt.mutate(&s)
+			assert.Error(t, s.Validate())
+		})
+	}
+}
+
+func TestAuthorSnapshot_Validate(t *testing.T) {
+	valid := AuthorSnapshot{ReadAuthorFromSnapshot: true, StaleAfter: time.Hour, RefreshInterval: time.Minute, RefreshBatchSize: 200}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, AuthorSnapshot{}.Validate(), "reading authors from the user service needs no settings")
+
+	tests := []struct {
+		name   string
+		mutate func(a *AuthorSnapshot)
+	}{
+		{"zero stale after", func(a *AuthorSnapshot) { a.StaleAfter = 0 }},
+		{"negative refresh interval", func(a *AuthorSnapshot) { a.RefreshInterval = -time.Second }},
+		{"zero refresh batch size", func(a *AuthorSnapshot) { a.RefreshBatchSize = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid
+			tt.mutate(&a)
+			assert.Error(t, a.Validate())
+		})
+	}
+}
+
+func TestEvents_Validate(t *testing.T) {
+	assert.NoError(t, Events{}.Validate(), "disabled events need no channel")
+	assert.NoError(t, Events{Enabled: true, Channel: "pinstack:post-events"}.Validate())
+	assert.Error(t, Events{Enabled: true}.Validate())
+}
+
+// validConfig is the default config with the settings the defaults leave out.
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	setDefaults()
+	cfg := fromViper()
+	cfg.GRPCServer.TLS.Insecure = true
+	cfg.Post.MediaAllowAllHosts = true
+	require.NoError(t, cfg.Validate())
+	return cfg
+}
+
+func TestConfig_ValidateListsEveryProblem(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.GRPCServer.Port = 0
+	cfg.Database.DbName = ""
+	cfg.Database.Pool.MinConns = -1
+	cfg.Redis.PoolSize = -1
+	cfg.Cache.PostTTL = -time.Minute
+	cfg.RateLimit.CreatePost.Window = 0
+	cfg.Cache.Warmup = CacheWarmup{Enabled: true, Posts: cfg.Post.MaxListLimit + 1, Timeout: time.Second}
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	for _, key := range []string{
+		"grpc_server.port", "database.db_name", "database.pool.min_conns", "redis.pool_size",
+		"cache.post_ttl", "rate_limit.create_post.window", "cache.warmup.posts",
+	} {
+		assert.ErrorContains(t, err, key)
+	}
+	assert.Len(t, strings.Split(err.Error(), "\n"), 7, "one line per problem")
+}
+
+func TestConfig_ValidatePorts(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Prometheus.Port = cfg.GRPCServer.Port
+	assert.ErrorContains(t, cfg.Validate(), "prometheus.port must differ from grpc_server.port")
+
+	cfg = validConfig(t)
+	cfg.Database.Port = "postgres"
+	assert.ErrorContains(t, cfg.Validate(), "database.port must be a number")
+
+	cfg = validConfig(t)
+	cfg.Redis.Port = 65536
+	assert.ErrorContains(t, cfg.Validate(), "redis.port must be between 1 and 65535")
+}
+
+func TestConfig_ValidateMigrateOnStart(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Database.MigrateOnStart = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Env = envProd
+	assert.ErrorContains(t, cfg.Validate(), `database.migrate_on_start is refused in env "prod"`)
+}
+
+func TestRateLimit_Validate(t *testing.T) {
+	rule := RateLimitRule{Limit: 10, Window: time.Minute}
+	assert.NoError(t, RateLimit{Enabled: true, CreatePost: rule, BulkCreatePosts: rule, UpdatePost: rule, DeletePost: rule}.Validate())
+	assert.NoError(t, RateLimit{}.Validate(), "disabled rate limits need no rules")
+	assert.ErrorContains(t, RateLimit{Enabled: true, CreatePost: rule, BulkCreatePosts: rule, UpdatePost: rule}.Validate(), "rate_limit.delete_post.limit")
+	assert.ErrorContains(t, RateLimit{Enabled: true, CreatePost: rule, UpdatePost: rule, DeletePost: rule}.Validate(), "rate_limit.bulk_create_posts.window")
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	setDefaults()
+	bindEnv()
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(`
+database:
+  host: "file-db"
+  pool:
+    max_conns: 50
+redis:
+  read_timeout: "250ms"
+`)))
+	t.Setenv("POST_SERVICE_DATABASE_POOL_MAX_CONNS", "70")
+	t.Setenv("POST_SERVICE_REDIS_PASSWORD", "from-env")
+	t.Setenv("POST_SERVICE_CACHE_POST_TTL", "1h")
+
+	cfg := fromViper()
+
+	assert.Equal(t, int32(70), cfg.Database.Pool.MaxConns, "the environment wins over the file")
+	assert.Equal(t, "file-db", cfg.Database.Host, "the file wins over the defaults")
+	assert.Equal(t, 250*time.Millisecond, cfg.Redis.ReadTimeout)
+	assert.Equal(t, "from-env", cfg.Redis.Password, "keys without a file value are read from the environment")
+	assert.Equal(t, time.Hour, cfg.Cache.PostTTL)
+	assert.Equal(t, int32(2), cfg.Database.Pool.MinConns, "unset keys keep their defaults")
+}
+
+func TestConfig_LogValue(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Database.Password = "s3cret"
+	cfg.Redis.Password = ""
+
+	var buf strings.Builder
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("Effective config", slog.Any("config", cfg))
+
+	var logged struct {
+		Config map[string]any `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(buf.String()), &logged))
+	section := func(name string) map[string]any { return logged.Config[name].(map[string]any) }
+	assert.NotContains(t, buf.String(), "s3cret")
+	assert.Equal(t, "[REDACTED]", section("database")["password"])
+	assert.Equal(t, "", section("redis")["password"], "an empty secret is shown as empty")
+	assert.Equal(t, "30m0s", section("cache")["post_ttl"])
+	assert.Equal(t, float64(50053), section("grpc_server")["port"])
+
+	// Every key viper knows is logged under the same name.
+	var keys []string
+	var collect func(prefix string, m map[string]any)
+	collect = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			if nested, ok := v.(map[string]any); ok {
+				collect(prefix+k+".", nested)
+			} else {
+				keys = append(keys, prefix+k)
+			}
+		}
+	}
+	collect("", logged.Config)
+	for _, key := range viper.AllKeys() {
+		assert.Contains(t, keys, key)
+	}
+}
+
+func TestGRPCServer_ReflectionDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want bool
+	}{
+		{name: "dev", yaml: `env: "dev"`, want: true},
+		{name: "stage", yaml: `env: "stage"`, want: true},
+		{name: "prod", yaml: `env: "prod"`, want: false},
+		{name: "enabled in prod", yaml: "env: \"prod\"\ngrpc_server:\n  reflection: true", want: true},
+		{name: "disabled in dev", yaml: "env: \"dev\"\ngrpc_server:\n  reflection: false", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			setDefaults()
+			viper.SetConfigType("yaml")
+			require.NoError(t, viper.ReadConfig(strings.NewReader(tt.yaml)))
+
+			assert.Equal(t, tt.want, fromViper().GRPCServer.Reflection)
+		})
+	}
+}
+
+func TestLog_LevelDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want slog.Level
+	}{
+		{name: "dev", yaml: `env: "dev"`, want: slog.LevelDebug},
+		{name: "prod", yaml: `env: "prod"`, want: slog.LevelInfo},
+		{name: "set in prod", yaml: "env: \"prod\"\nlog:\n  level: \"warn\"", want: slog.LevelWarn},
+		{name: "set in dev", yaml: "env: \"dev\"\nlog:\n  level: \"info\"", want: slog.LevelInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			setDefaults()
+			viper.SetConfigType("yaml")
+			require.NoError(t, viper.ReadConfig(strings.NewReader(tt.yaml)))
+
+			log := fromViper().Log
+			require.NoError(t, log.Validate())
+			assert.Equal(t, tt.want, log.SlogLevel())
+		})
+	}
+
+	assert.Error(t, Log{Level: "verbose", DebugDuration: time.Minute}.Validate())
+	assert.Error(t, Log{Level: "info"}.Validate(), "the debug window needs a duration")
+}
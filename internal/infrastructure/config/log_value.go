@@ -0,0 +1,59 @@
+package config
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// redacted replaces the value of a field tagged `config:"secret"` in logs.
+const redacted = "[REDACTED]"
+
+// LogValue renders the config as nested groups named after its keys, so database.pool.max_conns
+// logs as database > pool > max_conns. Durations are written like "30s" and secrets are redacted.
+func (c *Config) LogValue() slog.Value {
+	return structValue(reflect.ValueOf(*c))
+}
+
+func structValue(v reflect.Value) slog.Value {
+	t := v.Type()
+	attrs := make([]slog.Attr, 0, t.NumField())
+	for i := range t.NumField() {
+		field, value := t.Field(i), v.Field(i)
+		key := snakeCase(field.Name)
+		switch {
+		case field.Tag.Get("config") == "secret":
+			if value.String() != "" {
+				attrs = append(attrs, slog.String(key, redacted))
+			} else {
+				attrs = append(attrs, slog.String(key, ""))
+			}
+		case value.Type() == reflect.TypeFor[time.Duration]():
+			attrs = append(attrs, slog.String(key, time.Duration(value.Int()).String()))
+		case value.Kind() == reflect.Struct:
+			attrs = append(attrs, slog.Attr{Key: key, Value: structValue(value)})
+		default:
+			attrs = append(attrs, slog.Any(key, value.Interface()))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// snakeCase turns a field name into its config key: GRPCServer becomes grpc_server and
+// ClientCAFile client_ca_file.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
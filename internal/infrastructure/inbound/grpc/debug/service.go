@@ -0,0 +1,235 @@
+// Package debug_grpc serves DebugService, an internal-only gRPC service that reports the
+// build, the effective configuration and the database schema version of the running binary,
+// and runs maintenance jobs. The proto definitions have no debug RPCs, so the service is
+// described here with well-known types: GetDebugInfo takes a google.protobuf.Empty and
+// ReconcileOrphans a google.protobuf.BoolValue, and both return a google.protobuf.Struct.
+package debug_grpc
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/config"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	"pinstack-post-service/internal/infrastructure/migrator"
+)
+
+const (
+	ServiceName = "pinstack.post.debug.v1.DebugService"
+	// GetDebugInfoFullMethod is the method name the admin interceptor guards.
+	GetDebugInfoFullMethod = "/" + ServiceName + "/GetDebugInfo"
+	// ReconcileOrphansFullMethod is guarded by the admin interceptor too.
+	ReconcileOrphansFullMethod = "/" + ServiceName + "/ReconcileOrphans"
+
+	descriptorFile = "pinstack/post/debug/v1/debug.proto"
+)
+
+// BuildInfo identifies the binary; main sets it from variables injected with -ldflags.
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	BuildDate string
+}
+
+// DebugServer is the server API of DebugService.
+type DebugServer interface {
+	GetDebugInfo(ctx context.Context, req *emptypb.Empty) (*structpb.Struct, error)
+	ReconcileOrphans(ctx context.Context, req *wrapperspb.BoolValue) (*structpb.Struct, error)
+}
+
+// SchemaVersionFunc reads the schema version of the database the service runs against.
+type SchemaVersionFunc func(ctx context.Context) (migrator.Version, error)
+
+// OrphanReconciler removes post_media and posts_tags rows left without their post or tag.
+type OrphanReconciler interface {
+	ReconcileOrphans(ctx context.Context, dryRun bool) (*model.OrphanReport, error)
+}
+
+type Service struct {
+	config        *config.Config
+	build         BuildInfo
+	schemaVersion SchemaVersionFunc
+	orphans       OrphanReconciler
+	log           ports.Logger
+}
+
+// NewService reports the schema version read by schemaVersion; nil leaves it out, as for the
+// in-memory database.
+func NewService(cfg *config.Config, build BuildInfo, schemaVersion SchemaVersionFunc, orphans OrphanReconciler, log ports.Logger) *Service {
+	return &Service{config: cfg, build: build, schemaVersion: schemaVersion, orphans: orphans, log: log}
+}
+
+// GetDebugInfo returns {"build": {...}, "config": {...}, "schema": {...}}, with the config
+// keyed like the config file and its secrets redacted. The schema has the applied version,
+// whether it is dirty and the latest version the binary embeds, or the error reading them.
+func (s *Service) GetDebugInfo(ctx context.Context, _ *emptypb.Empty) (*structpb.Struct, error) {
+	fields := map[string]any{
+		"build": map[string]any{
+			"version":    s.build.Version,
+			"commit":     s.build.Commit,
+			"build_date": s.build.BuildDate,
+		},
+		"config": valueOf(s.config.LogValue()),
+	}
+	if s.schemaVersion != nil {
+		fields["schema"] = s.schema(ctx)
+	}
+	info, err := structpb.NewStruct(fields)
+	if err != nil {
+		s.log.Error("Failed to encode debug info", slog.String("error", err.Error()))
+		return nil, errinfo.Error(codes.Internal, errinfo.ReasonInternal, "failed to encode debug info")
+	}
+	return info, nil
+}
+
+func (s *Service) schema(ctx context.Context) map[string]any {
+	latest, err := migrator.Latest()
+	if err != nil {
+		s.log.Error("Failed to read embedded migrations", slog.String("error", err.Error()))
+		return map[string]any{"error": err.Error()}
+	}
+	version, err := s.schemaVersion(ctx)
+	if err != nil {
+		s.log.Warn("Failed to read schema version", slog.String("error", err.Error()))
+		return map[string]any{"error": err.Error(), "latest": latest}
+	}
+	return map[string]any{"version": version.Version, "dirty": version.Dirty, "latest": latest}
+}
+
+// ReconcileOrphans removes the orphaned post_media and posts_tags rows, or with req true only
+// counts them, and returns {"dry_run": ..., "found": {...}, "removed": {...}} keyed by kind.
+// It runs in the call, so a large cleanup needs a generous deadline; when the deadline cuts
+// it short, the batches already removed stay removed.
+func (s *Service) ReconcileOrphans(ctx context.Context, req *wrapperspb.BoolValue) (*structpb.Struct, error) {
+	report, err := s.orphans.ReconcileOrphans(ctx, req.GetValue())
+	if err != nil {
+		s.log.Error("Failed to reconcile orphans", slog.String("error", err.Error()))
+		return nil, errinfo.Error(codes.Internal, errinfo.ReasonInternal, "failed to reconcile orphans")
+	}
+	result, err := structpb.NewStruct(map[string]any{
+		"dry_run": report.DryRun,
+		"found":   orphanCounts(report.Found),
+		"removed": orphanCounts(report.Removed),
+	})
+	if err != nil {
+		s.log.Error("Failed to encode orphan report", slog.String("error", err.Error()))
+		return nil, errinfo.Error(codes.Internal, errinfo.ReasonInternal, "failed to encode orphan report")
+	}
+	return result, nil
+}
+
+func orphanCounts(counts map[model.OrphanKind]int) map[string]any {
+	result := make(map[string]any, len(model.OrphanKinds))
+	for _, kind := range model.OrphanKinds {
+		result[string(kind)] = counts[kind]
+	}
+	return result
+}
+
+// valueOf turns a resolved log value into the plain values structpb accepts.
+func valueOf(v slog.Value) any {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := make(map[string]any)
+		for _, attr := range v.Group() {
+			group[attr.Key] = valueOf(attr.Value)
+		}
+		return group
+	case slog.KindAny:
+		if strs, ok := v.Any().([]string); ok {
+			list := make([]any, len(strs))
+			for i, s := range strs {
+				list[i] = s
+			}
+			return list
+		}
+	}
+	return v.Any()
+}
+
+// Register serves srv on s.
+func Register(s grpc.ServiceRegistrar, srv DebugServer) {
+	s.RegisterService(&ServiceDesc, srv)
+}
+
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*DebugServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetDebugInfo", Handler: getDebugInfoHandler},
+		{MethodName: "ReconcileOrphans", Handler: reconcileOrphansHandler},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: descriptorFile,
+}
+
+func getDebugInfoHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServer).GetDebugInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: GetDebugInfoFullMethod}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(DebugServer).GetDebugInfo(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func reconcileOrphansHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(wrapperspb.BoolValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugServer).ReconcileOrphans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: ReconcileOrphansFullMethod}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(DebugServer).ReconcileOrphans(ctx, req.(*wrapperspb.BoolValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// init registers the descriptor of DebugService so server reflection can describe it.
+func init() {
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String(descriptorFile),
+		Package:    proto.String("pinstack.post.debug.v1"),
+		Dependency: []string{"google/protobuf/empty.proto", "google/protobuf/struct.proto", "google/protobuf/wrappers.proto"},
+		Syntax:     proto.String("proto3"),
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("DebugService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("GetDebugInfo"),
+				InputType:  proto.String(".google.protobuf.Empty"),
+				OutputType: proto.String(".google.protobuf.Struct"),
+			}, {
+				Name:       proto.String("ReconcileOrphans"),
+				InputType:  proto.String(".google.protobuf.BoolValue"),
+				OutputType: proto.String(".google.protobuf.Struct"),
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		panic(err)
+	}
+	if err := protoregistry.GlobalFiles.RegisterFile(file); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,169 @@
+package delivery_grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/config"
+	delivery_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc"
+	debug_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/debug"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/inbound/middleware"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/migrator"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+// stubReconciler finds two orphaned media rows and removes them unless dryRun.
+type stubReconciler struct {
+	calls []bool
+}
+
+func (s *stubReconciler) ReconcileOrphans(ctx context.Context, dryRun bool) (*model.OrphanReport, error) {
+	s.calls = append(s.calls, dryRun)
+	report := &model.OrphanReport{DryRun: dryRun, Found: map[model.OrphanKind]int{model.OrphanMedia: 2}, Removed: map[model.OrphanKind]int{}}
+	if !dryRun {
+		report.Removed[model.OrphanMedia] = 2
+	}
+	return report, nil
+}
+
+// startDebugServer serves the debug service for cfg, and reflection when reflection is set.
+func startDebugServer(t *testing.T, cfg *config.Config, reflection bool) *grpc.ClientConn {
+	conn, _ := startDebugServerWith(t, cfg, reflection)
+	return conn
+}
+
+func startDebugServerWith(t *testing.T, cfg *config.Config, reflection bool) (*grpc.ClientConn, *stubReconciler) {
+	t.Helper()
+	log := logger.New("test")
+	server := delivery_grpc.NewServer(post_grpc.NewPostGRPCService(new(mockpost.Service), log), "127.0.0.1", 0, log, prometheus.NewPrometheusMetricsProvider())
+	schemaVersion := func(context.Context) (migrator.Version, error) { return migrator.Version{Version: 7, Dirty: true}, nil }
+	orphans := &stubReconciler{}
+	server.RegisterDebug(debug_grpc.NewService(cfg, debug_grpc.BuildInfo{Version: "1.2.3", Commit: "abc123", BuildDate: "2026-10-01"}, schemaVersion, orphans, log))
+	if reflection {
+		server.EnableReflection()
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, orphans
+}
+
+func debugConfig() *config.Config {
+	return &config.Config{
+		Env:      "dev",
+		Database: config.Database{Host: "post-db", Username: "postgres", Password: "db-secret"},
+		Redis:    config.Redis{Address: "redis", Password: "redis-secret"},
+		Post:     config.Post{MediaHosts: []string{"media.pinstack.io"}},
+	}
+}
+
+func TestServer_DebugInfoRequiresAdmin(t *testing.T) {
+	conn := startDebugServer(t, debugConfig(), false)
+
+	var info structpb.Struct
+	err := conn.Invoke(context.Background(), debug_grpc.GetDebugInfoFullMethod, &emptypb.Empty{}, &info)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	admin := metadata.AppendToOutgoingContext(context.Background(), middleware.InternalAdminMetadataKey, "true")
+	require.NoError(t, conn.Invoke(admin, debug_grpc.GetDebugInfoFullMethod, &emptypb.Empty{}, &info))
+
+	got := info.AsMap()
+	assert.Equal(t, map[string]any{"version": "1.2.3", "commit": "abc123", "build_date": "2026-10-01"}, got["build"])
+	latest, err := migrator.Latest()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"version": 7.0, "dirty": true, "latest": float64(latest)}, got["schema"])
+	cfg := got["config"].(map[string]any)
+	database := cfg["database"].(map[string]any)
+	assert.Equal(t, "post-db", database["host"])
+	assert.Equal(t, "[REDACTED]", database["password"])
+	assert.Equal(t, "[REDACTED]", cfg["redis"].(map[string]any)["password"])
+	assert.Equal(t, []any{"media.pinstack.io"}, cfg["post"].(map[string]any)["media_hosts"])
+	raw, err := info.MarshalJSON()
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "secret")
+}
+
+func TestServer_ReconcileOrphansRequiresAdmin(t *testing.T) {
+	conn, orphans := startDebugServerWith(t, debugConfig(), false)
+
+	var report structpb.Struct
+	err := conn.Invoke(context.Background(), debug_grpc.ReconcileOrphansFullMethod, wrapperspb.Bool(false), &report)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, orphans.calls)
+
+	admin := metadata.AppendToOutgoingContext(context.Background(), middleware.InternalAdminMetadataKey, "true")
+	require.NoError(t, conn.Invoke(admin, debug_grpc.ReconcileOrphansFullMethod, wrapperspb.Bool(true), &report))
+	assert.Equal(t, map[string]any{
+		"dry_run": true,
+		"found":   map[string]any{"post_media": 2.0, "posts_tags_post": 0.0, "posts_tags_tag": 0.0},
+		"removed": map[string]any{"post_media": 0.0, "posts_tags_post": 0.0, "posts_tags_tag": 0.0},
+	}, report.AsMap())
+
+	require.NoError(t, conn.Invoke(admin, debug_grpc.ReconcileOrphansFullMethod, wrapperspb.Bool(false), &report))
+	assert.Equal(t, 2.0, report.AsMap()["removed"].(map[string]any)["post_media"])
+	assert.Equal(t, []bool{true, false}, orphans.calls)
+}
+
+func TestServer_ReflectionIsOptIn(t *testing.T) {
+	listServices := func(t *testing.T, conn *grpc.ClientConn) ([]string, error) {
+		t.Helper()
+		stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+		}))
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, s := range resp.GetListServicesResponse().GetService() {
+			names = append(names, s.GetName())
+		}
+		return names, nil
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := listServices(t, startDebugServer(t, debugConfig(), false))
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		conn := startDebugServer(t, debugConfig(), true)
+		names, err := listServices(t, conn)
+		require.NoError(t, err)
+		assert.Contains(t, names, "post.v1.PostService")
+		assert.Contains(t, names, debug_grpc.ServiceName)
+
+		stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: debug_grpc.ServiceName},
+		}))
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.GetFileDescriptorResponse().GetFileDescriptorProto(), "the debug service can be described")
+	})
+}
@@ -0,0 +1,104 @@
+// Package errinfo builds the non-OK gRPC statuses the service returns. Each carries a
+// google.rpc.ErrorInfo detail in Domain whose reason says what went wrong. Clients switch on
+// the reason; the status message is for people and may be reworded at any time.
+package errinfo
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain is the ErrorInfo domain of every status the service returns.
+const Domain = "post-service"
+
+// Reasons the service reports. A reason is never renamed or reused for another error once
+// clients may see it.
+const (
+	// The request is malformed: a missing or out-of-range field, an unknown enum value.
+	ReasonInvalidArgument = "INVALID_ARGUMENT"
+	// The post breaks a content rule; a BadRequest detail names the fields when it can.
+	ReasonValidationFailed = "VALIDATION_FAILED"
+	ReasonEmptyUpdate      = "EMPTY_UPDATE"
+	ReasonMediaDuplicate   = "MEDIA_DUPLICATE"
+	// The post exists but is not in a state that allows the operation, such as pinning a
+	// draft or cancelling the schedule of a post that has none.
+	ReasonFailedPrecondition = "FAILED_PRECONDITION"
+
+	ReasonPostNotFound     = "POST_NOT_FOUND"
+	ReasonTagNotFound      = "TAG_NOT_FOUND"
+	ReasonUserNotFound     = "USER_NOT_FOUND"
+	ReasonTagAlreadyExists = "TAG_ALREADY_EXISTS"
+	// The post changed since the version the update names; the ErrorInfo metadata holds
+	// post_id and current_version.
+	ReasonVersionConflict = "VERSION_CONFLICT"
+
+	ReasonNotAuthor = "NOT_AUTHOR"
+	ReasonNotAdmin  = "NOT_ADMIN"
+
+	// A RetryInfo detail says when to try again.
+	ReasonRateLimited = "RATE_LIMITED"
+	// The caller's deadline passed, or the caller went away.
+	ReasonDeadlineExceeded = "DEADLINE_EXCEEDED"
+	ReasonCanceled         = "CANCELED"
+	// A database or cache query ran out of its own time, with the caller's deadline still ahead.
+	ReasonTimeout = "TIMEOUT"
+
+	// The user service could not be reached or failed.
+	ReasonDependencyUnavailable = "DEPENDENCY_UNAVAILABLE"
+	ReasonMediaQueryFailed      = "MEDIA_QUERY_FAILED"
+	ReasonMediaAttachFailed     = "MEDIA_ATTACH_FAILED"
+	ReasonMediaDetachFailed     = "MEDIA_DETACH_FAILED"
+	ReasonMediaReorderFailed    = "MEDIA_REORDER_FAILED"
+	ReasonTagQueryFailed        = "TAG_QUERY_FAILED"
+	ReasonTagCreateFailed       = "TAG_CREATE_FAILED"
+	ReasonTagDeleteFailed       = "TAG_DELETE_FAILED"
+	ReasonTagPostFailed         = "TAG_POST_FAILED"
+	ReasonDatabaseError         = "DATABASE_ERROR"
+	ReasonInternal              = "INTERNAL"
+)
+
+// Error returns a status error with code and msg whose ErrorInfo carries reason.
+func Error(code codes.Code, reason, msg string) error {
+	return New(code, reason, msg).Err()
+}
+
+// New is Error for callers that need the status itself, such as a per-item result.
+func New(code codes.Code, reason, msg string) *status.Status {
+	return WithDetails(code, msg, &errdetails.ErrorInfo{Reason: reason})
+}
+
+// WithDetails returns a status with code and msg carrying details and then info, whose
+// domain it sets. info comes last so details keep their positions.
+func WithDetails(code codes.Code, msg string, info *errdetails.ErrorInfo, details ...protoadapt.MessageV1) *status.Status {
+	info.Domain = Domain
+	st := status.New(code, msg)
+	detailed, err := st.WithDetails(append(details, info)...)
+	if err != nil {
+		// Only an OK code or a detail that fails to marshal gets here.
+		return st
+	}
+	return detailed
+}
+
+// Reason returns the ErrorInfo reason err carries in Domain, or "" when it carries none.
+func Reason(err error) string {
+	var st interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &st) {
+		return ""
+	}
+	return StatusReason(st.GRPCStatus())
+}
+
+// StatusReason is Reason for a status.
+func StatusReason(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == Domain {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package errinfo_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+)
+
+func TestError(t *testing.T) {
+	err := errinfo.Error(codes.NotFound, errinfo.ReasonPostNotFound, "post not found")
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "post not found", st.Message())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, errinfo.ReasonPostNotFound, info.GetReason())
+	assert.Equal(t, errinfo.Domain, info.GetDomain())
+}
+
+func TestWithDetails_PutsErrorInfoLast(t *testing.T) {
+	st := errinfo.WithDetails(codes.InvalidArgument, "post validation failed",
+		&errdetails.ErrorInfo{Reason: errinfo.ReasonValidationFailed},
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "title"}}})
+
+	require.Len(t, st.Details(), 2)
+	_, ok := st.Details()[0].(*errdetails.BadRequest)
+	assert.True(t, ok)
+	assert.Equal(t, errinfo.ReasonValidationFailed, errinfo.StatusReason(st))
+}
+
+func TestReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "status with reason", err: errinfo.Error(codes.Aborted, errinfo.ReasonVersionConflict, "conflict"), want: errinfo.ReasonVersionConflict},
+		{name: "wrapped status", err: fmt.Errorf("call: %w", errinfo.Error(codes.Internal, errinfo.ReasonInternal, "internal")), want: errinfo.ReasonInternal},
+		{name: "status without details", err: status.Error(codes.Internal, "internal"), want: ""},
+		{
+			name: "another domain",
+			err: func() error {
+				st, _ := status.New(codes.Internal, "internal").WithDetails(&errdetails.ErrorInfo{Reason: "OTHER", Domain: "user-service"})
+				return st.Err()
+			}(),
+			want: "",
+		},
+		{name: "plain error", err: errors.New("boom"), want: ""},
+		{name: "nil", err: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errinfo.Reason(tt.err))
+		})
+	}
+}
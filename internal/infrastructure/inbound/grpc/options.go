@@ -0,0 +1,43 @@
+package delivery_grpc
+
+import (
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/config"
+	"pinstack-post-service/internal/infrastructure/inbound/middleware"
+	"pinstack-post-service/internal/infrastructure/tlsconfig"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+)
+
+// ServerOptions turns the server config into transport credentials, message size limits,
+// keepalive settings and the request budget for NewServer. Without cfg.TLS.Insecure the certificate must load.
+func ServerOptions(cfg config.GRPCServer) ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.MaxSendMsgSize),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.Keepalive.MinTime,
+			PermitWithoutStream: cfg.Keepalive.PermitWithoutStream,
+		}),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    cfg.Keepalive.Time,
+			Timeout: cfg.Keepalive.Timeout,
+		}),
+		// Runs after the interceptors of NewServer, right before the handler.
+		grpc.ChainUnaryInterceptor(middleware.UnaryBudgetInterceptor(cfg.Budget.Default, model.BudgetRatios{
+			Cache:       cfg.Budget.CacheRatio,
+			Database:    cfg.Budget.DatabaseRatio,
+			UserService: cfg.Budget.UserServiceRatio,
+		})),
+	}
+	if cfg.TLS.Insecure {
+		return opts, nil
+	}
+	tlsConfig, err := tlsconfig.Server(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, grpc.Creds(credentials.NewTLS(tlsConfig))), nil
+}
@@ -3,6 +3,8 @@ package post_grpc
 import (
 	"context"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	model "pinstack-post-service/internal/domain/models"
 	post_service "pinstack-post-service/internal/domain/ports/input/post"
 	ports "pinstack-post-service/internal/domain/ports/output"
 
@@ -10,20 +12,35 @@ import (
 	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
 )
 
-const MaxMediaPosition = 9
-const MinMediaPosition = 1
-
 var validate = validator.New()
 
+// PostGRPCService serves the PostService RPCs. The methods that take plain arguments instead
+// of pb requests have no RPC in the pinned proto definitions yet; they are called in process
+// and reach the wire once the definitions gain them.
 type PostGRPCService struct {
 	pb.UnimplementedPostServiceServer
-	postService       post_service.Service
-	log               ports.Logger
-	createPostHandler *CreatePostHandler
-	getPostHandler    *GetPostHandler
-	listPostsHandler  *ListPostsHandler
-	updatePostHandler *UpdatePostHandler
-	deletePostHandler *DeletePostHandler
+	postService        post_service.Service
+	log                ports.Logger
+	createPostHandler  *CreatePostHandler
+	getPostHandler     *GetPostHandler
+	listPostsHandler   *ListPostsHandler
+	updatePostHandler  *UpdatePostHandler
+	deletePostHandler  *DeletePostHandler
+	forceDeleteHandler *ForceDeletePostHandler
+	moderationHandler  *SetModerationStatusHandler
+	publishPostHandler *PublishPostHandler
+	tagAdminHandler    *TagAdminHandler
+	getPostTagsHandler *GetPostTagsHandler
+	exportPostsHandler *ExportAuthorPostsHandler
+	getPostsHandler    *GetPostsByIDsHandler
+	postCountHandler   *GetAuthorPostCountHandler
+	cancelHandler      *CancelScheduledPostHandler
+	suggestTagsHandler *SuggestTagsHandler
+	revisionsHandler   *GetPostRevisionsHandler
+	mediaHandler       *GetPostMediaHandler
+	pinHandler         *PinPostHandler
+	bulkCreateHandler  *BulkCreatePostsHandler
+	tagActivityHandler *HasPostsSinceHandler
 }
 
 func NewPostGRPCService(postService post_service.Service, log ports.Logger) *PostGRPCService {
@@ -32,14 +49,44 @@ func NewPostGRPCService(postService post_service.Service, log ports.Logger) *Pos
 	listPostsHandler := NewListPostsHandler(postService, validate, log)
 	updatePostHandler := NewUpdatePostHandler(postService, validate, log)
 	deletePostHandler := NewDeletePostHandler(postService, validate, log)
+	forceDeleteHandler := NewForceDeletePostHandler(postService, validate, log)
+	moderationHandler := NewSetModerationStatusHandler(postService, validate, log)
+	publishPostHandler := NewPublishPostHandler(postService, validate, log)
+	tagAdminHandler := NewTagAdminHandler(postService, validate, log)
+	getPostTagsHandler := NewGetPostTagsHandler(postService, validate, log)
+	exportPostsHandler := NewExportAuthorPostsHandler(postService, validate, log)
+	getPostsHandler := NewGetPostsByIDsHandler(postService, validate, log)
+	postCountHandler := NewGetAuthorPostCountHandler(postService, validate, log)
+	cancelHandler := NewCancelScheduledPostHandler(postService, validate, log)
+	suggestTagsHandler := NewSuggestTagsHandler(postService, validate, log)
+	revisionsHandler := NewGetPostRevisionsHandler(postService, validate, log)
+	mediaHandler := NewGetPostMediaHandler(postService, validate, log)
+	pinHandler := NewPinPostHandler(postService, validate, log)
+	bulkCreateHandler := NewBulkCreatePostsHandler(postService, validate, log)
+	tagActivityHandler := NewHasPostsSinceHandler(postService, validate, log)
 	return &PostGRPCService{
-		postService:       postService,
-		log:               log,
-		createPostHandler: createPostHandler,
-		getPostHandler:    getPostHandler,
-		listPostsHandler:  listPostsHandler,
-		updatePostHandler: updatePostHandler,
-		deletePostHandler: deletePostHandler,
+		postService:        postService,
+		log:                log,
+		createPostHandler:  createPostHandler,
+		getPostHandler:     getPostHandler,
+		listPostsHandler:   listPostsHandler,
+		updatePostHandler:  updatePostHandler,
+		deletePostHandler:  deletePostHandler,
+		forceDeleteHandler: forceDeleteHandler,
+		moderationHandler:  moderationHandler,
+		publishPostHandler: publishPostHandler,
+		tagAdminHandler:    tagAdminHandler,
+		getPostTagsHandler: getPostTagsHandler,
+		exportPostsHandler: exportPostsHandler,
+		getPostsHandler:    getPostsHandler,
+		postCountHandler:   postCountHandler,
+		cancelHandler:      cancelHandler,
+		suggestTagsHandler: suggestTagsHandler,
+		revisionsHandler:   revisionsHandler,
+		mediaHandler:       mediaHandler,
+		pinHandler:         pinHandler,
+		bulkCreateHandler:  bulkCreateHandler,
+		tagActivityHandler: tagActivityHandler,
 	}
 }
 
@@ -47,18 +94,203 @@ func (s *PostGRPCService) CreatePost(ctx context.Context, req *pb.CreatePostRequ
 	return s.createPostHandler.CreatePost(ctx, req)
 }
 
+// CreateScheduledPost and CancelScheduledPost are in process only until CreatePostRequest
+// gains scheduled_at and PostService gains a CancelScheduledPost RPC.
+func (s *PostGRPCService) CreateScheduledPost(
+	ctx context.Context,
+	req *pb.CreatePostRequest,
+	scheduledAt *timestamppb.Timestamp,
+) (*pb.Post, error) {
+	return s.createPostHandler.CreateScheduledPost(ctx, req, scheduledAt)
+}
+
+// CreatePostWithVisibility and UpdatePostVisibility are in process only until
+// CreatePostRequest and UpdatePostRequest gain a visibility field.
+func (s *PostGRPCService) CreatePostWithVisibility(ctx context.Context, req *pb.CreatePostRequest, visibility string) (*pb.Post, error) {
+	return s.createPostHandler.CreatePostWithVisibility(ctx, req, visibility)
+}
+
+// CreatePostWithLanguage, UpdatePostLanguage and ListPostsByLanguage are in process only
+// until CreatePostRequest, UpdatePostRequest and ListPostsRequest gain a language field.
+func (s *PostGRPCService) CreatePostWithLanguage(ctx context.Context, req *pb.CreatePostRequest, language string) (*pb.Post, error) {
+	return s.createPostHandler.CreatePostWithLanguage(ctx, req, language)
+}
+
+// CreatePostWithSource and ListPostsBySource are in process only until CreatePostRequest and
+// ListPostsRequest gain a source field; CreatePost reads the x-client-source metadata.
+func (s *PostGRPCService) CreatePostWithSource(ctx context.Context, req *pb.CreatePostRequest, source string) (*pb.Post, error) {
+	return s.createPostHandler.CreatePostWithSource(ctx, req, source)
+}
+
+// BulkCreatePosts is called in process by the content migration tools until PostService
+// gains a BulkCreatePosts RPC; the handler's admin flag check is the only gate.
+func (s *PostGRPCService) BulkCreatePosts(ctx context.Context, actorID int64, reqs []*pb.CreatePostRequest) (*BulkCreatePostsResponse, error) {
+	return s.bulkCreateHandler.BulkCreatePosts(ctx, actorID, reqs)
+}
+
 func (s *PostGRPCService) GetPost(ctx context.Context, req *pb.GetPostRequest) (*pb.Post, error) {
 	return s.getPostHandler.GetPost(ctx, req)
 }
 
+// GetPostForRequester is in process only until GetPostRequest gains requester_id and Post
+// gains is_author, can_edit, can_delete and is_pinned.
+func (s *PostGRPCService) GetPostForRequester(ctx context.Context, req *pb.GetPostRequest, requesterID *int64) (*GetPostResponse, error) {
+	return s.getPostHandler.GetPostForRequester(ctx, req, requesterID)
+}
+
+// GetPostsByIDs is in process only until PostService gains a GetPostsByIDs RPC.
+func (s *PostGRPCService) GetPostsByIDs(ctx context.Context, ids []int64) (*GetPostsByIDsResponse, error) {
+	return s.getPostsHandler.GetPostsByIDs(ctx, ids)
+}
+
 func (s *PostGRPCService) ListPosts(ctx context.Context, req *pb.ListPostsRequest) (*pb.ListPostsResponse, error) {
 	return s.listPostsHandler.ListPosts(ctx, req)
 }
 
+// ListPostsSorted is in process only until ListPostsRequest gains sort fields.
+func (s *PostGRPCService) ListPostsSorted(
+	ctx context.Context,
+	req *pb.ListPostsRequest,
+	sortBy, sortOrder string,
+) (*pb.ListPostsResponse, error) {
+	return s.listPostsHandler.ListPostsSorted(ctx, req, sortBy, sortOrder)
+}
+
+// ListPostsUpdatedSince is in process only until ListPostsRequest gains an updated_after field.
+func (s *PostGRPCService) ListPostsUpdatedSince(
+	ctx context.Context,
+	req *pb.ListPostsRequest,
+	updatedAfter *timestamppb.Timestamp,
+) (*pb.ListPostsResponse, error) {
+	return s.listPostsHandler.ListPostsUpdatedSince(ctx, req, updatedAfter)
+}
+
+// ListPostsView is in process only until ListPostsRequest gains a view field and pb.Post a
+// has_more_content flag.
+func (s *PostGRPCService) ListPostsView(ctx context.Context, req *pb.ListPostsRequest, view string) (*ListPostsViewResponse, error) {
+	return s.listPostsHandler.ListPostsView(ctx, req, view)
+}
+
+// ListPostsByMedia is in process only until ListPostsRequest gains has_media and media_type
+// fields.
+func (s *PostGRPCService) ListPostsByMedia(ctx context.Context, req *pb.ListPostsRequest, hasMedia *bool, mediaType string) (*pb.ListPostsResponse, error) {
+	return s.listPostsHandler.ListPostsByMedia(ctx, req, hasMedia, mediaType)
+}
+
+func (s *PostGRPCService) ListPostsByLanguage(ctx context.Context, req *pb.ListPostsRequest, language string) (*pb.ListPostsResponse, error) {
+	return s.listPostsHandler.ListPostsByLanguage(ctx, req, language)
+}
+
+func (s *PostGRPCService) ListPostsBySource(ctx context.Context, req *pb.ListPostsRequest, source string) (*pb.ListPostsResponse, error) {
+	return s.listPostsHandler.ListPostsBySource(ctx, req, source)
+}
+
+// ListFeed is in process only until PostService gains a ListFeed RPC.
+func (s *PostGRPCService) ListFeed(ctx context.Context, authorIDs []int64, limit, offset int) (*pb.ListPostsResponse, error) {
+	return s.listPostsHandler.ListFeed(ctx, authorIDs, limit, offset)
+}
+
 func (s *PostGRPCService) UpdatePost(ctx context.Context, req *pb.UpdatePostRequest) (*pb.Post, error) {
 	return s.updatePostHandler.UpdatePost(ctx, req)
 }
 
+// UpdatePostWithSummary is in process only until PostService.UpdatePost returns an
+// UpdatePostResponse with a change_summary field.
+func (s *PostGRPCService) UpdatePostWithSummary(ctx context.Context, req *pb.UpdatePostRequest) (*UpdatePostResponse, error) {
+	return s.updatePostHandler.UpdatePostWithSummary(ctx, req)
+}
+
+// UpdatePostIfVersion is in process only until UpdatePostRequest gains an expected_version
+// field.
+func (s *PostGRPCService) UpdatePostIfVersion(ctx context.Context, req *pb.UpdatePostRequest, expectedVersion int64) (*UpdatePostResponse, error) {
+	return s.updatePostHandler.UpdatePostIfVersion(ctx, req, expectedVersion)
+}
+
+func (s *PostGRPCService) UpdatePostVisibility(ctx context.Context, req *pb.UpdatePostRequest, visibility string) (*pb.Post, error) {
+	return s.updatePostHandler.UpdatePostVisibility(ctx, req, visibility)
+}
+
+func (s *PostGRPCService) UpdatePostLanguage(ctx context.Context, req *pb.UpdatePostRequest, language string) (*pb.Post, error) {
+	return s.updatePostHandler.UpdatePostLanguage(ctx, req, language)
+}
+
 func (s *PostGRPCService) DeletePost(ctx context.Context, req *pb.DeletePostRequest) (*emptypb.Empty, error) {
 	return s.deletePostHandler.DeletePost(ctx, req)
 }
+
+// ForceDeletePost is called in process by the moderation tools; PostService has no such RPC,
+// so the handler's admin flag check is the only gate.
+func (s *PostGRPCService) ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (*model.ModerationAction, error) {
+	return s.forceDeleteHandler.ForceDeletePost(ctx, actorID, postID, reason)
+}
+
+// SetModerationStatus is called in process by the moderation tools and gated like
+// ForceDeletePost.
+func (s *PostGRPCService) SetModerationStatus(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason) (*model.Post, error) {
+	return s.moderationHandler.SetModerationStatus(ctx, actorID, postID, status, reason)
+}
+
+// PublishPost is in process only until PostService gains a PublishPost RPC.
+func (s *PostGRPCService) PublishPost(ctx context.Context, userID int64, postID int64) (*pb.Post, error) {
+	return s.publishPostHandler.PublishPost(ctx, userID, postID)
+}
+
+func (s *PostGRPCService) CancelScheduledPost(ctx context.Context, userID int64, postID int64) (*pb.Post, error) {
+	return s.cancelHandler.CancelScheduledPost(ctx, userID, postID)
+}
+
+// PinPost and UnpinPost are in process only until PostService gains the RPCs and Post an
+// is_pinned field.
+func (s *PostGRPCService) PinPost(ctx context.Context, userID int64, postID int64) (*PinPostResponse, error) {
+	return s.pinHandler.PinPost(ctx, userID, postID)
+}
+
+func (s *PostGRPCService) UnpinPost(ctx context.Context, userID int64, postID int64) (*PinPostResponse, error) {
+	return s.pinHandler.UnpinPost(ctx, userID, postID)
+}
+
+// GetPostRevisions is in process only until PostService gains a GetPostRevisions RPC.
+func (s *PostGRPCService) GetPostRevisions(ctx context.Context, userID int64, postID int64, limit, offset int) (*GetPostRevisionsResponse, error) {
+	return s.revisionsHandler.GetPostRevisions(ctx, userID, postID, limit, offset)
+}
+
+// GetPostMedia is in process only until PostService gains a GetPostMedia RPC and Post a
+// has_more_media field.
+func (s *PostGRPCService) GetPostMedia(ctx context.Context, postID int64, limit, offset int) (*GetPostMediaResponse, error) {
+	return s.mediaHandler.GetPostMedia(ctx, postID, limit, offset)
+}
+
+// GetPostTags is in process only until PostService gains a GetPostTags RPC.
+func (s *PostGRPCService) GetPostTags(ctx context.Context, postID int64, includeCounts bool) ([]*model.Tag, error) {
+	return s.getPostTagsHandler.GetPostTags(ctx, postID, includeCounts)
+}
+
+// RenameTag and MergeTags are called in process by the moderation tools; PostService has no
+// such RPCs, so the handler's admin flag check is the only gate.
+func (s *PostGRPCService) RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error) {
+	return s.tagAdminHandler.RenameTag(ctx, tagID, name)
+}
+
+func (s *PostGRPCService) MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error) {
+	return s.tagAdminHandler.MergeTags(ctx, sourceIDs, destID)
+}
+
+// SuggestTags is in process only until PostService gains a SuggestTags RPC.
+func (s *PostGRPCService) SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error) {
+	return s.suggestTagsHandler.SuggestTags(ctx, prefix, limit, authorID)
+}
+
+// ExportAuthorPosts is in process only until PostService gains a streaming ExportAuthorPosts RPC.
+func (s *PostGRPCService) ExportAuthorPosts(authorID int64, stream PostExportStream) error {
+	return s.exportPostsHandler.ExportAuthorPosts(authorID, stream)
+}
+
+// GetAuthorPostCount is in process only until PostService gains a GetAuthorPostCount RPC.
+func (s *PostGRPCService) GetAuthorPostCount(ctx context.Context, authorID int64) (*GetAuthorPostCountResponse, error) {
+	return s.postCountHandler.GetAuthorPostCount(ctx, authorID)
+}
+
+// HasPostsSince is in process only until PostService gains a HasPostsSince RPC.
+func (s *PostGRPCService) HasPostsSince(ctx context.Context, tagNames []string, since *timestamppb.Timestamp) (*HasPostsSinceResponse, error) {
+	return s.tagActivityHandler.HasPostsSince(ctx, tagNames, since)
+}
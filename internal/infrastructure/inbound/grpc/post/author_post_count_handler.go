@@ -0,0 +1,57 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type AuthorPostCounter interface {
+	GetAuthorPostCount(ctx context.Context, authorID int64) (*model.AuthorPostCount, error)
+}
+
+type GetAuthorPostCountHandler struct {
+	postService AuthorPostCounter
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewGetAuthorPostCountHandler(postService AuthorPostCounter, validate *validator.Validate, log ports.Logger) *GetAuthorPostCountHandler {
+	return &GetAuthorPostCountHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type GetAuthorPostCountRequestInternal struct {
+	AuthorID int64 `validate:"required,gt=0"`
+}
+
+// GetAuthorPostCountResponse has the shape of the GetAuthorPostCount response message.
+type GetAuthorPostCountResponse struct {
+	Count  int64
+	Cached bool
+}
+
+// GetAuthorPostCount answers the "N posts" of a profile page without listing posts. It is not
+// exposed on the wire until the proto definitions gain the RPC.
+func (h *GetAuthorPostCountHandler) GetAuthorPostCount(ctx context.Context, authorID int64) (*GetAuthorPostCountResponse, error) {
+	h.log.Debug("Handling GetAuthorPostCount request", slog.Int64("author_id", authorID))
+
+	if err := h.validate.Struct(&GetAuthorPostCountRequestInternal{AuthorID: authorID}); err != nil {
+		h.log.Debug("GetAuthorPostCount validation failed", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	result, err := h.postService.GetAuthorPostCount(ctx, authorID)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to count author posts", slog.Int64("author_id", authorID))
+	}
+
+	return &GetAuthorPostCountResponse{Count: result.Count, Cached: result.Cached}, nil
+}
@@ -0,0 +1,57 @@
+package post_grpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestGetAuthorPostCountHandler_GetAuthorPostCount(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetAuthorPostCountHandler(mockPostService, validate, testLogger)
+		mockPostService.On("GetAuthorPostCount", mock.Anything, int64(5)).
+			Return(&model.AuthorPostCount{AuthorID: 5, Count: 12, Cached: true}, nil)
+
+		resp, err := handler.GetAuthorPostCount(context.Background(), 5)
+
+		require.NoError(t, err)
+		assert.Equal(t, &post_grpc.GetAuthorPostCountResponse{Count: 12, Cached: true}, resp)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetAuthorPostCountHandler(mockPostService, validate, testLogger)
+
+		_, err := handler.GetAuthorPostCount(context.Background(), 0)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "GetAuthorPostCount", mock.Anything, mock.Anything)
+	})
+
+	t.Run("InternalError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetAuthorPostCountHandler(mockPostService, validate, testLogger)
+		mockPostService.On("GetAuthorPostCount", mock.Anything, int64(5)).Return(nil, errors.New("db down"))
+
+		_, err := handler.GetAuthorPostCount(context.Background(), 5)
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
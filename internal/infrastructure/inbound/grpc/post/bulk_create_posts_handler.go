@@ -0,0 +1,120 @@
+package post_grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/post/mapper"
+)
+
+type PostBulkCreator interface {
+	BulkCreatePosts(ctx context.Context, actorID int64, posts []*model.CreatePostDTO) (*model.BulkCreateResult, error)
+}
+
+type BulkCreatePostsHandler struct {
+	postService PostBulkCreator
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewBulkCreatePostsHandler(postService PostBulkCreator, validate *validator.Validate, log ports.Logger) *BulkCreatePostsHandler {
+	return &BulkCreatePostsHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type BulkCreatePostsRequestInternal struct {
+	ActorID int64 `validate:"required,gt=0"`
+	Count   int   `validate:"gte=1"`
+}
+
+// BulkCreatePostsItem is the outcome of the post at Index in the request: the id it was
+// created with, or the status it failed with.
+type BulkCreatePostsItem struct {
+	Index  int
+	PostID int64
+	Error  *status.Status
+}
+
+type BulkCreatePostsResponse struct {
+	Items   []*BulkCreatePostsItem
+	Created int
+	Failed  int
+}
+
+// BulkCreatePosts imports up to model.MaxBulkCreatePosts posts for a content migration. A
+// post that fails validation or creation fails only its own item. It is not exposed on the
+// wire until the proto definitions gain the RPC.
+func (h *BulkCreatePostsHandler) BulkCreatePosts(ctx context.Context, actorID int64, reqs []*pb.CreatePostRequest) (*BulkCreatePostsResponse, error) {
+	h.log.Debug("Handling BulkCreatePosts request", slog.Int64("actor_id", actorID), slog.Int("count", len(reqs)))
+
+	if !isInternalAdmin(ctx) {
+		h.log.Debug("BulkCreatePosts called without admin flag", slog.Int64("actor_id", actorID))
+		return nil, notAdmin()
+	}
+	if err := h.validate.Struct(&BulkCreatePostsRequestInternal{ActorID: actorID, Count: len(reqs)}); err != nil || len(reqs) > model.MaxBulkCreatePosts {
+		h.log.Debug("BulkCreatePosts validation failed", slog.Int64("actor_id", actorID), slog.Int("count", len(reqs)))
+		return nil, invalidRequest(fmt.Sprintf("a bulk import takes 1 to %d posts", model.MaxBulkCreatePosts))
+	}
+
+	resp := &BulkCreatePostsResponse{Items: make([]*BulkCreatePostsItem, len(reqs))}
+	dtos := make([]*model.CreatePostDTO, 0, len(reqs))
+	indexes := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		resp.Items[i] = &BulkCreatePostsItem{Index: i}
+		if st := mediaTypeStatus(req.GetMedia()); st != nil {
+			resp.Items[i].Error = st
+			continue
+		}
+		if err := h.validate.Struct(createPostRequestInternal(req, "")); err != nil {
+			resp.Items[i].Error = errinfo.New(codes.InvalidArgument, errinfo.ReasonInvalidArgument, "invalid request")
+			continue
+		}
+		dtos = append(dtos, mapper.CreatePostRequestToDTO(req))
+		indexes = append(indexes, i)
+	}
+
+	if len(dtos) > 0 {
+		result, err := h.postService.BulkCreatePosts(ctx, actorID, dtos)
+		if err != nil {
+			return nil, serviceErrorStatus(h.log, err, "Failed to bulk create posts", slog.Int64("actor_id", actorID))
+		}
+		for _, outcome := range result.Items {
+			item := resp.Items[indexes[outcome.Index]]
+			if outcome.Err != nil {
+				item.Error = h.bulkItemStatus(outcome.Err)
+				continue
+			}
+			item.PostID = outcome.Post.ID
+		}
+	}
+
+	for _, item := range resp.Items {
+		if item.Error != nil {
+			resp.Failed++
+		} else {
+			resp.Created++
+		}
+	}
+	h.log.Info("Bulk import handled",
+		slog.Int64("actor_id", actorID),
+		slog.Int("created", resp.Created),
+		slog.Int("failed", resp.Failed))
+	return resp, nil
+}
+
+// bulkItemStatus maps the error of one post as CreatePost maps the error of its post.
+func (h *BulkCreatePostsHandler) bulkItemStatus(err error) *status.Status {
+	return status.Convert(serviceErrorStatus(h.log, err, "Failed to bulk create a post"))
+}
@@ -0,0 +1,108 @@
+package post_grpc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestBulkCreatePostsHandler_BulkCreatePosts(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	request := func(i int) *pb.CreatePostRequest {
+		return &pb.CreatePostRequest{AuthorId: 3, Title: fmt.Sprintf("Imported %d", i), Content: "Content carried over from the old site"}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewBulkCreatePostsHandler(mockPostService, validate, testLogger)
+
+		reqs := []*pb.CreatePostRequest{request(0), {AuthorId: 0, Title: "No author", Content: "Content carried over from the old site"}, request(2), request(3)}
+		hasThreePosts := mock.MatchedBy(func(posts []*model.CreatePostDTO) bool {
+			return len(posts) == 3 && posts[0].Title == "Imported 0" && posts[2].Title == "Imported 3"
+		})
+		mockPostService.On("BulkCreatePosts", mock.Anything, int64(99), hasThreePosts).Return(&model.BulkCreateResult{
+			Items: []*model.BulkCreateItem{
+				{Index: 0, Post: &model.Post{ID: 10}},
+				{Index: 1, Err: custom_errors.ErrUserNotFound},
+				{Index: 2, Post: &model.Post{ID: 11}},
+			},
+			Created: 2,
+			Failed:  1,
+		}, nil)
+
+		resp, err := handler.BulkCreatePosts(adminContext(), 99, reqs)
+
+		require.NoError(t, err)
+		require.Len(t, resp.Items, 4)
+		assert.Equal(t, 2, resp.Created)
+		assert.Equal(t, 2, resp.Failed)
+		assert.Equal(t, int64(10), resp.Items[0].PostID)
+		assert.Nil(t, resp.Items[0].Error)
+		assert.Equal(t, codes.InvalidArgument, resp.Items[1].Error.Code(), "a post that fails validation fails only itself")
+		assert.Equal(t, codes.NotFound, resp.Items[2].Error.Code())
+		assert.Equal(t, int64(11), resp.Items[3].PostID)
+		for i, item := range resp.Items {
+			assert.Equal(t, i, item.Index)
+		}
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("MissingAdminFlag", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewBulkCreatePostsHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.BulkCreatePosts(context.Background(), 99, []*pb.CreatePostRequest{request(0)})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, errinfo.ReasonNotAdmin, errinfo.Reason(err))
+		mockPostService.AssertNotCalled(t, "BulkCreatePosts", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidCount", func(t *testing.T) {
+		tooMany := make([]*pb.CreatePostRequest, model.MaxBulkCreatePosts+1)
+		for i := range tooMany {
+			tooMany[i] = request(i)
+		}
+		for _, reqs := range [][]*pb.CreatePostRequest{nil, tooMany} {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewBulkCreatePostsHandler(mockPostService, validate, testLogger)
+
+			resp, err := handler.BulkCreatePosts(adminContext(), 99, reqs)
+
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), "%d posts", len(reqs))
+			mockPostService.AssertNotCalled(t, "BulkCreatePosts", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewBulkCreatePostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("BulkCreatePosts", mock.Anything, int64(99), mock.Anything).
+			Return(nil, &model.RateLimitExceededError{Operation: "bulk_create_posts", RetryAfter: 30 * time.Second})
+
+		resp, err := handler.BulkCreatePosts(adminContext(), 99, []*pb.CreatePostRequest{request(0)})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
@@ -0,0 +1,62 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+)
+
+// cacheDebugMetadataKey asks GetPost and ListPosts to report how the cache served them in the
+// response trailers. Requests without it get no trailers.
+const cacheDebugMetadataKey = "x-debug-cache"
+
+// Trailers set for a request that carries cacheDebugMetadataKey. Durations are in
+// milliseconds; the age is left out for an entry that does not record its write time.
+const (
+	cacheHitTrailerKey    = "x-cache-hit"
+	cacheSourceTrailerKey = "x-cache-source"
+	cacheAgeTrailerKey    = "x-cache-age-ms"
+	cacheTTLTrailerKey    = "x-cache-ttl-ms"
+)
+
+// withCacheDebug returns ctx ready to record how the read is served when the request asked
+// for it, and nil otherwise.
+func withCacheDebug(ctx context.Context) (context.Context, *model.CacheInfo) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(cacheDebugMetadataKey)) == 0 {
+		return ctx, nil
+	}
+	return model.WithCacheInfo(ctx)
+}
+
+// setCacheDebugTrailer reports info in the response trailers. Nothing is set when the request
+// did not ask, or when no cache took part in the read.
+func setCacheDebugTrailer(ctx context.Context, log ports.Logger, info *model.CacheInfo) {
+	if info == nil || info.Source == "" {
+		return
+	}
+	trailer := metadata.Pairs(
+		cacheHitTrailerKey, strconv.FormatBool(info.Hit),
+		cacheSourceTrailerKey, string(info.Source),
+	)
+	if info.Source == model.CacheSourceCache {
+		if info.Age > 0 {
+			trailer.Set(cacheAgeTrailerKey, formatMillis(info.Age))
+		}
+		trailer.Set(cacheTTLTrailerKey, formatMillis(info.TTL))
+	}
+	if err := grpc.SetTrailer(ctx, trailer); err != nil {
+		log.Debug("Failed to set cache debug trailer", slog.String("error", err.Error()))
+	}
+}
+
+func formatMillis(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
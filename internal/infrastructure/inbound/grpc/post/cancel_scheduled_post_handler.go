@@ -0,0 +1,68 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+
+	"github.com/go-playground/validator/v10"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type ScheduledPostCanceller interface {
+	CancelScheduledPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error)
+}
+
+type CancelScheduledPostHandler struct {
+	postService ScheduledPostCanceller
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewCancelScheduledPostHandler(postService ScheduledPostCanceller, validate *validator.Validate, log ports.Logger) *CancelScheduledPostHandler {
+	return &CancelScheduledPostHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type CancelScheduledPostRequestInternal struct {
+	PostID int64 `validate:"required,gt=0"`
+	UserID int64 `validate:"required,gt=0"`
+}
+
+// CancelScheduledPost turns a scheduled post back into a draft. It is not exposed on the wire
+// until the proto definitions gain the RPC.
+func (h *CancelScheduledPostHandler) CancelScheduledPost(ctx context.Context, userID int64, postID int64) (*pb.Post, error) {
+	h.log.Debug("Handling CancelScheduledPost request", slog.Int64("post_id", postID), slog.Int64("user_id", userID))
+
+	validationReq := &CancelScheduledPostRequestInternal{
+		PostID: postID,
+		UserID: userID,
+	}
+
+	if err := h.validate.Struct(validationReq); err != nil {
+		h.log.Debug("CancelScheduledPost validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	draft, err := h.postService.CancelScheduledPost(ctx, userID, postID)
+	if err != nil {
+		// The service refuses a post that is not scheduled with ErrInvalidInput.
+		return nil, serviceErrorStatus(h.log, failedPrecondition(err, custom_errors.ErrInvalidInput), "Failed to cancel scheduled post",
+			slog.Int64("post_id", postID), slog.Int64("user_id", userID))
+	}
+
+	resp, err := postResponse(h.log, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	h.log.Debug("Scheduled post cancelled successfully", slog.Int64("post_id", resp.Id))
+	return resp, nil
+}
@@ -0,0 +1,112 @@
+package post_grpc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestCancelScheduledPostHandler_CancelScheduledPost(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewCancelScheduledPostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("CancelScheduledPost", mock.Anything, int64(1), int64(7)).
+			Return(&model.PostDetailed{Post: &model.Post{ID: 7, AuthorID: 1, Title: "Title", Status: model.PostStatusDraft}}, nil)
+
+		resp, err := handler.CancelScheduledPost(context.Background(), 1, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), resp.Id)
+		mockPostService.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name       string
+		postID     int64
+		err        error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{name: "invalid post id", postID: 0, wantCode: codes.InvalidArgument, wantReason: errinfo.ReasonInvalidArgument},
+		{name: "not found", postID: 7, err: custom_errors.ErrPostNotFound, wantCode: codes.NotFound, wantReason: errinfo.ReasonPostNotFound},
+		{name: "not the author", postID: 7, err: custom_errors.ErrForbidden, wantCode: codes.PermissionDenied, wantReason: errinfo.ReasonNotAuthor},
+		{name: "not scheduled", postID: 7, err: fmt.Errorf("%w: post 7 is not scheduled", custom_errors.ErrInvalidInput), wantCode: codes.FailedPrecondition, wantReason: errinfo.ReasonFailedPrecondition},
+		{name: "database error", postID: 7, err: custom_errors.ErrDatabaseQuery, wantCode: codes.Internal, wantReason: errinfo.ReasonDatabaseError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewCancelScheduledPostHandler(mockPostService, validate, testLogger)
+			mockPostService.On("CancelScheduledPost", mock.Anything, int64(1), tt.postID).Return(nil, tt.err)
+
+			_, err := handler.CancelScheduledPost(context.Background(), 1, tt.postID)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantReason, errinfo.Reason(err))
+		})
+	}
+}
+
+func TestCreatePostHandler_CreateScheduledPost(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	req := &pb.CreatePostRequest{AuthorId: 1, Title: "Scheduled post", Content: "This is a test post content with enough length"}
+	at := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("PassesTheScheduleToTheService", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewCreatePostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("CreatePost", mock.Anything, mock.MatchedBy(func(dto *model.CreatePostDTO) bool {
+			return dto.ScheduledAt != nil && dto.ScheduledAt.Equal(at)
+		})).Return(&model.PostDetailed{Post: &model.Post{ID: 3, AuthorID: 1, Status: model.PostStatusScheduled}}, nil)
+
+		resp, err := handler.CreateScheduledPost(context.Background(), req, timestamppb.New(at))
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), resp.Id)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("MissingTime", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewCreatePostHandler(mockPostService, validate, testLogger)
+
+		_, err := handler.CreateScheduledPost(context.Background(), req, nil)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "CreatePost", mock.Anything, mock.Anything)
+	})
+
+	t.Run("TimeInThePast", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewCreatePostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("CreatePost", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: scheduled_at must be in the future", custom_errors.ErrInvalidInput))
+
+		_, err := handler.CreateScheduledPost(context.Background(), req, timestamppb.New(at))
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
+		assert.Contains(t, status.Convert(err).Message(), "scheduled_at must be in the future")
+	})
+}
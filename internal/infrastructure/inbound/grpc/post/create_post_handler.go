@@ -5,17 +5,20 @@ import (
 	"log/slog"
 	ports "pinstack-post-service/internal/domain/ports/output"
 
-	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
-
 	"github.com/go-playground/validator/v10"
 	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/post/mapper"
 )
 
+// failedTagsMetadataKey is the response header listing requested tags the post was created
+// without. pb.Post has no warnings field in proto v0.1.22, so the header carries them.
+const failedTagsMetadataKey = "x-failed-tags"
+
 type PostCreator interface {
 	CreatePost(ctx context.Context, post *model.CreatePostDTO) (*model.PostDetailed, error)
 }
@@ -35,28 +38,24 @@ func NewCreatePostHandler(postService PostCreator, validate *validator.Validate,
 	}
 }
 
+// CreatePostRequestInternal checks the shape of a create request. Title and content are
+// optional; the service rejects a post with neither of them nor media.
 type CreatePostRequestInternal struct {
 	AuthorID int64                 `validate:"required"`
-	Title    string                `validate:"required,min=3,max=255"`
-	Content  string                `validate:"required,min=10"`
+	Title    string                `validate:"omitempty,min=3,max=255"`
+	Content  string                `validate:"omitempty,min=10"`
 	Tags     []string              `validate:"omitempty,dive,min=2,max=50"`
 	Media    []*MediaInputInternal `validate:"omitempty,max=9,dive"`
+	Language string                `validate:"omitempty,bcp47_language_tag"`
 }
 
 type MediaInputInternal struct {
 	URL      string `validate:"required,url"`
-	Type     string `validate:"required,oneof=image video"`
+	Type     string `validate:"required"`
 	Position int32  `validate:"gte=1,lte=9"`
 }
 
-func (h *CreatePostHandler) CreatePost(ctx context.Context, req *pb.CreatePostRequest) (*pb.Post, error) {
-	h.log.Debug("Received CreatePost request",
-		slog.Int64("author_id", req.GetAuthorId()),
-		slog.String("title", req.GetTitle()),
-		slog.Bool("has_content", req.Content != ""),
-		slog.Int("media_items_count", len(req.GetMedia())),
-		slog.Int("tags_count", len(req.GetTags())))
-
+func createPostRequestInternal(req *pb.CreatePostRequest, language string) *CreatePostRequestInternal {
 	internalMedia := make([]*MediaInputInternal, len(req.GetMedia()))
 	for i, m := range req.GetMedia() {
 		internalMedia[i] = &MediaInputInternal{
@@ -65,133 +64,131 @@ func (h *CreatePostHandler) CreatePost(ctx context.Context, req *pb.CreatePostRe
 			Position: m.GetPosition(),
 		}
 	}
-
-	validationReq := &CreatePostRequestInternal{
+	return &CreatePostRequestInternal{
 		AuthorID: req.GetAuthorId(),
 		Title:    req.GetTitle(),
 		Content:  req.GetContent(),
 		Tags:     req.GetTags(),
 		Media:    internalMedia,
+		Language: language,
 	}
+}
 
-	if err := h.validate.Struct(validationReq); err != nil {
-		h.log.Debug("Request validation failed",
-			slog.Int64("author_id", req.GetAuthorId()),
-			slog.String("error", err.Error()))
-		return nil, status.Error(codes.InvalidArgument, "invalid request")
+func (h *CreatePostHandler) CreatePost(ctx context.Context, req *pb.CreatePostRequest) (*pb.Post, error) {
+	return h.createPost(ctx, req, nil, "", "", "")
+}
+
+// CreateScheduledPost is CreatePost for a post that the scheduler publishes at scheduledAt.
+// CreatePostRequest has no schedule field in proto v0.1.22, so this is not exposed over gRPC yet.
+func (h *CreatePostHandler) CreateScheduledPost(
+	ctx context.Context,
+	req *pb.CreatePostRequest,
+	scheduledAt *timestamppb.Timestamp,
+) (*pb.Post, error) {
+	if scheduledAt == nil || scheduledAt.CheckValid() != nil {
+		h.log.Debug("Invalid scheduled_at", slog.Int64("author_id", req.GetAuthorId()))
+		return nil, invalidRequest("invalid scheduled_at")
 	}
+	return h.createPost(ctx, req, scheduledAt, "", "", "")
+}
 
-	dtoMediaItems := make([]*model.PostMediaInput, 0, len(req.GetMedia()))
-	for i, m := range req.GetMedia() {
-		position := m.GetPosition()
-		if position < MinMediaPosition || position > MaxMediaPosition {
-			h.log.Debug("Invalid media position, adjusting",
-				slog.Int("original_position", int(position)),
-				slog.Int("index", i),
-				slog.String("url", m.GetUrl()))
-
-			position = int32(i + 1)
-
-			if position > MaxMediaPosition {
-				h.log.Debug("Skipping media item due to position constraints",
-					slog.Int("adjusted_position", int(position)),
-					slog.Int("max_allowed", MaxMediaPosition),
-					slog.String("url", m.GetUrl()))
-				continue
-			}
-
-			h.log.Debug("Media position adjusted",
-				slog.Int("new_position", int(position)),
-				slog.String("url", m.GetUrl()))
-		}
-		dtoMediaItems = append(dtoMediaItems, &model.PostMediaInput{
-			URL:      m.GetUrl(),
-			Type:     model.MediaType(m.GetType()),
-			Position: position,
-		})
+// CreatePostWithVisibility is CreatePost for a post that is unlisted or private from the
+// start. CreatePostRequest has no visibility field in proto v0.1.22, so this is not exposed
+// over gRPC yet.
+func (h *CreatePostHandler) CreatePostWithVisibility(ctx context.Context, req *pb.CreatePostRequest, visibility string) (*pb.Post, error) {
+	if err := model.PostVisibility(visibility).IsValid(); err != nil {
+		h.log.Debug("Invalid visibility", slog.Int64("author_id", req.GetAuthorId()), slog.String("visibility", visibility))
+		return nil, invalidRequest(err.Error())
 	}
+	return h.createPost(ctx, req, nil, model.PostVisibility(visibility), "", "")
+}
+
+// CreatePostWithLanguage is CreatePost for a post written in language, a BCP-47 tag the
+// server allows. CreatePostRequest has no language field in proto v0.1.22, so this is not
+// exposed over gRPC yet.
+func (h *CreatePostHandler) CreatePostWithLanguage(ctx context.Context, req *pb.CreatePostRequest, language string) (*pb.Post, error) {
+	return h.createPost(ctx, req, nil, "", language, "")
+}
+
+// CreatePostWithSource is CreatePost for a post created from source, the client or
+// application sending it ("web", "ios", "android", "import", ...). A source the server does not
+// allow is recorded as "unknown" rather than rejected. CreatePostRequest has no source field
+// in proto v0.1.22, so over gRPC the source comes from the x-client-source metadata instead.
+func (h *CreatePostHandler) CreatePostWithSource(ctx context.Context, req *pb.CreatePostRequest, source string) (*pb.Post, error) {
+	return h.createPost(ctx, req, nil, "", "", source)
+}
 
-	postDTO := &model.CreatePostDTO{
-		AuthorID:   req.GetAuthorId(),
-		Title:      req.GetTitle(),
-		Content:    &req.Content,
-		Tags:       req.GetTags(),
-		MediaItems: dtoMediaItems,
+func (h *CreatePostHandler) createPost(
+	ctx context.Context,
+	req *pb.CreatePostRequest,
+	scheduledAt *timestamppb.Timestamp,
+	visibility model.PostVisibility,
+	language string,
+	source string,
+) (*pb.Post, error) {
+	if source == "" {
+		source = clientSourceFromContext(ctx)
 	}
+	h.log.Debug("Received CreatePost request",
+		slog.Int64("author_id", req.GetAuthorId()),
+		slog.String("title", req.GetTitle()),
+		slog.Bool("scheduled", scheduledAt != nil),
+		slog.String("visibility", string(visibility)),
+		slog.String("language", language),
+		slog.String("source", source),
+		slog.Bool("has_content", req.Content != ""),
+		slog.Int("media_items_count", len(req.GetMedia())),
+		slog.Int("tags_count", len(req.GetTags())))
 
-	createdPostModel, err := h.postService.CreatePost(ctx, postDTO)
-	if err != nil {
-		h.log.Debug("Error creating post",
+	if st := mediaTypeStatus(req.GetMedia()); st != nil {
+		h.log.Debug("Invalid media type", slog.Int64("author_id", req.GetAuthorId()), slog.String("error", st.Message()))
+		return nil, st.Err()
+	}
+	if err := h.validate.Struct(createPostRequestInternal(req, language)); err != nil {
+		h.log.Debug("Request validation failed",
 			slog.Int64("author_id", req.GetAuthorId()),
 			slog.String("error", err.Error()))
-
-		switch err {
-		case custom_errors.ErrPostValidation:
-			return nil, status.Error(codes.InvalidArgument, "validation failed")
-		default:
-			h.log.Error("Unexpected error creating post",
-				slog.Int64("author_id", req.GetAuthorId()),
-				slog.String("error", err.Error()))
-			return nil, status.Error(codes.Internal, "internal service error")
-		}
+		return nil, invalidRequest("invalid request")
 	}
 
-	pbMedia := make([]*pb.Media, len(createdPostModel.Media))
-	for i, m := range createdPostModel.Media {
-		var mediaCreatedAtPb *timestamppb.Timestamp
-		if m.CreatedAt.Valid {
-			mediaCreatedAtPb = timestamppb.New(m.CreatedAt.Time)
-		}
-		pbMedia[i] = &pb.Media{
-			Id:        m.ID,
-			Url:       m.URL,
-			Type:      string(m.Type),
-			Position:  m.Position,
-			CreatedAt: mediaCreatedAtPb,
-		}
+	postDTO := mapper.CreatePostRequestToDTO(req)
+	if dropped := len(req.GetMedia()) - len(postDTO.MediaItems); dropped > 0 {
+		h.log.Debug("Dropped media items past the last position",
+			slog.Int("dropped", dropped),
+			slog.Int("max_position", mapper.MaxMediaPosition))
+	}
+	if scheduledAt != nil {
+		at := scheduledAt.AsTime()
+		postDTO.ScheduledAt = &at
 	}
+	postDTO.Visibility = visibility
+	postDTO.Language = language
+	postDTO.Source = model.PostSource(source)
 
-	var postID int64
-	var authorID int64
-	var title string
-	var content string
-	var createdAtPb *timestamppb.Timestamp
-	var updatedAtPb *timestamppb.Timestamp
-
-	if createdPostModel.Post != nil {
-		postID = createdPostModel.Post.ID
-		authorID = createdPostModel.Post.AuthorID
-		title = createdPostModel.Post.Title
-		content = *createdPostModel.Post.Content
-		if createdPostModel.Post.CreatedAt.Valid {
-			createdAtPb = timestamppb.New(createdPostModel.Post.CreatedAt.Time)
-		}
-		if createdPostModel.Post.UpdatedAt.Valid {
-			updatedAtPb = timestamppb.New(createdPostModel.Post.UpdatedAt.Time)
-		}
+	createdPostModel, err := h.postService.CreatePost(ctx, postDTO)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to create post", slog.Int64("author_id", req.GetAuthorId()))
 	}
 
-	pbTags := make([]string, len(createdPostModel.Tags))
-	for i, t := range createdPostModel.Tags {
-		pbTags[i] = t.Name
+	resp, err := postResponse(h.log, createdPostModel)
+	if err != nil {
+		return nil, err
 	}
 
-	resp := &pb.Post{
-		Id:        postID,
-		AuthorId:  authorID,
-		Title:     title,
-		Content:   content,
-		Tags:      pbTags,
-		Media:     pbMedia,
-		CreatedAt: createdAtPb,
-		UpdatedAt: updatedAtPb,
+	if len(createdPostModel.FailedTags) > 0 {
+		h.log.Warn("Post created without some tags",
+			slog.Int64("post_id", resp.Id),
+			slog.Any("failed_tags", createdPostModel.FailedTags))
+		if err := grpc.SetHeader(ctx, metadata.MD{failedTagsMetadataKey: createdPostModel.FailedTags}); err != nil {
+			h.log.Debug("Failed to set failed tags header", slog.String("error", err.Error()))
+		}
 	}
 
 	h.log.Debug("Post created successfully",
-		slog.Int64("post_id", postID),
-		slog.Int64("author_id", authorID),
-		slog.Int("tags_count", len(pbTags)),
-		slog.Int("media_count", len(pbMedia)))
+		slog.Int64("post_id", resp.Id),
+		slog.Int64("author_id", resp.AuthorId),
+		slog.Int("tags_count", len(resp.Tags)),
+		slog.Int("media_count", len(resp.Media)))
 
 	return resp, nil
 }
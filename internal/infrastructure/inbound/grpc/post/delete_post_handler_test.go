@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
 	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
 	"pinstack-post-service/internal/infrastructure/logger"
 	mockpost "pinstack-post-service/mocks/post"
@@ -59,7 +60,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
-		assert.Equal(t, "invalid request", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
 
 		mockPostService.AssertNotCalled(t, "DeletePost")
 	})
@@ -84,7 +85,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.NotFound, statusErr.Code())
-		assert.Equal(t, "post not found", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonPostNotFound, errinfo.Reason(err))
 		mockPostService.AssertExpectations(t)
 	})
 
@@ -108,7 +109,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
-		assert.Equal(t, "validation failed", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonValidationFailed, errinfo.Reason(err))
 		mockPostService.AssertExpectations(t)
 	})
 
@@ -132,7 +133,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.PermissionDenied, statusErr.Code())
-		assert.Equal(t, "user is not the author", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonNotAuthor, errinfo.Reason(err))
 		mockPostService.AssertExpectations(t)
 	})
 
@@ -156,7 +157,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Equal(t, "failed to query media", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonMediaQueryFailed, errinfo.Reason(err))
 		mockPostService.AssertExpectations(t)
 	})
 
@@ -180,7 +181,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Equal(t, "failed to detach media", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonMediaDetachFailed, errinfo.Reason(err))
 		mockPostService.AssertExpectations(t)
 	})
 
@@ -204,7 +205,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Equal(t, "failed to query tags", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonTagQueryFailed, errinfo.Reason(err))
 		mockPostService.AssertExpectations(t)
 	})
 
@@ -228,7 +229,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Equal(t, "failed to remove tags", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonTagDeleteFailed, errinfo.Reason(err))
 		mockPostService.AssertExpectations(t)
 	})
 
@@ -252,7 +253,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Equal(t, "database error", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonDatabaseError, errinfo.Reason(err))
 		mockPostService.AssertExpectations(t)
 	})
 
@@ -276,7 +277,7 @@ func TestDeletePostHandler_DeletePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Equal(t, "failed to delete post", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonInternal, errinfo.Reason(err))
 		mockPostService.AssertExpectations(t)
 	})
 }
@@ -0,0 +1,199 @@
+package post_grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// serviceError is how an error the service returns is reported. The status message is the
+// error's own text when verbose, since it tells the client what was wrong with the request,
+// and the sentinel's text otherwise, so nothing internal leaks. details adds details before
+// the ErrorInfo and metadata fills its metadata.
+type serviceError struct {
+	err      error
+	code     codes.Code
+	reason   string
+	verbose  bool
+	details  func(err error) []protoadapt.MessageV1
+	metadata func(err error) map[string]string
+}
+
+// serviceErrors maps every error the service returns to its status. The first entry err
+// matches wins: the caller's own deadline comes before a query timeout, and a specific
+// failure before the database error it wraps. An error matching none is Internal.
+var serviceErrors = []serviceError{
+	{err: model.ErrDeadlineExceeded, code: codes.DeadlineExceeded, reason: errinfo.ReasonDeadlineExceeded},
+	{err: model.ErrCanceled, code: codes.Canceled, reason: errinfo.ReasonCanceled},
+	{err: model.ErrTimeout, code: codes.DeadlineExceeded, reason: errinfo.ReasonTimeout},
+	// A stream stopped by its client.
+	{err: context.DeadlineExceeded, code: codes.DeadlineExceeded, reason: errinfo.ReasonDeadlineExceeded},
+	{err: context.Canceled, code: codes.Canceled, reason: errinfo.ReasonCanceled},
+	{err: custom_errors.ErrRateLimitExceeded, code: codes.ResourceExhausted, reason: errinfo.ReasonRateLimited, details: retryInfo},
+	{err: model.ErrVersionConflict, code: codes.Aborted, reason: errinfo.ReasonVersionConflict, metadata: versionConflictMetadata},
+
+	{err: errFailedPrecondition, code: codes.FailedPrecondition, reason: errinfo.ReasonFailedPrecondition, verbose: true},
+	{err: custom_errors.ErrPostValidation, code: codes.InvalidArgument, reason: errinfo.ReasonValidationFailed, details: fieldViolations},
+	{err: model.ErrEmptyUpdate, code: codes.InvalidArgument, reason: errinfo.ReasonEmptyUpdate},
+	{err: model.ErrMediaDuplicate, code: codes.InvalidArgument, reason: errinfo.ReasonMediaDuplicate},
+	{err: custom_errors.ErrInvalidInput, code: codes.InvalidArgument, reason: errinfo.ReasonInvalidArgument, verbose: true},
+	{err: custom_errors.ErrPostNotFound, code: codes.NotFound, reason: errinfo.ReasonPostNotFound},
+	{err: custom_errors.ErrTagNotFound, code: codes.NotFound, reason: errinfo.ReasonTagNotFound},
+	{err: custom_errors.ErrUserNotFound, code: codes.NotFound, reason: errinfo.ReasonUserNotFound},
+	{err: custom_errors.ErrTagAlreadyExists, code: codes.Aborted, reason: errinfo.ReasonTagAlreadyExists},
+	{err: custom_errors.ErrForbidden, code: codes.PermissionDenied, reason: errinfo.ReasonNotAuthor},
+
+	{err: custom_errors.ErrExternalServiceError, code: codes.Unavailable, reason: errinfo.ReasonDependencyUnavailable},
+	{err: custom_errors.ErrMediaQueryFailed, code: codes.Internal, reason: errinfo.ReasonMediaQueryFailed},
+	{err: custom_errors.ErrMediaBatchQueryFailed, code: codes.Internal, reason: errinfo.ReasonMediaQueryFailed},
+	{err: custom_errors.ErrMediaAttachFailed, code: codes.Internal, reason: errinfo.ReasonMediaAttachFailed},
+	{err: custom_errors.ErrMediaDetachFailed, code: codes.Internal, reason: errinfo.ReasonMediaDetachFailed},
+	{err: custom_errors.ErrMediaReorderFailed, code: codes.Internal, reason: errinfo.ReasonMediaReorderFailed},
+	{err: custom_errors.ErrTagQueryFailed, code: codes.Internal, reason: errinfo.ReasonTagQueryFailed},
+	{err: custom_errors.ErrTagScanFailed, code: codes.Internal, reason: errinfo.ReasonTagQueryFailed},
+	{err: custom_errors.ErrTagCreateFailed, code: codes.Internal, reason: errinfo.ReasonTagCreateFailed},
+	{err: custom_errors.ErrTagInsertFailed, code: codes.Internal, reason: errinfo.ReasonTagCreateFailed},
+	{err: custom_errors.ErrTagDeleteFailed, code: codes.Internal, reason: errinfo.ReasonTagDeleteFailed},
+	{err: custom_errors.ErrTagUntagFailed, code: codes.Internal, reason: errinfo.ReasonTagDeleteFailed},
+	{err: custom_errors.ErrTagPost, code: codes.Internal, reason: errinfo.ReasonTagPostFailed},
+	{err: custom_errors.ErrTagVerifyPostFailed, code: codes.Internal, reason: errinfo.ReasonTagPostFailed},
+	{err: custom_errors.ErrUnknownTagError, code: codes.Internal, reason: errinfo.ReasonTagPostFailed},
+	{err: custom_errors.ErrDatabaseQuery, code: codes.Internal, reason: errinfo.ReasonDatabaseError},
+	{err: custom_errors.ErrDatabaseConnection, code: codes.Internal, reason: errinfo.ReasonDatabaseError},
+	{err: custom_errors.ErrDatabaseTransaction, code: codes.Internal, reason: errinfo.ReasonDatabaseError},
+}
+
+// errFailedPrecondition marks the errors a handler reports as FailedPrecondition; see
+// failedPrecondition.
+var errFailedPrecondition = errors.New("failed precondition")
+
+// preconditionError is an error of the service that means the post is not in a state that
+// allows the operation. It reads as the error it wraps.
+type preconditionError struct {
+	err error
+}
+
+func (e *preconditionError) Error() string   { return e.err.Error() }
+func (e *preconditionError) Unwrap() []error { return []error{errFailedPrecondition, e.err} }
+
+// failedPrecondition marks err as a FailedPrecondition when it is one of targets, for
+// operations where those errors mean the post is in the wrong state rather than the request
+// being malformed.
+func failedPrecondition(err error, targets ...error) error {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return &preconditionError{err: err}
+		}
+	}
+	return err
+}
+
+// serviceErrorStatus reports err, returned by the service, as a status (see serviceErrors).
+// A failure of the service is logged with msg and args at error level; a rejected request
+// only at debug level.
+func serviceErrorStatus(log ports.Logger, err error, msg string, args ...any) error {
+	st := serviceErrorToStatus(err)
+	args = append(args, slog.String("error", err.Error()))
+	if isServerError(st.Code()) {
+		log.Error(msg, args...)
+	} else {
+		log.Debug(msg, args...)
+	}
+	return st.Err()
+}
+
+func serviceErrorToStatus(err error) *status.Status {
+	for _, e := range serviceErrors {
+		if !errors.Is(err, e.err) {
+			continue
+		}
+		msg := e.err.Error()
+		if e.verbose {
+			msg = err.Error()
+		}
+		info := &errdetails.ErrorInfo{Reason: e.reason}
+		if e.metadata != nil {
+			info.Metadata = e.metadata(err)
+		}
+		var details []protoadapt.MessageV1
+		if e.details != nil {
+			details = e.details(err)
+		}
+		return errinfo.WithDetails(e.code, msg, info, details...)
+	}
+	return errinfo.New(codes.Internal, errinfo.ReasonInternal, custom_errors.ErrInternalServiceError.Error())
+}
+
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// invalidRequest reports a request that failed validation in the handler.
+func invalidRequest(msg string) error {
+	return errinfo.Error(codes.InvalidArgument, errinfo.ReasonInvalidArgument, msg)
+}
+
+// notAdmin reports a call to an admin operation without the internal admin flag.
+func notAdmin() error {
+	return errinfo.Error(codes.PermissionDenied, errinfo.ReasonNotAdmin, custom_errors.ErrForbidden.Error())
+}
+
+// internalError reports a failure of the handler itself, logged by the caller.
+func internalError() error {
+	return errinfo.Error(codes.Internal, errinfo.ReasonInternal, custom_errors.ErrInternalServiceError.Error())
+}
+
+// retryInfo tells a rate-limited client when to try again.
+func retryInfo(err error) []protoadapt.MessageV1 {
+	var limitErr *model.RateLimitExceededError
+	if !errors.As(err, &limitErr) {
+		return nil
+	}
+	return []protoadapt.MessageV1{&errdetails.RetryInfo{RetryDelay: durationpb.New(limitErr.RetryAfter)}}
+}
+
+// fieldViolations lists the invalid fields of a post as BadRequest field violations.
+func fieldViolations(err error) []protoadapt.MessageV1 {
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range validationErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	return []protoadapt.MessageV1{badRequest}
+}
+
+// versionConflictMetadata carries the version the post is at, so the client can re-read and
+// retry.
+func versionConflictMetadata(err error) map[string]string {
+	var conflictErr *model.VersionConflictError
+	if !errors.As(err, &conflictErr) {
+		return nil
+	}
+	return map[string]string{
+		"post_id":         strconv.FormatInt(conflictErr.PostID, 10),
+		"current_version": strconv.FormatInt(conflictErr.CurrentVersion, 10),
+	}
+}
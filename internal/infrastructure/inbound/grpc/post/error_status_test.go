@@ -0,0 +1,211 @@
+package post_grpc
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+)
+
+// reasonCatalog is every error the service can return and the status it is reported as,
+// keyed by the sentinel's name in its package.
+var reasonCatalog = []struct {
+	name   string
+	err    error
+	code   codes.Code
+	reason string
+}{
+	{"model.ErrDeadlineExceeded", model.ErrDeadlineExceeded, codes.DeadlineExceeded, errinfo.ReasonDeadlineExceeded},
+	{"model.ErrCanceled", model.ErrCanceled, codes.Canceled, errinfo.ReasonCanceled},
+	{"model.ErrTimeout", model.ErrTimeout, codes.DeadlineExceeded, errinfo.ReasonTimeout},
+	{"model.ErrVersionConflict", model.ErrVersionConflict, codes.Aborted, errinfo.ReasonVersionConflict},
+	{"model.ErrEmptyUpdate", model.ErrEmptyUpdate, codes.InvalidArgument, errinfo.ReasonEmptyUpdate},
+	{"model.ErrMediaDuplicate", model.ErrMediaDuplicate, codes.InvalidArgument, errinfo.ReasonMediaDuplicate},
+	{"custom_errors.ErrRateLimitExceeded", custom_errors.ErrRateLimitExceeded, codes.ResourceExhausted, errinfo.ReasonRateLimited},
+	{"custom_errors.ErrPostValidation", custom_errors.ErrPostValidation, codes.InvalidArgument, errinfo.ReasonValidationFailed},
+	{"custom_errors.ErrInvalidInput", custom_errors.ErrInvalidInput, codes.InvalidArgument, errinfo.ReasonInvalidArgument},
+	{"custom_errors.ErrPostNotFound", custom_errors.ErrPostNotFound, codes.NotFound, errinfo.ReasonPostNotFound},
+	{"custom_errors.ErrTagNotFound", custom_errors.ErrTagNotFound, codes.NotFound, errinfo.ReasonTagNotFound},
+	{"custom_errors.ErrUserNotFound", custom_errors.ErrUserNotFound, codes.NotFound, errinfo.ReasonUserNotFound},
+	{"custom_errors.ErrTagAlreadyExists", custom_errors.ErrTagAlreadyExists, codes.Aborted, errinfo.ReasonTagAlreadyExists},
+	{"custom_errors.ErrForbidden", custom_errors.ErrForbidden, codes.PermissionDenied, errinfo.ReasonNotAuthor},
+	{"custom_errors.ErrExternalServiceError", custom_errors.ErrExternalServiceError, codes.Unavailable, errinfo.ReasonDependencyUnavailable},
+	{"custom_errors.ErrMediaQueryFailed", custom_errors.ErrMediaQueryFailed, codes.Internal, errinfo.ReasonMediaQueryFailed},
+	{"custom_errors.ErrMediaBatchQueryFailed", custom_errors.ErrMediaBatchQueryFailed, codes.Internal, errinfo.ReasonMediaQueryFailed},
+	{"custom_errors.ErrMediaAttachFailed", custom_errors.ErrMediaAttachFailed, codes.Internal, errinfo.ReasonMediaAttachFailed},
+	{"custom_errors.ErrMediaDetachFailed", custom_errors.ErrMediaDetachFailed, codes.Internal, errinfo.ReasonMediaDetachFailed},
+	{"custom_errors.ErrMediaReorderFailed", custom_errors.ErrMediaReorderFailed, codes.Internal, errinfo.ReasonMediaReorderFailed},
+	{"custom_errors.ErrTagQueryFailed", custom_errors.ErrTagQueryFailed, codes.Internal, errinfo.ReasonTagQueryFailed},
+	{"custom_errors.ErrTagScanFailed", custom_errors.ErrTagScanFailed, codes.Internal, errinfo.ReasonTagQueryFailed},
+	{"custom_errors.ErrTagCreateFailed", custom_errors.ErrTagCreateFailed, codes.Internal, errinfo.ReasonTagCreateFailed},
+	{"custom_errors.ErrTagInsertFailed", custom_errors.ErrTagInsertFailed, codes.Internal, errinfo.ReasonTagCreateFailed},
+	{"custom_errors.ErrTagDeleteFailed", custom_errors.ErrTagDeleteFailed, codes.Internal, errinfo.ReasonTagDeleteFailed},
+	{"custom_errors.ErrTagUntagFailed", custom_errors.ErrTagUntagFailed, codes.Internal, errinfo.ReasonTagDeleteFailed},
+	{"custom_errors.ErrTagPost", custom_errors.ErrTagPost, codes.Internal, errinfo.ReasonTagPostFailed},
+	{"custom_errors.ErrTagVerifyPostFailed", custom_errors.ErrTagVerifyPostFailed, codes.Internal, errinfo.ReasonTagPostFailed},
+	{"custom_errors.ErrUnknownTagError", custom_errors.ErrUnknownTagError, codes.Internal, errinfo.ReasonTagPostFailed},
+	{"custom_errors.ErrDatabaseQuery", custom_errors.ErrDatabaseQuery, codes.Internal, errinfo.ReasonDatabaseError},
+	{"custom_errors.ErrDatabaseConnection", custom_errors.ErrDatabaseConnection, codes.Internal, errinfo.ReasonDatabaseError},
+	{"custom_errors.ErrDatabaseTransaction", custom_errors.ErrDatabaseTransaction, codes.Internal, errinfo.ReasonDatabaseError},
+}
+
+// neverReturned are the sentinels the service handles itself, so no handler sees them.
+var neverReturned = map[string]string{
+	"custom_errors.ErrCacheMiss":            "a cache miss falls through to the database",
+	"custom_errors.ErrTagsNotFound":         "a post without tags is not an error",
+	"custom_errors.ErrNoUpdateRows":         "the service rejects an update without changes as ErrEmptyUpdate first",
+	"custom_errors.ErrInternalServiceError": "the fallback of serviceErrorToStatus itself",
+}
+
+func TestServiceErrorToStatus_ReasonCatalog(t *testing.T) {
+	for _, tt := range reasonCatalog {
+		t.Run(tt.name, func(t *testing.T) {
+			st := serviceErrorToStatus(fmt.Errorf("service: %w", tt.err))
+
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.reason, errinfo.StatusReason(st))
+			assert.NotEqual(t, errinfo.ReasonInternal, errinfo.StatusReason(st), "a cataloged error needs its own reason")
+		})
+	}
+}
+
+func TestServiceErrorToStatus_Fallback(t *testing.T) {
+	st := serviceErrorToStatus(fmt.Errorf("connection reset"))
+
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, errinfo.ReasonInternal, errinfo.StatusReason(st))
+	assert.Equal(t, custom_errors.ErrInternalServiceError.Error(), st.Message())
+}
+
+func TestServiceErrorToStatus_Precedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{
+			name:   "caller deadline over the query it cut short",
+			err:    fmt.Errorf("%w: %w", model.ErrDeadlineExceeded, custom_errors.ErrDatabaseQuery),
+			code:   codes.DeadlineExceeded,
+			reason: errinfo.ReasonDeadlineExceeded,
+		},
+		{
+			name:   "specific failure over the database error it wraps",
+			err:    fmt.Errorf("%w: %w", custom_errors.ErrMediaQueryFailed, custom_errors.ErrDatabaseQuery),
+			code:   codes.Internal,
+			reason: errinfo.ReasonMediaQueryFailed,
+		},
+		{
+			name:   "stream stopped by its client",
+			err:    fmt.Errorf("send: %w", context.Canceled),
+			code:   codes.Canceled,
+			reason: errinfo.ReasonCanceled,
+		},
+		{
+			name:   "invalid input of a post in the wrong state",
+			err:    failedPrecondition(fmt.Errorf("%w: post 7 is not scheduled", custom_errors.ErrInvalidInput), custom_errors.ErrInvalidInput),
+			code:   codes.FailedPrecondition,
+			reason: errinfo.ReasonFailedPrecondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := serviceErrorToStatus(tt.err)
+
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.reason, errinfo.StatusReason(st))
+		})
+	}
+}
+
+func TestServiceErrorToStatus_Details(t *testing.T) {
+	t.Run("RetryInfoComesFirst", func(t *testing.T) {
+		st := serviceErrorToStatus(&model.RateLimitExceededError{Operation: "create_post", RetryAfter: 30 * time.Second})
+
+		require.Len(t, st.Details(), 2)
+		retry, ok := st.Details()[0].(*errdetails.RetryInfo)
+		require.True(t, ok)
+		assert.Equal(t, 30*time.Second, retry.GetRetryDelay().AsDuration())
+		info, ok := st.Details()[1].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, errinfo.Domain, info.GetDomain())
+	})
+
+	t.Run("VerboseMessage", func(t *testing.T) {
+		st := serviceErrorToStatus(fmt.Errorf("%w: limit must be between 1 and 100", custom_errors.ErrInvalidInput))
+
+		assert.Contains(t, st.Message(), "limit must be between 1 and 100")
+	})
+
+	t.Run("SentinelMessage", func(t *testing.T) {
+		st := serviceErrorToStatus(fmt.Errorf("%w: select posts: connection refused", custom_errors.ErrDatabaseQuery))
+
+		assert.Equal(t, custom_errors.ErrDatabaseQuery.Error(), st.Message())
+	})
+}
+
+// TestServiceErrors_CoverEveryServiceError fails when the service, the domain or a
+// repository refers to a sentinel that is neither in reasonCatalog nor in neverReturned: a new
+// error needs a reason before it can reach a client.
+func TestServiceErrors_CoverEveryServiceError(t *testing.T) {
+	known := map[string]bool{}
+	for _, tt := range reasonCatalog {
+		known[tt.name] = true
+	}
+
+	referenced := map[string]string{}
+	for _, dir := range []string{"../../../../application", "../../../../domain", "../../../outbound"} {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+			if err != nil {
+				return err
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.SelectorExpr:
+					if pkg, ok := n.X.(*ast.Ident); ok && (pkg.Name == "custom_errors" || pkg.Name == "model") && strings.HasPrefix(n.Sel.Name, "Err") {
+						referenced[pkg.Name+"."+n.Sel.Name] = path
+					}
+				case *ast.ValueSpec:
+					if file.Name.Name == "model" {
+						for _, name := range n.Names {
+							if strings.HasPrefix(name.Name, "Err") {
+								referenced["model."+name.Name] = path
+							}
+						}
+					}
+				}
+				return true
+			})
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	require.NotEmpty(t, referenced)
+	for name, path := range referenced {
+		if _, ok := neverReturned[name]; ok {
+			continue
+		}
+		assert.True(t, known[name], "%s (referenced in %s) has no reason in serviceErrors", name, path)
+	}
+}
@@ -0,0 +1,82 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+
+	"github.com/go-playground/validator/v10"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"google.golang.org/grpc/codes"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+)
+
+type PostExporter interface {
+	ExportAuthorPosts(ctx context.Context, authorID int64, send func(*model.PostDetailed) error) error
+}
+
+// PostExportStream is the server side of an export stream. It has the shape grpc generates
+// for server-streaming methods, so the generated stream satisfies it as is.
+type PostExportStream interface {
+	Send(*pb.Post) error
+	Context() context.Context
+}
+
+type ExportAuthorPostsHandler struct {
+	postService PostExporter
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewExportAuthorPostsHandler(postService PostExporter, validate *validator.Validate, log ports.Logger) *ExportAuthorPostsHandler {
+	return &ExportAuthorPostsHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type ExportAuthorPostsRequestInternal struct {
+	AuthorID int64 `validate:"required,gt=0"`
+}
+
+// ExportAuthorPosts streams every post of authorID, drafts included, one message per post in
+// ascending id order. Only the author or an internal admin may export.
+// ExportAuthorPosts is not exposed on the wire until the proto definitions gain the RPC.
+func (h *ExportAuthorPostsHandler) ExportAuthorPosts(authorID int64, stream PostExportStream) error {
+	ctx := stream.Context()
+	h.log.Debug("Handling ExportAuthorPosts request", slog.Int64("author_id", authorID))
+
+	if err := h.validate.Struct(&ExportAuthorPostsRequestInternal{AuthorID: authorID}); err != nil {
+		h.log.Debug("ExportAuthorPosts validation failed", slog.String("error", err.Error()))
+		return invalidRequest("invalid request")
+	}
+	if requesterID := requesterIDFromContext(ctx); (requesterID == nil || *requesterID != authorID) && !isInternalAdmin(ctx) {
+		h.log.Debug("Requester may not export posts", slog.Int64("author_id", authorID), slog.Any("requester_id", requesterID))
+		return errinfo.Error(codes.PermissionDenied, errinfo.ReasonNotAuthor, custom_errors.ErrForbidden.Error())
+	}
+
+	var sendErr error
+	err := h.postService.ExportAuthorPosts(ctx, authorID, func(post *model.PostDetailed) error {
+		resp, err := postResponse(h.log, post)
+		if err == nil {
+			err = stream.Send(resp)
+		}
+		sendErr = err
+		return sendErr
+	})
+	if err != nil {
+		if sendErr != nil {
+			h.log.Debug("Export stream closed", slog.Int64("author_id", authorID), slog.String("error", sendErr.Error()))
+			return sendErr
+		}
+		return serviceErrorStatus(h.log, err, "Failed to export posts", slog.Int64("author_id", authorID))
+	}
+
+	h.log.Debug("Exported posts successfully", slog.Int64("author_id", authorID))
+	return nil
+}
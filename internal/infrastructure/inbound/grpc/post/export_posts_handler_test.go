@@ -0,0 +1,162 @@
+package post_grpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+// fakeExportStream records sent posts and fails every Send once err is set.
+type fakeExportStream struct {
+	ctx  context.Context
+	sent []*pb.Post
+	err  error
+}
+
+func (s *fakeExportStream) Send(post *pb.Post) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, post)
+	return nil
+}
+
+func (s *fakeExportStream) Context() context.Context { return s.ctx }
+
+func userContext(userID string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", userID))
+}
+
+// exportPosts answers ExportAuthorPosts by sending posts and returning what send returned.
+func exportPosts(posts ...*model.PostDetailed) func(context.Context, int64, func(*model.PostDetailed) error) error {
+	return func(_ context.Context, _ int64, send func(*model.PostDetailed) error) error {
+		for _, post := range posts {
+			if err := send(post); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestExportAuthorPostsHandler_ExportAuthorPosts(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	content := "Body"
+	posts := []*model.PostDetailed{
+		{
+			Post:  &model.Post{ID: 1, AuthorID: 7, Title: "First", Content: &content},
+			Media: []*model.PostMedia{{ID: 3, URL: "https://example.com/a.jpg", Type: model.MediaTypeImage, Position: 1}},
+			Tags:  []*model.Tag{{ID: 1, Name: "go"}},
+		},
+		{Post: &model.Post{ID: 2, AuthorID: 7, Title: "Second"}},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewExportAuthorPostsHandler(mockPostService, validate, testLogger)
+		mockPostService.On("ExportAuthorPosts", mock.Anything, int64(7), mock.Anything).Return(exportPosts(posts...))
+		stream := &fakeExportStream{ctx: userContext("7")}
+
+		err := handler.ExportAuthorPosts(7, stream)
+
+		require.NoError(t, err)
+		require.Len(t, stream.sent, 2)
+		assert.Equal(t, int64(1), stream.sent[0].Id)
+		assert.Equal(t, "Body", stream.sent[0].Content)
+		assert.Equal(t, "https://example.com/a.jpg", stream.sent[0].Media[0].Url)
+		assert.Equal(t, []string{"go"}, stream.sent[0].Tags)
+		assert.Equal(t, int64(2), stream.sent[1].Id)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("AdminMayExportAnyAuthor", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewExportAuthorPostsHandler(mockPostService, validate, testLogger)
+		mockPostService.On("ExportAuthorPosts", mock.Anything, int64(7), mock.Anything).Return(exportPosts(posts...))
+		stream := &fakeExportStream{ctx: adminContext()}
+
+		require.NoError(t, handler.ExportAuthorPosts(7, stream))
+		assert.Len(t, stream.sent, 2)
+	})
+
+	t.Run("OtherUserIsDenied", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewExportAuthorPostsHandler(mockPostService, validate, testLogger)
+
+		err := handler.ExportAuthorPosts(7, &fakeExportStream{ctx: userContext("8")})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, errinfo.ReasonNotAuthor, errinfo.Reason(err))
+		mockPostService.AssertNotCalled(t, "ExportAuthorPosts", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("AnonymousIsDenied", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewExportAuthorPostsHandler(mockPostService, validate, testLogger)
+
+		err := handler.ExportAuthorPosts(7, &fakeExportStream{ctx: context.Background()})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, errinfo.ReasonNotAuthor, errinfo.Reason(err))
+	})
+
+	t.Run("InvalidAuthorID", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewExportAuthorPostsHandler(mockPostService, validate, testLogger)
+
+		err := handler.ExportAuthorPosts(0, &fakeExportStream{ctx: adminContext()})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("SendErrorIsReturnedUnchanged", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewExportAuthorPostsHandler(mockPostService, validate, testLogger)
+		mockPostService.On("ExportAuthorPosts", mock.Anything, int64(7), mock.Anything).Return(exportPosts(posts...))
+		sendErr := status.Error(codes.Unavailable, "transport is closing")
+
+		err := handler.ExportAuthorPosts(7, &fakeExportStream{ctx: userContext("7"), err: sendErr})
+
+		assert.Equal(t, sendErr, err)
+	})
+
+	errorCases := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"ClientCancelled", context.Canceled, codes.Canceled},
+		{"DeadlineExceeded", context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"Timeout", model.ErrTimeout, codes.DeadlineExceeded},
+		{"UserServiceDown", custom_errors.ErrExternalServiceError, codes.Unavailable},
+		{"DatabaseError", errors.New("boom"), codes.Internal},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewExportAuthorPostsHandler(mockPostService, validate, testLogger)
+			mockPostService.On("ExportAuthorPosts", mock.Anything, int64(7), mock.Anything).Return(tc.err)
+
+			err := handler.ExportAuthorPosts(7, &fakeExportStream{ctx: userContext("7")})
+
+			assert.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type PostForceDeleter interface {
+	ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (*model.ModerationAction, error)
+}
+
+type ForceDeletePostHandler struct {
+	postService PostForceDeleter
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewForceDeletePostHandler(postService PostForceDeleter, validate *validator.Validate, log ports.Logger) *ForceDeletePostHandler {
+	return &ForceDeletePostHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type ForceDeletePostRequestInternal struct {
+	ActorID int64  `validate:"required,gt=0"`
+	PostID  int64  `validate:"required,gt=0"`
+	Reason  string `validate:"required,min=5,max=500"`
+}
+
+// ForceDeletePost is not exposed on the wire until the proto definitions gain moderation RPCs.
+func (h *ForceDeletePostHandler) ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (*model.ModerationAction, error) {
+	h.log.Debug("Handling ForceDeletePost request", slog.Int64("post_id", postID), slog.Int64("actor_id", actorID))
+
+	if !isInternalAdmin(ctx) {
+		h.log.Debug("ForceDeletePost called without admin flag", slog.Int64("post_id", postID))
+		return nil, notAdmin()
+	}
+	if err := h.validate.Struct(&ForceDeletePostRequestInternal{ActorID: actorID, PostID: postID, Reason: reason}); err != nil {
+		h.log.Debug("ForceDeletePost validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	action, err := h.postService.ForceDeletePost(ctx, actorID, postID, reason)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to force delete post", slog.Int64("post_id", postID))
+	}
+
+	h.log.Info("Post force deleted",
+		slog.Int64("post_id", postID),
+		slog.Int64("actor_id", actorID),
+		slog.Int64("author_id", action.AuthorID))
+	return action, nil
+}
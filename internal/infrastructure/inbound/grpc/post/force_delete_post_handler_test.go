@@ -0,0 +1,99 @@
+package post_grpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestForceDeletePostHandler_ForceDeletePost(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewForceDeletePostHandler(mockPostService, validate, testLogger)
+
+		action := &model.ModerationAction{ID: 1, Action: model.ModerationActionForceDelete, PostID: 7, AuthorID: 3, ActorID: 99, Reason: "spam links"}
+		mockPostService.On("ForceDeletePost", mock.Anything, int64(99), int64(7), "spam links").Return(action, nil)
+
+		resp, err := handler.ForceDeletePost(adminContext(), 99, 7, "spam links")
+
+		require.NoError(t, err)
+		assert.Equal(t, action, resp)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("MissingAdminFlag", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewForceDeletePostHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.ForceDeletePost(context.Background(), 99, 7, "spam links")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, errinfo.ReasonNotAdmin, errinfo.Reason(err))
+		mockPostService.AssertNotCalled(t, "ForceDeletePost", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidReason", func(t *testing.T) {
+		for _, reason := range []string{"", "bad", string(make([]byte, 501))} {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewForceDeletePostHandler(mockPostService, validate, testLogger)
+
+			resp, err := handler.ForceDeletePost(adminContext(), 99, 7, reason)
+
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), "reason of %d bytes", len(reason))
+			mockPostService.AssertNotCalled(t, "ForceDeletePost", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("ServiceValidationError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewForceDeletePostHandler(mockPostService, validate, testLogger)
+
+		reason := "     "
+		mockPostService.On("ForceDeletePost", mock.Anything, int64(99), int64(7), reason).
+			Return(nil, model.ValidateModerationReason(reason))
+
+		_, err := handler.ForceDeletePost(adminContext(), 99, 7, reason)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewForceDeletePostHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ForceDeletePost", mock.Anything, int64(99), int64(7), "spam links").Return(nil, custom_errors.ErrPostNotFound)
+
+		_, err := handler.ForceDeletePost(adminContext(), 99, 7, "spam links")
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("DatabaseError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewForceDeletePostHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ForceDeletePost", mock.Anything, int64(99), int64(7), "spam links").Return(nil, custom_errors.ErrDatabaseQuery)
+
+		_, err := handler.ForceDeletePost(adminContext(), 99, 7, "spam links")
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
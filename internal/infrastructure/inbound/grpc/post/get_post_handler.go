@@ -2,23 +2,17 @@ package post_grpc
 
 import (
 	"context"
-	"errors"
 	"log/slog"
 	ports "pinstack-post-service/internal/domain/ports/output"
 
-	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
-
 	"github.com/go-playground/validator/v10"
 	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 
 	model "pinstack-post-service/internal/domain/models"
 )
 
 type PostGetter interface {
-	GetPostByID(ctx context.Context, id int64) (*model.PostDetailed, error)
+	GetPostByID(ctx context.Context, id int64, requesterID *int64) (*model.PostDetailed, error)
 }
 
 type GetPostHandler struct {
@@ -40,7 +34,71 @@ type GetPostRequestInternal struct {
 	PostID int64 `validate:"required,gt=0"`
 }
 
+// GetPostResponse is a post with what the requester may do with it.
+type GetPostResponse struct {
+	Post      *pb.Post
+	IsAuthor  bool
+	CanEdit   bool
+	CanDelete bool
+	// EditCount is how many edits changed the title or content; Edited is EditCount > 0.
+	EditCount int64
+	Edited    bool
+	// IsPinned reports whether the post is pinned to the top of its author's profile.
+	IsPinned bool
+	// HasMoreMedia reports that Post carries only the first of the post's MediaTotal media;
+	// the rest are paged through GetPostMedia.
+	HasMoreMedia bool
+	MediaTotal   int
+	// Source is the client the post was created from; see model.PostSource.
+	Source model.PostSource
+	// ModerationStatus and RejectionReason let clients render a placeholder for a rejected
+	// post, whose title, content and media are stripped for everyone but its author.
+	ModerationStatus model.ModerationStatus
+	RejectionReason  model.RejectionReason
+}
+
 func (h *GetPostHandler) GetPost(ctx context.Context, req *pb.GetPostRequest) (*pb.Post, error) {
+	_, resp, err := h.getPost(ctx, req, requesterIDFromContext(ctx))
+	return resp, err
+}
+
+// GetPostForRequester is GetPost with the permissions of requesterID on the post; a nil
+// requesterID falls back to the x-user-id metadata. The permissions are computed after the
+// post is read, so a cached post serves every requester.
+// GetPostRequest has no requester_id and Post no permission fields in proto v0.1.22, so this
+// is not exposed over gRPC yet.
+func (h *GetPostHandler) GetPostForRequester(ctx context.Context, req *pb.GetPostRequest, requesterID *int64) (*GetPostResponse, error) {
+	if requesterID == nil {
+		requesterID = requesterIDFromContext(ctx)
+	}
+	post, resp, err := h.getPost(ctx, req, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	permissions := model.PermissionsFor(post.Post, requesterID)
+	result := &GetPostResponse{
+		Post:             resp,
+		IsAuthor:         permissions.IsAuthor,
+		CanEdit:          permissions.CanEdit,
+		CanDelete:        permissions.CanDelete,
+		EditCount:        post.Post.EditCount,
+		Edited:           post.Post.Edited(),
+		IsPinned:         post.Post.IsPinned(),
+		HasMoreMedia:     post.HasMoreMedia(),
+		MediaTotal:       post.MediaTotal,
+		Source:           post.Post.Source,
+		ModerationStatus: post.Post.ModerationStatus,
+	}
+	if result.ModerationStatus == "" {
+		result.ModerationStatus = model.ModerationStatusApproved
+	}
+	if post.Post.RejectionReason != nil {
+		result.RejectionReason = *post.Post.RejectionReason
+	}
+	return result, nil
+}
+
+func (h *GetPostHandler) getPost(ctx context.Context, req *pb.GetPostRequest, requesterID *int64) (*model.PostDetailed, *pb.Post, error) {
 	h.log.Debug("Handling GetPost request", slog.Int64("post_id", req.GetId()))
 
 	validationReq := &GetPostRequestInternal{
@@ -49,83 +107,27 @@ func (h *GetPostHandler) GetPost(ctx context.Context, req *pb.GetPostRequest) (*
 
 	if err := h.validate.Struct(validationReq); err != nil {
 		h.log.Debug("GetPost validation failed", slog.Int64("post_id", req.GetId()), slog.String("error", err.Error()))
-		return nil, status.Error(codes.InvalidArgument, "invalid request")
+		return nil, nil, invalidRequest("invalid request")
 	}
 
 	h.log.Debug("Getting post by ID", slog.Int64("post_id", req.GetId()))
-	retrievedPostModel, err := h.postService.GetPostByID(ctx, req.GetId())
+	serviceCtx, cacheInfo := withCacheDebug(ctx)
+	retrievedPostModel, err := h.postService.GetPostByID(serviceCtx, req.GetId(), requesterID)
 	if err != nil {
-		switch {
-		case errors.Is(err, custom_errors.ErrPostNotFound):
-			h.log.Debug("Post not found", slog.Int64("post_id", req.GetId()))
-			return nil, status.Error(codes.NotFound, "post not found")
-		case errors.Is(err, custom_errors.ErrPostValidation):
-			h.log.Debug("Post retrieval validation failed", slog.Int64("post_id", req.GetId()), slog.String("error", err.Error()))
-			return nil, status.Error(codes.InvalidArgument, "post retrieval validation failed")
-		default:
-			h.log.Error("Failed to get post", slog.Int64("post_id", req.GetId()), slog.String("error", err.Error()))
-			return nil, status.Error(codes.Internal, "failed to get post")
-		}
-	}
-
-	pbMedia := make([]*pb.Media, len(retrievedPostModel.Media))
-	for i, m := range retrievedPostModel.Media {
-		var mediaCreatedAtPb *timestamppb.Timestamp
-		if m.CreatedAt.Valid {
-			mediaCreatedAtPb = timestamppb.New(m.CreatedAt.Time)
-		}
-		pbMedia[i] = &pb.Media{
-			Id:        m.ID,
-			Url:       m.URL,
-			Type:      string(m.Type),
-			Position:  m.Position,
-			CreatedAt: mediaCreatedAtPb,
-		}
+		return nil, nil, serviceErrorStatus(h.log, err, "Failed to get post", slog.Int64("post_id", req.GetId()))
 	}
 
-	var postID int64
-	var authorID int64
-	var title string
-	var content string
-	var createdAtPb *timestamppb.Timestamp
-	var updatedAtPb *timestamppb.Timestamp
-
-	if retrievedPostModel.Post != nil {
-		postID = retrievedPostModel.Post.ID
-		authorID = retrievedPostModel.Post.AuthorID
-		title = retrievedPostModel.Post.Title
-		if retrievedPostModel.Post.Content != nil {
-			content = *retrievedPostModel.Post.Content
-		}
-		if retrievedPostModel.Post.CreatedAt.Valid {
-			createdAtPb = timestamppb.New(retrievedPostModel.Post.CreatedAt.Time)
-		}
-		if retrievedPostModel.Post.UpdatedAt.Valid {
-			updatedAtPb = timestamppb.New(retrievedPostModel.Post.UpdatedAt.Time)
-		}
-	}
-
-	pbTags := make([]string, len(retrievedPostModel.Tags))
-	for i, t := range retrievedPostModel.Tags {
-		pbTags[i] = t.Name
-	}
-
-	resp := &pb.Post{
-		Id:        postID,
-		AuthorId:  authorID,
-		Title:     title,
-		Content:   content,
-		Tags:      pbTags,
-		Media:     pbMedia,
-		CreatedAt: createdAtPb,
-		UpdatedAt: updatedAtPb,
+	resp, err := postResponse(h.log, retrievedPostModel)
+	if err != nil {
+		return nil, nil, err
 	}
+	setCacheDebugTrailer(ctx, h.log, cacheInfo)
 
 	h.log.Debug("Post retrieved successfully",
-		slog.Int64("post_id", postID),
-		slog.Int64("author_id", authorID),
-		slog.Int("tags_count", len(pbTags)),
-		slog.Int("media_count", len(pbMedia)))
+		slog.Int64("post_id", resp.Id),
+		slog.Int64("author_id", resp.AuthorId),
+		slog.Int("tags_count", len(resp.Tags)),
+		slog.Int("media_count", len(resp.Media)))
 
-	return resp, nil
+	return retrievedPostModel, resp, nil
 }
@@ -3,9 +3,15 @@ package post_grpc_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	post_service "pinstack-post-service/internal/application/service/post"
 	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
 	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
 	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/testsupport"
+	cache_mock "pinstack-post-service/mocks/cache"
 	mockpost "pinstack-post-service/mocks/post"
 	"testing"
 	"time"
@@ -13,12 +19,13 @@ import (
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/jackc/pgx/v5/pgtype"
 	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -36,44 +43,19 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 			Id: postID,
 		}
 
-		createdAt := time.Now()
-		updatedAt := time.Now()
-		content := "This is the post content"
-
-		expectedPostDetailed := &model.PostDetailed{
-			Post: &model.Post{
-				ID:        postID,
-				AuthorID:  456,
-				Title:     "Test Post Title",
-				Content:   &content,
-				CreatedAt: pgtype.Timestamp{Time: createdAt, Valid: true},
-				UpdatedAt: pgtype.Timestamp{Time: updatedAt, Valid: true},
-			},
-			Media: []*model.PostMedia{
-				{
-					ID:        1,
-					PostID:    postID,
-					URL:       "https://example.com/image1.jpg",
-					Type:      "image",
-					Position:  1,
-					CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
-				},
-				{
-					ID:        2,
-					PostID:    postID,
-					URL:       "https://example.com/image2.jpg",
-					Type:      "video",
-					Position:  1,
-					CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
-				},
-			},
-			Tags: []*model.Tag{
-				{ID: 1, Name: "tag1"},
-				{ID: 2, Name: "tag2"},
-			},
-		}
+		expectedPostDetailed := testsupport.NewPostDetailedBuilder().
+			WithID(postID).
+			WithAuthor(456).
+			WithTitle("Test Post Title").
+			WithContent("This is the post content").
+			WithMedia("https://example.com/image1.jpg", "https://example.com/image2.jpg").
+			WithTags("tag1", "tag2").
+			WithoutAuthor().
+			Build()
+		createdAt := expectedPostDetailed.Post.CreatedAt.Time
+		updatedAt := expectedPostDetailed.Post.UpdatedAt.Time
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).Return(expectedPostDetailed, nil)
+		mockPostService.On("GetPostByID", mock.Anything, postID, mock.Anything).Return(expectedPostDetailed, nil)
 
 		resp, err := handler.GetPost(context.Background(), req)
 
@@ -105,6 +87,41 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 		mockPostService.AssertExpectations(t)
 	})
 
+	t.Run("NilMediaAndTagEntriesAreSkipped", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
+
+		partial := &model.PostDetailed{
+			Post:  &model.Post{ID: 123, AuthorID: 1, Title: "title"},
+			Media: []*model.PostMedia{nil, {ID: 7, URL: "https://example.com/7.jpg", Type: "image"}},
+			Tags:  []*model.Tag{{ID: 1, Name: "go"}, nil},
+		}
+		mockPostService.On("GetPostByID", mock.Anything, int64(123), mock.Anything).Return(partial, nil)
+
+		resp, err := handler.GetPost(context.Background(), &pb.GetPostRequest{Id: 123})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(123), resp.Id)
+		assert.Equal(t, []string{"go"}, resp.Tags)
+		require.Len(t, resp.Media, 1)
+		assert.Equal(t, int64(7), resp.Media[0].Id)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("ResultWithoutPostIsInternal", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("GetPostByID", mock.Anything, int64(123), mock.Anything).
+			Return(&model.PostDetailed{Tags: []*model.Tag{{ID: 1, Name: "go"}}}, nil)
+
+		resp, err := handler.GetPost(context.Background(), &pb.GetPostRequest{Id: 123})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.Internal, status.Code(err))
+		mockPostService.AssertExpectations(t)
+	})
+
 	t.Run("SuccessWithNullableFields", func(t *testing.T) {
 		mockPostService := new(mockpost.Service)
 		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
@@ -114,20 +131,15 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 			Id: postID,
 		}
 
-		expectedPostDetailed := &model.PostDetailed{
-			Post: &model.Post{
-				ID:        postID,
-				AuthorID:  456,
-				Title:     "Test Post Title",
-				Content:   nil,
-				CreatedAt: pgtype.Timestamp{Valid: false},
-				UpdatedAt: pgtype.Timestamp{Valid: false},
-			},
-			Media: nil,
-			Tags:  nil,
-		}
+		expectedPostDetailed := testsupport.NewPostDetailedBuilder().
+			WithID(postID).
+			WithAuthor(456).
+			WithTitle("Test Post Title").
+			Post(func(b *testsupport.PostBuilder) { b.WithoutTimestamps() }).
+			WithoutAuthor().
+			Build()
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).Return(expectedPostDetailed, nil)
+		mockPostService.On("GetPostByID", mock.Anything, postID, mock.Anything).Return(expectedPostDetailed, nil)
 
 		resp, err := handler.GetPost(context.Background(), req)
 
@@ -145,6 +157,31 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 		mockPostService.AssertExpectations(t)
 	})
 
+	t.Run("SuccessWithDeletedAuthor", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
+
+		postID := int64(123)
+		expectedPostDetailed := testsupport.NewPostDetailedBuilder().
+			WithID(postID).
+			WithAuthor(456).
+			WithTitle("Orphaned Post").
+			WithTags("tag1").
+			WithoutAuthor().
+			Build()
+
+		mockPostService.On("GetPostByID", mock.Anything, postID, mock.Anything).Return(expectedPostDetailed, nil)
+
+		resp, err := handler.GetPost(context.Background(), &pb.GetPostRequest{Id: postID})
+
+		require.NoError(t, err)
+		assert.Equal(t, postID, resp.Id)
+		assert.Equal(t, int64(456), resp.AuthorId)
+		assert.Equal(t, []string{"tag1"}, resp.Tags)
+
+		mockPostService.AssertExpectations(t)
+	})
+
 	t.Run("ValidationError", func(t *testing.T) {
 		mockPostService := new(mockpost.Service)
 		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
@@ -161,7 +198,7 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
-		assert.Equal(t, "invalid request", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
 
 		mockPostService.AssertNotCalled(t, "GetPostByID")
 	})
@@ -175,7 +212,7 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 			Id: postID,
 		}
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).Return(nil, custom_errors.ErrPostNotFound)
+		mockPostService.On("GetPostByID", mock.Anything, postID, mock.Anything).Return(nil, custom_errors.ErrPostNotFound)
 
 		resp, err := handler.GetPost(context.Background(), req)
 
@@ -185,11 +222,24 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.NotFound, statusErr.Code())
-		assert.Equal(t, "post not found", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonPostNotFound, errinfo.Reason(err))
 
 		mockPostService.AssertExpectations(t)
 	})
 
+	t.Run("PrivatePostOfAnotherAuthor", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("GetPostByID", mock.Anything, int64(7), mock.Anything).Return(nil, custom_errors.ErrForbidden)
+
+		resp, err := handler.GetPost(context.Background(), &pb.GetPostRequest{Id: 7})
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockPostService.AssertExpectations(t)
+	})
+
 	t.Run("ValidationErrorFromService", func(t *testing.T) {
 		mockPostService := new(mockpost.Service)
 		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
@@ -199,7 +249,7 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 			Id: postID,
 		}
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).Return(nil, custom_errors.ErrPostValidation)
+		mockPostService.On("GetPostByID", mock.Anything, postID, mock.Anything).Return(nil, custom_errors.ErrPostValidation)
 
 		resp, err := handler.GetPost(context.Background(), req)
 
@@ -209,7 +259,7 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
-		assert.Equal(t, "post retrieval validation failed", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonValidationFailed, errinfo.Reason(err))
 
 		mockPostService.AssertExpectations(t)
 	})
@@ -223,7 +273,7 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 			Id: postID,
 		}
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).Return(nil, errors.New("database error"))
+		mockPostService.On("GetPostByID", mock.Anything, postID, mock.Anything).Return(nil, errors.New("database error"))
 
 		resp, err := handler.GetPost(context.Background(), req)
 
@@ -233,8 +283,135 @@ func TestGetPostHandler_GetPost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Equal(t, "failed to get post", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonInternal, errinfo.Reason(err))
+
+		mockPostService.AssertExpectations(t)
+	})
+	t.Run("Timeout", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
+
+		postID := int64(123)
+		timedOut := fmt.Errorf("%w: %w", custom_errors.ErrDatabaseQuery, model.ErrTimeout)
+		mockPostService.On("GetPostByID", mock.Anything, postID, mock.Anything).Return(nil, timedOut)
+
+		resp, err := handler.GetPost(context.Background(), &pb.GetPostRequest{Id: postID})
+
+		assert.Nil(t, resp)
+		statusErr, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.DeadlineExceeded, statusErr.Code())
 
 		mockPostService.AssertExpectations(t)
 	})
 }
+
+func TestGetPostHandler_GetPostForRequester(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	author, other := int64(7), int64(8)
+	// The post comes from the cache decorator, so every requester is served the same cached
+	// post and the flags must be computed per request.
+	cached := testsupport.NewPostDetailedBuilder().WithAuthor(author).WithoutAuthor().Build()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		requesterID *int64
+		want        model.PostPermissions
+	}{
+		{name: "author", ctx: context.Background(), requesterID: &author,
+			want: model.PostPermissions{IsAuthor: true, CanEdit: true, CanDelete: true}},
+		{name: "another user", ctx: context.Background(), requesterID: &other},
+		{name: "anonymous", ctx: context.Background()},
+		{name: "author from metadata", ctx: userContext("7"),
+			want: model.PostPermissions{IsAuthor: true, CanEdit: true, CanDelete: true}},
+		{name: "explicit requester wins over metadata", ctx: userContext("7"), requesterID: &other},
+	}
+
+	postCache := new(cache_mock.PostCache)
+	postCache.On("GetPost", mock.Anything, int64(1)).Return(cached, nil)
+	service := post_service.NewPostServiceCacheDecorator(new(mockpost.Service), new(cache_mock.UserCache), postCache,
+		new(cache_mock.CacheBatcher), testLogger, prometheus.NewPrometheusMetricsProvider())
+	handler := post_grpc.NewGetPostHandler(service, validate, testLogger)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := handler.GetPostForRequester(tt.ctx, &pb.GetPostRequest{Id: 1}, tt.requesterID)
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), resp.Post.Id)
+			assert.Equal(t, tt.want, model.PostPermissions{IsAuthor: resp.IsAuthor, CanEdit: resp.CanEdit, CanDelete: resp.CanDelete})
+		})
+	}
+	postCache.AssertNotCalled(t, "SetPost", mock.Anything, mock.Anything)
+
+	t.Run("errors are mapped as in GetPost", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		mockPostService.On("GetPostByID", mock.Anything, int64(2), &other).Return(nil, custom_errors.ErrForbidden)
+		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.GetPostForRequester(context.Background(), &pb.GetPostRequest{Id: 2}, &other)
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("media past the cap are flagged", func(t *testing.T) {
+		post := testsupport.NewPostDetailedBuilder().WithAuthor(author).WithoutAuthor().Build()
+		post.Media = []*model.PostMedia{
+			{ID: 1, URL: "https://media.pinstack.io/1.jpg", Type: model.MediaTypeImage, Position: 1},
+			{ID: 2, URL: "https://media.pinstack.io/2.jpg", Type: model.MediaTypeImage, Position: 2},
+			{ID: 3, URL: "https://media.pinstack.io/3.jpg", Type: model.MediaTypeImage, Position: 3},
+		}
+		post.CapMedia(2)
+		mockPostService := new(mockpost.Service)
+		mockPostService.On("GetPostByID", mock.Anything, int64(3), &other).Return(post, nil)
+		handler := post_grpc.NewGetPostHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.GetPostForRequester(context.Background(), &pb.GetPostRequest{Id: 3}, &other)
+
+		require.NoError(t, err)
+		assert.Len(t, resp.Post.Media, 2)
+		assert.True(t, resp.HasMoreMedia)
+		assert.Equal(t, 3, resp.MediaTotal)
+	})
+}
+
+func TestGetPostHandler_GetPost_CacheDebugTrailer(t *testing.T) {
+	content := "This is a test post content with enough length"
+	post := &model.PostDetailed{Post: &model.Post{ID: 123, AuthorID: 1, Title: "Test Post", Content: &content}}
+	get := func(t *testing.T, md metadata.MD) *headerStream {
+		t.Helper()
+		mockPostService := new(mockpost.Service)
+		mockPostService.On("GetPostByID", mock.Anything, int64(123), (*int64)(nil)).
+			Run(func(args mock.Arguments) {
+				model.RecordCacheInfo(args.Get(0).(context.Context), model.CacheInfo{
+					Hit: true, Source: model.CacheSourceCache, Age: 1500 * time.Millisecond, TTL: 4 * time.Minute,
+				})
+			}).
+			Return(post, nil)
+		handler := post_grpc.NewGetPostHandler(mockPostService, validator.New(), logger.New("test"))
+		stream := &headerStream{}
+		ctx := grpc.NewContextWithServerTransportStream(metadata.NewIncomingContext(context.Background(), md), stream)
+
+		_, err := handler.GetPost(ctx, &pb.GetPostRequest{Id: 123})
+		require.NoError(t, err)
+		return stream
+	}
+
+	t.Run("WithDebugFlag", func(t *testing.T) {
+		stream := get(t, metadata.Pairs("x-debug-cache", "1"))
+
+		assert.Equal(t, []string{"true"}, stream.trailer.Get("x-cache-hit"))
+		assert.Equal(t, []string{"cache"}, stream.trailer.Get("x-cache-source"))
+		assert.Equal(t, []string{"1500"}, stream.trailer.Get("x-cache-age-ms"))
+		assert.Equal(t, []string{"240000"}, stream.trailer.Get("x-cache-ttl-ms"))
+	})
+
+	t.Run("WithoutDebugFlag", func(t *testing.T) {
+		stream := get(t, metadata.Pairs("x-request-id", "abc"))
+
+		assert.Empty(t, stream.trailer, "normal clients get no trailers")
+	})
+}
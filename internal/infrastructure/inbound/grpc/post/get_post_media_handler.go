@@ -0,0 +1,68 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/post/mapper"
+)
+
+type PostMediaGetter interface {
+	GetPostMedia(ctx context.Context, postID int64, requesterID *int64, limit, offset int) ([]*model.PostMedia, int, error)
+}
+
+type GetPostMediaHandler struct {
+	postService PostMediaGetter
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewGetPostMediaHandler(postService PostMediaGetter, validate *validator.Validate, log ports.Logger) *GetPostMediaHandler {
+	return &GetPostMediaHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type GetPostMediaRequestInternal struct {
+	PostID int64 `validate:"required,gt=0"`
+	Limit  int   `validate:"gte=0,lte=100"`
+	Offset int   `validate:"gte=0"`
+}
+
+// GetPostMediaResponse has the shape of the planned GetPostMedia response message.
+type GetPostMediaResponse struct {
+	Media []*pb.Media
+	Total int
+}
+
+// GetPostMedia pages through the media of a post past those GetPost carries (see
+// GetPostResponse.HasMoreMedia), ordered by position. It is not exposed on the wire until the
+// proto definitions gain the RPC.
+func (h *GetPostMediaHandler) GetPostMedia(ctx context.Context, postID int64, limit, offset int) (*GetPostMediaResponse, error) {
+	h.log.Debug("Handling GetPostMedia request", slog.Int64("post_id", postID), slog.Int("limit", limit), slog.Int("offset", offset))
+
+	validationReq := &GetPostMediaRequestInternal{
+		PostID: postID,
+		Limit:  limit,
+		Offset: offset,
+	}
+	if err := h.validate.Struct(validationReq); err != nil {
+		h.log.Debug("GetPostMedia validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	media, total, err := h.postService.GetPostMedia(ctx, postID, requesterIDFromContext(ctx), limit, offset)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to get post media", slog.Int64("post_id", postID))
+	}
+
+	h.log.Debug("Post media retrieved successfully", slog.Int64("post_id", postID), slog.Int("count", len(media)), slog.Int("total", total))
+	return &GetPostMediaResponse{Media: mapper.MediaToProto(media), Total: total}, nil
+}
@@ -0,0 +1,81 @@
+package post_grpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestGetPostMediaHandler_GetPostMedia(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostMediaHandler(mockPostService, validate, testLogger)
+		media := []*model.PostMedia{
+			{ID: 21, PostID: 3, URL: "https://media.pinstack.io/21.jpg", Type: model.MediaTypeImage, Position: 21},
+			{ID: 22, PostID: 3, URL: "https://media.pinstack.io/22.jpg", Type: model.MediaTypeImage, Position: 22},
+		}
+		requesterID := int64(7)
+		mockPostService.On("GetPostMedia", mock.Anything, int64(3), &requesterID, 20, 20).Return(media, 22, nil)
+
+		resp, err := handler.GetPostMedia(userContext("7"), 3, 20, 20)
+
+		require.NoError(t, err)
+		assert.Equal(t, 22, resp.Total)
+		require.Len(t, resp.Media, 2)
+		assert.Equal(t, int64(21), resp.Media[0].Id)
+		assert.Equal(t, int32(22), resp.Media[1].Position)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("LimitAbovePageSize", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostMediaHandler(mockPostService, validate, testLogger)
+
+		_, err := handler.GetPostMedia(context.Background(), 3, 101, 0)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
+		mockPostService.AssertNotCalled(t, "GetPostMedia", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{name: "NotFound", err: custom_errors.ErrPostNotFound, code: codes.NotFound, reason: errinfo.ReasonPostNotFound},
+		{name: "Hidden", err: custom_errors.ErrForbidden, code: codes.PermissionDenied, reason: errinfo.ReasonNotAuthor},
+		{name: "QueryFailed", err: custom_errors.ErrMediaQueryFailed, code: codes.Internal, reason: errinfo.ReasonMediaQueryFailed},
+		{name: "InternalError", err: errors.New("db down"), code: codes.Internal, reason: errinfo.ReasonInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewGetPostMediaHandler(mockPostService, validate, testLogger)
+			mockPostService.On("GetPostMedia", mock.Anything, int64(3), (*int64)(nil), 0, 0).Return(nil, 0, tt.err)
+
+			_, err := handler.GetPostMedia(context.Background(), 3, 0, 0)
+
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.reason, errinfo.Reason(err))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type PostRevisionsGetter interface {
+	GetPostRevisions(ctx context.Context, userID int64, postID int64, limit, offset int) ([]*model.PostRevision, int, error)
+}
+
+type GetPostRevisionsHandler struct {
+	postService PostRevisionsGetter
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewGetPostRevisionsHandler(postService PostRevisionsGetter, validate *validator.Validate, log ports.Logger) *GetPostRevisionsHandler {
+	return &GetPostRevisionsHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type GetPostRevisionsRequestInternal struct {
+	PostID int64 `validate:"required,gt=0"`
+	UserID int64 `validate:"required,gt=0"`
+	Limit  int   `validate:"gte=0"`
+	Offset int   `validate:"gte=0"`
+}
+
+// GetPostRevisionsResponse has the shape of the planned GetPostRevisions response message.
+type GetPostRevisionsResponse struct {
+	Revisions []*model.PostRevision
+	Total     int
+}
+
+// GetPostRevisions lists the earlier titles and contents of a post, newest first, to its
+// author. It is not exposed on the wire until the proto definitions gain the RPC.
+func (h *GetPostRevisionsHandler) GetPostRevisions(ctx context.Context, userID int64, postID int64, limit, offset int) (*GetPostRevisionsResponse, error) {
+	h.log.Debug("Handling GetPostRevisions request", slog.Int64("post_id", postID), slog.Int64("user_id", userID))
+
+	validationReq := &GetPostRevisionsRequestInternal{
+		PostID: postID,
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	}
+	if err := h.validate.Struct(validationReq); err != nil {
+		h.log.Debug("GetPostRevisions validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	revisions, total, err := h.postService.GetPostRevisions(ctx, userID, postID, limit, offset)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to get post revisions", slog.Int64("post_id", postID))
+	}
+
+	h.log.Debug("Post revisions retrieved successfully", slog.Int64("post_id", postID), slog.Int("count", len(revisions)), slog.Int("total", total))
+	return &GetPostRevisionsResponse{Revisions: revisions, Total: total}, nil
+}
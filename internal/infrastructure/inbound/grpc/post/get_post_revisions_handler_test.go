@@ -0,0 +1,70 @@
+package post_grpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestGetPostRevisionsHandler_GetPostRevisions(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostRevisionsHandler(mockPostService, validate, testLogger)
+		revisions := []*model.PostRevision{{PostID: 3, Revision: 2, Title: "Second"}, {PostID: 3, Revision: 1, Title: "First"}}
+		mockPostService.On("GetPostRevisions", mock.Anything, int64(7), int64(3), 10, 0).Return(revisions, 2, nil)
+
+		resp, err := handler.GetPostRevisions(context.Background(), 7, 3, 10, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, &post_grpc.GetPostRevisionsResponse{Revisions: revisions, Total: 2}, resp)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostRevisionsHandler(mockPostService, validate, testLogger)
+
+		_, err := handler.GetPostRevisions(context.Background(), 7, 3, 10, -1)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "GetPostRevisions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{name: "InvalidPage", err: custom_errors.ErrInvalidInput, code: codes.InvalidArgument},
+		{name: "NotFound", err: custom_errors.ErrPostNotFound, code: codes.NotFound},
+		{name: "NotAuthor", err: custom_errors.ErrForbidden, code: codes.PermissionDenied},
+		{name: "InternalError", err: errors.New("db down"), code: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewGetPostRevisionsHandler(mockPostService, validate, testLogger)
+			mockPostService.On("GetPostRevisions", mock.Anything, int64(7), int64(3), 10, 0).Return(nil, 0, tt.err)
+
+			_, err := handler.GetPostRevisions(context.Background(), 7, 3, 10, 0)
+
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type PostTagsGetter interface {
+	GetPostTags(ctx context.Context, postID int64, requesterID *int64, includeCounts bool) ([]*model.Tag, error)
+}
+
+type GetPostTagsHandler struct {
+	postService PostTagsGetter
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewGetPostTagsHandler(postService PostTagsGetter, validate *validator.Validate, log ports.Logger) *GetPostTagsHandler {
+	return &GetPostTagsHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type GetPostTagsRequestInternal struct {
+	PostID int64 `validate:"required,gt=0"`
+}
+
+// GetPostTags is not exposed on the wire until the proto definitions gain a GetPostTags RPC
+// and a Tag message with a post count.
+func (h *GetPostTagsHandler) GetPostTags(ctx context.Context, postID int64, includeCounts bool) ([]*model.Tag, error) {
+	h.log.Debug("Handling GetPostTags request", slog.Int64("post_id", postID), slog.Bool("include_counts", includeCounts))
+
+	if err := h.validate.Struct(&GetPostTagsRequestInternal{PostID: postID}); err != nil {
+		h.log.Debug("GetPostTags validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	tags, err := h.postService.GetPostTags(ctx, postID, requesterIDFromContext(ctx), includeCounts)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to get post tags", slog.Int64("post_id", postID))
+	}
+
+	h.log.Debug("Post tags retrieved successfully", slog.Int64("post_id", postID), slog.Int("count", len(tags)))
+	return tags, nil
+}
@@ -0,0 +1,88 @@
+package post_grpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestGetPostTagsHandler_GetPostTags(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostTagsHandler(mockPostService, validate, testLogger)
+
+		requesterID := int64(7)
+		count := int64(3)
+		tags := []*model.Tag{{ID: 1, Name: "go", PostCount: &count}}
+		mockPostService.On("GetPostTags", mock.Anything, int64(10), &requesterID, true).Return(tags, nil)
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "7"))
+		resp, err := handler.GetPostTags(ctx, 10, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, tags, resp)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("EmptyList", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostTagsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("GetPostTags", mock.Anything, int64(10), (*int64)(nil), false).Return([]*model.Tag{}, nil)
+
+		resp, err := handler.GetPostTags(context.Background(), 10, false)
+
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+		assert.Empty(t, resp)
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostTagsHandler(mockPostService, validate, testLogger)
+
+		_, err := handler.GetPostTags(context.Background(), 0, false)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "GetPostTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("PostNotFound", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostTagsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("GetPostTags", mock.Anything, int64(10), (*int64)(nil), false).Return(nil, custom_errors.ErrPostNotFound)
+
+		_, err := handler.GetPostTags(context.Background(), 10, false)
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("InternalError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostTagsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("GetPostTags", mock.Anything, int64(10), (*int64)(nil), false).Return(nil, errors.New("boom"))
+
+		_, err := handler.GetPostTags(context.Background(), 10, false)
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
@@ -0,0 +1,83 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type PostsByIDsGetter interface {
+	GetPostsByIDs(ctx context.Context, ids []int64) ([]*model.PostDetailed, error)
+}
+
+type GetPostsByIDsHandler struct {
+	postService PostsByIDsGetter
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewGetPostsByIDsHandler(postService PostsByIDsGetter, validate *validator.Validate, log ports.Logger) *GetPostsByIDsHandler {
+	return &GetPostsByIDsHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type GetPostsByIDsRequestInternal struct {
+	IDs []int64 `validate:"required,min=1,max=100,dive,gt=0"`
+}
+
+// GetPostsByIDsResponse has the shape of the GetPostsByIDs response message: the posts found,
+// in request order, and the requested ids that have no published post.
+type GetPostsByIDsResponse struct {
+	Posts      []*pb.Post
+	MissingIDs []int64
+}
+
+// GetPostsByIDs hydrates up to 100 posts in one call for internal services that already hold
+// post ids. Duplicated ids are answered once. It is not exposed on the wire until the proto
+// definitions gain the RPC.
+func (h *GetPostsByIDsHandler) GetPostsByIDs(ctx context.Context, ids []int64) (*GetPostsByIDsResponse, error) {
+	h.log.Debug("Handling GetPostsByIDs request", slog.Int("ids_count", len(ids)))
+
+	if err := h.validate.Struct(&GetPostsByIDsRequestInternal{IDs: ids}); err != nil {
+		h.log.Debug("GetPostsByIDs validation failed", slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	posts, err := h.postService.GetPostsByIDs(ctx, ids)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to get posts by ids")
+	}
+
+	pbPosts, err := postsResponse(h.log, posts)
+	if err != nil {
+		return nil, err
+	}
+	resp := &GetPostsByIDsResponse{
+		Posts:      pbPosts,
+		MissingIDs: []int64{},
+	}
+	returned := make(map[int64]bool, len(posts))
+	for _, post := range pbPosts {
+		returned[post.Id] = true
+	}
+	for _, id := range ids {
+		if !returned[id] {
+			resp.MissingIDs = append(resp.MissingIDs, id)
+			returned[id] = true
+		}
+	}
+
+	h.log.Debug("Posts retrieved successfully",
+		slog.Int("requested", len(ids)),
+		slog.Int("found", len(resp.Posts)),
+		slog.Int("missing", len(resp.MissingIDs)))
+	return resp, nil
+}
@@ -0,0 +1,96 @@
+package post_grpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	model "pinstack-post-service/internal/domain/models"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetPostsByIDsHandler_GetPostsByIDs(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success reports missing ids", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewGetPostsByIDsHandler(mockPostService, validate, testLogger)
+
+		ids := []int64{7, 3, 9, 7, 5}
+		mockPostService.On("GetPostsByIDs", mock.Anything, ids).Return([]*model.PostDetailed{
+			{Post: &model.Post{ID: 7, AuthorID: 1, Title: "Seven"}, Tags: []*model.Tag{{ID: 1, Name: "go"}}},
+			{Post: &model.Post{ID: 5, AuthorID: 2, Title: "Five"}},
+		}, nil)
+
+		resp, err := handler.GetPostsByIDs(context.Background(), ids)
+
+		require.NoError(t, err)
+		require.Len(t, resp.Posts, 2)
+		assert.Equal(t, int64(7), resp.Posts[0].Id)
+		assert.Equal(t, []string{"go"}, resp.Posts[0].Tags)
+		assert.Equal(t, int64(5), resp.Posts[1].Id)
+		assert.Equal(t, []int64{3, 9}, resp.MissingIDs)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		tooMany := make([]int64, 101)
+		for i := range tooMany {
+			tooMany[i] = int64(i + 1)
+		}
+		for name, ids := range map[string][]int64{
+			"empty":        nil,
+			"too many":     tooMany,
+			"non-positive": {1, -2},
+		} {
+			t.Run(name, func(t *testing.T) {
+				mockPostService := new(mockpost.Service)
+				handler := post_grpc.NewGetPostsByIDsHandler(mockPostService, validate, testLogger)
+
+				resp, err := handler.GetPostsByIDs(context.Background(), ids)
+
+				assert.Nil(t, resp)
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+				mockPostService.AssertNotCalled(t, "GetPostsByIDs", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Service errors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			err      error
+			wantCode codes.Code
+		}{
+			{"user service unavailable", custom_errors.ErrExternalServiceError, codes.Unavailable},
+			{"database failure", errors.New("db error"), codes.Internal},
+			{"timeout", model.ErrTimeout, codes.DeadlineExceeded},
+			{"caller deadline", fmt.Errorf("%w: %w", model.ErrDeadlineExceeded, custom_errors.ErrDatabaseQuery), codes.DeadlineExceeded},
+			{"caller canceled", fmt.Errorf("%w: %w", model.ErrCanceled, custom_errors.ErrDatabaseQuery), codes.Canceled},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockPostService := new(mockpost.Service)
+				handler := post_grpc.NewGetPostsByIDsHandler(mockPostService, validate, testLogger)
+				mockPostService.On("GetPostsByIDs", mock.Anything, []int64{1}).Return(nil, tt.err)
+
+				resp, err := handler.GetPostsByIDs(context.Background(), []int64{1})
+
+				assert.Nil(t, resp)
+				assert.Equal(t, tt.wantCode, status.Code(err))
+			})
+		}
+	})
+}
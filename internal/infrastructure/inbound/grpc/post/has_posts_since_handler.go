@@ -0,0 +1,73 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/post/mapper"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type TagActivityChecker interface {
+	HasPostsSince(ctx context.Context, tagNames []string, since time.Time) (*model.TagActivity, error)
+}
+
+type HasPostsSinceHandler struct {
+	postService TagActivityChecker
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewHasPostsSinceHandler(postService TagActivityChecker, validate *validator.Validate, log ports.Logger) *HasPostsSinceHandler {
+	return &HasPostsSinceHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type HasPostsSinceRequestInternal struct {
+	TagNames []string `validate:"required,min=1,max=50,dive,required"`
+}
+
+// HasPostsSinceResponse has the shape of the HasPostsSince response message. Newest is nil
+// when no tag has new posts.
+type HasPostsSinceResponse struct {
+	HasPosts map[string]bool
+	Newest   *timestamppb.Timestamp
+	Cached   bool
+}
+
+// HasPostsSince tells a client polling the tags a user follows which of them have posts
+// published after since, without listing them. It is not exposed on the wire until the proto
+// definitions gain the RPC.
+func (h *HasPostsSinceHandler) HasPostsSince(ctx context.Context, tagNames []string, since *timestamppb.Timestamp) (*HasPostsSinceResponse, error) {
+	h.log.Debug("Handling HasPostsSince request", slog.Int("tag_names_count", len(tagNames)))
+
+	if err := h.validate.Struct(&HasPostsSinceRequestInternal{TagNames: tagNames}); err != nil {
+		h.log.Debug("HasPostsSince validation failed", slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+	sinceTime, err := mapper.TimestampFromProto(since)
+	if err != nil || sinceTime == nil {
+		h.log.Debug("HasPostsSince has a missing or invalid since")
+		return nil, invalidRequest("invalid request")
+	}
+
+	result, err := h.postService.HasPostsSince(ctx, tagNames, sinceTime.Time)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to check tags for new posts", slog.Int("tag_names_count", len(tagNames)))
+	}
+
+	resp := &HasPostsSinceResponse{HasPosts: result.HasPosts, Cached: result.Cached}
+	if !result.Newest.IsZero() {
+		resp.Newest = timestamppb.New(result.Newest)
+	}
+	return resp, nil
+}
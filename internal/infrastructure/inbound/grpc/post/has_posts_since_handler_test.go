@@ -0,0 +1,108 @@
+package post_grpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/testsupport"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestHasPostsSinceHandler_HasPostsSince(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	since := testsupport.FixedTime
+	newest := since.Add(time.Minute)
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewHasPostsSinceHandler(mockPostService, validate, testLogger)
+		mockPostService.On("HasPostsSince", mock.Anything, []string{"go", "rust"}, since).
+			Return(&model.TagActivity{HasPosts: map[string]bool{"go": true, "rust": false}, Newest: newest, Cached: true}, nil)
+
+		resp, err := handler.HasPostsSince(context.Background(), []string{"go", "rust"}, timestamppb.New(since))
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{"go": true, "rust": false}, resp.HasPosts)
+		assert.True(t, resp.Newest.AsTime().Equal(newest))
+		assert.True(t, resp.Cached)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("NoNewPostsHasNoNewest", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewHasPostsSinceHandler(mockPostService, validate, testLogger)
+		mockPostService.On("HasPostsSince", mock.Anything, []string{"go"}, since).
+			Return(&model.TagActivity{HasPosts: map[string]bool{"go": false}}, nil)
+
+		resp, err := handler.HasPostsSince(context.Background(), []string{"go"}, timestamppb.New(since))
+
+		require.NoError(t, err)
+		assert.Nil(t, resp.Newest)
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		tooMany := make([]string, model.MaxPolledTags+1)
+		for i := range tooMany {
+			tooMany[i] = "tag"
+		}
+		tests := []struct {
+			name     string
+			tagNames []string
+			since    *timestamppb.Timestamp
+		}{
+			{name: "no tags", since: timestamppb.New(since)},
+			{name: "too many tags", tagNames: tooMany, since: timestamppb.New(since)},
+			{name: "empty tag", tagNames: []string{"go", ""}, since: timestamppb.New(since)},
+			{name: "no since", tagNames: []string{"go"}},
+			{name: "invalid since", tagNames: []string{"go"}, since: &timestamppb.Timestamp{Nanos: -1}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockPostService := new(mockpost.Service)
+				handler := post_grpc.NewHasPostsSinceHandler(mockPostService, validate, testLogger)
+
+				_, err := handler.HasPostsSince(context.Background(), tt.tagNames, tt.since)
+
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+				mockPostService.AssertNotCalled(t, "HasPostsSince", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("ServiceErrors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			err  error
+			want codes.Code
+		}{
+			{name: "invalid input", err: custom_errors.ErrInvalidInput, want: codes.InvalidArgument},
+			{name: "internal", err: errors.New("db down"), want: codes.Internal},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockPostService := new(mockpost.Service)
+				handler := post_grpc.NewHasPostsSinceHandler(mockPostService, validate, testLogger)
+				mockPostService.On("HasPostsSince", mock.Anything, []string{"go"}, since).Return(nil, tt.err)
+
+				_, err := handler.HasPostsSince(context.Background(), []string{"go"}, timestamppb.New(since))
+
+				assert.Equal(t, tt.want, status.Code(err))
+			})
+		}
+	})
+}
@@ -3,15 +3,15 @@ package post_grpc
 import (
 	"context"
 	"log/slog"
+	"time"
+
 	ports "pinstack-post-service/internal/domain/ports/output"
 
 	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/post/mapper"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/jackc/pgx/v5/pgtype"
 	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -35,71 +35,219 @@ func NewListPostsHandler(postService PostLister, validate *validator.Validate, l
 }
 
 type ListPostsRequestInternal struct {
-	AuthorID *int64 `validate:"omitempty,gt=0"`
-	Offset   *int   `validate:"omitempty,gte=0"`
-	Limit    *int   `validate:"omitempty,gt=0,lte=100"`
+	AuthorID  *int64  `validate:"omitempty,gt=0"`
+	AuthorIDs []int64 `validate:"omitempty,dive,gt=0"`
+	Offset    *int    `validate:"omitempty,gte=0"`
+	Limit     *int    `validate:"omitempty,gt=0"`
+	SortBy    string  `validate:"omitempty,oneof=created_at updated_at"`
+	SortOrder string  `validate:"omitempty,oneof=asc desc"`
+	View      string  `validate:"omitempty,oneof=full summary"`
+	Language  string  `validate:"omitempty,bcp47_language_tag"`
+}
+
+// ListedPost is a post of a list with the has_more_content flag of the summary view,
+// whether the post is pinned, which only leads a list of one author's posts, and the client
+// it was created from.
+type ListedPost struct {
+	Post           *pb.Post
+	HasMoreContent bool
+	IsPinned       bool
+	Source         model.PostSource
+}
+
+// ListPostsViewResponse has the shape of a ListPostsResponse whose posts carry
+// has_more_content and is_pinned.
+type ListPostsViewResponse struct {
+	Posts []*ListedPost
+	Total int64
 }
 
 func (h *ListPostsHandler) ListPosts(ctx context.Context, req *pb.ListPostsRequest) (*pb.ListPostsResponse, error) {
+	return h.listPosts(ctx, req, "", "", nil)
+}
+
+// ListPostsView is ListPosts in view "full" or "summary"; empty means "full". Summaries cut
+// content to an excerpt on a word boundary, flag the cut with HasMoreContent and keep only
+// the first media item, which keeps list pages of long posts small.
+// ListPostsRequest has no view field in proto v0.1.22, so this is not exposed over gRPC yet.
+func (h *ListPostsHandler) ListPostsView(ctx context.Context, req *pb.ListPostsRequest, view string) (*ListPostsViewResponse, error) {
+	if err := h.validate.Struct(&ListPostsRequestInternal{View: view}); err != nil {
+		h.log.Debug("ListPosts view validation failed", slog.String("view", view), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+	filters, err := h.filters(ctx, req, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	filters.View = model.PostView(view)
+
+	posts, total, err := h.fetch(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	pbPosts, err := postsResponse(h.log, posts)
+	if err != nil {
+		return nil, err
+	}
+	listed := make([]*ListedPost, len(posts))
+	for i, post := range posts {
+		listed[i] = &ListedPost{Post: pbPosts[i], HasMoreContent: post.HasMoreContent, IsPinned: post.Post.IsPinned(), Source: post.Post.Source}
+	}
+	return &ListPostsViewResponse{Posts: listed, Total: int64(total)}, nil
+}
+
+// ListPostsByMedia is ListPosts restricted by media: hasMedia true keeps posts with media,
+// false posts without, and a non-empty mediaType ("image" or "video") keeps posts with at
+// least one item of that type; nil and empty leave the filter out.
+// ListPostsRequest has no media filter fields in proto v0.1.22, so this is not exposed over gRPC yet.
+func (h *ListPostsHandler) ListPostsByMedia(
+	ctx context.Context,
+	req *pb.ListPostsRequest,
+	hasMedia *bool,
+	mediaType string,
+) (*pb.ListPostsResponse, error) {
+	var filterType *model.MediaType
+	if mediaType != "" {
+		t, err := model.ParseMediaType(mediaType)
+		if err != nil {
+			h.log.Debug("ListPosts media type validation failed", slog.String("media_type", mediaType), slog.String("error", err.Error()))
+			return nil, invalidRequest(err.Error())
+		}
+		filterType = &t
+	}
+	filters, err := h.filters(ctx, req, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	filters.HasMedia = hasMedia
+	filters.MediaType = filterType
+	return h.list(ctx, filters)
+}
+
+// ListPostsByLanguage is ListPosts restricted to posts written in language, a BCP-47 tag
+// matched exactly after canonicalization: "en" does not match "en-US", and posts without a
+// language never match. An empty language leaves the filter out.
+// ListPostsRequest has no language field in proto v0.1.22, so this is not exposed over gRPC yet.
+func (h *ListPostsHandler) ListPostsByLanguage(ctx context.Context, req *pb.ListPostsRequest, language string) (*pb.ListPostsResponse, error) {
+	if err := h.validate.Struct(&ListPostsRequestInternal{Language: language}); err != nil {
+		h.log.Debug("ListPosts language validation failed", slog.String("language", language), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+	filters, err := h.filters(ctx, req, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if language != "" {
+		filters.Language = &language
+	}
+	return h.list(ctx, filters)
+}
+
+// ListPostsBySource is ListPosts restricted to posts created from source, one of the clients
+// the server records or "unknown"; an empty source leaves the filter out.
+// ListPostsRequest has no source field in proto v0.1.22, so this is not exposed over gRPC yet.
+func (h *ListPostsHandler) ListPostsBySource(ctx context.Context, req *pb.ListPostsRequest, source string) (*pb.ListPostsResponse, error) {
+	var filterSource *model.PostSource
+	if source != "" {
+		s, err := model.ParsePostSource(source)
+		if err != nil {
+			h.log.Debug("ListPosts source validation failed", slog.String("source", source), slog.String("error", err.Error()))
+			return nil, invalidRequest("source " + err.Error())
+		}
+		filterSource = &s
+	}
+	filters, err := h.filters(ctx, req, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	filters.Source = filterSource
+	return h.list(ctx, filters)
+}
+
+// ListPostsUpdatedSince is ListPosts restricted to posts changed strictly after updatedAfter,
+// for clients that sync incrementally. It combines with the created_after and created_before
+// filters of req; updatedAfter must not be in the future.
+// ListPostsRequest has no updated_after field in proto v0.1.22, so this is not exposed over gRPC yet.
+func (h *ListPostsHandler) ListPostsUpdatedSince(
+	ctx context.Context,
+	req *pb.ListPostsRequest,
+	updatedAfter *timestamppb.Timestamp,
+) (*pb.ListPostsResponse, error) {
+	return h.listPosts(ctx, req, "", "", updatedAfter)
+}
+
+// ListPostsSorted is ListPosts ordered by sortBy ("created_at" or "updated_at") in sortOrder
+// ("asc" or "desc"); empty values keep the default of newest first.
+// ListPostsRequest has no sort fields in proto v0.1.22, so this is not exposed over gRPC yet.
+func (h *ListPostsHandler) ListPostsSorted(
+	ctx context.Context,
+	req *pb.ListPostsRequest,
+	sortBy, sortOrder string,
+) (*pb.ListPostsResponse, error) {
+	return h.listPosts(ctx, req, sortBy, sortOrder, nil)
+}
+
+func (h *ListPostsHandler) listPosts(
+	ctx context.Context,
+	req *pb.ListPostsRequest,
+	sortBy, sortOrder string,
+	updatedAfter *timestamppb.Timestamp,
+) (*pb.ListPostsResponse, error) {
+	filters, err := h.filters(ctx, req, sortBy, sortOrder, updatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	return h.list(ctx, filters)
+}
+
+// filters validates req and turns it into post filters; a failure is already a gRPC status.
+func (h *ListPostsHandler) filters(
+	ctx context.Context,
+	req *pb.ListPostsRequest,
+	sortBy, sortOrder string,
+	updatedAfter *timestamppb.Timestamp,
+) (*model.PostFilters, error) {
 	h.log.Debug("Handling ListPosts request",
 		slog.Int64("author_id", req.GetAuthorId()),
 		slog.Int("limit", int(req.GetLimit())),
 		slog.Int("offset", int(req.GetOffset())),
-		slog.Int("tag_names_count", len(req.GetTagNames())))
+		slog.Int("tag_names_count", len(req.GetTagNames())),
+		slog.String("sort_by", sortBy),
+		slog.String("sort_order", sortOrder))
 
-	var authorIDPtr *int64
-	if req.AuthorId != 0 {
-		authorIDPtr = &req.AuthorId
-	}
-	var offsetPtr *int
-	if req.Offset != 0 {
-		offset := int(req.Offset)
-		offsetPtr = &offset
+	filters, err := mapper.ListPostsRequestToFilters(req)
+	if err != nil {
+		h.log.Debug("ListPosts has an invalid timestamp", slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
 	}
-	var limitPtr *int
-	if req.Limit != 0 {
-		limit := int(req.Limit)
-		limitPtr = &limit
+	updatedSince, err := mapper.TimestampFromProto(updatedAfter)
+	if err != nil {
+		h.log.Debug("ListPosts has an invalid updated_after", slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
 	}
 
 	validationReq := &ListPostsRequestInternal{
-		AuthorID: authorIDPtr,
-		Offset:   offsetPtr,
-		Limit:    limitPtr,
+		AuthorID:  filters.AuthorID,
+		Offset:    filters.Offset,
+		Limit:     filters.Limit,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
 	}
 
 	if err := h.validate.Struct(validationReq); err != nil {
 		h.log.Debug("ListPosts validation failed",
 			slog.String("error", err.Error()))
-		return nil, status.Error(codes.InvalidArgument, "invalid request")
+		return nil, invalidRequest("invalid request")
 	}
-
-	h.log.Debug("Building post filters")
-	filters := &model.PostFilters{
-		AuthorID: authorIDPtr,
-		Limit:    limitPtr,
-		Offset:   offsetPtr,
+	if updatedSince != nil && updatedSince.Time.After(time.Now()) {
+		h.log.Debug("ListPosts updated_after is in the future", slog.Time("updated_after", updatedSince.Time))
+		return nil, invalidRequest("updated_after is in the future")
 	}
 
-	if req.CreatedAfter != nil {
-		createdAfter := pgtype.Timestamptz{
-			Time:  req.CreatedAfter.AsTime(),
-			Valid: true,
-		}
-		filters.CreatedAfter = &createdAfter
-	}
-
-	if req.CreatedBefore != nil {
-		createdBefore := pgtype.Timestamptz{
-			Time:  req.CreatedBefore.AsTime(),
-			Valid: true,
-		}
-		filters.CreatedBefore = &createdBefore
-	}
-
-	if len(req.TagNames) > 0 {
-		filters.TagNames = req.TagNames
-	}
+	filters.RequesterID = requesterIDFromContext(ctx)
+	filters.SortBy = model.PostSortField(sortBy)
+	filters.SortOrder = model.SortOrder(sortOrder)
+	filters.UpdatedAfter = updatedSince
 
 	h.log.Debug("Fetching posts with filters",
 		slog.Any("author_id", filters.AuthorID),
@@ -107,69 +255,50 @@ func (h *ListPostsHandler) ListPosts(ctx context.Context, req *pb.ListPostsReque
 		slog.Any("offset", filters.Offset),
 		slog.Int("tag_names_count", len(filters.TagNames)))
 
-	posts, total, err := h.postService.ListPosts(ctx, filters)
-	if err != nil {
-		h.log.Error("Failed to list posts", slog.String("error", err.Error()))
-		return nil, status.Error(codes.Internal, "failed to list posts")
+	return filters, nil
+}
+
+// ListFeed lists the posts of any of authorIDs, newest first, for feeds that follow many authors.
+// ListPostsRequest has no repeated author field in proto v0.1.22, so this is not exposed over gRPC yet.
+func (h *ListPostsHandler) ListFeed(ctx context.Context, authorIDs []int64, limit, offset int) (*pb.ListPostsResponse, error) {
+	h.log.Debug("Handling ListFeed request",
+		slog.Int("author_ids_count", len(authorIDs)),
+		slog.Int("limit", limit),
+		slog.Int("offset", offset))
+
+	filters := &model.PostFilters{
+		AuthorIDs:   authorIDs,
+		RequesterID: requesterIDFromContext(ctx),
+	}
+	if limit != 0 {
+		filters.Limit = &limit
+	}
+	if offset != 0 {
+		filters.Offset = &offset
 	}
 
-	pbPosts := make([]*pb.Post, len(posts))
-	for i, post := range posts {
-		var pbMedia []*pb.Media
-		if post.Media != nil {
-			pbMedia = make([]*pb.Media, len(post.Media))
-			for j, m := range post.Media {
-				var mediaCreatedAtPb *timestamppb.Timestamp
-				if m.CreatedAt.Valid {
-					mediaCreatedAtPb = timestamppb.New(m.CreatedAt.Time)
-				}
-				pbMedia[j] = &pb.Media{
-					Id:        m.ID,
-					Url:       m.URL,
-					Type:      string(m.Type),
-					Position:  m.Position,
-					CreatedAt: mediaCreatedAtPb,
-				}
-			}
-		}
+	validationReq := &ListPostsRequestInternal{
+		AuthorIDs: authorIDs,
+		Offset:    filters.Offset,
+		Limit:     filters.Limit,
+	}
+	if err := h.validate.Struct(validationReq); err != nil {
+		h.log.Debug("ListFeed validation failed", slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
 
-		var postID int64
-		var authorID int64
-		var title string
-		var content string
-		var createdAtPb *timestamppb.Timestamp
-		var updatedAtPb *timestamppb.Timestamp
-
-		if post.Post != nil {
-			postID = post.Post.ID
-			authorID = post.Post.AuthorID
-			title = post.Post.Title
-			if post.Post.Content != nil {
-				content = *post.Post.Content
-			}
-			if post.Post.CreatedAt.Valid {
-				createdAtPb = timestamppb.New(post.Post.CreatedAt.Time)
-			}
-			if post.Post.UpdatedAt.Valid {
-				updatedAtPb = timestamppb.New(post.Post.UpdatedAt.Time)
-			}
-		}
+	return h.list(ctx, filters)
+}
 
-		pbTags := make([]string, len(post.Tags))
-		for k, t := range post.Tags {
-			pbTags[k] = t.Name
-		}
+func (h *ListPostsHandler) list(ctx context.Context, filters *model.PostFilters) (*pb.ListPostsResponse, error) {
+	posts, total, err := h.fetch(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
 
-		pbPosts[i] = &pb.Post{
-			Id:        postID,
-			AuthorId:  authorID,
-			Title:     title,
-			Content:   content,
-			Tags:      pbTags,
-			Media:     pbMedia,
-			CreatedAt: createdAtPb,
-			UpdatedAt: updatedAtPb,
-		}
+	pbPosts, err := postsResponse(h.log, posts)
+	if err != nil {
+		return nil, err
 	}
 
 	resp := &pb.ListPostsResponse{
@@ -183,3 +312,15 @@ func (h *ListPostsHandler) ListPosts(ctx context.Context, req *pb.ListPostsReque
 
 	return resp, nil
 }
+
+// fetch lists posts and maps service errors to gRPC statuses.
+func (h *ListPostsHandler) fetch(ctx context.Context, filters *model.PostFilters) ([]*model.PostDetailed, int, error) {
+	serviceCtx, cacheInfo := withCacheDebug(ctx)
+	posts, total, err := h.postService.ListPosts(serviceCtx, filters)
+	if err != nil {
+		return nil, 0, serviceErrorStatus(h.log, err, "Failed to list posts")
+	}
+
+	setCacheDebugTrailer(ctx, h.log, cacheInfo)
+	return posts, total, nil
+}
@@ -3,20 +3,26 @@ package post_grpc_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
 	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
 	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/testsupport"
 	mockpost "pinstack-post-service/mocks/post"
 	"testing"
 	"time"
 
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+
 	"github.com/go-playground/validator/v10"
-	"github.com/jackc/pgx/v5/pgtype"
 	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -35,60 +41,23 @@ func TestListPostsHandler_ListPosts(t *testing.T) {
 			Offset:   20,
 		}
 
-		createdAt := time.Now()
-		updatedAt := time.Now()
-		content := "Test post content"
-
+		listed := func(id int64, title, mediaURL string, tags ...string) *model.PostDetailed {
+			return testsupport.NewPostDetailedBuilder().
+				WithID(id).
+				WithAuthor(123).
+				WithTitle(title).
+				WithContent("Test post content").
+				WithMedia(mediaURL).
+				WithTags(tags...).
+				WithoutAuthor().
+				Build()
+		}
 		expectedPosts := []*model.PostDetailed{
-			{
-				Post: &model.Post{
-					ID:        1,
-					AuthorID:  123,
-					Title:     "First Post",
-					Content:   &content,
-					CreatedAt: pgtype.Timestamp{Time: createdAt, Valid: true},
-					UpdatedAt: pgtype.Timestamp{Time: updatedAt, Valid: true},
-				},
-				Media: []*model.PostMedia{
-					{
-						ID:        1,
-						PostID:    1,
-						URL:       "https://example.com/image1.jpg",
-						Type:      "image",
-						Position:  1,
-						CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
-					},
-				},
-				Tags: []*model.Tag{
-					{ID: 1, Name: "tag1"},
-					{ID: 2, Name: "tag2"},
-				},
-			},
-			{
-				Post: &model.Post{
-					ID:        2,
-					AuthorID:  123,
-					Title:     "Second Post",
-					Content:   &content,
-					CreatedAt: pgtype.Timestamp{Time: createdAt, Valid: true},
-					UpdatedAt: pgtype.Timestamp{Time: updatedAt, Valid: true},
-				},
-				Media: []*model.PostMedia{
-					{
-						ID:        2,
-						PostID:    2,
-						URL:       "https://example.com/image2.jpg",
-						Type:      "image",
-						Position:  2,
-						CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
-					},
-				},
-				Tags: []*model.Tag{
-					{ID: 2, Name: "tag2"},
-					{ID: 3, Name: "tag3"},
-				},
-			},
+			listed(1, "First Post", "https://example.com/image1.jpg", "tag1", "tag2"),
+			listed(2, "Second Post", "https://example.com/image2.jpg", "tag2", "tag3"),
 		}
+		createdAt := expectedPosts[0].Post.CreatedAt.Time
+		updatedAt := expectedPosts[0].Post.UpdatedAt.Time
 
 		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
 			return filters != nil &&
@@ -200,7 +169,7 @@ func TestListPostsHandler_ListPosts(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
-		assert.Equal(t, "invalid request", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
 
 		mockPostService.AssertNotCalled(t, "ListPosts")
 	})
@@ -212,6 +181,10 @@ func TestListPostsHandler_ListPosts(t *testing.T) {
 		req := &pb.ListPostsRequest{
 			Limit: 500,
 		}
+		// The service owns the configurable maximum.
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(f *model.PostFilters) bool {
+			return f.Limit != nil && *f.Limit == 500
+		})).Return(nil, 0, fmt.Errorf("%w: limit must be between 1 and 100, got 500", custom_errors.ErrInvalidInput))
 
 		resp, err := handler.ListPosts(context.Background(), req)
 
@@ -221,9 +194,10 @@ func TestListPostsHandler_ListPosts(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
-		assert.Equal(t, "invalid request", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
+		assert.Contains(t, statusErr.Message(), "limit must be between 1 and 100")
 
-		mockPostService.AssertNotCalled(t, "ListPosts")
+		mockPostService.AssertExpectations(t)
 	})
 
 	t.Run("ServiceError", func(t *testing.T) {
@@ -246,7 +220,30 @@ func TestListPostsHandler_ListPosts(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Equal(t, "failed to list posts", statusErr.Message())
+		assert.Equal(t, errinfo.ReasonInternal, errinfo.Reason(err))
+
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("UserServiceError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		req := &pb.ListPostsRequest{
+			Limit: 10,
+		}
+
+		mockPostService.On("ListPosts", mock.Anything, mock.Anything).
+			Return(nil, 0, custom_errors.ErrExternalServiceError)
+
+		resp, err := handler.ListPosts(context.Background(), req)
+
+		assert.Nil(t, resp)
+		assert.Error(t, err)
+
+		statusErr, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.Unavailable, statusErr.Code())
 
 		mockPostService.AssertExpectations(t)
 	})
@@ -260,18 +257,12 @@ func TestListPostsHandler_ListPosts(t *testing.T) {
 		}
 
 		expectedPosts := []*model.PostDetailed{
-			{
-				Post: &model.Post{
-					ID:        1,
-					AuthorID:  123,
-					Title:     "Post with nullable fields",
-					Content:   nil,
-					CreatedAt: pgtype.Timestamp{Valid: false},
-					UpdatedAt: pgtype.Timestamp{Valid: false},
-				},
-				Media: nil,
-				Tags:  nil,
-			},
+			testsupport.NewPostDetailedBuilder().
+				WithAuthor(123).
+				WithTitle("Post with nullable fields").
+				Post(func(b *testsupport.PostBuilder) { b.WithoutTimestamps() }).
+				WithoutAuthor().
+				Build(),
 		}
 
 		mockPostService.On("ListPosts", mock.Anything, mock.Anything).Return(expectedPosts, len(expectedPosts), nil)
@@ -296,3 +287,381 @@ func TestListPostsHandler_ListPosts(t *testing.T) {
 		mockPostService.AssertExpectations(t)
 	})
 }
+
+func TestListPostsHandler_ListFeed(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		authorIDs := []int64{1, 2, 3}
+		posts := []*model.PostDetailed{
+			{Post: &model.Post{ID: 10, AuthorID: 2, Title: "Newer"}},
+			{Post: &model.Post{ID: 9, AuthorID: 1, Title: "Older"}},
+		}
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.AuthorID == nil &&
+				assert.ObjectsAreEqual(authorIDs, filters.AuthorIDs) &&
+				filters.Limit != nil && *filters.Limit == 20 &&
+				filters.Offset == nil
+		})).Return(posts, 2, nil)
+
+		resp, err := handler.ListFeed(context.Background(), authorIDs, 20, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Total)
+		require.Len(t, resp.Posts, 2)
+		assert.Equal(t, int64(10), resp.Posts[0].Id)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("ValidationError_NonPositiveAuthorID", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.ListFeed(context.Background(), []int64{1, 0}, 20, 0)
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "ListPosts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("TooManyAuthors", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		tooMany := fmt.Errorf("%w: at most 500 author ids, got 501", custom_errors.ErrInvalidInput)
+		mockPostService.On("ListPosts", mock.Anything, mock.Anything).Return(nil, 0, tooMany)
+
+		resp, err := handler.ListFeed(context.Background(), []int64{1}, 0, 0)
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertExpectations(t)
+	})
+}
+
+func TestListPostsHandler_ListPostsSorted(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("PassesSortToService", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.SortBy == model.SortByUpdatedAt && filters.SortOrder == model.SortAsc
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		resp, err := handler.ListPostsSorted(context.Background(), &pb.ListPostsRequest{Limit: 10}, "updated_at", "asc")
+
+		require.NoError(t, err)
+		assert.Empty(t, resp.Posts)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("DefaultsWhenEmpty", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.SortBy == "" && filters.SortOrder == ""
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		_, err := handler.ListPosts(context.Background(), &pb.ListPostsRequest{Limit: 10})
+
+		require.NoError(t, err)
+		mockPostService.AssertExpectations(t)
+	})
+
+	for _, tc := range []struct{ name, sortBy, sortOrder string }{
+		{"UnknownSortBy", "views", "desc"},
+		{"UnknownSortOrder", "created_at", "sideways"},
+		{"InjectedSortBy", "created_at; DROP TABLE posts", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+			resp, err := handler.ListPostsSorted(context.Background(), &pb.ListPostsRequest{}, tc.sortBy, tc.sortOrder)
+
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			mockPostService.AssertNotCalled(t, "ListPosts", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestListPostsHandler_ListPostsByMedia(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	hasMedia := true
+
+	t.Run("PassesMediaFiltersToService", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.HasMedia != nil && *filters.HasMedia &&
+				filters.MediaType != nil && *filters.MediaType == model.MediaTypeVideo
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		resp, err := handler.ListPostsByMedia(context.Background(), &pb.ListPostsRequest{Limit: 10}, &hasMedia, "video")
+
+		require.NoError(t, err)
+		assert.Empty(t, resp.Posts)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("EmptyLeavesTheFiltersOut", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.HasMedia == nil && filters.MediaType == nil
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		_, err := handler.ListPostsByMedia(context.Background(), &pb.ListPostsRequest{Limit: 10}, nil, "")
+
+		require.NoError(t, err)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("UnknownMediaType", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.ListPostsByMedia(context.Background(), &pb.ListPostsRequest{}, nil, "audio")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "ListPosts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("MediaTypeWithoutMedia", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+		noMedia := false
+		mockPostService.On("ListPosts", mock.Anything, mock.Anything).
+			Return(nil, 0, fmt.Errorf("%w: media_type needs posts with media", custom_errors.ErrInvalidInput))
+
+		resp, err := handler.ListPostsByMedia(context.Background(), &pb.ListPostsRequest{}, &noMedia, "image")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestListPostsHandler_ListPostsByLanguage(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("PassesLanguageToService", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.Language != nil && *filters.Language == "ru"
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		_, err := handler.ListPostsByLanguage(context.Background(), &pb.ListPostsRequest{Limit: 10}, "ru")
+
+		require.NoError(t, err)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("EmptyLeavesTheFilterOut", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.Language == nil
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		_, err := handler.ListPostsByLanguage(context.Background(), &pb.ListPostsRequest{Limit: 10}, "")
+
+		require.NoError(t, err)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("MalformedLanguage", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.ListPostsByLanguage(context.Background(), &pb.ListPostsRequest{}, "e")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "ListPosts", mock.Anything, mock.Anything)
+	})
+}
+
+func TestListPostsHandler_ListPostsBySource(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("PassesSourceToService", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.Source != nil && *filters.Source == model.PostSourceIOS
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		_, err := handler.ListPostsBySource(context.Background(), &pb.ListPostsRequest{Limit: 10}, " iOS ")
+
+		require.NoError(t, err)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("EmptyLeavesTheFilterOut", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.Source == nil
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		_, err := handler.ListPostsBySource(context.Background(), &pb.ListPostsRequest{Limit: 10}, "")
+
+		require.NoError(t, err)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("MalformedSource", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.ListPostsBySource(context.Background(), &pb.ListPostsRequest{}, "smart tv")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
+		mockPostService.AssertNotCalled(t, "ListPosts", mock.Anything, mock.Anything)
+	})
+}
+
+func TestListPostsHandler_ListPostsUpdatedSince(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("CombinesWithCreatedFilters", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+		since := time.Now().Add(-time.Hour).UTC()
+		createdAfter := since.Add(-24 * time.Hour)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.UpdatedAfter != nil && filters.UpdatedAfter.Time.Equal(since) &&
+				filters.CreatedAfter != nil && filters.CreatedAfter.Time.Equal(createdAfter)
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		req := &pb.ListPostsRequest{Limit: 10, CreatedAfter: timestamppb.New(createdAfter)}
+		resp, err := handler.ListPostsUpdatedSince(context.Background(), req, timestamppb.New(since))
+
+		require.NoError(t, err)
+		assert.Empty(t, resp.Posts)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("RejectsFutureTimestamp", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.ListPostsUpdatedSince(context.Background(), &pb.ListPostsRequest{},
+			timestamppb.New(time.Now().Add(time.Hour)))
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "ListPosts", mock.Anything, mock.Anything)
+	})
+}
+
+func TestListPostsHandler_ListPostsView(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Summary", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+		excerpt := "Hello"
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.View == model.PostViewSummary && filters.Limit != nil && *filters.Limit == 10
+		})).Return([]*model.PostDetailed{
+			{Post: &model.Post{ID: 1, AuthorID: 7, Title: "Long", Content: &excerpt}, HasMoreContent: true},
+			{Post: &model.Post{ID: 2, AuthorID: 7, Title: "Short"}},
+		}, 2, nil)
+
+		resp, err := handler.ListPostsView(context.Background(), &pb.ListPostsRequest{Limit: 10}, "summary")
+
+		require.NoError(t, err)
+		require.Len(t, resp.Posts, 2)
+		assert.Equal(t, int64(2), resp.Total)
+		assert.Equal(t, "Hello", resp.Posts[0].Post.Content)
+		assert.True(t, resp.Posts[0].HasMoreContent)
+		assert.False(t, resp.Posts[1].HasMoreContent)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("EmptyViewIsFull", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.MatchedBy(func(filters *model.PostFilters) bool {
+			return filters.View == ""
+		})).Return([]*model.PostDetailed{}, 0, nil)
+
+		resp, err := handler.ListPostsView(context.Background(), &pb.ListPostsRequest{}, "")
+
+		require.NoError(t, err)
+		assert.Empty(t, resp.Posts)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("InvalidView", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.ListPostsView(context.Background(), &pb.ListPostsRequest{}, "compact")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "ListPosts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ServiceError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewListPostsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("ListPosts", mock.Anything, mock.Anything).
+			Return(nil, 0, custom_errors.ErrDatabaseQuery)
+
+		resp, err := handler.ListPostsView(context.Background(), &pb.ListPostsRequest{}, "summary")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestListPostsHandler_ListPosts_CacheDebugTrailer(t *testing.T) {
+	mockPostService := new(mockpost.Service)
+	mockPostService.On("ListPosts", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			model.RecordCacheInfo(args.Get(0).(context.Context), model.CacheInfo{Hit: true, Source: model.CacheSourceDatabase})
+		}).
+		Return([]*model.PostDetailed{}, 0, nil)
+	handler := post_grpc.NewListPostsHandler(mockPostService, validator.New(), logger.New("test"))
+	stream := &headerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(
+		metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-debug-cache", "1")), stream)
+
+	_, err := handler.ListPosts(ctx, &pb.ListPostsRequest{Limit: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"true"}, stream.trailer.Get("x-cache-hit"))
+	assert.Equal(t, []string{"database"}, stream.trailer.Get("x-cache-source"))
+	assert.Empty(t, stream.trailer.Get("x-cache-age-ms"), "the posts of a list are not cache entries")
+}
@@ -0,0 +1,216 @@
+// Package mapper converts between the domain models and the post protobuf messages. It is the
+// one place that decides how missing data crosses the wire:
+//
+//   - a nil PostDetailed or one without a Post cannot be converted and fails with ErrNilPost;
+//   - a nil Author leaves nothing empty, since pb.Post only carries the author ID of the Post;
+//   - nil media and tag entries are skipped, and repeated fields are never nil;
+//   - timestamps that are unset or outside the range protobuf can represent become nil.
+//
+// Adding a field to a message should only need a change here.
+package mapper
+
+import (
+	"errors"
+
+	model "pinstack-post-service/internal/domain/models"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	MinMediaPosition = 1
+	MaxMediaPosition = 9
+)
+
+var (
+	// ErrNilPost is returned for a post without post data, which has no wire form.
+	ErrNilPost = errors.New("post has no post data")
+	// ErrInvalidTimestamp is returned for a request timestamp protobuf considers invalid.
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+)
+
+// PostDetailedToProto converts a post for the wire.
+func PostDetailedToProto(post *model.PostDetailed) (*pb.Post, error) {
+	if post == nil || post.Post == nil {
+		return nil, ErrNilPost
+	}
+	resp := &pb.Post{
+		Id:        post.Post.ID,
+		AuthorId:  post.Post.AuthorID,
+		Title:     post.Post.Title,
+		Tags:      TagNames(post.Tags),
+		Media:     MediaToProto(post.Media),
+		CreatedAt: TimestampToProto(post.Post.CreatedAt),
+		UpdatedAt: TimestampToProto(post.Post.UpdatedAt),
+	}
+	if post.Post.Content != nil {
+		resp.Content = *post.Post.Content
+	}
+	return resp, nil
+}
+
+// PostsToProto converts posts in order and fails on the first that cannot be converted.
+func PostsToProto(posts []*model.PostDetailed) ([]*pb.Post, error) {
+	resp := make([]*pb.Post, len(posts))
+	for i, post := range posts {
+		converted, err := PostDetailedToProto(post)
+		if err != nil {
+			return nil, err
+		}
+		resp[i] = converted
+	}
+	return resp, nil
+}
+
+// MediaToProto converts attachments for the wire. pb.Media has no fields for width, height,
+// size or alt text yet, so that metadata is stored but not sent.
+func MediaToProto(media []*model.PostMedia) []*pb.Media {
+	resp := make([]*pb.Media, 0, len(media))
+	for _, m := range media {
+		if m == nil {
+			continue
+		}
+		resp = append(resp, &pb.Media{
+			Id:        m.ID,
+			Url:       m.URL,
+			Type:      string(m.Type),
+			Position:  m.Position,
+			CreatedAt: TimestampToProto(m.CreatedAt),
+		})
+	}
+	return resp
+}
+
+// TagNames returns the names of tags.
+func TagNames(tags []*model.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t != nil {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// TimestampToProto converts ts, or returns nil when it is unset or out of protobuf's range.
+func TimestampToProto(ts pgtype.Timestamptz) *timestamppb.Timestamp {
+	if !ts.Valid {
+		return nil
+	}
+	resp := timestamppb.New(ts.Time)
+	if resp.CheckValid() != nil {
+		return nil
+	}
+	return resp
+}
+
+// TimestampFromProto converts an optional request timestamp: nil stays nil and an invalid
+// one fails with ErrInvalidTimestamp.
+func TimestampFromProto(ts *timestamppb.Timestamp) (*pgtype.Timestamptz, error) {
+	if ts == nil {
+		return nil, nil
+	}
+	if ts.CheckValid() != nil {
+		return nil, ErrInvalidTimestamp
+	}
+	return &pgtype.Timestamptz{Time: ts.AsTime(), Valid: true}, nil
+}
+
+// ProtoMediaInputToDTO converts requested attachments. A position outside
+// [MinMediaPosition, MaxMediaPosition] is replaced by the item's place in the list, and
+// items whose place is past MaxMediaPosition are dropped. Nil entries are skipped.
+// pb.MediaInput carries no media metadata yet, so it is left unset.
+func ProtoMediaInputToDTO(media []*pb.MediaInput) []*model.PostMediaInput {
+	resp := make([]*model.PostMediaInput, 0, len(media))
+	for i, m := range media {
+		if m == nil {
+			continue
+		}
+		position := m.GetPosition()
+		if position < MinMediaPosition || position > MaxMediaPosition {
+			position = int32(i + 1)
+			if position > MaxMediaPosition {
+				continue
+			}
+		}
+		resp = append(resp, &model.PostMediaInput{
+			URL:      m.GetUrl(),
+			Type:     model.MediaType(m.GetType()),
+			Position: position,
+		})
+	}
+	return resp
+}
+
+// CreatePostRequestToDTO converts a create request. Content is always set, possibly empty.
+func CreatePostRequestToDTO(req *pb.CreatePostRequest) *model.CreatePostDTO {
+	content := req.GetContent()
+	return &model.CreatePostDTO{
+		AuthorID:   req.GetAuthorId(),
+		Title:      req.GetTitle(),
+		Content:    &content,
+		Tags:       req.GetTags(),
+		MediaItems: ProtoMediaInputToDTO(req.GetMedia()),
+	}
+}
+
+// UpdatePostRequestToDTO converts an update request. UpdatePostRequest has no optional
+// fields yet, so an empty title or content means "leave unchanged" rather than "clear".
+func UpdatePostRequestToDTO(req *pb.UpdatePostRequest) *model.UpdatePostDTO {
+	return &model.UpdatePostDTO{
+		UserID:     req.GetUserId(),
+		Title:      OptionalString(req.GetTitle()),
+		Content:    OptionalString(req.GetContent()),
+		Tags:       req.GetTags(),
+		MediaItems: ProtoMediaInputToDTO(req.GetMedia()),
+	}
+}
+
+// ListPostsRequestToFilters converts the filters of a list request. Zero author ID, offset
+// and limit are unset, as are empty tag names.
+func ListPostsRequestToFilters(req *pb.ListPostsRequest) (*model.PostFilters, error) {
+	createdAfter, err := TimestampFromProto(req.GetCreatedAfter())
+	if err != nil {
+		return nil, err
+	}
+	createdBefore, err := TimestampFromProto(req.GetCreatedBefore())
+	if err != nil {
+		return nil, err
+	}
+	filters := &model.PostFilters{
+		AuthorID:      optionalInt64(req.GetAuthorId()),
+		Offset:        optionalInt(req.GetOffset()),
+		Limit:         optionalInt(req.GetLimit()),
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+	}
+	if len(req.GetTagNames()) > 0 {
+		filters.TagNames = req.GetTagNames()
+	}
+	return filters, nil
+}
+
+// OptionalString returns nil for an empty string, which proto3 cannot tell from an unset field.
+func OptionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalInt64(v int64) *int64 {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+func optionalInt(v int32) *int {
+	if v == 0 {
+		return nil
+	}
+	n := int(v)
+	return &n
+}
@@ -0,0 +1,378 @@
+package mapper_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/post/mapper"
+	"pinstack-post-service/internal/testsupport"
+)
+
+func TestPostDetailedToProto(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	valid := testsupport.Timestamptz(now)
+
+	tests := []struct {
+		name    string
+		post    *model.PostDetailed
+		want    *pb.Post
+		wantErr error
+	}{
+		{
+			name:    "NilPost",
+			post:    nil,
+			wantErr: mapper.ErrNilPost,
+		},
+		{
+			name:    "NilPostData",
+			post:    &model.PostDetailed{Author: &model.User{ID: 1}},
+			wantErr: mapper.ErrNilPost,
+		},
+		{
+			name: "MinimalPost",
+			post: &model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: 2, Title: "title"}},
+			want: &pb.Post{Id: 1, AuthorId: 2, Title: "title", Tags: []string{}, Media: []*pb.Media{}},
+		},
+		{
+			name: "NilAuthor",
+			post: &model.PostDetailed{
+				Post:   &model.Post{ID: 1, AuthorID: 2, Title: "title", Content: testsupport.Ptr("content")},
+				Author: nil,
+			},
+			want: &pb.Post{Id: 1, AuthorId: 2, Title: "title", Content: "content", Tags: []string{}, Media: []*pb.Media{}},
+		},
+		{
+			name: "FullPost",
+			post: &model.PostDetailed{
+				Post: &model.Post{
+					ID: 1, AuthorID: 2, Title: "title", Content: testsupport.Ptr("content"),
+					CreatedAt: valid, UpdatedAt: valid,
+				},
+				Author: &model.User{ID: 2},
+				Media: []*model.PostMedia{
+					{ID: 3, URL: "https://example.com/a.jpg", Type: model.MediaTypeImage, Position: 1, CreatedAt: valid},
+				},
+				Tags: []*model.Tag{{ID: 4, Name: "go"}},
+			},
+			want: &pb.Post{
+				Id: 1, AuthorId: 2, Title: "title", Content: "content",
+				Tags: []string{"go"},
+				Media: []*pb.Media{
+					{Id: 3, Url: "https://example.com/a.jpg", Type: string(model.MediaTypeImage), Position: 1, CreatedAt: timestamppb.New(now)},
+				},
+				CreatedAt: timestamppb.New(now),
+				UpdatedAt: timestamppb.New(now),
+			},
+		},
+		{
+			name: "NilMediaAndTagEntries",
+			post: &model.PostDetailed{
+				Post:  &model.Post{ID: 1},
+				Media: []*model.PostMedia{nil},
+				Tags:  []*model.Tag{nil, {Name: "go"}},
+			},
+			want: &pb.Post{Id: 1, Tags: []string{"go"}, Media: []*pb.Media{}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mapper.PostDetailedToProto(tc.post)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPostsToProto(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		got, err := mapper.PostsToProto(nil)
+		require.NoError(t, err)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+
+	t.Run("KeepsOrder", func(t *testing.T) {
+		got, err := mapper.PostsToProto([]*model.PostDetailed{
+			{Post: &model.Post{ID: 2}},
+			{Post: &model.Post{ID: 1}},
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, int64(2), got[0].Id)
+		assert.Equal(t, int64(1), got[1].Id)
+	})
+
+	t.Run("FailsOnNilPost", func(t *testing.T) {
+		got, err := mapper.PostsToProto([]*model.PostDetailed{{Post: &model.Post{ID: 1}}, nil})
+		assert.ErrorIs(t, err, mapper.ErrNilPost)
+		assert.Nil(t, got)
+	})
+}
+
+func TestMediaToProto(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		media []*model.PostMedia
+		want  []*pb.Media
+	}{
+		{name: "Nil", media: nil, want: []*pb.Media{}},
+		{name: "Empty", media: []*model.PostMedia{}, want: []*pb.Media{}},
+		{name: "NilEntry", media: []*model.PostMedia{nil}, want: []*pb.Media{}},
+		{
+			name:  "InvalidTimestamp",
+			media: []*model.PostMedia{{ID: 1, URL: "u", Type: model.MediaTypeVideo, Position: 2}},
+			want:  []*pb.Media{{Id: 1, Url: "u", Type: string(model.MediaTypeVideo), Position: 2}},
+		},
+		{
+			name:  "ValidTimestamp",
+			media: []*model.PostMedia{{ID: 1, CreatedAt: testsupport.Timestamptz(now)}},
+			want:  []*pb.Media{{Id: 1, CreatedAt: timestamppb.New(now)}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, mapper.MediaToProto(tc.media))
+		})
+	}
+}
+
+func TestTagNames(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []*model.Tag
+		want []string
+	}{
+		{name: "Nil", tags: nil, want: []string{}},
+		{name: "NilEntry", tags: []*model.Tag{nil}, want: []string{}},
+		{name: "Names", tags: []*model.Tag{{Name: "a"}, {Name: "b"}}, want: []string{"a", "b"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, mapper.TagNames(tc.tags))
+		})
+	}
+}
+
+func TestTimestampToProto(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		ts   pgtype.Timestamptz
+		want *timestamppb.Timestamp
+	}{
+		{name: "Unset", ts: pgtype.Timestamptz{}, want: nil},
+		{name: "OutOfRange", ts: testsupport.Timestamptz(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)), want: nil},
+		{name: "Valid", ts: testsupport.Timestamptz(now), want: timestamppb.New(now)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, mapper.TimestampToProto(tc.ts))
+		})
+	}
+}
+
+func TestTimestampFromProto(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		ts      *timestamppb.Timestamp
+		want    *pgtype.Timestamptz
+		wantErr error
+	}{
+		{name: "Nil", ts: nil, want: nil},
+		{name: "Invalid", ts: &timestamppb.Timestamp{Seconds: math.MaxInt64}, wantErr: mapper.ErrInvalidTimestamp},
+		{name: "Valid", ts: timestamppb.New(now), want: testsupport.TimestamptzPtr(now)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mapper.TimestampFromProto(tc.ts)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			if tc.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.True(t, got.Valid)
+			assert.True(t, tc.want.Time.Equal(got.Time))
+		})
+	}
+}
+
+func TestProtoMediaInputToDTO(t *testing.T) {
+	tests := []struct {
+		name  string
+		media []*pb.MediaInput
+		want  []*model.PostMediaInput
+	}{
+		{name: "Nil", media: nil, want: []*model.PostMediaInput{}},
+		{name: "NilEntry", media: []*pb.MediaInput{nil}, want: []*model.PostMediaInput{}},
+		{
+			name:  "ValidPosition",
+			media: []*pb.MediaInput{{Url: "u", Type: "image", Position: 3}},
+			want:  []*model.PostMediaInput{{URL: "u", Type: model.MediaType("image"), Position: 3}},
+		},
+		{
+			name:  "PositionBelowRangeUsesIndex",
+			media: []*pb.MediaInput{{Url: "a", Position: 1}, {Url: "b", Position: 0}},
+			want:  []*model.PostMediaInput{{URL: "a", Position: 1}, {URL: "b", Position: 2}},
+		},
+		{
+			name:  "PositionAboveRangeUsesIndex",
+			media: []*pb.MediaInput{{Url: "a", Position: mapper.MaxMediaPosition + 1}},
+			want:  []*model.PostMediaInput{{URL: "a", Position: 1}},
+		},
+		{
+			name: "ItemsPastLastPositionAreDropped",
+			media: func() []*pb.MediaInput {
+				media := make([]*pb.MediaInput, mapper.MaxMediaPosition+1)
+				for i := range media {
+					media[i] = &pb.MediaInput{Url: "u"}
+				}
+				return media
+			}(),
+			want: func() []*model.PostMediaInput {
+				media := make([]*model.PostMediaInput, mapper.MaxMediaPosition)
+				for i := range media {
+					media[i] = &model.PostMediaInput{URL: "u", Position: int32(i + 1)}
+				}
+				return media
+			}(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, mapper.ProtoMediaInputToDTO(tc.media))
+		})
+	}
+}
+
+func TestCreatePostRequestToDTO(t *testing.T) {
+	t.Run("Full", func(t *testing.T) {
+		got := mapper.CreatePostRequestToDTO(&pb.CreatePostRequest{
+			AuthorId: 1, Title: "title", Content: "content", Tags: []string{"go"},
+			Media: []*pb.MediaInput{{Url: "u", Type: "image", Position: 1}},
+		})
+		assert.Equal(t, &model.CreatePostDTO{
+			AuthorID: 1, Title: "title", Content: testsupport.Ptr("content"), Tags: []string{"go"},
+			MediaItems: []*model.PostMediaInput{{URL: "u", Type: model.MediaType("image"), Position: 1}},
+		}, got)
+	})
+
+	t.Run("EmptyContentIsSet", func(t *testing.T) {
+		got := mapper.CreatePostRequestToDTO(&pb.CreatePostRequest{AuthorId: 1, Title: "title"})
+		require.NotNil(t, got.Content)
+		assert.Empty(t, *got.Content)
+		assert.Empty(t, got.MediaItems)
+	})
+
+	t.Run("NilRequest", func(t *testing.T) {
+		got := mapper.CreatePostRequestToDTO(nil)
+		require.NotNil(t, got)
+		assert.Zero(t, got.AuthorID)
+	})
+}
+
+func TestUpdatePostRequestToDTO(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.UpdatePostRequest
+		want *model.UpdatePostDTO
+	}{
+		{
+			name: "EmptyFieldsAreUnchanged",
+			req:  &pb.UpdatePostRequest{UserId: 1, Id: 2},
+			want: &model.UpdatePostDTO{UserID: 1, MediaItems: []*model.PostMediaInput{}},
+		},
+		{
+			name: "Full",
+			req:  &pb.UpdatePostRequest{UserId: 1, Id: 2, Title: "title", Content: "content", Tags: []string{"go"}},
+			want: &model.UpdatePostDTO{
+				UserID: 1, Title: testsupport.Ptr("title"), Content: testsupport.Ptr("content"), Tags: []string{"go"},
+				MediaItems: []*model.PostMediaInput{},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, mapper.UpdatePostRequestToDTO(tc.req))
+		})
+	}
+}
+
+func TestListPostsRequestToFilters(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Empty", func(t *testing.T) {
+		got, err := mapper.ListPostsRequestToFilters(&pb.ListPostsRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, &model.PostFilters{}, got)
+	})
+
+	t.Run("Full", func(t *testing.T) {
+		got, err := mapper.ListPostsRequestToFilters(&pb.ListPostsRequest{
+			AuthorId: 1, Offset: 10, Limit: 20, TagNames: []string{"go"},
+			CreatedAfter: timestamppb.New(now), CreatedBefore: timestamppb.New(now.Add(time.Hour)),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, testsupport.Ptr(int64(1)), got.AuthorID)
+		assert.Equal(t, testsupport.Ptr(10), got.Offset)
+		assert.Equal(t, testsupport.Ptr(20), got.Limit)
+		assert.Equal(t, []string{"go"}, got.TagNames)
+		require.NotNil(t, got.CreatedAfter)
+		assert.True(t, got.CreatedAfter.Time.Equal(now))
+		require.NotNil(t, got.CreatedBefore)
+		assert.True(t, got.CreatedBefore.Time.Equal(now.Add(time.Hour)))
+	})
+
+	t.Run("InvalidCreatedAfter", func(t *testing.T) {
+		got, err := mapper.ListPostsRequestToFilters(&pb.ListPostsRequest{CreatedAfter: &timestamppb.Timestamp{Nanos: -1}})
+		assert.ErrorIs(t, err, mapper.ErrInvalidTimestamp)
+		assert.Nil(t, got)
+	})
+
+	t.Run("InvalidCreatedBefore", func(t *testing.T) {
+		got, err := mapper.ListPostsRequestToFilters(&pb.ListPostsRequest{CreatedBefore: &timestamppb.Timestamp{Nanos: -1}})
+		assert.ErrorIs(t, err, mapper.ErrInvalidTimestamp)
+		assert.Nil(t, got)
+	})
+
+	t.Run("EmptyTagNamesAreUnset", func(t *testing.T) {
+		got, err := mapper.ListPostsRequestToFilters(&pb.ListPostsRequest{TagNames: []string{}})
+		require.NoError(t, err)
+		assert.Nil(t, got.TagNames)
+	})
+}
+
+func TestOptionalString(t *testing.T) {
+	assert.Nil(t, mapper.OptionalString(""))
+	assert.Equal(t, testsupport.Ptr("a"), mapper.OptionalString("a"))
+}
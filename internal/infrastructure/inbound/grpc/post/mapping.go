@@ -0,0 +1,32 @@
+package post_grpc
+
+import (
+	"log/slog"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/post/mapper"
+
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+)
+
+// postResponse converts a post returned by the service for the wire. The service never
+// returns a post it cannot convert, so a failure is an internal error.
+func postResponse(log ports.Logger, post *model.PostDetailed) (*pb.Post, error) {
+	resp, err := mapper.PostDetailedToProto(post)
+	if err != nil {
+		log.Error("Failed to convert post", slog.String("error", err.Error()))
+		return nil, internalError()
+	}
+	return resp, nil
+}
+
+// postsResponse is postResponse for a list of posts.
+func postsResponse(log ports.Logger, posts []*model.PostDetailed) ([]*pb.Post, error) {
+	resp, err := mapper.PostsToProto(posts)
+	if err != nil {
+		log.Error("Failed to convert posts", slog.Int("posts", len(posts)), slog.String("error", err.Error()))
+		return nil, internalError()
+	}
+	return resp, nil
+}
@@ -0,0 +1,90 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+
+	"github.com/go-playground/validator/v10"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type PostPinner interface {
+	PinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error)
+	UnpinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error)
+}
+
+type PinPostHandler struct {
+	postService PostPinner
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewPinPostHandler(postService PostPinner, validate *validator.Validate, log ports.Logger) *PinPostHandler {
+	return &PinPostHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type PinPostRequestInternal struct {
+	PostID int64 `validate:"required,gt=0"`
+	UserID int64 `validate:"required,gt=0"`
+}
+
+// PinPostResponse is a post with the is_pinned flag pb.Post does not carry yet.
+type PinPostResponse struct {
+	Post     *pb.Post
+	IsPinned bool
+}
+
+// PinPost pins a post to the top of its author's profile, unpinning the one pinned before.
+// It is not exposed on the wire until the proto definitions gain the RPC.
+func (h *PinPostHandler) PinPost(ctx context.Context, userID int64, postID int64) (*PinPostResponse, error) {
+	return h.changePin(ctx, "PinPost", userID, postID, h.postService.PinPost)
+}
+
+// UnpinPost unpins a post; a post that is not pinned is returned unchanged.
+func (h *PinPostHandler) UnpinPost(ctx context.Context, userID int64, postID int64) (*PinPostResponse, error) {
+	return h.changePin(ctx, "UnpinPost", userID, postID, h.postService.UnpinPost)
+}
+
+func (h *PinPostHandler) changePin(
+	ctx context.Context,
+	method string,
+	userID int64,
+	postID int64,
+	change func(ctx context.Context, userID int64, id int64) (*model.PinChange, error),
+) (*PinPostResponse, error) {
+	h.log.Debug("Handling "+method+" request", slog.Int64("post_id", postID), slog.Int64("user_id", userID))
+
+	validationReq := &PinPostRequestInternal{
+		PostID: postID,
+		UserID: userID,
+	}
+	if err := h.validate.Struct(validationReq); err != nil {
+		h.log.Debug(method+" validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	result, err := change(ctx, userID, postID)
+	if err != nil {
+		// The service refuses to pin a post that is not published with ErrInvalidInput.
+		return nil, serviceErrorStatus(h.log, failedPrecondition(err, custom_errors.ErrInvalidInput), "Failed to change post pin",
+			slog.String("method", method), slog.Int64("post_id", postID), slog.Int64("user_id", userID))
+	}
+
+	resp, err := postResponse(h.log, result.Post)
+	if err != nil {
+		return nil, err
+	}
+
+	h.log.Debug("Post pin changed successfully", slog.String("method", method), slog.Int64("post_id", resp.Id),
+		slog.Int("affected_posts", len(result.AffectedPostIDs)))
+	return &PinPostResponse{Post: resp, IsPinned: result.Post.Post.IsPinned()}, nil
+}
@@ -0,0 +1,80 @@
+package post_grpc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/testsupport"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestPinPostHandler(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("PinReportsThePin", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewPinPostHandler(mockPostService, validate, testLogger)
+		pinned := &model.Post{ID: 7, AuthorID: 1, Title: "Title", PinnedAt: testsupport.Timestamptz(time.Now())}
+		mockPostService.On("PinPost", mock.Anything, int64(1), int64(7)).
+			Return(&model.PinChange{Post: &model.PostDetailed{Post: pinned}, AffectedPostIDs: []int64{6, 7}}, nil)
+
+		resp, err := handler.PinPost(context.Background(), 1, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), resp.Post.Id)
+		assert.True(t, resp.IsPinned)
+	})
+
+	t.Run("UnpinReportsThePin", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewPinPostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("UnpinPost", mock.Anything, int64(1), int64(7)).
+			Return(&model.PinChange{Post: &model.PostDetailed{Post: &model.Post{ID: 7, AuthorID: 1, Title: "Title"}}}, nil)
+
+		resp, err := handler.UnpinPost(context.Background(), 1, 7)
+
+		require.NoError(t, err)
+		assert.False(t, resp.IsPinned)
+	})
+
+	tests := []struct {
+		name       string
+		postID     int64
+		err        error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{name: "invalid post id", postID: 0, wantCode: codes.InvalidArgument, wantReason: errinfo.ReasonInvalidArgument},
+		{name: "not found", postID: 7, err: custom_errors.ErrPostNotFound, wantCode: codes.NotFound, wantReason: errinfo.ReasonPostNotFound},
+		{name: "not the author", postID: 7, err: custom_errors.ErrForbidden, wantCode: codes.PermissionDenied, wantReason: errinfo.ReasonNotAuthor},
+		{name: "draft", postID: 7, err: fmt.Errorf("%w: only a published post can be pinned", custom_errors.ErrInvalidInput), wantCode: codes.FailedPrecondition, wantReason: errinfo.ReasonFailedPrecondition},
+		{name: "database error", postID: 7, err: custom_errors.ErrDatabaseQuery, wantCode: codes.Internal, wantReason: errinfo.ReasonDatabaseError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewPinPostHandler(mockPostService, validate, testLogger)
+			mockPostService.On("PinPost", mock.Anything, int64(1), tt.postID).Return(nil, tt.err)
+
+			_, err := handler.PinPost(context.Background(), 1, tt.postID)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantReason, errinfo.Reason(err))
+		})
+	}
+}
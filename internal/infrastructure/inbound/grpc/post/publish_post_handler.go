@@ -0,0 +1,63 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type PostPublisher interface {
+	PublishPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error)
+}
+
+type PublishPostHandler struct {
+	postService PostPublisher
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewPublishPostHandler(postService PostPublisher, validate *validator.Validate, log ports.Logger) *PublishPostHandler {
+	return &PublishPostHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type PublishPostRequestInternal struct {
+	PostID int64 `validate:"required,gt=0"`
+	UserID int64 `validate:"required,gt=0"`
+}
+
+// PublishPost is not exposed on the wire until the proto definitions gain a PublishPost RPC.
+func (h *PublishPostHandler) PublishPost(ctx context.Context, userID int64, postID int64) (*pb.Post, error) {
+	h.log.Debug("Handling PublishPost request", slog.Int64("post_id", postID), slog.Int64("user_id", userID))
+
+	validationReq := &PublishPostRequestInternal{
+		PostID: postID,
+		UserID: userID,
+	}
+
+	if err := h.validate.Struct(validationReq); err != nil {
+		h.log.Debug("PublishPost validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	published, err := h.postService.PublishPost(ctx, userID, postID)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to publish post", slog.Int64("post_id", postID), slog.Int64("user_id", userID))
+	}
+
+	resp, err := postResponse(h.log, published)
+	if err != nil {
+		return nil, err
+	}
+
+	h.log.Debug("Post published successfully", slog.Int64("post_id", resp.Id))
+	return resp, nil
+}
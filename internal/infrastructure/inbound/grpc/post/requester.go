@@ -0,0 +1,50 @@
+package post_grpc
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+
+	"pinstack-post-service/internal/infrastructure/inbound/middleware"
+)
+
+// requesterIDMetadataKey carries the authenticated user ID set by the API gateway.
+const requesterIDMetadataKey = "x-user-id"
+
+// clientSourceMetadataKey names the client a request comes from ("web", "ios", ...), set by
+// the API gateway or the client itself.
+const clientSourceMetadataKey = "x-client-source"
+
+// requesterIDFromContext returns the caller's user ID from incoming metadata, or nil for anonymous calls.
+func requesterIDFromContext(ctx context.Context) *int64 {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	values := md.Get(requesterIDMetadataKey)
+	if len(values) == 0 {
+		return nil
+	}
+	id, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || id <= 0 {
+		return nil
+	}
+	return &id
+}
+
+// clientSourceFromContext returns the client named by incoming metadata, or "" when there is
+// none; the service decides whether it is one it records.
+func clientSourceFromContext(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, clientSourceMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// isInternalAdmin reports whether the call carries the internal admin flag. The admin methods
+// are not on the wire and are called in process, so this check is their only access control.
+func isInternalAdmin(ctx context.Context) bool {
+	return middleware.IsInternalAdmin(ctx)
+}
@@ -0,0 +1,63 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type PostModerator interface {
+	SetModerationStatus(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason) (*model.Post, error)
+}
+
+type SetModerationStatusHandler struct {
+	postService PostModerator
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewSetModerationStatusHandler(postService PostModerator, validate *validator.Validate, log ports.Logger) *SetModerationStatusHandler {
+	return &SetModerationStatusHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+// SetModerationStatusRequestInternal leaves the pairing of status and reason to the service,
+// whose validation error names the offending field.
+type SetModerationStatusRequestInternal struct {
+	ActorID int64  `validate:"required,gt=0"`
+	PostID  int64  `validate:"required,gt=0"`
+	Status  string `validate:"required,oneof=approved under_review rejected"`
+}
+
+// SetModerationStatus is not exposed on the wire until the proto definitions gain moderation
+// RPCs. It returns the post as stored, unredacted.
+func (h *SetModerationStatusHandler) SetModerationStatus(ctx context.Context, actorID int64, postID int64, moderationStatus model.ModerationStatus, reason model.RejectionReason) (*model.Post, error) {
+	h.log.Debug("Handling SetModerationStatus request", slog.Int64("post_id", postID), slog.Int64("actor_id", actorID), slog.String("status", string(moderationStatus)))
+
+	if !isInternalAdmin(ctx) {
+		h.log.Debug("SetModerationStatus called without admin flag", slog.Int64("post_id", postID))
+		return nil, notAdmin()
+	}
+	if err := h.validate.Struct(&SetModerationStatusRequestInternal{ActorID: actorID, PostID: postID, Status: string(moderationStatus)}); err != nil {
+		h.log.Debug("SetModerationStatus validation failed", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	post, err := h.postService.SetModerationStatus(ctx, actorID, postID, moderationStatus, reason)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to set moderation status", slog.Int64("post_id", postID))
+	}
+
+	h.log.Info("Post moderation status set",
+		slog.Int64("post_id", postID),
+		slog.Int64("actor_id", actorID),
+		slog.String("status", string(moderationStatus)))
+	return post, nil
+}
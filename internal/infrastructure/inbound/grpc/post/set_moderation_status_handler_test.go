@@ -0,0 +1,89 @@
+package post_grpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestSetModerationStatusHandler_SetModerationStatus(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewSetModerationStatusHandler(mockPostService, validate, testLogger)
+
+		reason := model.RejectionReasonSpam
+		post := &model.Post{ID: 7, AuthorID: 3, Title: "Deals", ModerationStatus: model.ModerationStatusRejected, RejectionReason: &reason}
+		mockPostService.On("SetModerationStatus", mock.Anything, int64(99), int64(7), model.ModerationStatusRejected, reason).Return(post, nil)
+
+		resp, err := handler.SetModerationStatus(adminContext(), 99, 7, model.ModerationStatusRejected, reason)
+
+		require.NoError(t, err)
+		assert.Equal(t, post, resp, "the moderator sees the post unredacted")
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("MissingAdminFlag", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewSetModerationStatusHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.SetModerationStatus(context.Background(), 99, 7, model.ModerationStatusUnderReview, "")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, errinfo.ReasonNotAdmin, errinfo.Reason(err))
+		mockPostService.AssertNotCalled(t, "SetModerationStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidStatus", func(t *testing.T) {
+		for _, moderationStatus := range []model.ModerationStatus{"", "hidden"} {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewSetModerationStatusHandler(mockPostService, validate, testLogger)
+
+			resp, err := handler.SetModerationStatus(adminContext(), 99, 7, moderationStatus, "")
+
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), "status %q", moderationStatus)
+			mockPostService.AssertNotCalled(t, "SetModerationStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("ServiceValidationError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewSetModerationStatusHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("SetModerationStatus", mock.Anything, int64(99), int64(7), model.ModerationStatusRejected, model.RejectionReason("")).
+			Return(nil, model.ValidateModerationStatus(model.ModerationStatusRejected, ""))
+
+		_, err := handler.SetModerationStatus(adminContext(), 99, 7, model.ModerationStatusRejected, "")
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewSetModerationStatusHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("SetModerationStatus", mock.Anything, int64(99), int64(7), model.ModerationStatusApproved, model.RejectionReason("")).
+			Return(nil, custom_errors.ErrPostNotFound)
+
+		_, err := handler.SetModerationStatus(adminContext(), 99, 7, model.ModerationStatusApproved, "")
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
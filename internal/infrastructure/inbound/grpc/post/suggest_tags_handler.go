@@ -0,0 +1,58 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type TagSuggester interface {
+	SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error)
+}
+
+type SuggestTagsHandler struct {
+	postService TagSuggester
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewSuggestTagsHandler(postService TagSuggester, validate *validator.Validate, log ports.Logger) *SuggestTagsHandler {
+	return &SuggestTagsHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type SuggestTagsRequestInternal struct {
+	Prefix   string `validate:"min=1,max=50"`
+	Limit    int    `validate:"gte=0,lte=10"`
+	AuthorID *int64 `validate:"omitempty,gt=0"`
+}
+
+// SuggestTags autocompletes tag names for the tag input of the post editor. A limit of 0 asks
+// for the default of 10; with authorID the author's own tags come first. It is not exposed on
+// the wire until the proto definitions gain the RPC.
+func (h *SuggestTagsHandler) SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error) {
+	h.log.Debug("Handling SuggestTags request", slog.String("prefix", prefix), slog.Int("limit", limit))
+
+	if err := h.validate.Struct(&SuggestTagsRequestInternal{Prefix: prefix, Limit: limit, AuthorID: authorID}); err != nil {
+		h.log.Debug("SuggestTags validation failed", slog.String("prefix", prefix), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	tags, err := h.postService.SuggestTags(ctx, prefix, limit, authorID)
+	if err != nil {
+		return nil, serviceErrorStatus(h.log, err, "Failed to suggest tags", slog.String("prefix", prefix))
+	}
+
+	if tags == nil {
+		tags = []*model.Tag{}
+	}
+	h.log.Debug("Tags suggested", slog.String("prefix", prefix), slog.Int("count", len(tags)))
+	return tags, nil
+}
@@ -0,0 +1,103 @@
+package post_grpc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func TestSuggestTagsHandler_SuggestTags(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewSuggestTagsHandler(mockPostService, validate, testLogger)
+
+		authorID := int64(7)
+		tags := []*model.Tag{{ID: 1, Name: "golang"}}
+		mockPostService.On("SuggestTags", mock.Anything, "go", 5, &authorID).Return(tags, nil)
+
+		resp, err := handler.SuggestTags(context.Background(), "go", 5, &authorID)
+
+		require.NoError(t, err)
+		assert.Equal(t, tags, resp)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("NoMatchIsEmpty", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewSuggestTagsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("SuggestTags", mock.Anything, "zz", 0, (*int64)(nil)).Return(nil, nil)
+
+		resp, err := handler.SuggestTags(context.Background(), "zz", 0, nil)
+
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+		assert.Empty(t, resp)
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		badAuthor := int64(0)
+		tests := []struct {
+			name     string
+			prefix   string
+			limit    int
+			authorID *int64
+		}{
+			{name: "empty prefix", prefix: ""},
+			{name: "prefix too long", prefix: strings.Repeat("a", 51)},
+			{name: "limit too large", prefix: "go", limit: 11},
+			{name: "invalid author", prefix: "go", authorID: &badAuthor},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockPostService := new(mockpost.Service)
+				handler := post_grpc.NewSuggestTagsHandler(mockPostService, validate, testLogger)
+
+				_, err := handler.SuggestTags(context.Background(), tt.prefix, tt.limit, tt.authorID)
+
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+				mockPostService.AssertNotCalled(t, "SuggestTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("BlankPrefix", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewSuggestTagsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("SuggestTags", mock.Anything, "  ", 0, (*int64)(nil)).
+			Return(nil, custom_errors.ErrInvalidInput)
+
+		_, err := handler.SuggestTags(context.Background(), "  ", 0, nil)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("InternalError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewSuggestTagsHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("SuggestTags", mock.Anything, "go", 0, (*int64)(nil)).Return(nil, errors.New("boom"))
+
+		_, err := handler.SuggestTags(context.Background(), "go", 0, nil)
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
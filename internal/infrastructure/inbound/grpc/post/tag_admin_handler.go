@@ -0,0 +1,86 @@
+package post_grpc
+
+import (
+	"context"
+	"log/slog"
+	ports "pinstack-post-service/internal/domain/ports/output"
+
+	"github.com/go-playground/validator/v10"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+type TagAdministrator interface {
+	RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error)
+	MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error)
+}
+
+type TagAdminHandler struct {
+	postService TagAdministrator
+	validate    *validator.Validate
+	log         ports.Logger
+}
+
+func NewTagAdminHandler(postService TagAdministrator, validate *validator.Validate, log ports.Logger) *TagAdminHandler {
+	return &TagAdminHandler{
+		postService: postService,
+		validate:    validate,
+		log:         log,
+	}
+}
+
+type RenameTagRequestInternal struct {
+	TagID int64  `validate:"required,gt=0"`
+	Name  string `validate:"required"`
+}
+
+type MergeTagsRequestInternal struct {
+	SourceIDs []int64 `validate:"required,min=1,dive,gt=0"`
+	DestID    int64   `validate:"required,gt=0"`
+}
+
+// RenameTag is not exposed on the wire until the proto definitions gain tag admin RPCs.
+func (h *TagAdminHandler) RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error) {
+	h.log.Debug("Handling RenameTag request", slog.Int64("tag_id", tagID), slog.String("name", name))
+
+	if !isInternalAdmin(ctx) {
+		h.log.Debug("RenameTag called without admin flag", slog.Int64("tag_id", tagID))
+		return nil, notAdmin()
+	}
+	if err := h.validate.Struct(&RenameTagRequestInternal{TagID: tagID, Name: name}); err != nil {
+		h.log.Debug("RenameTag validation failed", slog.Int64("tag_id", tagID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	result, err := h.postService.RenameTag(ctx, tagID, name)
+	if err != nil {
+		return nil, h.tagAdminStatus("RenameTag", err)
+	}
+	h.log.Info("Tag renamed", slog.Int64("tag_id", tagID), slog.String("name", result.Tag.Name))
+	return result, nil
+}
+
+// MergeTags is not exposed on the wire until the proto definitions gain tag admin RPCs.
+func (h *TagAdminHandler) MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error) {
+	h.log.Debug("Handling MergeTags request", slog.Any("source_ids", sourceIDs), slog.Int64("dest_id", destID))
+
+	if !isInternalAdmin(ctx) {
+		h.log.Debug("MergeTags called without admin flag", slog.Int64("dest_id", destID))
+		return nil, notAdmin()
+	}
+	if err := h.validate.Struct(&MergeTagsRequestInternal{SourceIDs: sourceIDs, DestID: destID}); err != nil {
+		h.log.Debug("MergeTags validation failed", slog.Int64("dest_id", destID), slog.String("error", err.Error()))
+		return nil, invalidRequest("invalid request")
+	}
+
+	result, err := h.postService.MergeTags(ctx, sourceIDs, destID)
+	if err != nil {
+		return nil, h.tagAdminStatus("MergeTags", err)
+	}
+	h.log.Info("Tags merged", slog.Int64("dest_id", destID), slog.Int("affected_posts", len(result.AffectedPostIDs)))
+	return result, nil
+}
+
+func (h *TagAdminHandler) tagAdminStatus(method string, err error) error {
+	return serviceErrorStatus(h.log, err, "Tag admin operation failed", slog.String("method", method))
+}
@@ -0,0 +1,143 @@
+package post_grpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+func adminContext() context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-internal-admin", "true"))
+}
+
+func TestTagAdminHandler_RenameTag(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewTagAdminHandler(mockPostService, validate, testLogger)
+
+		change := &model.TagChange{Tag: &model.Tag{ID: 5, Name: "golang"}, AffectedPostIDs: []int64{1}}
+		mockPostService.On("RenameTag", mock.Anything, int64(5), "golang").Return(change, nil)
+
+		resp, err := handler.RenameTag(adminContext(), 5, "golang")
+
+		require.NoError(t, err)
+		assert.Equal(t, change, resp)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("MissingAdminFlag", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewTagAdminHandler(mockPostService, validate, testLogger)
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-internal-admin", "false"))
+		resp, err := handler.RenameTag(ctx, 5, "golang")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, errinfo.ReasonNotAdmin, errinfo.Reason(err))
+		mockPostService.AssertNotCalled(t, "RenameTag", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewTagAdminHandler(mockPostService, validate, testLogger)
+
+		resp, err := handler.RenameTag(adminContext(), 0, "golang")
+
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("ServiceFieldViolations", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewTagAdminHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("RenameTag", mock.Anything, int64(5), "c++").
+			Return(nil, model.ValidateTagName("c++"))
+
+		_, err := handler.RenameTag(adminContext(), 5, "c++")
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, errinfo.ReasonValidationFailed, errinfo.Reason(err))
+		assert.Len(t, st.Details(), 2)
+	})
+
+	t.Run("TagNotFound", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewTagAdminHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("RenameTag", mock.Anything, int64(5), "golang").Return(nil, custom_errors.ErrTagNotFound)
+
+		_, err := handler.RenameTag(adminContext(), 5, "golang")
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestTagAdminHandler_MergeTags(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewTagAdminHandler(mockPostService, validate, testLogger)
+
+		change := &model.TagChange{Tag: &model.Tag{ID: 1, Name: "golang"}, AffectedPostIDs: []int64{10, 11}}
+		mockPostService.On("MergeTags", mock.Anything, []int64{2, 3}, int64(1)).Return(change, nil)
+
+		resp, err := handler.MergeTags(adminContext(), []int64{2, 3}, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, change, resp)
+	})
+
+	t.Run("MissingAdminFlag", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewTagAdminHandler(mockPostService, validate, testLogger)
+
+		_, err := handler.MergeTags(context.Background(), []int64{2}, 1)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, errinfo.ReasonNotAdmin, errinfo.Reason(err))
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewTagAdminHandler(mockPostService, validate, testLogger)
+
+		_, err := handler.MergeTags(adminContext(), nil, 1)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "MergeTags", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("InternalError", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewTagAdminHandler(mockPostService, validate, testLogger)
+
+		mockPostService.On("MergeTags", mock.Anything, []int64{2}, int64(1)).Return(nil, errors.New("boom"))
+
+		_, err := handler.MergeTags(adminContext(), []int64{2}, 1)
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
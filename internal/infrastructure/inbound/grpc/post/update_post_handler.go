@@ -2,24 +2,19 @@ package post_grpc
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"log/slog"
 	ports "pinstack-post-service/internal/domain/ports/output"
 
-	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
-
 	"github.com/go-playground/validator/v10"
 	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 
 	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/post/mapper"
 )
 
 type PostUpdater interface {
-	UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) error
-	GetPostByID(ctx context.Context, id int64) (*model.PostDetailed, error)
+	UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) (*model.PostDetailed, error)
 }
 
 type UpdatePostHandler struct {
@@ -38,22 +33,102 @@ func NewUpdatePostHandler(postService PostUpdater, validate *validator.Validate,
 }
 
 type UpdatePostRequestInternal struct {
-	Id      int64                 `validate:"required,gt=0"`
-	Title   *string               `validate:"omitempty"`
-	Content *string               `validate:"omitempty"`
-	Tags    []string              `validate:"omitempty,dive"`
-	Media   []*MediaInputInternal `validate:"omitempty,dive"`
+	Id       int64                 `validate:"required,gt=0"`
+	Title    *string               `validate:"omitempty"`
+	Content  *string               `validate:"omitempty"`
+	Tags     []string              `validate:"omitempty,dive"`
+	Media    []*MediaInputInternal `validate:"omitempty,dive"`
+	Language *string               `validate:"omitempty,bcp47_language_tag"`
+}
+
+// UpdatePostResponse is the planned UpdatePostResponse message: the updated post, as
+// UpdatePost returns it, its version and edit count after the update and what the update
+// changed.
+type UpdatePostResponse struct {
+	Post          *pb.Post
+	Version       int64
+	EditCount     int64
+	ChangeSummary *model.PostChangeSummary
 }
 
 func (h *UpdatePostHandler) UpdatePost(ctx context.Context, req *pb.UpdatePostRequest) (*pb.Post, error) {
+	resp, err := h.updatePost(ctx, req, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Post, nil
+}
+
+// UpdatePostWithSummary is UpdatePost that also returns the tags added, created and removed,
+// the media attached and detached with their ids, and the fields changed. Proto v0.1.22 has
+// no UpdatePostResponse message, so this is not exposed over gRPC yet.
+func (h *UpdatePostHandler) UpdatePostWithSummary(ctx context.Context, req *pb.UpdatePostRequest) (*UpdatePostResponse, error) {
+	return h.updatePost(ctx, req, nil, nil, nil)
+}
+
+// UpdatePostIfVersion is UpdatePostWithSummary that applies only while the post is still at
+// expectedVersion; otherwise it fails with Aborted and the current version in an ErrorInfo
+// detail. UpdatePostRequest has no expected_version field in proto v0.1.22, so this is not
+// exposed over gRPC yet.
+func (h *UpdatePostHandler) UpdatePostIfVersion(ctx context.Context, req *pb.UpdatePostRequest, expectedVersion int64) (*UpdatePostResponse, error) {
+	return h.updatePost(ctx, req, nil, &expectedVersion, nil)
+}
+
+// UpdatePostVisibility is UpdatePost that also changes the visibility of the post.
+// UpdatePostRequest has no visibility field in proto v0.1.22, so this is not exposed over
+// gRPC yet.
+func (h *UpdatePostHandler) UpdatePostVisibility(ctx context.Context, req *pb.UpdatePostRequest, visibility string) (*pb.Post, error) {
+	v := model.PostVisibility(visibility)
+	if err := v.IsValid(); err != nil {
+		h.log.Debug("Invalid visibility", slog.Int64("post_id", req.GetId()), slog.String("visibility", visibility))
+		return nil, invalidRequest(err.Error())
+	}
+	resp, err := h.updatePost(ctx, req, &v, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Post, nil
+}
+
+// UpdatePostLanguage is UpdatePost that also sets the language of the post to language, a
+// BCP-47 tag the server allows. A post's language cannot be cleared once set.
+// UpdatePostRequest has no language field in proto v0.1.22, so this is not exposed over
+// gRPC yet.
+func (h *UpdatePostHandler) UpdatePostLanguage(ctx context.Context, req *pb.UpdatePostRequest, language string) (*pb.Post, error) {
+	if language == "" {
+		h.log.Debug("Empty language", slog.Int64("post_id", req.GetId()))
+		return nil, invalidRequest("language is required")
+	}
+	resp, err := h.updatePost(ctx, req, nil, nil, &language)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Post, nil
+}
+
+func (h *UpdatePostHandler) updatePost(
+	ctx context.Context,
+	req *pb.UpdatePostRequest,
+	visibility *model.PostVisibility,
+	expectedVersion *int64,
+	language *string,
+) (*UpdatePostResponse, error) {
 	h.log.Debug("Received UpdatePost request",
 		slog.Int64("post_id", req.GetId()),
 		slog.Int64("user_id", req.GetUserId()),
+		slog.Bool("has_visibility_update", visibility != nil),
+		slog.Bool("has_expected_version", expectedVersion != nil),
+		slog.Bool("has_language_update", language != nil),
 		slog.Bool("has_title_update", req.Title != ""),
 		slog.Bool("has_content_update", req.Content != ""),
 		slog.Int("media_items_count", len(req.GetMedia())),
 		slog.Int("tags_count", len(req.GetTags())))
 
+	if st := mediaTypeStatus(req.GetMedia()); st != nil {
+		h.log.Debug("Invalid media type", slog.Int64("post_id", req.GetId()), slog.String("error", st.Message()))
+		return nil, st.Err()
+	}
+
 	internalMedia := make([]*MediaInputInternal, len(req.GetMedia()))
 	for i, m := range req.GetMedia() {
 		internalMedia[i] = &MediaInputInternal{
@@ -63,12 +138,18 @@ func (h *UpdatePostHandler) UpdatePost(ctx context.Context, req *pb.UpdatePostRe
 		}
 	}
 
+	updateDTO := mapper.UpdatePostRequestToDTO(req)
+	updateDTO.Visibility = visibility
+	updateDTO.ExpectedVersion = expectedVersion
+	updateDTO.Language = language
+
 	validationReq := &UpdatePostRequestInternal{
-		Id:      req.GetId(),
-		Title:   &req.Title,
-		Content: &req.Content,
-		Tags:    req.GetTags(),
-		Media:   internalMedia,
+		Id:       req.GetId(),
+		Title:    updateDTO.Title,
+		Content:  updateDTO.Content,
+		Tags:     req.GetTags(),
+		Media:    internalMedia,
+		Language: language,
 	}
 
 	if err := h.validate.Struct(validationReq); err != nil {
@@ -76,139 +157,29 @@ func (h *UpdatePostHandler) UpdatePost(ctx context.Context, req *pb.UpdatePostRe
 			slog.Int64("post_id", req.GetId()),
 			slog.Int64("user_id", req.GetUserId()),
 			slog.String("error", err.Error()))
-		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+		return nil, invalidRequest(fmt.Sprintf("invalid request: %v", err))
 	}
 
-	dtoMediaItems := make([]*model.PostMediaInput, 0, len(req.GetMedia()))
-	for i, m := range req.GetMedia() {
-		position := m.GetPosition()
-
-		if position < MinMediaPosition || position > MaxMediaPosition {
-			h.log.Debug("Invalid media position, adjusting",
-				slog.Int("original_position", int(position)),
-				slog.Int("index", i),
-				slog.String("url", m.GetUrl()))
-
-			position = int32(i + 1)
-
-			if position > MaxMediaPosition {
-				h.log.Debug("Skipping media item due to position constraints",
-					slog.Int("adjusted_position", int(position)),
-					slog.Int("max_allowed", MaxMediaPosition),
-					slog.String("url", m.GetUrl()))
-				continue
-			}
-
-			h.log.Debug("Media position adjusted",
-				slog.Int("new_position", int(position)),
-				slog.String("url", m.GetUrl()))
-		}
-
-		dtoMediaItems = append(dtoMediaItems, &model.PostMediaInput{
-			URL:      m.GetUrl(),
-			Type:     model.MediaType(m.GetType()),
-			Position: position,
-		})
+	if dropped := len(req.GetMedia()) - len(updateDTO.MediaItems); dropped > 0 {
+		h.log.Debug("Dropped media items past the last position",
+			slog.Int("dropped", dropped),
+			slog.Int("max_position", mapper.MaxMediaPosition))
 	}
 
-	updateDTO := &model.UpdatePostDTO{
-		UserID:     req.GetUserId(),
-		Title:      &req.Title,
-		Content:    &req.Content,
-		Tags:       req.GetTags(),
-		MediaItems: dtoMediaItems,
-	}
-
-	err := h.postService.UpdatePost(ctx, req.GetUserId(), req.GetId(), updateDTO)
+	updatedPost, err := h.postService.UpdatePost(ctx, req.GetUserId(), req.GetId(), updateDTO)
 	if err != nil {
-		h.log.Debug("Error updating post", slog.Int64("id", req.GetId()), slog.Int64("user_id", req.GetUserId()), slog.String("error", err.Error()))
-		switch {
-		case errors.Is(err, custom_errors.ErrPostNotFound):
-			return nil, status.Error(codes.NotFound, custom_errors.ErrPostNotFound.Error())
-		case errors.Is(err, custom_errors.ErrPostValidation):
-			return nil, status.Error(codes.InvalidArgument, custom_errors.ErrPostValidation.Error())
-		case errors.Is(err, custom_errors.ErrForbidden) || errors.Is(err, custom_errors.ErrInvalidInput):
-			// Map both ErrForbidden and ErrInvalidInput to PermissionDenied for consistency with API gateway
-			return nil, status.Error(codes.PermissionDenied, custom_errors.ErrForbidden.Error())
-		default:
-			h.log.Error("Unexpected error updating post", slog.Int64("id", req.GetId()), slog.String("error", err.Error()))
-			return nil, status.Error(codes.Internal, custom_errors.ErrInternalServiceError.Error())
-		}
+		return nil, serviceErrorStatus(h.log, err, "Failed to update post", slog.Int64("post_id", req.GetId()), slog.Int64("user_id", req.GetUserId()))
 	}
 
-	updatedPost, err := h.postService.GetPostByID(ctx, req.GetId())
+	resp, err := postResponse(h.log, updatedPost)
 	if err != nil {
-		h.log.Debug("Error getting updated post", slog.Int64("id", req.GetId()), slog.String("error", err.Error()))
-		switch {
-		case errors.Is(err, custom_errors.ErrPostNotFound):
-			return nil, status.Error(codes.NotFound, custom_errors.ErrPostNotFound.Error())
-		case errors.Is(err, custom_errors.ErrPostValidation):
-			return nil, status.Error(codes.InvalidArgument, custom_errors.ErrPostValidation.Error())
-		case errors.Is(err, custom_errors.ErrForbidden):
-			return nil, status.Error(codes.PermissionDenied, custom_errors.ErrForbidden.Error())
-		default:
-			h.log.Error("Unexpected error retrieving updated post", slog.Int64("id", req.GetId()), slog.String("error", err.Error()))
-			return nil, status.Error(codes.Internal, custom_errors.ErrInternalServiceError.Error())
-		}
-	}
-
-	pbMedia := make([]*pb.Media, len(updatedPost.Media))
-	for i, m := range updatedPost.Media {
-		var mediaCreatedAtPb *timestamppb.Timestamp
-		if m.CreatedAt.Valid {
-			mediaCreatedAtPb = timestamppb.New(m.CreatedAt.Time)
-		}
-		pbMedia[i] = &pb.Media{
-			Id:        m.ID,
-			Url:       m.URL,
-			Type:      string(m.Type),
-			Position:  m.Position,
-			CreatedAt: mediaCreatedAtPb,
-		}
-	}
-
-	var postID int64
-	var authorID int64
-	var title string
-	var content string
-	var createdAtPb *timestamppb.Timestamp
-	var updatedAtPb *timestamppb.Timestamp
-
-	if updatedPost.Post != nil {
-		postID = updatedPost.Post.ID
-		authorID = updatedPost.Post.AuthorID
-		title = updatedPost.Post.Title
-		if updatedPost.Post.Content != nil {
-			content = *updatedPost.Post.Content
-		}
-		if updatedPost.Post.CreatedAt.Valid {
-			createdAtPb = timestamppb.New(updatedPost.Post.CreatedAt.Time)
-		}
-		if updatedPost.Post.UpdatedAt.Valid {
-			updatedAtPb = timestamppb.New(updatedPost.Post.UpdatedAt.Time)
-		}
-	}
-
-	pbTags := make([]string, len(updatedPost.Tags))
-	for i, t := range updatedPost.Tags {
-		pbTags[i] = t.Name
-	}
-
-	resp := &pb.Post{
-		Id:        postID,
-		AuthorId:  authorID,
-		Title:     title,
-		Content:   content,
-		Tags:      pbTags,
-		Media:     pbMedia,
-		CreatedAt: createdAtPb,
-		UpdatedAt: updatedAtPb,
+		return nil, err
 	}
 
 	h.log.Debug("Successfully updated post",
-		slog.Int64("post_id", postID),
-		slog.Int64("author_id", authorID),
-		slog.Int("tags_count", len(pbTags)),
-		slog.Int("media_count", len(pbMedia)))
-	return resp, nil
+		slog.Int64("post_id", resp.Id),
+		slog.Int64("author_id", resp.AuthorId),
+		slog.Int("tags_count", len(resp.Tags)),
+		slog.Int("media_count", len(resp.Media)))
+	return &UpdatePostResponse{Post: resp, Version: updatedPost.Post.Version, EditCount: updatedPost.Post.EditCount, ChangeSummary: updatedPost.Changes}, nil
 }
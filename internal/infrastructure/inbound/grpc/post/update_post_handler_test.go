@@ -13,16 +13,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
 	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
 	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/testsupport"
 	mockpost "pinstack-post-service/mocks/post"
-
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func TestUpdatePostHandler_UpdatePost(t *testing.T) {
@@ -53,43 +54,29 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 			},
 		}
 
-		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
+		updateCall := mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
 			return dto.UserID == req.GetUserId() &&
 				*dto.Title == req.Title &&
 				*dto.Content == req.Content &&
 				len(dto.Tags) == len(req.Tags) &&
 				len(dto.MediaItems) == len(req.Media)
-		})).Return(nil)
+		}))
 
 		createdAt := time.Now().Add(-24 * time.Hour)
 		updatedAt := time.Now()
 
-		expectedPostDetailed := &model.PostDetailed{
-			Post: &model.Post{
-				ID:        postID,
-				AuthorID:  userID,
-				Title:     title,
-				Content:   &content,
-				CreatedAt: pgtype.Timestamp{Time: createdAt, Valid: true},
-				UpdatedAt: pgtype.Timestamp{Time: updatedAt, Valid: true},
-			},
-			Media: []*model.PostMedia{
-				{
-					ID:        1,
-					PostID:    postID,
-					URL:       "https://example.com/new-image.jpg",
-					Type:      "image",
-					Position:  1,
-					CreatedAt: pgtype.Timestamptz{Time: updatedAt, Valid: true},
-				},
-			},
-			Tags: []*model.Tag{
-				{ID: 1, Name: "updated"},
-				{ID: 2, Name: "golang"},
-			},
-		}
+		expectedPostDetailed := testsupport.NewPostDetailedBuilder().
+			WithID(postID).
+			WithAuthor(userID).
+			WithTitle(title).
+			WithContent(content).
+			Post(func(b *testsupport.PostBuilder) { b.CreatedAt(createdAt).UpdatedAt(updatedAt) }).
+			WithMedia("https://example.com/new-image.jpg").
+			WithTags("updated", "golang").
+			WithoutAuthor().
+			Build()
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).Return(expectedPostDetailed, nil)
+		updateCall.Return(expectedPostDetailed, nil)
 
 		resp, err := handler.UpdatePost(context.Background(), req)
 
@@ -129,40 +116,35 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		postID := int64(456)
 		title := "Only Title Updated"
 
+		// Fields left out of the request are optional and stay as they are.
 		req := &pb.UpdatePostRequest{
-			UserId:  userID,
-			Id:      postID,
-			Title:   title,
-			Content: "",
-			Tags:    []string{},
+			UserId: userID,
+			Id:     postID,
+			Title:  title,
 		}
 
-		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
+		updateCall := mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
 			return dto.UserID == req.GetUserId() &&
 				*dto.Title == req.Title &&
-				*dto.Content == req.Content &&
+				dto.Content == nil &&
 				len(dto.Tags) == 0 &&
 				len(dto.MediaItems) == 0
-		})).Return(nil)
+		}))
 
 		createdAt := time.Now().Add(-24 * time.Hour)
 		updatedAt := time.Now()
 		originalContent := "Original content that wasn't changed"
 
-		expectedPostDetailed := &model.PostDetailed{
-			Post: &model.Post{
-				ID:        postID,
-				AuthorID:  userID,
-				Title:     title,
-				Content:   &originalContent,
-				CreatedAt: pgtype.Timestamp{Time: createdAt, Valid: true},
-				UpdatedAt: pgtype.Timestamp{Time: updatedAt, Valid: true},
-			},
-			Media: nil,
-			Tags:  nil,
-		}
+		expectedPostDetailed := testsupport.NewPostDetailedBuilder().
+			WithID(postID).
+			WithAuthor(userID).
+			WithTitle(title).
+			WithContent(originalContent).
+			Post(func(b *testsupport.PostBuilder) { b.CreatedAt(createdAt).UpdatedAt(updatedAt) }).
+			WithoutAuthor().
+			Build()
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).Return(expectedPostDetailed, nil)
+		updateCall.Return(expectedPostDetailed, nil)
 
 		resp, err := handler.UpdatePost(context.Background(), req)
 
@@ -175,6 +157,47 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		mockPostService.AssertExpectations(t)
 	})
 
+	t.Run("Success_TagsOnly", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+
+		req := &pb.UpdatePostRequest{UserId: 123, Id: 456, Tags: []string{"go"}}
+		content := "Unchanged content"
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
+			return dto.Title == nil && dto.Content == nil && !dto.ChangesFields() &&
+				assert.ObjectsAreEqual([]string{"go"}, dto.Tags)
+		})).Return(&model.PostDetailed{
+			Post: &model.Post{ID: 456, AuthorID: 123, Title: "Unchanged title", Content: &content},
+			Tags: []*model.Tag{{ID: 1, Name: "go"}},
+		}, nil)
+
+		resp, err := handler.UpdatePost(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Unchanged title", resp.Title)
+		assert.Equal(t, []string{"go"}, resp.Tags)
+		mockPostService.AssertExpectations(t)
+	})
+
+	t.Run("EmptyUpdate", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+
+		req := &pb.UpdatePostRequest{UserId: 123, Id: 456}
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
+			return dto.IsEmpty()
+		})).Return(nil, model.ErrEmptyUpdate)
+
+		resp, err := handler.UpdatePost(context.Background(), req)
+
+		assert.Nil(t, resp)
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, errinfo.ReasonEmptyUpdate, errinfo.Reason(err))
+		mockPostService.AssertExpectations(t)
+	})
+
 	t.Run("ValidationError", func(t *testing.T) {
 		mockPostService := new(mockpost.Service)
 		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
@@ -194,10 +217,9 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
-		assert.Contains(t, statusErr.Message(), "invalid request")
+		assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
 
 		mockPostService.AssertNotCalled(t, "UpdatePost")
-		mockPostService.AssertNotCalled(t, "GetPostByID")
 	})
 
 	t.Run("ValidationError_InvalidMedia", func(t *testing.T) {
@@ -247,7 +269,7 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		}
 
 		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.Anything).
-			Return(custom_errors.ErrPostNotFound)
+			Return(nil, custom_errors.ErrPostNotFound)
 
 		resp, err := handler.UpdatePost(context.Background(), req)
 
@@ -257,9 +279,26 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.NotFound, statusErr.Code())
-		assert.Contains(t, statusErr.Message(), "post not found")
+		assert.Equal(t, errinfo.ReasonPostNotFound, errinfo.Reason(err))
 
-		mockPostService.AssertNotCalled(t, "GetPostByID")
+	})
+
+	t.Run("DuplicateMedia", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+
+		req := &pb.UpdatePostRequest{UserId: 123, Id: 456, Title: "Updated Title"}
+
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), mock.Anything).
+			Return(nil, model.ErrMediaDuplicate)
+
+		resp, err := handler.UpdatePost(context.Background(), req)
+
+		assert.Nil(t, resp)
+		statusErr, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
+		assert.Equal(t, errinfo.ReasonMediaDuplicate, errinfo.Reason(err))
 	})
 
 	t.Run("ValidationErrorFromService", func(t *testing.T) {
@@ -279,7 +318,7 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		}
 
 		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.Anything).
-			Return(custom_errors.ErrPostValidation)
+			Return(nil, custom_errors.ErrPostValidation)
 
 		resp, err := handler.UpdatePost(context.Background(), req)
 
@@ -289,10 +328,10 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
-		assert.Contains(t, statusErr.Message(), "validation failed")
+		assert.Equal(t, errinfo.ReasonValidationFailed, errinfo.Reason(err))
 	})
 
-	t.Run("NotAuthorError_InvalidInput", func(t *testing.T) {
+	t.Run("InvalidInputFromService", func(t *testing.T) {
 		mockPostService := new(mockpost.Service)
 		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
 
@@ -309,7 +348,7 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		}
 
 		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.Anything).
-			Return(custom_errors.ErrInvalidInput)
+			Return(nil, custom_errors.ErrInvalidInput)
 
 		resp, err := handler.UpdatePost(context.Background(), req)
 
@@ -318,8 +357,8 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
-		assert.Equal(t, codes.PermissionDenied, statusErr.Code())
-		assert.Contains(t, statusErr.Message(), "forbidden")
+		assert.Equal(t, codes.InvalidArgument, statusErr.Code())
+		assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
 	})
 
 	t.Run("NotAuthorError_Forbidden", func(t *testing.T) {
@@ -339,7 +378,7 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		}
 
 		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.Anything).
-			Return(custom_errors.ErrForbidden)
+			Return(nil, custom_errors.ErrForbidden)
 
 		resp, err := handler.UpdatePost(context.Background(), req)
 
@@ -349,7 +388,7 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.PermissionDenied, statusErr.Code())
-		assert.Contains(t, statusErr.Message(), "forbidden")
+		assert.Equal(t, errinfo.ReasonNotAuthor, errinfo.Reason(err))
 	})
 
 	t.Run("InternalError_Update", func(t *testing.T) {
@@ -369,7 +408,7 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		}
 
 		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.Anything).
-			Return(errors.New("database error"))
+			Return(nil, errors.New("database error"))
 
 		resp, err := handler.UpdatePost(context.Background(), req)
 
@@ -379,105 +418,215 @@ func TestUpdatePostHandler_UpdatePost(t *testing.T) {
 		statusErr, ok := status.FromError(err)
 		assert.True(t, ok)
 		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Contains(t, statusErr.Message(), "internal service error")
+		assert.Equal(t, errinfo.ReasonInternal, errinfo.Reason(err))
 	})
+}
 
-	t.Run("GetError_PostNotFound", func(t *testing.T) {
+func TestUpdatePostHandler_UpdatePostVisibility(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	req := &pb.UpdatePostRequest{UserId: 123, Id: 456}
+
+	t.Run("VisibilityOnly", func(t *testing.T) {
 		mockPostService := new(mockpost.Service)
 		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
+			return dto.Title == nil && dto.Content == nil && dto.ChangesFields() &&
+				dto.Visibility != nil && *dto.Visibility == model.PostVisibilityUnlisted
+		})).Return(&model.PostDetailed{
+			Post: &model.Post{ID: 456, AuthorID: 123, Title: "Unchanged title", Visibility: model.PostVisibilityUnlisted},
+		}, nil)
 
-		userID := int64(123)
-		postID := int64(456)
-		title := "Updated Title"
-		content := "Updated Content"
+		resp, err := handler.UpdatePostVisibility(context.Background(), req, "unlisted")
 
-		req := &pb.UpdatePostRequest{
-			UserId:  userID,
-			Id:      postID,
-			Title:   title,
-			Content: content,
-		}
+		require.NoError(t, err)
+		assert.Equal(t, int64(456), resp.Id)
+		mockPostService.AssertExpectations(t)
+	})
 
-		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.Anything).
-			Return(nil)
+	t.Run("InvalidVisibility", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).
-			Return(nil, custom_errors.ErrPostNotFound)
+		_, err := handler.UpdatePostVisibility(context.Background(), req, "")
 
-		resp, err := handler.UpdatePost(context.Background(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPostService.AssertNotCalled(t, "UpdatePost", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
 
-		assert.Nil(t, resp)
-		assert.Error(t, err)
+func TestUpdatePostHandler_UpdatePost_RejectsUnknownMediaType(t *testing.T) {
+	mockPostService := new(mockpost.Service)
+	handler := post_grpc.NewUpdatePostHandler(mockPostService, validator.New(), logger.New("test"))
 
-		statusErr, ok := status.FromError(err)
-		assert.True(t, ok)
-		assert.Equal(t, codes.NotFound, statusErr.Code())
-		assert.Contains(t, statusErr.Message(), "post not found")
+	_, err := handler.UpdatePost(context.Background(), &pb.UpdatePostRequest{
+		UserId: 123,
+		Id:     456,
+		Media:  []*pb.MediaInput{{Url: "https://example.com/1.jpg", Type: "gif", Position: 1}},
 	})
 
-	t.Run("GetError_Forbidden", func(t *testing.T) {
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, errinfo.ReasonInvalidArgument, errinfo.Reason(err))
+	assert.Contains(t, status.Convert(err).Message(), `media[0].type: invalid input: invalid media type: "gif"`)
+	mockPostService.AssertNotCalled(t, "UpdatePost", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdatePostHandler_UpdatePostLanguage(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	req := &pb.UpdatePostRequest{UserId: 123, Id: 456}
+
+	t.Run("LanguageOnly", func(t *testing.T) {
 		mockPostService := new(mockpost.Service)
 		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
+			return dto.Title == nil && dto.ChangesFields() && dto.Language != nil && *dto.Language == "en"
+		})).Return(&model.PostDetailed{
+			Post: &model.Post{ID: 456, AuthorID: 123, Title: "Unchanged title"},
+		}, nil)
 
-		userID := int64(123)
-		postID := int64(456)
-		title := "Updated Title"
-		content := "Updated Content"
+		resp, err := handler.UpdatePostLanguage(context.Background(), req, "en")
 
-		req := &pb.UpdatePostRequest{
-			UserId:  userID,
-			Id:      postID,
-			Title:   title,
-			Content: content,
+		require.NoError(t, err)
+		assert.Equal(t, int64(456), resp.Id)
+		mockPostService.AssertExpectations(t)
+	})
+
+	for name, language := range map[string]string{"Empty": "", "Malformed": "english!"} {
+		t.Run(name, func(t *testing.T) {
+			mockPostService := new(mockpost.Service)
+			handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+
+			_, err := handler.UpdatePostLanguage(context.Background(), req, language)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			mockPostService.AssertNotCalled(t, "UpdatePost", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdatePostHandler_UpdatePostWithSummary(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	req := &pb.UpdatePostRequest{
+		UserId: 123,
+		Id:     456,
+		Title:  "Renamed",
+		Tags:   []string{"go", "grpc"},
+		Media:  []*pb.MediaInput{{Url: "https://example.com/new.jpg", Type: "image", Position: 1}},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+		media := []*model.PostMedia{{ID: 12, PostID: 456, URL: "https://example.com/new.jpg", Type: model.MediaTypeImage, Position: 1}}
+		tags := []*model.Tag{{ID: 1, Name: "go"}, {ID: 7, Name: "grpc"}}
+		changes := &model.PostChangeSummary{
+			FieldsChanged: []string{"title"},
+			TagsAdded:     []*model.Tag{tags[1]},
+			TagsCreated:   []*model.Tag{tags[1]},
+			TagsRemoved:   []*model.Tag{{ID: 3, Name: "rest"}},
+			MediaAttached: media,
+			MediaDetached: []int64{10, 11},
 		}
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), mock.AnythingOfType("*model.UpdatePostDTO")).Return(&model.PostDetailed{
+			Post:    &model.Post{ID: 456, AuthorID: 123, Title: "Renamed"},
+			Media:   media,
+			Tags:    tags,
+			Changes: changes,
+		}, nil)
 
-		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.Anything).
-			Return(nil)
+		resp, err := handler.UpdatePostWithSummary(context.Background(), req)
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).
-			Return(nil, custom_errors.ErrForbidden)
+		require.NoError(t, err)
+		assert.Equal(t, int64(456), resp.Post.Id)
+		assert.Equal(t, "Renamed", resp.Post.Title)
+		assert.Equal(t, []string{"go", "grpc"}, resp.Post.Tags)
+		require.Len(t, resp.Post.Media, 1)
+		assert.Equal(t, int64(12), resp.Post.Media[0].Id)
+		assert.Same(t, changes, resp.ChangeSummary)
+		mockPostService.AssertExpectations(t)
+	})
 
-		resp, err := handler.UpdatePost(context.Background(), req)
+	t.Run("NotFound", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), mock.AnythingOfType("*model.UpdatePostDTO")).
+			Return(nil, custom_errors.ErrPostNotFound)
 
-		assert.Nil(t, resp)
-		assert.Error(t, err)
+		resp, err := handler.UpdatePostWithSummary(context.Background(), req)
 
-		statusErr, ok := status.FromError(err)
-		assert.True(t, ok)
-		assert.Equal(t, codes.PermissionDenied, statusErr.Code())
-		assert.Contains(t, statusErr.Message(), "forbidden")
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.NotFound, status.Code(err))
 	})
+}
 
-	t.Run("InternalError_Get", func(t *testing.T) {
+func TestUpdatePostHandler_UpdatePostIfVersion(t *testing.T) {
+	validate := validator.New()
+	testLogger := logger.New("test")
+	req := &pb.UpdatePostRequest{UserId: 123, Id: 456, Title: "Renamed", Content: "Body"}
+	withVersion := func(version int64) interface{} {
+		return mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
+			return dto.ExpectedVersion != nil && *dto.ExpectedVersion == version
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
 		mockPostService := new(mockpost.Service)
 		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), withVersion(3)).
+			Return(&model.PostDetailed{Post: &model.Post{ID: 456, AuthorID: 123, Title: "Renamed", Version: 4}}, nil)
 
-		userID := int64(123)
-		postID := int64(456)
-		title := "Updated Title"
-		content := "Updated Content"
+		resp, err := handler.UpdatePostIfVersion(context.Background(), req, 3)
 
-		req := &pb.UpdatePostRequest{
-			UserId:  userID,
-			Id:      postID,
-			Title:   title,
-			Content: content,
-		}
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", resp.Post.Title)
+		assert.Equal(t, int64(4), resp.Version)
+		mockPostService.AssertExpectations(t)
+	})
 
-		mockPostService.On("UpdatePost", mock.Anything, userID, postID, mock.Anything).
-			Return(nil)
+	t.Run("Conflict", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), withVersion(3)).
+			Return(nil, &model.VersionConflictError{PostID: 456, CurrentVersion: 5})
 
-		mockPostService.On("GetPostByID", mock.Anything, postID).
-			Return(nil, errors.New("database error"))
+		resp, err := handler.UpdatePostIfVersion(context.Background(), req, 3)
 
-		resp, err := handler.UpdatePost(context.Background(), req)
+		assert.Nil(t, resp)
+		statusErr, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.Aborted, statusErr.Code())
+		require.Len(t, statusErr.Details(), 1)
+		info, ok := statusErr.Details()[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, "VERSION_CONFLICT", info.Reason)
+		assert.Equal(t, "5", info.Metadata["current_version"])
+		assert.Equal(t, "456", info.Metadata["post_id"])
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), withVersion(3)).
+			Return(nil, custom_errors.ErrPostNotFound)
+
+		resp, err := handler.UpdatePostIfVersion(context.Background(), req, 3)
 
 		assert.Nil(t, resp)
-		assert.Error(t, err)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
 
-		statusErr, ok := status.FromError(err)
-		assert.True(t, ok)
-		assert.Equal(t, codes.Internal, statusErr.Code())
-		assert.Contains(t, statusErr.Message(), "internal")
+	t.Run("UpdatePost sends no expected version", func(t *testing.T) {
+		mockPostService := new(mockpost.Service)
+		handler := post_grpc.NewUpdatePostHandler(mockPostService, validate, testLogger)
+		mockPostService.On("UpdatePost", mock.Anything, int64(123), int64(456), mock.MatchedBy(func(dto *model.UpdatePostDTO) bool {
+			return dto.ExpectedVersion == nil
+		})).Return(&model.PostDetailed{Post: &model.Post{ID: 456, AuthorID: 123, Title: "Renamed", Version: 2}}, nil)
+
+		_, err := handler.UpdatePost(context.Background(), req)
+
+		require.NoError(t, err)
+		mockPostService.AssertExpectations(t)
 	})
 }
@@ -0,0 +1,27 @@
+package post_grpc
+
+import (
+	"fmt"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mediaTypeStatus returns InvalidArgument naming the first requested media item whose type is
+// not a model.MediaType, or nil when every type is one. Nil items are skipped, as the mapper
+// skips them.
+func mediaTypeStatus(media []*pb.MediaInput) *status.Status {
+	for i, m := range media {
+		if m == nil {
+			continue
+		}
+		if _, err := model.ParseMediaType(m.GetType()); err != nil {
+			return errinfo.New(codes.InvalidArgument, errinfo.ReasonInvalidArgument, fmt.Sprintf("media[%d].type: %v", i, err))
+		}
+	}
+	return nil
+}
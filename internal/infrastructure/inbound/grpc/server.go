@@ -1,35 +1,52 @@
 package delivery_grpc
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net"
 	ports "pinstack-post-service/internal/domain/ports/output"
+	debug_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/debug"
 	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
 	"pinstack-post-service/internal/infrastructure/inbound/middleware"
-	"runtime/debug"
-
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
-	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 type Server struct {
 	postGRPCService *post_grpc.PostGRPCService
 	server          *grpc.Server
+	health          *health.Server
 	address         string
 	port            int
 	log             ports.Logger
 	metrics         ports.MetricsProvider
 }
 
-func NewServer(grpcServer *post_grpc.PostGRPCService, address string, port int, log ports.Logger, metrics ports.MetricsProvider) *Server {
+// NewServer serves grpcServer with the interceptor chain. opts, usually from ServerOptions,
+// set the transport; without them the server is plaintext with gRPC defaults.
+func NewServer(grpcServer *post_grpc.PostGRPCService, address string, port int, log ports.Logger, metrics ports.MetricsProvider, opts ...grpc.ServerOption) *Server {
+	server := grpc.NewServer(append([]grpc.ServerOption{
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+			middleware.UnaryRecoveryInterceptor(log, metrics),
+			middleware.UnaryLoggerInterceptor(log),
+			middleware.UnaryMetricsInterceptor(metrics),
+			middleware.UnaryAdminInterceptor(log, debug_grpc.GetDebugInfoFullMethod, debug_grpc.ReconcileOrphansFullMethod),
+		)),
+	}, opts...)...)
+	pb.RegisterPostServiceServer(server, grpcServer)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
 	return &Server{
 		postGRPCService: grpcServer,
+		server:          server,
+		health:          healthServer,
 		address:         address,
 		port:            port,
 		log:             log,
@@ -37,6 +54,17 @@ func NewServer(grpcServer *post_grpc.PostGRPCService, address string, port int,
 	}
 }
 
+// RegisterDebug serves the debug service, which only answers calls with the internal admin
+// flag. Call it before Serve.
+func (s *Server) RegisterDebug(debug debug_grpc.DebugServer) {
+	debug_grpc.Register(s.server, debug)
+}
+
+// EnableReflection serves the gRPC reflection service. Call it before Serve.
+func (s *Server) EnableReflection() {
+	reflection.Register(s.server)
+}
+
 func (s *Server) Run() error {
 	address := fmt.Sprintf("%s:%d", s.address, s.port)
 	lis, err := net.Listen("tcp", address)
@@ -44,30 +72,35 @@ func (s *Server) Run() error {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
 
-	opts := []grpc_recovery.Option{
-		grpc_recovery.WithRecoveryHandler(func(p interface{}) (err error) {
-			s.log.Error("panic recovered", slog.Any("panic", p), slog.String("stack", string(debug.Stack())))
-			return status.Errorf(codes.Internal, "internal server error")
-		}),
-	}
-
-	s.server = grpc.NewServer(
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			middleware.UnaryLoggerInterceptor(s.log),
-			middleware.UnaryMetricsInterceptor(s.metrics),
-			grpc_recovery.UnaryServerInterceptor(opts...),
-		)),
-	)
-
-	pb.RegisterPostServiceServer(s.server, s.postGRPCService)
-
 	s.log.Info("Starting gRPC server", slog.Int("port", s.port))
+	return s.Serve(lis)
+}
+
+// Serve accepts connections on lis until Shutdown is called. The health service reports
+// SERVING until then.
+func (s *Server) Serve(lis net.Listener) error {
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(pb.PostService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
 	return s.server.Serve(lis)
 }
 
-func (s *Server) Shutdown() error {
-	if s.server != nil {
+// Shutdown reports NOT_SERVING, stops accepting requests and waits for the in-flight ones.
+// When ctx ends first, the remaining requests are cancelled and ctx's error is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
 		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.log.Warn("gRPC drain timed out, cancelling in-flight requests")
+		s.server.Stop()
+		<-stopped
+		return fmt.Errorf("drain in-flight requests: %w", ctx.Err())
 	}
-	return nil
 }
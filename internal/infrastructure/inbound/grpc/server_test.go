@@ -0,0 +1,169 @@
+package delivery_grpc_test
+
+import (
+	"context"
+	"net"
+	"os/signal"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	delivery_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+// panicMetrics records recovered panics and forwards everything else to Prometheus.
+type panicMetrics struct {
+	ports.MetricsProvider
+	mu     sync.Mutex
+	panics []string
+}
+
+func (m *panicMetrics) IncrementPanics(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.panics = append(m.panics, method)
+}
+
+func TestServer_RecoversFromPanics(t *testing.T) {
+	service := new(mockpost.Service)
+	service.On("GetPostByID", mock.Anything, int64(1), mock.Anything).
+		Run(func(mock.Arguments) { panic("boom") })
+	service.On("GetPostByID", mock.Anything, int64(2), mock.Anything).
+		Return(&model.PostDetailed{Post: &model.Post{ID: 2, AuthorID: 1, Title: "Title"}}, nil)
+
+	log := logger.New("test")
+	metrics := &panicMetrics{MetricsProvider: prometheus.NewPrometheusMetricsProvider()}
+	server := delivery_grpc.NewServer(post_grpc.NewPostGRPCService(service, log), "127.0.0.1", 0, log, metrics)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := pb.NewPostServiceClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1")
+	_, err = client.GetPost(ctx, &pb.GetPostRequest{Id: 1})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	post, err := client.GetPost(context.Background(), &pb.GetPostRequest{Id: 2})
+	require.NoError(t, err, "the server must keep serving after a panic")
+	assert.Equal(t, int64(2), post.Id)
+
+	_, err = client.GetPost(context.Background(), &pb.GetPostRequest{Id: 1})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	assert.Equal(t, []string{pb.PostService_GetPost_FullMethodName, pb.PostService_GetPost_FullMethodName}, metrics.panics)
+}
+
+// startSlowServer serves GetPost for post 1 only after release is closed, or fails it when
+// the request is cancelled, and reports on started when such a request has arrived.
+func startSlowServer(t *testing.T) (server *delivery_grpc.Server, conn *grpc.ClientConn, started, release chan struct{}) {
+	t.Helper()
+	started, release = make(chan struct{}), make(chan struct{})
+	service := new(mockpost.Service)
+	service.On("GetPostByID", mock.Anything, int64(1), mock.Anything).
+		Run(func(args mock.Arguments) {
+			close(started)
+			select {
+			case <-release:
+			case <-args.Get(0).(context.Context).Done():
+			}
+		}).
+		Return(&model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: 1, Title: "Title"}}, nil)
+
+	log := logger.New("test")
+	server = delivery_grpc.NewServer(post_grpc.NewPostGRPCService(service, log), "127.0.0.1", 0, log, prometheus.NewPrometheusMetricsProvider())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(lis) }()
+
+	conn, err = grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return server, conn, started, release
+}
+
+func TestServer_SIGTERMDrainsInFlightRequests(t *testing.T) {
+	server, conn, started, release := startSlowServer(t)
+	health := healthpb.NewHealthClient(conn)
+	serving, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, serving.Status)
+
+	sigCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+
+	type result struct {
+		post *pb.Post
+		err  error
+	}
+	slow := make(chan result, 1)
+	go func() {
+		post, err := pb.NewPostServiceClient(conn).GetPost(context.Background(), &pb.GetPostRequest{Id: 1})
+		slow <- result{post, err}
+	}()
+	<-started
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+	<-sigCtx.Done()
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- server.Shutdown(drainCtx) }()
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("shutdown returned before the in-flight request finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	got := <-slow
+	require.NoError(t, got.err, "the in-flight request completes")
+	assert.Equal(t, int64(1), got.post.Id)
+	assert.NoError(t, <-shutdown)
+}
+
+func TestServer_ShutdownCancelsRequestsAfterDrainTimeout(t *testing.T) {
+	server, conn, started, release := startSlowServer(t)
+	defer close(release)
+
+	slow := make(chan error, 1)
+	go func() {
+		_, err := pb.NewPostServiceClient(conn).GetPost(context.Background(), &pb.GetPostRequest{Id: 1})
+		slow <- err
+	}()
+	<-started
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := server.Shutdown(drainCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, codes.Unavailable, status.Code(<-slow))
+}
@@ -0,0 +1,201 @@
+package delivery_grpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/soloda1/pinstack-proto-definitions/gen/go/pinstack-proto-definitions/post/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/config"
+	delivery_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/infrastructure/tlsconfig"
+	mockpost "pinstack-post-service/mocks/post"
+)
+
+// testPKI is a self-signed CA with a server certificate for 127.0.0.1 and a client
+// certificate, written as PEM files.
+type testPKI struct {
+	caFile, certFile, keyFile string
+	clientCert                tls.Certificate
+}
+
+func newTestPKI(t *testing.T) testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "post-service"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	pki := testPKI{
+		caFile:   filepath.Join(dir, "ca.pem"),
+		certFile: filepath.Join(dir, "server.pem"),
+		keyFile:  filepath.Join(dir, "server-key.pem"),
+	}
+	require.NoError(t, os.WriteFile(pki.caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0o600))
+	serverCert, serverKey := issue(2, x509.ExtKeyUsageServerAuth)
+	require.NoError(t, os.WriteFile(pki.certFile, serverCert, 0o600))
+	require.NoError(t, os.WriteFile(pki.keyFile, serverKey, 0o600))
+	clientCert, clientKey := issue(3, x509.ExtKeyUsageClientAuth)
+	pki.clientCert, err = tls.X509KeyPair(clientCert, clientKey)
+	require.NoError(t, err)
+	return pki
+}
+
+func serverConfig(tlsConfig config.ServerTLS) config.GRPCServer {
+	return config.GRPCServer{
+		MaxRecvMsgSize: 16 << 20,
+		MaxSendMsgSize: 16 << 20,
+		TLS:            tlsConfig,
+		Keepalive:      config.GRPCKeepalive{MinTime: 30 * time.Second, Time: 2 * time.Hour, Timeout: 20 * time.Second},
+	}
+}
+
+// startServer serves a post service that knows post 1 and returns its address.
+func startServer(t *testing.T, cfg config.GRPCServer) string {
+	t.Helper()
+	service := new(mockpost.Service)
+	service.On("GetPostByID", mock.Anything, int64(1), mock.Anything).
+		Return(&model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: 1, Title: "Title"}}, nil)
+	service.On("CreatePost", mock.Anything, mock.Anything).
+		Return(&model.PostDetailed{Post: &model.Post{ID: 2, AuthorID: 1, Title: "Title"}}, nil)
+
+	opts, err := delivery_grpc.ServerOptions(cfg)
+	require.NoError(t, err)
+	log := logger.New("test")
+	server := delivery_grpc.NewServer(post_grpc.NewPostGRPCService(service, log), "127.0.0.1", 0, log,
+		prometheus.NewPrometheusMetricsProvider(), opts...)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+	return lis.Addr().String()
+}
+
+func getPost(t *testing.T, address string, creds credentials.TransportCredentials) error {
+	t.Helper()
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = pb.NewPostServiceClient(conn).GetPost(ctx, &pb.GetPostRequest{Id: 1})
+	return err
+}
+
+func TestServer_TLS(t *testing.T) {
+	pki := newTestPKI(t)
+	address := startServer(t, serverConfig(config.ServerTLS{CertFile: pki.certFile, KeyFile: pki.keyFile}))
+
+	clientTLS, err := tlsconfig.Client(config.ClientTLS{CAFile: pki.caFile})
+	require.NoError(t, err)
+	assert.NoError(t, getPost(t, address, credentials.NewTLS(clientTLS)), "handshake with the trusted CA")
+
+	err = getPost(t, address, insecure.NewCredentials())
+	assert.Equal(t, codes.Unavailable, status.Code(err), "plaintext clients are refused")
+
+	untrusting, err := tlsconfig.Client(config.ClientTLS{})
+	require.NoError(t, err)
+	err = getPost(t, address, credentials.NewTLS(untrusting))
+	assert.Equal(t, codes.Unavailable, status.Code(err), "the self-signed CA is not in the system roots")
+
+	renamed, err := tlsconfig.Client(config.ClientTLS{CAFile: pki.caFile, ServerName: "other.example.com"})
+	require.NoError(t, err)
+	err = getPost(t, address, credentials.NewTLS(renamed))
+	assert.Equal(t, codes.Unavailable, status.Code(err), "the server name override is checked")
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	address := startServer(t, serverConfig(config.ServerTLS{CertFile: pki.certFile, KeyFile: pki.keyFile, ClientCAFile: pki.caFile}))
+
+	clientTLS, err := tlsconfig.Client(config.ClientTLS{CAFile: pki.caFile})
+	require.NoError(t, err)
+	err = getPost(t, address, credentials.NewTLS(clientTLS))
+	assert.Error(t, err, "clients without a certificate are refused")
+
+	clientTLS.Certificates = []tls.Certificate{pki.clientCert}
+	assert.NoError(t, getPost(t, address, credentials.NewTLS(clientTLS)))
+}
+
+func TestServer_MaxRecvMsgSize(t *testing.T) {
+	cfg := serverConfig(config.ServerTLS{Insecure: true})
+	cfg.MaxRecvMsgSize = 1024
+	address := startServer(t, cfg)
+
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewPostServiceClient(conn)
+
+	_, err = client.CreatePost(context.Background(), &pb.CreatePostRequest{AuthorId: 1, Title: "Title", Content: strings.Repeat("a", 2048)})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestServerOptions_BadCertificate(t *testing.T) {
+	pki := newTestPKI(t)
+
+	_, err := delivery_grpc.ServerOptions(serverConfig(config.ServerTLS{CertFile: pki.certFile, KeyFile: pki.caFile}))
+	assert.ErrorContains(t, err, "failed to load server certificate")
+
+	_, err = delivery_grpc.ServerOptions(serverConfig(config.ServerTLS{CertFile: pki.certFile, KeyFile: pki.keyFile, ClientCAFile: pki.keyFile}))
+	assert.ErrorContains(t, err, "holds no PEM certificates")
+}
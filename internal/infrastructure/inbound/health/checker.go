@@ -0,0 +1,96 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCheckTimeout bounds each dependency check, so one hanging dependency cannot hold a
+// probe past its own timeout.
+const DefaultCheckTimeout = 2 * time.Second
+
+// ShuttingDown is the failure reported for every probe once shutdown has begun.
+const ShuttingDown = "shutting_down"
+
+// Check reports whether a dependency is usable; a nil error means healthy.
+type Check func(ctx context.Context) error
+
+// Report is the result of one readiness check: Failing maps each unhealthy dependency to
+// the reason it failed.
+type Report struct {
+	Ready   bool              `json:"ready"`
+	Failing map[string]string `json:"failing,omitempty"`
+}
+
+// Checker runs the readiness checks of the service's dependencies. It is shared by the
+// probes that report readiness, so they always agree.
+type Checker struct {
+	timeout      time.Duration
+	mu           sync.RWMutex
+	checks       map[string]Check
+	shuttingDown atomic.Bool
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Add registers check under name, replacing any check of that name.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// SetShuttingDown makes every later check fail, so load balancers stop routing to the
+// instance before its listeners close.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Check runs every check concurrently, each bounded by the checker timeout.
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.shuttingDown.Load() {
+		return Report{Failing: map[string]string{ShuttingDown: "the service is shutting down"}}
+	}
+
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		failing = make(map[string]string)
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			if err := check(checkCtx); err != nil {
+				mu.Lock()
+				failing[name] = err.Error()
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failing) > 0 {
+		return Report{Failing: failing}
+	}
+	return Report{Ready: true}
+}
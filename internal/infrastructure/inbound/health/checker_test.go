@@ -0,0 +1,90 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/connectivity"
+
+	"pinstack-post-service/internal/infrastructure/inbound/health"
+)
+
+func TestChecker_Check(t *testing.T) {
+	healthy := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name        string
+		checks      map[string]health.Check
+		wantReady   bool
+		wantFailing map[string]string
+	}{
+		{name: "no checks", wantReady: true},
+		{name: "all healthy", checks: map[string]health.Check{"postgres": healthy, "redis": healthy}, wantReady: true},
+		{
+			name:        "one down",
+			checks:      map[string]health.Check{"postgres": healthy, "redis": down},
+			wantFailing: map[string]string{"redis": "connection refused"},
+		},
+		{
+			name:        "hanging check times out",
+			checks:      map[string]health.Check{"postgres": hanging, "redis": healthy},
+			wantFailing: map[string]string{"postgres": context.DeadlineExceeded.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := health.NewChecker(50 * time.Millisecond)
+			for name, check := range tt.checks {
+				checker.Add(name, check)
+			}
+
+			report := checker.Check(context.Background())
+
+			assert.Equal(t, tt.wantReady, report.Ready)
+			assert.Equal(t, tt.wantFailing, report.Failing)
+		})
+	}
+}
+
+func TestChecker_ShuttingDown(t *testing.T) {
+	checker := health.NewChecker(time.Second)
+	checker.Add("postgres", func(ctx context.Context) error { return nil })
+	assert.True(t, checker.Check(context.Background()).Ready)
+
+	checker.SetShuttingDown()
+
+	report := checker.Check(context.Background())
+	assert.False(t, report.Ready)
+	assert.Contains(t, report.Failing, health.ShuttingDown)
+}
+
+type fakeConn connectivity.State
+
+func (c fakeConn) GetState() connectivity.State { return connectivity.State(c) }
+
+func TestConnCheck(t *testing.T) {
+	for _, tt := range []struct {
+		state   connectivity.State
+		healthy bool
+	}{
+		{connectivity.Idle, true},
+		{connectivity.Connecting, true},
+		{connectivity.Ready, true},
+		{connectivity.TransientFailure, false},
+		{connectivity.Shutdown, false},
+	} {
+		t.Run(tt.state.String(), func(t *testing.T) {
+			err := health.ConnCheck(fakeConn(tt.state))(context.Background())
+			assert.Equal(t, tt.healthy, err == nil)
+		})
+	}
+}
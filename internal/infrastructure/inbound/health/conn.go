@@ -0,0 +1,25 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+// ConnState is the part of a gRPC client connection a check reads; *grpc.ClientConn has it.
+type ConnState interface {
+	GetState() connectivity.State
+}
+
+// ConnCheck fails while conn is in transient failure or shut down. An idle connection counts
+// as healthy: it dials on the next call.
+func ConnCheck(conn ConnState) Check {
+	return func(ctx context.Context) error {
+		switch state := conn.GetState(); state {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return fmt.Errorf("connection is %s", state)
+		}
+		return nil
+	}
+}
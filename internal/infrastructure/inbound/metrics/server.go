@@ -2,38 +2,72 @@ package metrics
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
 	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/inbound/health"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Server serves Prometheus metrics and the liveness and readiness probes.
 type Server struct {
 	server  *http.Server
 	address string
 	port    int
+	checker *health.Checker
 	log     ports.Logger
 }
 
-func NewMetricsServer(address string, port int, log ports.Logger) *Server {
+func NewMetricsServer(address string, port int, checker *health.Checker, log ports.Logger) *Server {
 	return &Server{
 		address: address,
 		port:    port,
+		checker: checker,
 		log:     log,
 	}
 }
 
-func (s *Server) Run() error {
-	addr := fmt.Sprintf("%s:%d", s.address, s.port)
-
+// Handler routes /metrics, /healthz and /readyz.
+func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/readyz", s.readyz)
+	return mux
+}
+
+// healthz answers 200 for as long as the process serves HTTP.
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// readyz answers 200 when every dependency is healthy and 503 otherwise, with the report as
+// JSON either way.
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	report := s.checker.Check(r.Context())
+	code := http.StatusOK
+	if !report.Ready {
+		code = http.StatusServiceUnavailable
+		s.log.Warn("Readiness check failed", slog.Any("failing", report.Failing))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		s.log.Debug("Failed to write readiness report", slog.String("error", err.Error()))
+	}
+}
+
+func (s *Server) Run() error {
+	addr := fmt.Sprintf("%s:%d", s.address, s.port)
 
 	s.server = &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: s.Handler(),
 	}
 
 	s.log.Info("Starting Prometheus metrics server", slog.String("address", addr))
@@ -0,0 +1,96 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/inbound/health"
+	"pinstack-post-service/internal/infrastructure/inbound/metrics"
+	"pinstack-post-service/internal/infrastructure/logger"
+)
+
+func serve(t *testing.T, checker *health.Checker, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	server := metrics.NewMetricsServer("localhost", 0, checker, logger.New("test"))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestServer_Healthz(t *testing.T) {
+	checker := health.NewChecker(time.Second)
+	checker.Add("postgres", func(ctx context.Context) error { return errors.New("down") })
+	checker.SetShuttingDown()
+
+	rec := serve(t, checker, "/healthz")
+
+	assert.Equal(t, http.StatusOK, rec.Code, "liveness does not depend on dependencies or shutdown")
+}
+
+func TestServer_Readyz(t *testing.T) {
+	healthy := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name         string
+		checks       map[string]health.Check
+		shuttingDown bool
+		wantCode     int
+		wantFailing  []string
+	}{
+		{
+			name:     "all dependencies healthy",
+			checks:   map[string]health.Check{"postgres": healthy, "redis": healthy, "user_service": healthy},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "failing dependencies are listed",
+			checks: map[string]health.Check{
+				"postgres":     healthy,
+				"redis":        func(ctx context.Context) error { return errors.New("not connected") },
+				"user_service": func(ctx context.Context) error { return errors.New("connection is TRANSIENT_FAILURE") },
+			},
+			wantCode:    http.StatusServiceUnavailable,
+			wantFailing: []string{"redis", "user_service"},
+		},
+		{
+			name:         "shutting down",
+			checks:       map[string]health.Check{"postgres": healthy},
+			shuttingDown: true,
+			wantCode:     http.StatusServiceUnavailable,
+			wantFailing:  []string{health.ShuttingDown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := health.NewChecker(time.Second)
+			for name, check := range tt.checks {
+				checker.Add(name, check)
+			}
+			if tt.shuttingDown {
+				checker.SetShuttingDown()
+			}
+
+			rec := serve(t, checker, "/readyz")
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			var report health.Report
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+			assert.Equal(t, tt.wantCode == http.StatusOK, report.Ready)
+			failing := make([]string, 0, len(report.Failing))
+			for name := range report.Failing {
+				failing = append(failing, name)
+			}
+			assert.ElementsMatch(t, tt.wantFailing, failing)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// InternalAdminMetadataKey is set to "true" by the API gateway for calls from the moderation tools.
+const InternalAdminMetadataKey = "x-internal-admin"
+
+// IsInternalAdmin reports whether the call carries the internal admin flag.
+func IsInternalAdmin(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(InternalAdminMetadataKey)
+	return len(values) > 0 && values[0] == "true"
+}
+
+// UnaryAdminInterceptor answers calls to the given full method names with PermissionDenied
+// unless they carry the internal admin flag. Other methods pass through. PostService has no
+// admin RPCs, so its admin methods check the flag in process; the server chain guards the
+// debug service with it.
+func UnaryAdminInterceptor(log ports.Logger, methods ...string) grpc.UnaryServerInterceptor {
+	admin := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		admin[method] = true
+	}
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if admin[info.FullMethod] && !IsInternalAdmin(ctx) {
+			log.Warn("Admin method called without admin flag", slog.String("method", info.FullMethod))
+			return nil, errinfo.Error(codes.PermissionDenied, errinfo.ReasonNotAdmin, custom_errors.ErrForbidden.Error())
+		}
+		return handler(ctx, req)
+	}
+}
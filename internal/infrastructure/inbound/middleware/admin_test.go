@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+	"pinstack-post-service/internal/infrastructure/logger"
+)
+
+func TestUnaryAdminInterceptor(t *testing.T) {
+	interceptor := UnaryAdminInterceptor(logger.New("test"), "/post.v1.PostService/ForceDeletePost")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	admin := metadata.NewIncomingContext(context.Background(), metadata.Pairs(InternalAdminMetadataKey, "true"))
+	notAdmin := metadata.NewIncomingContext(context.Background(), metadata.Pairs(InternalAdminMetadataKey, "false"))
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "admin method with flag", ctx: admin, method: "/post.v1.PostService/ForceDeletePost", wantCode: codes.OK},
+		{name: "admin method without metadata", ctx: context.Background(), method: "/post.v1.PostService/ForceDeletePost", wantCode: codes.PermissionDenied},
+		{name: "admin method with flag off", ctx: notAdmin, method: "/post.v1.PostService/ForceDeletePost", wantCode: codes.PermissionDenied},
+		{name: "other method without flag", ctx: context.Background(), method: "/post.v1.PostService/DeletePost", wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			require.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, "ok", resp)
+			} else {
+				assert.Nil(t, resp)
+				assert.Equal(t, errinfo.ReasonNotAdmin, errinfo.Reason(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryBudgetInterceptor gives every call a time budget split across its stages by ratios
+// (see model.StageContext). The budget is what is left of the caller's deadline, or
+// defaultBudget for a caller that set none, which then also becomes the call's deadline.
+func UnaryBudgetInterceptor(defaultBudget time.Duration, ratios model.BudgetRatios) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		total := defaultBudget
+		if deadline, ok := ctx.Deadline(); ok {
+			total = time.Until(deadline)
+		} else {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, defaultBudget)
+			defer cancel()
+		}
+		return handler(model.WithBudget(ctx, total, ratios), req)
+	}
+}
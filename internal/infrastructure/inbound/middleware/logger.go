@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/logger"
 	"time"
 
 	"google.golang.org/grpc"
@@ -11,6 +12,8 @@ import (
 	"log/slog"
 )
 
+// UnaryLoggerInterceptor logs every call once it completes. The caller's request ID, or a
+// generated one, is put on the context so loggers derived with WithContext add it too.
 func UnaryLoggerInterceptor(log ports.Logger) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
@@ -19,6 +22,7 @@ func UnaryLoggerInterceptor(log ports.Logger) grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler,
 	) (resp interface{}, err error) {
 		start := time.Now()
+		ctx = logger.ContextWith(ctx, slog.String("request_id", requestID(ctx)))
 
 		var remoteAddr string
 		if p, ok := peer.FromContext(ctx); ok {
@@ -30,7 +34,7 @@ func UnaryLoggerInterceptor(log ports.Logger) grpc.UnaryServerInterceptor {
 		latency := time.Since(start)
 		st, _ := status.FromError(err)
 
-		log.With(
+		log.WithContext(ctx).With(
 			slog.String("method", info.FullMethod),
 			slog.String("remote_address", remoteAddr),
 			slog.String("latency", latency.String()),
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"runtime/debug"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/inbound/grpc/errinfo"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+const requestIDHeader = "x-request-id"
+
+// UnaryRecoveryInterceptor turns a panic in any later interceptor or handler into codes.Internal.
+// It must be registered first so that the logger and metrics interceptors still see the failed
+// call. The panic value is logged with the stack, the method and the caller's request ID, or a
+// generated one when the caller sent none, and counted in panics_total.
+func UnaryRecoveryInterceptor(log ports.Logger, metrics ports.MetricsProvider) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				log.Error("panic recovered",
+					slog.String("method", info.FullMethod),
+					slog.String("request_id", requestID(ctx)),
+					slog.Any("panic", p),
+					slog.String("stack", string(debug.Stack())))
+				metrics.IncrementPanics(info.FullMethod)
+				resp, err = nil, errinfo.Error(codes.Internal, errinfo.ReasonInternal, "internal server error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+func requestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDHeader); len(ids) > 0 && ids[0] != "" {
+			return ids[0]
+		}
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Level is a logger level that can change while the service runs. Set changes it for good;
+// DebugFor drops it to debug for a while, to look into a production instance without a
+// restart, and then returns to the level last Set.
+type Level struct {
+	current slog.LevelVar
+
+	mu   sync.Mutex
+	base slog.Level
+	// debugUntil stops the running DebugFor window; generation tells a window that was
+	// replaced by a later DebugFor not to end it.
+	debugUntil *time.Timer
+	generation uint64
+}
+
+func newLevel(level slog.Level) *Level {
+	l := &Level{base: level}
+	l.current.Set(level)
+	return l
+}
+
+// Level implements slog.Leveler.
+func (l *Level) Level() slog.Level {
+	return l.current.Level()
+}
+
+// Set makes level the logger's level. During a DebugFor window it takes effect when the
+// window ends.
+func (l *Level) Set(level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.base = level
+	if l.debugUntil == nil {
+		l.current.Set(level)
+	}
+}
+
+// DebugFor logs at debug level for d. A call during a running window restarts it.
+func (l *Level) DebugFor(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.debugUntil != nil {
+		l.debugUntil.Stop()
+	}
+	l.generation++
+	generation := l.generation
+	l.current.Set(slog.LevelDebug)
+	l.debugUntil = time.AfterFunc(d, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.generation != generation {
+			return
+		}
+		l.debugUntil = nil
+		l.current.Set(l.base)
+	})
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevel_DebugFor(t *testing.T) {
+	level := newLevel(slog.LevelInfo)
+
+	level.DebugFor(20 * time.Millisecond)
+	assert.Equal(t, slog.LevelDebug, level.Level())
+	level.Set(slog.LevelWarn)
+	assert.Equal(t, slog.LevelDebug, level.Level(), "Set waits for the window to end")
+
+	assert.Eventually(t, func() bool { return level.Level() == slog.LevelWarn }, time.Second, time.Millisecond,
+		"the window ends on the level last Set")
+}
+
+func TestLevel_DebugForRestartsWindow(t *testing.T) {
+	level := newLevel(slog.LevelInfo)
+
+	level.DebugFor(20 * time.Millisecond)
+	level.DebugFor(time.Hour)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, slog.LevelDebug, level.Level(), "the first window must not end the second")
+}
+
+func TestLogger_SharesLevel(t *testing.T) {
+	log, recorder := NewTest()
+	scoped := log.With(slog.String("component", "tags"))
+
+	log.Level().Set(slog.LevelInfo)
+	scoped.Debug("hidden")
+	scoped.Info("shown")
+	log.Level().DebugFor(time.Hour)
+	scoped.Debug("shown while debugging")
+
+	assert.Equal(t, []string{"shown"}, recorder.Messages(slog.LevelInfo))
+	assert.Equal(t, []string{"shown while debugging"}, recorder.Messages(slog.LevelDebug))
+}
+
+func TestLogger_WithContext(t *testing.T) {
+	log, recorder := NewTest()
+	ctx := ContextWith(t.Context(), slog.String("request_id", "abc"))
+	ctx = ContextWith(ctx, slog.Int64("user_id", 7))
+
+	log.WithContext(ctx).Info("handled")
+	log.WithContext(t.Context()).Info("no fields")
+
+	entries := recorder.Entries()
+	assert.Equal(t, map[string]any{"request_id": "abc", "user_id": int64(7)}, entries[0].Attrs)
+	assert.Empty(t, entries[1].Attrs)
+}
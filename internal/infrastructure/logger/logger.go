@@ -1,43 +1,73 @@
+// Package logger is the slog-backed implementation of ports.Logger used across the service.
+// Every logger derived from one with With or WithContext shares its level, so the level can
+// be changed at runtime, and NewTest swaps the JSON output for a Recorder to assert on.
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"slices"
+
 	ports "pinstack-post-service/internal/domain/ports/output"
 )
 
-const (
-	envDev  = "dev"
-	envProd = "prod"
-)
+const envDev = "dev"
 
 type Logger struct {
 	*slog.Logger
+	level *Level
 }
 
-func (l *Logger) With(args ...any) ports.Logger {
-	return &Logger{Logger: l.Logger.With(args...)}
+// New logs JSON to stdout, at debug level in env dev and info elsewhere.
+func New(env string) *Logger {
+	level := slog.LevelInfo
+	if env == envDev {
+		level = slog.LevelDebug
+	}
+	return NewWithLevel(level)
 }
 
-func New(env string) *Logger {
-	var log *slog.Logger
-	switch env {
-	case envDev:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level:     slog.LevelDebug,
-			AddSource: true,
-		}))
-	case envProd:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level:     slog.LevelInfo,
-			AddSource: true,
-		}))
-	default:
-		log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level:     slog.LevelInfo,
+// NewWithLevel logs JSON to stdout at level.
+func NewWithLevel(level slog.Level) *Logger {
+	l := newLevel(level)
+	return &Logger{
+		Logger: slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+			Level:     l,
 			AddSource: true,
-		}))
+		})),
+		level: l,
+	}
+}
+
+// Level returns the level shared by l and every logger derived from it.
+func (l *Logger) Level() *Level {
+	return l.level
+}
+
+func (l *Logger) With(args ...any) ports.Logger {
+	return &Logger{Logger: l.Logger.With(args...), level: l.level}
+}
+
+// WithContext returns a logger adding the fields ctx carries from ContextWith, or l itself
+// when there are none.
+func (l *Logger) WithContext(ctx context.Context) ports.Logger {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return l
 	}
+	return l.With(fields...)
+}
+
+type contextKey struct{}
+
+// ContextWith returns a copy of ctx carrying args, which loggers derived with WithContext add
+// to every entry. Fields already on ctx are kept; args follow them.
+func ContextWith(ctx context.Context, args ...any) context.Context {
+	return context.WithValue(ctx, contextKey{}, append(slices.Clip(contextFields(ctx)), args...))
+}
 
-	return &Logger{log}
+func contextFields(ctx context.Context) []any {
+	fields, _ := ctx.Value(contextKey{}).([]any)
+	return fields
 }
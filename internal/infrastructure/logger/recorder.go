@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Entry is one log entry kept by a Recorder. Attrs holds the entry's attributes together with
+// those added by With, keyed by name; attributes in a group are keyed "group.name".
+type Entry struct {
+	Level   slog.Level
+	Message string
+	Attrs   map[string]any
+}
+
+// Recorder is a slog.Handler keeping every entry in memory, so tests can assert on what was
+// logged and at which level.
+type Recorder struct {
+	mu      *sync.Mutex
+	entries *[]Entry
+	level   slog.Leveler
+	attrs   []slog.Attr
+	group   string
+}
+
+// NewTest returns a logger at debug level that writes to the returned Recorder instead of
+// stdout. Entries below the logger's level are not recorded.
+func NewTest() (*Logger, *Recorder) {
+	level := newLevel(slog.LevelDebug)
+	recorder := &Recorder{mu: new(sync.Mutex), entries: new([]Entry), level: level}
+	return &Logger{Logger: slog.New(recorder), level: level}, recorder
+}
+
+// Entries returns a copy of the entries logged so far, oldest first.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), *r.entries...)
+}
+
+// Messages returns the messages logged at level, oldest first.
+func (r *Recorder) Messages(level slog.Level) []string {
+	var messages []string
+	for _, entry := range r.Entries() {
+		if entry.Level == level {
+			messages = append(messages, entry.Message)
+		}
+	}
+	return messages
+}
+
+// Levels counts the entries logged at each level.
+func (r *Recorder) Levels() map[slog.Level]int {
+	levels := map[slog.Level]int{}
+	for _, entry := range r.Entries() {
+		levels[entry.Level]++
+	}
+	return levels
+}
+
+func (r *Recorder) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= r.level.Level()
+}
+
+func (r *Recorder) Handle(_ context.Context, record slog.Record) error {
+	attrs := make(map[string]any, len(r.attrs)+record.NumAttrs())
+	for _, attr := range r.attrs {
+		addAttr(attrs, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(attrs, r.group, attr)
+		return true
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*r.entries = append(*r.entries, Entry{Level: record.Level, Message: record.Message, Attrs: attrs})
+	return nil
+}
+
+func (r *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *r
+	clone.attrs = make([]slog.Attr, 0, len(r.attrs)+len(attrs))
+	clone.attrs = append(clone.attrs, r.attrs...)
+	for _, attr := range attrs {
+		if r.group != "" {
+			attr.Key = r.group + "." + attr.Key
+		}
+		clone.attrs = append(clone.attrs, attr)
+	}
+	return &clone
+}
+
+func (r *Recorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+	clone := *r
+	if r.group != "" {
+		name = r.group + "." + name
+	}
+	clone.group = name
+	return &clone
+}
+
+func addAttr(attrs map[string]any, prefix string, attr slog.Attr) {
+	if prefix != "" {
+		attr.Key = prefix + "." + attr.Key
+	}
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		for _, nested := range value.Group() {
+			addAttr(attrs, attr.Key, nested)
+		}
+		return
+	}
+	attrs[attr.Key] = value.Any()
+}
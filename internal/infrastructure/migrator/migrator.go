@@ -1,11 +1,21 @@
 package migrator
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io/fs"
+
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
 	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/migrations"
 )
 
 type Migrator struct {
@@ -13,11 +23,23 @@ type Migrator struct {
 	log ports.Logger
 }
 
+// NewMigrator reads migrations from migrationsPath, or from the ones embedded in the binary
+// when migrationsPath is empty.
 func NewMigrator(migrationsPath, dsn string, log ports.Logger) (*Migrator, error) {
-	m, err := migrate.New(
-		"file://"+migrationsPath,
-		dsn,
+	var (
+		m   *migrate.Migrate
+		err error
 	)
+	if migrationsPath != "" {
+		m, err = migrate.New("file://"+migrationsPath, dsn)
+	} else {
+		var src source.Driver
+		src, err = iofs.New(migrations.FS, ".")
+		if err != nil {
+			return nil, err
+		}
+		m, err = migrate.NewWithSourceInstance("iofs", src, dsn)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +63,18 @@ func (m *Migrator) Down() error {
 	return nil
 }
 
+// Version returns the applied schema version; a database without migrations is at version 0.
+func (m *Migrator) Version() (Version, error) {
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return Version{}, nil
+	}
+	if err != nil {
+		return Version{}, err
+	}
+	return Version{Version: version, Dirty: dirty}, nil
+}
+
 func (m *Migrator) Close() error {
 	sourceErr, dbErr := m.m.Close()
 	if sourceErr != nil {
@@ -51,3 +85,63 @@ func (m *Migrator) Close() error {
 	}
 	return nil
 }
+
+// Version is the schema version of a database. Dirty means the migration to Version failed
+// halfway and the schema must be repaired by hand.
+type Version struct {
+	Version uint
+	Dirty   bool
+}
+
+// Latest returns the version of the newest embedded migration.
+func Latest() (uint, error) {
+	entries, err := fs.ReadDir(migrations.FS, ".")
+	if err != nil {
+		return 0, err
+	}
+	var latest uint
+	for _, entry := range entries {
+		migration, err := source.DefaultParse(entry.Name())
+		if err != nil {
+			continue
+		}
+		latest = max(latest, migration.Version)
+	}
+	return latest, nil
+}
+
+// RowQuerier is the part of a pgx pool ReadVersion needs.
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// ReadVersion reads the schema version from the schema_migrations table golang-migrate keeps,
+// without taking its lock; a database never migrated is at version 0.
+func ReadVersion(ctx context.Context, db RowQuerier) (Version, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := db.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	var pgerr *pgconn.PgError
+	if errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgerr) && pgerr.Code == "42P01" {
+		// 42P01 is undefined_table: golang-migrate creates the table on its first run.
+		return Version{}, nil
+	}
+	if err != nil {
+		return Version{}, fmt.Errorf("reading schema version: %w", err)
+	}
+	return Version{Version: uint(version), Dirty: dirty}, nil
+}
+
+// Check fails unless the schema is clean and at least at the latest embedded migration. A
+// newer schema passes: during a rollout the previous release runs against it.
+func (v Version) Check(latest uint) error {
+	switch {
+	case v.Dirty:
+		return fmt.Errorf("schema version %d is dirty", v.Version)
+	case v.Version < latest:
+		return fmt.Errorf("schema version %d is behind the binary's %d", v.Version, latest)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package migrator
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/migrations"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	entries, err := fs.ReadDir(migrations.FS, ".")
+	require.NoError(t, err)
+
+	directions := make(map[uint][]source.Direction)
+	for _, entry := range entries {
+		migration, err := source.DefaultParse(entry.Name())
+		require.NoError(t, err, "%s is not named like a migration", entry.Name())
+		directions[migration.Version] = append(directions[migration.Version], migration.Direction)
+	}
+
+	latest, err := Latest()
+	require.NoError(t, err)
+	require.Len(t, directions, int(latest), "versions run from 1 without gaps")
+	for version := uint(1); version <= latest; version++ {
+		assert.ElementsMatch(t, []source.Direction{source.Up, source.Down}, directions[version], "version %d", version)
+	}
+}
+
+func TestVersion_Check(t *testing.T) {
+	assert.NoError(t, Version{Version: 12}.Check(12))
+	assert.NoError(t, Version{Version: 13}.Check(12), "a newer schema serves the previous release")
+	assert.EqualError(t, Version{Version: 11}.Check(12), "schema version 11 is behind the binary's 12")
+	assert.EqualError(t, Version{}.Check(12), "schema version 0 is behind the binary's 12")
+	assert.EqualError(t, Version{Version: 12, Dirty: true}.Check(12), "schema version 12 is dirty")
+}
@@ -0,0 +1,154 @@
+// Package noop provides cache implementations that store nothing, a rate limiter that
+// allows everything and an event publisher that drops every event. They stand in for Redis while it is unreachable, so every read misses
+// and the service serves from Postgres.
+package noop
+
+import (
+	"context"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/domain/ports/output/cache"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+type PostCache struct{}
+
+func NewPostCache() *PostCache {
+	return &PostCache{}
+}
+
+func (PostCache) GetPost(ctx context.Context, postID int64) (*model.PostDetailed, error) {
+	return nil, custom_errors.ErrCacheMiss
+}
+
+func (PostCache) GetPosts(ctx context.Context, postIDs []int64) (map[int64]*model.PostDetailed, error) {
+	return map[int64]*model.PostDetailed{}, nil
+}
+
+func (PostCache) SetPost(ctx context.Context, post *model.PostDetailed) error {
+	return nil
+}
+
+func (PostCache) DeletePost(ctx context.Context, postID int64) error {
+	return nil
+}
+
+func (PostCache) DeletePosts(ctx context.Context, postIDs []int64) error {
+	return nil
+}
+
+func (PostCache) GetTagSuggestions(ctx context.Context, prefix string, limit int) ([]*model.Tag, error) {
+	return nil, custom_errors.ErrCacheMiss
+}
+
+func (PostCache) SetTagSuggestions(ctx context.Context, prefix string, limit int, tags []*model.Tag) error {
+	return nil
+}
+
+func (PostCache) GetTagLatestPosts(ctx context.Context, tagNames []string) (map[string]time.Time, error) {
+	return map[string]time.Time{}, nil
+}
+
+func (PostCache) AdvanceTagLatestPosts(ctx context.Context, latest map[string]time.Time) error {
+	return nil
+}
+
+type UserCache struct{}
+
+func NewUserCache() *UserCache {
+	return &UserCache{}
+}
+
+func (UserCache) GetUser(ctx context.Context, userID int64) (*model.User, error) {
+	return nil, custom_errors.ErrCacheMiss
+}
+
+func (UserCache) GetFreshUser(ctx context.Context, userID int64) (*model.User, error) {
+	return nil, custom_errors.ErrCacheMiss
+}
+
+func (UserCache) SetUser(ctx context.Context, user *model.User) error {
+	return nil
+}
+
+func (UserCache) DeleteUser(ctx context.Context, userID int64) error {
+	return nil
+}
+
+func (UserCache) InvalidateUserPostsMeta(ctx context.Context, userID int64) error {
+	return nil
+}
+
+func (UserCache) GetUserPostCount(ctx context.Context, userID int64) (int64, error) {
+	return 0, custom_errors.ErrCacheMiss
+}
+
+func (UserCache) SetUserPostCount(ctx context.Context, userID int64, count int64) error {
+	return nil
+}
+
+func (UserCache) GetBlockedUsers(ctx context.Context, userID int64) ([]int64, error) {
+	return nil, custom_errors.ErrCacheMiss
+}
+
+func (UserCache) SetBlockedUsers(ctx context.Context, userID int64, blockedIDs []int64) error {
+	return nil
+}
+
+func (UserCache) GetRecentPosts(ctx context.Context, userID int64) ([]int64, error) {
+	return nil, custom_errors.ErrCacheMiss
+}
+
+type Batcher struct{}
+
+func NewBatcher() *Batcher {
+	return &Batcher{}
+}
+
+func (Batcher) NewBatch() cache.CacheBatch {
+	return &Batch{}
+}
+
+// Batch counts queued commands so callers that skip empty batches behave as with Redis.
+type Batch struct {
+	queued int
+}
+
+func (b *Batch) SetPost(post *model.PostDetailed)                  { b.queued++ }
+func (b *Batch) SetUser(user *model.User)                          { b.queued++ }
+func (b *Batch) DeletePost(postID int64)                           { b.queued++ }
+func (b *Batch) DeleteUser(userID int64)                           { b.queued++ }
+func (b *Batch) InvalidateUserPostsMeta(userID int64)              { b.queued++ }
+func (b *Batch) AdjustUserPostCount(userID int64, delta int64)     { b.queued++ }
+func (b *Batch) AdvanceTagLatestPost(tagName string, at time.Time) { b.queued++ }
+func (b *Batch) AddRecentPost(userID int64, postID int64)          { b.queued++ }
+func (b *Batch) Len() int                                          { return b.queued }
+
+func (b *Batch) Exec(ctx context.Context) error {
+	b.queued = 0
+	return nil
+}
+
+// RateLimiter allows every request.
+type RateLimiter struct{}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{}
+}
+
+func (RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	return true, 0, nil
+}
+
+// EventPublisher drops every event. It is used when events are disabled or Redis is down.
+type EventPublisher struct{}
+
+func NewEventPublisher() *EventPublisher {
+	return &EventPublisher{}
+}
+
+func (EventPublisher) Publish(ctx context.Context, event model.PostEvent) error {
+	return nil
+}
@@ -0,0 +1,59 @@
+package noop_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/domain/ports/output/cache"
+	"pinstack-post-service/internal/domain/ports/output/events"
+	"pinstack-post-service/internal/domain/ports/output/rate_limiter"
+	"pinstack-post-service/internal/infrastructure/outbound/cache/noop"
+)
+
+var (
+	_ cache.PostCache    = (*noop.PostCache)(nil)
+	_ cache.UserCache    = (*noop.UserCache)(nil)
+	_ cache.CacheBatcher = (*noop.Batcher)(nil)
+
+	_ rate_limiter.RateLimiter = (*noop.RateLimiter)(nil)
+	_ events.Publisher         = (*noop.EventPublisher)(nil)
+)
+
+func TestNoopCaches_AlwaysMiss(t *testing.T) {
+	ctx := context.Background()
+	postCache := noop.NewPostCache()
+	userCache := noop.NewUserCache()
+
+	assert.NoError(t, postCache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 1}}))
+	_, err := postCache.GetPost(ctx, 1)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+	posts, err := postCache.GetPosts(ctx, []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Empty(t, posts)
+	assert.NoError(t, postCache.DeletePost(ctx, 1))
+
+	assert.NoError(t, userCache.SetUser(ctx, &model.User{ID: 1}))
+	_, err = userCache.GetUser(ctx, 1)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+	assert.NoError(t, userCache.DeleteUser(ctx, 1))
+}
+
+func TestNoopBatch(t *testing.T) {
+	batch := noop.NewBatcher().NewBatch()
+	batch.SetPost(&model.PostDetailed{Post: &model.Post{ID: 1}})
+	batch.SetUser(&model.User{ID: 1})
+	assert.Equal(t, 2, batch.Len())
+	assert.NoError(t, batch.Exec(context.Background()))
+}
+
+func TestNoopRateLimiter_AllowsEverything(t *testing.T) {
+	allowed, retryAfter, err := noop.NewRateLimiter().Allow(context.Background(), "create:1", 1, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Zero(t, retryAfter)
+}
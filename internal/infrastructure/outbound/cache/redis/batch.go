@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/domain/ports/output/cache"
+	"pinstack-post-service/internal/infrastructure/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Batcher struct {
+	client    *Client
+	keyPrefix string
+	postTTL   time.Duration
+	userTTL   time.Duration
+	tagTTL    time.Duration
+	recentTTL time.Duration
+	log       ports.Logger
+	metrics   ports.MetricsProvider
+}
+
+func NewBatcher(client *Client, cfg config.Cache, log ports.Logger, metrics ports.MetricsProvider) *Batcher {
+	return &Batcher{
+		client:    client,
+		keyPrefix: cfg.KeyPrefix,
+		postTTL:   cfg.PostTTL,
+		userTTL:   cfg.UserTTL,
+		tagTTL:    cfg.TagLatestTTL,
+		recentTTL: cfg.RecentPostsTTL,
+		log:       log,
+		metrics:   metrics,
+	}
+}
+
+func (b *Batcher) NewBatch() cache.CacheBatch {
+	return &Batch{
+		batcher: b,
+		pipe:    b.client.client.Pipeline(),
+	}
+}
+
+// Batch is a pipeline of cache writes. Values that fail to marshal are skipped
+// and reported by Exec together with any Redis error.
+type Batch struct {
+	batcher *Batcher
+	pipe    redis.Pipeliner
+	errs    []error
+}
+
+func (b *Batch) SetPost(post *model.PostDetailed) {
+	if post == nil || post.Post == nil {
+		b.errs = append(b.errs, fmt.Errorf("post cannot be nil"))
+		return
+	}
+	b.set(postKey(b.batcher.keyPrefix, post.Post.ID), postPayloadVersion, post, b.batcher.postTTL)
+}
+
+func (b *Batch) SetUser(user *model.User) {
+	if user == nil {
+		b.errs = append(b.errs, fmt.Errorf("user cannot be nil"))
+		return
+	}
+	b.set(userKey(b.batcher.keyPrefix, user.ID), userPayloadVersion, user, b.batcher.userTTL)
+}
+
+func (b *Batch) DeletePost(postID int64) {
+	b.pipe.Del(context.Background(), postKey(b.batcher.keyPrefix, postID))
+}
+
+func (b *Batch) DeleteUser(userID int64) {
+	b.pipe.Del(context.Background(), userKey(b.batcher.keyPrefix, userID))
+}
+
+func (b *Batch) InvalidateUserPostsMeta(userID int64) {
+	b.pipe.Del(context.Background(), userPostsMetaKey(b.batcher.keyPrefix, userID))
+}
+
+// adjustCountScript adds ARGV[1] to the counter at KEYS[1] only when it exists, keeping its
+// TTL. A counter that is not an integer or would go negative is deleted instead, so the next
+// read recounts it.
+var adjustCountScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+local ok, count = pcall(redis.call, 'INCRBY', KEYS[1], ARGV[1])
+if not ok or count < 0 then
+	redis.call('DEL', KEYS[1])
+end
+return 1
+`)
+
+func (b *Batch) AdjustUserPostCount(userID int64, delta int64) {
+	adjustCountScript.Eval(context.Background(), b.pipe, []string{userPostsMetaKey(b.batcher.keyPrefix, userID)}, delta)
+}
+
+func (b *Batch) AdvanceTagLatestPost(tagName string, at time.Time) {
+	advanceTimestampScript.Eval(context.Background(), b.pipe, []string{tagLatestPostKey(b.batcher.keyPrefix, tagName)},
+		at.UnixMicro(), b.batcher.tagTTL.Milliseconds())
+}
+
+func (b *Batch) AddRecentPost(userID int64, postID int64) {
+	addRecentPostScript.Eval(context.Background(), b.pipe, []string{userRecentPostsKey(b.batcher.keyPrefix, userID)},
+		postID, b.batcher.recentTTL.Milliseconds(), maxRecentPosts)
+}
+
+func (b *Batch) Len() int {
+	return b.pipe.Len()
+}
+
+func (b *Batch) Exec(ctx context.Context) error {
+	start := time.Now()
+	queued := b.pipe.Len()
+	defer func() {
+		b.batcher.metrics.RecordCacheOperationDuration("batch_exec", time.Since(start))
+	}()
+
+	if queued > 0 {
+		execCtx, cancel := b.batcher.client.withTimeout(ctx, queued)
+		defer cancel()
+		if _, err := b.pipe.Exec(execCtx); err != nil {
+			b.errs = append(b.errs, b.batcher.client.timeoutError(execCtx, "batch_exec", err))
+		}
+	}
+
+	if len(b.errs) > 0 {
+		b.batcher.log.Error("Failed to execute cache batch",
+			slog.Int("commands", queued),
+			slog.String("error", fmt.Sprint(b.errs)))
+		return fmt.Errorf("failed to execute cache batch: %w", b.errs[0])
+	}
+
+	b.batcher.log.Debug("Cache batch executed", slog.Int("commands", queued))
+	return nil
+}
+
+func (b *Batch) set(key string, version int, value interface{}, ttl time.Duration) {
+	data, err := encodeEnvelope(version, value, time.Now())
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("failed to marshal value for %s: %w", key, err))
+		return
+	}
+	b.pipe.Set(context.Background(), key, data, ttl)
+}
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+)
+
+func TestBatch_SingleRoundTrip(t *testing.T) {
+	client, store := newTestClient(t)
+	cfg := testCacheConfig()
+	batcher := NewBatcher(client, cfg, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	store.values["staging:user:1"] = `{"v":1,"payload":{"id":1,"username":"stale"}}`
+
+	batch := batcher.NewBatch()
+	batch.DeleteUser(1)
+	batch.SetPost(&model.PostDetailed{Post: &model.Post{ID: 10, AuthorID: 1}})
+	batch.SetUser(&model.User{ID: 1, Username: "fresh"})
+	assert.Equal(t, 3, batch.Len())
+
+	require.NoError(t, batch.Exec(ctx))
+
+	assert.Equal(t, 1, store.roundTrips)
+	assert.Equal(t, cfg.PostTTL, store.ttls["staging:post:10"])
+	assert.Equal(t, cfg.UserTTL, store.ttls["staging:user:1"])
+	assert.Contains(t, store.values["staging:user:1"], "fresh")
+}
+
+func TestBatch_KeysMatchSingleCaches(t *testing.T) {
+	client, store := newTestClient(t)
+	cfg := testCacheConfig()
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	batcher := NewBatcher(client, cfg, logger.New("test"), metrics)
+	postCache := NewPostCache(client, cfg, logger.New("test"), metrics)
+	ctx := context.Background()
+
+	batch := batcher.NewBatch()
+	batch.SetPost(&model.PostDetailed{Post: &model.Post{ID: 5, Title: "batched"}})
+	require.NoError(t, batch.Exec(ctx))
+
+	got, err := postCache.GetPost(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "batched", got.Post.Title)
+
+	batch = batcher.NewBatch()
+	batch.DeletePost(5)
+	require.NoError(t, batch.Exec(ctx))
+	assert.Empty(t, store.values)
+}
+
+func TestBatch_InvalidValueReportedOnExec(t *testing.T) {
+	client, store := newTestClient(t)
+	batcher := NewBatcher(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	batch := batcher.NewBatch()
+	batch.SetPost(&model.PostDetailed{})
+	batch.SetUser(&model.User{ID: 3})
+
+	assert.Error(t, batch.Exec(context.Background()))
+	assert.Contains(t, store.values, "staging:user:3", "valid writes in the batch are still applied")
+}
+
+func TestBatch_EmptyExecSkipsRedis(t *testing.T) {
+	client, store := newTestClient(t)
+	batcher := NewBatcher(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	require.NoError(t, batcher.NewBatch().Exec(context.Background()))
+	assert.Zero(t, store.roundTrips)
+}
+
+// The benchmarks report round trips per cache refill after a post cache miss:
+// two with the individual caches, one with a batch.
+func BenchmarkCacheRefill_Sequential(b *testing.B) {
+	client, store := newTestClient(b)
+	cfg := testCacheConfig()
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	postCache := NewPostCache(client, cfg, logger.New("error"), metrics)
+	userCache := NewUserCache(client, cfg, logger.New("error"), metrics)
+	ctx := context.Background()
+	post := &model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: 1}, Author: &model.User{ID: 1}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = postCache.SetPost(ctx, post)
+		_ = userCache.SetUser(ctx, post.Author)
+	}
+	b.ReportMetric(float64(store.roundTrips)/float64(b.N), "roundtrips/op")
+}
+
+func BenchmarkCacheRefill_Batched(b *testing.B) {
+	client, store := newTestClient(b)
+	batcher := NewBatcher(client, testCacheConfig(), logger.New("error"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+	post := &model.PostDetailed{Post: &model.Post{ID: 1, AuthorID: 1}, Author: &model.User{ID: 1}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch := batcher.NewBatch()
+		batch.SetPost(post)
+		batch.SetUser(post.Author)
+		_ = batch.Exec(ctx)
+	}
+	b.ReportMetric(float64(store.roundTrips)/float64(b.N), "roundtrips/op")
+}
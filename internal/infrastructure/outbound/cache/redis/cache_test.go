@@ -0,0 +1,1022 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	post_service "pinstack-post-service/internal/application/service/post"
+	model "pinstack-post-service/internal/domain/models"
+	post_ports "pinstack-post-service/internal/domain/ports/input/post"
+	"pinstack-post-service/internal/infrastructure/config"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/testsupport"
+	post_service_mock "pinstack-post-service/mocks/post"
+)
+
+// fakeStore answers GET/MGET/SET/DEL/PTTL/SCAN/PUBLISH in memory from a go-redis hook, so no Redis server is needed.
+// EVAL runs adjustCountScript, advanceTimestampScript or addRecentPostScript, and EVALSHA runs
+// slidingWindowScript. roundTrips counts what would have been network round
+// trips: one per command or per pipeline. writes lists every SET, DEL and EVAL as "SET key",
+// "DEL key" or "EVAL key", in order. published lists every PUBLISH as "channel message".
+// pipelines lists how many commands each pipeline held; a pipeline fails without applying any
+// of them while failPipelines is set.
+type fakeStore struct {
+	values        map[string]string
+	ttls          map[string]time.Duration
+	roundTrips    int
+	writes        []string
+	published     []string
+	pipelines     []int
+	failPipelines bool
+}
+
+func (f *fakeStore) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (f *fakeStore) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		f.roundTrips++
+		return f.apply(cmd)
+	}
+}
+
+func (f *fakeStore) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		f.roundTrips++
+		f.pipelines = append(f.pipelines, len(cmds))
+		if f.failPipelines {
+			err := errors.New("connection reset")
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		var firstErr error
+		for _, cmd := range cmds {
+			if err := f.apply(cmd); err != nil && firstErr == nil && err != redis.Nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+}
+
+func (f *fakeStore) apply(cmd redis.Cmder) error {
+	args := cmd.Args()
+	if args[0] == "publish" {
+		f.published = append(f.published, fmt.Sprintf("%s %s", args[1], args[2]))
+		cmd.(*redis.IntCmd).SetVal(0)
+		return nil
+	}
+	switch c := cmd.(type) {
+	case *redis.StatusCmd:
+		key := args[1].(string)
+		f.writes = append(f.writes, "SET "+key)
+		f.values[key] = fmt.Sprint(args[2])
+		if data, ok := args[2].([]byte); ok {
+			f.values[key] = string(data)
+		}
+		f.ttls[key] = 0
+		if len(args) == 5 {
+			f.ttls[key] = time.Duration(args[4].(int64)) * time.Second
+		}
+		c.SetVal("OK")
+	case *redis.StringCmd:
+		val, ok := f.values[args[1].(string)]
+		if !ok {
+			c.SetErr(redis.Nil)
+			return redis.Nil
+		}
+		c.SetVal(val)
+	case *redis.DurationCmd:
+		ttl, ok := f.ttls[args[1].(string)]
+		if !ok {
+			c.SetVal(-2)
+			return nil
+		}
+		c.SetVal(ttl)
+	case *redis.SliceCmd:
+		vals := make([]interface{}, len(args)-1)
+		for i, key := range args[1:] {
+			if val, ok := f.values[key.(string)]; ok {
+				vals[i] = val
+			}
+		}
+		c.SetVal(vals)
+	case *redis.IntCmd:
+		var deleted int64
+		for _, a := range args[1:] {
+			f.writes = append(f.writes, "DEL "+a.(string))
+			if _, ok := f.values[a.(string)]; ok {
+				delete(f.values, a.(string))
+				deleted++
+			}
+		}
+		c.SetVal(deleted)
+	case *redis.Cmd:
+		key := args[3].(string)
+		f.writes = append(f.writes, "EVAL "+key)
+		if args[0] == "evalsha" && args[1] == slidingWindowScript.Hash() {
+			c.SetVal(f.slidingWindow(key, args[4].(int64), args[5].(int), args[6].(string)))
+			return nil
+		}
+		if redis.NewScript(args[1].(string)).Hash() == advanceTimestampScript.Hash() {
+			c.SetVal(f.advance(key, args[4].(int64), args[5].(int64)))
+			return nil
+		}
+		if redis.NewScript(args[1].(string)).Hash() == addRecentPostScript.Hash() {
+			c.SetVal(f.addRecent(key, strconv.FormatInt(args[4].(int64), 10), args[5].(int64), args[6].(int)))
+			return nil
+		}
+		val, ok := f.values[key]
+		if !ok {
+			c.SetVal(int64(0))
+			return nil
+		}
+		count, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || count+args[4].(int64) < 0 {
+			delete(f.values, key)
+		} else {
+			f.values[key] = strconv.FormatInt(count+args[4].(int64), 10)
+		}
+		c.SetVal(int64(1))
+	case *redis.ScanCmd:
+		c.SetVal(f.scan(args))
+	}
+	return nil
+}
+
+// advance is advanceTimestampScript.
+func (f *fakeStore) advance(key string, at, ttlMillis int64) int64 {
+	if current, err := strconv.ParseInt(f.values[key], 10, 64); err == nil && current >= at {
+		return 0
+	}
+	f.values[key] = strconv.FormatInt(at, 10)
+	f.ttls[key] = time.Duration(ttlMillis) * time.Millisecond
+	return 1
+}
+
+// slidingWindow is slidingWindowScript with a window that never slides: every member added
+// stays in it.
+func (f *fakeStore) slidingWindow(key string, windowMillis int64, limit int, member string) []interface{} {
+	var members []string
+	if current, ok := f.values[key]; ok {
+		members = strings.Split(current, ",")
+	}
+	if len(members) >= limit {
+		return []interface{}{int64(0), windowMillis}
+	}
+	f.values[key] = strings.Join(append(members, member), ",")
+	f.ttls[key] = time.Duration(windowMillis) * time.Millisecond
+	return []interface{}{int64(1), int64(0)}
+}
+
+// addRecent is addRecentPostScript.
+func (f *fakeStore) addRecent(key, id string, ttlMillis int64, limit int) int64 {
+	ids := []string{id}
+	if current, ok := f.values[key]; ok {
+		for _, other := range strings.Split(current, ",") {
+			if len(ids) >= limit {
+				break
+			}
+			if other != id {
+				ids = append(ids, other)
+			}
+		}
+	}
+	f.values[key] = strings.Join(ids, ",")
+	f.ttls[key] = time.Duration(ttlMillis) * time.Millisecond
+	return int64(len(ids))
+}
+
+// scan pages through the sorted keys matching the pattern, count keys at a time. The cursor
+// is the offset of the next page.
+func (f *fakeStore) scan(args []interface{}) ([]string, uint64) {
+	cursor := int(args[1].(uint64))
+	pattern, count := "*", 10
+	for i := 2; i+1 < len(args); i += 2 {
+		switch args[i] {
+		case "match":
+			pattern = args[i+1].(string)
+		case "count":
+			count = int(args[i+1].(int64))
+		}
+	}
+	var keys []string
+	for key := range f.values {
+		if ok, _ := path.Match(pattern, key); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	end := min(cursor+count, len(keys))
+	if end == len(keys) {
+		return keys[cursor:], 0
+	}
+	return keys[cursor:end], uint64(end)
+}
+
+func newTestClient(t testing.TB) (*Client, *fakeStore) {
+	t.Helper()
+	store := &fakeStore{values: map[string]string{}, ttls: map[string]time.Duration{}}
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	rdb.AddHook(store)
+	t.Cleanup(func() { _ = rdb.Close() })
+	return &Client{client: rdb, log: logger.New("test"), metrics: prometheus.NewPrometheusMetricsProvider()}, store
+}
+
+func testCacheConfig() config.Cache {
+	return config.Cache{
+		KeyPrefix:        "staging:",
+		PostTTL:          10 * time.Minute,
+		UserTTL:          2 * time.Minute,
+		ListTTL:          time.Minute,
+		PostCountTTL:     30 * time.Second,
+		TagSuggestionTTL: time.Minute,
+		TagLatestTTL:     5 * time.Minute,
+		RecentPostsTTL:   30 * time.Second,
+		BlockedUsersTTL:  30 * time.Second,
+		AuthorMaxAge:     30 * time.Second,
+	}
+}
+
+func TestPostCache_KeysAndTTL(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	post := &model.PostDetailed{Post: &model.Post{ID: 42, AuthorID: 1, Title: "Test Post"}, FailedTags: []string{"go"}}
+	require.NoError(t, cache.SetPost(ctx, post))
+
+	assert.Equal(t, 10*time.Minute, store.ttls["staging:post:42"])
+	_, unprefixed := store.values["post:42"]
+	assert.False(t, unprefixed)
+
+	var stored model.PostDetailed
+	cachedAt, err := decodeEnvelope([]byte(store.values["staging:post:42"]), postPayloadVersion, &stored)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Post", stored.Post.Title)
+	assert.WithinDuration(t, time.Now(), cachedAt, time.Minute, "the envelope records when it was written")
+
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), got.Post.ID)
+	assert.Nil(t, got.FailedTags, "failed tags belong to one CreatePost call and are not cached")
+
+	require.NoError(t, cache.DeletePost(ctx, 42))
+	_, err = cache.GetPost(ctx, 42)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+}
+
+func TestPostCache_GetPosts(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	for _, id := range []int64{1, 3} {
+		require.NoError(t, cache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: id, Title: "Cached"}}))
+	}
+	store.roundTrips = 0
+
+	got, err := cache.GetPosts(ctx, []int64{1, 2, 3})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.roundTrips, "one MGET for the whole batch")
+	require.Len(t, got, 2, "missing entries have no key")
+	assert.Equal(t, int64(1), got[1].Post.ID)
+	assert.Equal(t, int64(3), got[3].Post.ID)
+
+	empty, err := cache.GetPosts(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestPostCache_DeletePosts(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	ids := make([]int64, 1200)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+		store.values[postKey("staging:", ids[i])] = "cached"
+	}
+	store.values["staging:post:5000"] = "kept"
+
+	require.NoError(t, cache.DeletePosts(ctx, ids))
+
+	assert.Equal(t, 1, store.roundTrips, "one pipeline for every chunk")
+	assert.Equal(t, []int{3}, store.pipelines, "1200 keys go out as three DELs of at most 500")
+	assert.Len(t, store.writes, 1200)
+	assert.Equal(t, map[string]string{"staging:post:5000": "kept"}, store.values)
+
+	store.roundTrips = 0
+	require.NoError(t, cache.DeletePosts(ctx, nil))
+	assert.Zero(t, store.roundTrips)
+}
+
+func TestPostCache_DeletePostsFallsBackToSingleDeletes(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	store.values["staging:post:1"] = "cached"
+	store.values["staging:post:2"] = "cached"
+	store.failPipelines = true
+
+	require.NoError(t, cache.DeletePosts(context.Background(), []int64{1, 2, 3}))
+
+	assert.Equal(t, 4, store.roundTrips, "the failed pipeline, then one DEL per post")
+	assert.Equal(t, []string{"DEL staging:post:1", "DEL staging:post:2", "DEL staging:post:3"}, store.writes)
+	assert.Empty(t, store.values)
+}
+
+func TestPostCache_DeletePostsReportsPostsLeftCached(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	store.failPipelines = true
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := cache.DeletePosts(ctx, []int64{1, 2})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete 2 of 2 posts")
+}
+
+func TestPostCache_EntryMetadata(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+	cachedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	store.values["staging:post:42"] = `{"v":8,"cached_at":"2026-01-02T03:04:05Z","payload":{"post":{"id":42,"author_id":1,"title":"Cached"}}}`
+	store.ttls["staging:post:42"] = 4 * time.Minute
+	// Written before envelopes recorded their write time; still a hit.
+	store.values["staging:post:43"] = `{"v":8,"payload":{"post":{"id":43,"author_id":1,"title":"Old"}}}`
+
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	assert.True(t, got.CachedAt.Equal(cachedAt), "the write time comes from the envelope")
+	assert.Equal(t, 4*time.Minute, got.CacheTTL)
+
+	old, err := cache.GetPost(ctx, 43)
+	require.NoError(t, err)
+	assert.True(t, old.CachedAt.IsZero())
+
+	batch, err := cache.GetPosts(ctx, []int64{42, 43})
+	require.NoError(t, err)
+	assert.True(t, batch[42].CachedAt.Equal(cachedAt))
+	assert.True(t, batch[43].CachedAt.IsZero())
+}
+
+func TestPostCache_TagSuggestions(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	_, err := cache.GetTagSuggestions(ctx, "go", 10)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	usage := int64(3)
+	require.NoError(t, cache.SetTagSuggestions(ctx, "go", 10, []*model.Tag{{ID: 1, Name: "golang", PostCount: &usage}}))
+	require.NoError(t, cache.SetTagSuggestions(ctx, "rust", 10, nil))
+	assert.Equal(t, time.Minute, store.ttls["staging:tag_suggestions:10:go"])
+
+	got, err := cache.GetTagSuggestions(ctx, "go", 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "golang", got[0].Name)
+	assert.Equal(t, int64(3), *got[0].PostCount)
+
+	_, err = cache.GetTagSuggestions(ctx, "go", 5)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss, "each limit has its own entry")
+
+	empty, err := cache.GetTagSuggestions(ctx, "rust", 10)
+	require.NoError(t, err, "an empty result is cached too")
+	assert.Empty(t, empty)
+}
+
+func TestPostCache_TagLatestPosts(t *testing.T) {
+	client, store := newTestClient(t)
+	cfg := testCacheConfig()
+	cache := NewPostCache(client, cfg, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	batcher := NewBatcher(client, cfg, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+	first := testsupport.FixedTime
+	second := first.Add(time.Second)
+
+	got, err := cache.GetTagLatestPosts(ctx, []string{"go", "rust"})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	require.NoError(t, cache.AdvanceTagLatestPosts(ctx, map[string]time.Time{"go": second, "rust": first}))
+	assert.Equal(t, strconv.FormatInt(second.UnixMicro(), 10), store.values["staging:tag_latest_post:go"])
+	assert.Equal(t, 5*time.Minute, store.ttls["staging:tag_latest_post:go"])
+
+	batch := batcher.NewBatch()
+	batch.AdvanceTagLatestPost("go", first)
+	batch.AdvanceTagLatestPost("rust", second)
+	batch.AdvanceTagLatestPost("zig", second)
+	require.NoError(t, batch.Exec(ctx))
+
+	got, err = cache.GetTagLatestPosts(ctx, []string{"go", "rust", "zig", "missing"})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.True(t, got["go"].Equal(second), "an older time does not move a tag back")
+	assert.True(t, got["rust"].Equal(second))
+	assert.True(t, got["zig"].Equal(second), "a new post starts an entry")
+
+	store.values["staging:tag_latest_post:go"] = "not a time"
+	got, err = cache.GetTagLatestPosts(ctx, []string{"go"})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	assert.NotContains(t, store.values, "staging:tag_latest_post:go", "the corrupt entry is deleted")
+}
+
+func TestUserCache_KeysAndTTL(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewUserCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	require.NoError(t, cache.SetUser(ctx, &model.User{ID: 7, Username: "alice"}))
+
+	assert.Equal(t, 2*time.Minute, store.ttls["staging:user:7"])
+
+	got, err := cache.GetUser(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", got.Username)
+
+	require.NoError(t, cache.DeleteUser(ctx, 7))
+	assert.Empty(t, store.values)
+}
+
+func TestUserCache_GetFreshUser(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewUserCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	_, err := cache.GetFreshUser(ctx, 7)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss, "not cached")
+
+	require.NoError(t, cache.SetUser(ctx, &model.User{ID: 7, Username: "alice"}))
+	got, err := cache.GetFreshUser(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", got.Username)
+
+	// 2m user TTL with 1m35s left: the entry is 25s old, within the 30s bound.
+	store.ttls["staging:user:7"] = 95 * time.Second
+	_, err = cache.GetFreshUser(ctx, 7)
+	assert.NoError(t, err)
+
+	// 31s old.
+	store.ttls["staging:user:7"] = 89 * time.Second
+	_, err = cache.GetFreshUser(ctx, 7)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss, "too old to vouch for an author")
+	stale, err := cache.GetUser(ctx, 7)
+	require.NoError(t, err, "a stale entry still serves plain reads")
+	assert.Equal(t, "alice", stale.Username)
+
+	store.ttls["staging:user:7"] = -1
+	_, err = cache.GetFreshUser(ctx, 7)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss, "an entry without expiry has no known age")
+}
+
+func TestCaches_SharedRedisDoesNotCollide(t *testing.T) {
+	client, store := newTestClient(t)
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	ctx := context.Background()
+
+	blueCfg := testCacheConfig()
+	blueCfg.KeyPrefix = "blue:"
+	greenCfg := testCacheConfig()
+	greenCfg.KeyPrefix = "green:"
+
+	blue := NewPostCache(client, blueCfg, logger.New("test"), metrics)
+	green := NewPostCache(client, greenCfg, logger.New("test"), metrics)
+
+	require.NoError(t, blue.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 1, Title: "blue"}}))
+
+	_, err := green.GetPost(ctx, 1)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+	assert.Contains(t, store.values, "blue:post:1")
+}
+
+func TestPostCache_DecodesPayloadWithoutMediaMetadata(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	// Written without media width, height, size and alt text. Those fields are optional, so
+	// adding them needed no payload version bump.
+	store.values["staging:post:42"] = `{"v":8,"payload":{"post":{"id":42,"author_id":1,"title":"Old"},` +
+		`"media":[{"id":1,"post_id":42,"url":"https://example.com/a.jpg","type":"image","position":1,"created_at":null}]}}`
+
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	require.Len(t, got.Media, 1)
+	assert.Equal(t, "https://example.com/a.jpg", got.Media[0].URL)
+	assert.Nil(t, got.Media[0].Width)
+	assert.Nil(t, got.Media[0].Height)
+	assert.Nil(t, got.Media[0].SizeBytes)
+	assert.Nil(t, got.Media[0].AltText)
+
+	width, alt := int32(640), "A bridge"
+	got.Media[0].Width, got.Media[0].AltText = &width, &alt
+	require.NoError(t, cache.SetPost(ctx, got))
+
+	again, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, int32(640), *again.Media[0].Width)
+	assert.Equal(t, "A bridge", *again.Media[0].AltText)
+	assert.Nil(t, again.Media[0].Height)
+}
+
+func TestPostCache_EntryWithoutVisibilityIsAMiss(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	// A release without visibility caches private posts too; read as public they would leak.
+	store.values["staging:post:42"] = `{"v":1,"payload":{"post":{"id":42,"author_id":1,"title":"Old"}}}`
+
+	_, err := cache.GetPost(ctx, 42)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	private := &model.PostDetailed{Post: &model.Post{ID: 42, AuthorID: 1, Title: "Mine", Visibility: model.PostVisibilityPrivate}}
+	require.NoError(t, cache.SetPost(ctx, private))
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, model.PostVisibilityPrivate, got.Post.Visibility)
+}
+
+func TestPostCache_EntryWithoutVersionIsAMiss(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	// A post read without its version cannot be sent back as an expected version.
+	store.values["staging:post:42"] = `{"v":2,"payload":{"post":{"id":42,"author_id":1,"title":"Old","visibility":"public"}}}`
+
+	_, err := cache.GetPost(ctx, 42)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	require.NoError(t, cache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 42, AuthorID: 1, Title: "Old", Version: 4}}))
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), got.Post.Version)
+}
+
+func TestPostCache_Language(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	// A release without languages drops the field while another instance may already set it.
+	store.values["staging:post:42"] = `{"v":3,"payload":{"post":{"id":42,"author_id":1,"title":"Old","visibility":"public","version":1}}}`
+	_, err := cache.GetPost(ctx, 42)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	lang := "pt-BR"
+	require.NoError(t, cache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 42, AuthorID: 1, Title: "Olá", Language: &lang}}))
+	require.NoError(t, cache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 43, AuthorID: 1, Title: "Unset"}}))
+
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	require.NotNil(t, got.Post.Language)
+	assert.Equal(t, "pt-BR", *got.Post.Language)
+	unset, err := cache.GetPost(ctx, 43)
+	require.NoError(t, err)
+	assert.Nil(t, unset.Post.Language)
+	assert.NotContains(t, store.values["staging:post:43"], "language")
+}
+
+func TestPostCache_Source(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	// A release without sources drops the field while another instance may already set it.
+	store.values["staging:post:42"] = `{"v":7,"payload":{"post":{"id":42,"author_id":1,"title":"Old","version":1}}}`
+	_, err := cache.GetPost(ctx, 42)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	require.NoError(t, cache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 42, AuthorID: 1, Title: "From iOS", Source: model.PostSourceIOS}}))
+
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, model.PostSourceIOS, got.Post.Source)
+}
+
+func TestPostCache_EditCount(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	// An entry from before edit counts would read as never edited.
+	store.values["staging:post:42"] = `{"v":4,"payload":{"post":{"id":42,"author_id":1,"title":"Old","visibility":"public","version":3}}}`
+	_, err := cache.GetPost(ctx, 42)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	require.NoError(t, cache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 42, AuthorID: 1, Title: "Edited", EditCount: 2}}))
+
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), got.Post.EditCount)
+	assert.True(t, got.Post.Edited())
+}
+
+func TestPostCache_PinnedAt(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	// An entry from before pinning would read as not pinned.
+	store.values["staging:post:42"] = `{"v":5,"payload":{"post":{"id":42,"author_id":1,"title":"Old","edit_count":1}}}`
+	_, err := cache.GetPost(ctx, 42)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	pinnedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, cache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 42, AuthorID: 1, Title: "Pinned",
+		PinnedAt: testsupport.Timestamptz(pinnedAt)}}))
+
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	assert.True(t, got.Post.IsPinned())
+	assert.True(t, got.Post.PinnedAt.Time.Equal(pinnedAt))
+}
+
+func TestPostCache_CappedMedia(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	// An entry from before media were capped holds all of them without a total.
+	store.values["staging:post:42"] = `{"v":6,"payload":{"post":{"id":42,"author_id":1,"title":"Old"},"media":[{"id":1,"url":"https://example.com/1.jpg"}]}}`
+	_, err := cache.GetPost(ctx, 42)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	post := &model.PostDetailed{Post: &model.Post{ID: 42, AuthorID: 1, Title: "Imported"}}
+	for i := range 5 {
+		post.Media = append(post.Media, &model.PostMedia{ID: int64(i + 1), URL: fmt.Sprintf("https://example.com/%d.jpg", i+1), Type: model.MediaTypeImage, Position: int32(i + 1)})
+	}
+	post.CapMedia(2)
+	require.NoError(t, cache.SetPost(ctx, post))
+
+	got, err := cache.GetPost(ctx, 42)
+	require.NoError(t, err)
+	require.Len(t, got.Media, 2)
+	assert.Equal(t, "https://example.com/2.jpg", got.Media[1].URL)
+	assert.Equal(t, 5, got.MediaTotal)
+	assert.True(t, got.HasMoreMedia())
+}
+
+// hangingHook stands in for a Redis server that accepted the connection but never answers.
+type hangingHook struct{}
+
+func (hangingHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (hangingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		<-ctx.Done()
+		cmd.SetErr(ctx.Err())
+		return ctx.Err()
+	}
+}
+
+func (hangingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+}
+
+func TestClient_OpTimeout(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	rdb.AddHook(hangingHook{})
+	t.Cleanup(func() { _ = rdb.Close() })
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	client := &Client{client: rdb, log: logger.New("test"), metrics: metrics, opTimeout: 20 * time.Millisecond}
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), metrics)
+
+	start := time.Now()
+	_, err := cache.GetPost(context.Background(), 42)
+
+	require.ErrorIs(t, err, model.ErrTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+
+	batch := NewBatcher(client, testCacheConfig(), logger.New("test"), metrics).NewBatch()
+	batch.DeletePost(42)
+	assert.ErrorIs(t, batch.Exec(context.Background()), model.ErrTimeout)
+}
+
+func TestClient_CallerDeadlineIsNotATimeout(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	rdb.AddHook(hangingHook{})
+	t.Cleanup(func() { _ = rdb.Close() })
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	client := &Client{client: rdb, log: logger.New("test"), metrics: metrics, opTimeout: time.Minute}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var dest string
+	err := client.Get(ctx, "post:42", 1, &dest)
+
+	require.ErrorIs(t, err, model.ErrDeadlineExceeded)
+	assert.NotErrorIs(t, err, model.ErrTimeout)
+}
+
+// corruptionCounter records IncrementCacheCorruption calls per operation.
+type corruptionCounter struct {
+	*prometheus.PrometheusMetricsProvider
+	counts map[string]int
+}
+
+func (c *corruptionCounter) IncrementCacheCorruption(operation string) {
+	if c.counts == nil {
+		c.counts = map[string]int{}
+	}
+	c.counts[operation]++
+}
+
+func TestPostCache_CorruptEntriesAreDiscarded(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "legacy value without envelope", value: `{"post":{"id":42,"author_id":1,"title":"Old"}}`},
+		{name: "garbled value", value: "\x00\x01 not json"},
+		{name: "unknown payload version", value: `{"v":99,"payload":{"post":{"id":42}}}`},
+		{name: "payload of the wrong shape", value: `{"v":1,"payload":"a string"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, store := newTestClient(t)
+			metrics := &corruptionCounter{PrometheusMetricsProvider: &prometheus.PrometheusMetricsProvider{}}
+			client.metrics = metrics
+			cache := NewPostCache(client, testCacheConfig(), logger.New("test"), metrics)
+			store.values["staging:post:42"] = tt.value
+
+			got, err := cache.GetPost(context.Background(), 42)
+
+			assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+			assert.Nil(t, got)
+			assert.NotContains(t, store.values, "staging:post:42", "the corrupt entry is deleted")
+			assert.Equal(t, map[string]int{"post_get": 1}, metrics.counts)
+		})
+	}
+}
+
+func TestPostCache_GetPostsDiscardsCorruptEntries(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewPostCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	require.NoError(t, cache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 1}}))
+	store.values["staging:post:2"] = `{"post":{"id":2}}`
+
+	got, err := cache.GetPosts(ctx, []int64{1, 2})
+
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Contains(t, store.values, "staging:post:1")
+	assert.NotContains(t, store.values, "staging:post:2")
+}
+
+func TestUserCache_CorruptEntryIsDiscarded(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewUserCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	store.values["staging:user:7"] = `{"id":7,"username":"legacy"}`
+
+	got, err := cache.GetUser(context.Background(), 7)
+
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+	assert.Nil(t, got)
+	assert.NotContains(t, store.values, "staging:user:7")
+}
+
+func TestUserCache_PostCount(t *testing.T) {
+	client, store := newTestClient(t)
+	cfg := testCacheConfig()
+	cache := NewUserCache(client, cfg, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	batcher := NewBatcher(client, cfg, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	_, err := cache.GetUserPostCount(ctx, 7)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	require.NoError(t, cache.SetUserPostCount(ctx, 7, 3))
+	assert.Equal(t, "3", store.values["staging:user_posts_meta:7"])
+	assert.Equal(t, 30*time.Second, store.ttls["staging:user_posts_meta:7"])
+
+	batch := batcher.NewBatch()
+	batch.AdjustUserPostCount(7, 1)
+	batch.AdjustUserPostCount(8, 1)
+	require.NoError(t, batch.Exec(ctx))
+
+	got, err := cache.GetUserPostCount(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), got)
+	assert.NotContains(t, store.values, "staging:user_posts_meta:8", "a missing count stays missing")
+
+	require.NoError(t, cache.InvalidateUserPostsMeta(ctx, 7))
+	_, err = cache.GetUserPostCount(ctx, 7)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	store.values["staging:user_posts_meta:7"] = `{"v":1}`
+	_, err = cache.GetUserPostCount(ctx, 7)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+	assert.NotContains(t, store.values, "staging:user_posts_meta:7", "the corrupt count is deleted")
+}
+
+func TestUserCache_BlockedUsers(t *testing.T) {
+	client, store := newTestClient(t)
+	cache := NewUserCache(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	_, err := cache.GetBlockedUsers(ctx, 7)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	require.NoError(t, cache.SetBlockedUsers(ctx, 7, []int64{3, 5}))
+	assert.Equal(t, 30*time.Second, store.ttls["staging:user_blocks:7"])
+	got, err := cache.GetBlockedUsers(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 5}, got)
+
+	require.NoError(t, cache.SetBlockedUsers(ctx, 8, nil))
+	empty, err := cache.GetBlockedUsers(ctx, 8)
+	require.NoError(t, err, "an empty block list is cached too")
+	assert.Empty(t, empty)
+}
+
+func TestUserCache_RecentPosts(t *testing.T) {
+	client, store := newTestClient(t)
+	cfg := testCacheConfig()
+	cache := NewUserCache(client, cfg, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	batcher := NewBatcher(client, cfg, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	_, err := cache.GetRecentPosts(ctx, 7)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	batch := batcher.NewBatch()
+	batch.AddRecentPost(7, 10)
+	batch.AddRecentPost(7, 11)
+	batch.AddRecentPost(7, 10)
+	require.NoError(t, batch.Exec(ctx))
+
+	got, err := cache.GetRecentPosts(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{10, 11}, got, "newest first, without duplicates")
+	assert.Equal(t, 30*time.Second, store.ttls["staging:user_recent_posts:7"])
+
+	batch = batcher.NewBatch()
+	for id := range int64(maxRecentPosts + 5) {
+		batch.AddRecentPost(8, 100+id)
+	}
+	require.NoError(t, batch.Exec(ctx))
+	got, err = cache.GetRecentPosts(ctx, 8)
+	require.NoError(t, err)
+	require.Len(t, got, maxRecentPosts)
+	assert.Equal(t, int64(100+maxRecentPosts+4), got[0])
+
+	store.values["staging:user_recent_posts:7"] = "10,eleven"
+	_, err = cache.GetRecentPosts(ctx, 7)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+	assert.NotContains(t, store.values, "staging:user_recent_posts:7", "the corrupt list is deleted")
+}
+
+// The decorator sees a corrupt entry as a plain miss: it reads the post from the service and
+// the refill overwrites the entry with a current envelope.
+func TestPostServiceCacheDecorator_RepairsCorruptEntry(t *testing.T) {
+	client, store := newTestClient(t)
+	cfg := testCacheConfig()
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	store.values["staging:post:42"] = `{"post":{"id":42,"title":"Half-decoded"}}`
+
+	post := &model.PostDetailed{Post: &model.Post{ID: 42, AuthorID: 1, Title: "From the database", Status: model.PostStatusPublished}}
+	service := new(post_service_mock.Service)
+	service.On("GetPostByID", mock.Anything, int64(42), (*int64)(nil)).Return(post, nil).Once()
+
+	d := post_service.NewPostServiceCacheDecorator(service, NewUserCache(client, cfg, log, metrics),
+		NewPostCache(client, cfg, log, metrics), NewBatcher(client, cfg, log, metrics), log, metrics)
+
+	got, err := d.GetPostByID(context.Background(), 42, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "From the database", got.Post.Title)
+
+	var repaired model.PostDetailed
+	_, err = decodeEnvelope([]byte(store.values["staging:post:42"]), postPayloadVersion, &repaired)
+	require.NoError(t, err)
+	assert.Equal(t, "From the database", repaired.Post.Title)
+
+	again, err := d.GetPostByID(context.Background(), 42, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "From the database", again.Post.Title)
+	service.AssertExpectations(t)
+}
+
+// TestPostServiceCacheDecorator_KeysTouched pins down the Redis keys each post write sets or
+// deletes. A failure here means the invalidation strategy changed: update the table on purpose.
+func TestPostServiceCacheDecorator_KeysTouched(t *testing.T) {
+	author := &model.User{ID: 1, Username: "author"}
+	post := &model.PostDetailed{Post: &model.Post{ID: 10, AuthorID: 1, Title: "Post", Status: model.PostStatusPublished}, Author: author}
+	title := "New title"
+
+	tests := []struct {
+		name  string
+		mocks func(service *post_service_mock.Service)
+		call  func(ctx context.Context, d post_ports.Service) error
+		want  []string
+	}{
+		{
+			name: "create",
+			mocks: func(service *post_service_mock.Service) {
+				service.On("CreatePost", mock.Anything, mock.Anything).Return(post, nil)
+			},
+			call: func(ctx context.Context, d post_ports.Service) error {
+				_, err := d.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 1, Title: "Post"})
+				return err
+			},
+			want: []string{"EVAL staging:user_posts_meta:1", "SET staging:post:10", "EVAL staging:user_recent_posts:1", "SET staging:user:1"},
+		},
+		{
+			name: "update",
+			mocks: func(service *post_service_mock.Service) {
+				service.On("UpdatePost", mock.Anything, int64(1), int64(10), mock.Anything).Return(post, nil)
+			},
+			call: func(ctx context.Context, d post_ports.Service) error {
+				_, err := d.UpdatePost(ctx, 1, 10, &model.UpdatePostDTO{Title: &title})
+				return err
+			},
+			want: []string{"SET staging:post:10"},
+		},
+		{
+			name: "delete",
+			mocks: func(service *post_service_mock.Service) {
+				service.On("DeletePost", mock.Anything, int64(1), int64(10)).Return(nil)
+			},
+			call: func(ctx context.Context, d post_ports.Service) error {
+				return d.DeletePost(ctx, 1, 10)
+			},
+			want: []string{"DEL staging:post:10", "DEL staging:user_posts_meta:1"},
+		},
+		{
+			name: "publish",
+			mocks: func(service *post_service_mock.Service) {
+				service.On("PublishPost", mock.Anything, int64(1), int64(10)).Return(post, nil)
+			},
+			call: func(ctx context.Context, d post_ports.Service) error {
+				_, err := d.PublishPost(ctx, 1, 10)
+				return err
+			},
+			want: []string{"DEL staging:post:10", "DEL staging:user_posts_meta:1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, store := newTestClient(t)
+			cfg := testCacheConfig()
+			log := logger.New("test")
+			metrics := prometheus.NewPrometheusMetricsProvider()
+			service := new(post_service_mock.Service)
+			tt.mocks(service)
+			d := post_service.NewPostServiceCacheDecorator(service, NewUserCache(client, cfg, log, metrics),
+				NewPostCache(client, cfg, log, metrics), NewBatcher(client, cfg, log, metrics), log, metrics)
+
+			require.NoError(t, tt.call(context.Background(), d))
+
+			assert.Equal(t, tt.want, store.writes)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReconnect_StopsWithContext(t *testing.T) {
+	cfg := config.Redis{
+		Address:           "127.0.0.1",
+		Port:              1,
+		PoolSize:          1,
+		DialTimeout:       50 * time.Millisecond,
+		ReadTimeout:       50 * time.Millisecond,
+		WriteTimeout:      50 * time.Millisecond,
+		ConnectTimeout:    50 * time.Millisecond,
+		ReconnectInterval: 10 * time.Millisecond,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	assert.Nil(t, Reconnect(ctx, cfg, logger.New("test"), prometheus.NewPrometheusMetricsProvider()))
+	assert.Error(t, ctx.Err(), "Reconnect keeps retrying until the context is done")
+}
@@ -2,12 +2,14 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strconv"
 	"time"
 
+	model "pinstack-post-service/internal/domain/models"
 	ports "pinstack-post-service/internal/domain/ports/output"
 	"pinstack-post-service/internal/infrastructure/config"
 
@@ -15,24 +17,37 @@ import (
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 )
 
+// pipelineCommandsPerTimeout is how many pipelined commands share one operation timeout.
+const pipelineCommandsPerTimeout = 10
+
 type Client struct {
-	client *redis.Client
-	log    ports.Logger
+	client    *redis.Client
+	log       ports.Logger
+	metrics   ports.MetricsProvider
+	opTimeout time.Duration
 }
 
-func NewClient(cfg config.Redis, log ports.Logger) (*Client, error) {
+func NewClient(cfg config.Redis, log ports.Logger, metrics ports.MetricsProvider) (*Client, error) {
 	rdb := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", cfg.Address, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
-		PoolSize: cfg.PoolSize,
+		Addr:         fmt.Sprintf("%s:%d", cfg.Address, cfg.Port),
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
 	})
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
 	defer cancel()
 
 	if err := rdb.Ping(ctx).Err(); err != nil {
-		log.Error("Failed to connect to Redis", slog.String("error", err.Error()))
+		log.Error("Failed to connect to Redis",
+			slog.String("address", cfg.Address),
+			slog.Int("port", cfg.Port),
+			slog.Duration("timeout", cfg.ConnectTimeout),
+			slog.String("error", err.Error()))
+		_ = rdb.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
@@ -42,12 +57,70 @@ func NewClient(cfg config.Redis, log ports.Logger) (*Client, error) {
 		slog.Int("db", cfg.DB))
 
 	return &Client{
-		client: rdb,
-		log:    log,
+		client:    rdb,
+		log:       log,
+		metrics:   metrics,
+		opTimeout: cfg.OpTimeout,
 	}, nil
 }
 
-func (c *Client) Get(ctx context.Context, key string, dest interface{}) error {
+// Reconnect calls NewClient every cfg.ReconnectInterval until Redis answers, and returns the
+// client. It returns nil once ctx is done.
+func Reconnect(ctx context.Context, cfg config.Redis, log ports.Logger, metrics ports.MetricsProvider) *Client {
+	ticker := time.NewTicker(cfg.ReconnectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		client, err := NewClient(cfg, log, metrics)
+		if err == nil {
+			return client
+		}
+		log.Debug("Redis still unavailable", slog.Duration("retry_in", cfg.ReconnectInterval))
+	}
+}
+
+// PoolStats reports the connections of the Redis pool.
+func (c *Client) PoolStats() *redis.PoolStats {
+	return c.client.PoolStats()
+}
+
+// withTimeout bounds a call that sends the given number of commands by the operation timeout,
+// scaled up for pipelines. Without a configured timeout ctx is returned as is.
+func (c *Client) withTimeout(ctx context.Context, commands int) (context.Context, context.CancelFunc) {
+	if c.opTimeout <= 0 {
+		return ctx, func() {}
+	}
+	shares := (commands + pipelineCommandsPerTimeout - 1) / pipelineCommandsPerTimeout
+	return context.WithTimeoutCause(ctx, c.opTimeout*time.Duration(max(shares, 1)), model.ErrTimeout)
+}
+
+// timeoutError marks err as model.ErrTimeout when the operation timeout ran out while it was
+// being produced, and as a caller error when the caller's context ended first. Either is
+// counted under operation.
+func (c *Client) timeoutError(ctx context.Context, operation string, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	if !errors.Is(context.Cause(ctx), model.ErrTimeout) {
+		err = model.CallerError(ctx, err)
+		c.metrics.IncrementCallerAborts("redis", operation, errors.Is(err, model.ErrDeadlineExceeded))
+		return err
+	}
+	c.metrics.IncrementOperationTimeouts("redis", operation)
+	return fmt.Errorf("%w: %w", model.ErrTimeout, err)
+}
+
+// Get decodes the value at key into dest. A value that is not an envelope of the given
+// payload version fails with errCorruptEntry.
+func (c *Client) Get(ctx context.Context, key string, version int, dest interface{}) error {
+	ctx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+
 	val, err := c.client.Get(ctx, key).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
@@ -57,22 +130,90 @@ func (c *Client) Get(ctx context.Context, key string, dest interface{}) error {
 		c.log.Error("Failed to get from cache",
 			slog.String("key", key),
 			slog.String("error", err.Error()))
-		return fmt.Errorf("failed to get from cache: %w", err)
+		return fmt.Errorf("failed to get from cache: %w", c.timeoutError(ctx, "get", err))
 	}
 
-	if err := json.Unmarshal([]byte(val), dest); err != nil {
-		c.log.Error("Failed to unmarshal cache value",
+	if _, err := decodeEnvelope([]byte(val), version, dest); err != nil {
+		c.log.Warn("Failed to decode cache value",
 			slog.String("key", key),
 			slog.String("error", err.Error()))
-		return fmt.Errorf("failed to unmarshal cache value: %w", err)
+		return err
 	}
 
 	c.log.Debug("Cache hit", slog.String("key", key))
 	return nil
 }
 
-func (c *Client) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
-	data, err := json.Marshal(value)
+// entryMeta describes a cached value beside its payload. cachedAt is when it was written, zero
+// for an entry written before envelopes recorded it; ttl is how long it has left to live,
+// negative for a value stored without expiry.
+type entryMeta struct {
+	cachedAt time.Time
+	ttl      time.Duration
+}
+
+// GetWithMeta is Get that also returns the metadata of the value, read in the same round trip.
+func (c *Client) GetWithMeta(ctx context.Context, key string, version int, dest interface{}) (entryMeta, error) {
+	ctx, cancel := c.withTimeout(ctx, 2)
+	defer cancel()
+
+	pipe := c.client.Pipeline()
+	get := pipe.Get(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+	_, err := pipe.Exec(ctx)
+	val, getErr := get.Result()
+	if getErr != nil {
+		err = getErr
+	}
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			c.log.Debug("Cache miss", slog.String("key", key))
+			return entryMeta{}, custom_errors.ErrCacheMiss
+		}
+		c.log.Error("Failed to get from cache",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
+		return entryMeta{}, fmt.Errorf("failed to get from cache: %w", c.timeoutError(ctx, "get_ttl", err))
+	}
+
+	cachedAt, err := decodeEnvelope([]byte(val), version, dest)
+	if err != nil {
+		c.log.Warn("Failed to decode cache value",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
+		return entryMeta{}, err
+	}
+
+	c.log.Debug("Cache hit", slog.String("key", key))
+	return entryMeta{cachedAt: cachedAt, ttl: ttl.Val()}, nil
+}
+
+// MGet fetches keys in one command. The result holds the raw envelope of each key in order,
+// or nil for a key that is not cached; decode each with decodeEnvelope.
+func (c *Client) MGet(ctx context.Context, keys []string) ([]*string, error) {
+	ctx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+
+	vals, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		c.log.Error("Failed to get keys from cache",
+			slog.Int("keys", len(keys)),
+			slog.String("error", err.Error()))
+		return nil, fmt.Errorf("failed to get from cache: %w", c.timeoutError(ctx, "mget", err))
+	}
+
+	result := make([]*string, len(vals))
+	for i, val := range vals {
+		if s, ok := val.(string); ok {
+			result[i] = &s
+		}
+	}
+	return result, nil
+}
+
+// Set stores value at key in an envelope of the given payload version.
+func (c *Client) Set(ctx context.Context, key string, version int, value interface{}, ttl time.Duration) error {
+	data, err := encodeEnvelope(version, value, time.Now())
 	if err != nil {
 		c.log.Error("Failed to marshal value for cache",
 			slog.String("key", key),
@@ -80,11 +221,14 @@ func (c *Client) Set(ctx context.Context, key string, value interface{}, ttl tim
 		return fmt.Errorf("failed to marshal value: %w", err)
 	}
 
+	ctx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+
 	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
 		c.log.Error("Failed to set cache",
 			slog.String("key", key),
 			slog.String("error", err.Error()))
-		return fmt.Errorf("failed to set cache: %w", err)
+		return fmt.Errorf("failed to set cache: %w", c.timeoutError(ctx, "set", err))
 	}
 
 	c.log.Debug("Successfully set cache",
@@ -93,13 +237,73 @@ func (c *Client) Set(ctx context.Context, key string, value interface{}, ttl tim
 	return nil
 }
 
+// GetCount reads a counter stored by SetCount. A value that is not an integer fails with
+// errCorruptEntry.
+func (c *Client) GetCount(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+
+	val, err := c.client.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			c.log.Debug("Cache miss", slog.String("key", key))
+			return 0, custom_errors.ErrCacheMiss
+		}
+		c.log.Error("Failed to get counter from cache",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
+		return 0, fmt.Errorf("failed to get from cache: %w", c.timeoutError(ctx, "get", err))
+	}
+
+	count, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		c.log.Warn("Failed to parse cached counter",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
+		return 0, fmt.Errorf("%w: %w", errCorruptEntry, err)
+	}
+	return count, nil
+}
+
+// SetCount stores a bare integer rather than an envelope, so that it can be adjusted in place
+// with INCRBY.
+func (c *Client) SetCount(ctx context.Context, key string, count int64, ttl time.Duration) error {
+	ctx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+
+	if err := c.client.Set(ctx, key, strconv.FormatInt(count, 10), ttl).Err(); err != nil {
+		c.log.Error("Failed to set counter in cache",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
+		return fmt.Errorf("failed to set cache: %w", c.timeoutError(ctx, "set", err))
+	}
+	return nil
+}
+
+// Publish sends message on channel. Redis delivers it to the current subscribers only.
+func (c *Client) Publish(ctx context.Context, channel string, message []byte) error {
+	ctx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+
+	if err := c.client.Publish(ctx, channel, message).Err(); err != nil {
+		c.log.Error("Failed to publish",
+			slog.String("channel", channel),
+			slog.String("error", err.Error()))
+		return fmt.Errorf("failed to publish: %w", c.timeoutError(ctx, "publish", err))
+	}
+	return nil
+}
+
 func (c *Client) Delete(ctx context.Context, key string) error {
+	ctx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+
 	result, err := c.client.Del(ctx, key).Result()
 	if err != nil {
 		c.log.Error("Failed to delete from cache",
 			slog.String("key", key),
 			slog.String("error", err.Error()))
-		return fmt.Errorf("failed to delete from cache: %w", err)
+		return fmt.Errorf("failed to delete from cache: %w", c.timeoutError(ctx, "delete", err))
 	}
 
 	if result == 0 {
@@ -111,13 +315,41 @@ func (c *Client) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// DeleteKeys deletes keys in one pipeline of DEL commands of at most chunkSize keys each, so
+// no single command blocks Redis for long. It returns how many keys existed.
+func (c *Client) DeleteKeys(ctx context.Context, keys []string, chunkSize int) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx, len(keys))
+	defer cancel()
+
+	pipe := c.client.Pipeline()
+	var dels []*redis.IntCmd
+	for chunk := range slices.Chunk(keys, chunkSize) {
+		dels = append(dels, pipe.Del(ctx, chunk...))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.log.Error("Failed to delete keys from cache",
+			slog.Int("keys", len(keys)),
+			slog.String("error", err.Error()))
+		return 0, fmt.Errorf("failed to delete from cache: %w", c.timeoutError(ctx, "delete", err))
+	}
+
+	var deleted int64
+	for _, del := range dels {
+		deleted += del.Val()
+	}
+	return deleted, nil
+}
+
 func (c *Client) DeletePattern(ctx context.Context, pattern string) error {
-	keys, err := c.client.Keys(ctx, pattern).Result()
+	keysCtx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+
+	keys, err := c.client.Keys(keysCtx, pattern).Result()
 	if err != nil {
 		c.log.Error("Failed to find keys by pattern",
 			slog.String("pattern", pattern),
 			slog.String("error", err.Error()))
-		return fmt.Errorf("failed to find keys by pattern: %w", err)
+		return fmt.Errorf("failed to find keys by pattern: %w", c.timeoutError(keysCtx, "keys", err))
 	}
 
 	if len(keys) == 0 {
@@ -125,12 +357,15 @@ func (c *Client) DeletePattern(ctx context.Context, pattern string) error {
 		return nil
 	}
 
-	deleted, err := c.client.Del(ctx, keys...).Result()
+	delCtx, cancel := c.withTimeout(ctx, len(keys))
+	defer cancel()
+
+	deleted, err := c.client.Del(delCtx, keys...).Result()
 	if err != nil {
 		c.log.Error("Failed to delete keys by pattern",
 			slog.String("pattern", pattern),
 			slog.String("error", err.Error()))
-		return fmt.Errorf("failed to delete keys by pattern: %w", err)
+		return fmt.Errorf("failed to delete keys by pattern: %w", c.timeoutError(delCtx, "delete", err))
 	}
 
 	c.log.Debug("Successfully deleted keys by pattern",
@@ -140,6 +375,19 @@ func (c *Client) DeletePattern(ctx context.Context, pattern string) error {
 	return nil
 }
 
+// Scan returns one page of the keys matching pattern and the cursor of the next page, which
+// is 0 once the iteration is complete. count is a hint for how many keys Redis looks at.
+func (c *Client) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	ctx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+
+	keys, next, err := c.client.Scan(ctx, cursor, pattern, count).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan keys: %w", c.timeoutError(ctx, "scan", err))
+	}
+	return keys, next, nil
+}
+
 func (c *Client) Close() error {
 	if err := c.client.Close(); err != nil {
 		c.log.Error("Failed to close Redis connection", slog.String("error", err.Error()))
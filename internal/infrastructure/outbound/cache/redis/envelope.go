@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Versions of the cached payloads. Bump a version whenever the JSON shape of its model
+// changes: entries written by an older release then read as misses and are refilled,
+// instead of decoding into half-empty structs.
+const (
+	postPayloadVersion           = 8
+	userPayloadVersion           = 1
+	tagSuggestionsPayloadVersion = 1
+	blockedUsersPayloadVersion   = 1
+)
+
+// errCorruptEntry marks a cached value that cannot be used: it is not an envelope, carries
+// another payload version, or its payload does not decode.
+var errCorruptEntry = errors.New("corrupt cache entry")
+
+// envelope wraps every cached value with the version of its payload and the time it was
+// written. CachedAt is not part of the payload: entries written before it existed decode with
+// a zero CachedAt instead of reading as corrupt.
+type envelope struct {
+	Version  int             `json:"v"`
+	CachedAt time.Time       `json:"cached_at,omitzero"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+func encodeEnvelope(version int, value interface{}, cachedAt time.Time) ([]byte, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{Version: version, CachedAt: cachedAt.UTC(), Payload: payload})
+}
+
+// decodeEnvelope decodes the payload of data into dest and returns when the entry was written.
+func decodeEnvelope(data []byte, version int, dest interface{}) (time.Time, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", errCorruptEntry, err)
+	}
+	if env.Version != version {
+		return time.Time{}, fmt.Errorf("%w: payload version %d, want %d", errCorruptEntry, env.Version, version)
+	}
+	if err := json.Unmarshal(env.Payload, dest); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", errCorruptEntry, err)
+	}
+	return env.CachedAt, nil
+}
+
+// discard deletes corrupt entries so the next read misses cleanly and refills them. A failed
+// delete is only logged: the entry keeps reading as a miss until it is overwritten or expires.
+func (c *Client) discard(ctx context.Context, operation string, keys ...string) {
+	for range keys {
+		c.metrics.IncrementCacheCorruption(operation)
+	}
+
+	ctx, cancel := c.withTimeout(ctx, 1)
+	defer cancel()
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		c.log.Warn("Failed to delete corrupt cache entries",
+			slog.Any("keys", keys),
+			slog.String("error", c.timeoutError(ctx, "delete", err).Error()))
+		return
+	}
+	c.log.Warn("Discarded corrupt cache entries", slog.Any("keys", keys))
+}
@@ -0,0 +1,29 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/config"
+)
+
+// EventPublisher publishes post events as JSON on a Redis pub/sub channel. Subscribers that are
+// not connected when an event is published never see it.
+type EventPublisher struct {
+	client  *Client
+	channel string
+}
+
+func NewEventPublisher(client *Client, cfg config.Events) *EventPublisher {
+	return &EventPublisher{client: client, channel: cfg.Channel}
+}
+
+func (p *EventPublisher) Publish(ctx context.Context, event model.PostEvent) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal post event: %w", err)
+	}
+	return p.client.Publish(ctx, p.channel, message)
+}
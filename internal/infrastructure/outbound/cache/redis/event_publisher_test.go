@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	post_service "pinstack-post-service/internal/application/service/post"
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/config"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	post_service_mock "pinstack-post-service/mocks/post"
+)
+
+func TestEventPublisher_PublishesOncePerCommittedChange(t *testing.T) {
+	client, store := newTestClient(t)
+	publisher := NewEventPublisher(client, config.Events{Enabled: true, Channel: "post-events"})
+	post := &model.PostDetailed{Post: &model.Post{ID: 10, AuthorID: 3, Title: "Post"}}
+
+	service := new(post_service_mock.Service)
+	service.On("CreatePost", mock.Anything, mock.Anything).Return(post, nil).Once()
+	service.On("UpdatePost", mock.Anything, int64(3), int64(10), mock.Anything).Return(post, nil).Once()
+	// A failed update was rolled back and must not be announced.
+	service.On("UpdatePost", mock.Anything, int64(4), int64(10), mock.Anything).Return(nil, custom_errors.ErrForbidden).Once()
+	service.On("DeletePost", mock.Anything, int64(3), int64(10)).Return(nil).Once()
+	d := post_service.NewPostServiceEventDecorator(service, publisher, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	_, err := d.CreatePost(ctx, &model.CreatePostDTO{AuthorID: 3, Title: "Post"})
+	require.NoError(t, err)
+	_, err = d.UpdatePost(ctx, 3, 10, &model.UpdatePostDTO{UserID: 3})
+	require.NoError(t, err)
+	_, err = d.UpdatePost(ctx, 4, 10, &model.UpdatePostDTO{UserID: 4})
+	require.ErrorIs(t, err, custom_errors.ErrForbidden)
+	require.NoError(t, d.DeletePost(ctx, 3, 10))
+
+	require.Len(t, store.published, 3)
+	var types []model.PostEventType
+	for _, published := range store.published {
+		channel, message, _ := strings.Cut(published, " ")
+		assert.Equal(t, "post-events", channel)
+		var event model.PostEvent
+		require.NoError(t, json.Unmarshal([]byte(message), &event))
+		assert.Equal(t, int64(10), event.PostID)
+		assert.Equal(t, int64(3), event.AuthorID)
+		assert.False(t, event.OccurredAt.IsZero())
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []model.PostEventType{model.PostEventCreated, model.PostEventUpdated, model.PostEventDeleted}, types)
+	service.AssertExpectations(t)
+}
+
+func TestEventPublisher_MessageFormat(t *testing.T) {
+	client, store := newTestClient(t)
+	publisher := NewEventPublisher(client, config.Events{Enabled: true, Channel: "post-events"})
+
+	require.NoError(t, publisher.Publish(context.Background(), model.PostEvent{Type: model.PostEventCreated, PostID: 1, AuthorID: 2}))
+
+	require.Len(t, store.published, 1)
+	assert.Equal(t, `post-events {"type":"post_created","post_id":1,"author_id":2,"occurred_at":"0001-01-01T00:00:00Z"}`, store.published[0])
+}
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/config"
+)
+
+// cacheKeyPrefixes maps the key prefix of each cache to the cache label of its metrics.
+// userCacheKeyPrefix, userPostsMetaKeyPrefix and userBlocksKeyPrefix do not prefix one another.
+var cacheKeyPrefixes = []struct {
+	prefix string
+	cache  string
+}{
+	{postCacheKeyPrefix, "post"},
+	{userCacheKeyPrefix, "user"},
+	{userPostsMetaKeyPrefix, "user_posts_meta"},
+	{userBlocksKeyPrefix, "user_blocks"},
+	{tagSuggestionsKeyPrefix, "tag_suggestions"},
+	{rateLimitKeyPrefix, "ratelimit"},
+}
+
+// KeyCountCollector samples how many keys each cache holds into the cache_keys gauges once
+// per interval. It walks the keys under the configured prefix with SCAN, so Redis is never
+// blocked, and stops after the configured limit: past it the counts are lower bounds.
+type KeyCountCollector struct {
+	client    *Client
+	log       ports.Logger
+	metrics   ports.MetricsProvider
+	keyPrefix string
+	interval  time.Duration
+	scanCount int64
+	limit     int
+}
+
+func NewKeyCountCollector(client *Client, cfg config.Cache, log ports.Logger, metrics ports.MetricsProvider) *KeyCountCollector {
+	return &KeyCountCollector{
+		client:    client,
+		log:       log,
+		metrics:   metrics,
+		keyPrefix: cfg.KeyPrefix,
+		interval:  cfg.KeyCount.Interval,
+		scanCount: int64(cfg.KeyCount.ScanCount),
+		limit:     cfg.KeyCount.Limit,
+	}
+}
+
+// Collect samples the keys once. The gauges are left as they were when the scan fails.
+func (c *KeyCountCollector) Collect(ctx context.Context) error {
+	counts := make(map[string]int, len(cacheKeyPrefixes))
+	scanned := 0
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, c.keyPrefix+"*", c.scanCount)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if cache, ok := c.cacheOf(key); ok {
+				counts[cache]++
+			}
+		}
+		scanned += len(keys)
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+		if scanned >= c.limit {
+			c.log.Debug("Cache key sample stopped at its limit", slog.Int("limit", c.limit))
+			break
+		}
+	}
+
+	for _, p := range cacheKeyPrefixes {
+		c.metrics.SetCacheKeys(p.cache, counts[p.cache])
+	}
+	return nil
+}
+
+func (c *KeyCountCollector) cacheOf(key string) (string, bool) {
+	key, ok := strings.CutPrefix(key, c.keyPrefix)
+	if !ok {
+		return "", false
+	}
+	for _, p := range cacheKeyPrefixes {
+		if strings.HasPrefix(key, p.prefix) {
+			return p.cache, true
+		}
+	}
+	return "", false
+}
+
+// Run collects right away and then once per interval until ctx is done.
+func (c *KeyCountCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Collect(ctx); err != nil && ctx.Err() == nil {
+			c.log.Warn("Failed to sample cache key counts", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/config"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+)
+
+// keyCounts records the last SetCacheKeys call per cache.
+type keyCounts struct {
+	*prometheus.PrometheusMetricsProvider
+	counts map[string]int
+}
+
+func (k *keyCounts) SetCacheKeys(cache string, count int) {
+	if k.counts == nil {
+		k.counts = map[string]int{}
+	}
+	k.counts[cache] = count
+}
+
+func keyCountConfig(scanCount, limit int) config.Cache {
+	cfg := testCacheConfig()
+	cfg.KeyCount = config.CacheKeyCount{Interval: time.Minute, ScanCount: scanCount, Limit: limit}
+	return cfg
+}
+
+func TestKeyCountCollector_Collect(t *testing.T) {
+	client, store := newTestClient(t)
+	for i := range 3 {
+		store.values["staging:post:"+strconv.Itoa(i)] = "{}"
+	}
+	store.values["staging:user:1"] = "{}"
+	store.values["staging:user_posts_meta:1"] = "4"
+	store.values["staging:user_blocks:1"] = "[]"
+	store.values["staging:tag_suggestions:10:go"] = "[]"
+	store.values["staging:unknown:1"] = "{}"
+	store.values["production:post:1"] = "{}"
+
+	metrics := &keyCounts{}
+	collector := NewKeyCountCollector(client, keyCountConfig(2, 100), logger.New("test"), metrics)
+	require.NoError(t, collector.Collect(context.Background()))
+
+	assert.Equal(t, map[string]int{
+		"post":            3,
+		"user":            1,
+		"user_posts_meta": 1,
+		"user_blocks":     1,
+		"tag_suggestions": 1,
+		"ratelimit":       0,
+	}, metrics.counts, "keys of other prefixes and unknown caches are not counted")
+}
+
+func TestKeyCountCollector_StopsAtLimit(t *testing.T) {
+	client, store := newTestClient(t)
+	for i := range 10 {
+		store.values["staging:post:"+strconv.Itoa(i)] = "{}"
+	}
+
+	metrics := &keyCounts{}
+	collector := NewKeyCountCollector(client, keyCountConfig(2, 4), logger.New("test"), metrics)
+	store.roundTrips = 0
+	require.NoError(t, collector.Collect(context.Background()))
+
+	assert.Equal(t, 4, metrics.counts["post"], "the sample reports a lower bound past its limit")
+	assert.Equal(t, 2, store.roundTrips)
+}
+
+func TestKeyCountCollector_KeepsGaugesOnTimeout(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	rdb.AddHook(hangingHook{})
+	t.Cleanup(func() { _ = rdb.Close() })
+	metrics := &keyCounts{PrometheusMetricsProvider: &prometheus.PrometheusMetricsProvider{}}
+	client := &Client{client: rdb, log: logger.New("test"), metrics: metrics, opTimeout: 20 * time.Millisecond}
+	collector := NewKeyCountCollector(client, keyCountConfig(2, 100), logger.New("test"), metrics)
+
+	assert.ErrorIs(t, collector.Collect(context.Background()), model.ErrTimeout)
+	assert.Empty(t, metrics.counts)
+}
@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -10,26 +11,58 @@ import (
 
 	model "pinstack-post-service/internal/domain/models"
 	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/config"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 )
 
 const (
 	postCacheKeyPrefix = "post:"
-	postCacheTTL       = 30 * time.Minute
+	// tagSuggestionsKeyPrefix namespaces tag suggestions by prefix and limit.
+	tagSuggestionsKeyPrefix = "tag_suggestions:"
+	// tagLatestPostKeyPrefix namespaces the newest post time of each tag, stored as a bare
+	// Unix time in microseconds so a script can compare it in place.
+	tagLatestPostKeyPrefix = "tag_latest_post:"
+	// deletePostsChunkSize caps the keys of one DEL sent by DeletePosts.
+	deletePostsChunkSize = 500
 )
 
+// advanceTimestampScript sets KEYS[1] to ARGV[1] with a TTL of ARGV[2] milliseconds unless it
+// already holds a later time. A value that is not a number is overwritten.
+var advanceTimestampScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]))
+if current and current >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
 type PostCache struct {
-	client  *Client
-	log     ports.Logger
-	metrics ports.MetricsProvider
+	client    *Client
+	keyPrefix string
+	ttl       time.Duration
+	// listTTL is reserved for cached list pages; ListPosts is not cached yet.
+	listTTL time.Duration
+	// suggestionTTL is short: cached suggestions are never invalidated.
+	suggestionTTL time.Duration
+	// tagLatestTTL bounds how long a tag's newest post time misses posts created elsewhere.
+	tagLatestTTL time.Duration
+	log          ports.Logger
+	metrics      ports.MetricsProvider
 }
 
-func NewPostCache(client *Client, log ports.Logger, metrics ports.MetricsProvider) *PostCache {
+func NewPostCache(client *Client, cfg config.Cache, log ports.Logger, metrics ports.MetricsProvider) *PostCache {
 	return &PostCache{
-		client:  client,
-		log:     log,
-		metrics: metrics,
+		client:        client,
+		keyPrefix:     cfg.KeyPrefix,
+		ttl:           cfg.PostTTL,
+		listTTL:       cfg.ListTTL,
+		suggestionTTL: cfg.TagSuggestionTTL,
+		tagLatestTTL:  cfg.TagLatestTTL,
+		log:           log,
+		metrics:       metrics,
 	}
 }
 
@@ -38,11 +71,15 @@ func (p *PostCache) GetPost(ctx context.Context, postID int64) (*model.PostDetai
 	key := p.getPostKey(postID)
 
 	var post model.PostDetailed
-	err := p.client.Get(ctx, key, &post)
+	meta, err := p.client.GetWithMeta(ctx, key, postPayloadVersion, &post)
+	if errors.Is(err, errCorruptEntry) {
+		p.client.discard(ctx, "post_get", key)
+		err = custom_errors.ErrCacheMiss
+	}
 	if err != nil {
 		if errors.Is(err, custom_errors.ErrCacheMiss) {
 			p.log.Debug("Post cache miss", slog.Int64("post_id", postID))
-			p.metrics.IncrementCacheMisses()
+			p.metrics.IncrementCacheMisses("post")
 			p.metrics.RecordCacheMissDuration("post_get", time.Since(start))
 			return nil, custom_errors.ErrCacheMiss
 		}
@@ -53,12 +90,59 @@ func (p *PostCache) GetPost(ctx context.Context, postID int64) (*model.PostDetai
 		return nil, fmt.Errorf("failed to get post from cache: %w", err)
 	}
 
-	p.metrics.IncrementCacheHits()
+	post.CachedAt = meta.cachedAt
+	post.CacheTTL = max(meta.ttl, 0)
+	p.metrics.IncrementCacheHits("post")
 	p.metrics.RecordCacheHitDuration("post_get", time.Since(start))
 	p.log.Debug("Post cache hit", slog.Int64("post_id", postID))
 	return &post, nil
 }
 
+func (p *PostCache) GetPosts(ctx context.Context, postIDs []int64) (map[int64]*model.PostDetailed, error) {
+	start := time.Now()
+	result := make(map[int64]*model.PostDetailed, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	keys := make([]string, len(postIDs))
+	for i, id := range postIDs {
+		keys[i] = p.getPostKey(id)
+	}
+	vals, err := p.client.MGet(ctx, keys)
+	if err != nil {
+		p.metrics.RecordCacheOperationDuration("post_mget", time.Since(start))
+		return nil, fmt.Errorf("failed to get posts from cache: %w", err)
+	}
+
+	var corrupt []string
+	for i, val := range vals {
+		if val == nil {
+			continue
+		}
+		var post model.PostDetailed
+		cachedAt, err := decodeEnvelope([]byte(*val), postPayloadVersion, &post)
+		if err != nil {
+			p.log.Warn("Failed to decode cached post",
+				slog.Int64("post_id", postIDs[i]),
+				slog.String("error", err.Error()))
+			corrupt = append(corrupt, keys[i])
+			continue
+		}
+		post.CachedAt = cachedAt
+		result[postIDs[i]] = &post
+	}
+	if len(corrupt) > 0 {
+		p.client.discard(ctx, "post_mget", corrupt...)
+	}
+
+	p.metrics.RecordCacheOperationDuration("post_mget", time.Since(start))
+	p.log.Debug("Post cache batch lookup",
+		slog.Int("requested", len(postIDs)),
+		slog.Int("hits", len(result)))
+	return result, nil
+}
+
 func (p *PostCache) SetPost(ctx context.Context, post *model.PostDetailed) error {
 	start := time.Now()
 	if post == nil {
@@ -70,7 +154,7 @@ func (p *PostCache) SetPost(ctx context.Context, post *model.PostDetailed) error
 
 	key := p.getPostKey(post.Post.ID)
 
-	if err := p.client.Set(ctx, key, post, postCacheTTL); err != nil {
+	if err := p.client.Set(ctx, key, postPayloadVersion, post, p.ttl); err != nil {
 		p.log.Error("Failed to set post cache",
 			slog.Int64("post_id", post.Post.ID),
 			slog.String("error", err.Error()))
@@ -81,7 +165,7 @@ func (p *PostCache) SetPost(ctx context.Context, post *model.PostDetailed) error
 	p.metrics.RecordCacheOperationDuration("post_set", time.Since(start))
 	p.log.Debug("Post cached successfully",
 		slog.Int64("post_id", post.Post.ID),
-		slog.Duration("ttl", postCacheTTL))
+		slog.Duration("ttl", p.ttl))
 	return nil
 }
 
@@ -102,6 +186,198 @@ func (p *PostCache) DeletePost(ctx context.Context, postID int64) error {
 	return nil
 }
 
+// DeletePosts sends one pipeline of chunked DELs. When the pipeline fails, each post is
+// deleted on its own, so one bad chunk does not leave every other post stale.
+func (p *PostCache) DeletePosts(ctx context.Context, postIDs []int64) error {
+	start := time.Now()
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(postIDs))
+	for i, id := range postIDs {
+		keys[i] = p.getPostKey(id)
+	}
+	deleted, err := p.client.DeleteKeys(ctx, keys, deletePostsChunkSize)
+	if err == nil {
+		p.metrics.RecordCacheOperationDuration("post_delete_many", time.Since(start))
+		p.log.Debug("Posts deleted from cache",
+			slog.Int("requested", len(postIDs)),
+			slog.Int64("deleted", deleted))
+		return nil
+	}
+
+	p.log.Warn("Failed to delete posts from cache in a pipeline, deleting one by one",
+		slog.Int("posts", len(postIDs)),
+		slog.String("error", err.Error()))
+	var failed int
+	var firstErr error
+	for i, key := range keys {
+		if ctx.Err() != nil {
+			failed += len(keys) - i
+			firstErr = cmp.Or(firstErr, ctx.Err())
+			break
+		}
+		if err := p.client.Delete(ctx, key); err != nil {
+			failed++
+			firstErr = cmp.Or(firstErr, err)
+		}
+	}
+	p.metrics.RecordCacheOperationDuration("post_delete_many", time.Since(start))
+	if failed > 0 {
+		p.log.Error("Failed to delete posts from cache",
+			slog.Int("posts", len(postIDs)),
+			slog.Int("failed", failed),
+			slog.String("error", firstErr.Error()))
+		return fmt.Errorf("failed to delete %d of %d posts from cache: %w", failed, len(postIDs), firstErr)
+	}
+	return nil
+}
+
+func (p *PostCache) GetTagSuggestions(ctx context.Context, prefix string, limit int) ([]*model.Tag, error) {
+	start := time.Now()
+	key := tagSuggestionsKey(p.keyPrefix, prefix, limit)
+
+	var tags []*model.Tag
+	err := p.client.Get(ctx, key, tagSuggestionsPayloadVersion, &tags)
+	if errors.Is(err, errCorruptEntry) {
+		p.client.discard(ctx, "tag_suggestions_get", key)
+		err = custom_errors.ErrCacheMiss
+	}
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrCacheMiss) {
+			p.log.Debug("Tag suggestions cache miss", slog.String("prefix", prefix))
+			p.metrics.IncrementCacheMisses("tag_suggestions")
+			p.metrics.RecordCacheMissDuration("tag_suggestions_get", time.Since(start))
+			return nil, custom_errors.ErrCacheMiss
+		}
+		p.log.Error("Failed to get tag suggestions from cache",
+			slog.String("prefix", prefix),
+			slog.String("error", err.Error()))
+		p.metrics.RecordCacheOperationDuration("tag_suggestions_get", time.Since(start))
+		return nil, fmt.Errorf("failed to get tag suggestions from cache: %w", err)
+	}
+
+	p.metrics.IncrementCacheHits("tag_suggestions")
+	p.metrics.RecordCacheHitDuration("tag_suggestions_get", time.Since(start))
+	p.log.Debug("Tag suggestions cache hit", slog.String("prefix", prefix))
+	return tags, nil
+}
+
+func (p *PostCache) SetTagSuggestions(ctx context.Context, prefix string, limit int, tags []*model.Tag) error {
+	start := time.Now()
+	if tags == nil {
+		tags = []*model.Tag{}
+	}
+	key := tagSuggestionsKey(p.keyPrefix, prefix, limit)
+
+	if err := p.client.Set(ctx, key, tagSuggestionsPayloadVersion, tags, p.suggestionTTL); err != nil {
+		p.log.Error("Failed to set tag suggestions cache",
+			slog.String("prefix", prefix),
+			slog.String("error", err.Error()))
+		p.metrics.RecordCacheOperationDuration("tag_suggestions_set", time.Since(start))
+		return fmt.Errorf("failed to set tag suggestions cache: %w", err)
+	}
+
+	p.metrics.RecordCacheOperationDuration("tag_suggestions_set", time.Since(start))
+	p.log.Debug("Tag suggestions cached",
+		slog.String("prefix", prefix),
+		slog.Duration("ttl", p.suggestionTTL))
+	return nil
+}
+
+func (p *PostCache) GetTagLatestPosts(ctx context.Context, tagNames []string) (map[string]time.Time, error) {
+	start := time.Now()
+	result := make(map[string]time.Time, len(tagNames))
+	if len(tagNames) == 0 {
+		return result, nil
+	}
+
+	keys := make([]string, len(tagNames))
+	for i, name := range tagNames {
+		keys[i] = tagLatestPostKey(p.keyPrefix, name)
+	}
+	vals, err := p.client.MGet(ctx, keys)
+	if err != nil {
+		p.metrics.RecordCacheOperationDuration("tag_latest_post_mget", time.Since(start))
+		return nil, fmt.Errorf("failed to get tag latest posts from cache: %w", err)
+	}
+
+	var corrupt []string
+	for i, val := range vals {
+		if val == nil {
+			continue
+		}
+		micros, err := strconv.ParseInt(*val, 10, 64)
+		if err != nil {
+			p.log.Warn("Failed to parse cached tag latest post",
+				slog.String("tag", tagNames[i]),
+				slog.String("error", err.Error()))
+			corrupt = append(corrupt, keys[i])
+			continue
+		}
+		result[tagNames[i]] = time.UnixMicro(micros)
+	}
+	if len(corrupt) > 0 {
+		p.client.discard(ctx, "tag_latest_post_mget", corrupt...)
+	}
+
+	// A lookup is a hit only when it can answer a poll on its own.
+	if len(result) == len(tagNames) {
+		p.metrics.IncrementCacheHits("tag_latest_post")
+	} else {
+		p.metrics.IncrementCacheMisses("tag_latest_post")
+	}
+	p.metrics.RecordCacheOperationDuration("tag_latest_post_mget", time.Since(start))
+	p.log.Debug("Tag latest post cache lookup",
+		slog.Int("requested", len(tagNames)),
+		slog.Int("hits", len(result)))
+	return result, nil
+}
+
+func (p *PostCache) AdvanceTagLatestPosts(ctx context.Context, latest map[string]time.Time) error {
+	start := time.Now()
+	if len(latest) == 0 {
+		return nil
+	}
+
+	pipe := p.client.client.Pipeline()
+	for name, at := range latest {
+		advanceTimestampScript.Eval(context.Background(), pipe, []string{tagLatestPostKey(p.keyPrefix, name)},
+			at.UnixMicro(), p.tagLatestTTL.Milliseconds())
+	}
+	execCtx, cancel := p.client.withTimeout(ctx, len(latest))
+	defer cancel()
+	if _, err := pipe.Exec(execCtx); err != nil {
+		err = p.client.timeoutError(execCtx, "tag_latest_post_advance", err)
+		p.log.Error("Failed to cache tag latest posts",
+			slog.Int("tags", len(latest)),
+			slog.String("error", err.Error()))
+		p.metrics.RecordCacheOperationDuration("tag_latest_post_advance", time.Since(start))
+		return fmt.Errorf("failed to cache tag latest posts: %w", err)
+	}
+
+	p.metrics.RecordCacheOperationDuration("tag_latest_post_advance", time.Since(start))
+	p.log.Debug("Tag latest posts cached",
+		slog.Int("tags", len(latest)),
+		slog.Duration("ttl", p.tagLatestTTL))
+	return nil
+}
+
 func (p *PostCache) getPostKey(postID int64) string {
-	return postCacheKeyPrefix + strconv.FormatInt(postID, 10)
+	return postKey(p.keyPrefix, postID)
+}
+
+func postKey(prefix string, postID int64) string {
+	return prefix + postCacheKeyPrefix + strconv.FormatInt(postID, 10)
+}
+
+func tagLatestPostKey(prefix string, tagName string) string {
+	return prefix + tagLatestPostKeyPrefix + tagName
+}
+
+// tagSuggestionsKey puts the limit first: it is a number, so the prefix after it can hold
+// any character without making two keys collide.
+func tagSuggestionsKey(keyPrefix, prefix string, limit int) string {
+	return keyPrefix + tagSuggestionsKeyPrefix + strconv.Itoa(limit) + ":" + prefix
 }
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const rateLimitKeyPrefix = "ratelimit:"
+
+// slidingWindowScript keeps a sorted set of request timestamps (in milliseconds) per key.
+// It returns {1, 0} when the request fits into the window and {0, retry_after_ms} otherwise.
+// The timestamps come from the Redis clock, so replicas with skewed clocks share one window.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local member = ARGV[3]
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+if count < limit then
+	redis.call('ZADD', key, now, member)
+	redis.call('PEXPIRE', key, window)
+	return {1, 0}
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+local retry = window
+if oldest[2] then
+	retry = tonumber(oldest[2]) + window - now
+end
+return {0, retry}
+`)
+
+type RateLimiter struct {
+	client    *Client
+	keyPrefix string
+	log       ports.Logger
+	metrics   ports.MetricsProvider
+	// instance and seq make each request a distinct member of the sorted set, across every
+	// replica sharing the key.
+	instance string
+	seq      atomic.Uint64
+}
+
+func NewRateLimiter(client *Client, cfg config.Cache, log ports.Logger, metrics ports.MetricsProvider) *RateLimiter {
+	return &RateLimiter{
+		client:    client,
+		keyPrefix: cfg.KeyPrefix,
+		log:       log,
+		metrics:   metrics,
+		instance:  newInstanceID(),
+	}
+}
+
+// newInstanceID returns a random id for this process. Should the system random source fail,
+// the start time in nanoseconds stands in for it.
+func newInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (r *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	start := time.Now()
+	member := r.instance + "-" + strconv.FormatUint(r.seq.Add(1), 10)
+
+	ctx, cancel := r.client.withTimeout(ctx, 1)
+	defer cancel()
+
+	res, err := slidingWindowScript.Run(ctx, r.client.client,
+		[]string{r.keyPrefix + rateLimitKeyPrefix + key},
+		window.Milliseconds(), limit, member,
+	).Int64Slice()
+	r.metrics.RecordCacheOperationDuration("rate_limit_check", time.Since(start))
+	if err != nil {
+		err = r.client.timeoutError(ctx, "rate_limit_check", err)
+		r.log.Error("Failed to evaluate rate limit",
+			slog.String("key", key),
+			slog.String("error", err.Error()))
+		return false, 0, fmt.Errorf("failed to evaluate rate limit: %w", err)
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script result: %v", res)
+	}
+
+	allowed := res[0] == 1
+	retryAfter := time.Duration(res[1]) * time.Millisecond
+	r.log.Debug("Rate limit evaluated",
+		slog.String("key", key),
+		slog.Bool("allowed", allowed),
+		slog.Duration("retry_after", retryAfter))
+	return allowed, retryAfter, nil
+}
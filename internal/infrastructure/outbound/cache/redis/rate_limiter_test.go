@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+)
+
+func TestRateLimiter_CountsEveryReplica(t *testing.T) {
+	client, store := newTestClient(t)
+	ctx := context.Background()
+	// Two replicas sharing the key, each at its first request.
+	first := NewRateLimiter(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	second := NewRateLimiter(client, testCacheConfig(), logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	for _, limiter := range []*RateLimiter{first, second} {
+		allowed, _, err := limiter.Allow(ctx, "create:1", 2, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+	allowed, retryAfter, err := first.Allow(ctx, "create:1", 2, time.Minute)
+
+	require.NoError(t, err)
+	assert.False(t, allowed, "both replicas' requests count toward the limit")
+	assert.Equal(t, time.Minute, retryAfter)
+	members := strings.Split(store.values["staging:ratelimit:create:1"], ",")
+	require.Len(t, members, 2)
+	assert.NotEqual(t, members[0], members[1])
+}
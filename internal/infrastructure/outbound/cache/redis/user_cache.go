@@ -6,30 +6,70 @@ import (
 	"fmt"
 	"log/slog"
 	"strconv"
+	"strings"
 	"time"
 
 	model "pinstack-post-service/internal/domain/models"
 	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/config"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 )
 
 const (
 	userCacheKeyPrefix = "user:"
-	userCacheTTL       = 15 * time.Minute
+	// userPostsMetaKeyPrefix namespaces post-related data derived for a user, kept apart from
+	// the user entry so post writes never evict the user.
+	userPostsMetaKeyPrefix = "user_posts_meta:"
+	userBlocksKeyPrefix    = "user_blocks:"
+	// userRecentPostsKeyPrefix namespaces the posts a user just wrote, stored as their ids
+	// separated by commas, newest first.
+	userRecentPostsKeyPrefix = "user_recent_posts:"
+	// maxRecentPosts caps the recent posts kept per user; older ones are dropped first.
+	maxRecentPosts = 20
 )
 
+// addRecentPostScript puts ARGV[1] first in the id list at KEYS[1], keeps at most ARGV[3] ids
+// and restarts its TTL of ARGV[2] milliseconds.
+var addRecentPostScript = redis.NewScript(`
+local ids = {ARGV[1]}
+local current = redis.call('GET', KEYS[1])
+if current then
+	for id in string.gmatch(current, '[^,]+') do
+		if #ids >= tonumber(ARGV[3]) then
+			break
+		end
+		if id ~= ARGV[1] then
+			table.insert(ids, id)
+		end
+	end
+end
+redis.call('SET', KEYS[1], table.concat(ids, ','), 'PX', ARGV[2])
+return #ids
+`)
+
 type UserCache struct {
-	client  *Client
-	log     ports.Logger
-	metrics ports.MetricsProvider
+	client    *Client
+	keyPrefix string
+	ttl       time.Duration
+	countTTL  time.Duration
+	blocksTTL time.Duration
+	maxAge    time.Duration
+	log       ports.Logger
+	metrics   ports.MetricsProvider
 }
 
-func NewUserCache(client *Client, log ports.Logger, metrics ports.MetricsProvider) *UserCache {
+func NewUserCache(client *Client, cfg config.Cache, log ports.Logger, metrics ports.MetricsProvider) *UserCache {
 	return &UserCache{
-		client:  client,
-		log:     log,
-		metrics: metrics,
+		client:    client,
+		keyPrefix: cfg.KeyPrefix,
+		ttl:       cfg.UserTTL,
+		countTTL:  cfg.PostCountTTL,
+		blocksTTL: cfg.BlockedUsersTTL,
+		maxAge:    cfg.AuthorMaxAge,
+		log:       log,
+		metrics:   metrics,
 	}
 }
 
@@ -38,11 +78,15 @@ func (u *UserCache) GetUser(ctx context.Context, userID int64) (*model.User, err
 	key := u.getUserKey(userID)
 
 	var user model.User
-	err := u.client.Get(ctx, key, &user)
+	err := u.client.Get(ctx, key, userPayloadVersion, &user)
+	if errors.Is(err, errCorruptEntry) {
+		u.client.discard(ctx, "user_get", key)
+		err = custom_errors.ErrCacheMiss
+	}
 	if err != nil {
 		if errors.Is(err, custom_errors.ErrCacheMiss) {
 			u.log.Debug("User cache miss", slog.Int64("user_id", userID))
-			u.metrics.IncrementCacheMisses()
+			u.metrics.IncrementCacheMisses("user")
 			u.metrics.RecordCacheMissDuration("user_get", time.Since(start))
 			return nil, custom_errors.ErrCacheMiss
 		}
@@ -53,12 +97,50 @@ func (u *UserCache) GetUser(ctx context.Context, userID int64) (*model.User, err
 		return nil, fmt.Errorf("failed to get user from cache: %w", err)
 	}
 
-	u.metrics.IncrementCacheHits()
+	u.metrics.IncrementCacheHits("user")
 	u.metrics.RecordCacheHitDuration("user_get", time.Since(start))
 	u.log.Debug("User cache hit", slog.Int64("user_id", userID))
 	return &user, nil
 }
 
+// GetFreshUser is GetUser for entries cached at most cache.author_max_age ago; older ones
+// read as ErrCacheMiss. The age is what the entry has used of cache.user_ttl, so it is off
+// by the difference for entries written before a change of the TTL.
+func (u *UserCache) GetFreshUser(ctx context.Context, userID int64) (*model.User, error) {
+	start := time.Now()
+	key := u.getUserKey(userID)
+
+	var user model.User
+	meta, err := u.client.GetWithMeta(ctx, key, userPayloadVersion, &user)
+	remaining := meta.ttl
+	if errors.Is(err, errCorruptEntry) {
+		u.client.discard(ctx, "user_get_fresh", key)
+		err = custom_errors.ErrCacheMiss
+	}
+	if err == nil && (remaining < 0 || u.ttl-remaining > u.maxAge) {
+		u.log.Debug("Cached user is too old to verify an author",
+			slog.Int64("user_id", userID),
+			slog.Duration("age", u.ttl-remaining))
+		err = custom_errors.ErrCacheMiss
+	}
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrCacheMiss) {
+			u.metrics.IncrementCacheMisses("user")
+			u.metrics.RecordCacheMissDuration("user_get_fresh", time.Since(start))
+			return nil, custom_errors.ErrCacheMiss
+		}
+		u.log.Error("Failed to get user from cache",
+			slog.Int64("user_id", userID),
+			slog.String("error", err.Error()))
+		u.metrics.RecordCacheOperationDuration("user_get_fresh", time.Since(start))
+		return nil, fmt.Errorf("failed to get user from cache: %w", err)
+	}
+
+	u.metrics.IncrementCacheHits("user")
+	u.metrics.RecordCacheHitDuration("user_get_fresh", time.Since(start))
+	return &user, nil
+}
+
 func (u *UserCache) SetUser(ctx context.Context, user *model.User) error {
 	start := time.Now()
 	if user == nil {
@@ -67,7 +149,7 @@ func (u *UserCache) SetUser(ctx context.Context, user *model.User) error {
 
 	key := u.getUserKey(user.ID)
 
-	if err := u.client.Set(ctx, key, user, userCacheTTL); err != nil {
+	if err := u.client.Set(ctx, key, userPayloadVersion, user, u.ttl); err != nil {
 		u.log.Error("Failed to set user cache",
 			slog.Int64("user_id", user.ID),
 			slog.String("error", err.Error()))
@@ -78,7 +160,7 @@ func (u *UserCache) SetUser(ctx context.Context, user *model.User) error {
 	u.metrics.RecordCacheOperationDuration("user_set", time.Since(start))
 	u.log.Debug("User cached successfully",
 		slog.Int64("user_id", user.ID),
-		slog.Duration("ttl", userCacheTTL))
+		slog.Duration("ttl", u.ttl))
 	return nil
 }
 
@@ -99,6 +181,184 @@ func (u *UserCache) DeleteUser(ctx context.Context, userID int64) error {
 	return nil
 }
 
+func (u *UserCache) InvalidateUserPostsMeta(ctx context.Context, userID int64) error {
+	start := time.Now()
+	key := userPostsMetaKey(u.keyPrefix, userID)
+
+	if err := u.client.Delete(ctx, key); err != nil {
+		u.log.Error("Failed to invalidate user posts metadata",
+			slog.Int64("user_id", userID),
+			slog.String("error", err.Error()))
+		u.metrics.RecordCacheOperationDuration("user_posts_meta_delete", time.Since(start))
+		return fmt.Errorf("failed to invalidate user posts metadata: %w", err)
+	}
+
+	u.metrics.RecordCacheOperationDuration("user_posts_meta_delete", time.Since(start))
+	u.log.Debug("User posts metadata invalidated", slog.Int64("user_id", userID))
+	return nil
+}
+
+func (u *UserCache) GetUserPostCount(ctx context.Context, userID int64) (int64, error) {
+	start := time.Now()
+	key := userPostsMetaKey(u.keyPrefix, userID)
+
+	count, err := u.client.GetCount(ctx, key)
+	if errors.Is(err, errCorruptEntry) {
+		u.client.discard(ctx, "user_post_count_get", key)
+		err = custom_errors.ErrCacheMiss
+	}
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrCacheMiss) {
+			u.log.Debug("User post count cache miss", slog.Int64("user_id", userID))
+			u.metrics.IncrementCacheMisses("user_posts_meta")
+			u.metrics.RecordCacheMissDuration("user_post_count_get", time.Since(start))
+			return 0, custom_errors.ErrCacheMiss
+		}
+		u.log.Error("Failed to get user post count from cache",
+			slog.Int64("user_id", userID),
+			slog.String("error", err.Error()))
+		u.metrics.RecordCacheOperationDuration("user_post_count_get", time.Since(start))
+		return 0, fmt.Errorf("failed to get user post count from cache: %w", err)
+	}
+
+	u.metrics.IncrementCacheHits("user_posts_meta")
+	u.metrics.RecordCacheHitDuration("user_post_count_get", time.Since(start))
+	u.log.Debug("User post count cache hit", slog.Int64("user_id", userID))
+	return count, nil
+}
+
+func (u *UserCache) SetUserPostCount(ctx context.Context, userID int64, count int64) error {
+	start := time.Now()
+	key := userPostsMetaKey(u.keyPrefix, userID)
+
+	if err := u.client.SetCount(ctx, key, count, u.countTTL); err != nil {
+		u.log.Error("Failed to set user post count cache",
+			slog.Int64("user_id", userID),
+			slog.String("error", err.Error()))
+		u.metrics.RecordCacheOperationDuration("user_post_count_set", time.Since(start))
+		return fmt.Errorf("failed to set user post count cache: %w", err)
+	}
+
+	u.metrics.RecordCacheOperationDuration("user_post_count_set", time.Since(start))
+	u.log.Debug("User post count cached",
+		slog.Int64("user_id", userID),
+		slog.Duration("ttl", u.countTTL))
+	return nil
+}
+
+func (u *UserCache) GetBlockedUsers(ctx context.Context, userID int64) ([]int64, error) {
+	start := time.Now()
+	key := userBlocksKey(u.keyPrefix, userID)
+
+	var blockedIDs []int64
+	err := u.client.Get(ctx, key, blockedUsersPayloadVersion, &blockedIDs)
+	if errors.Is(err, errCorruptEntry) {
+		u.client.discard(ctx, "user_blocks_get", key)
+		err = custom_errors.ErrCacheMiss
+	}
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrCacheMiss) {
+			u.log.Debug("Blocked users cache miss", slog.Int64("user_id", userID))
+			u.metrics.IncrementCacheMisses("user_blocks")
+			u.metrics.RecordCacheMissDuration("user_blocks_get", time.Since(start))
+			return nil, custom_errors.ErrCacheMiss
+		}
+		u.log.Error("Failed to get blocked users from cache",
+			slog.Int64("user_id", userID),
+			slog.String("error", err.Error()))
+		u.metrics.RecordCacheOperationDuration("user_blocks_get", time.Since(start))
+		return nil, fmt.Errorf("failed to get blocked users from cache: %w", err)
+	}
+
+	u.metrics.IncrementCacheHits("user_blocks")
+	u.metrics.RecordCacheHitDuration("user_blocks_get", time.Since(start))
+	u.log.Debug("Blocked users cache hit", slog.Int64("user_id", userID))
+	return blockedIDs, nil
+}
+
+func (u *UserCache) SetBlockedUsers(ctx context.Context, userID int64, blockedIDs []int64) error {
+	start := time.Now()
+	if blockedIDs == nil {
+		blockedIDs = []int64{}
+	}
+	key := userBlocksKey(u.keyPrefix, userID)
+
+	if err := u.client.Set(ctx, key, blockedUsersPayloadVersion, blockedIDs, u.blocksTTL); err != nil {
+		u.log.Error("Failed to set blocked users cache",
+			slog.Int64("user_id", userID),
+			slog.String("error", err.Error()))
+		u.metrics.RecordCacheOperationDuration("user_blocks_set", time.Since(start))
+		return fmt.Errorf("failed to set blocked users cache: %w", err)
+	}
+
+	u.metrics.RecordCacheOperationDuration("user_blocks_set", time.Since(start))
+	u.log.Debug("Blocked users cached",
+		slog.Int64("user_id", userID),
+		slog.Int("count", len(blockedIDs)),
+		slog.Duration("ttl", u.blocksTTL))
+	return nil
+}
+
+func (u *UserCache) GetRecentPosts(ctx context.Context, userID int64) ([]int64, error) {
+	start := time.Now()
+	key := userRecentPostsKey(u.keyPrefix, userID)
+
+	vals, err := u.client.MGet(ctx, []string{key})
+	if err != nil {
+		u.metrics.RecordCacheOperationDuration("user_recent_posts_get", time.Since(start))
+		return nil, fmt.Errorf("failed to get recent posts from cache: %w", err)
+	}
+	var postIDs []int64
+	if vals[0] != nil {
+		postIDs, err = parseRecentPosts(*vals[0])
+		if err != nil {
+			u.log.Warn("Failed to parse cached recent posts",
+				slog.Int64("user_id", userID),
+				slog.String("error", err.Error()))
+			u.client.discard(ctx, "user_recent_posts_get", key)
+		}
+	}
+	if len(postIDs) == 0 {
+		u.metrics.IncrementCacheMisses("user_recent_posts")
+		u.metrics.RecordCacheMissDuration("user_recent_posts_get", time.Since(start))
+		return nil, custom_errors.ErrCacheMiss
+	}
+
+	u.metrics.IncrementCacheHits("user_recent_posts")
+	u.metrics.RecordCacheHitDuration("user_recent_posts_get", time.Since(start))
+	u.log.Debug("Recent posts cache hit", slog.Int64("user_id", userID), slog.Int("posts", len(postIDs)))
+	return postIDs, nil
+}
+
+func parseRecentPosts(val string) ([]int64, error) {
+	fields := strings.Split(val, ",")
+	postIDs := make([]int64, len(fields))
+	for i, field := range fields {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		postIDs[i] = id
+	}
+	return postIDs, nil
+}
+
 func (u *UserCache) getUserKey(userID int64) string {
-	return userCacheKeyPrefix + strconv.FormatInt(userID, 10)
+	return userKey(u.keyPrefix, userID)
+}
+
+func userKey(prefix string, userID int64) string {
+	return prefix + userCacheKeyPrefix + strconv.FormatInt(userID, 10)
+}
+
+func userPostsMetaKey(prefix string, userID int64) string {
+	return prefix + userPostsMetaKeyPrefix + strconv.FormatInt(userID, 10)
+}
+
+func userRecentPostsKey(prefix string, userID int64) string {
+	return prefix + userRecentPostsKeyPrefix + strconv.FormatInt(userID, 10)
+}
+
+func userBlocksKey(prefix string, userID int64) string {
+	return prefix + userBlocksKeyPrefix + strconv.FormatInt(userID, 10)
 }
@@ -0,0 +1,194 @@
+// Package swap wraps the caches, the rate limiter and the event publisher so that the
+// implementation behind them can be replaced while the service runs. cmd/server starts with
+// the noop implementations when Redis is unreachable and swaps in the Redis ones once it
+// answers.
+package swap
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/domain/ports/output/cache"
+	"pinstack-post-service/internal/domain/ports/output/events"
+	"pinstack-post-service/internal/domain/ports/output/rate_limiter"
+)
+
+// slot holds the current implementation. A call that loaded the old one finishes with it.
+type slot[T any] struct {
+	current atomic.Pointer[T]
+}
+
+func (s *slot[T]) load() T {
+	return *s.current.Load()
+}
+
+func (s *slot[T]) store(v T) {
+	s.current.Store(&v)
+}
+
+type PostCache struct {
+	slot[cache.PostCache]
+}
+
+func NewPostCache(initial cache.PostCache) *PostCache {
+	c := &PostCache{}
+	c.store(initial)
+	return c
+}
+
+// Swap makes every later call go to next.
+func (c *PostCache) Swap(next cache.PostCache) {
+	c.store(next)
+}
+
+func (c *PostCache) GetPost(ctx context.Context, postID int64) (*model.PostDetailed, error) {
+	return c.load().GetPost(ctx, postID)
+}
+
+func (c *PostCache) GetPosts(ctx context.Context, postIDs []int64) (map[int64]*model.PostDetailed, error) {
+	return c.load().GetPosts(ctx, postIDs)
+}
+
+func (c *PostCache) SetPost(ctx context.Context, post *model.PostDetailed) error {
+	return c.load().SetPost(ctx, post)
+}
+
+func (c *PostCache) DeletePost(ctx context.Context, postID int64) error {
+	return c.load().DeletePost(ctx, postID)
+}
+
+func (c *PostCache) DeletePosts(ctx context.Context, postIDs []int64) error {
+	return c.load().DeletePosts(ctx, postIDs)
+}
+
+func (c *PostCache) GetTagSuggestions(ctx context.Context, prefix string, limit int) ([]*model.Tag, error) {
+	return c.load().GetTagSuggestions(ctx, prefix, limit)
+}
+
+func (c *PostCache) SetTagSuggestions(ctx context.Context, prefix string, limit int, tags []*model.Tag) error {
+	return c.load().SetTagSuggestions(ctx, prefix, limit, tags)
+}
+
+func (c *PostCache) GetTagLatestPosts(ctx context.Context, tagNames []string) (map[string]time.Time, error) {
+	return c.load().GetTagLatestPosts(ctx, tagNames)
+}
+
+func (c *PostCache) AdvanceTagLatestPosts(ctx context.Context, latest map[string]time.Time) error {
+	return c.load().AdvanceTagLatestPosts(ctx, latest)
+}
+
+type UserCache struct {
+	slot[cache.UserCache]
+}
+
+func NewUserCache(initial cache.UserCache) *UserCache {
+	c := &UserCache{}
+	c.store(initial)
+	return c
+}
+
+// Swap makes every later call go to next.
+func (c *UserCache) Swap(next cache.UserCache) {
+	c.store(next)
+}
+
+func (c *UserCache) GetUser(ctx context.Context, userID int64) (*model.User, error) {
+	return c.load().GetUser(ctx, userID)
+}
+
+func (c *UserCache) GetFreshUser(ctx context.Context, userID int64) (*model.User, error) {
+	return c.load().GetFreshUser(ctx, userID)
+}
+
+func (c *UserCache) SetUser(ctx context.Context, user *model.User) error {
+	return c.load().SetUser(ctx, user)
+}
+
+func (c *UserCache) DeleteUser(ctx context.Context, userID int64) error {
+	return c.load().DeleteUser(ctx, userID)
+}
+
+func (c *UserCache) InvalidateUserPostsMeta(ctx context.Context, userID int64) error {
+	return c.load().InvalidateUserPostsMeta(ctx, userID)
+}
+
+func (c *UserCache) GetUserPostCount(ctx context.Context, userID int64) (int64, error) {
+	return c.load().GetUserPostCount(ctx, userID)
+}
+
+func (c *UserCache) SetUserPostCount(ctx context.Context, userID int64, count int64) error {
+	return c.load().SetUserPostCount(ctx, userID, count)
+}
+
+func (c *UserCache) GetBlockedUsers(ctx context.Context, userID int64) ([]int64, error) {
+	return c.load().GetBlockedUsers(ctx, userID)
+}
+
+func (c *UserCache) SetBlockedUsers(ctx context.Context, userID int64, blockedIDs []int64) error {
+	return c.load().SetBlockedUsers(ctx, userID, blockedIDs)
+}
+
+func (c *UserCache) GetRecentPosts(ctx context.Context, userID int64) ([]int64, error) {
+	return c.load().GetRecentPosts(ctx, userID)
+}
+
+// Batcher hands out batches of the current implementation. A batch keeps the implementation
+// it was created with.
+type Batcher struct {
+	slot[cache.CacheBatcher]
+}
+
+func NewBatcher(initial cache.CacheBatcher) *Batcher {
+	b := &Batcher{}
+	b.store(initial)
+	return b
+}
+
+// Swap makes every later batch come from next.
+func (b *Batcher) Swap(next cache.CacheBatcher) {
+	b.store(next)
+}
+
+func (b *Batcher) NewBatch() cache.CacheBatch {
+	return b.load().NewBatch()
+}
+
+type RateLimiter struct {
+	slot[rate_limiter.RateLimiter]
+}
+
+func NewRateLimiter(initial rate_limiter.RateLimiter) *RateLimiter {
+	l := &RateLimiter{}
+	l.store(initial)
+	return l
+}
+
+// Swap makes every later call go to next.
+func (l *RateLimiter) Swap(next rate_limiter.RateLimiter) {
+	l.store(next)
+}
+
+func (l *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	return l.load().Allow(ctx, key, limit, window)
+}
+
+type EventPublisher struct {
+	slot[events.Publisher]
+}
+
+func NewEventPublisher(initial events.Publisher) *EventPublisher {
+	p := &EventPublisher{}
+	p.store(initial)
+	return p
+}
+
+// Swap makes every later call go to next.
+func (p *EventPublisher) Swap(next events.Publisher) {
+	p.store(next)
+}
+
+func (p *EventPublisher) Publish(ctx context.Context, event model.PostEvent) error {
+	return p.load().Publish(ctx, event)
+}
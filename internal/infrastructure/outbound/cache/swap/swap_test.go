@@ -0,0 +1,113 @@
+package swap_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/domain/ports/output/cache"
+	"pinstack-post-service/internal/domain/ports/output/events"
+	"pinstack-post-service/internal/domain/ports/output/rate_limiter"
+	"pinstack-post-service/internal/infrastructure/outbound/cache/noop"
+	"pinstack-post-service/internal/infrastructure/outbound/cache/swap"
+	cache_mock "pinstack-post-service/mocks/cache"
+	rate_limiter_mock "pinstack-post-service/mocks/rate_limiter"
+)
+
+var (
+	_ cache.PostCache    = (*swap.PostCache)(nil)
+	_ cache.UserCache    = (*swap.UserCache)(nil)
+	_ cache.CacheBatcher = (*swap.Batcher)(nil)
+
+	_ rate_limiter.RateLimiter = (*swap.RateLimiter)(nil)
+	_ events.Publisher         = (*swap.EventPublisher)(nil)
+)
+
+func TestPostCache_Swap(t *testing.T) {
+	ctx := context.Background()
+	postCache := swap.NewPostCache(noop.NewPostCache())
+	_, err := postCache.GetPost(ctx, 1)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	next := cache_mock.NewPostCache(t)
+	cached := &model.PostDetailed{Post: &model.Post{ID: 1}}
+	next.On("GetPost", mock.Anything, int64(1)).Return(cached, nil)
+	postCache.Swap(next)
+
+	got, err := postCache.GetPost(ctx, 1)
+	require.NoError(t, err)
+	assert.Same(t, cached, got)
+}
+
+func TestUserCache_Swap(t *testing.T) {
+	ctx := context.Background()
+	userCache := swap.NewUserCache(noop.NewUserCache())
+	_, err := userCache.GetUserPostCount(ctx, 1)
+	assert.ErrorIs(t, err, custom_errors.ErrCacheMiss)
+
+	next := cache_mock.NewUserCache(t)
+	next.On("GetUserPostCount", mock.Anything, int64(1)).Return(int64(3), nil)
+	userCache.Swap(next)
+
+	count, err := userCache.GetUserPostCount(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestBatcher_BatchKeepsItsImplementation(t *testing.T) {
+	batcher := swap.NewBatcher(noop.NewBatcher())
+	before := batcher.NewBatch()
+
+	next := cache_mock.NewCacheBatcher(t)
+	batch := cache_mock.NewCacheBatch(t)
+	next.On("NewBatch").Return(batch)
+	batcher.Swap(next)
+
+	assert.Same(t, batch, batcher.NewBatch())
+	before.DeletePost(1)
+	assert.NoError(t, before.Exec(context.Background()), "a batch created before the swap is not moved")
+}
+
+func TestRateLimiter_Swap(t *testing.T) {
+	ctx := context.Background()
+	limiter := swap.NewRateLimiter(noop.NewRateLimiter())
+	allowed, _, err := limiter.Allow(ctx, "create:1", 1, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	next := rate_limiter_mock.NewRateLimiter(t)
+	next.On("Allow", mock.Anything, "create:1", 1, time.Minute).Return(false, time.Second, nil)
+	limiter.Swap(next)
+
+	allowed, retryAfter, err := limiter.Allow(ctx, "create:1", 1, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Second, retryAfter)
+}
+
+func TestPostCache_SwapWhileInUse(t *testing.T) {
+	ctx := context.Background()
+	postCache := swap.NewPostCache(noop.NewPostCache())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = postCache.SetPost(ctx, &model.PostDetailed{Post: &model.Post{ID: 1}})
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		postCache.Swap(noop.NewPostCache())
+	}
+	wg.Wait()
+}
@@ -0,0 +1,53 @@
+package user_client
+
+import (
+	"context"
+	"log/slog"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/domain/ports/output/cache"
+	user_port "pinstack-post-service/internal/domain/ports/output/user"
+)
+
+// CachedClient answers GetBlockedUsers from the user cache for cache.blocked_users_ttl, so a
+// requester paging through a feed asks the user service once. Users are looked up as before:
+// the cache decorator already caches them.
+type CachedClient struct {
+	client user_port.Client
+	cache  cache.UserCache
+	log    ports.Logger
+}
+
+func NewCachedClient(client user_port.Client, userCache cache.UserCache, log ports.Logger) *CachedClient {
+	return &CachedClient{client: client, cache: userCache, log: log}
+}
+
+func (c *CachedClient) GetUser(ctx context.Context, id int64) (*model.User, error) {
+	return c.client.GetUser(ctx, id)
+}
+
+func (c *CachedClient) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	return c.client.GetUserByUsername(ctx, username)
+}
+
+func (c *CachedClient) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return c.client.GetUserByEmail(ctx, email)
+}
+
+// GetBlockedUsers caches only answers of the user service; a failure is returned uncached, so
+// the next call asks again.
+func (c *CachedClient) GetBlockedUsers(ctx context.Context, userID int64) ([]int64, error) {
+	if blockedIDs, err := c.cache.GetBlockedUsers(ctx, userID); err == nil {
+		return blockedIDs, nil
+	}
+
+	blockedIDs, err := c.client.GetBlockedUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cache.SetBlockedUsers(ctx, userID, blockedIDs); err != nil {
+		c.log.Warn("Failed to cache blocked users", slog.Int64("user_id", userID), slog.String("error", err.Error()))
+	}
+	return blockedIDs, nil
+}
@@ -0,0 +1,23 @@
+package user_client
+
+import (
+	"pinstack-post-service/internal/infrastructure/config"
+	"pinstack-post-service/internal/infrastructure/tlsconfig"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// DialOptions returns the transport credentials for the user service connection: TLS
+// verified against the configured CA, or plaintext when cfg.TLS.Insecure is set.
+func DialOptions(cfg config.UserService) ([]grpc.DialOption, error) {
+	if cfg.TLS.Insecure {
+		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, nil
+	}
+	tlsConfig, err := tlsconfig.Client(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}, nil
+}
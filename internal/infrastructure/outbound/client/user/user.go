@@ -4,7 +4,7 @@ import (
 	"context"
 	"log/slog"
 	model "pinstack-post-service/internal/domain/models"
-	"pinstack-post-service/internal/infrastructure/logger"
+	ports "pinstack-post-service/internal/domain/ports/output"
 
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 
@@ -16,10 +16,10 @@ import (
 
 type UserClient struct {
 	client pb.UserServiceClient
-	log    *logger.Logger
+	log    ports.Logger
 }
 
-func NewUserClient(conn *grpc.ClientConn, log *logger.Logger) *UserClient {
+func NewUserClient(conn *grpc.ClientConn, log ports.Logger) *UserClient {
 	return &UserClient{
 		client: pb.NewUserServiceClient(conn),
 		log:    log,
@@ -73,3 +73,11 @@ func (u *UserClient) GetUserByEmail(ctx context.Context, email string) (*model.U
 	u.log.Info("Successfully got user by email", slog.String("email", email))
 	return model.UserFromProto(resp), nil
 }
+
+// GetBlockedUsers is answered by the user service's GetBlockedUsers RPC, which the pinned
+// proto definitions (v0.1.22) do not have yet. Until they are bumped it reports the user
+// service as unavailable, and ListPosts lists without hiding blocked authors.
+func (u *UserClient) GetBlockedUsers(ctx context.Context, userID int64) ([]int64, error) {
+	u.log.Debug("GetBlockedUsers is not in the user service proto yet", slog.Int64("user_id", userID))
+	return nil, custom_errors.ErrExternalServiceError
+}
@@ -23,6 +23,14 @@ var (
 		[]string{"method", "status"},
 	)
 
+	PanicsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "panics_total",
+			Help: "Total number of panics recovered while handling gRPC requests",
+		},
+		[]string{"method"},
+	)
+
 	DatabaseQueriesTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "database_queries_total",
@@ -40,18 +48,86 @@ var (
 		[]string{"query_type"},
 	)
 
+	TransactionRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "database_transaction_retries_total",
+			Help: "Total number of transactions re-run after a serialization failure, deadlock or lock timeout",
+		},
+		[]string{"operation"},
+	)
+
+	LockWaitDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "database_lock_wait_seconds",
+			Help:    "Time spent waiting for database locks in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"lock"},
+	)
+
+	OperationTimeoutsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "operation_timeouts_total",
+			Help: "Total number of database and cache operations cut off by their per-operation timeout",
+		},
+		[]string{"component", "operation"},
+	)
+
+	CallerAbortsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "operation_caller_aborts_total",
+			Help: "Total number of database and cache operations cut off because the caller's deadline passed or it canceled the request",
+		},
+		[]string{"component", "operation", "reason"},
+	)
+
+	CacheLookupsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_lookups_total",
+			Help: "Total number of cache lookups by cache and result (hit or miss)",
+		},
+		[]string{"cache", "result"},
+	)
+
+	// CacheHitsTotal and CacheMissesTotal are the sums of CacheLookupsTotal over all caches,
+	// kept for the dashboards of other services until the next release.
 	CacheHitsTotal = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "cache_hits_total",
-			Help: "Total number of cache hits",
+			Help: "Deprecated: use cache_lookups_total{result=\"hit\"}; removed in the next release. Total number of cache hits",
 		},
 	)
 
 	CacheMissesTotal = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "cache_misses_total",
-			Help: "Total number of cache misses",
+			Help: "Deprecated: use cache_lookups_total{result=\"miss\"}; removed in the next release. Total number of cache misses",
+		},
+	)
+
+	CacheKeys = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cache_keys",
+			Help: "Number of keys per cache found by the last sample; a lower bound when the sample hit its limit",
 		},
+		[]string{"cache"},
+	)
+
+	BudgetStageDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "request_budget_stage_duration_seconds",
+			Help:    "Duration of the stages of a request run within their share of the request budget",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"operation", "stage"},
+	)
+
+	BudgetExhaustedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "request_budget_exhausted_total",
+			Help: "Total number of request stages skipped because the request budget was spent",
+		},
+		[]string{"operation", "stage"},
 	)
 
 	CacheOperationDuration = promauto.NewHistogramVec(
@@ -81,12 +157,160 @@ var (
 		[]string{"operation"},
 	)
 
-	PostOperationsTotal = promauto.NewCounterVec(
+	CacheEntryAge = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cache_served_entry_age_seconds",
+			Help:    "Age of the cache entries served to callers in seconds",
+			Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+		},
+		[]string{"cache"},
+	)
+
+	CoalescedRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "post_operations_total",
-			Help: "Total number of post operations processed",
+			Name: "cache_coalesced_requests_total",
+			Help: "Total number of cache misses served by another in-flight request",
+		},
+		[]string{"operation"},
+	)
+
+	AuthorVerificationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "author_verifications_total",
+			Help: "Total number of post author checks by result (hit: cached user, miss: user service)",
+		},
+		[]string{"result"},
+	)
+
+	AuthorSnapshotReadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "author_snapshot_reads_total",
+			Help: "Total number of post authors built on read by result (hit: post snapshot, miss: user service)",
+		},
+		[]string{"result"},
+	)
+
+	AuthorSnapshotsRefreshedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "author_snapshots_refreshed_total",
+			Help: "Total number of posts whose author snapshot was rewritten by the refresher",
+		},
+	)
+
+	CacheCorruptionTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_corruption_total",
+			Help: "Total number of cached values discarded because they could not be decoded or had an unknown version",
+		},
+		[]string{"operation"},
+	)
+
+	CacheInvalidationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_invalidations_total",
+			Help: "Total number of posts handed to the background cache invalidation queue by outcome (queued, dropped, failed)",
+		},
+		[]string{"outcome"},
+	)
+
+	CacheWarmedEntries = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "cache_warmed_entries",
+			Help: "Number of posts written to the cache by the last startup warm-up",
+		},
+	)
+
+	CacheAvailable = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "cache_available",
+			Help: "Cache availability as seen by the service (1 = available, 0 = disabled or circuit open)",
+		},
+	)
+
+	PostOperationOutcomesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "post_operation_outcomes_total",
+			Help: "Total number of post service operations by outcome (ok, not_found, forbidden, validation, db_error or error)",
+		},
+		[]string{"operation", "outcome"},
+	)
+
+	PostOperationDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "post_operation_duration_seconds",
+			Help:    "Duration of post service operations in seconds, cache hits excluded",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"operation"},
+	)
+
+	PostReadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "post_reads_total",
+			Help: "Total number of post reads by whether the cache served them",
+		},
+		[]string{"operation", "cached"},
+	)
+
+	ExportedPostsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "posts_exported_total",
+			Help: "Total number of posts streamed by author exports",
+		},
+	)
+
+	BulkCreatedPostsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "posts_bulk_created_total",
+			Help: "Total number of posts of bulk imports by result (created or failed)",
+		},
+		[]string{"result"},
+	)
+
+	BulkCreateThroughput = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "post_bulk_create_items_per_second",
+			Help:    "Posts per second gone through by each bulk import call",
+			Buckets: []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000},
+		},
+	)
+
+	ArchivedPostsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "posts_archived_total",
+			Help: "Total number of posts moved to the archive tables",
+		},
+	)
+
+	OrphansFoundTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "post_orphans_found_total",
+			Help: "Total number of post_media and posts_tags rows found without their post or tag",
+		},
+		[]string{"kind"},
+	)
+
+	OrphansRemovedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "post_orphans_removed_total",
+			Help: "Total number of post_media and posts_tags rows removed for lacking their post or tag",
+		},
+		[]string{"kind"},
+	)
+
+	ScheduledPostsPublishedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "scheduled_posts_published_total",
+			Help: "Total number of scheduled posts published by the scheduler",
+		},
+	)
+
+	ArchiveRunDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "post_archive_run_duration_seconds",
+			Help:    "Duration of archive runs in seconds",
+			Buckets: []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900},
 		},
-		[]string{"operation", "success"},
 	)
 
 	TagOperationsTotal = promauto.NewCounterVec(
@@ -105,6 +329,30 @@ var (
 		[]string{"operation", "success"},
 	)
 
+	RateLimitChecksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limit_checks_total",
+			Help: "Total number of rate limit checks by result (allowed, rejected, error)",
+		},
+		[]string{"operation", "result"},
+	)
+
+	UserServiceFallbacksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "user_service_fallbacks_total",
+			Help: "Total number of requests served without a user service answer, by operation",
+		},
+		[]string{"operation"},
+	)
+
+	EventPublishesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "event_publishes_total",
+			Help: "Total number of post events published, by event type and success",
+		},
+		[]string{"event", "success"},
+	)
+
 	ActiveConnections = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "active_connections",
@@ -112,6 +360,30 @@ var (
 		},
 	)
 
+	PoolAcquiredConns = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "connection_pool_acquired_conns",
+			Help: "Number of connections of a pool currently in use",
+		},
+		[]string{"pool"},
+	)
+
+	PoolIdleConns = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "connection_pool_idle_conns",
+			Help: "Number of open connections of a pool not in use",
+		},
+		[]string{"pool"},
+	)
+
+	PoolTotalConns = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "connection_pool_total_conns",
+			Help: "Total number of open connections of a pool",
+		},
+		[]string{"pool"},
+	)
+
 	ServiceHealth = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "service_health",
@@ -0,0 +1,63 @@
+package prometheus
+
+import (
+	"context"
+	"time"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+)
+
+// PoolStats is a snapshot of the connections of one pool.
+type PoolStats struct {
+	Acquired int
+	Idle     int
+	Total    int
+}
+
+// PoolStatsCollector copies the connection counts of the registered pools into the
+// connection_pool_* gauges once per interval.
+type PoolStatsCollector struct {
+	metrics  ports.MetricsProvider
+	interval time.Duration
+	pools    []namedPool
+}
+
+type namedPool struct {
+	name  string
+	stats func() PoolStats
+}
+
+func NewPoolStatsCollector(metrics ports.MetricsProvider, interval time.Duration) *PoolStatsCollector {
+	return &PoolStatsCollector{
+		metrics:  metrics,
+		interval: interval,
+	}
+}
+
+// Add registers a pool under name, the value of the pool label. Pools must be added before Run.
+func (c *PoolStatsCollector) Add(name string, stats func() PoolStats) {
+	c.pools = append(c.pools, namedPool{name: name, stats: stats})
+}
+
+// Collect reads every pool once.
+func (c *PoolStatsCollector) Collect() {
+	for _, pool := range c.pools {
+		stats := pool.stats()
+		c.metrics.SetConnectionPoolStats(pool.name, stats.Acquired, stats.Idle, stats.Total)
+	}
+}
+
+// Run collects right away and then once per interval until ctx is done.
+func (c *PoolStatsCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.Collect()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
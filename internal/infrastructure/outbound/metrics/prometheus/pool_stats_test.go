@@ -0,0 +1,72 @@
+package prometheus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+)
+
+// poolMetrics records the last stats set for each pool.
+type poolMetrics struct {
+	ports.MetricsProvider
+	mu    sync.Mutex
+	pools map[string]PoolStats
+	sets  int
+}
+
+func (m *poolMetrics) SetConnectionPoolStats(pool string, acquired, idle, total int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pools[pool] = PoolStats{Acquired: acquired, Idle: idle, Total: total}
+	m.sets++
+}
+
+func (m *poolMetrics) setCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sets
+}
+
+func TestPoolStatsCollector_Collect(t *testing.T) {
+	metrics := &poolMetrics{pools: map[string]PoolStats{}}
+	collector := NewPoolStatsCollector(metrics, time.Minute)
+	postgres := PoolStats{Acquired: 3, Idle: 2, Total: 5}
+	collector.Add("postgres", func() PoolStats { return postgres })
+	collector.Add("redis", func() PoolStats { return PoolStats{Idle: 4, Total: 4} })
+
+	collector.Collect()
+	assert.Equal(t, map[string]PoolStats{
+		"postgres": {Acquired: 3, Idle: 2, Total: 5},
+		"redis":    {Idle: 4, Total: 4},
+	}, metrics.pools)
+
+	postgres = PoolStats{Acquired: 1, Idle: 4, Total: 5}
+	collector.Collect()
+	assert.Equal(t, postgres, metrics.pools["postgres"], "every collection reads the pool again")
+}
+
+func TestPoolStatsCollector_Run(t *testing.T) {
+	metrics := &poolMetrics{pools: map[string]PoolStats{}}
+	collector := NewPoolStatsCollector(metrics, 10*time.Millisecond)
+	collector.Add("postgres", func() PoolStats { return PoolStats{Total: 1} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		collector.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return metrics.setCount() >= 3 }, time.Second, 5*time.Millisecond, "collects once per interval")
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop with its context")
+	}
+}
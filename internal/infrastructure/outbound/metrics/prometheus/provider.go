@@ -21,6 +21,10 @@ func (p *PrometheusMetricsProvider) RecordGRPCRequestDuration(method, status str
 	GRPCRequestDuration.WithLabelValues(method, status).Observe(duration.Seconds())
 }
 
+func (p *PrometheusMetricsProvider) IncrementPanics(method string) {
+	PanicsTotal.WithLabelValues(method).Inc()
+}
+
 func (p *PrometheusMetricsProvider) IncrementDatabaseQueries(queryType string, success bool) {
 	DatabaseQueriesTotal.WithLabelValues(queryType, strconv.FormatBool(success)).Inc()
 }
@@ -29,14 +33,40 @@ func (p *PrometheusMetricsProvider) RecordDatabaseQueryDuration(queryType string
 	DatabaseQueryDuration.WithLabelValues(queryType).Observe(duration.Seconds())
 }
 
-func (p *PrometheusMetricsProvider) IncrementCacheHits() {
+func (p *PrometheusMetricsProvider) IncrementTransactionRetries(operation string) {
+	TransactionRetriesTotal.WithLabelValues(operation).Inc()
+}
+
+func (p *PrometheusMetricsProvider) RecordLockWaitDuration(lock string, duration time.Duration) {
+	LockWaitDuration.WithLabelValues(lock).Observe(duration.Seconds())
+}
+
+func (p *PrometheusMetricsProvider) IncrementOperationTimeouts(component, operation string) {
+	OperationTimeoutsTotal.WithLabelValues(component, operation).Inc()
+}
+
+func (p *PrometheusMetricsProvider) IncrementCallerAborts(component, operation string, deadlineExceeded bool) {
+	reason := "canceled"
+	if deadlineExceeded {
+		reason = "deadline_exceeded"
+	}
+	CallerAbortsTotal.WithLabelValues(component, operation, reason).Inc()
+}
+
+func (p *PrometheusMetricsProvider) IncrementCacheHits(cache string) {
+	CacheLookupsTotal.WithLabelValues(cache, "hit").Inc()
 	CacheHitsTotal.Inc()
 }
 
-func (p *PrometheusMetricsProvider) IncrementCacheMisses() {
+func (p *PrometheusMetricsProvider) IncrementCacheMisses(cache string) {
+	CacheLookupsTotal.WithLabelValues(cache, "miss").Inc()
 	CacheMissesTotal.Inc()
 }
 
+func (p *PrometheusMetricsProvider) SetCacheKeys(cache string, count int) {
+	CacheKeys.WithLabelValues(cache).Set(float64(count))
+}
+
 func (p *PrometheusMetricsProvider) RecordCacheOperationDuration(operation string, duration time.Duration) {
 	CacheOperationDuration.WithLabelValues(operation).Observe(duration.Seconds())
 }
@@ -45,12 +75,100 @@ func (p *PrometheusMetricsProvider) RecordCacheHitDuration(operation string, dur
 	CacheHitDuration.WithLabelValues(operation).Observe(duration.Seconds())
 }
 
+func (p *PrometheusMetricsProvider) RecordBudgetStageDuration(operation, stage string, duration time.Duration) {
+	BudgetStageDuration.WithLabelValues(operation, stage).Observe(duration.Seconds())
+}
+
+func (p *PrometheusMetricsProvider) IncrementBudgetExhausted(operation, stage string) {
+	BudgetExhaustedTotal.WithLabelValues(operation, stage).Inc()
+}
+
 func (p *PrometheusMetricsProvider) RecordCacheMissDuration(operation string, duration time.Duration) {
 	CacheMissDuration.WithLabelValues(operation).Observe(duration.Seconds())
 }
 
-func (p *PrometheusMetricsProvider) IncrementPostOperations(operation string, success bool) {
-	PostOperationsTotal.WithLabelValues(operation, strconv.FormatBool(success)).Inc()
+func (p *PrometheusMetricsProvider) RecordCacheEntryAge(cache string, age time.Duration) {
+	CacheEntryAge.WithLabelValues(cache).Observe(age.Seconds())
+}
+
+func (p *PrometheusMetricsProvider) IncrementCoalescedRequests(operation string) {
+	CoalescedRequestsTotal.WithLabelValues(operation).Inc()
+}
+
+func (p *PrometheusMetricsProvider) IncrementAuthorVerifications(result string) {
+	AuthorVerificationsTotal.WithLabelValues(result).Inc()
+}
+
+func (p *PrometheusMetricsProvider) IncrementAuthorSnapshotReads(result string) {
+	AuthorSnapshotReadsTotal.WithLabelValues(result).Inc()
+}
+
+func (p *PrometheusMetricsProvider) AddAuthorSnapshotsRefreshed(count int) {
+	AuthorSnapshotsRefreshedTotal.Add(float64(count))
+}
+
+func (p *PrometheusMetricsProvider) IncrementCacheCorruption(operation string) {
+	CacheCorruptionTotal.WithLabelValues(operation).Inc()
+}
+
+func (p *PrometheusMetricsProvider) AddCacheInvalidations(outcome string, count int) {
+	CacheInvalidationsTotal.WithLabelValues(outcome).Add(float64(count))
+}
+
+func (p *PrometheusMetricsProvider) SetCacheWarmedEntries(count int) {
+	CacheWarmedEntries.Set(float64(count))
+}
+
+func (p *PrometheusMetricsProvider) SetCacheAvailable(available bool) {
+	if available {
+		CacheAvailable.Set(1)
+	} else {
+		CacheAvailable.Set(0)
+	}
+}
+
+func (p *PrometheusMetricsProvider) IncrementPostOperation(operation, outcome string) {
+	PostOperationOutcomesTotal.WithLabelValues(operation, outcome).Inc()
+}
+
+func (p *PrometheusMetricsProvider) RecordPostOperationDuration(operation string, duration time.Duration) {
+	PostOperationDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+func (p *PrometheusMetricsProvider) IncrementPostReads(operation string, cached bool) {
+	PostReadsTotal.WithLabelValues(operation, strconv.FormatBool(cached)).Inc()
+}
+
+func (p *PrometheusMetricsProvider) AddExportedPosts(count int) {
+	ExportedPostsTotal.Add(float64(count))
+}
+
+func (p *PrometheusMetricsProvider) RecordBulkCreate(created, failed int, duration time.Duration) {
+	BulkCreatedPostsTotal.WithLabelValues("created").Add(float64(created))
+	BulkCreatedPostsTotal.WithLabelValues("failed").Add(float64(failed))
+	if seconds := duration.Seconds(); seconds > 0 {
+		BulkCreateThroughput.Observe(float64(created+failed) / seconds)
+	}
+}
+
+func (p *PrometheusMetricsProvider) AddArchivedPosts(count int) {
+	ArchivedPostsTotal.Add(float64(count))
+}
+
+func (p *PrometheusMetricsProvider) AddOrphansFound(kind string, count int) {
+	OrphansFoundTotal.WithLabelValues(kind).Add(float64(count))
+}
+
+func (p *PrometheusMetricsProvider) AddOrphansRemoved(kind string, count int) {
+	OrphansRemovedTotal.WithLabelValues(kind).Add(float64(count))
+}
+
+func (p *PrometheusMetricsProvider) AddScheduledPostsPublished(count int) {
+	ScheduledPostsPublishedTotal.Add(float64(count))
+}
+
+func (p *PrometheusMetricsProvider) RecordArchiveRunDuration(duration time.Duration) {
+	ArchiveRunDuration.Observe(duration.Seconds())
 }
 
 func (p *PrometheusMetricsProvider) IncrementTagOperations(operation string, success bool) {
@@ -65,6 +183,24 @@ func (p *PrometheusMetricsProvider) SetActiveConnections(count int) {
 	ActiveConnections.Set(float64(count))
 }
 
+func (p *PrometheusMetricsProvider) SetConnectionPoolStats(pool string, acquired, idle, total int) {
+	PoolAcquiredConns.WithLabelValues(pool).Set(float64(acquired))
+	PoolIdleConns.WithLabelValues(pool).Set(float64(idle))
+	PoolTotalConns.WithLabelValues(pool).Set(float64(total))
+}
+
+func (p *PrometheusMetricsProvider) IncrementRateLimitChecks(operation, result string) {
+	RateLimitChecksTotal.WithLabelValues(operation, result).Inc()
+}
+
+func (p *PrometheusMetricsProvider) IncrementUserServiceFallbacks(operation string) {
+	UserServiceFallbacksTotal.WithLabelValues(operation).Inc()
+}
+
+func (p *PrometheusMetricsProvider) IncrementEventPublishes(event string, success bool) {
+	EventPublishesTotal.WithLabelValues(event, strconv.FormatBool(success)).Inc()
+}
+
 func (p *PrometheusMetricsProvider) SetServiceHealth(healthy bool) {
 	if healthy {
 		ServiceHealth.Set(1)
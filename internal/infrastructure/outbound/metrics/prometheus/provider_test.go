@@ -0,0 +1,71 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// value reads the current value of a counter or gauge.
+func value(t *testing.T, m prometheus.Metric) float64 {
+	t.Helper()
+	var out dto.Metric
+	require.NoError(t, m.Write(&out))
+	if out.Counter != nil {
+		return out.Counter.GetValue()
+	}
+	return out.Gauge.GetValue()
+}
+
+func TestPrometheusMetricsProvider_CacheLookups(t *testing.T) {
+	provider := NewPrometheusMetricsProvider()
+	postHits := value(t, CacheLookupsTotal.WithLabelValues("post", "hit"))
+	postMisses := value(t, CacheLookupsTotal.WithLabelValues("post", "miss"))
+	userHits := value(t, CacheLookupsTotal.WithLabelValues("user", "hit"))
+	userMisses := value(t, CacheLookupsTotal.WithLabelValues("user", "miss"))
+	hits, misses := value(t, CacheHitsTotal), value(t, CacheMissesTotal)
+
+	provider.IncrementCacheHits("post")
+	provider.IncrementCacheHits("post")
+	provider.IncrementCacheMisses("post")
+	provider.IncrementCacheMisses("user")
+
+	assert.Equal(t, postHits+2, value(t, CacheLookupsTotal.WithLabelValues("post", "hit")))
+	assert.Equal(t, postMisses+1, value(t, CacheLookupsTotal.WithLabelValues("post", "miss")))
+	assert.Equal(t, userHits, value(t, CacheLookupsTotal.WithLabelValues("user", "hit")))
+	assert.Equal(t, userMisses+1, value(t, CacheLookupsTotal.WithLabelValues("user", "miss")))
+	assert.Equal(t, hits+2, value(t, CacheHitsTotal), "the deprecated series sums every cache")
+	assert.Equal(t, misses+2, value(t, CacheMissesTotal), "the deprecated series sums every cache")
+}
+
+func TestPrometheusMetricsProvider_SetCacheKeys(t *testing.T) {
+	provider := NewPrometheusMetricsProvider()
+
+	provider.SetCacheKeys("post", 12)
+	provider.SetCacheKeys("user", 3)
+	provider.SetCacheKeys("post", 7)
+
+	assert.Equal(t, float64(7), value(t, CacheKeys.WithLabelValues("post")))
+	assert.Equal(t, float64(3), value(t, CacheKeys.WithLabelValues("user")))
+}
+
+func TestPrometheusMetricsProvider_PostOperations(t *testing.T) {
+	provider := NewPrometheusMetricsProvider()
+	ok := value(t, PostOperationOutcomesTotal.WithLabelValues("update", "ok"))
+	forbidden := value(t, PostOperationOutcomesTotal.WithLabelValues("update", "forbidden"))
+	cached := value(t, PostReadsTotal.WithLabelValues("get", "true"))
+	uncached := value(t, PostReadsTotal.WithLabelValues("get", "false"))
+
+	provider.IncrementPostOperation("update", "ok")
+	provider.IncrementPostOperation("update", "ok")
+	provider.IncrementPostOperation("update", "forbidden")
+	provider.IncrementPostReads("get", true)
+
+	assert.Equal(t, ok+2, value(t, PostOperationOutcomesTotal.WithLabelValues("update", "ok")))
+	assert.Equal(t, forbidden+1, value(t, PostOperationOutcomesTotal.WithLabelValues("update", "forbidden")))
+	assert.Equal(t, cached+1, value(t, PostReadsTotal.WithLabelValues("get", "true")))
+	assert.Equal(t, uncached, value(t, PostReadsTotal.WithLabelValues("get", "false")))
+}
@@ -0,0 +1,236 @@
+package archive_repository_postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+type ArchiveRepository struct {
+	log     ports.Logger
+	db      db.PgDB
+	metrics ports.MetricsProvider
+}
+
+func NewArchiveRepository(db db.PgDB, log ports.Logger, metrics ports.MetricsProvider) *ArchiveRepository {
+	return &ArchiveRepository{db: db, log: log, metrics: metrics}
+}
+
+// archivedPostColumns are every column of a post, which posts_archive has too, so an archived
+// post reads back as it was. scanArchivedPost reads them in this order.
+var archivedPostColumns = strings.Join([]string{
+	"id", "author_id", "title", "content", "status", "visibility", "version", "edit_count",
+	"created_at", "updated_at", "published_at", "scheduled_at", "lang", "source", "pinned_at",
+	"moderation_status", "rejection_reason", "author_username", "author_avatar_url", "author_snapshot_at",
+}, ", ")
+
+func scanArchivedPost(row pgx.Row) (*model.Post, error) {
+	post := &model.Post{}
+	err := row.Scan(&post.ID, &post.AuthorID, &post.Title, &post.Content, &post.Status, &post.Visibility,
+		&post.Version, &post.EditCount, &post.CreatedAt, &post.UpdatedAt, &post.PublishedAt, &post.ScheduledAt,
+		&post.Language, &post.Source, &post.PinnedAt, &post.ModerationStatus, &post.RejectionReason,
+		&post.AuthorUsername, &post.AuthorAvatarURL, &post.AuthorSnapshotAt)
+	if err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// archiveStatements copy a batch of posts and their children into the archive tables, then
+// delete the posts; post_media, posts_tags and post_revisions rows go with them via ON DELETE
+// CASCADE.
+var archiveStatements = []string{
+	"INSERT INTO posts_archive (" + archivedPostColumns + ") SELECT " + archivedPostColumns + " FROM posts WHERE id = ANY(@ids)",
+	`INSERT INTO post_media_archive (id, post_id, url, type, position, width, height, size_bytes, alt_text, created_at)
+		SELECT id, post_id, url, type, position, width, height, size_bytes, alt_text, created_at
+		FROM post_media WHERE post_id = ANY(@ids)`,
+	`INSERT INTO posts_tags_archive (post_id, tag_id)
+		SELECT post_id, tag_id FROM posts_tags WHERE post_id = ANY(@ids)`,
+	`INSERT INTO post_revisions_archive (id, post_id, revision, title, content, editor_id, created_at)
+		SELECT id, post_id, revision, title, content, editor_id, created_at
+		FROM post_revisions WHERE post_id = ANY(@ids)`,
+	`DELETE FROM posts WHERE id = ANY(@ids)`,
+}
+
+func (a *ArchiveRepository) ArchiveOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (archived int, err error) {
+	defer db.ObserveQuery(a.metrics, a.log, "archive_older_than", time.Now(), &err, slog.Time("cutoff", cutoff), slog.Int("batch_size", batchSize))
+
+	// Posts locked by a concurrent update are skipped and picked up by a later batch. Scheduled
+	// posts are left to the scheduler.
+	var ids []int64
+	err = a.db.QueryRow(ctx, `
+		SELECT COALESCE(array_agg(id), '{}') FROM (
+			SELECT id FROM posts WHERE created_at < @cutoff AND status <> 'scheduled'
+			ORDER BY created_at, id LIMIT @batch_size
+			FOR UPDATE SKIP LOCKED
+		) oldest`,
+		pgx.NamedArgs{"cutoff": cutoff, "batch_size": batchSize},
+	).Scan(&ids)
+	if err != nil {
+		a.log.Error("Error selecting posts to archive", slog.Time("cutoff", cutoff), slog.String("error", err.Error()))
+		return 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	batch := &pgx.Batch{}
+	args := pgx.NamedArgs{"ids": ids}
+	for _, stmt := range archiveStatements {
+		batch.Queue(stmt, args)
+	}
+
+	results := a.db.SendBatch(ctx, batch)
+	defer func(results pgx.BatchResults) {
+		if err := results.Close(); err != nil {
+			a.log.Error("Failed to close batch result in ArchiveOlderThan", slog.String("error", err.Error()))
+		}
+	}(results)
+
+	for i := range archiveStatements {
+		tag, execErr := results.Exec()
+		if execErr != nil {
+			a.log.Error("Archiving posts failed", slog.Int("statement", i), slog.Int("post_count", len(ids)), slog.String("error", execErr.Error()))
+			return 0, db.WithCause(custom_errors.ErrDatabaseQuery, execErr)
+		}
+		archived = int(tag.RowsAffected())
+	}
+
+	a.log.Debug("Archived posts", slog.Int("count", archived), slog.Time("cutoff", cutoff))
+	return archived, nil
+}
+
+func (a *ArchiveRepository) GetByID(ctx context.Context, id int64) (result *model.PostDetailed, err error) {
+	defer db.ObserveQuery(a.metrics, a.log, "archive_get_by_id", time.Now(), &err, slog.Int64("post_id", id))
+
+	post, err := scanArchivedPost(a.db.QueryRow(ctx, "SELECT "+archivedPostColumns+" FROM posts_archive WHERE id = @id",
+		pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, custom_errors.ErrPostNotFound
+		}
+		a.log.Error("Error getting archived post", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	media, err := a.media(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+	tags, err := a.tags(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+	return &model.PostDetailed{Post: post, Media: media[id], Tags: tags[id]}, nil
+}
+
+func (a *ArchiveRepository) GetByAuthorAfter(ctx context.Context, authorID int64, afterID int64, limit int) (result []*model.PostDetailed, err error) {
+	defer db.ObserveQuery(a.metrics, a.log, "archive_get_by_author_after", time.Now(), &err,
+		slog.Int64("author_id", authorID), slog.Int64("after_id", afterID), slog.Int("limit", limit))
+
+	rows, err := a.db.Query(ctx, "SELECT "+archivedPostColumns+" FROM posts_archive WHERE author_id = @author_id AND id > @after_id ORDER BY id LIMIT @limit",
+		pgx.NamedArgs{"author_id": authorID, "after_id": afterID, "limit": limit})
+	if err != nil {
+		a.log.Error("Error getting page of archived posts by author", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	defer rows.Close()
+
+	posts := make([]*model.PostDetailed, 0)
+	ids := make([]int64, 0)
+	for rows.Next() {
+		post, err := scanArchivedPost(rows)
+		if err != nil {
+			return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		posts = append(posts, &model.PostDetailed{Post: post})
+		ids = append(ids, post.ID)
+	}
+	if err := rows.Err(); err != nil {
+		a.log.Error("Error iterating archived posts by author", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	// The connection is free for the media and tag queries only once the rows are closed.
+	rows.Close()
+	if len(posts) == 0 {
+		return posts, nil
+	}
+
+	media, err := a.media(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := a.tags(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		post.Media = media[post.Post.ID]
+		post.Tags = tags[post.Post.ID]
+	}
+	return posts, nil
+}
+
+// media loads the archived media of postIDs, keyed by post and ordered by position.
+func (a *ArchiveRepository) media(ctx context.Context, postIDs []int64) (map[int64][]*model.PostMedia, error) {
+	rows, err := a.db.Query(ctx, `
+		SELECT id, post_id, url, type, position, width, height, size_bytes, alt_text, created_at
+		FROM post_media_archive WHERE post_id = ANY(@post_ids) ORDER BY post_id, position`,
+		pgx.NamedArgs{"post_ids": postIDs})
+	if err != nil {
+		a.log.Error("Error getting archived media", slog.Int("post_count", len(postIDs)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	defer rows.Close()
+
+	media := make(map[int64][]*model.PostMedia, len(postIDs))
+	for rows.Next() {
+		var m model.PostMedia
+		if err := rows.Scan(&m.ID, &m.PostID, &m.URL, &m.Type, &m.Position, &m.Width, &m.Height, &m.SizeBytes, &m.AltText, &m.CreatedAt); err != nil {
+			return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		media[m.PostID] = append(media[m.PostID], &m)
+	}
+	if err := rows.Err(); err != nil {
+		a.log.Error("Error iterating archived media", slog.Int("post_count", len(postIDs)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return media, nil
+}
+
+// tags loads the tags of the archived postIDs, keyed by post and ordered by name.
+func (a *ArchiveRepository) tags(ctx context.Context, postIDs []int64) (map[int64][]*model.Tag, error) {
+	rows, err := a.db.Query(ctx, `
+		SELECT pt.post_id, t.id, t.name FROM tags t
+		INNER JOIN posts_tags_archive pt ON pt.tag_id = t.id
+		WHERE pt.post_id = ANY(@post_ids) ORDER BY pt.post_id, t.name`,
+		pgx.NamedArgs{"post_ids": postIDs})
+	if err != nil {
+		a.log.Error("Error getting archived tags", slog.Int("post_count", len(postIDs)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	defer rows.Close()
+
+	tags := make(map[int64][]*model.Tag, len(postIDs))
+	for rows.Next() {
+		var postID int64
+		var tag model.Tag
+		if err := rows.Scan(&postID, &tag.ID, &tag.Name); err != nil {
+			return nil, db.WithCause(custom_errors.ErrTagScanFailed, err)
+		}
+		tags[postID] = append(tags[postID], &tag)
+	}
+	if err := rows.Err(); err != nil {
+		a.log.Error("Error iterating archived tags", slog.Int("post_count", len(postIDs)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	return tags, nil
+}
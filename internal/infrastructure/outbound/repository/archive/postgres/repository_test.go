@@ -0,0 +1,169 @@
+package archive_repository_postgres_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	archive_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/archive/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+// fakeDB returns ids from the candidate query and replays batchErrs for the queued statements;
+// any other call panics via the nil embedded PgDB.
+type fakeDB struct {
+	db.PgDB
+	ids       []int64
+	batchErrs []error
+	queued    *pgx.Batch
+	batch     *fakeBatchResults
+}
+
+func (f *fakeDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return idsRow(f.ids)
+}
+
+func (f *fakeDB) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	f.queued = b
+	f.batch = &fakeBatchResults{errs: f.batchErrs, rows: int64(len(f.ids))}
+	return f.batch
+}
+
+type idsRow []int64
+
+func (r idsRow) Scan(dest ...any) error {
+	*dest[0].(*[]int64) = r
+	return nil
+}
+
+type fakeBatchResults struct {
+	pgx.BatchResults
+	errs  []error
+	rows  int64
+	calls int
+}
+
+func (f *fakeBatchResults) Exec() (pgconn.CommandTag, error) {
+	var err error
+	if f.calls < len(f.errs) {
+		err = f.errs[f.calls]
+	}
+	f.calls++
+	return pgconn.NewCommandTag("DELETE " + strconv.FormatInt(f.rows, 10)), err
+}
+
+func (f *fakeBatchResults) Close() error {
+	return nil
+}
+
+func newRepo(fdb *fakeDB) *archive_repository_postgres.ArchiveRepository {
+	return archive_repository_postgres.NewArchiveRepository(fdb, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+}
+
+func TestArchiveRepository_ArchiveOlderThan(t *testing.T) {
+	fdb := &fakeDB{ids: []int64{3, 4, 9}}
+
+	archived, err := newRepo(fdb).ArchiveOlderThan(context.Background(), time.Now(), 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, archived)
+	require.NotNil(t, fdb.queued)
+	require.Len(t, fdb.queued.QueuedQueries, 5)
+
+	// Children are copied before the delete cascades them away, all in the caller's transaction.
+	targets := []string{"INSERT INTO posts_archive", "INSERT INTO post_media_archive", "INSERT INTO posts_tags_archive",
+		"INSERT INTO post_revisions_archive", "DELETE FROM posts"}
+	for i, q := range fdb.queued.QueuedQueries {
+		assert.Contains(t, q.SQL, targets[i])
+		require.Len(t, q.Arguments, 1)
+		assert.Equal(t, []int64{3, 4, 9}, q.Arguments[0].(pgx.NamedArgs)["ids"])
+	}
+	assert.Contains(t, fdb.queued.QueuedQueries[0].SQL, "edit_count", "the edit count leaves with the post")
+	assert.Equal(t, 5, fdb.batch.calls)
+}
+
+func TestArchiveRepository_ArchiveOlderThan_NothingToArchive(t *testing.T) {
+	fdb := &fakeDB{}
+
+	archived, err := newRepo(fdb).ArchiveOlderThan(context.Background(), time.Now(), 100)
+
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+	assert.Nil(t, fdb.queued, "no statements are sent when no post is old enough")
+}
+
+func TestArchiveRepository_ArchiveOlderThan_StatementFails(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchErrs []error
+		wantCalls int
+	}{
+		{"copying posts fails", []error{errors.New("disk full")}, 1},
+		{"copying media fails", []error{nil, errors.New("disk full")}, 2},
+		{"copying tags fails", []error{nil, nil, &pgconn.PgError{Code: "23505"}}, 3},
+		{"copying revisions fails", []error{nil, nil, nil, errors.New("disk full")}, 4},
+		{"delete fails", []error{nil, nil, nil, nil, &pgconn.PgError{Code: "40P01"}}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fdb := &fakeDB{ids: []int64{1}, batchErrs: tt.batchErrs}
+
+			archived, err := newRepo(fdb).ArchiveOlderThan(context.Background(), time.Now(), 100)
+
+			assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+			assert.Zero(t, archived)
+			assert.Equal(t, tt.wantCalls, fdb.batch.calls, "statements after a failure are not reported as done")
+		})
+	}
+}
+
+// columnsDB records the statement of GetByID and how many columns its scan reads, then
+// reports the post missing.
+type columnsDB struct {
+	db.PgDB
+	sql     string
+	scanned int
+}
+
+func (c *columnsDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	c.sql = sql
+	return scanCounter{c}
+}
+
+type scanCounter struct{ db *columnsDB }
+
+func (s scanCounter) Scan(dest ...any) error {
+	s.db.scanned = len(dest)
+	return pgx.ErrNoRows
+}
+
+func TestArchiveRepository_KeepsEveryPostColumn(t *testing.T) {
+	cdb := &columnsDB{}
+	repo := archive_repository_postgres.NewArchiveRepository(cdb, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	_, err := repo.GetByID(context.Background(), 7)
+
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	selected := strings.Split(strings.TrimSpace(strings.TrimPrefix(strings.Split(cdb.sql, " FROM ")[0], "SELECT")), ", ")
+	assert.Len(t, selected, cdb.scanned, "every selected column is scanned")
+	for _, column := range []string{"version", "edit_count", "scheduled_at", "pinned_at", "author_username", "author_avatar_url", "author_snapshot_at"} {
+		assert.Contains(t, selected, column)
+	}
+
+	fdb := &fakeDB{ids: []int64{1}}
+	_, err = newRepo(fdb).ArchiveOlderThan(context.Background(), time.Now(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO posts_archive ("+strings.Join(selected, ", ")+") SELECT "+strings.Join(selected, ", ")+" FROM posts WHERE id = ANY(@ids)",
+		fdb.queued.QueuedQueries[0].SQL, "the archive copies what it reads back")
+}
@@ -0,0 +1,70 @@
+// Package conformance holds the behaviour every implementation of the post, tag and media
+// repository ports must share, written once as test suites. The memory implementations run
+// them in the regular test run and the postgres ones under the integration build tag, so a
+// contract the two disagree on fails in both places instead of only in production.
+//
+// Each suite runs its cases as subtests and calls Setup once per subtest for empty storage.
+package conformance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	media_repository "pinstack-post-service/internal/domain/ports/output/media"
+	post_repository "pinstack-post-service/internal/domain/ports/output/post"
+	tag_repository "pinstack-post-service/internal/domain/ports/output/tag"
+)
+
+// Repositories is one set of repositories sharing storage: posts created through Posts can be
+// tagged through Tags and given media through Media.
+type Repositories struct {
+	Posts post_repository.Repository
+	Tags  tag_repository.Repository
+	Media media_repository.Repository
+}
+
+// Setup returns repositories over empty storage. It registers its own cleanup with t.
+type Setup func(t *testing.T) Repositories
+
+// timestampGap separates posts whose order or time filters a case checks. Postgres keeps
+// timestamps to the microsecond, so posts created back to back may otherwise tie.
+const timestampGap = 2 * time.Millisecond
+
+func createPost(t *testing.T, repos Repositories, post *model.Post) *model.Post {
+	t.Helper()
+	created, err := repos.Posts.Create(context.Background(), post)
+	require.NoError(t, err)
+	time.Sleep(timestampGap)
+	return created
+}
+
+func createTags(t *testing.T, repos Repositories, names ...string) map[string]*model.Tag {
+	t.Helper()
+	tags, err := repos.Tags.CreateMany(context.Background(), names)
+	require.NoError(t, err)
+	byName := make(map[string]*model.Tag, len(tags))
+	for _, tag := range tags {
+		byName[tag.Name] = tag
+	}
+	return byName
+}
+
+func postIDs(posts []*model.Post) []int64 {
+	ids := make([]int64, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	return ids
+}
+
+func tagNames(tags []*model.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
@@ -0,0 +1,232 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/testsupport"
+)
+
+// MediaRepository checks an implementation of media_repository.Repository.
+func MediaRepository(t *testing.T, setup Setup) {
+	ctx := context.Background()
+	image := func(url string, position int32) *model.PostMedia {
+		return &model.PostMedia{URL: url, Type: model.MediaTypeImage, Position: position}
+	}
+	urls := func(media []*model.PostMedia) []string {
+		urls := make([]string, len(media))
+		for i, m := range media {
+			urls[i] = m.URL
+		}
+		return urls
+	}
+
+	t.Run("attach orders by position", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		alt := "Alt"
+
+		require.NoError(t, repos.Media.Attach(ctx, post.ID, []*model.PostMedia{
+			image("https://example.com/2.jpg", 2),
+			{URL: "https://example.com/1.jpg", Type: model.MediaTypeVideo, Position: 1, Width: testsupport.Ptr(int32(640)), AltText: &alt},
+		}))
+		require.NoError(t, repos.Media.Attach(ctx, post.ID, []*model.PostMedia{image("https://example.com/3.jpg", 3)}))
+
+		media, err := repos.Media.GetByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/1.jpg", "https://example.com/2.jpg", "https://example.com/3.jpg"}, urls(media))
+		first := media[0]
+		assert.NotZero(t, first.ID)
+		assert.Equal(t, post.ID, first.PostID)
+		assert.Equal(t, model.MediaTypeVideo, first.Type)
+		assert.Equal(t, int32(640), *first.Width)
+		assert.Nil(t, first.Height)
+		assert.Equal(t, "Alt", *first.AltText)
+	})
+
+	t.Run("attach to a missing post", func(t *testing.T) {
+		repos := setup(t)
+
+		err := repos.Media.Attach(ctx, 999, []*model.PostMedia{image("https://example.com/1.jpg", 1)})
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	})
+
+	t.Run("attach duplicates attaches nothing", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		require.NoError(t, repos.Media.Attach(ctx, post.ID, []*model.PostMedia{image("https://example.com/1.jpg", 1)}))
+
+		for name, media := range map[string][]*model.PostMedia{
+			"position taken": {image("https://example.com/2.jpg", 2), image("https://example.com/3.jpg", 1)},
+			"url taken":      {image("https://example.com/2.jpg", 2), image("https://example.com/1.jpg", 3)},
+			"repeated url":   {image("https://example.com/2.jpg", 2), image("https://example.com/2.jpg", 3)},
+		} {
+			assert.ErrorIs(t, repos.Media.Attach(ctx, post.ID, media), model.ErrMediaDuplicate, name)
+		}
+
+		media, err := repos.Media.GetByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/1.jpg"}, urls(media))
+	})
+
+	t.Run("attach an unknown type attaches nothing", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		media := []*model.PostMedia{image("https://example.com/1.jpg", 1), {URL: "https://example.com/2.jpg", Type: "img", Position: 2}}
+
+		assert.ErrorIs(t, repos.Media.Attach(ctx, post.ID, media), custom_errors.ErrInvalidInput)
+		for _, md := range media {
+			md.PostID = post.ID
+		}
+		assert.ErrorIs(t, repos.Media.AttachMany(ctx, media), custom_errors.ErrInvalidInput)
+
+		got, err := repos.Media.GetByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("attach many", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		other := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Other"})
+		withPost := func(postID int64, media *model.PostMedia) *model.PostMedia {
+			media.PostID = postID
+			return media
+		}
+
+		require.NoError(t, repos.Media.AttachMany(ctx, []*model.PostMedia{
+			withPost(post.ID, image("https://example.com/2.jpg", 2)),
+			withPost(other.ID, image("https://example.com/3.jpg", 1)),
+			withPost(post.ID, image("https://example.com/1.jpg", 1)),
+		}))
+		require.NoError(t, repos.Media.AttachMany(ctx, nil))
+
+		media, err := repos.Media.GetByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/1.jpg", "https://example.com/2.jpg"}, urls(media))
+		media, err = repos.Media.GetByPost(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/3.jpg"}, urls(media))
+
+		assert.ErrorIs(t, repos.Media.AttachMany(ctx, []*model.PostMedia{
+			withPost(other.ID, image("https://example.com/4.jpg", 2)),
+			withPost(post.ID, image("https://example.com/1.jpg", 3)),
+		}), model.ErrMediaDuplicate)
+		assert.ErrorIs(t, repos.Media.AttachMany(ctx, []*model.PostMedia{
+			withPost(999, image("https://example.com/5.jpg", 1)),
+		}), custom_errors.ErrPostNotFound)
+
+		media, err = repos.Media.GetByPost(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/3.jpg"}, urls(media), "a failed attach attaches nothing")
+	})
+
+	t.Run("reorder", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		other := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Other"})
+		require.NoError(t, repos.Media.Attach(ctx, post.ID, []*model.PostMedia{
+			image("https://example.com/1.jpg", 1), image("https://example.com/2.jpg", 2), image("https://example.com/3.jpg", 3),
+		}))
+		require.NoError(t, repos.Media.Attach(ctx, other.ID, []*model.PostMedia{image("https://example.com/other.jpg", 1)}))
+		media, err := repos.Media.GetByPost(ctx, post.ID)
+		require.NoError(t, err)
+		otherMedia, err := repos.Media.GetByPost(ctx, other.ID)
+		require.NoError(t, err)
+
+		// The first two swap places; the other post's media is skipped.
+		require.NoError(t, repos.Media.Reorder(ctx, post.ID, map[int64]int{media[0].ID: 2, media[1].ID: 1, otherMedia[0].ID: 5}))
+
+		got, err := repos.Media.GetByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/2.jpg", "https://example.com/1.jpg", "https://example.com/3.jpg"}, urls(got))
+		gotOther, err := repos.Media.GetByPost(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), gotOther[0].Position)
+
+		err = repos.Media.Reorder(ctx, post.ID, map[int64]int{media[0].ID: 3})
+		assert.ErrorIs(t, err, model.ErrMediaDuplicate)
+	})
+
+	t.Run("detach ignores unknown ids", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		require.NoError(t, repos.Media.Attach(ctx, post.ID, []*model.PostMedia{
+			image("https://example.com/1.jpg", 1), image("https://example.com/2.jpg", 2),
+		}))
+		media, err := repos.Media.GetByPost(ctx, post.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, repos.Media.Detach(ctx, []int64{media[0].ID, 999}))
+
+		got, err := repos.Media.GetByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/2.jpg"}, urls(got))
+	})
+
+	t.Run("get by post without media", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+
+		for _, id := range []int64{post.ID, 999} {
+			media, err := repos.Media.GetByPost(ctx, id)
+			require.NoError(t, err)
+			assert.NotNil(t, media)
+			assert.Empty(t, media)
+		}
+	})
+
+	t.Run("get page by post", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		require.NoError(t, repos.Media.Attach(ctx, post.ID, []*model.PostMedia{
+			image("https://example.com/3.jpg", 3), image("https://example.com/1.jpg", 1), image("https://example.com/2.jpg", 2),
+		}))
+
+		page, total, err := repos.Media.GetPageByPost(ctx, post.ID, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/1.jpg", "https://example.com/2.jpg"}, urls(page))
+		assert.Equal(t, 3, total)
+		assert.Equal(t, post.ID, page[0].PostID)
+
+		page, total, err = repos.Media.GetPageByPost(ctx, post.ID, 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/3.jpg"}, urls(page))
+		assert.Equal(t, 3, total)
+
+		page, total, err = repos.Media.GetPageByPost(ctx, post.ID, 2, 5)
+		require.NoError(t, err)
+		assert.NotNil(t, page)
+		assert.Empty(t, page)
+		assert.Equal(t, 3, total, "a page past the end still counts the media")
+
+		page, total, err = repos.Media.GetPageByPost(ctx, 999, 2, 0)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+		assert.Zero(t, total)
+	})
+
+	t.Run("get by posts", func(t *testing.T) {
+		repos := setup(t)
+		first := createPost(t, repos, &model.Post{AuthorID: 1, Title: "First"})
+		second := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Second"})
+		detached := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Detached"})
+		require.NoError(t, repos.Media.Attach(ctx, first.ID, []*model.PostMedia{
+			image("https://example.com/2.jpg", 2), image("https://example.com/1.jpg", 1),
+		}))
+		require.NoError(t, repos.Media.Attach(ctx, detached.ID, []*model.PostMedia{image("https://example.com/3.jpg", 1)}))
+		media, err := repos.Media.GetByPost(ctx, detached.ID)
+		require.NoError(t, err)
+		require.NoError(t, repos.Media.Detach(ctx, []int64{media[0].ID}))
+
+		byPost, err := repos.Media.GetByPosts(ctx, []int64{first.ID, second.ID, detached.ID, 999})
+		require.NoError(t, err)
+		require.Len(t, byPost, 1, "posts without media have no entry, even after a detach")
+		assert.Equal(t, []string{"https://example.com/1.jpg", "https://example.com/2.jpg"}, urls(byPost[first.ID]))
+	})
+}
@@ -0,0 +1,626 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/testsupport"
+)
+
+// PostRepository checks an implementation of post_repository.Repository.
+func PostRepository(t *testing.T, setup Setup) {
+	ctx := context.Background()
+
+	t.Run("missing post", func(t *testing.T) {
+		repos := setup(t)
+		title := "Title"
+
+		_, err := repos.Posts.GetByID(ctx, 999)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+		_, err = repos.Posts.GetByIDForUpdate(ctx, 999)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+		_, err = repos.Posts.Update(ctx, 999, &model.UpdatePostDTO{Title: &title})
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+		_, err = repos.Posts.Update(ctx, 999, &model.UpdatePostDTO{Title: &title, ExpectedVersion: testsupport.Ptr(int64(1))})
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound, "a versioned update of a missing post is not a conflict")
+		_, err = repos.Posts.Touch(ctx, 999)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+		_, err = repos.Posts.Publish(ctx, 999)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+		_, err = repos.Posts.CancelSchedule(ctx, 999)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+		_, err = repos.Posts.Pin(ctx, 999, time.Now())
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+		_, err = repos.Posts.Unpin(ctx, 999)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+		assert.ErrorIs(t, repos.Posts.Delete(ctx, 999), custom_errors.ErrPostNotFound)
+	})
+
+	t.Run("create fills defaults", func(t *testing.T) {
+		repos := setup(t)
+
+		published := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Published"})
+		draft := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft})
+
+		assert.NotZero(t, published.ID)
+		assert.Equal(t, model.PostStatusPublished, published.Status)
+		assert.Equal(t, model.PostVisibilityPublic, published.Visibility)
+		assert.Equal(t, int64(1), published.Version)
+		assert.True(t, published.CreatedAt.Valid)
+		assert.True(t, published.PublishedAt.Valid)
+		assert.False(t, draft.PublishedAt.Valid, "a draft has no publication time")
+		assert.Greater(t, draft.ID, published.ID)
+
+		got, err := repos.Posts.GetByID(ctx, published.ID)
+		require.NoError(t, err)
+		assert.Equal(t, published.Title, got.Title)
+		assert.Nil(t, got.Language, "a post has no language unless given one")
+		assert.Equal(t, model.PostSourceUnknown, got.Source, "a post without a source is unknown")
+		assert.True(t, got.CreatedAt.Time.Equal(published.CreatedAt.Time), "the stored time is the returned one")
+
+		withLanguage := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Language", Language: testsupport.Ptr("pt-BR")})
+		got, err = repos.Posts.GetByID(ctx, withLanguage.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "pt-BR", *got.Language)
+
+		withSource := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Source", Source: model.PostSourceAndroid})
+		assert.Equal(t, model.PostSourceAndroid, withSource.Source)
+		got, err = repos.Posts.GetByID(ctx, withSource.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.PostSourceAndroid, got.Source)
+
+		untitled := createPost(t, repos, &model.Post{AuthorID: 1})
+		got, err = repos.Posts.GetByID(ctx, untitled.ID)
+		require.NoError(t, err)
+		assert.Empty(t, got.Title, "a post may have no title")
+	})
+
+	t.Run("create many keeps the order", func(t *testing.T) {
+		repos := setup(t)
+
+		created, err := repos.Posts.CreateMany(ctx, []*model.Post{
+			{AuthorID: 1, Title: "First"},
+			{AuthorID: 2, Title: "Second", Status: model.PostStatusDraft},
+			{AuthorID: 1, Title: "Third", Source: model.PostSourceImport},
+		})
+		require.NoError(t, err)
+		require.Len(t, created, 3)
+		assert.Equal(t, []string{"First", "Second", "Third"}, []string{created[0].Title, created[1].Title, created[2].Title})
+		assert.Less(t, created[0].ID, created[1].ID)
+		assert.Less(t, created[1].ID, created[2].ID)
+		assert.Equal(t, model.PostStatusPublished, created[0].Status)
+		assert.True(t, created[0].PublishedAt.Valid)
+		assert.False(t, created[1].PublishedAt.Valid, "a draft has no publication time")
+		assert.Equal(t, int64(1), created[2].Version)
+
+		stored, err := repos.Posts.GetByID(ctx, created[2].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Third", stored.Title)
+		assert.Equal(t, model.PostSourceImport, stored.Source)
+		assert.Equal(t, model.PostSourceUnknown, created[0].Source)
+
+		created, err = repos.Posts.CreateMany(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, created)
+	})
+
+	t.Run("get detailed by id", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Title"})
+		bare := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Bare"})
+		createTags(t, repos, "rust", "go")
+		require.NoError(t, repos.Tags.TagPost(ctx, post.ID, []string{"rust", "go"}))
+		require.NoError(t, repos.Media.Attach(ctx, post.ID, []*model.PostMedia{
+			{URL: "https://example.com/2.jpg", Type: model.MediaTypeImage, Position: 2},
+			{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+		}))
+
+		got, err := repos.Posts.GetDetailedByID(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, post.ID, got.Post.ID)
+		assert.Equal(t, "Title", got.Post.Title)
+		assert.Nil(t, got.Author)
+		require.Len(t, got.Media, 2)
+		assert.Equal(t, "https://example.com/1.jpg", got.Media[0].URL, "media ordered by position")
+		assert.Equal(t, "https://example.com/2.jpg", got.Media[1].URL)
+		assert.Equal(t, []string{"go", "rust"}, tagNames(got.Tags), "tags ordered by name")
+
+		got, err = repos.Posts.GetDetailedByID(ctx, bare.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, got.Media, "no media reads as an empty slice, as GetByPost returns it")
+		assert.Empty(t, got.Media)
+		assert.Nil(t, got.Tags, "no tags read as nil, as FindByPost returns them")
+
+		_, err = repos.Posts.GetDetailedByID(ctx, 999)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	})
+
+	t.Run("get by ids skips missing ids", func(t *testing.T) {
+		repos := setup(t)
+		first := createPost(t, repos, &model.Post{AuthorID: 1, Title: "First"})
+		second := createPost(t, repos, &model.Post{AuthorID: 2, Title: "Second"})
+
+		got, err := repos.Posts.GetByIDs(ctx, []int64{second.ID, 999, first.ID})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{first.ID, second.ID}, postIDs(got))
+
+		got, err = repos.Posts.GetByIDs(ctx, []int64{999})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("get by author is newest first with drafts", func(t *testing.T) {
+		repos := setup(t)
+		first := createPost(t, repos, &model.Post{AuthorID: 1, Title: "First"})
+		draft := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft})
+		createPost(t, repos, &model.Post{AuthorID: 2, Title: "Other author"})
+		last := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Last", Visibility: model.PostVisibilityPrivate})
+
+		got, err := repos.Posts.GetByAuthor(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{last.ID, draft.ID, first.ID}, postIDs(got))
+
+		got, err = repos.Posts.GetByAuthor(ctx, 3)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("get by author after pages by id", func(t *testing.T) {
+		repos := setup(t)
+		var ids []int64
+		for range 3 {
+			ids = append(ids, createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"}).ID)
+		}
+		createPost(t, repos, &model.Post{AuthorID: 2, Title: "Other author"})
+
+		page, err := repos.Posts.GetByAuthorAfter(ctx, 1, 0, 2)
+		require.NoError(t, err)
+		assert.Equal(t, ids[:2], postIDs(page))
+		page, err = repos.Posts.GetByAuthorAfter(ctx, 1, ids[1], 2)
+		require.NoError(t, err)
+		assert.Equal(t, ids[2:], postIDs(page))
+		page, err = repos.Posts.GetByAuthorAfter(ctx, 1, ids[2], 2)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+	})
+
+	t.Run("count published by author", func(t *testing.T) {
+		repos := setup(t)
+		createPost(t, repos, &model.Post{AuthorID: 1, Title: "Published"})
+		createPost(t, repos, &model.Post{AuthorID: 1, Title: "Private", Visibility: model.PostVisibilityPrivate})
+		createPost(t, repos, &model.Post{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft})
+		createPost(t, repos, &model.Post{AuthorID: 1, Title: "Scheduled", Status: model.PostStatusScheduled,
+			ScheduledAt: testsupport.Timestamptz(time.Now().Add(time.Hour))})
+		createPost(t, repos, &model.Post{AuthorID: 2, Title: "Other author"})
+
+		count, err := repos.Posts.CountPublishedByAuthor(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count, "private posts are published too")
+
+		count, err = repos.Posts.CountPublishedByAuthor(ctx, 3)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("newest by tags since", func(t *testing.T) {
+		repos := setup(t)
+		createTags(t, repos, "go", "rust", "quiet")
+		tagged := func(post *model.Post, tags ...string) *model.Post {
+			created := createPost(t, repos, post)
+			require.NoError(t, repos.Tags.TagPost(ctx, created.ID, tags))
+			return created
+		}
+		old := tagged(&model.Post{AuthorID: 1, Title: "Old"}, "go", "quiet")
+		goPost := tagged(&model.Post{AuthorID: 1, Title: "Go"}, "go")
+		rustPost := tagged(&model.Post{AuthorID: 2, Title: "Rust"}, "rust", "go")
+		tagged(&model.Post{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft}, "go", "quiet")
+		tagged(&model.Post{AuthorID: 1, Title: "Private", Visibility: model.PostVisibilityPrivate}, "quiet")
+
+		newest, err := repos.Posts.NewestByTagsSince(ctx, []string{"go", "rust", "quiet", "missing"}, old.CreatedAt.Time)
+		require.NoError(t, err)
+		assert.Len(t, newest, 2, "quiet has only an old, a draft and a private post after since")
+		assert.True(t, newest["go"].Equal(rustPost.CreatedAt.Time), "got %v", newest["go"])
+		assert.True(t, newest["rust"].Equal(rustPost.CreatedAt.Time), "got %v", newest["rust"])
+
+		newest, err = repos.Posts.NewestByTagsSince(ctx, []string{"go"}, rustPost.CreatedAt.Time)
+		require.NoError(t, err)
+		assert.Empty(t, newest, "since is exclusive")
+
+		newest, err = repos.Posts.NewestByTagsSince(ctx, []string{"go"}, goPost.CreatedAt.Time.Add(-time.Microsecond))
+		require.NoError(t, err)
+		assert.True(t, newest["go"].Equal(rustPost.CreatedAt.Time))
+	})
+
+	t.Run("newest by tags since counts a post from its publication", func(t *testing.T) {
+		repos := setup(t)
+		createTags(t, repos, "go")
+		draft := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft})
+		require.NoError(t, repos.Tags.TagPost(ctx, draft.ID, []string{"go"}))
+		since := draft.CreatedAt.Time
+		time.Sleep(time.Millisecond)
+
+		newest, err := repos.Posts.NewestByTagsSince(ctx, []string{"go"}, since)
+		require.NoError(t, err)
+		assert.Empty(t, newest, "a draft is not visible")
+
+		published, err := repos.Posts.Publish(ctx, draft.ID)
+		require.NoError(t, err)
+		newest, err = repos.Posts.NewestByTagsSince(ctx, []string{"go"}, since)
+		require.NoError(t, err)
+		assert.True(t, newest["go"].Equal(published.PublishedAt.Time), "got %v", newest["go"])
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Title", Content: testsupport.Ptr("Content")})
+		title, empty := "Renamed", ""
+
+		updated, err := repos.Posts.Update(ctx, post.ID, &model.UpdatePostDTO{Title: &title, Content: &empty})
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed", updated.Title)
+		assert.Equal(t, "Content", *updated.Content, "an empty value leaves the field unchanged")
+		assert.Nil(t, updated.Language)
+		assert.Equal(t, post.Version+1, updated.Version)
+		assert.True(t, updated.UpdatedAt.Time.After(post.UpdatedAt.Time))
+
+		_, err = repos.Posts.Update(ctx, post.ID, &model.UpdatePostDTO{Content: &empty})
+		assert.ErrorIs(t, err, custom_errors.ErrNoUpdateRows)
+
+		_, err = repos.Posts.Update(ctx, post.ID, &model.UpdatePostDTO{Title: &title, ExpectedVersion: &post.Version})
+		var conflict *model.VersionConflictError
+		require.True(t, errors.As(err, &conflict), "got %v", err)
+		assert.Equal(t, updated.Version, conflict.CurrentVersion)
+
+		again, err := repos.Posts.Update(ctx, post.ID, &model.UpdatePostDTO{Title: &title, ExpectedVersion: &updated.Version})
+		require.NoError(t, err)
+		assert.Equal(t, updated.Version+1, again.Version)
+
+		_, err = repos.Posts.Update(ctx, post.ID, &model.UpdatePostDTO{Language: testsupport.Ptr("ru")})
+		require.NoError(t, err)
+		got, err := repos.Posts.GetByID(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "ru", *got.Language)
+	})
+
+	t.Run("update clears the title", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Title", Content: testsupport.Ptr("Content")})
+
+		updated, err := repos.Posts.Update(ctx, post.ID, &model.UpdatePostDTO{Title: testsupport.Ptr("")})
+		require.NoError(t, err)
+		assert.Empty(t, updated.Title)
+		assert.Equal(t, post.Version+1, updated.Version)
+
+		got, err := repos.Posts.GetByID(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Empty(t, got.Title)
+		assert.Equal(t, "Content", *got.Content)
+	})
+
+	t.Run("touch bumps updated at and version", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Title"})
+
+		touched, err := repos.Posts.Touch(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, post.Title, touched.Title)
+		assert.Equal(t, post.Version+1, touched.Version)
+		assert.True(t, touched.UpdatedAt.Time.After(post.UpdatedAt.Time))
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		repos := setup(t)
+		draft := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft})
+
+		published, err := repos.Posts.Publish(ctx, draft.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.PostStatusPublished, published.Status)
+		assert.True(t, published.PublishedAt.Valid)
+	})
+
+	t.Run("scheduled posts", func(t *testing.T) {
+		repos := setup(t)
+		now := time.Now()
+		scheduled := func(at time.Time) *model.Post {
+			return createPost(t, repos, &model.Post{AuthorID: 1, Title: "Scheduled", Status: model.PostStatusScheduled,
+				ScheduledAt: testsupport.Timestamptz(at)})
+		}
+		later := scheduled(now.Add(-time.Minute))
+		earlier := scheduled(now.Add(-time.Hour))
+		last := scheduled(now.Add(-time.Second))
+		future := scheduled(now.Add(time.Hour))
+
+		due, err := repos.Posts.PublishDue(ctx, now, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{earlier.ID, later.ID}, postIDs(due), "the longest overdue first")
+		for _, post := range due {
+			assert.Equal(t, model.PostStatusPublished, post.Status)
+			assert.True(t, post.PublishedAt.Valid)
+			assert.False(t, post.ScheduledAt.Valid)
+		}
+		due, err = repos.Posts.PublishDue(ctx, now, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{last.ID}, postIDs(due))
+		due, err = repos.Posts.PublishDue(ctx, now, 10)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		draft, err := repos.Posts.CancelSchedule(ctx, future.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.PostStatusDraft, draft.Status)
+		assert.False(t, draft.ScheduledAt.Valid)
+		_, err = repos.Posts.CancelSchedule(ctx, future.ID)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound, "a post that is not scheduled")
+	})
+
+	t.Run("pinning", func(t *testing.T) {
+		repos := setup(t)
+		older := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Older"})
+		newer := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Newer"})
+		newest := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Newest"})
+		other := createPost(t, repos, &model.Post{AuthorID: 2, Title: "Other"})
+
+		pinned, err := repos.Posts.Pin(ctx, older.ID, time.Now())
+		require.NoError(t, err)
+		assert.True(t, pinned.IsPinned())
+		assert.Equal(t, older.Version, pinned.Version, "pinning is no edit")
+		_, err = repos.Posts.Pin(ctx, other.ID, time.Now())
+		require.NoError(t, err, "every author has a pinned post of their own")
+
+		page := func(offset int) []int64 {
+			posts, total, err := repos.Posts.List(ctx, model.PostFilters{AuthorID: testsupport.Ptr(int64(1)), Limit: testsupport.Ptr(2), Offset: testsupport.Ptr(offset)})
+			require.NoError(t, err)
+			assert.Equal(t, 3, total)
+			return postIDs(posts)
+		}
+		assert.Equal(t, []int64{older.ID, newest.ID}, page(0), "the pinned post leads its author's list")
+		assert.Equal(t, []int64{newer.ID}, page(2), "and is not repeated on the next page")
+		all, _, err := repos.Posts.List(ctx, model.PostFilters{})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{other.ID, newest.ID, newer.ID, older.ID}, postIDs(all), "a list of every author ignores pinning")
+
+		unpinned, err := repos.Posts.UnpinAuthor(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{older.ID}, unpinned)
+		unpinned, err = repos.Posts.UnpinAuthor(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, unpinned)
+		_, err = repos.Posts.Pin(ctx, newer.ID, time.Now())
+		require.NoError(t, err, "the author's previous pin is gone")
+
+		got, err := repos.Posts.Unpin(ctx, other.ID)
+		require.NoError(t, err)
+		assert.False(t, got.IsPinned())
+		got, err = repos.Posts.GetByID(ctx, newer.ID)
+		require.NoError(t, err)
+		assert.True(t, got.IsPinned(), "unpinning one author leaves the others alone")
+	})
+
+	t.Run("moderation status", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Reported"})
+		other := createPost(t, repos, &model.Post{AuthorID: 2, Title: "Other"})
+		assert.Equal(t, model.ModerationStatusApproved, post.ModerationStatus)
+		assert.Nil(t, post.RejectionReason)
+
+		now := time.Now()
+		rejected, err := repos.Posts.SetModerationStatus(ctx, post.ID, model.ModerationStatusRejected, testsupport.Ptr(model.RejectionReasonSpam), now)
+		require.NoError(t, err)
+		assert.Equal(t, model.ModerationStatusRejected, rejected.ModerationStatus)
+		assert.Equal(t, model.RejectionReasonSpam, *rejected.RejectionReason)
+		assert.Equal(t, post.Version, rejected.Version, "moderation is no edit")
+		assert.Equal(t, "Reported", rejected.Title)
+
+		got, err := repos.Posts.GetByID(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ModerationStatusRejected, got.ModerationStatus)
+		assert.Equal(t, model.RejectionReasonSpam, *got.RejectionReason)
+
+		list := func(requesterID *int64) []int64 {
+			posts, total, err := repos.Posts.List(ctx, model.PostFilters{RequesterID: requesterID})
+			require.NoError(t, err)
+			assert.Len(t, posts, total)
+			return postIDs(posts)
+		}
+		assert.Equal(t, []int64{other.ID}, list(nil), "a rejected post is not listed")
+		assert.Equal(t, []int64{other.ID}, list(testsupport.Ptr(int64(2))))
+		assert.Equal(t, []int64{other.ID, post.ID}, list(testsupport.Ptr(int64(1))), "but its author still lists it")
+
+		approved, err := repos.Posts.SetModerationStatus(ctx, post.ID, model.ModerationStatusApproved, nil, now)
+		require.NoError(t, err)
+		assert.Nil(t, approved.RejectionReason)
+		assert.Equal(t, []int64{other.ID, post.ID}, list(nil))
+
+		_, err = repos.Posts.SetModerationStatus(ctx, 999, model.ModerationStatusUnderReview, nil, now)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	})
+
+	t.Run("author snapshot", func(t *testing.T) {
+		repos := setup(t)
+		takenAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+		snapshotted := &model.Post{AuthorID: 1, Title: "Snapshotted"}
+		snapshotted.SetAuthorSnapshot(&model.User{ID: 1, Username: "one", AvatarURL: testsupport.Ptr("https://example.com/one.png")}, takenAt)
+		post := createPost(t, repos, snapshotted)
+		legacy := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Before snapshots"})
+		other := createPost(t, repos, &model.Post{AuthorID: 2, Title: "Other"})
+		assert.Equal(t, "one", *post.AuthorUsername)
+		assert.Equal(t, "https://example.com/one.png", *post.AuthorAvatarURL)
+		assert.True(t, post.AuthorSnapshotAt.Time.Equal(takenAt))
+		assert.Nil(t, legacy.AuthorUsername)
+		assert.False(t, legacy.AuthorSnapshotAt.Valid)
+
+		refreshedAt := takenAt.Add(time.Minute)
+		count, err := repos.Posts.RefreshAuthorSnapshot(ctx, &model.User{ID: 1, Username: "one-renamed"}, refreshedAt)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		for _, id := range []int64{post.ID, legacy.ID} {
+			got, err := repos.Posts.GetByID(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, "one-renamed", *got.AuthorUsername)
+			assert.Nil(t, got.AuthorAvatarURL)
+			assert.True(t, got.AuthorSnapshotAt.Time.Equal(refreshedAt))
+			assert.Equal(t, int64(1), got.Version, "a refresh is no edit")
+		}
+		got, err := repos.Posts.GetByID(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got.AuthorUsername, "other authors are left alone")
+
+		count, err = repos.Posts.RefreshAuthorSnapshot(ctx, &model.User{ID: 1, Username: "older"}, takenAt)
+		require.NoError(t, err)
+		assert.Zero(t, count, "an older snapshot does not overwrite a newer one")
+	})
+
+	t.Run("delete removes tags and media", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Title"})
+		createTags(t, repos, "go")
+		require.NoError(t, repos.Tags.TagPost(ctx, post.ID, []string{"go"}))
+		require.NoError(t, repos.Media.Attach(ctx, post.ID, []*model.PostMedia{
+			{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+		}))
+
+		require.NoError(t, repos.Posts.Delete(ctx, post.ID))
+
+		_, err := repos.Posts.GetByID(ctx, post.ID)
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+		tags, err := repos.Tags.FindByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+		media, err := repos.Media.GetByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Empty(t, media)
+		assert.ErrorIs(t, repos.Posts.Delete(ctx, post.ID), custom_errors.ErrPostNotFound)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		listPosts(t, setup(t))
+	})
+}
+
+// listPosts checks List filter combinations against one set of posts. Posts are created in
+// the order below, timestampGap apart:
+//
+//	go:        author 1, published, en, from web, tagged go, one image
+//	rust:      author 1, published, ru, tagged rust and go-lang, one video
+//	draft:     author 1, draft, en, tagged go
+//	private:   author 1, published, private
+//	unlisted:  author 1, published, unlisted
+//	other:     author 2, published, from ios, tagged go, an image and a video
+//
+// Posts without a language are listed whenever no language filter is set; posts without a
+// source are unknown.
+func listPosts(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	createTags(t, repos, "go", "rust", "go-lang")
+	media := func(types ...model.MediaType) []*model.PostMedia {
+		items := make([]*model.PostMedia, len(types))
+		for i, mediaType := range types {
+			items[i] = &model.PostMedia{URL: "https://example.com/" + string(mediaType) + ".jpg", Type: mediaType, Position: int32(i + 1)}
+		}
+		return items
+	}
+
+	ids := make(map[string]int64)
+	posts := make(map[string]*model.Post)
+	for _, p := range []struct {
+		name  string
+		post  *model.Post
+		tags  []string
+		media []*model.PostMedia
+	}{
+		{name: "go", post: &model.Post{AuthorID: 1, Language: testsupport.Ptr("en"), Source: model.PostSourceWeb}, tags: []string{"go"}, media: media(model.MediaTypeImage)},
+		{name: "rust", post: &model.Post{AuthorID: 1, Language: testsupport.Ptr("ru")}, tags: []string{"rust", "go-lang"}, media: media(model.MediaTypeVideo)},
+		{name: "draft", post: &model.Post{AuthorID: 1, Status: model.PostStatusDraft, Language: testsupport.Ptr("en")}, tags: []string{"go"}},
+		{name: "private", post: &model.Post{AuthorID: 1, Visibility: model.PostVisibilityPrivate}},
+		{name: "unlisted", post: &model.Post{AuthorID: 1, Visibility: model.PostVisibilityUnlisted}},
+		{name: "other", post: &model.Post{AuthorID: 2, Source: model.PostSourceIOS}, tags: []string{"go"}, media: media(model.MediaTypeImage, model.MediaTypeVideo)},
+	} {
+		p.post.Title = p.name
+		created := createPost(t, repos, p.post)
+		if len(p.tags) > 0 {
+			require.NoError(t, repos.Tags.TagPost(ctx, created.ID, p.tags))
+		}
+		if len(p.media) > 0 {
+			require.NoError(t, repos.Media.Attach(ctx, created.ID, p.media))
+		}
+		ids[p.name], posts[p.name] = created.ID, created
+	}
+
+	// Touch the first post last, for the updated_at filter and sort.
+	time.Sleep(timestampGap)
+	touchedAfter := testsupport.Timestamptz(time.Now())
+	time.Sleep(timestampGap)
+	_, err := repos.Posts.Touch(ctx, ids["go"])
+	require.NoError(t, err)
+
+	author, other := int64(1), int64(2)
+	tests := []struct {
+		name    string
+		filters model.PostFilters
+		// want lists the page in order; wantTotal defaults to len(want).
+		want      []string
+		wantTotal int
+	}{
+		{name: "no filters", want: []string{"other", "rust", "go"}},
+		{name: "author", filters: model.PostFilters{AuthorID: &author}, want: []string{"rust", "go"}},
+		{name: "authors", filters: model.PostFilters{AuthorIDs: []int64{author, other}}, want: []string{"other", "rust", "go"}},
+		{name: "author listing their own posts", filters: model.PostFilters{AuthorID: &author, RequesterID: &author},
+			want: []string{"unlisted", "private", "draft", "rust", "go"}},
+		{name: "requester without author", filters: model.PostFilters{RequesterID: &author}, want: []string{"other", "draft", "rust", "go"}},
+		{name: "another requester", filters: model.PostFilters{AuthorID: &author, RequesterID: &other}, want: []string{"rust", "go"}},
+		{name: "excluded authors", filters: model.PostFilters{ExcludedAuthorIDs: []int64{author, 3}}, want: []string{"other"}},
+		{name: "excluded authors and requester", filters: model.PostFilters{RequesterID: &author, ExcludedAuthorIDs: []int64{other}},
+			want: []string{"draft", "rust", "go"}},
+		{name: "tag matched exactly", filters: model.PostFilters{TagNames: []string{"GO"}}, want: []string{}},
+		{name: "tag underscore is no wildcard", filters: model.PostFilters{TagNames: []string{"go_lang"}}, want: []string{}},
+		{name: "tag percent is no wildcard", filters: model.PostFilters{TagNames: []string{"go%"}}, want: []string{}},
+		{name: "tags match any once", filters: model.PostFilters{TagNames: []string{"go", "rust", "go-lang"}}, want: []string{"other", "rust", "go"}},
+		{name: "tag and author", filters: model.PostFilters{TagNames: []string{"go"}, AuthorID: &other}, want: []string{"other"}},
+		{name: "has media", filters: model.PostFilters{HasMedia: testsupport.Ptr(true), AuthorID: &author, RequesterID: &author}, want: []string{"rust", "go"}},
+		{name: "no media", filters: model.PostFilters{HasMedia: testsupport.Ptr(false), AuthorID: &author, RequesterID: &author},
+			want: []string{"unlisted", "private", "draft"}},
+		{name: "media type", filters: model.PostFilters{MediaType: testsupport.Ptr(model.MediaTypeVideo)}, want: []string{"other", "rust"}},
+		{name: "media type and tag", filters: model.PostFilters{MediaType: testsupport.Ptr(model.MediaTypeImage), TagNames: []string{"go"}},
+			want: []string{"other", "go"}},
+		{name: "language", filters: model.PostFilters{Language: testsupport.Ptr("en")}, want: []string{"go"}},
+		{name: "language and requester", filters: model.PostFilters{Language: testsupport.Ptr("en"), RequesterID: &author}, want: []string{"draft", "go"}},
+		{name: "language is matched whole", filters: model.PostFilters{Language: testsupport.Ptr("en-US")}, want: []string{}},
+		{name: "source", filters: model.PostFilters{Source: testsupport.Ptr(model.PostSourceIOS)}, want: []string{"other"}},
+		{name: "unknown source", filters: model.PostFilters{Source: testsupport.Ptr(model.PostSourceUnknown)}, want: []string{"rust"}},
+		{name: "source and tag", filters: model.PostFilters{Source: testsupport.Ptr(model.PostSourceWeb), TagNames: []string{"go"}}, want: []string{"go"}},
+		{name: "created after is exclusive", filters: model.PostFilters{CreatedAfter: &posts["rust"].CreatedAt}, want: []string{"other"}},
+		{name: "created before is exclusive", filters: model.PostFilters{CreatedBefore: &posts["rust"].CreatedAt}, want: []string{"go"}},
+		{name: "updated after", filters: model.PostFilters{UpdatedAfter: &touchedAfter}, want: []string{"go"}},
+		{name: "ascending", filters: model.PostFilters{SortOrder: model.SortAsc}, want: []string{"go", "rust", "other"}},
+		{name: "by updated at", filters: model.PostFilters{SortBy: model.SortByUpdatedAt}, want: []string{"go", "other", "rust"}},
+		{name: "page", filters: model.PostFilters{Limit: testsupport.Ptr(1), Offset: testsupport.Ptr(1)}, want: []string{"rust"}, wantTotal: 3},
+		{name: "offset past the end", filters: model.PostFilters{Offset: testsupport.Ptr(5)}, want: []string{}, wantTotal: 3},
+		{name: "no match", filters: model.PostFilters{AuthorID: testsupport.Ptr(int64(3))}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total, err := repos.Posts.List(ctx, tt.filters)
+
+			require.NoError(t, err)
+			want := make([]int64, len(tt.want))
+			for i, name := range tt.want {
+				want[i] = ids[name]
+			}
+			assert.Equal(t, want, postIDs(got))
+			wantTotal := tt.wantTotal
+			if wantTotal == 0 {
+				wantTotal = len(tt.want)
+			}
+			assert.Equal(t, wantTotal, total)
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// TagRepository checks an implementation of tag_repository.Repository.
+func TagRepository(t *testing.T, setup Setup) {
+	ctx := context.Background()
+
+	t.Run("create returns the existing tag", func(t *testing.T) {
+		repos := setup(t)
+
+		created, err := repos.Tags.Create(ctx, "go")
+		require.NoError(t, err)
+		again, err := repos.Tags.Create(ctx, "go")
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, again.ID)
+	})
+
+	t.Run("create many", func(t *testing.T) {
+		repos := setup(t)
+		existing, err := repos.Tags.Create(ctx, "go")
+		require.NoError(t, err)
+
+		tags, err := repos.Tags.CreateMany(ctx, []string{"rust", "go", "rust"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"go", "rust"}, tagNames(tags), "one row per name, created or not")
+		for _, tag := range tags {
+			if tag.Name == "go" {
+				assert.Equal(t, existing.ID, tag.ID)
+			}
+		}
+
+		tags, err = repos.Tags.CreateMany(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("find by names skips unknown names", func(t *testing.T) {
+		repos := setup(t)
+		createTags(t, repos, "go", "rust")
+
+		tags, err := repos.Tags.FindByNames(ctx, []string{"rust", "zig", "go"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"go", "rust"}, tagNames(tags))
+
+		for _, names := range [][]string{nil, {"zig"}} {
+			tags, err = repos.Tags.FindByNames(ctx, names)
+			require.NoError(t, err)
+			assert.Empty(t, tags)
+		}
+	})
+
+	t.Run("tag post", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		createTags(t, repos, "go", "rust", "zig")
+
+		require.NoError(t, repos.Tags.TagPost(ctx, post.ID, []string{"rust", "go"}))
+		require.NoError(t, repos.Tags.TagPost(ctx, post.ID, []string{"go", "zig"}), "a link the post has is kept")
+		require.NoError(t, repos.Tags.TagPost(ctx, post.ID, nil))
+
+		tags, err := repos.Tags.FindByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "rust", "zig"}, tagNames(tags), "ordered by name")
+
+		assert.ErrorIs(t, repos.Tags.TagPost(ctx, 999, []string{"go"}), custom_errors.ErrPostNotFound)
+		assert.ErrorIs(t, repos.Tags.TagPost(ctx, post.ID, []string{"unknown"}), custom_errors.ErrTagNotFound)
+
+		tags, err = repos.Tags.FindByPost(ctx, 999)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("link posts", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		other := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Other"})
+		tags := createTags(t, repos, "go", "rust")
+
+		require.NoError(t, repos.Tags.LinkPosts(ctx, []*model.PostTag{
+			{PostID: post.ID, TagID: tags["rust"].ID},
+			{PostID: post.ID, TagID: tags["go"].ID},
+			{PostID: other.ID, TagID: tags["go"].ID},
+			{PostID: other.ID, TagID: tags["go"].ID},
+		}), "a repeated link is kept once")
+		require.NoError(t, repos.Tags.LinkPosts(ctx, nil))
+
+		found, err := repos.Tags.FindByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "rust"}, tagNames(found))
+		found, err = repos.Tags.FindByPost(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, tagNames(found))
+
+		assert.ErrorIs(t, repos.Tags.LinkPosts(ctx, []*model.PostTag{{PostID: 999, TagID: tags["go"].ID}}), custom_errors.ErrPostNotFound)
+		assert.ErrorIs(t, repos.Tags.LinkPosts(ctx, []*model.PostTag{{PostID: post.ID, TagID: 999}}), custom_errors.ErrTagNotFound)
+	})
+
+	t.Run("tag post existing returns missing names", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		createTags(t, repos, "go")
+
+		missing, err := repos.Tags.TagPostExisting(ctx, post.ID, []string{"zig", "go", "rust"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"zig", "rust"}, missing)
+		missing, err = repos.Tags.TagPostExisting(ctx, post.ID, []string{"go"})
+		require.NoError(t, err)
+		assert.NotNil(t, missing)
+		assert.Empty(t, missing)
+
+		tags, err := repos.Tags.FindByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, tagNames(tags))
+
+		_, err = repos.Tags.TagPostExisting(ctx, 999, []string{"go"})
+		assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	})
+
+	t.Run("untag post ignores names it does not carry", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		createTags(t, repos, "go", "rust", "zig")
+		require.NoError(t, repos.Tags.TagPost(ctx, post.ID, []string{"go", "rust"}))
+
+		require.NoError(t, repos.Tags.UntagPost(ctx, post.ID, []string{"go", "zig", "unknown"}))
+
+		tags, err := repos.Tags.FindByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"rust"}, tagNames(tags))
+		assert.ErrorIs(t, repos.Tags.UntagPost(ctx, 999, []string{"go"}), custom_errors.ErrPostNotFound)
+	})
+
+	t.Run("replace post tags", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		createTags(t, repos, "go", "rust", "zig")
+		require.NoError(t, repos.Tags.TagPost(ctx, post.ID, []string{"go", "rust"}))
+
+		require.NoError(t, repos.Tags.ReplacePostTags(ctx, post.ID, []string{"zig", "rust", "zig"}))
+		tags, err := repos.Tags.FindByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"rust", "zig"}, tagNames(tags))
+
+		require.NoError(t, repos.Tags.ReplacePostTags(ctx, post.ID, nil))
+		tags, err = repos.Tags.FindByPost(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+
+		assert.ErrorIs(t, repos.Tags.ReplacePostTags(ctx, post.ID, []string{"unknown"}), custom_errors.ErrTagNotFound)
+		assert.ErrorIs(t, repos.Tags.ReplacePostTags(ctx, 999, []string{"go"}), custom_errors.ErrPostNotFound)
+	})
+
+	t.Run("find by posts", func(t *testing.T) {
+		repos := setup(t)
+		tagged := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Tagged"})
+		untagged := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Untagged"})
+		createTags(t, repos, "go", "rust")
+		require.NoError(t, repos.Tags.TagPost(ctx, tagged.ID, []string{"rust", "go"}))
+
+		byPost, err := repos.Tags.FindByPosts(ctx, []int64{tagged.ID, untagged.ID, tagged.ID, 999})
+		require.NoError(t, err)
+		require.Len(t, byPost, 1, "posts without tags have no entry")
+		assert.Equal(t, []string{"go", "rust"}, tagNames(byPost[tagged.ID]), "ordered by name, each once")
+	})
+
+	t.Run("post ids and counts by tag", func(t *testing.T) {
+		repos := setup(t)
+		first := createPost(t, repos, &model.Post{AuthorID: 1, Title: "First"})
+		second := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Second"})
+		tags := createTags(t, repos, "go", "rust", "zig")
+		require.NoError(t, repos.Tags.TagPost(ctx, second.ID, []string{"go", "rust"}))
+		require.NoError(t, repos.Tags.TagPost(ctx, first.ID, []string{"go"}))
+		goID, rustID, zigID := tags["go"].ID, tags["rust"].ID, tags["zig"].ID
+
+		ids, err := repos.Tags.FindPostIDsByTags(ctx, []int64{rustID, goID, zigID})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{first.ID, second.ID}, ids, "each post once, ascending")
+		ids, err = repos.Tags.FindPostIDsByTags(ctx, []int64{zigID})
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+
+		counts, err := repos.Tags.CountPosts(ctx, []int64{goID, rustID, zigID, 999})
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]int64{goID: 2, rustID: 1}, counts, "tags without posts have no entry")
+	})
+
+	t.Run("delete unused keeps tags in use", func(t *testing.T) {
+		repos := setup(t)
+		post := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Post"})
+		createTags(t, repos, "go", "rust")
+		require.NoError(t, repos.Tags.TagPost(ctx, post.ID, []string{"go"}))
+
+		require.NoError(t, repos.Tags.DeleteUnused(ctx))
+
+		tags, err := repos.Tags.FindByNames(ctx, []string{"go", "rust"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, tagNames(tags))
+	})
+
+	t.Run("search tags", func(t *testing.T) {
+		repos := setup(t)
+		mine := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Mine"})
+		others := []int64{
+			createPost(t, repos, &model.Post{AuthorID: 2, Title: "Other"}).ID,
+			createPost(t, repos, &model.Post{AuthorID: 2, Title: "Other"}).ID,
+		}
+		createTags(t, repos, "go", "golang", "gopher", "go_lang", "rust")
+		for _, id := range others {
+			require.NoError(t, repos.Tags.TagPost(ctx, id, []string{"golang"}))
+		}
+		require.NoError(t, repos.Tags.TagPost(ctx, others[0], []string{"gopher"}))
+		require.NoError(t, repos.Tags.TagPost(ctx, mine.ID, []string{"go"}))
+
+		tags, err := repos.Tags.SearchTags(ctx, " GO ", 10, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"golang", "go", "gopher", "go_lang"}, tagNames(tags), "most used first, then by name")
+		counts := make([]int64, len(tags))
+		for i, tag := range tags {
+			require.NotNil(t, tag.PostCount)
+			counts[i] = *tag.PostCount
+		}
+		assert.Equal(t, []int64{2, 1, 1, 0}, counts)
+
+		author := int64(1)
+		tags, err = repos.Tags.SearchTags(ctx, "go", 2, &author)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "golang"}, tagNames(tags), "the author's tags first")
+
+		tags, err = repos.Tags.SearchTags(ctx, "go_", 10, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go_lang"}, tagNames(tags), "an underscore is no wildcard")
+
+		tags, err = repos.Tags.SearchTags(ctx, "zig", 10, nil)
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		repos := setup(t)
+		tags := createTags(t, repos, "go", "rust")
+
+		renamed, err := repos.Tags.Rename(ctx, tags["go"].ID, "golang")
+		require.NoError(t, err)
+		assert.Equal(t, model.Tag{ID: tags["go"].ID, Name: "golang"}, *renamed)
+
+		_, err = repos.Tags.Rename(ctx, tags["go"].ID, "rust")
+		assert.ErrorIs(t, err, custom_errors.ErrTagAlreadyExists)
+		_, err = repos.Tags.Rename(ctx, 999, "zig")
+		assert.ErrorIs(t, err, custom_errors.ErrTagNotFound)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		repos := setup(t)
+		both := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Both"})
+		source := createPost(t, repos, &model.Post{AuthorID: 1, Title: "Source"})
+		tags := createTags(t, repos, "go", "golang", "go-lang")
+		require.NoError(t, repos.Tags.TagPost(ctx, both.ID, []string{"go", "golang"}))
+		require.NoError(t, repos.Tags.TagPost(ctx, source.ID, []string{"go-lang"}))
+
+		dest, err := repos.Tags.Merge(ctx, []int64{tags["golang"].ID, tags["go-lang"].ID}, tags["go"].ID)
+		require.NoError(t, err)
+		assert.Equal(t, tags["go"].ID, dest.ID)
+
+		byPost, err := repos.Tags.FindByPosts(ctx, []int64{both.ID, source.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, tagNames(byPost[both.ID]), "a post with both tags keeps one link")
+		assert.Equal(t, []string{"go"}, tagNames(byPost[source.ID]))
+		left, err := repos.Tags.FindByNames(ctx, []string{"golang", "go-lang"})
+		require.NoError(t, err)
+		assert.Empty(t, left, "the source tags are deleted")
+
+		dest, err = repos.Tags.Merge(ctx, nil, tags["go"].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "go", dest.Name)
+		_, err = repos.Tags.Merge(ctx, []int64{tags["go"].ID}, 999)
+		assert.ErrorIs(t, err, custom_errors.ErrTagNotFound)
+		_, err = repos.Tags.Merge(ctx, []int64{999}, tags["go"].ID)
+		assert.ErrorIs(t, err, custom_errors.ErrTagNotFound)
+	})
+}
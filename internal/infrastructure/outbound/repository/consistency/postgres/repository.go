@@ -0,0 +1,88 @@
+package consistency_repository_postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+type ConsistencyRepository struct {
+	log     ports.Logger
+	db      db.PgDB
+	metrics ports.MetricsProvider
+}
+
+func NewConsistencyRepository(db db.PgDB, log ports.Logger, metrics ports.MetricsProvider) *ConsistencyRepository {
+	return &ConsistencyRepository{db: db, log: log, metrics: metrics}
+}
+
+// orphan selects the rows of a kind: the table they live in, the columns identifying a row
+// and the condition making it an orphan, with the table aliased o.
+type orphan struct {
+	table string
+	key   string
+	where string
+}
+
+var orphans = map[model.OrphanKind]orphan{
+	model.OrphanMedia: {
+		table: "post_media",
+		key:   "id",
+		where: "NOT EXISTS (SELECT 1 FROM posts p WHERE p.id = o.post_id)",
+	},
+	model.OrphanPostTagsWithoutPost: {
+		table: "posts_tags",
+		key:   "post_id, tag_id",
+		where: "NOT EXISTS (SELECT 1 FROM posts p WHERE p.id = o.post_id)",
+	},
+	model.OrphanPostTagsWithoutTag: {
+		table: "posts_tags",
+		key:   "post_id, tag_id",
+		where: "NOT EXISTS (SELECT 1 FROM tags t WHERE t.id = o.tag_id)",
+	},
+}
+
+func (c *ConsistencyRepository) CountOrphans(ctx context.Context, kind model.OrphanKind) (count int, err error) {
+	defer db.ObserveQuery(c.metrics, c.log, "consistency_count_orphans", time.Now(), &err, slog.String("kind", string(kind)))
+
+	o, ok := orphans[kind]
+	if !ok {
+		return 0, custom_errors.ErrInvalidInput
+	}
+	err = c.db.QueryRow(ctx, fmt.Sprintf(`SELECT count(*) FROM %s o WHERE %s`, o.table, o.where)).Scan(&count)
+	if err != nil {
+		c.log.Error("Error counting orphans", slog.String("kind", string(kind)), slog.String("error", err.Error()))
+		return 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return count, nil
+}
+
+func (c *ConsistencyRepository) DeleteOrphans(ctx context.Context, kind model.OrphanKind, limit int) (deleted int, err error) {
+	defer db.ObserveQuery(c.metrics, c.log, "consistency_delete_orphans", time.Now(), &err, slog.String("kind", string(kind)), slog.Int("limit", limit))
+
+	o, ok := orphans[kind]
+	if !ok || limit <= 0 {
+		return 0, custom_errors.ErrInvalidInput
+	}
+	// Rows locked by a concurrent transaction are skipped and picked up by a later batch.
+	tag, err := c.db.Exec(ctx, fmt.Sprintf(`
+		DELETE FROM %[1]s WHERE (%[2]s) IN (
+			SELECT %[2]s FROM %[1]s o WHERE %[3]s LIMIT @limit FOR UPDATE SKIP LOCKED
+		)`, o.table, o.key, o.where),
+		pgx.NamedArgs{"limit": limit},
+	)
+	if err != nil {
+		c.log.Error("Error deleting orphans", slog.String("kind", string(kind)), slog.String("error", err.Error()))
+		return 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	deleted = int(tag.RowsAffected())
+	c.log.Debug("Deleted orphans", slog.String("kind", string(kind)), slog.Int("count", deleted))
+	return deleted, nil
+}
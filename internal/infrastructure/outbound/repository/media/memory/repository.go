@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	ports "pinstack-post-service/internal/domain/ports/output"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -21,6 +22,9 @@ type MediaRepository struct {
 	mediaByID     map[int64]*model.PostMedia
 	postExists    map[int64]bool
 	nextID        int64
+
+	// postLookup, when set, replaces postExists. See SetPostLookup.
+	postLookup func(postID int64) bool
 }
 
 func NewMediaRepository(log ports.Logger) *MediaRepository {
@@ -39,21 +43,169 @@ func (m *MediaRepository) SimulatePostExists(postID int64, exists bool) {
 	m.postExists[postID] = exists
 }
 
+// SetPostLookup makes the repository ask exists whether a post is stored instead of relying
+// on SimulatePostExists. exists is called with the repository lock held and must not call back
+// into it.
+func (m *MediaRepository) SetPostLookup(exists func(postID int64) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.postLookup = exists
+}
+
+func (m *MediaRepository) hasPost(postID int64) bool {
+	if m.postLookup != nil {
+		return m.postLookup(postID)
+	}
+	return m.postExists[postID]
+}
+
+// MediaTypes returns the types of the media of postID, for the post repository's media filter.
+func (m *MediaRepository) MediaTypes(postID int64) []model.MediaType {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	types := make([]model.MediaType, 0, len(m.mediaByPostID[postID]))
+	for _, media := range m.mediaByPostID[postID] {
+		types = append(types, media.Type)
+	}
+	return types
+}
+
+// RemovePost drops the media of a deleted post, as ON DELETE CASCADE does in Postgres.
+func (m *MediaRepository) RemovePost(postID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, media := range m.mediaByPostID[postID] {
+		delete(m.mediaByID, media.ID)
+	}
+	delete(m.mediaByPostID, postID)
+}
+
+// Snapshot copies the stored media and returns a function that puts the copy back. Ids are
+// not reused after a restore, as a Postgres sequence is not rolled back either.
+func (m *MediaRepository) Snapshot() (restore func()) {
+	m.mu.RLock()
+	mediaByPostID := make(map[int64][]*model.PostMedia, len(m.mediaByPostID))
+	mediaByID := make(map[int64]*model.PostMedia, len(m.mediaByID))
+	for postID, media := range m.mediaByPostID {
+		copies := make([]*model.PostMedia, len(media))
+		for i, item := range media {
+			copies[i] = item.Clone()
+			mediaByID[item.ID] = copies[i]
+		}
+		mediaByPostID[postID] = copies
+	}
+	postExists := make(map[int64]bool, len(m.postExists))
+	for id, exists := range m.postExists {
+		postExists[id] = exists
+	}
+	m.mu.RUnlock()
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.mediaByPostID = mediaByPostID
+		m.mediaByID = mediaByID
+		m.postExists = postExists
+	}
+}
+
 func (m *MediaRepository) Attach(ctx context.Context, postID int64, media []*model.PostMedia) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if exists, found := m.postExists[postID]; !found || !exists {
+	return m.attach(postID, media)
+}
+
+// AttachMany attaches nothing when the media of any post fail, like the single INSERT in
+// Postgres.
+func (m *MediaRepository) AttachMany(ctx context.Context, media []*model.PostMedia) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	byPost := make(map[int64][]*model.PostMedia)
+	postIDs := make([]int64, 0)
+	for _, md := range media {
+		if _, seen := byPost[md.PostID]; !seen {
+			postIDs = append(postIDs, md.PostID)
+		}
+		byPost[md.PostID] = append(byPost[md.PostID], md)
+	}
+	for _, postID := range postIDs {
+		if err := m.check(postID, byPost[postID]); err != nil {
+			return err
+		}
+	}
+	for _, postID := range postIDs {
+		if err := m.attach(postID, byPost[postID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check fails as attach would, without attaching; m.mu must be held.
+func (m *MediaRepository) check(postID int64, media []*model.PostMedia) error {
+	if !m.hasPost(postID) {
+		return custom_errors.ErrPostNotFound
+	}
+	if err := model.ValidateMediaTypes(media); err != nil {
+		return err
+	}
+	positions := make(map[int32]bool)
+	urls := make(map[string]bool)
+	for _, md := range m.mediaByPostID[postID] {
+		positions[md.Position], urls[md.URL] = true, true
+	}
+	for _, md := range media {
+		if positions[md.Position] || urls[md.URL] {
+			return model.ErrMediaDuplicate
+		}
+		positions[md.Position], urls[md.URL] = true, true
+	}
+	return nil
+}
+
+func (m *MediaRepository) attach(postID int64, media []*model.PostMedia) error {
+	if !m.hasPost(postID) {
 		m.log.Warn("Post not found during media attach", slog.Int64("post_id", postID))
 		return custom_errors.ErrPostNotFound
 	}
+	// Like the CHECK constraint in Postgres, an unknown type fails the whole attach.
+	if err := model.ValidateMediaTypes(media); err != nil {
+		return err
+	}
+
+	// Like the unique constraints in Postgres, a clash fails the whole attach.
+	positions := make(map[int32]bool)
+	urls := make(map[string]bool)
+	taken := func(md *model.PostMedia) bool {
+		clash := positions[md.Position] || urls[md.URL]
+		positions[md.Position], urls[md.URL] = true, true
+		return clash
+	}
+	for _, md := range m.mediaByPostID[postID] {
+		taken(md)
+	}
+	for _, md := range media {
+		if taken(md) {
+			m.log.Warn("Duplicate media during attach", slog.Int64("post_id", postID), slog.String("url", md.URL))
+			return model.ErrMediaDuplicate
+		}
+	}
 
 	for _, md := range media {
 		newMedia := &model.PostMedia{
-			ID:       m.nextID,
-			URL:      md.URL,
-			Type:     md.Type,
-			Position: md.Position,
+			ID:        m.nextID,
+			PostID:    postID,
+			URL:       md.URL,
+			Type:      md.Type,
+			Position:  md.Position,
+			Width:     md.Width,
+			Height:    md.Height,
+			SizeBytes: md.SizeBytes,
+			AltText:   md.AltText,
 			CreatedAt: pgtype.Timestamptz{
 				Time:  time.Now(),
 				Valid: true,
@@ -76,12 +228,22 @@ func (m *MediaRepository) Reorder(ctx context.Context, postID int64, newPosition
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if _, exists := m.mediaByPostID[postID]; !exists {
-		return custom_errors.ErrMediaReorderFailed
+	// Positions are checked after the whole move, as the deferrable constraint in Postgres is.
+	positions := make(map[int32]bool, len(m.mediaByPostID[postID]))
+	for _, media := range m.mediaByPostID[postID] {
+		position := media.Position
+		if newPosition, ok := newPositions[media.ID]; ok {
+			position = int32(newPosition)
+		}
+		if positions[position] {
+			return model.ErrMediaDuplicate
+		}
+		positions[position] = true
 	}
 
+	// Like the postgres repository, ids that are not media of postID are skipped.
 	for mediaID, newPosition := range newPositions {
-		if media, exists := m.mediaByID[mediaID]; exists {
+		if media, exists := m.mediaByID[mediaID]; exists && media.PostID == postID {
 			media.Position = int32(newPosition)
 		}
 	}
@@ -98,18 +260,20 @@ func (m *MediaRepository) Detach(ctx context.Context, mediaIDs []int64) error {
 	defer m.mu.Unlock()
 
 	for _, mediaID := range mediaIDs {
-		if media, exists := m.mediaByID[mediaID]; exists {
-			for postID, postMedia := range m.mediaByPostID {
-				var updatedMedia []*model.PostMedia
-				for _, pm := range postMedia {
-					if pm.ID != mediaID {
-						updatedMedia = append(updatedMedia, pm)
-					}
-				}
-				m.mediaByPostID[postID] = updatedMedia
-			}
-			delete(m.mediaByID, media.ID)
+		media, exists := m.mediaByID[mediaID]
+		if !exists {
+			continue
+		}
+		remaining := slices.DeleteFunc(m.mediaByPostID[media.PostID], func(pm *model.PostMedia) bool {
+			return pm.ID == mediaID
+		})
+		// A post whose last media is detached has no entry, so GetByPosts leaves it out.
+		if len(remaining) == 0 {
+			delete(m.mediaByPostID, media.PostID)
+		} else {
+			m.mediaByPostID[media.PostID] = remaining
 		}
+		delete(m.mediaByID, mediaID)
 	}
 
 	return nil
@@ -122,8 +286,7 @@ func (m *MediaRepository) GetByPost(ctx context.Context, postID int64) ([]*model
 	if media, exists := m.mediaByPostID[postID]; exists {
 		result := make([]*model.PostMedia, len(media))
 		for i, item := range media {
-			copy := *item
-			result[i] = &copy
+			result[i] = item.Clone()
 		}
 		return result, nil
 	}
@@ -131,6 +294,19 @@ func (m *MediaRepository) GetByPost(ctx context.Context, postID int64) ([]*model
 	return []*model.PostMedia{}, nil
 }
 
+func (m *MediaRepository) GetPageByPost(ctx context.Context, postID int64, limit, offset int) ([]*model.PostMedia, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	media := m.mediaByPostID[postID]
+	page := media[min(offset, len(media)):min(offset+limit, len(media))]
+	result := make([]*model.PostMedia, len(page))
+	for i, item := range page {
+		result[i] = item.Clone()
+	}
+	return result, len(media), nil
+}
+
 func (m *MediaRepository) GetByPosts(ctx context.Context, postIDs []int64) (map[int64][]*model.PostMedia, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -141,8 +317,7 @@ func (m *MediaRepository) GetByPosts(ctx context.Context, postIDs []int64) (map[
 		if media, exists := m.mediaByPostID[postID]; exists {
 			mediaCopy := make([]*model.PostMedia, len(media))
 			for i, item := range media {
-				copy := *item
-				mediaCopy[i] = &copy
+				mediaCopy[i] = item.Clone()
 			}
 			result[postID] = mediaCopy
 		}
@@ -2,6 +2,7 @@ package media_repository_postgres
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	model "pinstack-post-service/internal/domain/models"
 	ports "pinstack-post-service/internal/domain/ports/output"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 )
 
@@ -16,134 +18,257 @@ type MediaRepository struct {
 	log     ports.Logger
 	db      db.PgDB
 	metrics ports.MetricsProvider
+	limits  db.BatchLimits
 }
 
-func NewMediaRepository(db db.PgDB, log ports.Logger, metrics ports.MetricsProvider) *MediaRepository {
-	return &MediaRepository{db: db, log: log, metrics: metrics}
+func NewMediaRepository(pgDB db.PgDB, log ports.Logger, metrics ports.MetricsProvider, limits db.BatchLimits) *MediaRepository {
+	return &MediaRepository{db: pgDB, log: log, metrics: metrics, limits: limits}
 }
 
 func (m *MediaRepository) Attach(ctx context.Context, postID int64, media []*model.PostMedia) (err error) {
-	start := time.Now()
-	defer func() {
-		m.metrics.RecordDatabaseQueryDuration("media_attach", time.Since(start))
-		m.metrics.IncrementDatabaseQueries("media_attach", err == nil)
-	}()
+	defer db.ObserveQuery(m.metrics, m.log, "media_attach", time.Now(), &err, slog.Int64("post_id", postID), slog.Int("count", len(media)))
+
+	if err = db.CheckBatchSize("media", len(media), m.limits.MaxMedia); err != nil {
+		return err
+	}
+	// The CHECK constraint rejects an unknown type too, but only as a failed query.
+	if err = model.ValidateMediaTypes(media); err != nil {
+		return err
+	}
 
 	var exists bool
 	err = m.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = @post_id)`, pgx.NamedArgs{"post_id": postID}).Scan(&exists)
 	if err != nil {
 		m.log.Error("Failed to get post by id in Attach media", slog.Int64("post_id", postID), slog.String("err", err.Error()))
-		return custom_errors.ErrDatabaseQuery
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
 	if !exists {
 		m.log.Warn("Post not found during media attach", slog.Int64("post_id", postID))
 		return custom_errors.ErrPostNotFound
 	}
 
-	batch := &pgx.Batch{}
-	for _, md := range media {
-		batch.Queue(
-			`INSERT INTO post_media (post_id, url, type, position) VALUES (@post_id, @url, @type, @position)`,
-			pgx.NamedArgs{"post_id": postID, "url": md.URL, "type": md.Type, "position": md.Position},
-		)
+	err = db.SendChunked(ctx, m.db, len(media), db.BatchChunkSize,
+		func(b *pgx.Batch, i int) {
+			md := media[i]
+			b.Queue(
+				`INSERT INTO post_media (post_id, url, type, position, width, height, size_bytes, alt_text)
+				VALUES (@post_id, @url, @type, @position, @width, @height, @size_bytes, @alt_text)`,
+				pgx.NamedArgs{
+					"post_id":    postID,
+					"url":        md.URL,
+					"type":       md.Type,
+					"position":   md.Position,
+					"width":      md.Width,
+					"height":     md.Height,
+					"size_bytes": md.SizeBytes,
+					"alt_text":   md.AltText,
+				},
+			)
+		},
+		func(br pgx.BatchResults, i int) error {
+			if _, err := br.Exec(); err != nil {
+				m.log.Error("Media attach failed",
+					slog.String("error", err.Error()),
+					slog.Int64("post_id", postID),
+					slog.Int("index", i))
+				return err
+			}
+			return nil
+		})
+	if err != nil {
+		return mapMediaWriteError(err, custom_errors.ErrMediaAttachFailed)
 	}
+	return nil
+}
 
-	result := m.db.SendBatch(ctx, batch)
-	defer func(result pgx.BatchResults) {
-		err := result.Close()
-		if err != nil {
-			m.log.Error("Failed to close batch result in Attach media", slog.String("error", err.Error()), slog.Int64("post_id", postID))
-		}
-	}(result)
+// AttachMany inserts the media with one INSERT over unnest'ed columns. A missing post fails
+// the foreign key, which maps to ErrPostNotFound as in Attach.
+func (m *MediaRepository) AttachMany(ctx context.Context, media []*model.PostMedia) (err error) {
+	defer db.ObserveQuery(m.metrics, m.log, "media_attach_many", time.Now(), &err, slog.Int("count", len(media)))
+
+	if len(media) == 0 {
+		return nil
+	}
+	if err = model.ValidateMediaTypes(media); err != nil {
+		return err
+	}
 
-	if _, err = result.Exec(); err != nil {
-		m.log.Error("Media attach failed", slog.String("error", err.Error()), slog.Int64("post_id", postID))
-		return custom_errors.ErrMediaAttachFailed
+	var (
+		postIDs   = make([]int64, len(media))
+		urls      = make([]string, len(media))
+		types     = make([]string, len(media))
+		positions = make([]int32, len(media))
+		widths    = make([]*int32, len(media))
+		heights   = make([]*int32, len(media))
+		sizes     = make([]*int64, len(media))
+		altTexts  = make([]*string, len(media))
+	)
+	for i, md := range media {
+		postIDs[i] = md.PostID
+		urls[i] = md.URL
+		types[i] = string(md.Type)
+		positions[i] = md.Position
+		widths[i] = md.Width
+		heights[i] = md.Height
+		sizes[i] = md.SizeBytes
+		altTexts[i] = md.AltText
+	}
+
+	_, err = m.db.Exec(ctx, `
+		INSERT INTO post_media (post_id, url, type, position, width, height, size_bytes, alt_text)
+		SELECT * FROM unnest(@post_ids::bigint[], @urls::text[], @types::text[], @positions::smallint[],
+			@widths::integer[], @heights::integer[], @sizes::bigint[], @alt_texts::text[])`,
+		pgx.NamedArgs{
+			"post_ids":  postIDs,
+			"urls":      urls,
+			"types":     types,
+			"positions": positions,
+			"widths":    widths,
+			"heights":   heights,
+			"sizes":     sizes,
+			"alt_texts": altTexts,
+		})
+	if err != nil {
+		m.log.Error("Media attach failed", slog.Int("count", len(media)), slog.String("error", err.Error()))
+		return mapMediaWriteError(err, custom_errors.ErrMediaAttachFailed)
 	}
 	return nil
 }
 
+// Reorder moves the media in one statement, so positions can be swapped without tripping the
+// (post_id, position) constraint, which is checked at the end of each statement.
 func (m *MediaRepository) Reorder(ctx context.Context, postID int64, newPositions map[int64]int) (err error) {
-	start := time.Now()
-	defer func() {
-		m.metrics.RecordDatabaseQueryDuration("media_reorder", time.Since(start))
-		m.metrics.IncrementDatabaseQueries("media_reorder", err == nil)
-	}()
+	defer db.ObserveQuery(m.metrics, m.log, "media_reorder", time.Now(), &err, slog.Int64("post_id", postID), slog.Int("count", len(newPositions)))
 
-	batch := &pgx.Batch{}
-	for mediaID, position := range newPositions {
-		batch.Queue(
-			`UPDATE post_media SET position = @position WHERE post_id = @post_id AND id = @id`,
-			pgx.NamedArgs{"position": position, "post_id": postID, "id": mediaID},
-		)
+	if err = db.CheckBatchSize("media", len(newPositions), m.limits.MaxMedia); err != nil {
+		return err
 	}
 
-	result := m.db.SendBatch(ctx, batch)
-	defer func(result pgx.BatchResults) {
-		err := result.Close()
-		if err != nil {
-			m.log.Error("Failed to close batch result in Reorder media", slog.String("error", err.Error()), slog.Int64("post_id", postID))
-		}
-	}(result)
+	ids := make([]int64, 0, len(newPositions))
+	positions := make([]int32, 0, len(newPositions))
+	for mediaID, position := range newPositions {
+		ids = append(ids, mediaID)
+		positions = append(positions, int32(position))
+	}
 
-	if _, err = result.Exec(); err != nil {
-		m.log.Error("Media reorder failed", slog.String("error", err.Error()), slog.Int64("post_id", postID))
-		return custom_errors.ErrMediaReorderFailed
+	_, err = m.db.Exec(ctx,
+		`UPDATE post_media pm SET position = np.position
+		FROM unnest(@ids::bigint[], @positions::smallint[]) AS np(id, position)
+		WHERE pm.post_id = @post_id AND pm.id = np.id`,
+		pgx.NamedArgs{"ids": ids, "positions": positions, "post_id": postID},
+	)
+	if err != nil {
+		m.log.Error("Media reorder failed",
+			slog.String("error", err.Error()),
+			slog.Int64("post_id", postID),
+			slog.Any("media_ids", ids))
+		return mapMediaWriteError(err, custom_errors.ErrMediaReorderFailed)
 	}
 	return nil
 }
 
+// mapMediaWriteError translates constraint violations from post_media writes into domain errors.
+func mapMediaWriteError(err error, fallback error) error {
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) {
+		switch pgerr.Code {
+		case "23503":
+			return custom_errors.ErrPostNotFound
+		case "23514":
+			return custom_errors.ErrPostValidation
+		case "23505":
+			return model.ErrMediaDuplicate
+		}
+	}
+	return db.WithCause(fallback, err)
+}
+
 func (m *MediaRepository) Detach(ctx context.Context, mediaIDs []int64) (err error) {
-	start := time.Now()
-	defer func() {
-		m.metrics.RecordDatabaseQueryDuration("media_detach", time.Since(start))
-		m.metrics.IncrementDatabaseQueries("media_detach", err == nil)
-	}()
+	defer db.ObserveQuery(m.metrics, m.log, "media_detach", time.Now(), &err, slog.Int("count", len(mediaIDs)))
+
+	if err = db.CheckBatchSize("media ids", len(mediaIDs), m.limits.MaxDetach); err != nil {
+		return err
+	}
 
 	_, err = m.db.Exec(ctx, `DELETE FROM post_media WHERE id = ANY(@ids)`, pgx.NamedArgs{"ids": mediaIDs})
 	if err != nil {
 		m.log.Error("Media detach failed", slog.String("error", err.Error()), slog.Any("media_ids", mediaIDs))
-		return custom_errors.ErrMediaDetachFailed
+		return db.WithCause(custom_errors.ErrMediaDetachFailed, err)
 	}
 	return nil
 }
 
 func (m *MediaRepository) GetByPost(ctx context.Context, postID int64) (media []*model.PostMedia, err error) {
-	start := time.Now()
-	defer func() {
-		m.metrics.RecordDatabaseQueryDuration("media_get_by_post", time.Since(start))
-		m.metrics.IncrementDatabaseQueries("media_get_by_post", err == nil)
-	}()
+	defer db.ObserveQuery(m.metrics, m.log, "media_get_by_post", time.Now(), &err, slog.Int64("post_id", postID))
 
-	rows, err := m.db.Query(ctx, `SELECT id, url, type, position, created_at FROM post_media WHERE post_id = @postID ORDER BY position`, pgx.NamedArgs{"postID": postID})
+	rows, err := m.db.Query(ctx, `SELECT id, url, type, position, width, height, size_bytes, alt_text, created_at FROM post_media WHERE post_id = @postID ORDER BY position`, pgx.NamedArgs{"postID": postID})
 	if err != nil {
 		m.log.Error("Media query failed", slog.String("error", err.Error()), slog.Int64("post_id", postID))
-		return nil, custom_errors.ErrMediaQueryFailed
+		return nil, db.WithCause(custom_errors.ErrMediaQueryFailed, err)
 	}
 	defer rows.Close()
 
+	media = []*model.PostMedia{}
 	for rows.Next() {
 		var pm model.PostMedia
-		if err := rows.Scan(&pm.ID, &pm.URL, &pm.Type, &pm.Position, &pm.CreatedAt); err != nil {
-			return nil, custom_errors.ErrDatabaseQuery
+		if err := rows.Scan(&pm.ID, &pm.URL, &pm.Type, &pm.Position, &pm.Width, &pm.Height, &pm.SizeBytes, &pm.AltText, &pm.CreatedAt); err != nil {
+			return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 		}
 		media = append(media, &pm)
 	}
+	if err = rows.Err(); err != nil {
+		m.log.Error("Error iterating media rows", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrMediaQueryFailed, err)
+	}
 	m.log.Debug("Retrieved media for post", slog.Int64("post_id", postID), slog.Int("count", len(media)))
 	return media, nil
 }
 
+func (m *MediaRepository) GetPageByPost(ctx context.Context, postID int64, limit, offset int) (media []*model.PostMedia, total int, err error) {
+	defer db.ObserveQuery(m.metrics, m.log, "media_get_page_by_post", time.Now(), &err, slog.Int64("post_id", postID))
+
+	args := pgx.NamedArgs{"postID": postID, "limit": limit, "offset": offset}
+	rows, err := m.db.Query(ctx, `
+		SELECT id, url, type, position, width, height, size_bytes, alt_text, created_at
+		FROM post_media
+		WHERE post_id = @postID
+		ORDER BY position
+		LIMIT @limit OFFSET @offset`, args)
+	if err != nil {
+		m.log.Error("Media page query failed", slog.String("error", err.Error()), slog.Int64("post_id", postID))
+		return nil, 0, db.WithCause(custom_errors.ErrMediaQueryFailed, err)
+	}
+	defer rows.Close()
+
+	media = []*model.PostMedia{}
+	for rows.Next() {
+		pm := model.PostMedia{PostID: postID}
+		if err := rows.Scan(&pm.ID, &pm.URL, &pm.Type, &pm.Position, &pm.Width, &pm.Height, &pm.SizeBytes, &pm.AltText, &pm.CreatedAt); err != nil {
+			return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		media = append(media, &pm)
+	}
+	if err = rows.Err(); err != nil {
+		m.log.Error("Error iterating media page rows", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, 0, db.WithCause(custom_errors.ErrMediaQueryFailed, err)
+	}
+
+	err = m.db.QueryRow(ctx, `SELECT count(*) FROM post_media WHERE post_id = @postID`, pgx.NamedArgs{"postID": postID}).Scan(&total)
+	if err != nil {
+		m.log.Error("Error counting media of post", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, 0, db.WithCause(custom_errors.ErrMediaQueryFailed, err)
+	}
+	m.log.Debug("Retrieved media page for post", slog.Int64("post_id", postID), slog.Int("count", len(media)), slog.Int("total", total))
+	return media, total, nil
+}
+
 func (m *MediaRepository) GetByPosts(ctx context.Context, postIDs []int64) (result map[int64][]*model.PostMedia, err error) {
-	start := time.Now()
-	defer func() {
-		m.metrics.RecordDatabaseQueryDuration("media_get_by_posts", time.Since(start))
-		m.metrics.IncrementDatabaseQueries("media_get_by_posts", err == nil)
-	}()
+	defer db.ObserveQuery(m.metrics, m.log, "media_get_by_posts", time.Now(), &err, slog.Int("count", len(postIDs)))
 
-	rows, err := m.db.Query(ctx, `SELECT post_id, id, url, type, position, created_at FROM post_media WHERE post_id = ANY(@postIDs) ORDER BY post_id, position`, pgx.NamedArgs{"postIDs": postIDs})
+	rows, err := m.db.Query(ctx, `SELECT post_id, id, url, type, position, width, height, size_bytes, alt_text, created_at FROM post_media WHERE post_id = ANY(@postIDs) ORDER BY post_id, position`, pgx.NamedArgs{"postIDs": postIDs})
 	if err != nil {
 		m.log.Error("Batch media query failed", slog.String("error", err.Error()), slog.Any("post_ids", postIDs))
-		return nil, custom_errors.ErrMediaBatchQueryFailed
+		return nil, db.WithCause(custom_errors.ErrMediaBatchQueryFailed, err)
 	}
 	defer rows.Close()
 
@@ -154,8 +279,8 @@ func (m *MediaRepository) GetByPosts(ctx context.Context, postIDs []int64) (resu
 	for rows.Next() {
 		var postID int64
 		var pm model.PostMedia
-		if err := rows.Scan(&postID, &pm.ID, &pm.URL, &pm.Type, &pm.Position, &pm.CreatedAt); err != nil {
-			return nil, custom_errors.ErrDatabaseQuery
+		if err := rows.Scan(&postID, &pm.ID, &pm.URL, &pm.Type, &pm.Position, &pm.Width, &pm.Height, &pm.SizeBytes, &pm.AltText, &pm.CreatedAt); err != nil {
+			return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 		}
 
 		if postID != currentPostID {
@@ -167,6 +292,10 @@ func (m *MediaRepository) GetByPosts(ctx context.Context, postIDs []int64) (resu
 		}
 		mediaGroup = append(mediaGroup, &pm)
 	}
+	if err = rows.Err(); err != nil {
+		m.log.Error("Error iterating batch media rows", slog.Any("post_ids", postIDs), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrMediaBatchQueryFailed, err)
+	}
 
 	if currentPostID != -1 {
 		result[currentPostID] = mediaGroup
@@ -0,0 +1,235 @@
+package media_repository_postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	media_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/media/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+// fakeDB answers the post existence check, returns no rows from queries and replays the given per-command batch errors.
+type fakeDB struct {
+	db.PgDB
+	batchErrs []error
+	batch     *fakeBatchResults
+	queued    *pgx.Batch
+	execErr   error
+	execs     []pgx.NamedArgs
+}
+
+func (f *fakeDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.execs = append(f.execs, args[0].(pgx.NamedArgs))
+	return pgconn.NewCommandTag("UPDATE 3"), f.execErr
+}
+
+func (f *fakeDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return existsRow(true)
+}
+
+func (f *fakeDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return emptyRows{}, nil
+}
+
+func (f *fakeDB) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	f.queued = b
+	f.batch = &fakeBatchResults{errs: f.batchErrs}
+	return f.batch
+}
+
+type existsRow bool
+
+func (r existsRow) Scan(dest ...any) error {
+	*dest[0].(*bool) = bool(r)
+	return nil
+}
+
+// emptyRows is a result set with no rows.
+type emptyRows struct {
+	pgx.Rows
+}
+
+func (emptyRows) Next() bool { return false }
+func (emptyRows) Err() error { return nil }
+func (emptyRows) Close()     {}
+
+type fakeBatchResults struct {
+	pgx.BatchResults
+	errs  []error
+	calls int
+}
+
+func (f *fakeBatchResults) Exec() (pgconn.CommandTag, error) {
+	var err error
+	if f.calls < len(f.errs) {
+		err = f.errs[f.calls]
+	}
+	f.calls++
+	return pgconn.NewCommandTag("INSERT 0 1"), err
+}
+
+func (f *fakeBatchResults) Close() error {
+	return nil
+}
+
+func mediaItems(n int) []*model.PostMedia {
+	items := make([]*model.PostMedia, n)
+	for i := range items {
+		items[i] = &model.PostMedia{URL: "https://example.com/image.jpg", Type: model.MediaTypeImage, Position: int32(i + 1)}
+	}
+	return items
+}
+
+func TestMediaRepository_Attach_BatchErrors(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	tests := []struct {
+		name      string
+		batchErrs []error
+		wantErr   error
+	}{
+		{
+			name:      "all inserts succeed",
+			batchErrs: nil,
+			wantErr:   nil,
+		},
+		{
+			name:      "mid-batch insert fails",
+			batchErrs: []error{nil, nil, errors.New("connection reset"), nil, nil},
+			wantErr:   custom_errors.ErrMediaAttachFailed,
+		},
+		{
+			name:      "check constraint violation",
+			batchErrs: []error{nil, nil, nil, nil, &pgconn.PgError{Code: "23514"}},
+			wantErr:   custom_errors.ErrPostValidation,
+		},
+		{
+			name:      "unique violation",
+			batchErrs: []error{nil, nil, &pgconn.PgError{Code: "23505"}},
+			wantErr:   model.ErrMediaDuplicate,
+		},
+		{
+			name:      "foreign key violation",
+			batchErrs: []error{nil, &pgconn.PgError{Code: "23503"}},
+			wantErr:   custom_errors.ErrPostNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fdb := &fakeDB{batchErrs: tt.batchErrs}
+			repo := media_repository_postgres.NewMediaRepository(fdb, log, metrics, db.DefaultBatchLimits())
+
+			err := repo.Attach(context.Background(), 1, mediaItems(5))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			require.NotNil(t, fdb.batch)
+			assert.Equal(t, 5, fdb.batch.calls, "every queued insert must be checked")
+		})
+	}
+}
+
+func TestMediaRepository_Attach_Metadata(t *testing.T) {
+	fdb := &fakeDB{}
+	repo := media_repository_postgres.NewMediaRepository(fdb, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits())
+
+	width, height, size, alt := int32(1920), int32(1080), int64(204800), "A cat on a keyboard"
+	items := mediaItems(2)
+	items[0].Width, items[0].Height, items[0].SizeBytes, items[0].AltText = &width, &height, &size, &alt
+
+	require.NoError(t, repo.Attach(context.Background(), 1, items))
+	require.NotNil(t, fdb.queued)
+	require.Len(t, fdb.queued.QueuedQueries, 2)
+
+	withMeta := fdb.queued.QueuedQueries[0].Arguments[0].(pgx.NamedArgs)
+	assert.Equal(t, &width, withMeta["width"])
+	assert.Equal(t, &height, withMeta["height"])
+	assert.Equal(t, &size, withMeta["size_bytes"])
+	assert.Equal(t, &alt, withMeta["alt_text"])
+
+	// Missing metadata is stored as NULL rather than zero.
+	withoutMeta := fdb.queued.QueuedQueries[1].Arguments[0].(pgx.NamedArgs)
+	assert.Nil(t, withoutMeta["width"])
+	assert.Nil(t, withoutMeta["alt_text"])
+}
+
+func TestMediaRepository_Reorder(t *testing.T) {
+	log := logger.New("test")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+
+	tests := []struct {
+		name    string
+		execErr error
+		wantErr error
+	}{
+		{name: "success"},
+		{name: "query fails", execErr: errors.New("deadlock detected"), wantErr: custom_errors.ErrMediaReorderFailed},
+		{name: "position taken", execErr: &pgconn.PgError{Code: "23505"}, wantErr: model.ErrMediaDuplicate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fdb := &fakeDB{execErr: tt.execErr}
+			repo := media_repository_postgres.NewMediaRepository(fdb, log, metrics, db.DefaultBatchLimits())
+
+			err := repo.Reorder(context.Background(), 1, map[int64]int{1: 3, 2: 1, 3: 2})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			require.Len(t, fdb.execs, 1, "positions are swapped in a single statement")
+			args := fdb.execs[0]
+			ids, positions := args["ids"].([]int64), args["positions"].([]int32)
+			require.Len(t, ids, 3)
+			moved := make(map[int64]int32, len(ids))
+			for i, id := range ids {
+				moved[id] = positions[i]
+			}
+			assert.Equal(t, map[int64]int32{1: 3, 2: 1, 3: 2}, moved)
+		})
+	}
+}
+
+func TestMediaRepository_GetByPost_NoMedia(t *testing.T) {
+	repo := media_repository_postgres.NewMediaRepository(&fakeDB{}, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits())
+
+	media, err := repo.GetByPost(context.Background(), 1)
+
+	require.NoError(t, err, "a post without media is not an error")
+	assert.NotNil(t, media)
+	assert.Empty(t, media)
+}
+
+func TestMediaRepository_RejectsOversizedBatches(t *testing.T) {
+	fdb := &fakeDB{}
+	repo := media_repository_postgres.NewMediaRepository(fdb, logger.New("test"), prometheus.NewPrometheusMetricsProvider(),
+		db.BatchLimits{MaxMedia: 4, MaxDetach: 6})
+	ctx := context.Background()
+
+	assert.ErrorIs(t, repo.Attach(ctx, 1, mediaItems(5)), custom_errors.ErrInvalidInput)
+	assert.ErrorIs(t, repo.Reorder(ctx, 1, map[int64]int{1: 1, 2: 2, 3: 3, 4: 4, 5: 5}), custom_errors.ErrInvalidInput)
+	assert.ErrorIs(t, repo.Detach(ctx, []int64{1, 2, 3, 4, 5, 6, 7}), custom_errors.ErrInvalidInput)
+	assert.Nil(t, fdb.queued)
+	assert.Empty(t, fdb.execs)
+
+	require.NoError(t, repo.Attach(ctx, 1, mediaItems(4)))
+	assert.Equal(t, 4, fdb.queued.Len())
+	require.NoError(t, repo.Detach(ctx, []int64{1, 2, 3, 4, 5, 6}))
+}
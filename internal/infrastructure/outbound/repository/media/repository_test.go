@@ -148,12 +148,13 @@ func TestMediaRepository_Reorder(t *testing.T) {
 			wantErr: nil,
 		},
 		{
-			name:   "post not found",
+			// Like the postgres repository, media of another post are left alone.
+			name:   "media of another post",
 			postID: 999, // Non-existent post
 			newPositions: map[int64]int{
 				attachedMedia[0].ID: 1,
 			},
-			wantErr: custom_errors.ErrMediaReorderFailed,
+			wantErr: nil,
 		},
 	}
 
@@ -450,3 +451,107 @@ func TestMediaRepository_GetByPosts(t *testing.T) {
 		})
 	}
 }
+
+func TestMediaRepository_Metadata(t *testing.T) {
+	repo, cleanup := setupMediaTest(t)
+	defer cleanup()
+
+	mediaRepo, ok := repo.(*memory.MediaRepository)
+	require.True(t, ok)
+	mediaRepo.SimulatePostExists(1, true)
+
+	width, height, size, alt := int32(800), int32(600), int64(1024), "Sunset over the bay"
+	err := repo.Attach(context.Background(), 1, []*model.PostMedia{
+		{URL: "https://example.com/with-meta.jpg", Type: model.MediaTypeImage, Position: 1, Width: &width, Height: &height, SizeBytes: &size, AltText: &alt},
+		{URL: "https://example.com/without-meta.jpg", Type: model.MediaTypeImage, Position: 2},
+	})
+	require.NoError(t, err)
+
+	got, err := repo.GetByPost(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	byURL := map[string]*model.PostMedia{}
+	for _, m := range got {
+		byURL[m.URL] = m
+	}
+	withMeta := byURL["https://example.com/with-meta.jpg"]
+	require.NotNil(t, withMeta.Width)
+	assert.Equal(t, width, *withMeta.Width)
+	assert.Equal(t, height, *withMeta.Height)
+	assert.Equal(t, size, *withMeta.SizeBytes)
+	assert.Equal(t, alt, *withMeta.AltText)
+
+	withoutMeta := byURL["https://example.com/without-meta.jpg"]
+	assert.Nil(t, withoutMeta.Width)
+	assert.Nil(t, withoutMeta.AltText)
+
+	// Returned media must not alias the stored metadata.
+	*withMeta.AltText = "changed"
+	again, err := repo.GetByPost(context.Background(), 1)
+	require.NoError(t, err)
+	for _, m := range again {
+		if m.AltText != nil {
+			assert.Equal(t, alt, *m.AltText)
+		}
+	}
+}
+
+func TestMediaRepository_Duplicates(t *testing.T) {
+	repo, cleanup := setupMediaTest(t)
+	defer cleanup()
+	mediaRepo := repo.(*memory.MediaRepository)
+	postID := int64(1)
+	mediaRepo.SimulatePostExists(postID, true)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Attach(ctx, postID, []*model.PostMedia{
+		{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+		{URL: "https://example.com/2.jpg", Type: model.MediaTypeImage, Position: 2},
+	}))
+
+	tests := []struct {
+		name  string
+		media []*model.PostMedia
+	}{
+		{name: "position already taken", media: []*model.PostMedia{{URL: "https://example.com/3.jpg", Type: model.MediaTypeImage, Position: 2}}},
+		{name: "url already attached", media: []*model.PostMedia{{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 3}}},
+		{name: "clash within the batch", media: []*model.PostMedia{
+			{URL: "https://example.com/3.jpg", Type: model.MediaTypeImage, Position: 3},
+			{URL: "https://example.com/4.jpg", Type: model.MediaTypeImage, Position: 3},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.Attach(ctx, postID, tt.media)
+
+			assert.ErrorIs(t, err, model.ErrMediaDuplicate)
+			stored, err := repo.GetByPost(ctx, postID)
+			require.NoError(t, err)
+			assert.Len(t, stored, 2, "a rejected attach stores nothing")
+		})
+	}
+
+	t.Run("swap positions", func(t *testing.T) {
+		stored, err := repo.GetByPost(ctx, postID)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Reorder(ctx, postID, map[int64]int{stored[0].ID: 2, stored[1].ID: 1}))
+
+		swapped, err := repo.GetByPost(ctx, postID)
+		require.NoError(t, err)
+		assert.Equal(t, stored[1].ID, swapped[0].ID)
+	})
+
+	t.Run("reorder onto a taken position", func(t *testing.T) {
+		stored, err := repo.GetByPost(ctx, postID)
+		require.NoError(t, err)
+
+		err = repo.Reorder(ctx, postID, map[int64]int{stored[0].ID: 2})
+
+		assert.ErrorIs(t, err, model.ErrMediaDuplicate)
+		unchanged, err := repo.GetByPost(ctx, postID)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), unchanged[0].Position)
+	})
+}
@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"testing"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/conformance"
+)
+
+func setupConformance(t *testing.T) conformance.Repositories {
+	database := NewDatabase(logger.New("test"))
+	return conformance.Repositories{Posts: database.Posts, Tags: database.Tags, Media: database.Media}
+}
+
+func TestPostRepository_Conformance(t *testing.T) {
+	conformance.PostRepository(t, setupConformance)
+}
+
+func TestTagRepository_Conformance(t *testing.T) {
+	conformance.TagRepository(t, setupConformance)
+}
+
+func TestMediaRepository_Conformance(t *testing.T) {
+	conformance.MediaRepository(t, setupConformance)
+}
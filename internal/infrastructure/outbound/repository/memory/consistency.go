@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"context"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// ConsistencyRepository reconciles the in-memory database, where deleting a post removes
+// its tags and media in the same step: it never has orphans.
+type ConsistencyRepository struct{}
+
+func NewConsistencyRepository() *ConsistencyRepository {
+	return &ConsistencyRepository{}
+}
+
+func (c *ConsistencyRepository) CountOrphans(ctx context.Context, kind model.OrphanKind) (int, error) {
+	return 0, nil
+}
+
+func (c *ConsistencyRepository) DeleteOrphans(ctx context.Context, kind model.OrphanKind, limit int) (int, error) {
+	return 0, nil
+}
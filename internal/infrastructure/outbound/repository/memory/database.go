@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	media_memory "pinstack-post-service/internal/infrastructure/outbound/repository/media/memory"
+	post_memory "pinstack-post-service/internal/infrastructure/outbound/repository/post/memory"
+	tag_memory "pinstack-post-service/internal/infrastructure/outbound/repository/tag/memory"
+)
+
+// Database is an in-process stand-in for Postgres: the post, tag and media repositories share
+// their view of which posts exist, deleting a post removes its tags, media and revisions, and
+// the post tag and media filters and detailed reads use the tag and media repositories. Data is lost when the process exits.
+type Database struct {
+	Posts      *post_memory.PostRepository
+	Tags       *tag_memory.TagRepository
+	Media      *media_memory.MediaRepository
+	Moderation *ModerationRepository
+	Revisions  *RevisionRepository
+	UnitOfWork *UnitOfWork
+}
+
+func NewDatabase(log ports.Logger) *Database {
+	posts := post_memory.NewPostRepository(log)
+	tags := tag_memory.NewTagRepository(log)
+	media := media_memory.NewMediaRepository(log)
+	moderation := NewModerationRepository()
+	revisions := NewRevisionRepository(posts.BumpEditCount)
+
+	tags.SetPostLookup(posts.Exists)
+	tags.SetAuthorLookup(posts.AuthorOf)
+	media.SetPostLookup(posts.Exists)
+	posts.SetTagLookup(tags.TagNames)
+	posts.SetMediaLookup(media.MediaTypes)
+	posts.SetDetailLookup(func(postID int64) ([]*model.PostMedia, []*model.Tag) {
+		// Neither lookup fails in memory.
+		postMedia, _ := media.GetByPost(context.Background(), postID)
+		postTags, _ := tags.FindByPost(context.Background(), postID)
+		return postMedia, postTags
+	})
+	posts.SetDeleteHook(func(postID int64) {
+		tags.RemovePost(postID)
+		media.RemovePost(postID)
+		revisions.RemovePost(postID)
+	})
+
+	return &Database{
+		Posts:      posts,
+		Tags:       tags,
+		Media:      media,
+		Moderation: moderation,
+		Revisions:  revisions,
+		UnitOfWork: NewUnitOfWork(posts, tags, media, moderation, revisions, log),
+	}
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// ModerationRepository keeps the moderation log in memory, in the order actions were recorded.
+type ModerationRepository struct {
+	mu      sync.RWMutex
+	actions []*model.ModerationAction
+}
+
+func NewModerationRepository() *ModerationRepository {
+	return &ModerationRepository{}
+}
+
+func (m *ModerationRepository) Record(ctx context.Context, action *model.ModerationAction) (*model.ModerationAction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	recorded := *action
+	recorded.ID = int64(len(m.actions) + 1)
+	recorded.CreatedAt = time.Now()
+	m.actions = append(m.actions, &recorded)
+	result := recorded
+	return &result, nil
+}
+
+// Actions returns copies of every recorded action.
+func (m *ModerationRepository) Actions() []model.ModerationAction {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	actions := make([]model.ModerationAction, len(m.actions))
+	for i, action := range m.actions {
+		actions[i] = *action
+	}
+	return actions
+}
+
+// Snapshot captures the log; calling the returned function drops what was recorded since.
+func (m *ModerationRepository) Snapshot() (restore func()) {
+	m.mu.RLock()
+	n := len(m.actions)
+	m.mu.RUnlock()
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.actions = m.actions[:n]
+	}
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// RevisionRepository keeps post revisions in memory. Record bumps the post's edit count
+// through bumpEditCount, as the Postgres repository does in the same statement.
+type RevisionRepository struct {
+	mu            sync.RWMutex
+	revisions     map[int64][]*model.PostRevision // by post, oldest first
+	nextID        int64
+	bumpEditCount func(postID int64) (int64, bool)
+}
+
+func NewRevisionRepository(bumpEditCount func(postID int64) (int64, bool)) *RevisionRepository {
+	return &RevisionRepository{revisions: make(map[int64][]*model.PostRevision), nextID: 1, bumpEditCount: bumpEditCount}
+}
+
+func (r *RevisionRepository) Record(ctx context.Context, revision *model.PostRevision) (*model.PostRevision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	number, exists := r.bumpEditCount(revision.PostID)
+	if !exists {
+		return nil, custom_errors.ErrPostNotFound
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recorded := *revision
+	recorded.ID = r.nextID
+	recorded.Revision = number
+	recorded.CreatedAt = time.Now()
+	r.nextID++
+	r.revisions[revision.PostID] = append(r.revisions[revision.PostID], &recorded)
+	result := recorded
+	return &result, nil
+}
+
+func (r *RevisionRepository) Prune(ctx context.Context, postID int64, keep int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	revisions := r.revisions[postID]
+	if len(revisions) <= keep {
+		return 0, nil
+	}
+	pruned := len(revisions) - keep
+	r.revisions[postID] = slices.Clone(revisions[pruned:])
+	return pruned, nil
+}
+
+func (r *RevisionRepository) ListByPost(ctx context.Context, postID int64, limit, offset int) ([]*model.PostRevision, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	revisions := r.revisions[postID]
+	page := []*model.PostRevision{}
+	for i := len(revisions) - 1 - offset; i >= 0 && len(page) < limit; i-- {
+		revision := *revisions[i]
+		page = append(page, &revision)
+	}
+	return page, len(revisions), nil
+}
+
+// RemovePost drops the revisions of a deleted post.
+func (r *RevisionRepository) RemovePost(postID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.revisions, postID)
+}
+
+// Snapshot captures the stored revisions; calling the returned function puts them back.
+func (r *RevisionRepository) Snapshot() (restore func()) {
+	r.mu.RLock()
+	saved := make(map[int64][]*model.PostRevision, len(r.revisions))
+	for postID, revisions := range r.revisions {
+		saved[postID] = slices.Clone(revisions)
+	}
+	r.mu.RUnlock()
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.revisions = saved
+	}
+}
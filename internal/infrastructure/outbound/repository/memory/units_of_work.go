@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	archive_repository "pinstack-post-service/internal/domain/ports/output/archive"
+	media_repository "pinstack-post-service/internal/domain/ports/output/media"
+	moderation_repository "pinstack-post-service/internal/domain/ports/output/moderation"
+	post_repository "pinstack-post-service/internal/domain/ports/output/post"
+	revision_repository "pinstack-post-service/internal/domain/ports/output/revision"
+	tag_repository "pinstack-post-service/internal/domain/ports/output/tag"
+	media_memory "pinstack-post-service/internal/infrastructure/outbound/repository/media/memory"
+	post_memory "pinstack-post-service/internal/infrastructure/outbound/repository/post/memory"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	tag_memory "pinstack-post-service/internal/infrastructure/outbound/repository/tag/memory"
+)
+
+// UnitOfWork runs one transaction at a time over the in-memory repositories. Writes are applied
+// directly and Rollback restores a snapshot taken at Begin. Writes made outside a transaction
+// while one is open are lost if it rolls back; isolation options are accepted and ignored.
+type UnitOfWork struct {
+	mu         sync.Mutex
+	posts      *post_memory.PostRepository
+	tags       *tag_memory.TagRepository
+	media      *media_memory.MediaRepository
+	moderation *ModerationRepository
+	revisions  *RevisionRepository
+	log        ports.Logger
+}
+
+func NewUnitOfWork(posts *post_memory.PostRepository, tags *tag_memory.TagRepository, media *media_memory.MediaRepository, moderation *ModerationRepository, revisions *RevisionRepository, log ports.Logger) *UnitOfWork {
+	return &UnitOfWork{posts: posts, tags: tags, media: media, moderation: moderation, revisions: revisions, log: log}
+}
+
+func (u *UnitOfWork) Begin(ctx context.Context) (postgres.Transaction, error) {
+	return u.BeginWithOptions(ctx, postgres.TxOptions{})
+}
+
+func (u *UnitOfWork) BeginWithOptions(ctx context.Context, opts postgres.TxOptions) (postgres.Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	u.mu.Lock()
+	return &Transaction{
+		uow:      u,
+		restores: []func(){u.posts.Snapshot(), u.tags.Snapshot(), u.media.Snapshot(), u.moderation.Snapshot(), u.revisions.Snapshot()},
+	}, nil
+}
+
+type Transaction struct {
+	uow      *UnitOfWork
+	restores []func()
+	done     bool
+}
+
+func (t *Transaction) Commit(ctx context.Context) error {
+	if t.done {
+		return postgres.ErrTxClosed
+	}
+	t.done = true
+	t.uow.mu.Unlock()
+	return nil
+}
+
+// Rollback after Commit does nothing, so callers can defer it unconditionally.
+func (t *Transaction) Rollback(ctx context.Context) error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	for _, restore := range t.restores {
+		restore()
+	}
+	t.uow.log.Debug("Rolled back in-memory transaction")
+	t.uow.mu.Unlock()
+	return nil
+}
+
+func (t *Transaction) PostRepository() post_repository.Repository {
+	return t.uow.posts
+}
+
+func (t *Transaction) MediaRepository() media_repository.Repository {
+	return t.uow.media
+}
+
+func (t *Transaction) TagRepository() tag_repository.Repository {
+	return t.uow.tags
+}
+
+func (t *Transaction) ModerationRepository() moderation_repository.Repository {
+	return t.uow.moderation
+}
+
+func (t *Transaction) RevisionRepository() revision_repository.Repository {
+	return t.uow.revisions
+}
+
+func (t *Transaction) ArchiveRepository() archive_repository.Repository {
+	return NewArchiveRepository(t.uow.log)
+}
+
+// ArchiveRepository is the archive of the in-memory database, which keeps every post live:
+// nothing is ever archived, so nothing is ever found.
+type ArchiveRepository struct {
+	log ports.Logger
+}
+
+func NewArchiveRepository(log ports.Logger) *ArchiveRepository {
+	return &ArchiveRepository{log: log}
+}
+
+func (a *ArchiveRepository) ArchiveOlderThan(ctx context.Context, cutoff time.Time, batchSize int) (int, error) {
+	a.log.Debug("Archiving is not supported by the memory driver", slog.Time("cutoff", cutoff))
+	return 0, nil
+}
+
+func (a *ArchiveRepository) GetByID(ctx context.Context, id int64) (*model.PostDetailed, error) {
+	return nil, custom_errors.ErrPostNotFound
+}
+
+func (a *ArchiveRepository) GetByAuthorAfter(ctx context.Context, authorID int64, afterID int64, limit int) ([]*model.PostDetailed, error) {
+	return []*model.PostDetailed{}, nil
+}
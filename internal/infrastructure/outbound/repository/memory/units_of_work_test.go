@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+)
+
+// writePost creates a post with one tag and one image in a committed transaction.
+func writePost(t *testing.T, ctx context.Context, database *Database) *model.Post {
+	t.Helper()
+	tx, err := database.UnitOfWork.Begin(ctx)
+	require.NoError(t, err)
+	post, err := tx.PostRepository().Create(ctx, &model.Post{AuthorID: 1, Title: "Title"})
+	require.NoError(t, err)
+	_, err = tx.TagRepository().Create(ctx, "go")
+	require.NoError(t, err)
+	require.NoError(t, tx.TagRepository().TagPost(ctx, post.ID, []string{"go"}))
+	require.NoError(t, tx.MediaRepository().Attach(ctx, post.ID, []*model.PostMedia{
+		{URL: "https://example.com/1.jpg", Type: model.MediaTypeImage, Position: 1},
+	}))
+	require.NoError(t, tx.Commit(ctx))
+	return post
+}
+
+func TestUnitOfWork_CommitKeepsWrites(t *testing.T) {
+	ctx := context.Background()
+	database := NewDatabase(logger.New("test"))
+	post := writePost(t, ctx, database)
+
+	got, err := database.Posts.GetByID(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Title", got.Title)
+	tags, err := database.Tags.FindByPost(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Len(t, tags, 1)
+	media, err := database.Media.GetByPost(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Len(t, media, 1)
+}
+
+func TestUnitOfWork_RollbackRestoresEveryRepository(t *testing.T) {
+	ctx := context.Background()
+	database := NewDatabase(logger.New("test"))
+	post := writePost(t, ctx, database)
+	attached, err := database.Media.GetByPost(ctx, post.ID)
+	require.NoError(t, err)
+	require.Len(t, attached, 1)
+
+	tx, err := database.UnitOfWork.Begin(ctx)
+	require.NoError(t, err)
+	title := "Changed"
+	_, err = tx.PostRepository().Update(ctx, post.ID, &model.UpdatePostDTO{Title: &title})
+	require.NoError(t, err)
+	_, err = tx.TagRepository().Create(ctx, "rust")
+	require.NoError(t, err)
+	require.NoError(t, tx.TagRepository().ReplacePostTags(ctx, post.ID, []string{"rust"}))
+	require.NoError(t, tx.MediaRepository().Detach(ctx, []int64{attached[0].ID}))
+	_, err = tx.PostRepository().Create(ctx, &model.Post{AuthorID: 1, Title: "Second"})
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+	require.NoError(t, tx.Rollback(ctx), "a second rollback is a no-op")
+	assert.ErrorIs(t, tx.Commit(ctx), postgres.ErrTxClosed)
+
+	got, err := database.Posts.GetByID(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Title", got.Title)
+	tags, err := database.Tags.FindByPost(ctx, post.ID)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "go", tags[0].Name)
+	found, err := database.Tags.FindByNames(ctx, []string{"rust"})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	media, err := database.Media.GetByPost(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Len(t, media, 1)
+	_, total, err := database.Posts.List(ctx, model.PostFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+}
+
+func TestDatabase_DeleteCascades(t *testing.T) {
+	ctx := context.Background()
+	database := NewDatabase(logger.New("test"))
+	post := writePost(t, ctx, database)
+
+	require.NoError(t, database.Posts.Delete(ctx, post.ID))
+
+	tags, err := database.Tags.FindByPost(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+	media, err := database.Media.GetByPost(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Empty(t, media)
+	assert.ErrorIs(t, database.Tags.TagPost(ctx, post.ID, []string{"go"}), custom_errors.ErrPostNotFound)
+}
+
+func TestDatabase_ListByTagNames(t *testing.T) {
+	ctx := context.Background()
+	database := NewDatabase(logger.New("test"))
+	tagged := writePost(t, ctx, database)
+	_, err := database.Posts.Create(ctx, &model.Post{AuthorID: 1, Title: "Untagged"})
+	require.NoError(t, err)
+
+	posts, total, err := database.Posts.List(ctx, model.PostFilters{TagNames: []string{"go"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, posts, 1)
+	assert.Equal(t, tagged.ID, posts[0].ID)
+}
+
+func TestDatabase_ListByMedia(t *testing.T) {
+	ctx := context.Background()
+	database := NewDatabase(logger.New("test"))
+	create := func(title string, types ...model.MediaType) int64 {
+		post, err := database.Posts.Create(ctx, &model.Post{AuthorID: 1, Title: title})
+		require.NoError(t, err)
+		media := make([]*model.PostMedia, len(types))
+		for i, mediaType := range types {
+			media[i] = &model.PostMedia{URL: fmt.Sprintf("https://example.com/%d/%d", post.ID, i), Type: mediaType, Position: int32(i + 1)}
+		}
+		if len(media) > 0 {
+			require.NoError(t, database.Media.Attach(ctx, post.ID, media))
+		}
+		return post.ID
+	}
+	textOnly := create("Text")
+	photo := create("Photo", model.MediaTypeImage)
+	video := create("Video", model.MediaTypeVideo)
+	mixed := create("Mixed", model.MediaTypeImage, model.MediaTypeVideo)
+	yes, no := true, false
+	image, videoType := model.MediaTypeImage, model.MediaTypeVideo
+
+	tests := []struct {
+		name    string
+		filters model.PostFilters
+		want    []int64
+	}{
+		{name: "no filter", want: []int64{textOnly, photo, video, mixed}},
+		{name: "with media", filters: model.PostFilters{HasMedia: &yes}, want: []int64{photo, video, mixed}},
+		{name: "without media", filters: model.PostFilters{HasMedia: &no}, want: []int64{textOnly}},
+		{name: "images", filters: model.PostFilters{MediaType: &image}, want: []int64{photo, mixed}},
+		{name: "videos", filters: model.PostFilters{MediaType: &videoType}, want: []int64{video, mixed}},
+		{name: "videos with media", filters: model.PostFilters{HasMedia: &yes, MediaType: &videoType}, want: []int64{video, mixed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit := 1
+			filters := tt.filters
+			filters.Limit = &limit
+
+			posts, total, err := database.Posts.List(ctx, filters)
+
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.want), total, "the total counts every match, not the page")
+			require.Len(t, posts, 1)
+			assert.Contains(t, tt.want, posts[0].ID)
+
+			all, _, err := database.Posts.List(ctx, tt.filters)
+			require.NoError(t, err)
+			ids := make([]int64, len(all))
+			for i, post := range all {
+				ids[i] = post.ID
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package moderation_repository_postgres
+
+import (
+	"context"
+	"log/slog"
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+type ModerationRepository struct {
+	log     ports.Logger
+	db      db.PgDB
+	metrics ports.MetricsProvider
+}
+
+func NewModerationRepository(db db.PgDB, log ports.Logger, metrics ports.MetricsProvider) *ModerationRepository {
+	return &ModerationRepository{db: db, log: log, metrics: metrics}
+}
+
+func (m *ModerationRepository) Record(ctx context.Context, action *model.ModerationAction) (result *model.ModerationAction, err error) {
+	defer db.ObserveQuery(m.metrics, m.log, "moderation_record", time.Now(), &err)
+
+	recorded := *action
+	err = m.db.QueryRow(ctx, `
+		INSERT INTO moderation_log (action, post_id, author_id, actor_id, reason)
+		VALUES (@action, @post_id, @author_id, @actor_id, @reason)
+		RETURNING id, created_at`,
+		pgx.NamedArgs{
+			"action":    action.Action,
+			"post_id":   action.PostID,
+			"author_id": action.AuthorID,
+			"actor_id":  action.ActorID,
+			"reason":    action.Reason,
+		},
+	).Scan(&recorded.ID, &recorded.CreatedAt)
+	if err != nil {
+		m.log.Error("Error recording moderation action",
+			slog.String("action", action.Action),
+			slog.Int64("post_id", action.PostID),
+			slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return &recorded, nil
+}
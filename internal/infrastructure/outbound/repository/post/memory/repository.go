@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	ports "pinstack-post-service/internal/domain/ports/output"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -19,6 +20,14 @@ type PostRepository struct {
 	mu     sync.RWMutex
 	posts  map[int64]*model.Post
 	nextID int64
+
+	// tagNames, mediaTypes, details and onDelete connect the repository to the tag and media
+	// repositories of the same in-memory database; all are called without mu held. See
+	// SetTagLookup, SetMediaLookup, SetDetailLookup and SetDeleteHook.
+	tagNames   func(postID int64) []string
+	mediaTypes func(postID int64) []model.MediaType
+	details    func(postID int64) ([]*model.PostMedia, []*model.Tag)
+	onDelete   func(postID int64)
 }
 
 func NewPostRepository(log ports.Logger) *PostRepository {
@@ -29,29 +38,155 @@ func NewPostRepository(log ports.Logger) *PostRepository {
 	}
 }
 
+// SetTagLookup lets List filter by TagNames, using tagNames to read the tags of a post.
+// Without it the TagNames filter matches no post.
+func (p *PostRepository) SetTagLookup(tagNames func(postID int64) []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tagNames = tagNames
+}
+
+// SetMediaLookup lets List filter by HasMedia and MediaType, using mediaTypes to read the
+// types of the media of a post. Without it every post reads as having no media.
+func (p *PostRepository) SetMediaLookup(mediaTypes func(postID int64) []model.MediaType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mediaTypes = mediaTypes
+}
+
+// SetDetailLookup lets GetDetailedByID fill Media and Tags, using details to read them.
+// Without it every post reads as having neither.
+func (p *PostRepository) SetDetailLookup(details func(postID int64) ([]*model.PostMedia, []*model.Tag)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.details = details
+}
+
+// SetDeleteHook registers onDelete to run after a post is deleted, the way ON DELETE CASCADE
+// removes its tags and media in Postgres.
+func (p *PostRepository) SetDeleteHook(onDelete func(postID int64)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onDelete = onDelete
+}
+
+// Snapshot copies the stored posts and returns a function that puts the copy back. Ids are
+// not reused after a restore, as a Postgres sequence is not rolled back either.
+func (p *PostRepository) Snapshot() (restore func()) {
+	p.mu.RLock()
+	saved := make(map[int64]*model.Post, len(p.posts))
+	for id, post := range p.posts {
+		saved[id] = post.Clone()
+	}
+	p.mu.RUnlock()
+
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.posts = saved
+	}
+}
+
+// Exists reports whether a post with id is stored, drafts included.
+func (p *PostRepository) Exists(id int64) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, exists := p.posts[id]
+	return exists
+}
+
+// AuthorOf returns the author of a stored post.
+func (p *PostRepository) AuthorOf(id int64) (int64, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	post, exists := p.posts[id]
+	if !exists {
+		return 0, false
+	}
+	return post.AuthorID, true
+}
+
+// BumpEditCount adds an edit to a stored post and returns its new edit count.
+func (p *PostRepository) BumpEditCount(id int64) (int64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	post, exists := p.posts[id]
+	if !exists {
+		return 0, false
+	}
+	post.EditCount++
+	return post.EditCount, true
+}
+
 func (p *PostRepository) Create(ctx context.Context, post *model.Post) (*model.Post, error) {
 	p.log.Debug("Creating new post (memory impl)", slog.Int64("author_id", post.AuthorID), slog.String("title", post.Title))
 
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	now := pgtype.Timestamp{Time: time.Now(), Valid: true}
+	newPost := p.create(post, time.Now())
+	p.log.Debug("Successfully created post (memory impl)", slog.Int64("id", newPost.ID), slog.Int64("author_id", newPost.AuthorID))
+	return newPost.Clone(), nil
+}
+
+func (p *PostRepository) CreateMany(ctx context.Context, posts []*model.Post) ([]*model.Post, error) {
+	p.log.Debug("Creating posts (memory impl)", slog.Int("count", len(posts)))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	created := make([]*model.Post, 0, len(posts))
+	for _, post := range posts {
+		created = append(created, p.create(post, now).Clone())
+	}
+	return created, nil
+}
+
+// create stores a new post created at now and returns the stored row; p.mu must be held.
+func (p *PostRepository) create(post *model.Post, at time.Time) *model.Post {
+	now := pgtype.Timestamptz{Time: at, Valid: true}
+
+	status := post.Status
+	if status == "" {
+		status = model.PostStatusPublished
+	}
+	var publishedAt pgtype.Timestamptz
+	if status == model.PostStatusPublished {
+		publishedAt = now
+	}
+	visibility := post.Visibility
+	if visibility == "" {
+		visibility = model.PostVisibilityPublic
+	}
+	source := post.Source
+	if source == "" {
+		source = model.PostSourceUnknown
+	}
 
 	newPost := &model.Post{
-		ID:        p.nextID,
-		AuthorID:  post.AuthorID,
-		Title:     post.Title,
-		Content:   post.Content,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:               p.nextID,
+		AuthorID:         post.AuthorID,
+		Title:            post.Title,
+		Content:          post.Content,
+		Status:           status,
+		Visibility:       visibility,
+		Version:          1,
+		Language:         post.Language,
+		Source:           source,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		PublishedAt:      publishedAt,
+		ScheduledAt:      post.ScheduledAt,
+		ModerationStatus: model.ModerationStatusApproved,
+		AuthorUsername:   post.AuthorUsername,
+		AuthorAvatarURL:  post.AuthorAvatarURL,
+		AuthorSnapshotAt: post.AuthorSnapshotAt,
 	}
 	p.nextID++
 
 	p.posts[newPost.ID] = newPost
-
-	p.log.Debug("Successfully created post (memory impl)", slog.Int64("id", newPost.ID), slog.Int64("author_id", newPost.AuthorID))
-	result := *newPost
-	return &result, nil
+	return newPost
 }
 
 func (p *PostRepository) GetByID(ctx context.Context, id int64) (*model.Post, error) {
@@ -68,6 +203,28 @@ func (p *PostRepository) GetByID(ctx context.Context, id int64) (*model.Post, er
 	return &result, nil
 }
 
+func (p *PostRepository) GetDetailedByID(ctx context.Context, id int64) (*model.PostDetailed, error) {
+	post, err := p.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	details := p.details
+	p.mu.RUnlock()
+
+	detailed := &model.PostDetailed{Post: post, Media: []*model.PostMedia{}}
+	if details != nil {
+		detailed.Media, detailed.Tags = details(id)
+	}
+	return detailed, nil
+}
+
+// GetByIDForUpdate has no row locks to take in memory; the repository mutex already serializes writers.
+func (p *PostRepository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Post, error) {
+	return p.GetByID(ctx, id)
+}
+
 func (p *PostRepository) GetByAuthor(ctx context.Context, authorID int64) ([]*model.Post, error) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -80,14 +237,87 @@ func (p *PostRepository) GetByAuthor(ctx context.Context, authorID int64) ([]*mo
 		}
 	}
 
+	slices.SortFunc(result, model.PostFilters{}.ComparePosts)
+
+	return result, nil
+}
+
+func (p *PostRepository) CountPublishedByAuthor(ctx context.Context, authorID int64) (int64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var count int64
+	for _, post := range p.posts {
+		if post.AuthorID == authorID && post.IsVisibleTo(nil) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (p *PostRepository) NewestByTagsSince(ctx context.Context, tagNames []string, since time.Time) (map[string]time.Time, error) {
+	newest := make(map[string]time.Time, len(tagNames))
+	posts, postTags, _ := p.filter(model.PostFilters{})
+	if postTags == nil {
+		return newest, nil
+	}
+	for _, post := range posts {
+		visibleSince := post.VisibleSince()
+		if !visibleSince.After(since) {
+			continue
+		}
+		for _, tag := range postTags(post.ID) {
+			if slices.Contains(tagNames, tag) && visibleSince.After(newest[tag]) {
+				newest[tag] = visibleSince
+			}
+		}
+	}
+	return newest, nil
+}
+
+func (p *PostRepository) GetByIDs(ctx context.Context, ids []int64) ([]*model.Post, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := make([]*model.Post, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if post, exists := p.posts[id]; exists && !seen[id] {
+			seen[id] = true
+			postCopy := *post
+			result = append(result, &postCopy)
+		}
+	}
+	return result, nil
+}
+
+func (p *PostRepository) GetByAuthorAfter(ctx context.Context, authorID int64, afterID int64, limit int) ([]*model.Post, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var result []*model.Post
+	for _, post := range p.posts {
+		if post.AuthorID == authorID && post.ID > afterID {
+			postCopy := *post
+			result = append(result, &postCopy)
+		}
+	}
+
 	sort.Slice(result, func(i, j int) bool {
-		return result[i].CreatedAt.Time.After(result[j].CreatedAt.Time)
+		return result[i].ID < result[j].ID
 	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
 
 	return result, nil
 }
 
 func (p *PostRepository) Update(ctx context.Context, id int64, update *model.UpdatePostDTO) (*model.Post, error) {
+	if !update.ChangesFields() {
+		return nil, custom_errors.ErrNoUpdateRows
+	}
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -95,71 +325,227 @@ func (p *PostRepository) Update(ctx context.Context, id int64, update *model.Upd
 	if !exists {
 		return nil, custom_errors.ErrPostNotFound
 	}
+	if update.ExpectedVersion != nil && post.Version != *update.ExpectedVersion {
+		return nil, &model.VersionConflictError{PostID: id, CurrentVersion: post.Version}
+	}
 
+	// Like the postgres repository, empty values leave the field unchanged, except for the
+	// title, which they clear.
 	if update.Title != nil {
 		post.Title = *update.Title
 	}
-	if update.Content != nil {
+	if update.Content != nil && *update.Content != "" {
 		post.Content = update.Content
 	}
+	if update.Visibility != nil && *update.Visibility != "" {
+		post.Visibility = *update.Visibility
+	}
+	if update.Language != nil && *update.Language != "" {
+		language := *update.Language
+		post.Language = &language
+	}
 
-	post.UpdatedAt = pgtype.Timestamp{Time: time.Now(), Valid: true}
+	post.UpdatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	post.Version++
 
 	result := *post
 	return &result, nil
 }
 
-func (p *PostRepository) Delete(ctx context.Context, id int64) error {
+func (p *PostRepository) Touch(ctx context.Context, id int64) (*model.Post, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	post, exists := p.posts[id]
+	if !exists {
+		return nil, custom_errors.ErrPostNotFound
+	}
+	post.UpdatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	post.Version++
+
+	result := *post
+	return &result, nil
+}
+
+func (p *PostRepository) Delete(ctx context.Context, id int64) error {
+	p.mu.Lock()
 	if _, exists := p.posts[id]; !exists {
+		p.mu.Unlock()
 		return custom_errors.ErrPostNotFound
 	}
-
 	delete(p.posts, id)
+	onDelete := p.onDelete
+	p.mu.Unlock()
+
+	if onDelete != nil {
+		onDelete(id)
+	}
 	return nil
 }
 
+func (p *PostRepository) Publish(ctx context.Context, id int64) (*model.Post, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	post, exists := p.posts[id]
+	if !exists {
+		return nil, custom_errors.ErrPostNotFound
+	}
+
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	post.Status = model.PostStatusPublished
+	post.PublishedAt = now
+	post.UpdatedAt = now
+	post.ScheduledAt = pgtype.Timestamptz{}
+	post.Version++
+
+	result := *post
+	return &result, nil
+}
+
+func (p *PostRepository) PublishDue(ctx context.Context, now time.Time, limit int) ([]*model.Post, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var due []*model.Post
+	for _, post := range p.posts {
+		if post.Status == model.PostStatusScheduled && !post.ScheduledAt.Time.After(now) {
+			due = append(due, post)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].ScheduledAt.Time.Equal(due[j].ScheduledAt.Time) {
+			return due[i].ScheduledAt.Time.Before(due[j].ScheduledAt.Time)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	published := make([]*model.Post, len(due))
+	for i, post := range due {
+		post.Status = model.PostStatusPublished
+		post.PublishedAt = post.ScheduledAt
+		post.UpdatedAt = pgtype.Timestamptz{Time: now, Valid: true}
+		post.ScheduledAt = pgtype.Timestamptz{}
+		post.Version++
+		result := *post
+		published[i] = &result
+	}
+	return published, nil
+}
+
+func (p *PostRepository) CancelSchedule(ctx context.Context, id int64) (*model.Post, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	post, exists := p.posts[id]
+	if !exists || post.Status != model.PostStatusScheduled {
+		return nil, custom_errors.ErrPostNotFound
+	}
+
+	post.Status = model.PostStatusDraft
+	post.ScheduledAt = pgtype.Timestamptz{}
+	post.UpdatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	post.Version++
+
+	result := *post
+	return &result, nil
+}
+
+func (p *PostRepository) Pin(ctx context.Context, id int64, now time.Time) (*model.Post, error) {
+	return p.setPinned(id, pgtype.Timestamptz{Time: now, Valid: true})
+}
+
+func (p *PostRepository) Unpin(ctx context.Context, id int64) (*model.Post, error) {
+	return p.setPinned(id, pgtype.Timestamptz{})
+}
+
+func (p *PostRepository) setPinned(id int64, pinnedAt pgtype.Timestamptz) (*model.Post, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	post, exists := p.posts[id]
+	if !exists {
+		return nil, custom_errors.ErrPostNotFound
+	}
+	post.PinnedAt = pinnedAt
+
+	result := *post
+	return &result, nil
+}
+
+func (p *PostRepository) SetModerationStatus(ctx context.Context, id int64, status model.ModerationStatus, reason *model.RejectionReason, now time.Time) (*model.Post, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	post, exists := p.posts[id]
+	if !exists {
+		return nil, custom_errors.ErrPostNotFound
+	}
+	// Like the check constraint in Postgres.
+	if (status == model.ModerationStatusRejected) != (reason != nil) {
+		return nil, custom_errors.ErrPostValidation
+	}
+	post.ModerationStatus = status
+	post.RejectionReason = reason
+	post.UpdatedAt = pgtype.Timestamptz{Time: now, Valid: true}
+
+	return post.Clone(), nil
+}
+
+func (p *PostRepository) RefreshAuthorSnapshot(ctx context.Context, author *model.User, at time.Time) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var count int64
+	for _, post := range p.posts {
+		if post.AuthorID != author.ID || (post.AuthorSnapshotAt.Valid && !post.AuthorSnapshotAt.Time.Before(at)) {
+			continue
+		}
+		post.SetAuthorSnapshot(author, at)
+		count++
+	}
+	return count, nil
+}
+
+func (p *PostRepository) UnpinAuthor(ctx context.Context, authorID int64) ([]int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var ids []int64
+	for _, post := range p.posts {
+		if post.AuthorID == authorID && post.IsPinned() {
+			post.PinnedAt = pgtype.Timestamptz{}
+			ids = append(ids, post.ID)
+		}
+	}
+	return ids, nil
+}
+
 func (p *PostRepository) List(ctx context.Context, filters model.PostFilters) ([]*model.Post, int, error) {
 	p.log.Debug("Listing posts with filters (memory impl)",
 		slog.Any("author_id", filters.AuthorID),
 		slog.Any("created_after", filters.CreatedAfter),
 		slog.Any("created_before", filters.CreatedBefore),
+		slog.Any("updated_after", filters.UpdatedAfter),
 		slog.Any("tag_names", filters.TagNames),
+		slog.Any("has_media", filters.HasMedia),
+		slog.Any("media_type", filters.MediaType),
 		slog.Any("limit", filters.Limit),
 		slog.Any("offset", filters.Offset))
 
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
-	var filteredPosts []*model.Post
-	for _, post := range p.posts {
-		if filters.AuthorID != nil && post.AuthorID != *filters.AuthorID {
-			p.log.Debug("Skipping post: author ID doesn't match", slog.Int64("post_id", post.ID),
-				slog.Int64("post_author", post.AuthorID), slog.Int64("filter_author", *filters.AuthorID))
-			continue
-		}
-		if filters.CreatedAfter != nil && (post.CreatedAt.Time.Before(filters.CreatedAfter.Time) || post.CreatedAt.Time.Equal(filters.CreatedAfter.Time)) {
-			p.log.Debug("Skipping post: creation time not after filter", slog.Int64("post_id", post.ID),
-				slog.Time("post_time", post.CreatedAt.Time), slog.Time("filter_time", filters.CreatedAfter.Time))
-			continue
-		}
-		if filters.CreatedBefore != nil && post.CreatedAt.Time.After(filters.CreatedBefore.Time) {
-			p.log.Debug("Skipping post: creation time after filter", slog.Int64("post_id", post.ID),
-				slog.Time("post_time", post.CreatedAt.Time), slog.Time("filter_time", filters.CreatedBefore.Time))
-			continue
-		}
-		// TagNames filtering not implemented in memory repository
-
-		p.log.Debug("Post passed all filters", slog.Int64("post_id", post.ID))
-		postCopy := *post
-		filteredPosts = append(filteredPosts, &postCopy)
+	filteredPosts, tagNames, mediaTypes := p.filter(filters)
+	if len(filters.TagNames) > 0 {
+		filteredPosts = filterByTags(filteredPosts, filters.TagNames, tagNames)
+	}
+	if filters.HasMedia != nil || filters.MediaType != nil {
+		filteredPosts = filterByMedia(filteredPosts, filters.HasMedia, filters.MediaType, mediaTypes)
 	}
 
-	sort.Slice(filteredPosts, func(i, j int) bool {
-		return filteredPosts[i].CreatedAt.Time.After(filteredPosts[j].CreatedAt.Time)
-	})
+	// Like the postgres repository, an author's list starts with their pinned post.
+	slices.SortFunc(filteredPosts, filters.ComparePosts)
 
 	total := len(filteredPosts)
 	p.log.Debug("Total matching posts before pagination", slog.Int("total", total))
@@ -188,3 +574,101 @@ func (p *PostRepository) List(ctx context.Context, filters model.PostFilters) ([
 	p.log.Debug("Returning filtered posts", slog.Int("count", len(filteredPosts)), slog.Int("total", total))
 	return filteredPosts, total, nil
 }
+
+// filter applies every filter but TagNames, HasMedia and MediaType and returns copies of the
+// matching posts with the tag and media lookups, so those are read after mu is released.
+func (p *PostRepository) filter(filters model.PostFilters) ([]*model.Post, func(int64) []string, func(int64) []model.MediaType) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var filteredPosts []*model.Post
+	for _, post := range p.posts {
+		if filters.AuthorID != nil && post.AuthorID != *filters.AuthorID {
+			p.log.Debug("Skipping post: author ID doesn't match", slog.Int64("post_id", post.ID),
+				slog.Int64("post_author", post.AuthorID), slog.Int64("filter_author", *filters.AuthorID))
+			continue
+		}
+		if len(filters.AuthorIDs) > 0 && !slices.Contains(filters.AuthorIDs, post.AuthorID) {
+			p.log.Debug("Skipping post: author not in filter", slog.Int64("post_id", post.ID), slog.Int64("post_author", post.AuthorID))
+			continue
+		}
+		if slices.Contains(filters.ExcludedAuthorIDs, post.AuthorID) {
+			p.log.Debug("Skipping post: author excluded", slog.Int64("post_id", post.ID), slog.Int64("post_author", post.AuthorID))
+			continue
+		}
+		if !post.IsVisibleTo(filters.RequesterID) {
+			p.log.Debug("Skipping post: draft not visible to requester", slog.Int64("post_id", post.ID))
+			continue
+		}
+		if post.IsRejected() && (filters.RequesterID == nil || *filters.RequesterID != post.AuthorID) {
+			p.log.Debug("Skipping post: rejected", slog.Int64("post_id", post.ID))
+			continue
+		}
+		if !post.IsListed() && !filters.ListsOwnPosts() {
+			p.log.Debug("Skipping post: not listed", slog.Int64("post_id", post.ID), slog.String("visibility", string(post.Visibility)))
+			continue
+		}
+		if filters.CreatedAfter != nil && (post.CreatedAt.Time.Before(filters.CreatedAfter.Time) || post.CreatedAt.Time.Equal(filters.CreatedAfter.Time)) {
+			p.log.Debug("Skipping post: creation time not after filter", slog.Int64("post_id", post.ID),
+				slog.Time("post_time", post.CreatedAt.Time), slog.Time("filter_time", filters.CreatedAfter.Time))
+			continue
+		}
+		if filters.CreatedBefore != nil && !post.CreatedAt.Time.Before(filters.CreatedBefore.Time) {
+			p.log.Debug("Skipping post: creation time not before filter", slog.Int64("post_id", post.ID),
+				slog.Time("post_time", post.CreatedAt.Time), slog.Time("filter_time", filters.CreatedBefore.Time))
+			continue
+		}
+		if filters.Language != nil && (post.Language == nil || *post.Language != *filters.Language) {
+			p.log.Debug("Skipping post: language doesn't match", slog.Int64("post_id", post.ID))
+			continue
+		}
+		if filters.Source != nil && post.Source != *filters.Source {
+			p.log.Debug("Skipping post: source doesn't match", slog.Int64("post_id", post.ID))
+			continue
+		}
+		if filters.UpdatedAfter != nil && !post.UpdatedAt.Time.After(filters.UpdatedAfter.Time) {
+			p.log.Debug("Skipping post: update time not after filter", slog.Int64("post_id", post.ID),
+				slog.Time("post_time", post.UpdatedAt.Time), slog.Time("filter_time", filters.UpdatedAfter.Time))
+			continue
+		}
+		p.log.Debug("Post passed all filters", slog.Int64("post_id", post.ID))
+		postCopy := *post
+		filteredPosts = append(filteredPosts, &postCopy)
+	}
+	return filteredPosts, p.tagNames, p.mediaTypes
+}
+
+// filterByTags keeps the posts with at least one of names, compared case-insensitively as the
+// postgres repository does.
+func filterByTags(posts []*model.Post, names []string, tagNames func(int64) []string) []*model.Post {
+	if tagNames == nil {
+		return nil
+	}
+	var matched []*model.Post
+	for _, post := range posts {
+		if slices.ContainsFunc(tagNames(post.ID), func(tag string) bool { return slices.Contains(names, tag) }) {
+			matched = append(matched, post)
+		}
+	}
+	return matched
+}
+
+// filterByMedia keeps the posts with media when hasMedia is true, without media when it is
+// false, and with at least one item of mediaType when that is set.
+func filterByMedia(posts []*model.Post, hasMedia *bool, mediaType *model.MediaType, mediaTypes func(int64) []model.MediaType) []*model.Post {
+	var matched []*model.Post
+	for _, post := range posts {
+		var types []model.MediaType
+		if mediaTypes != nil {
+			types = mediaTypes(post.ID)
+		}
+		if hasMedia != nil && *hasMedia != (len(types) > 0) {
+			continue
+		}
+		if mediaType != nil && !slices.Contains(types, *mediaType) {
+			continue
+		}
+		matched = append(matched, post)
+	}
+	return matched
+}
@@ -0,0 +1,109 @@
+package post_repository_postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+// detailedStatements read a post, its media and its tags. They are sent as one batch, so a
+// single post costs one round trip instead of the three of GetByID, GetByPost and FindByPost,
+// while the media and tag queries stay those of the media and tag repositories.
+var detailedStatements = []string{
+	`SELECT ` + postColumns + `
+		FROM posts WHERE id = @id`,
+	`SELECT id, url, type, position, width, height, size_bytes, alt_text, created_at
+		FROM post_media WHERE post_id = @id ORDER BY position`,
+	`SELECT t.id, t.name
+		FROM tags t
+		INNER JOIN posts_tags pt ON pt.tag_id = t.id
+		WHERE pt.post_id = @id
+		ORDER BY t.name`,
+}
+
+func (p *PostRepository) GetDetailedByID(ctx context.Context, id int64) (result *model.PostDetailed, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_get_detailed_by_id", time.Now(), &err, slog.Int64("post_id", id))
+
+	p.log.Debug("Getting detailed post by ID", slog.Int64("id", id))
+
+	batch := &pgx.Batch{}
+	args := pgx.NamedArgs{"id": id}
+	for _, stmt := range detailedStatements {
+		batch.Queue(stmt, args)
+	}
+
+	results := p.db.SendBatch(ctx, batch)
+	defer func(results pgx.BatchResults) {
+		if err := results.Close(); err != nil {
+			p.log.Error("Failed to close batch result in GetDetailedByID", slog.String("error", err.Error()))
+		}
+	}(results)
+
+	post, err := scanPost(results.QueryRow())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.Debug("Post not found by id", slog.Int64("id", id))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		p.log.Error("Error getting post by id", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	media, err := scanMedia(results)
+	if err != nil {
+		p.log.Error("Media query failed", slog.Int64("post_id", id), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrMediaQueryFailed, err)
+	}
+
+	tags, err := scanTags(results)
+	if err != nil {
+		p.log.Error("Error finding tags by post", slog.Int64("post_id", id), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+
+	p.log.Debug("Successfully retrieved detailed post by ID", slog.Int64("id", id), slog.Int("media", len(media)), slog.Int("tags", len(tags)))
+	return &model.PostDetailed{Post: post, Media: media, Tags: tags}, nil
+}
+
+func scanMedia(results pgx.BatchResults) ([]*model.PostMedia, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	media := []*model.PostMedia{}
+	for rows.Next() {
+		var pm model.PostMedia
+		if err := rows.Scan(&pm.ID, &pm.URL, &pm.Type, &pm.Position, &pm.Width, &pm.Height, &pm.SizeBytes, &pm.AltText, &pm.CreatedAt); err != nil {
+			return nil, err
+		}
+		media = append(media, &pm)
+	}
+	return media, rows.Err()
+}
+
+func scanTags(results pgx.BatchResults) ([]*model.Tag, error) {
+	rows, err := results.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*model.Tag
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+	return tags, rows.Err()
+}
@@ -0,0 +1,174 @@
+package post_repository_postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	media_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/media/postgres"
+	post_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/post/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	tag_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/tag/postgres"
+)
+
+// roundTripDB answers every query with postErr for single rows and rows of ids, filling the
+// first column only, and counts the round trips; any other call panics via the nil embedded
+// PgDB.
+type roundTripDB struct {
+	db.PgDB
+	postErr    error
+	mediaErr   error
+	ids        []int64
+	queued     *pgx.Batch
+	roundTrips int
+}
+
+func (f *roundTripDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.roundTrips++
+	return idRow{err: f.postErr}
+}
+
+func (f *roundTripDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	f.roundTrips++
+	return &idRows{ids: f.ids}, nil
+}
+
+func (f *roundTripDB) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	f.roundTrips++
+	f.queued = b
+	return &roundTripBatch{db: f}
+}
+
+type roundTripBatch struct {
+	pgx.BatchResults
+	db      *roundTripDB
+	queries int
+}
+
+func (b *roundTripBatch) QueryRow() pgx.Row {
+	return idRow{err: b.db.postErr}
+}
+
+func (b *roundTripBatch) Query() (pgx.Rows, error) {
+	b.queries++
+	if b.queries == 1 && b.db.mediaErr != nil {
+		return nil, b.db.mediaErr
+	}
+	return &idRows{ids: b.db.ids}, nil
+}
+
+func (b *roundTripBatch) Close() error {
+	return nil
+}
+
+type idRow struct {
+	err error
+}
+
+func (r idRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int64) = 1
+	return nil
+}
+
+type idRows struct {
+	pgx.Rows
+	ids  []int64
+	next int
+}
+
+func (r *idRows) Next() bool {
+	r.next++
+	return r.next <= len(r.ids)
+}
+
+func (r *idRows) Scan(dest ...any) error {
+	*dest[0].(*int64) = r.ids[r.next-1]
+	return nil
+}
+
+func (r *idRows) Close()     {}
+func (r *idRows) Err() error { return nil }
+
+func newDetailedRepo(fdb *roundTripDB) *post_repository_postgres.PostRepository {
+	return post_repository_postgres.NewPostRepository(fdb, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+}
+
+func TestPostRepository_GetDetailedByID(t *testing.T) {
+	fdb := &roundTripDB{ids: []int64{7, 3}}
+
+	got, err := newDetailedRepo(fdb).GetDetailedByID(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, fdb.roundTrips)
+	require.Len(t, fdb.queued.QueuedQueries, 3)
+	assert.Contains(t, fdb.queued.QueuedQueries[1].SQL, "ORDER BY position")
+	assert.Contains(t, fdb.queued.QueuedQueries[2].SQL, "ORDER BY t.name")
+	assert.Equal(t, int64(1), got.Post.ID)
+	require.Len(t, got.Media, 2)
+	assert.Equal(t, int64(7), got.Media[0].ID, "rows keep the order of the query")
+	require.Len(t, got.Tags, 2)
+	assert.Equal(t, int64(3), got.Tags[1].ID)
+	assert.Nil(t, got.Author)
+}
+
+func TestPostRepository_GetDetailedByID_NoMediaNoTags(t *testing.T) {
+	got, err := newDetailedRepo(&roundTripDB{}).GetDetailedByID(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.NotNil(t, got.Media)
+	assert.Empty(t, got.Media)
+	assert.Nil(t, got.Tags)
+}
+
+func TestPostRepository_GetDetailedByID_Errors(t *testing.T) {
+	_, err := newDetailedRepo(&roundTripDB{postErr: pgx.ErrNoRows}).GetDetailedByID(context.Background(), 1)
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+
+	_, err = newDetailedRepo(&roundTripDB{postErr: errors.New("connection reset")}).GetDetailedByID(context.Background(), 1)
+	assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+
+	_, err = newDetailedRepo(&roundTripDB{mediaErr: errors.New("connection reset")}).GetDetailedByID(context.Background(), 1)
+	assert.ErrorIs(t, err, custom_errors.ErrMediaQueryFailed)
+}
+
+// The benchmarks report round trips per single-post read: three with the granular post,
+// media and tag reads, one with GetDetailedByID.
+func BenchmarkGetPost_Granular(b *testing.B) {
+	fdb := &roundTripDB{ids: []int64{1, 2}}
+	log := logger.New("error")
+	metrics := prometheus.NewPrometheusMetricsProvider()
+	posts := post_repository_postgres.NewPostRepository(fdb, log, metrics)
+	media := media_repository_postgres.NewMediaRepository(fdb, log, metrics, db.DefaultBatchLimits())
+	tags := tag_repository_postgres.NewTagRepository(fdb, log, metrics, db.DefaultBatchLimits(), 0)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = posts.GetByID(ctx, 1)
+		_, _ = media.GetByPost(ctx, 1)
+		_, _ = tags.FindByPost(ctx, 1)
+	}
+	b.ReportMetric(float64(fdb.roundTrips)/float64(b.N), "roundtrips/op")
+}
+
+func BenchmarkGetPost_Detailed(b *testing.B) {
+	fdb := &roundTripDB{ids: []int64{1, 2}}
+	posts := post_repository_postgres.NewPostRepository(fdb, logger.New("error"), prometheus.NewPrometheusMetricsProvider())
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = posts.GetDetailedByID(ctx, 1)
+	}
+	b.ReportMetric(float64(fdb.roundTrips)/float64(b.N), "roundtrips/op")
+}
@@ -1,12 +1,14 @@
 package post_repository_postgres
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	ports "pinstack-post-service/internal/domain/ports/output"
 	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	"slices"
 	"strings"
 	"time"
 
@@ -29,145 +31,394 @@ func NewPostRepository(db db.PgDB, log ports.Logger, metrics ports.MetricsProvid
 }
 
 func (p *PostRepository) Create(ctx context.Context, post *model.Post) (result *model.Post, err error) {
-	start := time.Now()
-	defer func() {
-		p.metrics.RecordDatabaseQueryDuration("post_create", time.Since(start))
-		p.metrics.IncrementDatabaseQueries("post_create", err == nil)
-	}()
+	defer db.ObserveQuery(p.metrics, p.log, "post_create", time.Now(), &err, slog.Int64("author_id", post.AuthorID))
 
 	p.log.Debug("Creating new post", slog.Int64("author_id", post.AuthorID), slog.String("title", post.Title))
 
 	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
 
+	status := post.Status
+	if status == "" {
+		status = model.PostStatusPublished
+	}
+	publishedAt := pgtype.Timestamptz{}
+	if status == model.PostStatusPublished {
+		publishedAt = now
+	}
+	visibility := post.Visibility
+	if visibility == "" {
+		visibility = model.PostVisibilityPublic
+	}
+	source := post.Source
+	if source == "" {
+		source = model.PostSourceUnknown
+	}
+
 	args := pgx.NamedArgs{
-		"author_id":  post.AuthorID,
-		"title":      post.Title,
-		"content":    post.Content,
-		"created_at": now,
-		"updated_at": now,
+		"author_id":          post.AuthorID,
+		"title":              post.Title,
+		"content":            post.Content,
+		"status":             status,
+		"visibility":         visibility,
+		"created_at":         now,
+		"updated_at":         now,
+		"published_at":       publishedAt,
+		"scheduled_at":       post.ScheduledAt,
+		"lang":               post.Language,
+		"source":             source,
+		"author_username":    post.AuthorUsername,
+		"author_avatar_url":  post.AuthorAvatarURL,
+		"author_snapshot_at": post.AuthorSnapshotAt,
 	}
 
 	query := `
-		INSERT INTO posts (author_id, title, content, created_at, updated_at)
-		VALUES (@author_id, @title, @content, @created_at, @updated_at)
-		RETURNING id, author_id, title, content, created_at, updated_at`
-
-	var createdPost model.Post
-	err = p.db.QueryRow(ctx, query, args).Scan(
-		&createdPost.ID,
-		&createdPost.AuthorID,
-		&createdPost.Title,
-		&createdPost.Content,
-		&createdPost.CreatedAt,
-		&createdPost.UpdatedAt,
-	)
+		INSERT INTO posts (author_id, title, content, status, visibility, created_at, updated_at, published_at, scheduled_at, lang, source, author_username, author_avatar_url, author_snapshot_at)
+		VALUES (@author_id, @title, @content, @status, @visibility, @created_at, @updated_at, @published_at, @scheduled_at, @lang, @source, @author_username, @author_avatar_url, @author_snapshot_at)
+		RETURNING ` + postColumns
+
+	createdPost, err := scanPost(p.db.QueryRow(ctx, query, args))
 
 	if err != nil {
 		p.log.Error("Error creating post", slog.String("error", err.Error()))
-		return nil, custom_errors.ErrDatabaseQuery
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
 
 	p.log.Debug("Successfully created post", slog.Int64("id", createdPost.ID), slog.Int64("author_id", createdPost.AuthorID))
-	return &createdPost, nil
+	return createdPost, nil
+}
+
+// CreateMany inserts the posts with one INSERT over unnest'ed columns. Ids are drawn in the
+// order of the rows selected, so the returned rows, sorted by id, line up with posts.
+func (p *PostRepository) CreateMany(ctx context.Context, posts []*model.Post) (result []*model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_create_many", time.Now(), &err, slog.Int("count", len(posts)))
+
+	if len(posts) == 0 {
+		return []*model.Post{}, nil
+	}
+	p.log.Debug("Creating posts", slog.Int("count", len(posts)))
+
+	var (
+		authorIDs    = make([]int64, len(posts))
+		titles       = make([]string, len(posts))
+		contents     = make([]*string, len(posts))
+		statuses     = make([]string, len(posts))
+		visibilities = make([]string, len(posts))
+		scheduledAts = make([]pgtype.Timestamptz, len(posts))
+		langs        = make([]*string, len(posts))
+		sources      = make([]string, len(posts))
+		usernames    = make([]*string, len(posts))
+		avatarURLs   = make([]*string, len(posts))
+		snapshotAts  = make([]pgtype.Timestamptz, len(posts))
+	)
+	for i, post := range posts {
+		status := post.Status
+		if status == "" {
+			status = model.PostStatusPublished
+		}
+		visibility := post.Visibility
+		if visibility == "" {
+			visibility = model.PostVisibilityPublic
+		}
+		authorIDs[i] = post.AuthorID
+		titles[i] = post.Title
+		contents[i] = post.Content
+		statuses[i] = string(status)
+		visibilities[i] = string(visibility)
+		scheduledAts[i] = post.ScheduledAt
+		langs[i] = post.Language
+		sources[i] = string(model.PostSourceUnknown)
+		if post.Source != "" {
+			sources[i] = string(post.Source)
+		}
+		usernames[i] = post.AuthorUsername
+		avatarURLs[i] = post.AuthorAvatarURL
+		snapshotAts[i] = post.AuthorSnapshotAt
+	}
+
+	args := pgx.NamedArgs{
+		"now":          pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		"author_ids":   authorIDs,
+		"titles":       titles,
+		"contents":     contents,
+		"statuses":     statuses,
+		"visibilities": visibilities,
+		"scheduled_at": scheduledAts,
+		"langs":        langs,
+		"sources":      sources,
+		"usernames":    usernames,
+		"avatar_urls":  avatarURLs,
+		"snapshot_ats": snapshotAts,
+	}
+	query := `
+		INSERT INTO posts (author_id, title, content, status, visibility, created_at, updated_at, published_at, scheduled_at, lang, source, author_username, author_avatar_url, author_snapshot_at)
+		SELECT i.author_id, i.title, i.content, i.status, i.visibility, @now, @now,
+			CASE WHEN i.status = 'published' THEN @now::timestamptz END, i.scheduled_at, i.lang, i.source,
+			i.author_username, i.author_avatar_url, i.author_snapshot_at
+		FROM unnest(@author_ids::bigint[], @titles::text[], @contents::text[], @statuses::text[],
+			@visibilities::text[], @scheduled_at::timestamptz[], @langs::text[], @sources::text[],
+			@usernames::text[], @avatar_urls::text[], @snapshot_ats::timestamptz[])
+			WITH ORDINALITY AS i(author_id, title, content, status, visibility, scheduled_at, lang, source, author_username, author_avatar_url, author_snapshot_at, ord)
+		ORDER BY i.ord
+		RETURNING ` + postColumns
+
+	rows, err := p.db.Query(ctx, query, args)
+	if err != nil {
+		p.log.Error("Error creating posts", slog.Int("count", len(posts)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	created, err := scanPosts(rows)
+	if err != nil {
+		p.log.Error("Error creating posts", slog.Int("count", len(posts)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	if len(created) != len(posts) {
+		p.log.Error("Created a different number of posts than asked", slog.Int("asked", len(posts)), slog.Int("created", len(created)))
+		return nil, custom_errors.ErrDatabaseQuery
+	}
+	slices.SortFunc(created, func(a, b *model.Post) int { return cmp.Compare(a.ID, b.ID) })
+
+	p.log.Debug("Successfully created posts", slog.Int("count", len(created)))
+	return created, nil
 }
 
 func (p *PostRepository) GetByID(ctx context.Context, id int64) (result *model.Post, err error) {
-	start := time.Now()
-	defer func() {
-		p.metrics.RecordDatabaseQueryDuration("post_get_by_id", time.Since(start))
-		p.metrics.IncrementDatabaseQueries("post_get_by_id", err == nil)
-	}()
+	defer db.ObserveQuery(p.metrics, p.log, "post_get_by_id", time.Now(), &err, slog.Int64("post_id", id))
 
 	p.log.Debug("Getting post by ID", slog.Int64("id", id))
+	return p.getByID(ctx, id, "SELECT "+postColumns+" FROM posts WHERE id = @id")
+}
+
+// GetByIDForUpdate locks the row until the surrounding transaction ends; it must run inside a transaction.
+func (p *PostRepository) GetByIDForUpdate(ctx context.Context, id int64) (result *model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_get_by_id_for_update", time.Now(), &err, slog.Int64("post_id", id))
 
+	p.log.Debug("Locking post by ID", slog.Int64("id", id))
+	return p.getByID(ctx, id, "SELECT "+postColumns+" FROM posts WHERE id = @id FOR UPDATE")
+}
+
+func (p *PostRepository) getByID(ctx context.Context, id int64, query string) (*model.Post, error) {
 	args := pgx.NamedArgs{"id": id}
-	query := `SELECT id, author_id, title, content, created_at, updated_at
-				FROM posts WHERE id = @id`
-	row := p.db.QueryRow(ctx, query, args)
+	post, err := scanPost(p.db.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.Debug("Post not found by id", slog.Int64("id", id), slog.String("error", err.Error()))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		p.log.Error("Error getting post by id", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	p.log.Debug("Successfully retrieved post by ID", slog.Int64("id", post.ID), slog.Int64("author_id", post.AuthorID))
+	return post, nil
+}
+
+// postColumnNames are the columns of a post row, in the order scanPost reads them. Every
+// query returning posts selects them through postColumns or qualifiedPostColumns, so the
+// queries and the scan cannot drift apart.
+var postColumnNames = []string{
+	"id", "author_id", "title", "content", "status", "visibility", "version", "edit_count",
+	"created_at", "updated_at", "published_at", "scheduled_at", "lang", "source", "pinned_at",
+	"moderation_status", "rejection_reason", "author_username", "author_avatar_url", "author_snapshot_at",
+}
+
+var postColumns = strings.Join(postColumnNames, ", ")
+
+// qualifiedPostColumns is postColumns with each column prefixed by table, for queries where
+// a bare name would be ambiguous.
+func qualifiedPostColumns(table string) string {
+	return table + "." + strings.Join(postColumnNames, ", "+table+".")
+}
+
+// scanPost reads a row of postColumns.
+func scanPost(row pgx.Row) (*model.Post, error) {
 	post := &model.Post{}
-	err = row.Scan(
+	err := row.Scan(
 		&post.ID,
 		&post.AuthorID,
 		&post.Title,
 		&post.Content,
+		&post.Status,
+		&post.Visibility,
+		&post.Version,
+		&post.EditCount,
 		&post.CreatedAt,
 		&post.UpdatedAt,
+		&post.PublishedAt,
+		&post.ScheduledAt,
+		&post.Language,
+		&post.Source,
+		&post.PinnedAt,
+		&post.ModerationStatus,
+		&post.RejectionReason,
+		&post.AuthorUsername,
+		&post.AuthorAvatarURL,
+		&post.AuthorSnapshotAt,
 	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			p.log.Debug("Post not found by id", slog.Int64("id", id), slog.String("error", err.Error()))
-			return nil, custom_errors.ErrPostNotFound
-		}
-		p.log.Error("Error getting post by id", slog.Int64("id", id), slog.String("error", err.Error()))
-		return nil, custom_errors.ErrDatabaseQuery
+		return nil, err
 	}
-	p.log.Debug("Successfully retrieved post by ID", slog.Int64("id", post.ID), slog.Int64("author_id", post.AuthorID))
 	return post, nil
 }
 
+// scanPosts reads every row of rows as scanPost does and closes them.
+func scanPosts(rows pgx.Rows) ([]*model.Post, error) {
+	defer rows.Close()
+	posts := []*model.Post{}
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
 func (p *PostRepository) GetByAuthor(ctx context.Context, authorID int64) (result []*model.Post, err error) {
-	start := time.Now()
-	defer func() {
-		p.metrics.RecordDatabaseQueryDuration("post_get_by_author", time.Since(start))
-		p.metrics.IncrementDatabaseQueries("post_get_by_author", err == nil)
-	}()
+	defer db.ObserveQuery(p.metrics, p.log, "post_get_by_author", time.Now(), &err, slog.Int64("author_id", authorID))
 
 	p.log.Debug("Getting posts by author", slog.Int64("author_id", authorID))
 
 	args := pgx.NamedArgs{"author_id": authorID}
-	query := `SELECT id, author_id, title, content, created_at, updated_at
-				FROM posts WHERE author_id = @author_id ORDER BY created_at DESC`
+	query := `SELECT ` + postColumns + `
+				FROM posts WHERE author_id = @author_id ORDER BY created_at DESC, id DESC`
 
 	rows, err := p.db.Query(ctx, query, args)
 	if err != nil {
 		p.log.Error("Error getting posts by author", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
-		return nil, custom_errors.ErrDatabaseQuery
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	posts, err := scanPosts(rows)
+	if err != nil {
+		p.log.Error("Error reading posts during GetByAuthor", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	p.log.Debug("Successfully retrieved posts by author", slog.Int64("author_id", authorID), slog.Int("count", len(posts)))
+	return posts, nil
+}
+
+func (p *PostRepository) GetByIDs(ctx context.Context, ids []int64) (result []*model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_get_by_ids", time.Now(), &err, slog.Int("count", len(ids)))
+
+	p.log.Debug("Getting posts by IDs", slog.Int("count", len(ids)))
+
+	rows, err := p.db.Query(ctx, "SELECT "+postColumns+" FROM posts WHERE id = ANY(@ids)", pgx.NamedArgs{"ids": ids})
+	if err != nil {
+		p.log.Error("Error getting posts by IDs", slog.Int("count", len(ids)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	posts, err := scanPosts(rows)
+	if err != nil {
+		p.log.Error("Error reading posts during GetByIDs", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return posts, nil
+}
+
+// CountPublishedByAuthor is answered from idx_posts_author_id without the tag joins of List.
+func (p *PostRepository) CountPublishedByAuthor(ctx context.Context, authorID int64) (count int64, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_count_by_author", time.Now(), &err, slog.Int64("author_id", authorID))
+
+	p.log.Debug("Counting published posts by author", slog.Int64("author_id", authorID))
+
+	err = p.db.QueryRow(ctx, `SELECT count(*) FROM posts WHERE author_id = @author_id AND status = 'published'`,
+		pgx.NamedArgs{"author_id": authorID}).Scan(&count)
+	if err != nil {
+		p.log.Error("Error counting posts by author", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return count, nil
+}
+
+// NewestByTagsSince looks up the newest post of each tag separately, so a tag whose newest
+// post is older than since costs one probe of posts_tags rather than a join over its posts.
+func (p *PostRepository) NewestByTagsSince(ctx context.Context, tagNames []string, since time.Time) (newest map[string]time.Time, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_newest_by_tags", time.Now(), &err, slog.Int("tags", len(tagNames)))
+
+	p.log.Debug("Getting newest posts by tags", slog.Int("tags", len(tagNames)), slog.Time("since", since))
+
+	newest = make(map[string]time.Time, len(tagNames))
+	if len(tagNames) == 0 {
+		return newest, nil
+	}
+
+	// The visibility conditions are those of an anonymous ListPosts. A post counts from when
+	// it was published (see model.Post.VisibleSince), so a draft published after since does.
+	query := `SELECT t.name, latest.visible_since
+				FROM tags t
+				CROSS JOIN LATERAL (
+					SELECT COALESCE(p.published_at, p.created_at) AS visible_since
+					FROM posts_tags pt JOIN posts p ON p.id = pt.post_id
+					WHERE pt.tag_id = t.id AND COALESCE(p.published_at, p.created_at) > @since
+						AND p.status = 'published' AND p.moderation_status <> 'rejected' AND p.visibility = 'public'
+					ORDER BY visible_since DESC LIMIT 1
+				) latest
+				WHERE t.name = ANY(@tag_names)`
+	rows, err := p.db.Query(ctx, query, pgx.NamedArgs{"tag_names": tagNames, "since": since})
+	if err != nil {
+		p.log.Error("Error getting newest posts by tags", slog.Int("tags", len(tagNames)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
 	defer rows.Close()
 
-	var posts []*model.Post
 	for rows.Next() {
-		var post model.Post
-		err := rows.Scan(
-			&post.ID,
-			&post.AuthorID,
-			&post.Title,
-			&post.Content,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-		)
-		if err != nil {
-			p.log.Error("Error scanning post during GetByAuthor", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
-			return nil, custom_errors.ErrDatabaseQuery
+		var name string
+		var visibleSince time.Time
+		if err := rows.Scan(&name, &visibleSince); err != nil {
+			p.log.Error("Error scanning newest post by tag", slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 		}
-		posts = append(posts, &post)
+		newest[name] = visibleSince
 	}
-
 	if err = rows.Err(); err != nil {
-		p.log.Error("Error iterating rows during GetByAuthor", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
-		return nil, custom_errors.ErrDatabaseQuery
+		p.log.Error("Error getting newest posts by tags", slog.Int("tags", len(tagNames)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
+	return newest, nil
+}
 
-	p.log.Debug("Successfully retrieved posts by author", slog.Int64("author_id", authorID), slog.Int("count", len(posts)))
+func (p *PostRepository) GetByAuthorAfter(ctx context.Context, authorID int64, afterID int64, limit int) (result []*model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_get_by_author_after", time.Now(), &err, slog.Int64("author_id", authorID), slog.Int64("after_id", afterID), slog.Int("limit", limit))
+
+	p.log.Debug("Getting page of posts by author",
+		slog.Int64("author_id", authorID), slog.Int64("after_id", afterID), slog.Int("limit", limit))
+
+	args := pgx.NamedArgs{"author_id": authorID, "after_id": afterID, "limit": limit}
+	query := `SELECT ` + postColumns + `
+				FROM posts WHERE author_id = @author_id AND id > @after_id ORDER BY id LIMIT @limit`
+
+	rows, err := p.db.Query(ctx, query, args)
+	if err != nil {
+		p.log.Error("Error getting page of posts by author", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	posts, err := scanPosts(rows)
+	if err != nil {
+		p.log.Error("Error reading posts during GetByAuthorAfter", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
 	return posts, nil
 }
 
 func (p *PostRepository) Update(ctx context.Context, id int64, update *model.UpdatePostDTO) (result *model.Post, err error) {
-	start := time.Now()
-	defer func() {
-		p.metrics.RecordDatabaseQueryDuration("post_update", time.Since(start))
-		p.metrics.IncrementDatabaseQueries("post_update", err == nil)
-	}()
+	defer db.ObserveQuery(p.metrics, p.log, "post_update", time.Now(), &err, slog.Int64("post_id", id))
 
 	p.log.Debug("Updating post", slog.Int64("id", id), slog.Any("update_fields", map[string]bool{
-		"title":   update.Title != nil,
-		"content": update.Content != nil,
+		"title":      update.Title != nil,
+		"content":    update.Content != nil,
+		"visibility": update.Visibility != nil,
+		"language":   update.Language != nil,
 	}))
 
 	setClauses := []string{}
 	args := pgx.NamedArgs{"id": id}
 
-	if update.Title != nil && *update.Title != "" {
+	// An empty title clears it; the other fields ignore empty values.
+	if update.Title != nil {
 		setClauses = append(setClauses, "title = @title")
 		args["title"] = *update.Title
 		p.log.Debug("Updating post title", slog.Int64("id", id), slog.String("new_title", *update.Title))
@@ -177,49 +428,94 @@ func (p *PostRepository) Update(ctx context.Context, id int64, update *model.Upd
 		args["content"] = *update.Content
 		p.log.Debug("Updating post content", slog.Int64("id", id))
 	}
-
-	updatedAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
-	setClauses = append(setClauses, "updated_at = @updated_at")
-	args["updated_at"] = updatedAt
+	if update.Visibility != nil && *update.Visibility != "" {
+		setClauses = append(setClauses, "visibility = @visibility")
+		args["visibility"] = *update.Visibility
+		p.log.Debug("Updating post visibility", slog.Int64("id", id), slog.String("visibility", string(*update.Visibility)))
+	}
+	if update.Language != nil && *update.Language != "" {
+		setClauses = append(setClauses, "lang = @lang")
+		args["lang"] = *update.Language
+		p.log.Debug("Updating post language", slog.Int64("id", id), slog.String("language", *update.Language))
+	}
 
 	if len(setClauses) == 0 {
 		p.log.Debug("No fields to update", slog.Int64("id", id))
 		return nil, custom_errors.ErrNoUpdateRows
 	}
 
+	setClauses = append(setClauses, "updated_at = @updated_at", "version = version + 1")
+	args["updated_at"] = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	where := " WHERE id = @id"
+	if update.ExpectedVersion != nil {
+		where += " AND version = @expected_version"
+		args["expected_version"] = *update.ExpectedVersion
+	}
+
 	p.log.Debug("Building update query", slog.Int64("id", id), slog.Int("set_clauses_count", len(setClauses)))
-	query := "UPDATE posts SET " + strings.Join(setClauses, ", ") + " WHERE id = @id RETURNING id, author_id, title, content, created_at, updated_at"
-
-	var updatedPost model.Post
-	err = p.db.QueryRow(ctx, query, args).Scan(
-		&updatedPost.ID,
-		&updatedPost.AuthorID,
-		&updatedPost.Title,
-		&updatedPost.Content,
-		&updatedPost.CreatedAt,
-		&updatedPost.UpdatedAt,
-	)
+	query := "UPDATE posts SET " + strings.Join(setClauses, ", ") + where + " RETURNING " + postColumns
+
+	updatedPost, err := scanPost(p.db.QueryRow(ctx, query, args))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			if update.ExpectedVersion != nil {
+				return nil, p.versionConflict(ctx, id)
+			}
 			p.log.Debug("Post not found by id during Update", slog.Int64("id", id), slog.String("error", err.Error()))
 			return nil, custom_errors.ErrPostNotFound
 		}
 		p.log.Error("Error updating post", slog.Int64("id", id), slog.String("error", err.Error()))
-		return nil, custom_errors.ErrDatabaseQuery
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
 
 	p.log.Debug("Successfully updated post", slog.Int64("id", updatedPost.ID), slog.Int64("author_id", updatedPost.AuthorID),
 		slog.Time("updated_at", updatedPost.UpdatedAt.Time))
-	return &updatedPost, nil
+	return updatedPost, nil
+}
+
+// versionConflict tells apart the two reasons a versioned Update matched no row: the post is
+// gone, or it is at another version.
+func (p *PostRepository) versionConflict(ctx context.Context, id int64) error {
+	var current int64
+	err := p.db.QueryRow(ctx, `SELECT version FROM posts WHERE id = @id`, pgx.NamedArgs{"id": id}).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.Debug("Post not found by id during Update", slog.Int64("id", id))
+			return custom_errors.ErrPostNotFound
+		}
+		p.log.Error("Error reading post version", slog.Int64("id", id), slog.String("error", err.Error()))
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	p.log.Debug("Post version conflict during Update", slog.Int64("id", id), slog.Int64("current_version", current))
+	return &model.VersionConflictError{PostID: id, CurrentVersion: current}
+}
+
+func (p *PostRepository) Touch(ctx context.Context, id int64) (result *model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_touch", time.Now(), &err, slog.Int64("post_id", id))
+
+	p.log.Debug("Touching post", slog.Int64("id", id))
+
+	args := pgx.NamedArgs{"id": id, "updated_at": pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+	query := `UPDATE posts SET updated_at = @updated_at, version = version + 1
+				WHERE id = @id
+				RETURNING ` + postColumns
+
+	touchedPost, err := scanPost(p.db.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.Debug("Post not found by id during Touch", slog.Int64("id", id))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		p.log.Error("Error touching post", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	return touchedPost, nil
 }
 
 func (p *PostRepository) Delete(ctx context.Context, id int64) (err error) {
-	start := time.Now()
-	defer func() {
-		p.metrics.RecordDatabaseQueryDuration("post_delete", time.Since(start))
-		p.metrics.IncrementDatabaseQueries("post_delete", err == nil)
-	}()
+	defer db.ObserveQuery(p.metrics, p.log, "post_delete", time.Now(), &err, slog.Int64("post_id", id))
 
 	p.log.Debug("Deleting post", slog.Int64("id", id))
 	args := pgx.NamedArgs{"id": id}
@@ -227,7 +523,7 @@ func (p *PostRepository) Delete(ctx context.Context, id int64) (err error) {
 	result, err := p.db.Exec(ctx, query, args)
 	if err != nil {
 		p.log.Error("Error deleting post", slog.Int64("id", id), slog.String("error", err.Error()))
-		return custom_errors.ErrDatabaseQuery
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
 	if result.RowsAffected() == 0 {
 		p.log.Debug("Post not found during deletion", slog.Int64("id", id))
@@ -237,24 +533,229 @@ func (p *PostRepository) Delete(ctx context.Context, id int64) (err error) {
 	return nil
 }
 
-func (p *PostRepository) List(ctx context.Context, filters model.PostFilters) (posts []*model.Post, total int, err error) {
-	start := time.Now()
-	defer func() {
-		p.metrics.RecordDatabaseQueryDuration("post_list", time.Since(start))
-		p.metrics.IncrementDatabaseQueries("post_list", err == nil)
-	}()
+func (p *PostRepository) Publish(ctx context.Context, id int64) (result *model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_publish", time.Now(), &err, slog.Int64("post_id", id))
 
-	p.log.Debug("Listing posts with filters",
-		slog.Any("author_id", filters.AuthorID),
-		slog.Any("created_after", filters.CreatedAfter),
-		slog.Any("created_before", filters.CreatedBefore),
-		slog.Any("tag_names", filters.TagNames),
-		slog.Any("limit", filters.Limit),
-		slog.Any("offset", filters.Offset))
+	p.log.Debug("Publishing post", slog.Int64("id", id))
 
-	args := pgx.NamedArgs{}
-	baseQuery := `SELECT DISTINCT p.id, p.author_id, p.title, p.content, p.created_at, p.updated_at FROM posts p`
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	args := pgx.NamedArgs{"id": id, "now": now}
+	query := `UPDATE posts SET status = 'published', published_at = @now, updated_at = @now, scheduled_at = NULL,
+					version = version + 1
+				WHERE id = @id
+				RETURNING ` + postColumns
+
+	publishedPost, err := scanPost(p.db.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.Debug("Post not found by id during Publish", slog.Int64("id", id))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		p.log.Error("Error publishing post", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	p.log.Debug("Successfully published post", slog.Int64("id", publishedPost.ID))
+	return publishedPost, nil
+}
+
+// PublishDue publishes up to limit scheduled posts whose time is at or before now, oldest
+// schedule first, and returns them. Rows locked by another replica's run are skipped.
+func (p *PostRepository) PublishDue(ctx context.Context, now time.Time, limit int) (result []*model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_publish_due", time.Now(), &err, slog.Int("limit", limit))
+
+	p.log.Debug("Publishing due scheduled posts", slog.Time("now", now), slog.Int("limit", limit))
+
+	query := `WITH due AS (
+					SELECT id FROM posts
+					WHERE status = 'scheduled' AND scheduled_at <= @now
+					ORDER BY scheduled_at, id LIMIT @limit
+					FOR UPDATE SKIP LOCKED
+				)
+				UPDATE posts SET status = 'published', published_at = posts.scheduled_at, updated_at = @now, scheduled_at = NULL,
+					version = posts.version + 1
+				FROM due WHERE posts.id = due.id
+				RETURNING ` + qualifiedPostColumns("posts")
+
+	rows, err := p.db.Query(ctx, query, pgx.NamedArgs{"now": now, "limit": limit})
+	if err != nil {
+		p.log.Error("Error publishing due posts", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	posts, err := scanPosts(rows)
+	if err != nil {
+		p.log.Error("Error reading published posts", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	p.log.Debug("Published due scheduled posts", slog.Int("count", len(posts)))
+	return posts, nil
+}
+
+// CancelSchedule turns a scheduled post back into a draft. A post that is not scheduled,
+// including one the scheduler has just published, fails with ErrPostNotFound.
+func (p *PostRepository) CancelSchedule(ctx context.Context, id int64) (result *model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_cancel_schedule", time.Now(), &err, slog.Int64("post_id", id))
+
+	p.log.Debug("Cancelling scheduled post", slog.Int64("id", id))
+
+	args := pgx.NamedArgs{"id": id, "now": pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+	query := `UPDATE posts SET status = 'draft', scheduled_at = NULL, updated_at = @now, version = version + 1
+				WHERE id = @id AND status = 'scheduled'
+				RETURNING ` + postColumns
+
+	draft, err := scanPost(p.db.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.Debug("Scheduled post not found during CancelSchedule", slog.Int64("id", id))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		p.log.Error("Error cancelling scheduled post", slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	p.log.Debug("Successfully cancelled scheduled post", slog.Int64("id", draft.ID))
+	return draft, nil
+}
+
+func (p *PostRepository) Pin(ctx context.Context, id int64, now time.Time) (result *model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_pin", time.Now(), &err, slog.Int64("post_id", id))
+
+	p.log.Debug("Pinning post", slog.Int64("id", id))
+	return p.setPinned(ctx, id, pgtype.Timestamptz{Time: now, Valid: true})
+}
+
+func (p *PostRepository) Unpin(ctx context.Context, id int64) (result *model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_unpin", time.Now(), &err, slog.Int64("post_id", id))
+
+	p.log.Debug("Unpinning post", slog.Int64("id", id))
+	return p.setPinned(ctx, id, pgtype.Timestamptz{})
+}
+
+func (p *PostRepository) setPinned(ctx context.Context, id int64, pinnedAt pgtype.Timestamptz) (*model.Post, error) {
+	query := `UPDATE posts SET pinned_at = @pinned_at
+				WHERE id = @id
+				RETURNING ` + postColumns
+
+	post, err := scanPost(p.db.QueryRow(ctx, query, pgx.NamedArgs{"id": id, "pinned_at": pinnedAt}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.Debug("Post not found by id during pin change", slog.Int64("id", id))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		p.log.Error("Error changing post pin", slog.Int64("id", id), slog.Bool("pinned", pinnedAt.Valid), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return post, nil
+}
+
+func (p *PostRepository) SetModerationStatus(ctx context.Context, id int64, status model.ModerationStatus, reason *model.RejectionReason, now time.Time) (result *model.Post, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_set_moderation_status", time.Now(), &err, slog.Int64("post_id", id), slog.String("status", string(status)))
+
+	p.log.Debug("Setting moderation status of post", slog.Int64("id", id), slog.String("status", string(status)))
+	query := `UPDATE posts SET moderation_status = @status, rejection_reason = @reason, updated_at = @now
+				WHERE id = @id
+				RETURNING ` + postColumns
+
+	result, err = scanPost(p.db.QueryRow(ctx, query, pgx.NamedArgs{"id": id, "status": status, "reason": reason, "now": now}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			p.log.Debug("Post not found by id during moderation", slog.Int64("id", id))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		p.log.Error("Error setting moderation status of post", slog.Int64("id", id), slog.String("status", string(status)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return result, nil
+}
 
+func (p *PostRepository) RefreshAuthorSnapshot(ctx context.Context, author *model.User, at time.Time) (count int64, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_refresh_author_snapshot", time.Now(), &err, slog.Int64("author_id", author.ID))
+
+	p.log.Debug("Refreshing author snapshot of posts", slog.Int64("author_id", author.ID))
+	query := `UPDATE posts SET author_username = @username, author_avatar_url = @avatar_url, author_snapshot_at = @at
+				WHERE author_id = @author_id AND (author_snapshot_at IS NULL OR author_snapshot_at < @at)`
+	result, err := p.db.Exec(ctx, query, pgx.NamedArgs{
+		"author_id":  author.ID,
+		"username":   author.Username,
+		"avatar_url": author.AvatarURL,
+		"at":         at,
+	})
+	if err != nil {
+		p.log.Error("Error refreshing author snapshot of posts", slog.Int64("author_id", author.ID), slog.String("error", err.Error()))
+		return 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// UnpinAuthor is answered from idx_posts_author_pinned, which holds only pinned posts.
+func (p *PostRepository) UnpinAuthor(ctx context.Context, authorID int64) (ids []int64, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_unpin_author", time.Now(), &err, slog.Int64("author_id", authorID))
+
+	p.log.Debug("Unpinning posts of author", slog.Int64("author_id", authorID))
+
+	rows, err := p.db.Query(ctx, `UPDATE posts SET pinned_at = NULL
+				WHERE author_id = @author_id AND pinned_at IS NOT NULL
+				RETURNING id`, pgx.NamedArgs{"author_id": authorID})
+	if err != nil {
+		p.log.Error("Error unpinning posts of author", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			p.log.Error("Error scanning unpinned post", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		p.log.Error("Error iterating unpinned posts", slog.Int64("author_id", authorID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return ids, nil
+}
+
+// sortColumns and sortDirections are the only strings that reach ORDER BY; filters pick
+// among them by key.
+var (
+	sortColumns = map[model.PostSortField]string{
+		model.SortByCreatedAt: "p.created_at",
+		model.SortByUpdatedAt: "p.updated_at",
+	}
+	sortDirections = map[model.SortOrder]string{
+		model.SortDesc: "DESC",
+		model.SortAsc:  "ASC",
+	}
+)
+
+// orderBy defaults to newest first and breaks ties by id so pages never overlap. With
+// pinnedFirst the pinned post leads the first page and is left out of the others.
+func orderBy(sortBy model.PostSortField, order model.SortOrder, pinnedFirst bool) string {
+	column, ok := sortColumns[sortBy]
+	if !ok {
+		column = sortColumns[model.SortByCreatedAt]
+	}
+	direction, ok := sortDirections[order]
+	if !ok {
+		direction = sortDirections[model.SortDesc]
+	}
+	if pinnedFirst {
+		return fmt.Sprintf(" ORDER BY p.pinned_at IS NULL, %s %s, p.id %s", column, direction, direction)
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, p.id %s", column, direction, direction)
+}
+
+// excludedAuthorsChunk is how many excluded author ids one array parameter of the List query
+// holds.
+const excludedAuthorsChunk = 1000
+
+// listWhere builds the WHERE clause and its arguments shared by the List page and count
+// queries, so the two always agree on which posts match filters.
+func (p *PostRepository) listWhere(filters model.PostFilters) (string, pgx.NamedArgs) {
+	args := pgx.NamedArgs{}
 	whereClauses := []string{}
 
 	if filters.AuthorID != nil {
@@ -262,6 +763,36 @@ func (p *PostRepository) List(ctx context.Context, filters model.PostFilters) (p
 		args["author_id"] = *filters.AuthorID
 		p.log.Debug("Adding author filter", slog.Int64("author_id", *filters.AuthorID))
 	}
+	if len(filters.AuthorIDs) > 0 {
+		whereClauses = append(whereClauses, "p.author_id = ANY(@author_ids)")
+		args["author_ids"] = filters.AuthorIDs
+		p.log.Debug("Adding authors filter", slog.Int("author_ids_count", len(filters.AuthorIDs)))
+	}
+	// One array parameter per chunk keeps each bound array small however many authors a
+	// requester has blocked.
+	if len(filters.ExcludedAuthorIDs) > 0 {
+		chunks := 0
+		for chunk := range slices.Chunk(filters.ExcludedAuthorIDs, excludedAuthorsChunk) {
+			paramName := fmt.Sprintf("excluded_author_ids_%d", chunks)
+			whereClauses = append(whereClauses, fmt.Sprintf("p.author_id <> ALL(@%s)", paramName))
+			args[paramName] = chunk
+			chunks++
+		}
+		p.log.Debug("Adding excluded authors filter", slog.Int("excluded_author_ids_count", len(filters.ExcludedAuthorIDs)), slog.Int("chunks", chunks))
+	}
+	// Drafts, scheduled posts and rejected posts are listed only for their author.
+	if filters.RequesterID != nil {
+		whereClauses = append(whereClauses,
+			"(p.status = 'published' OR p.author_id = @requester_id)",
+			"(p.moderation_status <> 'rejected' OR p.author_id = @requester_id)")
+		args["requester_id"] = *filters.RequesterID
+		p.log.Debug("Adding draft visibility filter", slog.Int64("requester_id", *filters.RequesterID))
+	} else {
+		whereClauses = append(whereClauses, "p.status = 'published'", "p.moderation_status <> 'rejected'")
+	}
+	if !filters.ListsOwnPosts() {
+		whereClauses = append(whereClauses, "p.visibility = 'public'")
+	}
 	if filters.CreatedAfter != nil {
 		whereClauses = append(whereClauses, "p.created_at > @created_after")
 		args["created_after"] = *filters.CreatedAfter
@@ -272,27 +803,70 @@ func (p *PostRepository) List(ctx context.Context, filters model.PostFilters) (p
 		args["created_before"] = *filters.CreatedBefore
 		p.log.Debug("Adding created_before filter", slog.Any("created_before", filters.CreatedBefore), slog.String("operator", "<"))
 	}
+	if filters.UpdatedAfter != nil {
+		whereClauses = append(whereClauses, "p.updated_at > @updated_after")
+		args["updated_after"] = *filters.UpdatedAfter
+		p.log.Debug("Adding updated_after filter", slog.Any("updated_after", filters.UpdatedAfter), slog.String("operator", ">"))
+	}
 
 	if len(filters.TagNames) > 0 {
+		// EXISTS matches each post once however many of its tags match, so neither the page
+		// nor the count needs DISTINCT over joined rows.
 		p.log.Debug("Adding tags filter", slog.Any("tag_names", filters.TagNames))
-		baseQuery += ` JOIN posts_tags pt ON p.id = pt.post_id JOIN tags t ON pt.tag_id = t.id`
-		var tagClauses []string
-		for i, tagName := range filters.TagNames {
-			paramName := fmt.Sprintf("tag_name_%d", i)
-			tagClauses = append(tagClauses, fmt.Sprintf("t.name ILIKE @%s", paramName))
-			args[paramName] = tagName
-			p.log.Debug("Adding tag filter", slog.String("tag_name", tagName), slog.String("param_name", paramName))
+		// Exact equality against the normalized names the service passes, so "_" and "%" in a
+		// name are plain characters and the unique index on tags.name applies.
+		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM posts_tags pt JOIN tags t ON pt.tag_id = t.id"+
+			" WHERE pt.post_id = p.id AND t.name = ANY(@tag_names))")
+		args["tag_names"] = filters.TagNames
+	}
+	if filters.Language != nil {
+		// Exact match, so idx_posts_lang_created_at applies.
+		whereClauses = append(whereClauses, "p.lang = @lang")
+		args["lang"] = *filters.Language
+		p.log.Debug("Adding language filter", slog.String("language", *filters.Language))
+	}
+	if filters.Source != nil {
+		whereClauses = append(whereClauses, "p.source = @source")
+		args["source"] = string(*filters.Source)
+		p.log.Debug("Adding source filter", slog.String("source", string(*filters.Source)))
+	}
+	if filters.HasMedia != nil {
+		exists := "EXISTS (SELECT 1 FROM post_media pm WHERE pm.post_id = p.id)"
+		if !*filters.HasMedia {
+			exists = "NOT " + exists
 		}
-		whereClauses = append(whereClauses, "("+strings.Join(tagClauses, " OR ")+")")
+		whereClauses = append(whereClauses, exists)
+		p.log.Debug("Adding has_media filter", slog.Bool("has_media", *filters.HasMedia))
 	}
-
-	if len(whereClauses) > 0 {
-		condition := " WHERE " + strings.Join(whereClauses, " AND ")
-		baseQuery += condition
-		p.log.Debug("Added WHERE conditions", slog.Int("conditions_count", len(whereClauses)))
+	if filters.MediaType != nil {
+		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM post_media pmt WHERE pmt.post_id = p.id AND pmt.type = @media_type)")
+		args["media_type"] = string(*filters.MediaType)
+		p.log.Debug("Adding media_type filter", slog.String("media_type", string(*filters.MediaType)))
 	}
 
-	baseQuery += " ORDER BY p.created_at DESC"
+	return " WHERE " + strings.Join(whereClauses, " AND "), args
+}
+
+func (p *PostRepository) List(ctx context.Context, filters model.PostFilters) (posts []*model.Post, total int, err error) {
+	defer db.ObserveQuery(p.metrics, p.log, "post_list", time.Now(), &err,
+		slog.Any("author_id", filters.AuthorID), slog.Int("tag_names_count", len(filters.TagNames)),
+		slog.Any("limit", filters.Limit), slog.Any("offset", filters.Offset))
+
+	p.log.Debug("Listing posts with filters",
+		slog.Any("author_id", filters.AuthorID),
+		slog.Int("author_ids_count", len(filters.AuthorIDs)),
+		slog.Any("created_after", filters.CreatedAfter),
+		slog.Any("created_before", filters.CreatedBefore),
+		slog.Any("updated_after", filters.UpdatedAfter),
+		slog.Any("tag_names", filters.TagNames),
+		slog.Any("has_media", filters.HasMedia),
+		slog.Any("media_type", filters.MediaType),
+		slog.Any("limit", filters.Limit),
+		slog.Any("offset", filters.Offset))
+
+	where, args := p.listWhere(filters)
+	baseQuery := "SELECT " + qualifiedPostColumns("p") + " FROM posts p" + where
+	baseQuery += orderBy(filters.SortBy, filters.SortOrder, filters.AuthorID != nil)
 	p.log.Debug("Query before pagination", slog.String("query", baseQuery))
 
 	if filters.Limit != nil {
@@ -310,48 +884,18 @@ func (p *PostRepository) List(ctx context.Context, filters model.PostFilters) (p
 	rows, err := p.db.Query(ctx, baseQuery, args)
 	if err != nil {
 		p.log.Error("Error listing posts", slog.String("error", err.Error()))
-		return nil, 0, custom_errors.ErrDatabaseQuery
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var post model.Post
-		err := rows.Scan(
-			&post.ID,
-			&post.AuthorID,
-			&post.Title,
-			&post.Content,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-		)
-		if err != nil {
-			p.log.Error("Error scanning post during List", slog.String("error", err.Error()))
-			return nil, 0, custom_errors.ErrDatabaseQuery
-		}
-		posts = append(posts, &post)
-		p.log.Debug("Scanned post in List", slog.Int64("post_id", post.ID), slog.Int64("author_id", post.AuthorID))
+		return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
-
-	if err = rows.Err(); err != nil {
-		p.log.Error("Error iterating rows during List", slog.String("error", err.Error()))
-		return nil, 0, custom_errors.ErrDatabaseQuery
+	posts, err = scanPosts(rows)
+	if err != nil {
+		p.log.Error("Error reading posts during List", slog.String("error", err.Error()))
+		return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
 
 	p.log.Debug("Retrieved posts in List", slog.Int("retrieved_posts_count", len(posts)))
 
-	p.log.Debug("Building count query")
-	countQuery := "SELECT COUNT(DISTINCT p.id) FROM posts p"
-
-	if len(filters.TagNames) > 0 {
-		countQuery += " JOIN posts_tags pt ON p.id = pt.post_id JOIN tags t ON pt.tag_id = t.id"
-	}
-
-	if len(whereClauses) > 0 {
-		countQuery += " WHERE " + strings.Join(whereClauses, " AND ")
-	}
-
-	// Create a copy of args without LIMIT and OFFSET for the count query
-	countArgs := make(pgx.NamedArgs)
+	countQuery := "SELECT COUNT(*) FROM posts p" + where
+	countArgs := make(pgx.NamedArgs, len(args))
 	for k, v := range args {
 		if k != "limit" && k != "offset" {
 			countArgs[k] = v
@@ -362,7 +906,7 @@ func (p *PostRepository) List(ctx context.Context, filters model.PostFilters) (p
 	err = p.db.QueryRow(ctx, countQuery, countArgs).Scan(&total)
 	if err != nil {
 		p.log.Error("Error counting posts", slog.String("error", err.Error()))
-		return nil, 0, custom_errors.ErrDatabaseQuery
+		return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
 	p.log.Debug("Count query result", slog.Int("total", total))
 
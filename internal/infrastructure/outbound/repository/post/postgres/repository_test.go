@@ -0,0 +1,496 @@
+package post_repository_postgres_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	post_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/post/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	"pinstack-post-service/internal/testsupport"
+)
+
+// queryRecorder keeps the SQL of the last Query and fails it; any other call panics via the
+// nil embedded PgDB.
+type queryRecorder struct {
+	db.PgDB
+	sql string
+}
+
+func (r *queryRecorder) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	r.sql = sql
+	return nil, errors.New("recorded")
+}
+
+func TestPostRepository_List_OrderBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters model.PostFilters
+		want    string
+	}{
+		{
+			name: "default is newest first",
+			want: " ORDER BY p.created_at DESC, p.id DESC",
+		},
+		{
+			name:    "created_at ascending",
+			filters: model.PostFilters{SortBy: model.SortByCreatedAt, SortOrder: model.SortAsc},
+			want:    " ORDER BY p.created_at ASC, p.id ASC",
+		},
+		{
+			name:    "updated_at descending",
+			filters: model.PostFilters{SortBy: model.SortByUpdatedAt, SortOrder: model.SortDesc},
+			want:    " ORDER BY p.updated_at DESC, p.id DESC",
+		},
+		{
+			name:    "updated_at ascending",
+			filters: model.PostFilters{SortBy: model.SortByUpdatedAt, SortOrder: model.SortAsc},
+			want:    " ORDER BY p.updated_at ASC, p.id ASC",
+		},
+		{
+			name:    "unknown values never reach the query",
+			filters: model.PostFilters{SortBy: "title; DROP TABLE posts", SortOrder: "sideways"},
+			want:    " ORDER BY p.created_at DESC, p.id DESC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &queryRecorder{}
+			repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			_, _, err := repo.List(context.Background(), tt.filters)
+
+			require.Error(t, err)
+			assert.Contains(t, recorder.sql, tt.want)
+			assert.NotContains(t, recorder.sql, "DROP")
+		})
+	}
+}
+
+func TestPostRepository_List_UpdatedAfter(t *testing.T) {
+	recorder := &queryRecorder{}
+	repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	since := testsupport.Timestamptz(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	_, _, err := repo.List(context.Background(), model.PostFilters{CreatedAfter: &since, UpdatedAfter: &since})
+
+	require.Error(t, err)
+	assert.Contains(t, recorder.sql, "p.created_at > @created_after AND p.updated_at > @updated_after")
+}
+
+// pageRecorder answers Query with no rows and fails QueryRow, keeping the SQL of both so a
+// List call exposes its page and count queries.
+type pageRecorder struct {
+	db.PgDB
+	pageSQL  string
+	countSQL string
+}
+
+type noRows struct{ pgx.Rows }
+
+func (noRows) Next() bool { return false }
+func (noRows) Err() error { return nil }
+func (noRows) Close()     {}
+
+type failedRow struct{}
+
+func (failedRow) Scan(...any) error { return errors.New("recorded") }
+
+func (r *pageRecorder) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	r.pageSQL = sql
+	return noRows{}, nil
+}
+
+func (r *pageRecorder) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	r.countSQL = sql
+	return failedRow{}
+}
+
+func TestPostRepository_List_TagFilterSharesWhereWithCount(t *testing.T) {
+	recorder := &pageRecorder{}
+	repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	limit, offset := 10, 10
+
+	_, _, err := repo.List(context.Background(), model.PostFilters{TagNames: []string{"go", "rust"}, Limit: &limit, Offset: &offset})
+	require.Error(t, err)
+
+	where := " WHERE p.status = 'published' AND p.moderation_status <> 'rejected' AND p.visibility = 'public' AND EXISTS (SELECT 1 FROM posts_tags pt JOIN tags t ON pt.tag_id = t.id" +
+		" WHERE pt.post_id = p.id AND t.name = ANY(@tag_names))"
+	assert.Equal(t, "SELECT p.id, p.author_id, p.title, p.content, p.status, p.visibility, p.version, p.edit_count, p.created_at, p.updated_at, p.published_at, p.scheduled_at, p.lang, p.source, p.pinned_at, p.moderation_status, p.rejection_reason, p.author_username, p.author_avatar_url, p.author_snapshot_at FROM posts p"+
+		where+" ORDER BY p.created_at DESC, p.id DESC LIMIT @limit OFFSET @offset", recorder.pageSQL)
+	assert.Equal(t, "SELECT COUNT(*) FROM posts p"+where, recorder.countSQL)
+}
+
+func TestPostRepository_List_LanguageFilter(t *testing.T) {
+	recorder := &pageRecorder{}
+	repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	lang := "en"
+
+	_, _, err := repo.List(context.Background(), model.PostFilters{Language: &lang})
+	require.Error(t, err)
+
+	assert.Contains(t, recorder.countSQL, " AND p.lang = @lang")
+	assert.Contains(t, recorder.pageSQL, " AND p.lang = @lang")
+}
+
+func TestPostRepository_List_SourceFilter(t *testing.T) {
+	recorder := &pageRecorder{}
+	repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	source := model.PostSourceIOS
+
+	_, _, err := repo.List(context.Background(), model.PostFilters{Source: &source})
+	require.Error(t, err)
+
+	assert.Contains(t, recorder.countSQL, " AND p.source = @source")
+	assert.Contains(t, recorder.pageSQL, " AND p.source = @source")
+}
+
+func TestPostRepository_List_PinnedFirstOnlyForAnAuthor(t *testing.T) {
+	author := int64(1)
+	tests := []struct {
+		name    string
+		filters model.PostFilters
+		want    string
+	}{
+		{name: "author list", filters: model.PostFilters{AuthorID: &author}, want: " ORDER BY p.pinned_at IS NULL, p.created_at DESC, p.id DESC"},
+		{name: "author list by updated at", filters: model.PostFilters{AuthorID: &author, SortBy: model.SortByUpdatedAt, SortOrder: model.SortAsc},
+			want: " ORDER BY p.pinned_at IS NULL, p.updated_at ASC, p.id ASC"},
+		{name: "global list", filters: model.PostFilters{}, want: " ORDER BY p.created_at DESC, p.id DESC"},
+		{name: "feed", filters: model.PostFilters{AuthorIDs: []int64{author}}, want: " ORDER BY p.created_at DESC, p.id DESC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &pageRecorder{}
+			repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			_, _, err := repo.List(context.Background(), tt.filters)
+
+			require.Error(t, err)
+			assert.True(t, strings.HasSuffix(recorder.pageSQL, tt.want), recorder.pageSQL)
+			assert.NotContains(t, recorder.countSQL, "pinned_at")
+		})
+	}
+}
+
+func TestPostRepository_List_ExcludedAuthorsAreChunked(t *testing.T) {
+	recorder := &pageRecorder{}
+	repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+	excluded := make([]int64, 1500)
+	for i := range excluded {
+		excluded[i] = int64(i + 1)
+	}
+
+	_, _, err := repo.List(context.Background(), model.PostFilters{ExcludedAuthorIDs: excluded})
+	require.Error(t, err)
+
+	clauses := " WHERE p.author_id <> ALL(@excluded_author_ids_0) AND p.author_id <> ALL(@excluded_author_ids_1) AND "
+	assert.Contains(t, recorder.pageSQL, clauses)
+	assert.Contains(t, recorder.countSQL, clauses)
+	assert.NotContains(t, recorder.countSQL, "excluded_author_ids_2")
+}
+
+func TestPostRepository_List_Visibility(t *testing.T) {
+	author, other := int64(1), int64(2)
+	tests := []struct {
+		name       string
+		filters    model.PostFilters
+		onlyPublic bool
+	}{
+		{name: "anonymous list", filters: model.PostFilters{}, onlyPublic: true},
+		{name: "anonymous list of an author", filters: model.PostFilters{AuthorID: &author}, onlyPublic: true},
+		{name: "requester without author filter", filters: model.PostFilters{RequesterID: &author}, onlyPublic: true},
+		{name: "requester listing another author", filters: model.PostFilters{RequesterID: &other, AuthorID: &author}, onlyPublic: true},
+		{name: "requester in a feed of their own", filters: model.PostFilters{RequesterID: &author, AuthorIDs: []int64{author}}, onlyPublic: true},
+		{name: "author listing their own posts", filters: model.PostFilters{RequesterID: &author, AuthorID: &author}, onlyPublic: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &pageRecorder{}
+			repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			_, _, err := repo.List(context.Background(), tt.filters)
+
+			require.Error(t, err)
+			if tt.onlyPublic {
+				assert.Contains(t, recorder.pageSQL, "p.visibility = 'public'")
+				assert.Contains(t, recorder.countSQL, "p.visibility = 'public'")
+			} else {
+				assert.NotContains(t, recorder.pageSQL, "p.visibility = 'public'")
+				assert.NotContains(t, recorder.countSQL, "p.visibility = 'public'")
+			}
+		})
+	}
+}
+
+func TestPostRepository_List_MediaFilters(t *testing.T) {
+	yes, no := true, false
+	video := model.MediaTypeVideo
+	const (
+		withMedia    = "EXISTS (SELECT 1 FROM post_media pm WHERE pm.post_id = p.id)"
+		withVideo    = "EXISTS (SELECT 1 FROM post_media pmt WHERE pmt.post_id = p.id AND pmt.type = @media_type)"
+		withoutMedia = "NOT " + withMedia
+	)
+	tests := []struct {
+		name    string
+		filters model.PostFilters
+		want    []string
+		notWant []string
+	}{
+		{name: "with media", filters: model.PostFilters{HasMedia: &yes}, want: []string{withMedia}, notWant: []string{withoutMedia, withVideo}},
+		{name: "without media", filters: model.PostFilters{HasMedia: &no}, want: []string{withoutMedia}, notWant: []string{withVideo}},
+		{name: "media type", filters: model.PostFilters{MediaType: &video}, want: []string{withVideo}, notWant: []string{withMedia}},
+		{name: "no media filter", notWant: []string{"post_media"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &pageRecorder{}
+			repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			_, _, err := repo.List(context.Background(), tt.filters)
+
+			require.Error(t, err)
+			for _, clause := range tt.want {
+				assert.Contains(t, recorder.pageSQL, clause)
+				assert.Contains(t, recorder.countSQL, clause)
+			}
+			for _, clause := range tt.notWant {
+				assert.NotContains(t, recorder.pageSQL, clause)
+				assert.NotContains(t, recorder.countSQL, clause)
+			}
+		})
+	}
+}
+
+// scanRow answers Scan with err, or copies version into the single destination.
+type scanRow struct {
+	version int64
+	err     error
+}
+
+func (r scanRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int64) = r.version
+	return nil
+}
+
+// rowRecorder keeps the SQL and arguments of every QueryRow and answers them from rows, in order.
+type rowRecorder struct {
+	db.PgDB
+	sql  []string
+	args []pgx.NamedArgs
+	rows []scanRow
+}
+
+func (r *rowRecorder) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	r.sql = append(r.sql, sql)
+	r.args = append(r.args, args[0].(pgx.NamedArgs))
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	return row
+}
+
+func TestPostRepository_Update_ExpectedVersion(t *testing.T) {
+	title, expected := "Renamed", int64(3)
+
+	tests := []struct {
+		name    string
+		rows    []scanRow
+		wantErr error
+		current int64
+	}{
+		{name: "conflict", rows: []scanRow{{err: pgx.ErrNoRows}, {version: 5}}, wantErr: model.ErrVersionConflict, current: 5},
+		{name: "not found", rows: []scanRow{{err: pgx.ErrNoRows}, {err: pgx.ErrNoRows}}, wantErr: custom_errors.ErrPostNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &rowRecorder{rows: tt.rows}
+			repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			_, err := repo.Update(context.Background(), 42, &model.UpdatePostDTO{Title: &title, ExpectedVersion: &expected})
+
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Len(t, recorder.sql, 2)
+			assert.Contains(t, recorder.sql[0], "version = version + 1")
+			assert.Contains(t, recorder.sql[0], "WHERE id = @id AND version = @expected_version")
+			assert.Equal(t, int64(3), recorder.args[0]["expected_version"])
+			if tt.current != 0 {
+				var conflictErr *model.VersionConflictError
+				require.True(t, errors.As(err, &conflictErr))
+				assert.Equal(t, int64(42), conflictErr.PostID)
+				assert.Equal(t, tt.current, conflictErr.CurrentVersion)
+			}
+		})
+	}
+}
+
+func TestPostRepository_Update_WithoutExpectedVersion(t *testing.T) {
+	title := "Renamed"
+	recorder := &rowRecorder{rows: []scanRow{{err: pgx.ErrNoRows}}}
+	repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+	_, err := repo.Update(context.Background(), 42, &model.UpdatePostDTO{Title: &title})
+
+	require.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+	require.Len(t, recorder.sql, 1, "last write wins: no version read")
+	assert.Contains(t, recorder.sql[0], "version = version + 1")
+	assert.NotContains(t, recorder.sql[0], "@expected_version")
+}
+
+// columnRecorder keeps the SQL of every post query and how many destinations its rows were
+// scanned into, then fails the scan.
+type columnRecorder struct {
+	db.PgDB
+	sql   string
+	dests int
+}
+
+func (r *columnRecorder) Scan(dest ...any) error {
+	r.dests = len(dest)
+	return errors.New("recorded")
+}
+
+func (r *columnRecorder) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	r.sql = sql
+	return r
+}
+
+func (r *columnRecorder) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	r.sql = sql
+	return &recordedRows{recorder: r}, nil
+}
+
+func (r *columnRecorder) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	r.sql = b.QueuedQueries[0].SQL
+	return &recordedBatch{recorder: r}
+}
+
+type recordedRows struct {
+	pgx.Rows
+	recorder *columnRecorder
+}
+
+func (r *recordedRows) Next() bool             { return true }
+func (r *recordedRows) Scan(dest ...any) error { return r.recorder.Scan(dest...) }
+func (r *recordedRows) Err() error             { return nil }
+func (r *recordedRows) Close()                 {}
+
+type recordedBatch struct {
+	pgx.BatchResults
+	recorder *columnRecorder
+}
+
+func (b *recordedBatch) QueryRow() pgx.Row { return b.recorder }
+func (b *recordedBatch) Close() error      { return nil }
+
+// selectedColumns counts the columns a post query returns: its RETURNING list, or the select
+// list of its first SELECT.
+func selectedColumns(sql string) int {
+	list := sql
+	if i := strings.LastIndex(sql, "RETURNING "); i >= 0 {
+		list = sql[i+len("RETURNING "):]
+	} else {
+		list = list[strings.Index(list, "SELECT ")+len("SELECT "):]
+		list = list[:strings.Index(list, "FROM")]
+	}
+	return len(strings.Split(list, ","))
+}
+
+func TestPostRepository_QueriesReturnTheColumnsScanned(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	title := "Renamed"
+	queries := map[string]func(repo *post_repository_postgres.PostRepository) error{
+		"Create": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.Create(ctx, &model.Post{AuthorID: 1})
+			return err
+		},
+		"CreateMany": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.CreateMany(ctx, []*model.Post{{AuthorID: 1}})
+			return err
+		},
+		"GetByID": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.GetByID(ctx, 1)
+			return err
+		},
+		"GetByIDForUpdate": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.GetByIDForUpdate(ctx, 1)
+			return err
+		},
+		"GetDetailedByID": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.GetDetailedByID(ctx, 1)
+			return err
+		},
+		"GetByIDs": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.GetByIDs(ctx, []int64{1})
+			return err
+		},
+		"GetByAuthor": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.GetByAuthor(ctx, 1)
+			return err
+		},
+		"GetByAuthorAfter": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.GetByAuthorAfter(ctx, 1, 0, 10)
+			return err
+		},
+		"List": func(repo *post_repository_postgres.PostRepository) error {
+			_, _, err := repo.List(ctx, model.PostFilters{})
+			return err
+		},
+		"Update": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.Update(ctx, 1, &model.UpdatePostDTO{Title: &title})
+			return err
+		},
+		"Touch": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.Touch(ctx, 1)
+			return err
+		},
+		"Publish": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.Publish(ctx, 1)
+			return err
+		},
+		"PublishDue": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.PublishDue(ctx, now, 10)
+			return err
+		},
+		"CancelSchedule": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.CancelSchedule(ctx, 1)
+			return err
+		},
+		"Pin": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.Pin(ctx, 1, now)
+			return err
+		},
+		"SetModerationStatus": func(repo *post_repository_postgres.PostRepository) error {
+			_, err := repo.SetModerationStatus(ctx, 1, model.ModerationStatusApproved, nil, now)
+			return err
+		},
+	}
+
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			recorder := &columnRecorder{}
+			repo := post_repository_postgres.NewPostRepository(recorder, logger.New("test"), prometheus.NewPrometheusMetricsProvider())
+
+			require.Error(t, query(repo))
+			require.NotZero(t, recorder.dests, "the row was never scanned")
+			assert.Equal(t, recorder.dests, selectedColumns(recorder.sql), recorder.sql)
+		})
+	}
+}
@@ -9,14 +9,13 @@ import (
 
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
 
-	"github.com/jackc/pgx/v5/pgtype"
-
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	model "pinstack-post-service/internal/domain/models"
 	"pinstack-post-service/internal/infrastructure/logger"
 	"pinstack-post-service/internal/infrastructure/outbound/repository/post/memory"
+	"pinstack-post-service/internal/testsupport"
 )
 
 func setupPostTest(t *testing.T) (post_repository.Repository, func()) {
@@ -197,6 +196,80 @@ func TestPostRepository_GetByAuthor(t *testing.T) {
 	}
 }
 
+func TestPostRepository_CountPublishedByAuthor(t *testing.T) {
+	repo, cleanup := setupPostTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, p := range []*model.Post{
+		{AuthorID: 1, Title: "Published"},
+		{AuthorID: 1, Title: "Also published"},
+		{AuthorID: 1, Title: "Draft", Status: model.PostStatusDraft},
+		{AuthorID: 2, Title: "Other author"},
+	} {
+		_, err := repo.Create(ctx, p)
+		require.NoError(t, err)
+	}
+
+	count, err := repo.CountPublishedByAuthor(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = repo.CountPublishedByAuthor(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestPostRepository_GetByIDs(t *testing.T) {
+	repo, cleanup := setupPostTest(t)
+	defer cleanup()
+
+	for _, title := range []string{"Post 1", "Post 2", "Post 3"} {
+		_, err := repo.Create(context.Background(), &model.Post{AuthorID: 1, Title: title})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name    string
+		ids     []int64
+		wantIDs []int64
+	}{
+		{
+			name:    "all found",
+			ids:     []int64{3, 1},
+			wantIDs: []int64{1, 3},
+		},
+		{
+			name:    "missing ids are skipped",
+			ids:     []int64{2, 42},
+			wantIDs: []int64{2},
+		},
+		{
+			name:    "duplicates returned once",
+			ids:     []int64{2, 2},
+			wantIDs: []int64{2},
+		},
+		{
+			name:    "none found",
+			ids:     []int64{42},
+			wantIDs: []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetByIDs(context.Background(), tt.ids)
+
+			require.NoError(t, err)
+			ids := make([]int64, len(got))
+			for i, post := range got {
+				ids[i] = post.ID
+			}
+			assert.ElementsMatch(t, tt.wantIDs, ids)
+		})
+	}
+}
+
 func TestPostRepository_Update(t *testing.T) {
 	repo, cleanup := setupPostTest(t)
 	defer cleanup()
@@ -253,6 +326,14 @@ func TestPostRepository_Update(t *testing.T) {
 			},
 			wantErr: custom_errors.ErrPostNotFound,
 		},
+		{
+			name: "tags only",
+			id:   created.ID,
+			update: &model.UpdatePostDTO{
+				Tags: []string{"go"},
+			},
+			wantErr: custom_errors.ErrNoUpdateRows,
+		},
 	}
 
 	for _, tt := range tests {
@@ -282,6 +363,27 @@ func TestPostRepository_Update(t *testing.T) {
 	}
 }
 
+func TestPostRepository_Touch(t *testing.T) {
+	repo, cleanup := setupPostTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	content := "Original content"
+	created, err := repo.Create(ctx, &model.Post{AuthorID: 1, Title: "Original Title", Content: &content})
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+
+	got, err := repo.Touch(ctx, created.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Original Title", got.Title)
+	assert.Equal(t, content, *got.Content)
+	assert.True(t, got.UpdatedAt.Time.After(created.UpdatedAt.Time))
+
+	_, err = repo.Touch(ctx, 999)
+	assert.Equal(t, custom_errors.ErrPostNotFound, err)
+}
+
 func TestPostRepository_Delete(t *testing.T) {
 	repo, cleanup := setupPostTest(t)
 	defer cleanup()
@@ -340,8 +442,8 @@ func TestPostRepository_List(t *testing.T) {
 	yesterday := now.Add(-24 * time.Hour)
 	tomorrow := now.Add(24 * time.Hour)
 
-	yesterdayTS := pgtype.Timestamp{Time: yesterday, Valid: true}
-	tomorrowTS := pgtype.Timestamp{Time: tomorrow, Valid: true}
+	yesterdayTS := testsupport.Timestamptz(yesterday)
+	tomorrowTS := testsupport.Timestamptz(tomorrow)
 
 	posts := []*model.Post{
 		{
@@ -387,7 +489,7 @@ func TestPostRepository_List(t *testing.T) {
 		{
 			name: "filter by created after",
 			filters: model.PostFilters{
-				CreatedAfter: (*pgtype.Timestamptz)(&yesterdayTS),
+				CreatedAfter: &yesterdayTS,
 			},
 			wantLen: 3,
 			wantErr: nil,
@@ -395,7 +497,7 @@ func TestPostRepository_List(t *testing.T) {
 		{
 			name: "filter by created before",
 			filters: model.PostFilters{
-				CreatedBefore: (*pgtype.Timestamptz)(&tomorrowTS),
+				CreatedBefore: &tomorrowTS,
 			},
 			wantLen: 3,
 			wantErr: nil,
@@ -415,6 +517,18 @@ func TestPostRepository_List(t *testing.T) {
 			wantLen: 3,
 			wantErr: nil,
 		},
+		{
+			name:    "filter by several authors",
+			filters: model.PostFilters{AuthorIDs: []int64{2, 3}},
+			wantLen: 1,
+			wantErr: nil,
+		},
+		{
+			name:    "empty author list is no filter",
+			filters: model.PostFilters{AuthorIDs: []int64{}},
+			wantLen: 3,
+			wantErr: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -443,7 +557,155 @@ func TestPostRepository_List(t *testing.T) {
 						assert.Equal(t, *tt.filters.AuthorID, p.AuthorID)
 					}
 				}
+				if len(tt.filters.AuthorIDs) > 0 {
+					for _, p := range got {
+						assert.Contains(t, tt.filters.AuthorIDs, p.AuthorID)
+					}
+				}
 			}
 		})
 	}
 }
+
+func TestPostRepository_List_Sort(t *testing.T) {
+	repo, cleanup := setupPostTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var ids []int64
+	for _, title := range []string{"First", "Second", "Third"} {
+		created, err := repo.Create(ctx, &model.Post{AuthorID: 1, Title: title})
+		require.NoError(t, err)
+		ids = append(ids, created.ID)
+	}
+	edited := "Second, edited"
+	_, err := repo.Update(ctx, ids[1], &model.UpdatePostDTO{Title: &edited})
+	require.NoError(t, err)
+
+	// Posts created in the same instant must still come back in id order, so created_at
+	// order always matches id order here.
+	tests := []struct {
+		name    string
+		filters model.PostFilters
+		want    []int64
+	}{
+		{
+			name: "default is created_at desc",
+			want: []int64{ids[2], ids[1], ids[0]},
+		},
+		{
+			name:    "created_at asc",
+			filters: model.PostFilters{SortBy: model.SortByCreatedAt, SortOrder: model.SortAsc},
+			want:    []int64{ids[0], ids[1], ids[2]},
+		},
+		{
+			name:    "updated_at desc",
+			filters: model.PostFilters{SortBy: model.SortByUpdatedAt, SortOrder: model.SortDesc},
+			want:    []int64{ids[1], ids[2], ids[0]},
+		},
+		{
+			name:    "updated_at asc",
+			filters: model.PostFilters{SortBy: model.SortByUpdatedAt, SortOrder: model.SortAsc},
+			want:    []int64{ids[0], ids[2], ids[1]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := repo.List(ctx, tt.filters)
+			require.NoError(t, err)
+
+			gotIDs := make([]int64, len(got))
+			for i, p := range got {
+				gotIDs[i] = p.ID
+			}
+			assert.Equal(t, tt.want, gotIDs)
+		})
+	}
+}
+
+func TestPostRepository_Publish(t *testing.T) {
+	repo, cleanup := setupPostTest(t)
+	defer cleanup()
+
+	draft, err := repo.Create(context.Background(), &model.Post{
+		AuthorID: 1,
+		Title:    "Draft",
+		Status:   model.PostStatusDraft,
+	})
+	require.NoError(t, err)
+	require.False(t, draft.PublishedAt.Valid)
+
+	authorID := int64(1)
+	otherUserID := int64(2)
+
+	got, _, err := repo.List(context.Background(), model.PostFilters{RequesterID: &otherUserID})
+	require.NoError(t, err)
+	assert.Empty(t, got, "draft must be hidden from other users")
+
+	got, _, err = repo.List(context.Background(), model.PostFilters{RequesterID: &authorID})
+	require.NoError(t, err)
+	assert.Len(t, got, 1, "draft must be visible to its author")
+
+	published, err := repo.Publish(context.Background(), draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.PostStatusPublished, published.Status)
+	assert.True(t, published.PublishedAt.Valid)
+
+	got, _, err = repo.List(context.Background(), model.PostFilters{RequesterID: &otherUserID})
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	_, err = repo.Publish(context.Background(), 999)
+	assert.Equal(t, custom_errors.ErrPostNotFound, err)
+}
+
+func TestPostRepository_List_NonUTCTimezone(t *testing.T) {
+	// The filter times carry a non-UTC zone of their own.
+	loc := time.FixedZone("UTC-5", -5*60*60)
+
+	repo, cleanup := setupPostTest(t)
+	defer cleanup()
+
+	before := time.Now().In(loc).Add(-time.Minute)
+	created, err := repo.Create(context.Background(), &model.Post{AuthorID: 1, Title: "Timezone"})
+	require.NoError(t, err)
+	after := time.Now().In(loc).Add(time.Minute)
+
+	createdAfter := testsupport.Timestamptz(before)
+	createdBefore := testsupport.Timestamptz(after)
+	got, _, err := repo.List(context.Background(), model.PostFilters{CreatedAfter: &createdAfter, CreatedBefore: &createdBefore})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, created.ID, got[0].ID)
+
+	// The same instant expressed in UTC must filter identically.
+	createdAfterUTC := testsupport.Timestamptz(after.UTC())
+	got, _, err = repo.List(context.Background(), model.PostFilters{CreatedAfter: &createdAfterUTC})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestPostRepository_List_UpdatedAfter(t *testing.T) {
+	repo, cleanup := setupPostTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	stale, err := repo.Create(ctx, &model.Post{AuthorID: 1, Title: "Stale"})
+	require.NoError(t, err)
+	touched, err := repo.Create(ctx, &model.Post{AuthorID: 1, Title: "Touched"})
+	require.NoError(t, err)
+
+	since := time.Now()
+	time.Sleep(time.Millisecond)
+	// A tags-only update touches the post instead of updating it.
+	_, err = repo.Touch(ctx, touched.ID)
+	require.NoError(t, err)
+
+	got, total, err := repo.List(ctx, model.PostFilters{UpdatedAfter: testsupport.TimestamptzPtr(since)})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, touched.ID, got[0].ID)
+	assert.NotEqual(t, stale.ID, got[0].ID)
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+const (
+	DefaultMaxBatchTags   = 100
+	DefaultMaxBatchMedia  = 50
+	DefaultMaxBatchDetach = 500
+
+	// BatchChunkSize is how many statements SendChunked queues in one pgx.Batch.
+	BatchChunkSize = 25
+)
+
+// BatchLimits caps how many tags or media one repository call may write, so no caller can
+// queue an unbounded pgx.Batch. A non-positive cap disables it.
+type BatchLimits struct {
+	MaxTags   int
+	MaxMedia  int
+	MaxDetach int
+}
+
+func DefaultBatchLimits() BatchLimits {
+	return BatchLimits{MaxTags: DefaultMaxBatchTags, MaxMedia: DefaultMaxBatchMedia, MaxDetach: DefaultMaxBatchDetach}
+}
+
+// CheckBatchSize fails with ErrInvalidInput when a call writes more than limit items.
+func CheckBatchSize(items string, n, limit int) error {
+	if limit > 0 && n > limit {
+		return fmt.Errorf("%w: %d %s in one call, at most %d", custom_errors.ErrInvalidInput, n, items, limit)
+	}
+	return nil
+}
+
+// SendChunked runs n statements in batches of at most chunkSize, one after the other, so a
+// large call does not build one large batch. queue adds statement i to b and read reads its
+// result from br. Every result of a batch is read; the first error read returns is returned as
+// is and no later batch is sent. Zero statements send nothing.
+//
+// More than one batch runs in a transaction, or a savepoint when d already is one, so the
+// statements stay all or nothing like a single batch.
+func SendChunked(ctx context.Context, d PgDB, n, chunkSize int,
+	queue func(b *pgx.Batch, i int), read func(br pgx.BatchResults, i int) error) (err error) {
+	if n == 0 {
+		return nil
+	}
+	if n <= chunkSize {
+		return sendChunk(ctx, d, 0, n, queue, read)
+	}
+
+	tx, err := d.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+	var chunkDB PgDB = tx
+	if t, ok := d.(*timeoutDB); ok {
+		chunkDB = WithTimeout(tx, t.timeout)
+	}
+	for start := 0; start < n; start += chunkSize {
+		if err = sendChunk(ctx, chunkDB, start, min(start+chunkSize, n), queue, read); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func sendChunk(ctx context.Context, d PgDB, start, end int,
+	queue func(b *pgx.Batch, i int), read func(br pgx.BatchResults, i int) error) error {
+	batch := &pgx.Batch{}
+	for i := start; i < end; i++ {
+		queue(batch, i)
+	}
+	br := d.SendBatch(ctx, batch)
+	var firstErr error
+	for i := start; i < end; i++ {
+		if err := read(br, i); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := br.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
@@ -0,0 +1,129 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+// batchDB records the batches sent to it, and the transaction SendChunked opens on it.
+type batchDB struct {
+	pgx.Tx
+	batches   []int
+	failAt    int
+	begun     bool
+	committed bool
+	rolled    bool
+	tx        *batchDB
+}
+
+func (b *batchDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	b.begun = true
+	b.tx = &batchDB{failAt: b.failAt}
+	return b.tx, nil
+}
+
+func (b *batchDB) Commit(ctx context.Context) error {
+	b.committed = true
+	return nil
+}
+
+func (b *batchDB) Rollback(ctx context.Context) error {
+	b.rolled = true
+	return nil
+}
+
+func (b *batchDB) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults {
+	start := 0
+	for _, n := range b.batches {
+		start += n
+	}
+	b.batches = append(b.batches, batch.Len())
+	return &indexedResults{next: start, failAt: b.failAt}
+}
+
+// indexedResults fails the statement numbered failAt, counting from 1 across batches.
+type indexedResults struct {
+	pgx.BatchResults
+	next   int
+	failAt int
+}
+
+func (r *indexedResults) Exec() (pgconn.CommandTag, error) {
+	r.next++
+	if r.next == r.failAt {
+		return pgconn.CommandTag{}, errors.New("insert failed")
+	}
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (r *indexedResults) Close() error { return nil }
+
+func sendChunked(d db.PgDB, n int) (read []int, err error) {
+	err = db.SendChunked(context.Background(), d, n, 25,
+		func(b *pgx.Batch, i int) { b.Queue("INSERT", i) },
+		func(br pgx.BatchResults, i int) error {
+			read = append(read, i)
+			_, err := br.Exec()
+			return err
+		})
+	return read, err
+}
+
+func TestSendChunked(t *testing.T) {
+	t.Run("nothing to send", func(t *testing.T) {
+		conn := &batchDB{}
+		read, err := sendChunked(conn, 0)
+		require.NoError(t, err)
+		assert.Empty(t, read)
+		assert.Empty(t, conn.batches)
+	})
+
+	t.Run("one batch needs no transaction", func(t *testing.T) {
+		conn := &batchDB{}
+		read, err := sendChunked(conn, 25)
+		require.NoError(t, err)
+		assert.Len(t, read, 25)
+		assert.Equal(t, []int{25}, conn.batches)
+		assert.False(t, conn.begun)
+	})
+
+	t.Run("batches run in order in one transaction", func(t *testing.T) {
+		conn := &batchDB{}
+		read, err := sendChunked(conn, 60)
+		require.NoError(t, err)
+		require.True(t, conn.begun)
+		assert.Empty(t, conn.batches, "the batches run on the transaction")
+		assert.Equal(t, []int{25, 25, 10}, conn.tx.batches)
+		for i, got := range read {
+			assert.Equal(t, i, got)
+		}
+		assert.True(t, conn.tx.committed)
+	})
+
+	t.Run("a failed batch rolls back and stops", func(t *testing.T) {
+		conn := &batchDB{failAt: 30}
+		read, err := sendChunked(conn, 60)
+		require.EqualError(t, err, "insert failed")
+		assert.Equal(t, []int{25, 25}, conn.tx.batches, "the failing batch is read to the end")
+		assert.Len(t, read, 50)
+		assert.True(t, conn.tx.rolled)
+		assert.False(t, conn.tx.committed)
+	})
+}
+
+func TestCheckBatchSize(t *testing.T) {
+	assert.NoError(t, db.CheckBatchSize("tags", 100, 100))
+	assert.NoError(t, db.CheckBatchSize("tags", 1000, 0), "zero disables the cap")
+	err := db.CheckBatchSize("tags", 101, 100)
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+	assert.ErrorContains(t, err, "101 tags in one call, at most 100")
+}
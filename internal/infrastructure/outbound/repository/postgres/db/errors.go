@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	model "pinstack-post-service/internal/domain/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+	codeLockNotAvailable     = "55P03"
+)
+
+// IsRetryable reports whether err comes from a serialization failure, a deadlock or a lock
+// wait cut off by lock_timeout, after which the whole transaction can be run again.
+func IsRetryable(err error) bool {
+	var pgerr *pgconn.PgError
+	if !errors.As(err, &pgerr) {
+		return false
+	}
+	switch pgerr.Code {
+	case codeSerializationFailure, codeDeadlockDetected, codeLockNotAvailable:
+		return true
+	}
+	return false
+}
+
+// WithCause returns domainErr, keeping cause behind it when the transaction may be retried,
+// the query timed out or the caller's context ended, so the retry loop and the handlers can
+// still recognise it. A bare context error is marked with model.ErrDeadlineExceeded or
+// model.ErrCanceled on the way. Any other cause is dropped as before. The message is that of
+// domainErr either way, so driver details never reach callers.
+func WithCause(domainErr, cause error) error {
+	switch {
+	case IsRetryable(cause), errors.Is(cause, model.ErrTimeout), model.IsCallerError(cause):
+	case errors.Is(cause, context.DeadlineExceeded):
+		cause = fmt.Errorf("%w: %w", model.ErrDeadlineExceeded, cause)
+	case errors.Is(cause, context.Canceled):
+		cause = fmt.Errorf("%w: %w", model.ErrCanceled, cause)
+	default:
+		return domainErr
+	}
+	return &retryableError{domain: domainErr, cause: cause}
+}
+
+type retryableError struct {
+	domain error
+	cause  error
+}
+
+func (e *retryableError) Error() string { return e.domain.Error() }
+
+func (e *retryableError) Unwrap() []error { return []error{e.domain, e.cause} }
@@ -0,0 +1,59 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, db.IsRetryable(&pgconn.PgError{Code: "40001"}))
+	assert.True(t, db.IsRetryable(fmt.Errorf("update: %w", &pgconn.PgError{Code: "40P01"})))
+	assert.True(t, db.IsRetryable(&pgconn.PgError{Code: "55P03"}))
+	assert.False(t, db.IsRetryable(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, db.IsRetryable(errors.New("connection refused")))
+	assert.False(t, db.IsRetryable(nil))
+}
+
+func TestWithCause(t *testing.T) {
+	t.Run("keeps a retryable cause", func(t *testing.T) {
+		err := db.WithCause(custom_errors.ErrDatabaseQuery, &pgconn.PgError{Code: "40001", Message: "could not serialize access"})
+
+		assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+		assert.True(t, db.IsRetryable(err))
+		assert.Equal(t, custom_errors.ErrDatabaseQuery.Error(), err.Error())
+	})
+
+	t.Run("keeps a timeout", func(t *testing.T) {
+		err := db.WithCause(custom_errors.ErrTagQueryFailed, fmt.Errorf("%w: %w", model.ErrTimeout, context.DeadlineExceeded))
+
+		assert.ErrorIs(t, err, custom_errors.ErrTagQueryFailed)
+		assert.ErrorIs(t, err, model.ErrTimeout)
+		assert.False(t, db.IsRetryable(err))
+	})
+
+	t.Run("marks a bare context error as the caller's", func(t *testing.T) {
+		deadline := db.WithCause(custom_errors.ErrDatabaseQuery, context.DeadlineExceeded)
+		canceled := db.WithCause(custom_errors.ErrDatabaseQuery, fmt.Errorf("read: %w", context.Canceled))
+
+		assert.ErrorIs(t, deadline, custom_errors.ErrDatabaseQuery)
+		assert.ErrorIs(t, deadline, model.ErrDeadlineExceeded)
+		assert.ErrorIs(t, canceled, model.ErrCanceled)
+		assert.False(t, errors.Is(canceled, model.ErrTimeout))
+		assert.Equal(t, custom_errors.ErrDatabaseQuery.Error(), canceled.Error())
+	})
+
+	t.Run("drops any other cause", func(t *testing.T) {
+		err := db.WithCause(custom_errors.ErrDatabaseQuery, &pgconn.PgError{Code: "23505"})
+
+		assert.Equal(t, custom_errors.ErrDatabaseQuery, err)
+	})
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+)
+
+// ObserveQuery records the duration and outcome of a repository call, and counts it as a
+// timeout when the error keeps model.ErrTimeout (see WithCause). A call cut off by its caller's
+// deadline or cancellation is counted as a caller abort instead of a failed query. When log is
+// a QueryLogger, a slow call is also logged with args, the call's key parameters.
+// Defer it with a pointer to the method's named error result so every return path is counted:
+//
+//	defer db.ObserveQuery(r.metrics, r.log, "post_list", time.Now(), &err, slog.Int("limit", limit))
+func ObserveQuery(metrics ports.MetricsProvider, log ports.Logger, queryType string, start time.Time, err *error, args ...any) {
+	elapsed := time.Since(start)
+	metrics.RecordDatabaseQueryDuration(queryType, elapsed)
+	if log, ok := log.(queryLogger); ok {
+		log.LogQuery(queryType, elapsed, args...)
+	}
+	if err != nil && model.IsCallerError(*err) {
+		metrics.IncrementCallerAborts("postgres", queryType, errors.Is(*err, model.ErrDeadlineExceeded))
+		return
+	}
+	metrics.IncrementDatabaseQueries(queryType, err == nil || *err == nil)
+	if err != nil && errors.Is(*err, model.ErrTimeout) {
+		metrics.IncrementOperationTimeouts("postgres", queryType)
+	}
+}
@@ -0,0 +1,100 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+type recordingMetrics struct {
+	ports.MetricsProvider
+	queries   map[string][]bool
+	durations map[string]int
+	timeouts  map[string]int
+	aborts    map[string][]bool
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		queries:   map[string][]bool{},
+		durations: map[string]int{},
+		timeouts:  map[string]int{},
+		aborts:    map[string][]bool{},
+	}
+}
+
+func (r *recordingMetrics) IncrementCallerAborts(component, operation string, deadlineExceeded bool) {
+	r.aborts[component+"/"+operation] = append(r.aborts[component+"/"+operation], deadlineExceeded)
+}
+
+func (r *recordingMetrics) IncrementOperationTimeouts(component, operation string) {
+	r.timeouts[component+"/"+operation]++
+}
+
+func (r *recordingMetrics) IncrementDatabaseQueries(queryType string, success bool) {
+	r.queries[queryType] = append(r.queries[queryType], success)
+}
+
+func (r *recordingMetrics) RecordDatabaseQueryDuration(queryType string, duration time.Duration) {
+	r.durations[queryType]++
+}
+
+func TestObserveQuery(t *testing.T) {
+	metrics := newRecordingMetrics()
+
+	run := func(fail bool) (err error) {
+		defer db.ObserveQuery(metrics, logger.New("test"), "post_list", time.Now(), &err)
+		if fail {
+			return errors.New("query failed")
+		}
+		return nil
+	}
+
+	assert.NoError(t, run(false))
+	assert.Error(t, run(true))
+
+	assert.Equal(t, []bool{true, false}, metrics.queries["post_list"])
+	assert.Equal(t, 2, metrics.durations["post_list"])
+}
+
+func TestObserveQuery_CountsTimeouts(t *testing.T) {
+	metrics := newRecordingMetrics()
+
+	run := func(err error) (result error) {
+		defer db.ObserveQuery(metrics, logger.New("test"), "post_get", time.Now(), &result)
+		return err
+	}
+
+	_ = run(db.WithCause(custom_errors.ErrDatabaseQuery, fmt.Errorf("%w: %w", model.ErrTimeout, context.DeadlineExceeded)))
+	_ = run(custom_errors.ErrDatabaseQuery)
+
+	assert.Equal(t, []bool{false, false}, metrics.queries["post_get"])
+	assert.Equal(t, 1, metrics.timeouts["postgres/post_get"])
+}
+
+func TestObserveQuery_CountsCallerAbortsApart(t *testing.T) {
+	metrics := newRecordingMetrics()
+
+	run := func(err error) (result error) {
+		defer db.ObserveQuery(metrics, logger.New("test"), "post_create", time.Now(), &result)
+		return err
+	}
+
+	_ = run(db.WithCause(custom_errors.ErrDatabaseQuery, context.DeadlineExceeded))
+	_ = run(db.WithCause(custom_errors.ErrDatabaseQuery, context.Canceled))
+
+	assert.Empty(t, metrics.queries["post_create"], "caller aborts are not failed queries")
+	assert.Empty(t, metrics.timeouts)
+	assert.Equal(t, []bool{true, false}, metrics.aborts["postgres/post_create"])
+	assert.Equal(t, 2, metrics.durations["post_create"])
+}
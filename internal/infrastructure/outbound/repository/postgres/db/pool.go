@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolConfig parses the connection settings of cfg and sizes the pool as configured.
+func PoolConfig(cfg config.Database) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse postgres config: %w", err)
+	}
+
+	poolConfig.MaxConns = cfg.Pool.MaxConns
+	poolConfig.MinConns = cfg.Pool.MinConns
+	poolConfig.MaxConnLifetime = cfg.Pool.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.Pool.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = cfg.Pool.HealthCheckPeriod
+	return poolConfig, nil
+}
+
+// Connect opens the pool and pings the database within cfg.ConnectTimeout, so a wrong
+// address or password fails startup instead of the first request.
+func Connect(ctx context.Context, cfg config.Database, log ports.Logger) (*pgxpool.Pool, error) {
+	poolConfig, err := PoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres pool: %w", err)
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+	if err := pool.Ping(pingCtx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to postgres at %s:%s within %s: %w", cfg.Host, cfg.Port, cfg.ConnectTimeout, err)
+	}
+
+	log.Info("Successfully connected to Postgres",
+		slog.String("host", cfg.Host),
+		slog.String("port", cfg.Port),
+		slog.String("db_name", cfg.DbName),
+		slog.Int("max_conns", int(cfg.Pool.MaxConns)))
+	return pool, nil
+}
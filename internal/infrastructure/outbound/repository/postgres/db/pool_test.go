@@ -0,0 +1,63 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/config"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+func TestPoolConfig(t *testing.T) {
+	cfg := config.Database{
+		Username: "postgres",
+		Password: "secret",
+		Host:     "post-db",
+		Port:     "5434",
+		DbName:   "postservice",
+		Pool: config.DatabasePool{
+			MaxConns:          25,
+			MinConns:          3,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   10 * time.Minute,
+			HealthCheckPeriod: 30 * time.Second,
+		},
+	}
+
+	poolConfig, err := db.PoolConfig(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "post-db", poolConfig.ConnConfig.Host)
+	assert.Equal(t, uint16(5434), poolConfig.ConnConfig.Port)
+	assert.Equal(t, "postservice", poolConfig.ConnConfig.Database)
+	assert.Equal(t, int32(25), poolConfig.MaxConns)
+	assert.Equal(t, int32(3), poolConfig.MinConns)
+	assert.Equal(t, time.Hour, poolConfig.MaxConnLifetime)
+	assert.Equal(t, 10*time.Minute, poolConfig.MaxConnIdleTime)
+	assert.Equal(t, 30*time.Second, poolConfig.HealthCheckPeriod)
+}
+
+func TestConnect_FailsFastWhenUnreachable(t *testing.T) {
+	cfg := config.Database{
+		Username:       "postgres",
+		Password:       "secret",
+		Host:           "127.0.0.1",
+		Port:           "1",
+		DbName:         "postservice",
+		ConnectTimeout: 500 * time.Millisecond,
+		Pool:           config.DatabasePool{MaxConns: 1, MaxConnLifetime: time.Hour, MaxConnIdleTime: time.Hour, HealthCheckPeriod: time.Minute},
+	}
+
+	start := time.Now()
+	pool, err := db.Connect(context.Background(), cfg, logger.New("test"))
+
+	require.Error(t, err)
+	assert.Nil(t, pool)
+	assert.Contains(t, err.Error(), "127.0.0.1:1")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
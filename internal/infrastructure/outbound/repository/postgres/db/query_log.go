@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+)
+
+// QueryLogger is the logger handed to the repositories. It keeps 1 in sampleRate Debug logs so
+// per-query logging can stay on in production, and warns about calls slower than
+// slowThreshold (see ObserveQuery). Info, Warn and Error are never sampled.
+type QueryLogger struct {
+	ports.Logger
+	slowThreshold time.Duration
+	sampleRate    uint64
+	// debugCalls is shared with the loggers returned by With, so they sample together.
+	debugCalls *atomic.Uint64
+}
+
+// NewQueryLogger wraps log. A zero slowThreshold disables slow query logging and a sampleRate
+// of 0 or 1 keeps every Debug log.
+func NewQueryLogger(log ports.Logger, slowThreshold time.Duration, sampleRate int) *QueryLogger {
+	rate := uint64(1)
+	if sampleRate > 1 {
+		rate = uint64(sampleRate)
+	}
+	return &QueryLogger{Logger: log, slowThreshold: slowThreshold, sampleRate: rate, debugCalls: new(atomic.Uint64)}
+}
+
+func (q *QueryLogger) Debug(msg string, args ...any) {
+	if (q.debugCalls.Add(1)-1)%q.sampleRate != 0 {
+		return
+	}
+	q.Logger.Debug(msg, args...)
+}
+
+func (q *QueryLogger) With(args ...any) ports.Logger {
+	clone := *q
+	clone.Logger = q.Logger.With(args...)
+	return &clone
+}
+
+func (q *QueryLogger) WithContext(ctx context.Context) ports.Logger {
+	clone := *q
+	clone.Logger = q.Logger.WithContext(ctx)
+	return &clone
+}
+
+// LogQuery warns when a repository call took longer than the slow query threshold. The
+// operation and duration_ms fields are the ones the log pipeline parses; args carry the
+// call's key parameters.
+func (q *QueryLogger) LogQuery(operation string, elapsed time.Duration, args ...any) {
+	if q.slowThreshold <= 0 || elapsed < q.slowThreshold {
+		return
+	}
+	fields := append([]any{slog.String("operation", operation), slog.Int64("duration_ms", elapsed.Milliseconds())}, args...)
+	q.Logger.Warn("Slow query", fields...)
+}
+
+// queryLogger is implemented by QueryLogger; other loggers do not log slow queries.
+type queryLogger interface {
+	LogQuery(operation string, elapsed time.Duration, args ...any)
+}
@@ -0,0 +1,115 @@
+package db_test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+func TestQueryLogger_SlowQueries(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		elapsed   time.Duration
+		wantWarn  bool
+	}{
+		{name: "fast", threshold: 200 * time.Millisecond, elapsed: 20 * time.Millisecond},
+		{name: "at the threshold", threshold: 200 * time.Millisecond, elapsed: 200 * time.Millisecond, wantWarn: true},
+		{name: "slow", threshold: 200 * time.Millisecond, elapsed: 350 * time.Millisecond, wantWarn: true},
+		{name: "disabled", elapsed: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, recorder := logger.NewTest()
+			log := db.NewQueryLogger(base, tt.threshold, 1)
+			metrics := newRecordingMetrics()
+
+			// The start time stands in for a clock: the call appears to have taken elapsed.
+			func() (err error) {
+				defer db.ObserveQuery(metrics, log, "post_get_by_id", time.Now().Add(-tt.elapsed), &err, slog.Int64("post_id", 42))
+				return nil
+			}()
+
+			entries := recorder.Entries()
+			if !tt.wantWarn {
+				assert.Empty(t, entries)
+				return
+			}
+			require.Len(t, entries, 1)
+			entry := entries[0]
+			assert.Equal(t, slog.LevelWarn, entry.Level)
+			assert.Equal(t, "post_get_by_id", entry.Attrs["operation"])
+			assert.GreaterOrEqual(t, entry.Attrs["duration_ms"], tt.elapsed.Milliseconds())
+			assert.Equal(t, int64(42), entry.Attrs["post_id"])
+		})
+	}
+
+	t.Run("failed slow calls are logged too", func(t *testing.T) {
+		base, recorder := logger.NewTest()
+		log := db.NewQueryLogger(base, time.Millisecond, 1)
+		func() (err error) {
+			defer db.ObserveQuery(newRecordingMetrics(), log, "post_list", time.Now().Add(-time.Second), &err)
+			return errors.New("query failed")
+		}()
+		assert.Equal(t, map[slog.Level]int{slog.LevelWarn: 1}, recorder.Levels())
+	})
+}
+
+func TestQueryLogger_Sampling(t *testing.T) {
+	tests := []struct {
+		name       string
+		sampleRate int
+		wantDebug  int
+	}{
+		{name: "every log", sampleRate: 1, wantDebug: 10},
+		{name: "zero keeps every log", sampleRate: 0, wantDebug: 10},
+		{name: "one in four", sampleRate: 4, wantDebug: 3},
+		{name: "one in ten", sampleRate: 10, wantDebug: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, recorder := logger.NewTest()
+			log := db.NewQueryLogger(base, 0, tt.sampleRate)
+
+			for range 10 {
+				log.Debug("Getting post by ID")
+				log.Info("Post created")
+				log.Warn("Tag missing")
+				log.Error("Error getting post")
+			}
+
+			assert.Equal(t, map[slog.Level]int{slog.LevelDebug: tt.wantDebug, slog.LevelInfo: 10, slog.LevelWarn: 10, slog.LevelError: 10},
+				recorder.Levels(),
+				"only debug logs are sampled")
+		})
+	}
+
+	t.Run("loggers from With sample together", func(t *testing.T) {
+		base, recorder := logger.NewTest()
+		log := db.NewQueryLogger(base, time.Millisecond, 2)
+		scoped := log.With(slog.String("component", "tags"))
+
+		log.Debug("first")
+		scoped.Debug("second")
+		scoped.Debug("third")
+		func() (err error) {
+			defer db.ObserveQuery(newRecordingMetrics(), scoped, "tag_search", time.Now().Add(-time.Second), &err)
+			return nil
+		}()
+
+		require.Equal(t, map[slog.Level]int{slog.LevelDebug: 2, slog.LevelWarn: 1}, recorder.Levels())
+		entries := recorder.Entries()
+		assert.Equal(t, "first", entries[0].Message)
+		assert.Equal(t, "third", entries[1].Message)
+		assert.Equal(t, "tags", entries[2].Attrs["component"], "With keeps slow query logging")
+	})
+}
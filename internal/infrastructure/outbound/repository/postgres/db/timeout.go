@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// batchQueriesPerTimeout is how many queued batch statements share one query timeout.
+const batchQueriesPerTimeout = 10
+
+type timeoutDB struct {
+	db      PgDB
+	timeout time.Duration
+}
+
+// WithTimeout bounds every call on db by timeout, and batches by a multiple of it that grows
+// with the number of queued statements. A call cut off by the deadline returns an error
+// wrapping model.ErrTimeout; one cut off by the caller's own deadline or cancellation wraps
+// model.ErrDeadlineExceeded or model.ErrCanceled instead. A zero timeout returns db unchanged.
+//
+// Rows from Query keep their deadline until Close, and a QueryRow result until Scan.
+func WithTimeout(db PgDB, timeout time.Duration) PgDB {
+	if timeout <= 0 {
+		return db
+	}
+	return &timeoutDB{db: db, timeout: timeout}
+}
+
+func (d *timeoutDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := withTimeout(ctx, d.timeout)
+	rows, err := d.db.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, timeoutError(ctx, err)
+	}
+	return &timeoutRows{Rows: rows, ctx: ctx, cancel: cancel}, nil
+}
+
+func (d *timeoutDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := withTimeout(ctx, d.timeout)
+	return &timeoutRow{row: d.db.QueryRow(ctx, sql, args...), ctx: ctx, cancel: cancel}
+}
+
+func (d *timeoutDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := withTimeout(ctx, d.timeout)
+	defer cancel()
+	tag, err := d.db.Exec(ctx, sql, args...)
+	return tag, timeoutError(ctx, err)
+}
+
+// Begin bounds only the BEGIN itself; the transaction does not keep the context.
+func (d *timeoutDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	ctx, cancel := withTimeout(ctx, d.timeout)
+	defer cancel()
+	tx, err := d.db.Begin(ctx)
+	return tx, timeoutError(ctx, err)
+}
+
+func (d *timeoutDB) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	ctx, cancel := withTimeout(ctx, d.batchTimeout(b.Len()))
+	return &timeoutBatchResults{results: d.db.SendBatch(ctx, b), ctx: ctx, cancel: cancel}
+}
+
+func (d *timeoutDB) batchTimeout(queued int) time.Duration {
+	shares := (queued + batchQueriesPerTimeout - 1) / batchQueriesPerTimeout
+	return d.timeout * time.Duration(max(shares, 1))
+}
+
+// withTimeout bounds ctx by timeout, recording model.ErrTimeout as the cause so that
+// timeoutError can tell this deadline from the caller's.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(ctx, timeout, model.ErrTimeout)
+}
+
+// timeoutError marks err as a timeout when the query timeout ran out while it was being
+// produced, and as a caller error when the caller's context ended first.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	if errors.Is(context.Cause(ctx), model.ErrTimeout) {
+		return fmt.Errorf("%w: %w", model.ErrTimeout, err)
+	}
+	return model.CallerError(ctx, err)
+}
+
+type timeoutRows struct {
+	pgx.Rows
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+func (r *timeoutRows) Err() error {
+	return timeoutError(r.ctx, r.Rows.Err())
+}
+
+func (r *timeoutRows) Scan(dest ...any) error {
+	return timeoutError(r.ctx, r.Rows.Scan(dest...))
+}
+
+type timeoutRow struct {
+	row    pgx.Row
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRow) Scan(dest ...any) error {
+	defer r.cancel()
+	return timeoutError(r.ctx, r.row.Scan(dest...))
+}
+
+type timeoutBatchResults struct {
+	results pgx.BatchResults
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func (b *timeoutBatchResults) Exec() (pgconn.CommandTag, error) {
+	tag, err := b.results.Exec()
+	return tag, timeoutError(b.ctx, err)
+}
+
+func (b *timeoutBatchResults) Query() (pgx.Rows, error) {
+	rows, err := b.results.Query()
+	if err != nil {
+		return nil, timeoutError(b.ctx, err)
+	}
+	// The batch owns the deadline; Close on the batch releases it.
+	return &timeoutRows{Rows: rows, ctx: b.ctx, cancel: func() {}}, nil
+}
+
+func (b *timeoutBatchResults) QueryRow() pgx.Row {
+	return &timeoutRow{row: b.results.QueryRow(), ctx: b.ctx, cancel: func() {}}
+}
+
+func (b *timeoutBatchResults) Close() error {
+	defer b.cancel()
+	return timeoutError(b.ctx, b.results.Close())
+}
@@ -0,0 +1,152 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+// slowDB behaves like a connection that never answers: every call blocks until its context
+// is done and then fails with the context error, as pgx does.
+type slowDB struct {
+	batchDeadline time.Duration
+}
+
+type slowRow struct{ ctx context.Context }
+
+func (r slowRow) Scan(dest ...any) error {
+	<-r.ctx.Done()
+	return r.ctx.Err()
+}
+
+type slowBatch struct {
+	pgx.BatchResults
+	ctx context.Context
+}
+
+func (b slowBatch) Exec() (pgconn.CommandTag, error) {
+	<-b.ctx.Done()
+	return pgconn.CommandTag{}, b.ctx.Err()
+}
+
+func (b slowBatch) Close() error { return nil }
+
+func (s *slowDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *slowDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return slowRow{ctx: ctx}
+}
+
+func (s *slowDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	<-ctx.Done()
+	return pgconn.CommandTag{}, ctx.Err()
+}
+
+func (s *slowDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *slowDB) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	deadline, _ := ctx.Deadline()
+	s.batchDeadline = time.Until(deadline)
+	return slowBatch{ctx: ctx}
+}
+
+func TestWithTimeout_InterruptsSlowCalls(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+	conn := db.WithTimeout(&slowDB{}, timeout)
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"Exec": func() error {
+			_, err := conn.Exec(ctx, "UPDATE posts SET title = 'x'")
+			return err
+		},
+		"QueryRow.Scan": func() error {
+			var id int64
+			return conn.QueryRow(ctx, "SELECT id FROM posts").Scan(&id)
+		},
+		"Query": func() error {
+			_, err := conn.Query(ctx, "SELECT id FROM posts")
+			return err
+		},
+		"Begin": func() error {
+			_, err := conn.Begin(ctx)
+			return err
+		},
+		"SendBatch.Exec": func() error {
+			batch := &pgx.Batch{}
+			batch.Queue("SELECT 1")
+			_, err := conn.SendBatch(ctx, batch).Exec()
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			err := call()
+
+			require.ErrorIs(t, err, model.ErrTimeout)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
+
+func TestWithTimeout_BatchBudgetGrowsWithQueuedStatements(t *testing.T) {
+	const timeout = time.Second
+	slow := &slowDB{}
+	conn := db.WithTimeout(slow, timeout)
+
+	batch := &pgx.Batch{}
+	for range 25 {
+		batch.Queue("SELECT 1")
+	}
+	results := conn.SendBatch(context.Background(), batch)
+	defer results.Close()
+
+	assert.Greater(t, slow.batchDeadline, 2*timeout)
+	assert.LessOrEqual(t, slow.batchDeadline, 3*timeout)
+}
+
+func TestWithTimeout_CallerCancellationIsNotATimeout(t *testing.T) {
+	conn := db.WithTimeout(&slowDB{}, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := conn.Exec(ctx, "UPDATE posts SET title = 'x'")
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, model.ErrCanceled)
+	assert.False(t, errors.Is(err, model.ErrTimeout))
+}
+
+func TestWithTimeout_CallerDeadlineIsNotATimeout(t *testing.T) {
+	conn := db.WithTimeout(&slowDB{}, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := conn.QueryRow(ctx, "SELECT 1").Scan()
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, model.ErrDeadlineExceeded)
+	assert.False(t, errors.Is(err, model.ErrTimeout))
+}
+
+func TestWithTimeout_ZeroDisables(t *testing.T) {
+	slow := &slowDB{}
+	assert.Same(t, db.PgDB(slow), db.WithTimeout(slow, 0))
+}
@@ -2,73 +2,222 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
 	ports "pinstack-post-service/internal/domain/ports/output"
+	archive_repository "pinstack-post-service/internal/domain/ports/output/archive"
 	media_repository "pinstack-post-service/internal/domain/ports/output/media"
+	moderation_repository "pinstack-post-service/internal/domain/ports/output/moderation"
 	post_repository "pinstack-post-service/internal/domain/ports/output/post"
+	revision_repository "pinstack-post-service/internal/domain/ports/output/revision"
 	tag_repository "pinstack-post-service/internal/domain/ports/output/tag"
+	archive_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/archive/postgres"
 	media_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/media/postgres"
+	moderation_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/moderation/postgres"
 	post_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/post/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	revision_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/revision/postgres"
 	tag_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/tag/postgres"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var (
+	// ErrTxClosed is returned by Commit or Rollback on a transaction that was already
+	// committed or rolled back.
+	ErrTxClosed = errors.New("transaction already committed or rolled back")
+	// ErrTxCommitRollback is returned by Commit when the database rolled the transaction back
+	// instead, because a statement in it had failed.
+	ErrTxCommitRollback = errors.New("commit resulted in rollback")
+)
+
 //go:generate mockery --name UnitOfWork --dir . --output ../../../mocks --outpkg mocks --with-expecter --filename UnitsOfWork.go
 type UnitOfWork interface {
+	// Begin starts a read-write transaction with the database's default isolation level.
 	Begin(ctx context.Context) (Transaction, error)
+	BeginWithOptions(ctx context.Context, opts TxOptions) (Transaction, error)
 }
 
+type IsolationLevel string
+
+const (
+	// IsolationDefault leaves the isolation level to the database (READ COMMITTED for Postgres).
+	IsolationDefault IsolationLevel = ""
+	ReadCommitted    IsolationLevel = "read committed"
+	RepeatableRead   IsolationLevel = "repeatable read"
+	Serializable     IsolationLevel = "serializable"
+)
+
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+}
+
+var pgxIsolation = map[IsolationLevel]pgx.TxIsoLevel{
+	ReadCommitted:  pgx.ReadCommitted,
+	RepeatableRead: pgx.RepeatableRead,
+	Serializable:   pgx.Serializable,
+}
+
+func (o TxOptions) toPgx() pgx.TxOptions {
+	opts := pgx.TxOptions{IsoLevel: pgxIsolation[o.Isolation]}
+	if o.ReadOnly {
+		opts.AccessMode = pgx.ReadOnly
+	}
+	return opts
+}
+
+// Transaction hands out repositories bound to one database transaction. Each repository is
+// built on first access and the same one is returned for the rest of the transaction, so a
+// caller fetches only the repositories it uses, where it uses them.
+//
 //go:generate mockery --name Transaction --dir . --output ../../../mocks --outpkg mocks --with-expecter --filename Transaction.go
 type Transaction interface {
 	PostRepository() post_repository.Repository
 	MediaRepository() media_repository.Repository
 	TagRepository() tag_repository.Repository
+	ArchiveRepository() archive_repository.Repository
+	ModerationRepository() moderation_repository.Repository
+	RevisionRepository() revision_repository.Repository
 	Commit(ctx context.Context) error
 	Rollback(ctx context.Context) error
 }
 
+// txBeginner is the part of *pgxpool.Pool the unit of work needs.
+type txBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
 type PostgresUnitOfWork struct {
-	pool    *pgxpool.Pool
-	log     ports.Logger
-	metrics ports.MetricsProvider
+	pool           txBeginner
+	log            ports.Logger
+	metrics        ports.MetricsProvider
+	queryTimeout   time.Duration
+	batchLimits    db.BatchLimits
+	tagLockTimeout time.Duration
 }
 
-func NewPostgresUOW(pool *pgxpool.Pool, log ports.Logger, metrics ports.MetricsProvider) UnitOfWork {
-	return &PostgresUnitOfWork{pool: pool, log: log, metrics: metrics}
+// NewPostgresUOW creates a unit of work whose transactional repositories bound each query by
+// queryTimeout (see db.WithTimeout); zero leaves queries unbounded. The tag and media
+// repositories reject calls over batchLimits, and the tag repository waits at most
+// tagLockTimeout for the lock on the tags of a post (see NewTagRepository).
+func NewPostgresUOW(
+	pool *pgxpool.Pool,
+	log ports.Logger,
+	metrics ports.MetricsProvider,
+	queryTimeout time.Duration,
+	batchLimits db.BatchLimits,
+	tagLockTimeout time.Duration,
+) UnitOfWork {
+	return &PostgresUnitOfWork{
+		pool:           pool,
+		log:            log,
+		metrics:        metrics,
+		queryTimeout:   queryTimeout,
+		batchLimits:    batchLimits,
+		tagLockTimeout: tagLockTimeout,
+	}
 }
 
 func (uow *PostgresUnitOfWork) Begin(ctx context.Context) (Transaction, error) {
-	tx, err := uow.pool.Begin(ctx)
+	return uow.BeginWithOptions(ctx, TxOptions{})
+}
+
+func (uow *PostgresUnitOfWork) BeginWithOptions(ctx context.Context, opts TxOptions) (Transaction, error) {
+	tx, err := uow.pool.BeginTx(ctx, opts.toPgx())
 	if err != nil {
 		return nil, fmt.Errorf("error beginning transaction: %w", err)
 	}
-	return &PostgresTransaction{tx: tx, log: uow.log, metrics: uow.metrics}, nil
+	return &PostgresTransaction{
+		tx:             tx,
+		db:             db.WithTimeout(tx, uow.queryTimeout),
+		log:            uow.log,
+		metrics:        uow.metrics,
+		batchLimits:    uow.batchLimits,
+		tagLockTimeout: uow.tagLockTimeout,
+	}, nil
 }
 
 type PostgresTransaction struct {
-	tx      pgx.Tx
-	log     ports.Logger
-	metrics ports.MetricsProvider
+	tx             pgx.Tx
+	db             db.PgDB
+	log            ports.Logger
+	metrics        ports.MetricsProvider
+	batchLimits    db.BatchLimits
+	tagLockTimeout time.Duration
+
+	// The repositories built so far. A transaction is used by one goroutine at a time, like
+	// the pgx.Tx under it, so they need no lock.
+	posts      post_repository.Repository
+	media      media_repository.Repository
+	tags       tag_repository.Repository
+	archive    archive_repository.Repository
+	moderation moderation_repository.Repository
+	revisions  revision_repository.Repository
 }
 
 func (t *PostgresTransaction) Commit(ctx context.Context) error {
-	return t.tx.Commit(ctx)
+	return txError(t.tx.Commit(ctx))
 }
 
 func (t *PostgresTransaction) Rollback(ctx context.Context) error {
-	return t.tx.Rollback(ctx)
+	return txError(t.tx.Rollback(ctx))
+}
+
+// txError marks the driver's errors for a closed or rolled back transaction with this
+// package's sentinels, keeping the driver error behind them.
+func txError(err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrTxCommitRollback):
+		return fmt.Errorf("%w: %w", ErrTxCommitRollback, err)
+	case errors.Is(err, pgx.ErrTxClosed):
+		return fmt.Errorf("%w: %w", ErrTxClosed, err)
+	default:
+		return err
+	}
 }
 
 func (t *PostgresTransaction) PostRepository() post_repository.Repository {
-	return post_repository_postgres.NewPostRepository(t.tx, t.log, t.metrics)
+	if t.posts == nil {
+		t.posts = post_repository_postgres.NewPostRepository(t.db, t.log, t.metrics)
+	}
+	return t.posts
 }
 
 func (t *PostgresTransaction) MediaRepository() media_repository.Repository {
-	return media_repository_postgres.NewMediaRepository(t.tx, t.log, t.metrics)
+	if t.media == nil {
+		t.media = media_repository_postgres.NewMediaRepository(t.db, t.log, t.metrics, t.batchLimits)
+	}
+	return t.media
 }
 
 func (t *PostgresTransaction) TagRepository() tag_repository.Repository {
-	return tag_repository_postgres.NewTagRepository(t.tx, t.log, t.metrics)
+	if t.tags == nil {
+		t.tags = tag_repository_postgres.NewTagRepository(t.db, t.log, t.metrics, t.batchLimits, t.tagLockTimeout)
+	}
+	return t.tags
+}
+
+func (t *PostgresTransaction) ArchiveRepository() archive_repository.Repository {
+	if t.archive == nil {
+		t.archive = archive_repository_postgres.NewArchiveRepository(t.db, t.log, t.metrics)
+	}
+	return t.archive
+}
+
+func (t *PostgresTransaction) ModerationRepository() moderation_repository.Repository {
+	if t.moderation == nil {
+		t.moderation = moderation_repository_postgres.NewModerationRepository(t.db, t.log, t.metrics)
+	}
+	return t.moderation
+}
+
+func (t *PostgresTransaction) RevisionRepository() revision_repository.Repository {
+	if t.revisions == nil {
+		t.revisions = revision_repository_postgres.NewRevisionRepository(t.db, t.log, t.metrics)
+	}
+	return t.revisions
 }
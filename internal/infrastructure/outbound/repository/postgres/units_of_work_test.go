@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+)
+
+// recordingPool remembers the options of every BeginTx call and hands out inert transactions.
+type recordingPool struct {
+	opts []pgx.TxOptions
+	err  error
+}
+
+type fakeTx struct {
+	pgx.Tx
+}
+
+func (p *recordingPool) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	p.opts = append(p.opts, txOptions)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return fakeTx{}, nil
+}
+
+func TestPostgresUnitOfWork_BeginWithOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		begin func(uow UnitOfWork) (Transaction, error)
+		want  pgx.TxOptions
+	}{
+		{
+			name:  "Begin keeps database defaults",
+			begin: func(uow UnitOfWork) (Transaction, error) { return uow.Begin(context.Background()) },
+			want:  pgx.TxOptions{},
+		},
+		{
+			name: "repeatable read",
+			begin: func(uow UnitOfWork) (Transaction, error) {
+				return uow.BeginWithOptions(context.Background(), TxOptions{Isolation: RepeatableRead})
+			},
+			want: pgx.TxOptions{IsoLevel: pgx.RepeatableRead},
+		},
+		{
+			name: "read-only serializable",
+			begin: func(uow UnitOfWork) (Transaction, error) {
+				return uow.BeginWithOptions(context.Background(), TxOptions{Isolation: Serializable, ReadOnly: true})
+			},
+			want: pgx.TxOptions{IsoLevel: pgx.Serializable, AccessMode: pgx.ReadOnly},
+		},
+		{
+			name: "read-only with default isolation",
+			begin: func(uow UnitOfWork) (Transaction, error) {
+				return uow.BeginWithOptions(context.Background(), TxOptions{ReadOnly: true})
+			},
+			want: pgx.TxOptions{AccessMode: pgx.ReadOnly},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &recordingPool{}
+			uow := &PostgresUnitOfWork{pool: pool, log: logger.New("test"), metrics: prometheus.NewPrometheusMetricsProvider()}
+
+			tx, err := tt.begin(uow)
+			require.NoError(t, err)
+			assert.NotNil(t, tx)
+			require.Len(t, pool.opts, 1)
+			assert.Equal(t, tt.want, pool.opts[0])
+		})
+	}
+}
+
+func TestPostgresUnitOfWork_BeginError(t *testing.T) {
+	poolErr := errors.New("too many connections")
+	uow := &PostgresUnitOfWork{pool: &recordingPool{err: poolErr}, log: logger.New("test"), metrics: prometheus.NewPrometheusMetricsProvider()}
+
+	tx, err := uow.BeginWithOptions(context.Background(), TxOptions{Isolation: RepeatableRead})
+
+	assert.Nil(t, tx)
+	assert.ErrorIs(t, err, poolErr)
+}
+
+func TestPostgresTransaction_BuildsEachRepositoryOnce(t *testing.T) {
+	uow := &PostgresUnitOfWork{pool: &recordingPool{}, log: logger.New("test"), metrics: prometheus.NewPrometheusMetricsProvider()}
+	tx, err := uow.Begin(context.Background())
+	require.NoError(t, err)
+	pgTx := tx.(*PostgresTransaction)
+
+	assert.Nil(t, pgTx.posts, "nothing is built until it is used")
+	assert.Nil(t, pgTx.tags)
+
+	posts := tx.PostRepository()
+	assert.Same(t, posts, tx.PostRepository())
+	assert.Same(t, tx.MediaRepository(), tx.MediaRepository())
+	assert.Same(t, tx.TagRepository(), tx.TagRepository())
+	assert.Same(t, tx.ArchiveRepository(), tx.ArchiveRepository())
+	assert.Same(t, tx.ModerationRepository(), tx.ModerationRepository())
+	assert.Same(t, tx.RevisionRepository(), tx.RevisionRepository())
+}
+
+// endingTx fails Commit and Rollback with err.
+type endingTx struct {
+	pgx.Tx
+	err error
+}
+
+func (tx endingTx) Commit(ctx context.Context) error   { return tx.err }
+func (tx endingTx) Rollback(ctx context.Context) error { return tx.err }
+
+func TestPostgresTransaction_EndErrors(t *testing.T) {
+	connErr := errors.New("connection reset")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "commit turned into a rollback", err: pgx.ErrTxCommitRollback, want: ErrTxCommitRollback},
+		{name: "already closed", err: pgx.ErrTxClosed, want: ErrTxClosed},
+		{name: "other errors are unchanged", err: connErr, want: connErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &PostgresTransaction{tx: endingTx{err: tt.err}}
+
+			for _, err := range []error{tx.Commit(context.Background()), tx.Rollback(context.Background())} {
+				assert.ErrorIs(t, err, tt.want)
+				assert.ErrorIs(t, err, tt.err, "the driver error is kept")
+			}
+		})
+	}
+	assert.NoError(t, (&PostgresTransaction{tx: endingTx{}}).Commit(context.Background()))
+}
@@ -0,0 +1,111 @@
+package revision_repository_postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	model "pinstack-post-service/internal/domain/models"
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+)
+
+type RevisionRepository struct {
+	log     ports.Logger
+	db      db.PgDB
+	metrics ports.MetricsProvider
+}
+
+func NewRevisionRepository(db db.PgDB, log ports.Logger, metrics ports.MetricsProvider) *RevisionRepository {
+	return &RevisionRepository{db: db, log: log, metrics: metrics}
+}
+
+func (r *RevisionRepository) Record(ctx context.Context, revision *model.PostRevision) (result *model.PostRevision, err error) {
+	defer db.ObserveQuery(r.metrics, r.log, "revision_record", time.Now(), &err, slog.Int64("post_id", revision.PostID))
+
+	// The post's edit count is the number of the revision, so both move in one statement.
+	recorded := *revision
+	err = r.db.QueryRow(ctx, `
+		WITH bumped AS (
+			UPDATE posts SET edit_count = edit_count + 1 WHERE id = @post_id RETURNING edit_count
+		)
+		INSERT INTO post_revisions (post_id, revision, title, content, editor_id)
+		SELECT @post_id, edit_count, @title, @content, @editor_id FROM bumped
+		RETURNING id, revision, created_at`,
+		pgx.NamedArgs{
+			"post_id":   revision.PostID,
+			"title":     revision.Title,
+			"content":   revision.Content,
+			"editor_id": revision.EditorID,
+		},
+	).Scan(&recorded.ID, &recorded.Revision, &recorded.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.log.Debug("Post not found when recording revision", slog.Int64("post_id", revision.PostID))
+			return nil, custom_errors.ErrPostNotFound
+		}
+		r.log.Error("Error recording post revision", slog.Int64("post_id", revision.PostID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return &recorded, nil
+}
+
+func (r *RevisionRepository) Prune(ctx context.Context, postID int64, keep int) (pruned int, err error) {
+	defer db.ObserveQuery(r.metrics, r.log, "revision_prune", time.Now(), &err, slog.Int64("post_id", postID))
+
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM post_revisions
+		WHERE id IN (
+			SELECT id FROM post_revisions WHERE post_id = @post_id
+			ORDER BY revision DESC
+			OFFSET @keep
+		)`,
+		pgx.NamedArgs{"post_id": postID, "keep": keep},
+	)
+	if err != nil {
+		r.log.Error("Error pruning post revisions", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+func (r *RevisionRepository) ListByPost(ctx context.Context, postID int64, limit, offset int) (revisions []*model.PostRevision, total int, err error) {
+	defer db.ObserveQuery(r.metrics, r.log, "revision_list_by_post", time.Now(), &err, slog.Int64("post_id", postID))
+
+	args := pgx.NamedArgs{"post_id": postID, "limit": limit, "offset": offset}
+	rows, err := r.db.Query(ctx, `
+		SELECT id, post_id, revision, title, content, editor_id, created_at
+		FROM post_revisions
+		WHERE post_id = @post_id
+		ORDER BY revision DESC
+		LIMIT @limit OFFSET @offset`, args)
+	if err != nil {
+		r.log.Error("Error listing post revisions", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	defer rows.Close()
+
+	revisions = []*model.PostRevision{}
+	for rows.Next() {
+		var revision model.PostRevision
+		if err := rows.Scan(&revision.ID, &revision.PostID, &revision.Revision, &revision.Title, &revision.Content, &revision.EditorID, &revision.CreatedAt); err != nil {
+			r.log.Error("Error scanning post revision row", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+			return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+		}
+		revisions = append(revisions, &revision)
+	}
+	if err = rows.Err(); err != nil {
+		r.log.Error("Error iterating post revision rows", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+
+	err = r.db.QueryRow(ctx, `SELECT count(*) FROM post_revisions WHERE post_id = @post_id`, pgx.NamedArgs{"post_id": postID}).Scan(&total)
+	if err != nil {
+		r.log.Error("Error counting post revisions", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, 0, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return revisions, total, nil
+}
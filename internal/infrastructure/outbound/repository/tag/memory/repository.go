@@ -3,6 +3,8 @@ package memory
 import (
 	"context"
 	ports "pinstack-post-service/internal/domain/ports/output"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
@@ -19,6 +21,11 @@ type TagRepository struct {
 	postsByTagID map[int64]map[int64]bool
 	postExists   map[int64]bool
 	nextID       int64
+
+	// postLookup, when set, replaces postExists. See SetPostLookup.
+	postLookup func(postID int64) bool
+	// authorOf, when set, lets SearchTags boost the tags of an author. See SetAuthorLookup.
+	authorOf func(postID int64) (int64, bool)
 }
 
 func NewTagRepository(log ports.Logger) *TagRepository {
@@ -39,6 +46,97 @@ func (t *TagRepository) SimulatePostExists(postID int64, exists bool) {
 	t.postExists[postID] = exists
 }
 
+// SetPostLookup makes the repository ask exists whether a post is stored instead of relying
+// on SimulatePostExists. exists is called with the repository lock held and must not call back
+// into it.
+func (t *TagRepository) SetPostLookup(exists func(postID int64) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.postLookup = exists
+}
+
+// SetAuthorLookup makes SearchTags read post authors through authorOf. Without it no tag
+// counts as used by the author. authorOf is called with the repository lock held and must not
+// call back into it.
+func (t *TagRepository) SetAuthorLookup(authorOf func(postID int64) (int64, bool)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.authorOf = authorOf
+}
+
+func (t *TagRepository) hasPost(postID int64) bool {
+	if t.postLookup != nil {
+		return t.postLookup(postID)
+	}
+	return t.postExists[postID]
+}
+
+// TagNames returns the names of the tags of postID, for the post repository's tag filter.
+func (t *TagRepository) TagNames(postID int64) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	names := make([]string, 0, len(t.postTags[postID]))
+	for tagID := range t.postTags[postID] {
+		if tag, found := t.tags[tagID]; found {
+			names = append(names, tag.Name)
+		}
+	}
+	return names
+}
+
+// RemovePost drops the tags of a deleted post, as ON DELETE CASCADE does in Postgres.
+func (t *TagRepository) RemovePost(postID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for tagID := range t.postTags[postID] {
+		delete(t.postsByTagID[tagID], postID)
+	}
+	delete(t.postTags, postID)
+}
+
+// Snapshot copies the stored tags and post links and returns a function that puts the copy
+// back. Ids are not reused after a restore, as a Postgres sequence is not rolled back either.
+func (t *TagRepository) Snapshot() (restore func()) {
+	t.mu.RLock()
+	tags := make(map[int64]*model.Tag, len(t.tags))
+	tagsByName := make(map[string]*model.Tag, len(t.tagsByName))
+	for id, tag := range t.tags {
+		tagCopy := *tag
+		tags[id] = &tagCopy
+		tagsByName[tagCopy.Name] = &tagCopy
+	}
+	postTags := copyLinks(t.postTags)
+	postsByTagID := copyLinks(t.postsByTagID)
+	postExists := make(map[int64]bool, len(t.postExists))
+	for id, exists := range t.postExists {
+		postExists[id] = exists
+	}
+	t.mu.RUnlock()
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.tags = tags
+		t.tagsByName = tagsByName
+		t.postTags = postTags
+		t.postsByTagID = postsByTagID
+		t.postExists = postExists
+	}
+}
+
+func copyLinks(links map[int64]map[int64]bool) map[int64]map[int64]bool {
+	result := make(map[int64]map[int64]bool, len(links))
+	for id, linked := range links {
+		result[id] = make(map[int64]bool, len(linked))
+		for other := range linked {
+			result[id][other] = true
+		}
+	}
+	return result
+}
+
 func (t *TagRepository) FindByNames(ctx context.Context, names []string) ([]*model.Tag, error) {
 	if len(names) == 0 {
 		return nil, nil
@@ -71,6 +169,30 @@ func (t *TagRepository) FindByPost(ctx context.Context, postID int64) ([]*model.
 			}
 		}
 	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, nil
+}
+
+func (t *TagRepository) FindByPosts(ctx context.Context, postIDs []int64) (map[int64][]*model.Tag, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	result := make(map[int64][]*model.Tag)
+	for _, postID := range postIDs {
+		if _, done := result[postID]; done {
+			continue
+		}
+		for tagID := range t.postTags[postID] {
+			if tag, found := t.tags[tagID]; found {
+				tagCopy := *tag
+				result[postID] = append(result[postID], &tagCopy)
+			}
+		}
+		sort.Slice(result[postID], func(i, j int) bool {
+			return result[postID][i].Name < result[postID][j].Name
+		})
+	}
 
 	return result, nil
 }
@@ -98,6 +220,31 @@ func (t *TagRepository) Create(ctx context.Context, name string) (*model.Tag, er
 	return &tagCopy, nil
 }
 
+func (t *TagRepository) CreateMany(ctx context.Context, names []string) ([]*model.Tag, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tags := make([]*model.Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		tag, exists := t.tagsByName[name]
+		if !exists {
+			tag = &model.Tag{ID: t.nextID, Name: name}
+			t.nextID++
+			t.tags[tag.ID] = tag
+			t.tagsByName[tag.Name] = tag
+			t.postsByTagID[tag.ID] = make(map[int64]bool)
+		}
+		tagCopy := *tag
+		tags = append(tags, &tagCopy)
+	}
+	return tags, nil
+}
+
 func (t *TagRepository) DeleteUnused(ctx context.Context) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -123,36 +270,85 @@ func (t *TagRepository) TagPost(ctx context.Context, postID int64, tagNames []st
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	if exists, found := t.postExists[postID]; !found || !exists {
+	if !t.hasPost(postID) {
 		return custom_errors.ErrPostNotFound
 	}
 
+	// Tags must exist, as in Postgres; nothing is linked if one of them does not.
+	for _, tagName := range tagNames {
+		if _, exists := t.tagsByName[tagName]; !exists {
+			return custom_errors.ErrTagNotFound
+		}
+	}
+	t.link(postID, tagNames)
+
+	return nil
+}
+
+func (t *TagRepository) link(postID int64, tagNames []string) {
 	if _, exists := t.postTags[postID]; !exists {
 		t.postTags[postID] = make(map[int64]bool)
 	}
-
 	for _, tagName := range tagNames {
-		var tag *model.Tag
-		if existingTag, exists := t.tagsByName[tagName]; exists {
-			tag = existingTag
-		} else {
-			tag = &model.Tag{
-				ID:   t.nextID,
-				Name: tagName,
-			}
-			t.nextID++
-			t.tags[tag.ID] = tag
-			t.tagsByName[tagName] = tag
+		tag := t.tagsByName[tagName]
+		t.postTags[postID][tag.ID] = true
+		if _, exists := t.postsByTagID[tag.ID]; !exists {
 			t.postsByTagID[tag.ID] = make(map[int64]bool)
 		}
-
-		t.postTags[postID][tag.ID] = true
 		t.postsByTagID[tag.ID][postID] = true
 	}
+}
 
+// LinkPosts links nothing when a post or tag is missing, like the single INSERT in Postgres.
+func (t *TagRepository) LinkPosts(ctx context.Context, links []*model.PostTag) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, link := range links {
+		if !t.hasPost(link.PostID) {
+			return custom_errors.ErrPostNotFound
+		}
+		if _, exists := t.tags[link.TagID]; !exists {
+			return custom_errors.ErrTagNotFound
+		}
+	}
+	for _, link := range links {
+		if _, exists := t.postTags[link.PostID]; !exists {
+			t.postTags[link.PostID] = make(map[int64]bool)
+		}
+		t.postTags[link.PostID][link.TagID] = true
+		t.postsByTagID[link.TagID][link.PostID] = true
+	}
 	return nil
 }
 
+func (t *TagRepository) TagPostExisting(ctx context.Context, postID int64, tagNames []string) ([]string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	missing := []string{}
+	if len(tagNames) == 0 {
+		return missing, nil
+	}
+	if !t.hasPost(postID) {
+		return nil, custom_errors.ErrPostNotFound
+	}
+	if _, exists := t.postTags[postID]; !exists {
+		t.postTags[postID] = make(map[int64]bool)
+	}
+
+	for _, tagName := range tagNames {
+		tag, exists := t.tagsByName[tagName]
+		if !exists {
+			missing = append(missing, tagName)
+			continue
+		}
+		t.postTags[postID][tag.ID] = true
+		t.postsByTagID[tag.ID][postID] = true
+	}
+	return missing, nil
+}
+
 func (t *TagRepository) UntagPost(ctx context.Context, postID int64, tagNames []string) error {
 	if len(tagNames) == 0 {
 		return nil
@@ -161,7 +357,7 @@ func (t *TagRepository) UntagPost(ctx context.Context, postID int64, tagNames []
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	if exists, found := t.postExists[postID]; !found || !exists {
+	if !t.hasPost(postID) {
 		return custom_errors.ErrPostNotFound
 	}
 
@@ -183,9 +379,14 @@ func (t *TagRepository) ReplacePostTags(ctx context.Context, postID int64, newTa
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	if exists, found := t.postExists[postID]; !found || !exists {
+	if !t.hasPost(postID) {
 		return custom_errors.ErrPostNotFound
 	}
+	for _, tagName := range newTags {
+		if _, exists := t.tagsByName[tagName]; !exists {
+			return custom_errors.ErrTagNotFound
+		}
+	}
 
 	if oldTags, exists := t.postTags[postID]; exists {
 		for tagID := range oldTags {
@@ -196,28 +397,146 @@ func (t *TagRepository) ReplacePostTags(ctx context.Context, postID int64, newTa
 	}
 
 	t.postTags[postID] = make(map[int64]bool)
+	t.link(postID, newTags)
 
-	for _, tagName := range newTags {
-		var tag *model.Tag
-		if existingTag, exists := t.tagsByName[tagName]; exists {
-			tag = existingTag
-		} else {
-			tag = &model.Tag{
-				ID:   t.nextID,
-				Name: tagName,
+	return nil
+}
+
+func (t *TagRepository) FindPostIDsByTags(ctx context.Context, tagIDs []int64) ([]int64, error) {
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	seen := make(map[int64]bool)
+	var result []int64
+	for _, tagID := range tagIDs {
+		for postID := range t.postsByTagID[tagID] {
+			if !seen[postID] {
+				seen[postID] = true
+				result = append(result, postID)
 			}
-			t.nextID++
-			t.tags[tag.ID] = tag
-			t.tagsByName[tagName] = tag
-			t.postsByTagID[tag.ID] = make(map[int64]bool)
 		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
 
-		t.postTags[postID][tag.ID] = true
-		if _, exists := t.postsByTagID[tag.ID]; !exists {
-			t.postsByTagID[tag.ID] = make(map[int64]bool)
+	return result, nil
+}
+
+func (t *TagRepository) CountPosts(ctx context.Context, tagIDs []int64) (map[int64]int64, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	counts := make(map[int64]int64, len(tagIDs))
+	for _, tagID := range tagIDs {
+		if n := len(t.postsByTagID[tagID]); n > 0 {
+			counts[tagID] = int64(n)
 		}
-		t.postsByTagID[tag.ID][postID] = true
 	}
 
-	return nil
+	return counts, nil
+}
+
+func (t *TagRepository) SearchTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error) {
+	prefix = model.NormalizeTagName(prefix)
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	type match struct {
+		tag        *model.Tag
+		usage      int64
+		authorUsed bool
+	}
+	var matches []match
+	for _, tag := range t.tags {
+		if !strings.HasPrefix(tag.Name, prefix) {
+			continue
+		}
+		m := match{tag: tag, usage: int64(len(t.postsByTagID[tag.ID]))}
+		if authorID != nil && t.authorOf != nil {
+			for postID := range t.postsByTagID[tag.ID] {
+				if author, found := t.authorOf(postID); found && author == *authorID {
+					m.authorUsed = true
+					break
+				}
+			}
+		}
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].authorUsed != matches[j].authorUsed {
+			return matches[i].authorUsed
+		}
+		if matches[i].usage != matches[j].usage {
+			return matches[i].usage > matches[j].usage
+		}
+		return matches[i].tag.Name < matches[j].tag.Name
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	result := make([]*model.Tag, 0, len(matches))
+	for _, m := range matches {
+		tagCopy := *m.tag
+		usage := m.usage
+		tagCopy.PostCount = &usage
+		result = append(result, &tagCopy)
+	}
+	return result, nil
+}
+
+func (t *TagRepository) Rename(ctx context.Context, id int64, name string) (*model.Tag, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tag, exists := t.tags[id]
+	if !exists {
+		return nil, custom_errors.ErrTagNotFound
+	}
+	if other, taken := t.tagsByName[name]; taken && other.ID != id {
+		return nil, custom_errors.ErrTagAlreadyExists
+	}
+
+	delete(t.tagsByName, tag.Name)
+	tag.Name = name
+	t.tagsByName[name] = tag
+
+	tagCopy := *tag
+	return &tagCopy, nil
+}
+
+func (t *TagRepository) Merge(ctx context.Context, sourceIDs []int64, destID int64) (*model.Tag, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	dest, exists := t.tags[destID]
+	if !exists {
+		return nil, custom_errors.ErrTagNotFound
+	}
+	for _, sourceID := range sourceIDs {
+		if _, found := t.tags[sourceID]; !found {
+			return nil, custom_errors.ErrTagNotFound
+		}
+	}
+
+	if _, exists := t.postsByTagID[destID]; !exists {
+		t.postsByTagID[destID] = make(map[int64]bool)
+	}
+	for _, sourceID := range sourceIDs {
+		for postID := range t.postsByTagID[sourceID] {
+			delete(t.postTags[postID], sourceID)
+			t.postTags[postID][destID] = true
+			t.postsByTagID[destID][postID] = true
+		}
+		delete(t.tagsByName, t.tags[sourceID].Name)
+		delete(t.tags, sourceID)
+		delete(t.postsByTagID, sourceID)
+	}
+
+	tagCopy := *dest
+	return &tagCopy, nil
 }
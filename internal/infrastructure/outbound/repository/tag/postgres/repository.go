@@ -8,6 +8,7 @@ import (
 	model "pinstack-post-service/internal/domain/models"
 	ports "pinstack-post-service/internal/domain/ports/output"
 	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	"strings"
 	"time"
 
 	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
@@ -16,22 +17,25 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// postTagsLock names the lock of lockPostTags in metrics.
+const postTagsLock = "post_tags"
+
 type TagRepository struct {
-	log     ports.Logger
-	db      db.PgDB
-	metrics ports.MetricsProvider
+	log         ports.Logger
+	db          db.PgDB
+	metrics     ports.MetricsProvider
+	limits      db.BatchLimits
+	lockTimeout time.Duration
 }
 
-func NewTagRepository(db db.PgDB, log ports.Logger, metrics ports.MetricsProvider) *TagRepository {
-	return &TagRepository{db: db, log: log, metrics: metrics}
+// NewTagRepository bounds the wait for the lock on the tags of a post by lockTimeout; zero
+// waits as long as the query timeout allows.
+func NewTagRepository(pgDB db.PgDB, log ports.Logger, metrics ports.MetricsProvider, limits db.BatchLimits, lockTimeout time.Duration) *TagRepository {
+	return &TagRepository{db: pgDB, log: log, metrics: metrics, limits: limits, lockTimeout: lockTimeout}
 }
 
 func (t *TagRepository) FindByNames(ctx context.Context, names []string) (result []*model.Tag, err error) {
-	start := time.Now()
-	defer func() {
-		t.metrics.RecordDatabaseQueryDuration("tag_find_by_names", time.Since(start))
-		t.metrics.IncrementDatabaseQueries("tag_find_by_names", err == nil)
-	}()
+	defer db.ObserveQuery(t.metrics, t.log, "tag_find_by_names", time.Now(), &err, slog.Int("count", len(names)))
 
 	if len(names) == 0 {
 		return nil, nil
@@ -43,7 +47,7 @@ func (t *TagRepository) FindByNames(ctx context.Context, names []string) (result
 	rows, err := t.db.Query(ctx, query, args)
 	if err != nil {
 		t.log.Error("Error finding tags by names", slog.String("error", err.Error()))
-		return nil, custom_errors.ErrTagQueryFailed
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
 	}
 	defer rows.Close()
 
@@ -52,32 +56,33 @@ func (t *TagRepository) FindByNames(ctx context.Context, names []string) (result
 		var tag model.Tag
 		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
 			t.log.Error("Error scanning tag row", slog.String("error", err.Error()))
-			return nil, custom_errors.ErrTagScanFailed
+			return nil, db.WithCause(custom_errors.ErrTagScanFailed, err)
 		}
 		tags = append(tags, &tag)
 	}
+	if err = rows.Err(); err != nil {
+		t.log.Error("Error iterating tag rows", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
 	return tags, nil
 }
 
 func (t *TagRepository) FindByPost(ctx context.Context, postID int64) (result []*model.Tag, err error) {
-	start := time.Now()
-	defer func() {
-		t.metrics.RecordDatabaseQueryDuration("tag_find_by_post", time.Since(start))
-		t.metrics.IncrementDatabaseQueries("tag_find_by_post", err == nil)
-	}()
+	defer db.ObserveQuery(t.metrics, t.log, "tag_find_by_post", time.Now(), &err, slog.Int64("post_id", postID))
 
 	query := `
 		SELECT t.id, t.name 
 		FROM tags t
 		INNER JOIN posts_tags pt ON pt.tag_id = t.id
-		WHERE pt.post_id = @post_id`
+		WHERE pt.post_id = @post_id
+		ORDER BY t.name`
 
 	args := pgx.NamedArgs{"post_id": postID}
 
 	rows, err := t.db.Query(ctx, query, args)
 	if err != nil {
 		t.log.Error("Error finding tags by post", slog.Int64("post_id", postID), slog.String("error", err.Error()))
-		return nil, custom_errors.ErrTagQueryFailed
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
 	}
 	defer rows.Close()
 
@@ -86,17 +91,54 @@ func (t *TagRepository) FindByPost(ctx context.Context, postID int64) (result []
 		var tag model.Tag
 		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
 			t.log.Error("Error scanning tag row", slog.String("error", err.Error()))
-			return nil, custom_errors.ErrTagScanFailed
+			return nil, db.WithCause(custom_errors.ErrTagScanFailed, err)
 		}
 		tags = append(tags, &tag)
 	}
+	if err = rows.Err(); err != nil {
+		t.log.Error("Error iterating tag rows", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
 	return tags, nil
 }
 
+func (t *TagRepository) FindByPosts(ctx context.Context, postIDs []int64) (result map[int64][]*model.Tag, err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_find_by_posts", time.Now(), &err, slog.Int("count", len(postIDs)))
+
+	query := `
+		SELECT pt.post_id, t.id, t.name
+		FROM tags t
+		INNER JOIN posts_tags pt ON pt.tag_id = t.id
+		WHERE pt.post_id = ANY(@post_ids)
+		ORDER BY pt.post_id, t.name`
+
+	rows, err := t.db.Query(ctx, query, pgx.NamedArgs{"post_ids": postIDs})
+	if err != nil {
+		t.log.Error("Error finding tags by posts", slog.Int("post_count", len(postIDs)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	defer rows.Close()
+
+	result = make(map[int64][]*model.Tag)
+	for rows.Next() {
+		var postID int64
+		var tag model.Tag
+		if err := rows.Scan(&postID, &tag.ID, &tag.Name); err != nil {
+			t.log.Error("Error scanning tag row", slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrTagScanFailed, err)
+		}
+		result[postID] = append(result[postID], &tag)
+	}
+	if err = rows.Err(); err != nil {
+		t.log.Error("Error iterating tag rows", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	return result, nil
+}
+
 func (t *TagRepository) Create(ctx context.Context, name string) (result *model.Tag, err error) {
-	start := time.Now()
+	defer db.ObserveQuery(t.metrics, t.log, "tag_create", time.Now(), &err)
 	defer func() {
-		t.metrics.RecordDatabaseQueryDuration("tag_create", time.Since(start))
 		t.metrics.IncrementTagOperations("create", err == nil)
 	}()
 
@@ -135,187 +177,574 @@ func (t *TagRepository) Create(ctx context.Context, name string) (result *model.
 	return &tag, nil
 }
 
-func (t *TagRepository) DeleteUnused(ctx context.Context) (err error) {
-	start := time.Now()
+func (t *TagRepository) CreateMany(ctx context.Context, names []string) (result []*model.Tag, err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_create_many", time.Now(), &err, slog.Int("count", len(names)))
 	defer func() {
-		t.metrics.RecordDatabaseQueryDuration("tag_delete_unused", time.Since(start))
-		t.metrics.IncrementDatabaseQueries("tag_delete_unused", err == nil)
+		t.metrics.IncrementTagOperations("create_many", err == nil)
 	}()
+	if len(names) == 0 {
+		return []*model.Tag{}, nil
+	}
+
+	// The final SELECT reads the snapshot taken before the INSERT, so it returns the names that
+	// existed before; inserted holds the rest. Inserting in name order keeps concurrent batches
+	// from deadlocking on the unique index.
+	query := `
+		WITH input AS (
+			SELECT DISTINCT unnest(@names::text[]) AS name
+		), inserted AS (
+			INSERT INTO tags(name)
+			SELECT name FROM input ORDER BY name
+			ON CONFLICT (name) DO NOTHING
+			RETURNING id, name
+		)
+		SELECT id, name FROM inserted
+		UNION ALL
+		SELECT t.id, t.name FROM tags t JOIN input i ON i.name = t.name`
+
+	rows, err := t.db.Query(ctx, query, pgx.NamedArgs{"names": names})
+	if err != nil {
+		t.log.Error("Error creating tags", slog.Int("names_count", len(names)), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagCreateFailed, err)
+	}
+	defer rows.Close()
+
+	byName := make(map[string]*model.Tag, len(names))
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			t.log.Error("Error scanning created tag", slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrTagCreateFailed, err)
+		}
+		byName[tag.Name] = &tag
+	}
+	if err := rows.Err(); err != nil {
+		t.log.Error("Error iterating created tags", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagCreateFailed, err)
+	}
 
-	query := `DELETE FROM tags WHERE id NOT IN (SELECT DISTINCT tag_id FROM posts_tags)`
+	// A tag another transaction committed while the INSERT waited on it conflicts but is not
+	// in the snapshot; a new statement sees it.
+	var missing []string
+	for _, name := range names {
+		if _, ok := byName[name]; !ok {
+			byName[name] = nil
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		found, err := t.FindByNames(ctx, missing)
+		if err != nil {
+			return nil, db.WithCause(custom_errors.ErrTagCreateFailed, err)
+		}
+		if len(found) != len(missing) {
+			t.log.Error("Tags exist but could not be fetched", slog.Any("names", missing))
+			return nil, custom_errors.ErrTagCreateFailed
+		}
+		for _, tag := range found {
+			byName[tag.Name] = tag
+		}
+	}
+
+	result = make([]*model.Tag, 0, len(byName))
+	for _, tag := range byName {
+		result = append(result, tag)
+	}
+	return result, nil
+}
+
+func (t *TagRepository) DeleteUnused(ctx context.Context) (err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_delete_unused", time.Now(), &err)
+
+	// Tags of archived posts are still in use: archived permalinks show them.
+	query := `DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM posts_tags UNION SELECT tag_id FROM posts_tags_archive)`
 
 	_, err = t.db.Exec(ctx, query)
 	if err != nil {
 		t.log.Error("Error deleting unused tags", slog.String("error", err.Error()))
-		return custom_errors.ErrTagDeleteFailed
+		return db.WithCause(custom_errors.ErrTagDeleteFailed, err)
 	}
 	return nil
 }
 
 func (t *TagRepository) TagPost(ctx context.Context, postID int64, tagNames []string) (err error) {
-	start := time.Now()
+	defer db.ObserveQuery(t.metrics, t.log, "tag_post", time.Now(), &err, slog.Int64("post_id", postID), slog.Int("count", len(tagNames)))
 	defer func() {
-		t.metrics.RecordDatabaseQueryDuration("tag_post", time.Since(start))
 		t.metrics.IncrementTagOperations("tag_post", err == nil)
 	}()
 
+	if err = db.CheckBatchSize("tags", len(tagNames), t.limits.MaxTags); err != nil {
+		return err
+	}
+
 	if len(tagNames) == 0 {
 		return nil
 	}
 
-	_, err = t.db.Exec(ctx, "SELECT 1 FROM posts WHERE id = @post_id", pgx.NamedArgs{"post_id": postID})
+	if err = t.lockPostTags(ctx, postID); err != nil {
+		return err
+	}
+	exists, err := t.postExists(ctx, postID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return custom_errors.ErrPostNotFound
-		}
-		return custom_errors.ErrTagVerifyPostFailed
+		return db.WithCause(custom_errors.ErrTagVerifyPostFailed, err)
+	}
+	if !exists {
+		return custom_errors.ErrPostNotFound
 	}
 
-	batch := &pgx.Batch{}
-	query := `INSERT INTO posts_tags (post_id, tag_id) VALUES (@post_id, (SELECT id FROM tags WHERE name = @tag_name))`
-
-	for _, tagName := range tagNames {
-		args := pgx.NamedArgs{
-			"post_id":  postID,
-			"tag_name": tagName,
+	// A link that already exists must not raise 23505: the error would abort the batch, and
+	// the caller's transaction with it, even if it were ignored here.
+	query := `INSERT INTO posts_tags (post_id, tag_id) VALUES (@post_id, (SELECT id FROM tags WHERE name = @tag_name))
+		ON CONFLICT DO NOTHING`
+	err = db.SendChunked(ctx, t.db, len(tagNames), db.BatchChunkSize,
+		func(b *pgx.Batch, i int) {
+			b.Queue(query, pgx.NamedArgs{"post_id": postID, "tag_name": tagNames[i]})
+		},
+		func(br pgx.BatchResults, _ int) error {
+			_, err := br.Exec()
+			return err
+		})
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && (pgerr.Code == "23502" || pgerr.Code == "23503") {
+			// An unknown name makes the tag_id subquery NULL.
+			return custom_errors.ErrTagNotFound
 		}
-		batch.Queue(query, args)
+		t.log.Error("Error tagging post", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return db.WithCause(custom_errors.ErrTagPost, err)
 	}
+	return nil
+}
 
-	br := t.db.SendBatch(ctx, batch)
-	defer func(br pgx.BatchResults) {
-		err := br.Close()
-		if err != nil {
-			t.log.Error("Failed to close batch result in TagPost", slog.String("error", err.Error()), slog.Int64("post_id", postID))
-		}
-	}(br)
+// LinkPosts inserts the links with one INSERT over unnest'ed columns; the foreign key that
+// fails tells a missing post from a missing tag.
+func (t *TagRepository) LinkPosts(ctx context.Context, links []*model.PostTag) (err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_link_posts", time.Now(), &err, slog.Int("count", len(links)))
+	defer func() {
+		t.metrics.IncrementTagOperations("link_posts", err == nil)
+	}()
 
-	for range tagNames {
-		_, err := br.Exec()
-		if err != nil {
-			var pgerr *pgconn.PgError
-			if errors.As(err, &pgerr) {
-				switch pgerr.Code {
-				case "23505":
-					continue
-				case "23503":
-					return custom_errors.ErrTagNotFound
-				}
+	if len(links) == 0 {
+		return nil
+	}
+	postIDs := make([]int64, len(links))
+	tagIDs := make([]int64, len(links))
+	for i, link := range links {
+		postIDs[i], tagIDs[i] = link.PostID, link.TagID
+	}
+
+	_, err = t.db.Exec(ctx, `
+		INSERT INTO posts_tags (post_id, tag_id)
+		SELECT * FROM unnest(@post_ids::bigint[], @tag_ids::bigint[])
+		ON CONFLICT DO NOTHING`,
+		pgx.NamedArgs{"post_ids": postIDs, "tag_ids": tagIDs})
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == "23503" {
+			if pgerr.ConstraintName == "posts_tags_post_id_fkey" {
+				return custom_errors.ErrPostNotFound
 			}
-			t.log.Error("Error tagging post", slog.Int64("post_id", postID), slog.String("error", err.Error()))
-			return custom_errors.ErrTagPost
+			return custom_errors.ErrTagNotFound
 		}
+		t.log.Error("Error linking posts to tags", slog.Int("count", len(links)), slog.String("error", err.Error()))
+		return db.WithCause(custom_errors.ErrTagPost, err)
 	}
 	return nil
 }
 
+func (t *TagRepository) TagPostExisting(ctx context.Context, postID int64, tagNames []string) (missing []string, err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_post_existing", time.Now(), &err, slog.Int64("post_id", postID), slog.Int("count", len(tagNames)))
+	defer func() {
+		t.metrics.IncrementTagOperations("tag_post_existing", err == nil)
+	}()
+
+	if err = db.CheckBatchSize("tags", len(tagNames), t.limits.MaxTags); err != nil {
+		return nil, err
+	}
+
+	missing = []string{}
+	if len(tagNames) == 0 {
+		return missing, nil
+	}
+
+	if err = t.lockPostTags(ctx, postID); err != nil {
+		return nil, err
+	}
+	exists, err := t.postExists(ctx, postID)
+	if err != nil {
+		return nil, db.WithCause(custom_errors.ErrTagVerifyPostFailed, err)
+	}
+	if !exists {
+		return nil, custom_errors.ErrPostNotFound
+	}
+
+	// Unlike TagPost, a missing tag inserts nothing instead of violating a constraint, which
+	// would abort the whole transaction.
+	query := `
+		WITH tag AS (SELECT id FROM tags WHERE name = @tag_name),
+		tagged AS (
+			INSERT INTO posts_tags (post_id, tag_id)
+			SELECT @post_id, id FROM tag
+			ON CONFLICT DO NOTHING
+		)
+		SELECT EXISTS (SELECT 1 FROM tag)`
+	err = db.SendChunked(ctx, t.db, len(tagNames), db.BatchChunkSize,
+		func(b *pgx.Batch, i int) {
+			b.Queue(query, pgx.NamedArgs{"post_id": postID, "tag_name": tagNames[i]})
+		},
+		func(br pgx.BatchResults, i int) error {
+			var found bool
+			if err := br.QueryRow().Scan(&found); err != nil {
+				return err
+			}
+			if !found {
+				missing = append(missing, tagNames[i])
+			}
+			return nil
+		})
+	if err != nil {
+		t.log.Error("Error tagging post", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagPost, err)
+	}
+	return missing, nil
+}
+
 func (t *TagRepository) UntagPost(ctx context.Context, postID int64, tagNames []string) (err error) {
-	start := time.Now()
+	defer db.ObserveQuery(t.metrics, t.log, "untag_post", time.Now(), &err, slog.Int64("post_id", postID), slog.Int("count", len(tagNames)))
 	defer func() {
-		t.metrics.RecordDatabaseQueryDuration("untag_post", time.Since(start))
 		t.metrics.IncrementTagOperations("untag_post", err == nil)
 	}()
 
+	if err = db.CheckBatchSize("tags", len(tagNames), t.limits.MaxTags); err != nil {
+		return err
+	}
+
 	if len(tagNames) == 0 {
 		return nil
 	}
 
-	_, err = t.db.Exec(ctx, "SELECT 1 FROM posts WHERE id = @post_id", pgx.NamedArgs{"post_id": postID})
+	if err = t.lockPostTags(ctx, postID); err != nil {
+		return err
+	}
+	exists, err := t.postExists(ctx, postID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return custom_errors.ErrPostNotFound
-		}
-		return custom_errors.ErrTagVerifyPostFailed
+		return db.WithCause(custom_errors.ErrTagVerifyPostFailed, err)
+	}
+	if !exists {
+		return custom_errors.ErrPostNotFound
 	}
 
-	batch := &pgx.Batch{}
 	query := `DELETE FROM posts_tags 
 		WHERE post_id = @post_id 
 		AND tag_id = (SELECT id FROM tags WHERE name = @tag_name)`
-
-	for _, tagName := range tagNames {
-		args := pgx.NamedArgs{
-			"post_id":  postID,
-			"tag_name": tagName,
-		}
-		batch.Queue(query, args)
-	}
-
-	br := t.db.SendBatch(ctx, batch)
-	defer func(br pgx.BatchResults) {
-		err := br.Close()
-		if err != nil {
-			t.log.Error("Failed to close batch result in UntagPost", slog.String("error", err.Error()), slog.Int64("post_id", postID))
-		}
-	}(br)
-
-	for range tagNames {
-		_, err := br.Exec()
-		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-			var pgerr *pgconn.PgError
-			if errors.As(err, &pgerr) && pgerr.Code == "23503" {
-				return custom_errors.ErrTagNotFound
+	err = db.SendChunked(ctx, t.db, len(tagNames), db.BatchChunkSize,
+		func(b *pgx.Batch, i int) {
+			b.Queue(query, pgx.NamedArgs{"post_id": postID, "tag_name": tagNames[i]})
+		},
+		func(br pgx.BatchResults, _ int) error {
+			if _, err := br.Exec(); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return err
 			}
-			t.log.Error("Error untagging post", slog.Int64("post_id", postID), slog.String("error", err.Error()))
-			return err
+			return nil
+		})
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == "23503" {
+			return custom_errors.ErrTagNotFound
 		}
+		t.log.Error("Error untagging post", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return err
 	}
 	return nil
 }
 
 func (t *TagRepository) ReplacePostTags(ctx context.Context, postID int64, newTags []string) (err error) {
-	start := time.Now()
+	defer db.ObserveQuery(t.metrics, t.log, "replace_post_tags", time.Now(), &err, slog.Int64("post_id", postID), slog.Int("count", len(newTags)))
 	defer func() {
-		t.metrics.RecordDatabaseQueryDuration("replace_post_tags", time.Since(start))
 		t.metrics.IncrementTagOperations("replace_post_tags", err == nil)
 	}()
 
-	_, err = t.db.Exec(ctx, "SELECT 1 FROM posts WHERE id = @post_id", pgx.NamedArgs{"post_id": postID})
+	if err = db.CheckBatchSize("tags", len(newTags), t.limits.MaxTags); err != nil {
+		return err
+	}
+
+	if err = t.lockPostTags(ctx, postID); err != nil {
+		return err
+	}
+	exists, err := t.postExists(ctx, postID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return custom_errors.ErrPostNotFound
-		}
 		return fmt.Errorf("failed to verify post: %w", err)
 	}
+	if !exists {
+		return custom_errors.ErrPostNotFound
+	}
 
 	deleteQuery := `DELETE FROM posts_tags WHERE post_id = @post_id`
 	_, err = t.db.Exec(ctx, deleteQuery, pgx.NamedArgs{"post_id": postID})
 	if err != nil {
 		t.log.Error("Error deleting old tags", slog.String("error", err.Error()))
-		return custom_errors.ErrDatabaseQuery
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
 	}
 
-	if len(newTags) > 0 {
-		batch := &pgx.Batch{}
-		insertQuery := `INSERT INTO posts_tags (post_id, tag_id) VALUES (@post_id, (SELECT id FROM tags WHERE name = @tag_name))`
+	insertQuery := `INSERT INTO posts_tags (post_id, tag_id) VALUES (@post_id, (SELECT id FROM tags WHERE name = @tag_name))
+		ON CONFLICT DO NOTHING`
+	err = db.SendChunked(ctx, t.db, len(newTags), db.BatchChunkSize,
+		func(b *pgx.Batch, i int) {
+			b.Queue(insertQuery, pgx.NamedArgs{"post_id": postID, "tag_name": newTags[i]})
+		},
+		func(br pgx.BatchResults, _ int) error {
+			_, err := br.Exec()
+			return err
+		})
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && (pgerr.Code == "23502" || pgerr.Code == "23503") {
+			return custom_errors.ErrTagNotFound
+		}
+		t.log.Error("Error inserting new tags", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return nil
+}
 
-		for _, tagName := range newTags {
-			batch.Queue(insertQuery, pgx.NamedArgs{
-				"post_id":  postID,
-				"tag_name": tagName,
-			})
+// lockPostTags takes the advisory lock on the tags of postID, held until the caller's
+// transaction ends, so that concurrent changes to them queue instead of interleaving; outside
+// a transaction it is released at once. The single-key advisory lock space belongs to post
+// tags, keyed on the post ID. With a lockTimeout, lock waits for the rest of the transaction
+// are bounded by it, and one that runs out fails with an error db.IsRetryable reports.
+func (t *TagRepository) lockPostTags(ctx context.Context, postID int64) error {
+	if t.lockTimeout > 0 {
+		timeout := fmt.Sprintf("%dms", max(t.lockTimeout.Milliseconds(), 1))
+		if _, err := t.db.Exec(ctx, `SELECT set_config('lock_timeout', @timeout, true)`, pgx.NamedArgs{"timeout": timeout}); err != nil {
+			t.log.Error("Failed to set lock timeout", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+			return db.WithCause(custom_errors.ErrDatabaseQuery, err)
 		}
+	}
 
-		br := t.db.SendBatch(ctx, batch)
-		defer func(br pgx.BatchResults) {
-			err := br.Close()
-			if err != nil {
-				t.log.Error("Failed to close batch result in ReplacePostTags", slog.String("error", err.Error()), slog.Int64("post_id", postID))
-			}
-		}(br)
+	start := time.Now()
+	_, err := t.db.Exec(ctx, `SELECT pg_advisory_xact_lock(@post_id)`, pgx.NamedArgs{"post_id": postID})
+	t.metrics.RecordLockWaitDuration(postTagsLock, time.Since(start))
+	if err != nil {
+		if db.IsRetryable(err) {
+			t.log.Warn("Timed out waiting for the tags of a post", slog.Int64("post_id", postID), slog.Duration("timeout", t.lockTimeout))
+		} else {
+			t.log.Error("Failed to lock the tags of a post", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		}
+		return db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return nil
+}
 
-		for range newTags {
-			_, err := br.Exec()
-			if err != nil {
-				var pgerr *pgconn.PgError
-				if errors.As(err, &pgerr) && pgerr.Code == "23503" {
-					return custom_errors.ErrTagNotFound
-				}
-				t.log.Error("Error inserting new tags", slog.Int64("post_id", postID), slog.String("error", err.Error()))
-				return custom_errors.ErrDatabaseQuery
-			}
+func (t *TagRepository) postExists(ctx context.Context, postID int64) (bool, error) {
+	var exists bool
+	err := t.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = @post_id)`, pgx.NamedArgs{"post_id": postID}).Scan(&exists)
+	if err != nil {
+		t.log.Error("Failed to verify post existence", slog.Int64("post_id", postID), slog.String("error", err.Error()))
+		return false, err
+	}
+	if !exists {
+		t.log.Debug("Post not found while changing tags", slog.Int64("post_id", postID))
+	}
+	return exists, nil
+}
+
+func (t *TagRepository) FindPostIDsByTags(ctx context.Context, tagIDs []int64) (result []int64, err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_find_post_ids", time.Now(), &err, slog.Int("count", len(tagIDs)))
+
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT DISTINCT post_id FROM posts_tags WHERE tag_id = ANY(@tag_ids) ORDER BY post_id`
+	args := pgx.NamedArgs{"tag_ids": tagIDs}
+
+	rows, err := t.db.Query(ctx, query, args)
+	if err != nil {
+		t.log.Error("Error finding posts by tags", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	defer rows.Close()
+
+	var postIDs []int64
+	for rows.Next() {
+		var postID int64
+		if err := rows.Scan(&postID); err != nil {
+			t.log.Error("Error scanning post id row", slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrTagScanFailed, err)
 		}
+		postIDs = append(postIDs, postID)
+	}
+	if err = rows.Err(); err != nil {
+		t.log.Error("Error iterating post id rows", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
 	}
+	return postIDs, nil
+}
 
-	return nil
+// CountPosts returns the number of posts per tag in one grouped query. Tags without posts
+// are absent from the map.
+func (t *TagRepository) CountPosts(ctx context.Context, tagIDs []int64) (result map[int64]int64, err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_count_posts", time.Now(), &err, slog.Int("count", len(tagIDs)))
+
+	if len(tagIDs) == 0 {
+		return map[int64]int64{}, nil
+	}
+
+	query := `SELECT tag_id, COUNT(*) FROM posts_tags WHERE tag_id = ANY(@tag_ids) GROUP BY tag_id`
+	args := pgx.NamedArgs{"tag_ids": tagIDs}
+
+	rows, err := t.db.Query(ctx, query, args)
+	if err != nil {
+		t.log.Error("Error counting posts by tags", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int64, len(tagIDs))
+	for rows.Next() {
+		var tagID, count int64
+		if err := rows.Scan(&tagID, &count); err != nil {
+			t.log.Error("Error scanning tag count row", slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrTagScanFailed, err)
+		}
+		counts[tagID] = count
+	}
+	if err = rows.Err(); err != nil {
+		t.log.Error("Error iterating tag count rows", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	return counts, nil
+}
+
+// likeEscaper escapes the LIKE wildcards so a prefix matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchTags matches names with LIKE against the text_pattern_ops index on tags(name); tag
+// names are stored normalized, so the prefix is normalized the same way.
+func (t *TagRepository) SearchTags(ctx context.Context, prefix string, limit int, authorID *int64) (result []*model.Tag, err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_search", time.Now(), &err, slog.Int("limit", limit))
+
+	prefix = model.NormalizeTagName(prefix)
+	t.log.Debug("Searching tags", slog.String("prefix", prefix), slog.Int("limit", limit), slog.Any("author_id", authorID))
+
+	args := pgx.NamedArgs{"prefix": likeEscaper.Replace(prefix), "limit": limit}
+	order := "usage DESC, t.name"
+	if authorID != nil {
+		args["author_id"] = *authorID
+		order = "bool_or(p.author_id = @author_id) DESC NULLS LAST, " + order
+	}
+	query := `SELECT t.id, t.name, COUNT(pt.post_id) AS usage
+		FROM tags t
+		LEFT JOIN posts_tags pt ON pt.tag_id = t.id
+		LEFT JOIN posts p ON p.id = pt.post_id
+		WHERE t.name LIKE @prefix || '%'
+		GROUP BY t.id, t.name
+		ORDER BY ` + order + `
+		LIMIT @limit`
+
+	rows, err := t.db.Query(ctx, query, args)
+	if err != nil {
+		t.log.Error("Error searching tags", slog.String("prefix", prefix), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	defer rows.Close()
+
+	tags := make([]*model.Tag, 0, limit)
+	for rows.Next() {
+		var (
+			tag   model.Tag
+			usage int64
+		)
+		if err := rows.Scan(&tag.ID, &tag.Name, &usage); err != nil {
+			t.log.Error("Error scanning tag search row", slog.String("error", err.Error()))
+			return nil, db.WithCause(custom_errors.ErrTagScanFailed, err)
+		}
+		tag.PostCount = &usage
+		tags = append(tags, &tag)
+	}
+	if err = rows.Err(); err != nil {
+		t.log.Error("Error iterating tag search rows", slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+	return tags, nil
+}
+
+func (t *TagRepository) Rename(ctx context.Context, id int64, name string) (result *model.Tag, err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_rename", time.Now(), &err, slog.Int64("tag_id", id))
+	defer func() {
+		t.metrics.IncrementTagOperations("rename", err == nil)
+	}()
+
+	query := `UPDATE tags SET name = @name WHERE id = @id RETURNING id, name`
+	args := pgx.NamedArgs{"id": id, "name": name}
+
+	var tag model.Tag
+	err = t.db.QueryRow(ctx, query, args).Scan(&tag.ID, &tag.Name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, custom_errors.ErrTagNotFound
+		}
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == "23505" {
+			return nil, custom_errors.ErrTagAlreadyExists
+		}
+		t.log.Error("Error renaming tag", slog.Int64("tag_id", id), slog.String("name", name), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrDatabaseQuery, err)
+	}
+	return &tag, nil
+}
+
+// Merge repoints every post tagged with a source tag to destID and deletes the source tags.
+// Posts that already carry the destination tag keep a single row. Callers run it inside a
+// transaction so a failure leaves no half-merged tags behind.
+func (t *TagRepository) Merge(ctx context.Context, sourceIDs []int64, destID int64) (result *model.Tag, err error) {
+	defer db.ObserveQuery(t.metrics, t.log, "tag_merge", time.Now(), &err, slog.Int64("tag_id", destID), slog.Int("count", len(sourceIDs)))
+	defer func() {
+		t.metrics.IncrementTagOperations("merge", err == nil)
+	}()
+
+	var dest model.Tag
+	err = t.db.QueryRow(ctx, `SELECT id, name FROM tags WHERE id = @id`, pgx.NamedArgs{"id": destID}).Scan(&dest.ID, &dest.Name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, custom_errors.ErrTagNotFound
+		}
+		t.log.Error("Error loading merge destination tag", slog.Int64("tag_id", destID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagQueryFailed, err)
+	}
+
+	if len(sourceIDs) == 0 {
+		return &dest, nil
+	}
+
+	repointQuery := `
+		INSERT INTO posts_tags (post_id, tag_id)
+		SELECT DISTINCT post_id, @dest_id FROM posts_tags WHERE tag_id = ANY(@source_ids)
+		ON CONFLICT (post_id, tag_id) DO NOTHING`
+	args := pgx.NamedArgs{"dest_id": destID, "source_ids": sourceIDs}
+
+	if _, err = t.db.Exec(ctx, repointQuery, args); err != nil {
+		t.log.Error("Error repointing posts to merged tag", slog.Int64("tag_id", destID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagInsertFailed, err)
+	}
+	repointArchiveQuery := `
+		INSERT INTO posts_tags_archive (post_id, tag_id)
+		SELECT DISTINCT post_id, @dest_id FROM posts_tags_archive WHERE tag_id = ANY(@source_ids)
+		ON CONFLICT (post_id, tag_id) DO NOTHING`
+	if _, err = t.db.Exec(ctx, repointArchiveQuery, args); err != nil {
+		t.log.Error("Error repointing archived posts to merged tag", slog.Int64("tag_id", destID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagInsertFailed, err)
+	}
+
+	// posts_tags and posts_tags_archive rows of the source tags go with them via ON DELETE CASCADE.
+	deleted, err := t.db.Exec(ctx, `DELETE FROM tags WHERE id = ANY(@source_ids)`, args)
+	if err != nil {
+		t.log.Error("Error deleting merged tags", slog.Int64("tag_id", destID), slog.String("error", err.Error()))
+		return nil, db.WithCause(custom_errors.ErrTagDeleteFailed, err)
+	}
+	if deleted.RowsAffected() != int64(len(sourceIDs)) {
+		t.log.Debug("Some merged tags did not exist", slog.Int64("tag_id", destID), slog.Int64("deleted", deleted.RowsAffected()))
+		return nil, custom_errors.ErrTagNotFound
+	}
+	return &dest, nil
 }
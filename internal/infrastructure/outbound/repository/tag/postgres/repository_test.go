@@ -0,0 +1,322 @@
+package tag_repository_postgres_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/soloda1/pinstack-proto-definitions/custom_errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ports "pinstack-post-service/internal/domain/ports/output"
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	tag_repository_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/tag/postgres"
+)
+
+// missingPostDB reports every post as absent and records the statements run through Exec,
+// failing the advisory lock with lockErr; any other call panics via the nil embedded PgDB.
+type missingPostDB struct {
+	db.PgDB
+	execs   []string
+	lockErr error
+}
+
+func (m *missingPostDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	m.execs = append(m.execs, sql)
+	if m.lockErr != nil && strings.Contains(sql, "pg_advisory_xact_lock") {
+		return pgconn.CommandTag{}, m.lockErr
+	}
+	return pgconn.NewCommandTag("SELECT 1"), nil
+}
+
+func (m *missingPostDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return existsRow(false)
+}
+
+type existsRow bool
+
+func (r existsRow) Scan(dest ...any) error {
+	*dest[0].(*bool) = bool(r)
+	return nil
+}
+
+func TestTagRepository_MissingPost(t *testing.T) {
+	repo := tag_repository_postgres.NewTagRepository(&missingPostDB{}, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits(), 0)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, repo.TagPost(ctx, 1, []string{"tag1"}), custom_errors.ErrPostNotFound)
+	assert.ErrorIs(t, repo.UntagPost(ctx, 1, []string{"tag1"}), custom_errors.ErrPostNotFound)
+	assert.ErrorIs(t, repo.ReplacePostTags(ctx, 1, []string{"tag1"}), custom_errors.ErrPostNotFound)
+}
+
+func TestTagRepository_LocksPostTags(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("every change takes the lock first", func(t *testing.T) {
+		conn := &missingPostDB{}
+		repo := tag_repository_postgres.NewTagRepository(conn, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits(), 0)
+
+		_ = repo.TagPost(ctx, 1, []string{"tag1"})
+		_ = repo.UntagPost(ctx, 1, []string{"tag1"})
+		_ = repo.ReplacePostTags(ctx, 1, nil)
+		_, _ = repo.TagPostExisting(ctx, 1, []string{"tag1"})
+
+		require.Len(t, conn.execs, 4, "a zero timeout leaves lock_timeout alone")
+		for _, sql := range conn.execs {
+			assert.Contains(t, sql, "pg_advisory_xact_lock")
+		}
+	})
+
+	t.Run("a lock timeout bounds the wait", func(t *testing.T) {
+		conn := &missingPostDB{}
+		repo := tag_repository_postgres.NewTagRepository(conn, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits(), 2*time.Second)
+
+		_ = repo.ReplacePostTags(ctx, 1, []string{"tag1"})
+
+		require.Len(t, conn.execs, 2)
+		assert.Contains(t, conn.execs[0], "lock_timeout")
+		assert.Contains(t, conn.execs[1], "pg_advisory_xact_lock")
+	})
+
+	t.Run("running out of the timeout is retryable", func(t *testing.T) {
+		conn := &missingPostDB{lockErr: &pgconn.PgError{Code: "55P03", Message: "canceling statement due to lock timeout"}}
+		repo := tag_repository_postgres.NewTagRepository(conn, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits(), time.Second)
+
+		err := repo.ReplacePostTags(ctx, 1, []string{"tag1"})
+
+		assert.ErrorIs(t, err, custom_errors.ErrDatabaseQuery)
+		assert.True(t, db.IsRetryable(err))
+	})
+}
+
+type recordingMetrics struct {
+	ports.MetricsProvider
+	queries   map[string][]bool
+	durations map[string]int
+}
+
+func (r *recordingMetrics) IncrementDatabaseQueries(queryType string, success bool) {
+	r.queries[queryType] = append(r.queries[queryType], success)
+}
+
+func (r *recordingMetrics) RecordDatabaseQueryDuration(queryType string, duration time.Duration) {
+	r.durations[queryType]++
+}
+
+// queryDB serves FindByNames: either fails the query or returns the given tag names.
+type queryDB struct {
+	db.PgDB
+	err   error
+	names []string
+}
+
+func (q *queryDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	return &tagRows{names: q.names, pos: -1}, nil
+}
+
+type tagRows struct {
+	pgx.Rows
+	names []string
+	pos   int
+}
+
+func (r *tagRows) Next() bool {
+	r.pos++
+	return r.pos < len(r.names)
+}
+
+func (r *tagRows) Scan(dest ...any) error {
+	*dest[0].(*int64) = int64(r.pos + 1)
+	*dest[1].(*string) = r.names[r.pos]
+	return nil
+}
+
+func (r *tagRows) Err() error { return nil }
+
+func (r *tagRows) Close() {}
+
+func TestTagRepository_FindByNames_Metrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		db      *queryDB
+		wantOK  bool
+		wantLen int
+	}{
+		{
+			name:    "success is recorded",
+			db:      &queryDB{names: []string{"go", "rust"}},
+			wantOK:  true,
+			wantLen: 2,
+		},
+		{
+			name:   "query failure is recorded",
+			db:     &queryDB{err: errors.New("connection refused")},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &recordingMetrics{queries: map[string][]bool{}, durations: map[string]int{}}
+			repo := tag_repository_postgres.NewTagRepository(tt.db, logger.New("test"), metrics, db.DefaultBatchLimits(), 0)
+
+			tags, err := repo.FindByNames(context.Background(), []string{"go", "rust"})
+
+			assert.Equal(t, tt.wantOK, err == nil)
+			assert.Len(t, tags, tt.wantLen)
+			assert.Equal(t, []bool{tt.wantOK}, metrics.queries["tag_find_by_names"])
+			assert.Equal(t, 1, metrics.durations["tag_find_by_names"])
+		})
+	}
+}
+
+// createManyDB answers each Query with the next batch of tag names and records the SQL.
+type createManyDB struct {
+	db.PgDB
+	batches [][]string
+	sql     []string
+}
+
+func (c *createManyDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	c.sql = append(c.sql, sql)
+	names := c.batches[0]
+	c.batches = c.batches[1:]
+	return &tagRows{names: names, pos: -1}, nil
+}
+
+func TestTagRepository_CreateMany(t *testing.T) {
+	names := []string{"go", "rust", "zig"}
+
+	t.Run("one statement for every name", func(t *testing.T) {
+		conn := &createManyDB{batches: [][]string{names}}
+		repo := tag_repository_postgres.NewTagRepository(conn, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits(), 0)
+
+		tags, err := repo.CreateMany(context.Background(), names)
+
+		require.NoError(t, err)
+		assert.Len(t, tags, 3)
+		require.Len(t, conn.sql, 1)
+		assert.Contains(t, conn.sql[0], "ON CONFLICT (name) DO NOTHING")
+	})
+
+	t.Run("tags committed concurrently are read again", func(t *testing.T) {
+		conn := &createManyDB{batches: [][]string{{"go", "rust"}, {"zig"}}}
+		repo := tag_repository_postgres.NewTagRepository(conn, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits(), 0)
+
+		tags, err := repo.CreateMany(context.Background(), names)
+
+		require.NoError(t, err)
+		assert.Len(t, tags, 3)
+		assert.Len(t, conn.sql, 2)
+	})
+
+	t.Run("a tag that cannot be read fails", func(t *testing.T) {
+		conn := &createManyDB{batches: [][]string{{"go"}, {"rust"}}}
+		repo := tag_repository_postgres.NewTagRepository(conn, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits(), 0)
+
+		_, err := repo.CreateMany(context.Background(), names)
+
+		assert.ErrorIs(t, err, custom_errors.ErrTagCreateFailed)
+	})
+
+	t.Run("no names make no query", func(t *testing.T) {
+		conn := &createManyDB{}
+		repo := tag_repository_postgres.NewTagRepository(conn, logger.New("test"), prometheus.NewPrometheusMetricsProvider(), db.DefaultBatchLimits(), 0)
+
+		tags, err := repo.CreateMany(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, tags)
+		assert.Empty(t, conn.sql)
+	})
+}
+
+// searchDB records the SearchTags query and returns one row per name, with usage counting down.
+type searchDB struct {
+	db.PgDB
+	sql   string
+	args  pgx.NamedArgs
+	names []string
+}
+
+func (s *searchDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	s.sql = sql
+	s.args = args[0].(pgx.NamedArgs)
+	return &searchRows{tagRows: tagRows{names: s.names, pos: -1}}, nil
+}
+
+type searchRows struct {
+	tagRows
+}
+
+func (r *searchRows) Scan(dest ...any) error {
+	*dest[2].(*int64) = int64(len(r.names) - r.pos)
+	return r.tagRows.Scan(dest[:2]...)
+}
+
+func TestTagRepository_SearchTags(t *testing.T) {
+	authorID := int64(7)
+	tests := []struct {
+		name       string
+		prefix     string
+		authorID   *int64
+		wantPrefix string
+		wantBoost  bool
+	}{
+		{name: "prefix is normalized", prefix: " GoLang ", wantPrefix: "golang"},
+		{name: "wildcards are escaped", prefix: `go_%\`, wantPrefix: `go\_\%\\`},
+		{name: "author boost", prefix: "go", authorID: &authorID, wantPrefix: "go", wantBoost: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &searchDB{names: []string{"golang", "gopher"}}
+			metrics := &recordingMetrics{queries: map[string][]bool{}, durations: map[string]int{}}
+			repo := tag_repository_postgres.NewTagRepository(fake, logger.New("test"), metrics, db.DefaultBatchLimits(), 0)
+
+			tags, err := repo.SearchTags(context.Background(), tt.prefix, 10, tt.authorID)
+
+			require.NoError(t, err)
+			require.Len(t, tags, 2)
+			assert.Equal(t, "golang", tags[0].Name)
+			assert.Equal(t, int64(2), *tags[0].PostCount)
+			assert.Contains(t, fake.sql, "t.name LIKE @prefix || '%'")
+			assert.Equal(t, tt.wantPrefix, fake.args["prefix"])
+			assert.Equal(t, 10, fake.args["limit"])
+			if tt.wantBoost {
+				assert.Contains(t, fake.sql, "ORDER BY bool_or(p.author_id = @author_id) DESC NULLS LAST, usage DESC, t.name")
+				assert.Equal(t, authorID, fake.args["author_id"])
+			} else {
+				assert.Contains(t, fake.sql, "ORDER BY usage DESC, t.name")
+				assert.NotContains(t, fake.args, "author_id")
+			}
+			assert.Equal(t, []bool{true}, metrics.queries["tag_search"])
+		})
+	}
+}
+
+func TestTagRepository_RejectsOversizedBatches(t *testing.T) {
+	// Any call on the database panics: the cap is checked before it is touched.
+	repo := tag_repository_postgres.NewTagRepository(&missingPostDB{}, logger.New("test"), prometheus.NewPrometheusMetricsProvider(),
+		db.BatchLimits{MaxTags: 3}, 0)
+	ctx := context.Background()
+	names := []string{"a", "b", "c", "d"}
+
+	assert.ErrorIs(t, repo.TagPost(ctx, 1, names), custom_errors.ErrInvalidInput)
+	assert.ErrorIs(t, repo.UntagPost(ctx, 1, names), custom_errors.ErrInvalidInput)
+	assert.ErrorIs(t, repo.ReplacePostTags(ctx, 1, names), custom_errors.ErrInvalidInput)
+	_, err := repo.TagPostExisting(ctx, 1, names)
+	assert.ErrorIs(t, err, custom_errors.ErrInvalidInput)
+
+	assert.ErrorIs(t, repo.TagPost(ctx, 1, names[:3]), custom_errors.ErrPostNotFound, "a call at the cap reaches the database")
+}
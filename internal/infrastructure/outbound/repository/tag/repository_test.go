@@ -10,8 +10,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	model "pinstack-post-service/internal/domain/models"
 	"pinstack-post-service/internal/infrastructure/logger"
+	repository_memory "pinstack-post-service/internal/infrastructure/outbound/repository/memory"
 	"pinstack-post-service/internal/infrastructure/outbound/repository/tag/memory"
+	"pinstack-post-service/internal/testsupport"
 )
 
 func setupTagTest(t *testing.T) (tag_repository.Repository, func()) {
@@ -20,6 +23,15 @@ func setupTagTest(t *testing.T) (tag_repository.Repository, func()) {
 	return repo, func() {}
 }
 
+// createTags stores names so posts can be tagged with them; TagPost does not create tags.
+func createTags(t *testing.T, repo tag_repository.Repository, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		_, err := repo.Create(context.Background(), name)
+		require.NoError(t, err)
+	}
+}
+
 func TestTagRepository_Create(t *testing.T) {
 	repo, cleanup := setupTagTest(t)
 	defer cleanup()
@@ -59,6 +71,40 @@ func TestTagRepository_Create(t *testing.T) {
 	}
 }
 
+func TestTagRepository_CreateMany(t *testing.T) {
+	repo, cleanup := setupTagTest(t)
+	defer cleanup()
+	ctx := context.Background()
+	existing, err := repo.Create(ctx, "go")
+	require.NoError(t, err)
+
+	created, err := repo.CreateMany(ctx, []string{"go", "rust", "zig", "rust"})
+	require.NoError(t, err)
+	require.Len(t, created, 3, "duplicate names give one row")
+	byName := make(map[string]int64, len(created))
+	for _, tag := range created {
+		assert.NotZero(t, tag.ID)
+		byName[tag.Name] = tag.ID
+	}
+	assert.Equal(t, existing.ID, byName["go"], "an existing tag keeps its row")
+	assert.NotEqual(t, byName["rust"], byName["zig"])
+
+	again, err := repo.CreateMany(ctx, []string{"rust", "zig"})
+	require.NoError(t, err)
+	require.Len(t, again, 2)
+	for _, tag := range again {
+		assert.Equal(t, byName[tag.Name], tag.ID, "every tag exists")
+	}
+
+	found, err := repo.FindByNames(ctx, []string{"go", "rust", "zig"})
+	require.NoError(t, err)
+	assert.Len(t, found, 3)
+
+	none, err := repo.CreateMany(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
 func TestTagRepository_FindByNames(t *testing.T) {
 	repo, cleanup := setupTagTest(t)
 	defer cleanup()
@@ -138,6 +184,7 @@ func TestTagRepository_FindByPost(t *testing.T) {
 	tagRepo.SimulatePostExists(postID, true)
 
 	tagNames := []string{"tag1", "tag2", "tag3"}
+	createTags(t, repo, tagNames...)
 	err := repo.TagPost(context.Background(), postID, tagNames)
 	require.NoError(t, err)
 
@@ -225,6 +272,8 @@ func TestTagRepository_TagPost(t *testing.T) {
 	postID := int64(1)
 	tagRepo.SimulatePostExists(postID, true)
 
+	createTags(t, repo, "tag1", "tag2", "tag3")
+
 	tests := []struct {
 		name     string
 		postID   int64
@@ -243,6 +292,12 @@ func TestTagRepository_TagPost(t *testing.T) {
 			tagNames: []string{"tag3"},
 			wantErr:  custom_errors.ErrPostNotFound,
 		},
+		{
+			name:     "tag with a tag that does not exist",
+			postID:   postID,
+			tagNames: []string{"tag1", "missing"},
+			wantErr:  custom_errors.ErrTagNotFound,
+		},
 		{
 			name:     "tag with empty tags",
 			postID:   postID,
@@ -291,6 +346,7 @@ func TestTagRepository_UntagPost(t *testing.T) {
 	tagRepo.SimulatePostExists(postID, true)
 
 	initialTags := []string{"tag1", "tag2", "tag3"}
+	createTags(t, repo, initialTags...)
 	err := repo.TagPost(context.Background(), postID, initialTags)
 	require.NoError(t, err)
 
@@ -377,6 +433,7 @@ func TestTagRepository_ReplacePostTags(t *testing.T) {
 
 	// First tag the post
 	initialTags := []string{"tag1", "tag2", "tag3"}
+	createTags(t, repo, "tag1", "tag2", "tag3", "newtag1", "newtag2")
 	err := repo.TagPost(context.Background(), postID, initialTags)
 	require.NoError(t, err)
 
@@ -401,6 +458,13 @@ func TestTagRepository_ReplacePostTags(t *testing.T) {
 			wantTags: []string{},
 			wantErr:  nil,
 		},
+		{
+			name:     "replace with a tag that does not exist",
+			postID:   postID,
+			newTags:  []string{"tag1", "missing"},
+			wantTags: []string{"tag1", "tag2", "tag3"},
+			wantErr:  custom_errors.ErrTagNotFound,
+		},
 		{
 			name:     "replace on non-existent post",
 			postID:   999,
@@ -458,3 +522,210 @@ func contains(slice []string, str string) bool {
 	}
 	return false
 }
+
+func TestTagRepository_DeletedPost(t *testing.T) {
+	repo, cleanup := setupTagTest(t)
+	defer cleanup()
+
+	tagRepo, ok := repo.(*memory.TagRepository)
+	require.True(t, ok)
+
+	postID := int64(1)
+	tagRepo.SimulatePostExists(postID, true)
+	createTags(t, repo, "tag1", "tag2", "tag3")
+	require.NoError(t, repo.TagPost(context.Background(), postID, []string{"tag1"}))
+
+	tagRepo.SimulatePostExists(postID, false)
+
+	assert.Equal(t, custom_errors.ErrPostNotFound, repo.TagPost(context.Background(), postID, []string{"tag2"}))
+	assert.Equal(t, custom_errors.ErrPostNotFound, repo.UntagPost(context.Background(), postID, []string{"tag1"}))
+	assert.Equal(t, custom_errors.ErrPostNotFound, repo.ReplacePostTags(context.Background(), postID, []string{"tag3"}))
+}
+
+func TestTagRepository_Rename(t *testing.T) {
+	repo, cleanup := setupTagTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	typo, err := repo.Create(ctx, "golnag")
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, "rust")
+	require.NoError(t, err)
+
+	renamed, err := repo.Rename(ctx, typo.ID, "golang")
+	require.NoError(t, err)
+	assert.Equal(t, typo.ID, renamed.ID)
+	assert.Equal(t, "golang", renamed.Name)
+
+	found, err := repo.FindByNames(ctx, []string{"golnag", "golang"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "golang", found[0].Name)
+
+	_, err = repo.Rename(ctx, typo.ID, "rust")
+	assert.Equal(t, custom_errors.ErrTagAlreadyExists, err)
+
+	_, err = repo.Rename(ctx, 999, "python")
+	assert.Equal(t, custom_errors.ErrTagNotFound, err)
+}
+
+func TestTagRepository_Merge(t *testing.T) {
+	repo, cleanup := setupTagTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tagRepo, ok := repo.(*memory.TagRepository)
+	require.True(t, ok)
+	for _, postID := range []int64{1, 2, 3} {
+		tagRepo.SimulatePostExists(postID, true)
+	}
+	createTags(t, repo, "golang", "golnag", "go-lang")
+	require.NoError(t, repo.TagPost(ctx, 1, []string{"golang"}))
+	require.NoError(t, repo.TagPost(ctx, 2, []string{"golnag", "golang"}))
+	require.NoError(t, repo.TagPost(ctx, 3, []string{"go-lang"}))
+
+	tags, err := repo.FindByNames(ctx, []string{"golang", "golnag", "go-lang"})
+	require.NoError(t, err)
+	ids := make(map[string]int64)
+	for _, tag := range tags {
+		ids[tag.Name] = tag.ID
+	}
+
+	affected, err := repo.FindPostIDsByTags(ctx, []int64{ids["golnag"], ids["go-lang"]})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, affected)
+
+	_, err = repo.Merge(ctx, []int64{ids["golnag"]}, 999)
+	assert.Equal(t, custom_errors.ErrTagNotFound, err)
+
+	dest, err := repo.Merge(ctx, []int64{ids["golnag"], ids["go-lang"]}, ids["golang"])
+	require.NoError(t, err)
+	assert.Equal(t, "golang", dest.Name)
+
+	for _, postID := range []int64{1, 2, 3} {
+		postTags, err := repo.FindByPost(ctx, postID)
+		require.NoError(t, err)
+		require.Len(t, postTags, 1, "post %d", postID)
+		assert.Equal(t, "golang", postTags[0].Name)
+	}
+
+	remaining, err := repo.FindByNames(ctx, []string{"golnag", "go-lang"})
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+func TestTagRepository_CountPosts(t *testing.T) {
+	repo, cleanup := setupTagTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tagRepo, ok := repo.(*memory.TagRepository)
+	require.True(t, ok)
+	for _, postID := range []int64{1, 2} {
+		tagRepo.SimulatePostExists(postID, true)
+	}
+	createTags(t, repo, "go", "grpc")
+	require.NoError(t, repo.TagPost(ctx, 1, []string{"go", "grpc"}))
+	require.NoError(t, repo.TagPost(ctx, 2, []string{"go"}))
+	unused, err := repo.Create(ctx, "unused")
+	require.NoError(t, err)
+
+	tags, err := repo.FindByNames(ctx, []string{"go", "grpc"})
+	require.NoError(t, err)
+	ids := map[string]int64{}
+	for _, tag := range tags {
+		ids[tag.Name] = tag.ID
+	}
+
+	counts, err := repo.CountPosts(ctx, []int64{ids["go"], ids["grpc"], unused.ID})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int64{ids["go"]: 2, ids["grpc"]: 1}, counts)
+}
+
+func TestTagRepository_TagPostExisting(t *testing.T) {
+	repo, cleanup := setupTagTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tagRepo, ok := repo.(*memory.TagRepository)
+	require.True(t, ok)
+	tagRepo.SimulatePostExists(1, true)
+	_, err := repo.Create(ctx, "go")
+	require.NoError(t, err)
+
+	missing, err := repo.TagPostExisting(ctx, 1, []string{"go", "vanished"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vanished"}, missing)
+
+	tags, err := repo.FindByPost(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "go", tags[0].Name)
+
+	_, err = repo.TagPostExisting(ctx, 999, []string{"go"})
+	assert.ErrorIs(t, err, custom_errors.ErrPostNotFound)
+}
+
+// setupSearchTest stores a post per author and tag list in a memory database, so SearchTags
+// can tell which author used a tag.
+func setupSearchTest(t *testing.T, posts map[int64][][]string) tag_repository.Repository {
+	t.Helper()
+	ctx := context.Background()
+	database := repository_memory.NewDatabase(logger.New("test"))
+	for authorID, tagLists := range posts {
+		for _, names := range tagLists {
+			post, err := database.Posts.Create(ctx, &model.Post{AuthorID: authorID, Title: "Post"})
+			require.NoError(t, err)
+			createTags(t, database.Tags, names...)
+			require.NoError(t, database.Tags.TagPost(ctx, post.ID, names))
+		}
+	}
+	return database.Tags
+}
+
+func searchNames(t *testing.T, repo tag_repository.Repository, prefix string, limit int, authorID *int64) []string {
+	t.Helper()
+	tags, err := repo.SearchTags(context.Background(), prefix, limit, authorID)
+	require.NoError(t, err)
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func TestTagRepository_SearchTags(t *testing.T) {
+	repo := setupSearchTest(t, map[int64][][]string{
+		1: {{"golang", "go-kit"}, {"golang"}, {"golang", "rust"}},
+		2: {{"gopher"}, {"go-kit"}},
+	})
+	createTags(t, repo, "go_unused", "graphql")
+
+	tests := []struct {
+		name     string
+		prefix   string
+		limit    int
+		authorID *int64
+		want     []string
+	}{
+		{name: "most used first, ties by name", prefix: "go", limit: 10, want: []string{"golang", "go-kit", "gopher", "go_unused"}},
+		{name: "prefix is normalized like tag names", prefix: "  GoL", limit: 10, want: []string{"golang"}},
+		{name: "limit", prefix: "go", limit: 2, want: []string{"golang", "go-kit"}},
+		{name: "wildcards match literally", prefix: "go_", limit: 10, want: []string{"go_unused"}},
+		{name: "no match", prefix: "java", limit: 10, want: []string{}},
+		{name: "author's tags first", prefix: "go", limit: 10, authorID: testsupport.Ptr(int64(2)), want: []string{"go-kit", "gopher", "golang", "go_unused"}},
+		{name: "author without posts", prefix: "go", limit: 10, authorID: testsupport.Ptr(int64(9)), want: []string{"golang", "go-kit", "gopher", "go_unused"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchNames(t, repo, tt.prefix, tt.limit, tt.authorID))
+		})
+	}
+
+	tags, err := repo.SearchTags(context.Background(), "golang", 10, nil)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	require.NotNil(t, tags[0].PostCount)
+	assert.Equal(t, int64(3), *tags[0].PostCount)
+}
@@ -0,0 +1,68 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+func TestStack_BulkCreateImportsAThousandPosts(t *testing.T) {
+	s := newStack(t)
+	ctx := context.Background()
+
+	// Two calls of 500, the most one call takes. Post 333 repeats a media url and fails alone.
+	const total = 1000
+	created := 0
+	for start := 0; start < total; start += model.MaxBulkCreatePosts {
+		posts := make([]*model.CreatePostDTO, model.MaxBulkCreatePosts)
+		for i := range posts {
+			n := start + i
+			url := fmt.Sprintf("https://example.com/%d.jpg", n)
+			posts[i] = &model.CreatePostDTO{
+				AuthorID: int64(n%20 + 1),
+				Title:    fmt.Sprintf("Imported %d", n),
+				Tags:     []string{"imported", fmt.Sprintf("batch-%d", n%7)},
+				MediaItems: []*model.PostMediaInput{
+					{URL: url, Type: model.MediaTypeImage, Position: 1},
+				},
+			}
+			if n == 333 {
+				posts[i].MediaItems = append(posts[i].MediaItems, &model.PostMediaInput{URL: url, Type: model.MediaTypeImage, Position: 2})
+			}
+		}
+
+		result, err := s.service.BulkCreatePosts(ctx, 99, posts)
+		require.NoError(t, err)
+		require.Len(t, result.Items, len(posts))
+		created += result.Created
+		if start == 0 {
+			var validation *model.ValidationError
+			assert.ErrorAs(t, result.Items[333].Err, &validation)
+			assert.Equal(t, 1, result.Failed)
+		}
+	}
+	assert.Equal(t, total-1, created)
+
+	count := func(sql string) int {
+		t.Helper()
+		var n int
+		require.NoError(t, s.pool.QueryRow(ctx, sql).Scan(&n))
+		return n
+	}
+	assert.Equal(t, total-1, count(`SELECT count(*) FROM posts`))
+	assert.Equal(t, total-1, count(`SELECT count(*) FROM post_media`))
+	assert.Equal(t, 2*(total-1), count(`SELECT count(*) FROM posts_tags`))
+	assert.Equal(t, 8, count(`SELECT count(*) FROM tags`))
+
+	authorID := int64(1)
+	_, listed, err := s.service.ListPosts(ctx, &model.PostFilters{AuthorID: &authorID})
+	require.NoError(t, err)
+	assert.Equal(t, total/20, listed)
+}
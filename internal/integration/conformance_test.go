@@ -0,0 +1,49 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"os"
+	"testing"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	prometheus_metrics "pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/conformance"
+	media_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/media/postgres"
+	post_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/post/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+	tag_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/tag/postgres"
+)
+
+// setupConformance runs the suites outside a transaction, as the repositories run when the
+// service does not open one. Only Postgres is needed.
+func setupConformance(t *testing.T) conformance.Repositories {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("integration tests do not run with -short")
+	}
+	dsn := os.Getenv("POST_IT_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("POST_IT_DATABASE_URL is not set")
+	}
+	log := logger.New("test")
+	metrics := prometheus_metrics.NewPrometheusMetricsProvider()
+	queryDB := db.WithTimeout(openDatabase(t, dsn), queryTimeout)
+	return conformance.Repositories{
+		Posts: post_postgres.NewPostRepository(queryDB, log, metrics),
+		Tags:  tag_postgres.NewTagRepository(queryDB, log, metrics, db.DefaultBatchLimits(), tagLockTimeout),
+		Media: media_postgres.NewMediaRepository(queryDB, log, metrics, db.DefaultBatchLimits()),
+	}
+}
+
+func TestPostRepository_Conformance(t *testing.T) {
+	conformance.PostRepository(t, setupConformance)
+}
+
+func TestTagRepository_Conformance(t *testing.T) {
+	conformance.TagRepository(t, setupConformance)
+}
+
+func TestMediaRepository_Conformance(t *testing.T) {
+	conformance.MediaRepository(t, setupConformance)
+}
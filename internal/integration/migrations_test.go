@@ -0,0 +1,189 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	gomigrate "github.com/golang-migrate/migrate/v4"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	"pinstack-post-service/internal/infrastructure/migrator"
+)
+
+// mediaUniqueVersion is the migration adding the post_media unique constraints.
+const mediaUniqueVersion = 8
+
+// mediaTypeCheckVersion is the migration restoring the post_media type check.
+const mediaTypeCheckVersion = 18
+
+type mediaRow struct {
+	url      string
+	position int
+}
+
+func TestMigrations_MediaUniqueKeepsPositionsInRange(t *testing.T) {
+	s := newStack(t)
+	ctx := context.Background()
+	m, err := gomigrate.New("file://../../migrations", os.Getenv("POST_IT_DATABASE_URL"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, ignoreNoChange(m.Up()), "the schema is restored for the other tests")
+		_, _ = m.Close()
+	})
+	require.NoError(t, m.Migrate(mediaUniqueVersion-1))
+
+	insertPost := func(rows []mediaRow) int64 {
+		t.Helper()
+		var id int64
+		require.NoError(t, s.pool.QueryRow(ctx, `INSERT INTO posts (author_id, title) VALUES (1, 'Gallery') RETURNING id`).Scan(&id))
+		for _, row := range rows {
+			_, err := s.pool.Exec(ctx, `INSERT INTO post_media (post_id, url, type, position) VALUES ($1, $2, 'image', $3)`, id, row.url, row.position)
+			require.NoError(t, err)
+		}
+		return id
+	}
+	url := func(n int) string { return fmt.Sprintf("https://example.com/%d.jpg", n) }
+
+	// Nine rows, two pairs sharing a position: all are kept and renumbered in order.
+	nine := insertPost([]mediaRow{
+		{url(1), 1}, {url(2), 1}, {url(3), 2}, {url(4), 2}, {url(5), 3},
+		{url(6), 4}, {url(7), 5}, {url(8), 6}, {url(9), 7},
+	})
+	// Nine distinct positions plus a repeated position and a repeated url: the repeats go.
+	eleven := insertPost([]mediaRow{
+		{url(1), 1}, {url(2), 2}, {url(3), 3}, {url(4), 4}, {url(5), 5},
+		{url(6), 6}, {url(7), 7}, {url(8), 8}, {url(9), 9},
+		{url(10), 3}, {url(2), 5},
+	})
+
+	require.NoError(t, m.Migrate(mediaUniqueVersion))
+
+	media := func(postID int64) []mediaRow {
+		t.Helper()
+		rows, err := s.pool.Query(ctx, `SELECT url, position FROM post_media WHERE post_id = $1 ORDER BY position`, postID)
+		require.NoError(t, err)
+		defer rows.Close()
+		var got []mediaRow
+		for rows.Next() {
+			var row mediaRow
+			require.NoError(t, rows.Scan(&row.url, &row.position))
+			got = append(got, row)
+		}
+		require.NoError(t, rows.Err())
+		return got
+	}
+	want := make([]mediaRow, 9)
+	for i := range want {
+		want[i] = mediaRow{url(i + 1), i + 1}
+	}
+	assert.Equal(t, want, media(nine))
+	assert.Equal(t, want, media(eleven))
+
+	require.NoError(t, m.Migrate(mediaUniqueVersion-1), "the migration can be rolled back")
+	require.NoError(t, m.Migrate(mediaUniqueVersion), "and applied again")
+	assert.Equal(t, want, media(nine))
+}
+
+func TestMigrations_MediaTypeCheckIsRestored(t *testing.T) {
+	s := newStack(t)
+	ctx := context.Background()
+	m, err := gomigrate.New("file://../../migrations", os.Getenv("POST_IT_DATABASE_URL"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, ignoreNoChange(m.Up()), "the schema is restored for the other tests")
+		_, _ = m.Close()
+	})
+
+	var postID int64
+	require.NoError(t, s.pool.QueryRow(ctx, `INSERT INTO posts (author_id, title) VALUES (1, 'Gallery') RETURNING id`).Scan(&postID))
+	insertMedia := func(url, mediaType string) error {
+		_, err := s.pool.Exec(ctx, `INSERT INTO post_media (post_id, url, type, position) VALUES ($1, $2, $3, (SELECT count(*) + 1 FROM post_media WHERE post_id = $1))`, postID, url, mediaType)
+		return err
+	}
+	assertCheckViolation := func(err error) {
+		t.Helper()
+		var pgErr *pgconn.PgError
+		require.True(t, errors.As(err, &pgErr), "got %v", err)
+		assert.Equal(t, "23514", pgErr.Code)
+		assert.Equal(t, "post_media_type_check", pgErr.ConstraintName)
+	}
+
+	assertCheckViolation(insertMedia("https://example.com/1.jpg", "img"))
+
+	// A database edited by hand lost the check and took a bad type.
+	require.NoError(t, m.Migrate(mediaTypeCheckVersion-1))
+	_, err = s.pool.Exec(ctx, `ALTER TABLE post_media DROP CONSTRAINT post_media_type_check`)
+	require.NoError(t, err)
+	require.NoError(t, insertMedia("https://example.com/2.jpg", "img"))
+
+	require.NoError(t, m.Migrate(mediaTypeCheckVersion), "rows written without the check do not fail the migration")
+	assertCheckViolation(insertMedia("https://example.com/3.jpg", "img"))
+	require.NoError(t, insertMedia("https://example.com/4.jpg", "video"))
+
+	require.NoError(t, m.Migrate(mediaTypeCheckVersion-1), "the migration can be rolled back")
+	require.NoError(t, m.Migrate(mediaTypeCheckVersion), "and applied again")
+	assertCheckViolation(insertMedia("https://example.com/5.jpg", "img"))
+}
+
+// TestMigrations_EmbeddedUpAndDownAreRepeatable runs the migrations built into the binary
+// down and up twice each; the second run of either must change nothing.
+func TestMigrations_EmbeddedUpAndDownAreRepeatable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration tests do not run with -short")
+	}
+	dsn := os.Getenv("POST_IT_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("POST_IT_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	pool := openDatabase(t, dsn)
+	m, err := migrator.NewMigrator("", dsn, logger.New("test"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, m.Up(), "the schema is restored for the other tests")
+		_ = m.Close()
+	})
+	latest, err := migrator.Latest()
+	require.NoError(t, err)
+	current := migrator.Version{Version: latest}
+
+	require.NoError(t, m.Up(), "a current schema has nothing to apply")
+	version, err := m.Version()
+	require.NoError(t, err)
+	assert.Equal(t, current, version)
+	read, err := migrator.ReadVersion(ctx, pool)
+	require.NoError(t, err)
+	assert.Equal(t, current, read)
+	assert.NoError(t, read.Check(latest))
+
+	require.NoError(t, m.Down())
+	require.NoError(t, m.Down(), "an empty schema has nothing to roll back")
+	read, err = migrator.ReadVersion(ctx, pool)
+	require.NoError(t, err)
+	assert.Equal(t, migrator.Version{}, read)
+	assert.Error(t, read.Check(latest))
+	var posts *string
+	require.NoError(t, pool.QueryRow(ctx, `SELECT to_regclass('posts')::text`).Scan(&posts))
+	assert.Nil(t, posts, "down drops every table")
+
+	require.NoError(t, m.Up())
+	require.NoError(t, m.Up())
+	version, err = m.Version()
+	require.NoError(t, err)
+	assert.Equal(t, current, version)
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, gomigrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
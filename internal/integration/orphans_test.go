@@ -0,0 +1,99 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	post_service "pinstack-post-service/internal/application/service/post"
+	model "pinstack-post-service/internal/domain/models"
+	"pinstack-post-service/internal/infrastructure/logger"
+	prometheus_metrics "pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	consistency_postgres "pinstack-post-service/internal/infrastructure/outbound/repository/consistency/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+func TestOrphans_ReconcileRemovesOrphansAndKeepsTheRest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration tests do not run with -short")
+	}
+	dsn := os.Getenv("POST_IT_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("POST_IT_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	pool := openDatabase(t, dsn)
+	exec := func(sql string, args ...any) {
+		t.Helper()
+		_, err := pool.Exec(ctx, sql, args...)
+		require.NoError(t, err)
+	}
+
+	exec(`INSERT INTO posts (author_id, title) VALUES (1, 'Kept')`)
+	exec(`INSERT INTO tags (name) VALUES ('go')`)
+	exec(`INSERT INTO post_media (post_id, url, type, position) VALUES (1, 'https://example.com/1.jpg', 'image', 1)`)
+	exec(`INSERT INTO posts_tags (post_id, tag_id) VALUES (1, 1)`)
+
+	// Orphans can only be written with the foreign keys switched off, as by the crashes and
+	// manual edits that left them behind.
+	conn, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	for _, sql := range []string{
+		`SET session_replication_role = replica`,
+		`INSERT INTO post_media (post_id, url, type, position) VALUES (404, 'https://example.com/a.jpg', 'image', 1), (404, 'https://example.com/b.jpg', 'image', 2), (405, 'https://example.com/c.jpg', 'image', 1)`,
+		`INSERT INTO posts_tags (post_id, tag_id) VALUES (404, 1), (1, 404)`,
+		`SET session_replication_role = DEFAULT`,
+	} {
+		_, err := conn.Exec(ctx, sql)
+		require.NoError(t, err)
+	}
+	conn.Release()
+
+	log := logger.New("test")
+	metrics := prometheus_metrics.NewPrometheusMetricsProvider()
+	repo := consistency_postgres.NewConsistencyRepository(db.WithTimeout(pool, queryTimeout), log, metrics)
+	reconciler := post_service.NewOrphanReconciler(repo, 2, time.Millisecond, log, metrics)
+	wantFound := map[model.OrphanKind]int{model.OrphanMedia: 3, model.OrphanPostTagsWithoutPost: 1, model.OrphanPostTagsWithoutTag: 1}
+
+	dryRun, err := reconciler.ReconcileOrphans(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, wantFound, dryRun.Found)
+	assert.Empty(t, dryRun.Removed)
+
+	report, err := reconciler.ReconcileOrphans(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, wantFound, report.Found)
+	assert.Equal(t, wantFound, report.Removed)
+
+	count := func(sql string) int {
+		t.Helper()
+		var n int
+		require.NoError(t, pool.QueryRow(ctx, sql).Scan(&n))
+		return n
+	}
+	assert.Equal(t, 1, count(`SELECT count(*) FROM post_media`), "the media of the kept post stay")
+	assert.Equal(t, 1, count(`SELECT count(*) FROM posts_tags`), "the tag of the kept post stays")
+
+	again, err := reconciler.ReconcileOrphans(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[model.OrphanKind]int{}, again.Removed)
+
+	_, err = pool.Exec(ctx, `INSERT INTO post_media (post_id, url, type, position) VALUES (404, 'https://example.com/d.jpg', 'image', 1)`)
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(err, &pgErr), "the foreign key rejects media of a missing post")
+	assert.Equal(t, "23503", pgErr.Code)
+	_, err = pool.Exec(ctx, `INSERT INTO posts_tags (post_id, tag_id) VALUES (1, 404)`)
+	require.True(t, errors.As(err, &pgErr), "the foreign key rejects a missing tag")
+	assert.Equal(t, "23503", pgErr.Code)
+
+	exec(`DELETE FROM posts WHERE id = 1`)
+	assert.Zero(t, count(`SELECT count(*) FROM post_media`)+count(`SELECT count(*) FROM posts_tags`), "deleting a post cascades")
+}
@@ -0,0 +1,102 @@
+//go:build integration
+
+package integration_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pinstack-post-service/internal/infrastructure/logger"
+	prometheus_metrics "pinstack-post-service/internal/infrastructure/outbound/metrics/prometheus"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres"
+	"pinstack-post-service/internal/infrastructure/outbound/repository/postgres/db"
+)
+
+// replaceInTx replaces the tags of postID in a transaction of its own and commits it.
+func replaceInTx(ctx context.Context, uow postgres.UnitOfWork, postID int64, tags []string) error {
+	tx, err := uow.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := tx.TagRepository().ReplacePostTags(ctx, postID, tags); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *stack) tagNames(t *testing.T, postID int64) []string {
+	t.Helper()
+	tags, err := s.tags.FindByPost(context.Background(), postID)
+	require.NoError(t, err)
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+func TestStack_ConcurrentReplacePostTags(t *testing.T) {
+	s := newStack(t)
+	ctx := context.Background()
+	first := []string{"alpha", "beta", "gamma"}
+	second := []string{"delta", "epsilon", "zeta"}
+	_, err := s.tags.CreateMany(ctx, append(slices.Clone(first), second...))
+	require.NoError(t, err)
+	uow := postgres.NewPostgresUOW(s.pool, logger.New("test"), prometheus_metrics.NewPrometheusMetricsProvider(),
+		queryTimeout, db.DefaultBatchLimits(), tagLockTimeout)
+
+	for round := range 20 {
+		post := s.createPost(t, 1, "Contested", "alpha", "delta")
+
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i, tags := range [][]string{first, second} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				errs[i] = replaceInTx(ctx, uow, post.Post.ID, tags)
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		require.NoError(t, errs[0], "round %d", round)
+		require.NoError(t, errs[1], "round %d", round)
+		got := s.tagNames(t, post.Post.ID)
+		assert.True(t, slices.Equal(got, first) || slices.Equal(got, second),
+			"round %d: the tags must be exactly one caller's, got %v", round, got)
+	}
+}
+
+func TestStack_ReplacePostTagsLockTimeout(t *testing.T) {
+	s := newStack(t)
+	ctx := context.Background()
+	_, err := s.tags.CreateMany(ctx, []string{"held", "waiting"})
+	require.NoError(t, err)
+	post := s.createPost(t, 1, "Locked")
+	log := logger.New("test")
+	metrics := prometheus_metrics.NewPrometheusMetricsProvider()
+	holder := postgres.NewPostgresUOW(s.pool, log, metrics, queryTimeout, db.DefaultBatchLimits(), tagLockTimeout)
+	impatient := postgres.NewPostgresUOW(s.pool, log, metrics, queryTimeout, db.DefaultBatchLimits(), 50*time.Millisecond)
+
+	held, err := holder.Begin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, held.TagRepository().ReplacePostTags(ctx, post.Post.ID, []string{"held"}))
+
+	err = replaceInTx(ctx, impatient, post.Post.ID, []string{"waiting"})
+	assert.True(t, db.IsRetryable(err), "a lock timeout must be retryable, got %v", err)
+
+	require.NoError(t, held.Commit(ctx))
+	assert.Equal(t, []string{"held"}, s.tagNames(t, post.Post.ID))
+	require.NoError(t, replaceInTx(ctx, impatient, post.Post.ID, []string{"waiting"}), "the lock ends with the transaction")
+	assert.Equal(t, []string{"waiting"}, s.tagNames(t, post.Post.ID))
+}
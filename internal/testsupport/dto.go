@@ -0,0 +1,178 @@
+package testsupport
+
+import (
+	"time"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// CreatePostDTOBuilder builds a request the service and the gRPC handlers accept: author 1,
+// a title and content long enough for either, and no tags or media.
+type CreatePostDTOBuilder struct {
+	dto model.CreatePostDTO
+}
+
+func NewCreatePostDTOBuilder() *CreatePostDTOBuilder {
+	return &CreatePostDTOBuilder{dto: model.CreatePostDTO{
+		AuthorID: 1,
+		Title:    "Test post",
+		Content:  Ptr("Test content"),
+	}}
+}
+
+func (b *CreatePostDTOBuilder) WithAuthor(authorID int64) *CreatePostDTOBuilder {
+	b.dto.AuthorID = authorID
+	return b
+}
+
+func (b *CreatePostDTOBuilder) WithTitle(title string) *CreatePostDTOBuilder {
+	b.dto.Title = title
+	return b
+}
+
+func (b *CreatePostDTOBuilder) WithContent(content string) *CreatePostDTOBuilder {
+	b.dto.Content = &content
+	return b
+}
+
+func (b *CreatePostDTOBuilder) WithoutContent() *CreatePostDTOBuilder {
+	b.dto.Content = nil
+	return b
+}
+
+func (b *CreatePostDTOBuilder) WithStatus(status model.PostStatus) *CreatePostDTOBuilder {
+	b.dto.Status = status
+	return b
+}
+
+func (b *CreatePostDTOBuilder) WithVisibility(visibility model.PostVisibility) *CreatePostDTOBuilder {
+	b.dto.Visibility = visibility
+	return b
+}
+
+func (b *CreatePostDTOBuilder) WithLanguage(language string) *CreatePostDTOBuilder {
+	b.dto.Language = language
+	return b
+}
+
+func (b *CreatePostDTOBuilder) ScheduledAt(at time.Time) *CreatePostDTOBuilder {
+	b.dto.ScheduledAt = &at
+	return b
+}
+
+func (b *CreatePostDTOBuilder) WithTags(names ...string) *CreatePostDTOBuilder {
+	b.dto.Tags = append(b.dto.Tags, names...)
+	return b
+}
+
+// WithMedia attaches an image for every url, numbered after the media already attached.
+func (b *CreatePostDTOBuilder) WithMedia(urls ...string) *CreatePostDTOBuilder {
+	b.dto.MediaItems = appendMedia(b.dto.MediaItems, urls)
+	return b
+}
+
+// WithMediaItems attaches media as given, for tests about the media fields themselves.
+func (b *CreatePostDTOBuilder) WithMediaItems(items ...*model.PostMediaInput) *CreatePostDTOBuilder {
+	b.dto.MediaItems = append(b.dto.MediaItems, items...)
+	return b
+}
+
+func (b *CreatePostDTOBuilder) Build() *model.CreatePostDTO {
+	dto := b.dto
+	dto.Content = clonePtr(b.dto.Content)
+	dto.ScheduledAt = clonePtr(b.dto.ScheduledAt)
+	dto.Tags = append([]string(nil), b.dto.Tags...)
+	dto.MediaItems = cloneMedia(b.dto.MediaItems)
+	return &dto
+}
+
+// UpdatePostDTOBuilder builds an update by user 1 that changes nothing until told to.
+type UpdatePostDTOBuilder struct {
+	dto model.UpdatePostDTO
+}
+
+func NewUpdatePostDTOBuilder() *UpdatePostDTOBuilder {
+	return &UpdatePostDTOBuilder{dto: model.UpdatePostDTO{UserID: 1}}
+}
+
+func (b *UpdatePostDTOBuilder) WithUser(userID int64) *UpdatePostDTOBuilder {
+	b.dto.UserID = userID
+	return b
+}
+
+func (b *UpdatePostDTOBuilder) WithTitle(title string) *UpdatePostDTOBuilder {
+	b.dto.Title = &title
+	return b
+}
+
+func (b *UpdatePostDTOBuilder) WithContent(content string) *UpdatePostDTOBuilder {
+	b.dto.Content = &content
+	return b
+}
+
+func (b *UpdatePostDTOBuilder) WithVisibility(visibility model.PostVisibility) *UpdatePostDTOBuilder {
+	b.dto.Visibility = &visibility
+	return b
+}
+
+func (b *UpdatePostDTOBuilder) WithLanguage(language string) *UpdatePostDTOBuilder {
+	b.dto.Language = &language
+	return b
+}
+
+func (b *UpdatePostDTOBuilder) WithTags(names ...string) *UpdatePostDTOBuilder {
+	b.dto.Tags = append(b.dto.Tags, names...)
+	return b
+}
+
+func (b *UpdatePostDTOBuilder) WithMedia(urls ...string) *UpdatePostDTOBuilder {
+	b.dto.MediaItems = appendMedia(b.dto.MediaItems, urls)
+	return b
+}
+
+func (b *UpdatePostDTOBuilder) ExpectingVersion(version int64) *UpdatePostDTOBuilder {
+	b.dto.ExpectedVersion = &version
+	return b
+}
+
+func (b *UpdatePostDTOBuilder) Build() *model.UpdatePostDTO {
+	dto := b.dto
+	dto.Title = clonePtr(b.dto.Title)
+	dto.Content = clonePtr(b.dto.Content)
+	dto.Visibility = clonePtr(b.dto.Visibility)
+	dto.Language = clonePtr(b.dto.Language)
+	dto.ExpectedVersion = clonePtr(b.dto.ExpectedVersion)
+	dto.Tags = append([]string(nil), b.dto.Tags...)
+	dto.MediaItems = cloneMedia(b.dto.MediaItems)
+	return &dto
+}
+
+func appendMedia(items []*model.PostMediaInput, urls []string) []*model.PostMediaInput {
+	for _, url := range urls {
+		items = append(items, &model.PostMediaInput{URL: url, Type: model.MediaTypeImage, Position: int32(len(items) + 1)})
+	}
+	return items
+}
+
+func cloneMedia(items []*model.PostMediaInput) []*model.PostMediaInput {
+	if items == nil {
+		return nil
+	}
+	clones := make([]*model.PostMediaInput, len(items))
+	for i, item := range items {
+		clone := *item
+		clone.Width = clonePtr(item.Width)
+		clone.Height = clonePtr(item.Height)
+		clone.SizeBytes = clonePtr(item.SizeBytes)
+		clone.AltText = clonePtr(item.AltText)
+		clones[i] = &clone
+	}
+	return clones
+}
+
+func clonePtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	return Ptr(*v)
+}
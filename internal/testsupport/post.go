@@ -0,0 +1,228 @@
+package testsupport
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	model "pinstack-post-service/internal/domain/models"
+)
+
+// PostBuilder builds a published, public, approved post at version 1 whose creation, update
+// and publication times are all FixedTime.
+type PostBuilder struct {
+	post model.Post
+}
+
+func NewPostBuilder() *PostBuilder {
+	return &PostBuilder{post: model.Post{
+		ID:               1,
+		AuthorID:         1,
+		Title:            "Test post",
+		Status:           model.PostStatusPublished,
+		Visibility:       model.PostVisibilityPublic,
+		Version:          1,
+		CreatedAt:        Timestamptz(FixedTime),
+		UpdatedAt:        Timestamptz(FixedTime),
+		PublishedAt:      Timestamptz(FixedTime),
+		ModerationStatus: model.ModerationStatusApproved,
+	}}
+}
+
+func (b *PostBuilder) WithID(id int64) *PostBuilder {
+	b.post.ID = id
+	return b
+}
+
+func (b *PostBuilder) WithAuthor(authorID int64) *PostBuilder {
+	b.post.AuthorID = authorID
+	return b
+}
+
+func (b *PostBuilder) WithTitle(title string) *PostBuilder {
+	b.post.Title = title
+	return b
+}
+
+func (b *PostBuilder) WithContent(content string) *PostBuilder {
+	b.post.Content = &content
+	return b
+}
+
+func (b *PostBuilder) WithLanguage(language string) *PostBuilder {
+	b.post.Language = &language
+	return b
+}
+
+func (b *PostBuilder) WithVisibility(visibility model.PostVisibility) *PostBuilder {
+	b.post.Visibility = visibility
+	return b
+}
+
+func (b *PostBuilder) WithVersion(version int64) *PostBuilder {
+	b.post.Version = version
+	return b
+}
+
+// WithStatus keeps the timestamps consistent with the status: only a published post has a
+// publication time.
+func (b *PostBuilder) WithStatus(status model.PostStatus) *PostBuilder {
+	b.post.Status = status
+	if status == model.PostStatusPublished {
+		b.post.PublishedAt = b.post.CreatedAt
+	} else {
+		b.post.PublishedAt = pgtype.Timestamptz{}
+	}
+	return b
+}
+
+// Scheduled makes the post a scheduled draft to be published at the given time.
+func (b *PostBuilder) Scheduled(at time.Time) *PostBuilder {
+	b.WithStatus(model.PostStatusScheduled)
+	b.post.ScheduledAt = Timestamptz(at)
+	return b
+}
+
+// CreatedAt sets the creation and update times, and the publication time of a published post.
+func (b *PostBuilder) CreatedAt(t time.Time) *PostBuilder {
+	b.post.CreatedAt = Timestamptz(t)
+	b.post.UpdatedAt = Timestamptz(t)
+	if b.post.Status == model.PostStatusPublished {
+		b.post.PublishedAt = Timestamptz(t)
+	}
+	return b
+}
+
+func (b *PostBuilder) UpdatedAt(t time.Time) *PostBuilder {
+	b.post.UpdatedAt = Timestamptz(t)
+	return b
+}
+
+// WithoutTimestamps clears every time, as for a post the repository has not stored yet.
+func (b *PostBuilder) WithoutTimestamps() *PostBuilder {
+	b.post.CreatedAt = pgtype.Timestamptz{}
+	b.post.UpdatedAt = pgtype.Timestamptz{}
+	b.post.PublishedAt = pgtype.Timestamptz{}
+	b.post.ScheduledAt = pgtype.Timestamptz{}
+	return b
+}
+
+func (b *PostBuilder) PinnedAt(t time.Time) *PostBuilder {
+	b.post.PinnedAt = Timestamptz(t)
+	return b
+}
+
+// Rejected sets the moderation status to rejected for reason.
+func (b *PostBuilder) Rejected(reason model.RejectionReason) *PostBuilder {
+	b.post.ModerationStatus = model.ModerationStatusRejected
+	b.post.RejectionReason = &reason
+	return b
+}
+
+// Build returns a new post on every call, so one builder can stamp out several.
+func (b *PostBuilder) Build() *model.Post {
+	return b.post.Clone()
+}
+
+// PostDetailedBuilder builds a post with its author, media and tags, all of which agree with
+// the post: the author's id is the post's author id, media point back at the post and are
+// numbered from position 1, and media share the post's creation time.
+type PostDetailedBuilder struct {
+	post     *PostBuilder
+	author   *model.User
+	noAuthor bool
+	mediaURL []string
+	tags     []string
+}
+
+func NewPostDetailedBuilder() *PostDetailedBuilder {
+	return &PostDetailedBuilder{post: NewPostBuilder()}
+}
+
+// Post gives access to the builder of the post itself.
+func (b *PostDetailedBuilder) Post(configure func(*PostBuilder)) *PostDetailedBuilder {
+	configure(b.post)
+	return b
+}
+
+func (b *PostDetailedBuilder) WithID(id int64) *PostDetailedBuilder {
+	b.post.WithID(id)
+	return b
+}
+
+func (b *PostDetailedBuilder) WithAuthor(authorID int64) *PostDetailedBuilder {
+	b.post.WithAuthor(authorID)
+	return b
+}
+
+func (b *PostDetailedBuilder) WithTitle(title string) *PostDetailedBuilder {
+	b.post.WithTitle(title)
+	return b
+}
+
+func (b *PostDetailedBuilder) WithContent(content string) *PostDetailedBuilder {
+	b.post.WithContent(content)
+	return b
+}
+
+// WithAuthorUser sets the author of the post; its id becomes the post's author id.
+func (b *PostDetailedBuilder) WithAuthorUser(author *model.User) *PostDetailedBuilder {
+	b.post.WithAuthor(author.ID)
+	b.author = author
+	b.noAuthor = false
+	return b
+}
+
+// WithoutAuthor leaves the author out, as when the user service could not be reached.
+func (b *PostDetailedBuilder) WithoutAuthor() *PostDetailedBuilder {
+	b.noAuthor = true
+	return b
+}
+
+// WithMedia attaches an image for every url, in order.
+func (b *PostDetailedBuilder) WithMedia(urls ...string) *PostDetailedBuilder {
+	b.mediaURL = append(b.mediaURL, urls...)
+	return b
+}
+
+// WithTags attaches the tags, numbered from 1 in order.
+func (b *PostDetailedBuilder) WithTags(names ...string) *PostDetailedBuilder {
+	b.tags = append(b.tags, names...)
+	return b
+}
+
+func (b *PostDetailedBuilder) Build() *model.PostDetailed {
+	post := b.post.Build()
+	detailed := &model.PostDetailed{Post: post}
+	if !b.noAuthor {
+		author := b.author
+		if author == nil {
+			author = NewUser(post.AuthorID)
+		}
+		detailed.Author = author.Clone()
+	}
+	for i, url := range b.mediaURL {
+		detailed.Media = append(detailed.Media, &model.PostMedia{
+			ID:        post.ID*100 + int64(i) + 1,
+			PostID:    post.ID,
+			URL:       url,
+			Type:      model.MediaTypeImage,
+			Position:  int32(i + 1),
+			CreatedAt: post.CreatedAt,
+		})
+	}
+	for i, name := range b.tags {
+		detailed.Tags = append(detailed.Tags, &model.Tag{ID: int64(i + 1), Name: name})
+	}
+	return detailed
+}
+
+// NewUser returns a user whose username and email are derived from id.
+func NewUser(id int64) *model.User {
+	return &model.User{
+		ID:       id,
+		Username: fmt.Sprintf("user%d", id),
+		Email:    fmt.Sprintf("user%d@example.com", id),
+	}
+}
@@ -0,0 +1,28 @@
+// Package testsupport builds deterministic model values for tests. Every builder starts
+// from values the service accepts, so a fixture only states what its test is about.
+package testsupport
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// FixedTime is the time every builder stamps its values with unless told otherwise.
+var FixedTime = time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// Ptr returns a pointer to a copy of v, for the optional fields of models and DTOs.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Timestamptz returns t as a valid timestamp.
+func Timestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: true}
+}
+
+// TimestamptzPtr returns a pointer to t as a valid timestamp, as the filters take it.
+func TimestamptzPtr(t time.Time) *pgtype.Timestamptz {
+	ts := Timestamptz(t)
+	return &ts
+}
@@ -0,0 +1,193 @@
+package testsupport_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	model "pinstack-post-service/internal/domain/models"
+	post_grpc "pinstack-post-service/internal/infrastructure/inbound/grpc/post"
+	"pinstack-post-service/internal/testsupport"
+)
+
+func TestPostBuilder(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		post := testsupport.NewPostBuilder().Build()
+
+		assert.Equal(t, int64(1), post.ID)
+		assert.Equal(t, model.PostStatusPublished, post.Status)
+		assert.Equal(t, model.PostVisibilityPublic, post.Visibility)
+		assert.Equal(t, model.ModerationStatusApproved, post.ModerationStatus)
+		assert.Equal(t, int64(1), post.Version)
+		for _, ts := range []struct {
+			name  string
+			valid bool
+			at    time.Time
+		}{
+			{"created", post.CreatedAt.Valid, post.CreatedAt.Time},
+			{"updated", post.UpdatedAt.Valid, post.UpdatedAt.Time},
+			{"published", post.PublishedAt.Valid, post.PublishedAt.Time},
+		} {
+			assert.True(t, ts.valid, ts.name)
+			assert.Equal(t, testsupport.FixedTime, ts.at, ts.name)
+		}
+		assert.False(t, post.ScheduledAt.Valid)
+		assert.False(t, post.PinnedAt.Valid)
+		assert.NoError(t, post.CheckAccess(nil))
+	})
+
+	t.Run("status keeps the timestamps consistent", func(t *testing.T) {
+		draft := testsupport.NewPostBuilder().WithStatus(model.PostStatusDraft).Build()
+		assert.False(t, draft.PublishedAt.Valid)
+
+		at := testsupport.FixedTime.Add(time.Hour)
+		scheduled := testsupport.NewPostBuilder().Scheduled(at).Build()
+		assert.Equal(t, model.PostStatusScheduled, scheduled.Status)
+		assert.False(t, scheduled.PublishedAt.Valid)
+		assert.Equal(t, testsupport.Timestamptz(at), scheduled.ScheduledAt)
+
+		created := testsupport.FixedTime.Add(-time.Hour)
+		published := testsupport.NewPostBuilder().CreatedAt(created).Build()
+		assert.Equal(t, created, published.CreatedAt.Time)
+		assert.Equal(t, created, published.UpdatedAt.Time)
+		assert.Equal(t, created, published.PublishedAt.Time)
+
+		bare := testsupport.NewPostBuilder().WithoutTimestamps().Build()
+		assert.False(t, bare.CreatedAt.Valid || bare.UpdatedAt.Valid || bare.PublishedAt.Valid)
+	})
+
+	t.Run("every build is a new post", func(t *testing.T) {
+		builder := testsupport.NewPostBuilder().WithContent("Content")
+		first, second := builder.Build(), builder.Build()
+
+		*first.Content = "Changed"
+		assert.Equal(t, "Content", *second.Content)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		post := testsupport.NewPostBuilder().Rejected(model.RejectionReasonSpam).Build()
+
+		assert.True(t, post.IsRejected())
+		assert.NoError(t, model.ValidateModerationStatus(post.ModerationStatus, *post.RejectionReason))
+	})
+}
+
+func TestPostDetailedBuilder(t *testing.T) {
+	detailed := testsupport.NewPostDetailedBuilder().
+		WithID(7).
+		WithAuthor(3).
+		WithMedia("https://example.com/1.jpg", "https://example.com/2.jpg").
+		WithTags("go", "testing").
+		Build()
+
+	require.NotNil(t, detailed.Author)
+	assert.Equal(t, detailed.Post.AuthorID, detailed.Author.ID)
+	require.Len(t, detailed.Media, 2)
+	for i, media := range detailed.Media {
+		assert.Equal(t, int64(7), media.PostID)
+		assert.Equal(t, int32(i+1), media.Position)
+		assert.Equal(t, detailed.Post.CreatedAt, media.CreatedAt)
+	}
+	assert.NotEqual(t, detailed.Media[0].ID, detailed.Media[1].ID)
+	assert.Equal(t, []string{"go", "testing"}, []string{detailed.Tags[0].Name, detailed.Tags[1].Name})
+
+	author := &model.User{ID: 5, Username: "someone"}
+	withUser := testsupport.NewPostDetailedBuilder().WithAuthorUser(author).Build()
+	assert.Equal(t, int64(5), withUser.Post.AuthorID)
+	assert.Equal(t, author, withUser.Author)
+	assert.NotSame(t, author, withUser.Author)
+
+	assert.Nil(t, testsupport.NewPostDetailedBuilder().WithoutAuthor().Build().Author)
+}
+
+func TestCreatePostDTOBuilder_SatisfiesValidation(t *testing.T) {
+	limits := model.DefaultPostLimits()
+
+	tests := []struct {
+		name    string
+		builder *testsupport.CreatePostDTOBuilder
+	}{
+		{name: "defaults", builder: testsupport.NewCreatePostDTOBuilder()},
+		{name: "without content", builder: testsupport.NewCreatePostDTOBuilder().WithoutContent()},
+		{name: "tags and media", builder: testsupport.NewCreatePostDTOBuilder().
+			WithTags("go", "testing").
+			WithMedia("https://example.com/1.jpg", "https://example.com/2.jpg")},
+		{name: "draft", builder: testsupport.NewCreatePostDTOBuilder().WithStatus(model.PostStatusDraft).WithVisibility(model.PostVisibilityPrivate)},
+		{name: "language", builder: testsupport.NewCreatePostDTOBuilder().WithLanguage("en")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dto := tt.builder.Build()
+
+			assert.NoError(t, limits.ValidateCreate(dto))
+		})
+	}
+
+	t.Run("media are numbered from 1", func(t *testing.T) {
+		dto := testsupport.NewCreatePostDTOBuilder().WithMedia("https://example.com/1.jpg").WithMedia("https://example.com/2.jpg").Build()
+
+		assert.Equal(t, int32(1), dto.MediaItems[0].Position)
+		assert.Equal(t, int32(2), dto.MediaItems[1].Position)
+	})
+
+	t.Run("the gRPC handler accepts the defaults", func(t *testing.T) {
+		dto := testsupport.NewCreatePostDTOBuilder().Build()
+
+		assert.NoError(t, validator.New().Struct(&post_grpc.CreatePostRequestInternal{
+			AuthorID: dto.AuthorID,
+			Title:    dto.Title,
+			Content:  *dto.Content,
+		}))
+	})
+
+	t.Run("every build is a new request", func(t *testing.T) {
+		builder := testsupport.NewCreatePostDTOBuilder().WithTags("go").WithMedia("https://example.com/1.jpg")
+		first, second := builder.Build(), builder.Build()
+
+		first.Tags[0] = "changed"
+		first.MediaItems[0].Position = 9
+		*first.Content = "changed"
+		assert.Equal(t, "go", second.Tags[0])
+		assert.Equal(t, int32(1), second.MediaItems[0].Position)
+		assert.Equal(t, "Test content", *second.Content)
+	})
+}
+
+func TestUpdatePostDTOBuilder_SatisfiesValidation(t *testing.T) {
+	limits := model.DefaultPostLimits()
+
+	empty := testsupport.NewUpdatePostDTOBuilder().Build()
+	assert.NoError(t, limits.ValidateUpdate(empty))
+	assert.False(t, empty.ChangesFields())
+
+	cleared := testsupport.NewUpdatePostDTOBuilder().WithTitle("").Build()
+	assert.NoError(t, limits.ValidateUpdate(cleared), "an empty title clears it")
+	assert.True(t, cleared.ChangesFields())
+
+	update := testsupport.NewUpdatePostDTOBuilder().
+		WithUser(3).
+		WithTitle("Updated title").
+		WithContent("Updated content").
+		WithVisibility(model.PostVisibilityUnlisted).
+		WithTags("go").
+		WithMedia("https://example.com/1.jpg").
+		ExpectingVersion(2).
+		Build()
+	assert.NoError(t, limits.ValidateUpdate(update))
+	assert.True(t, update.ChangesFields())
+	assert.Equal(t, int64(3), update.UserID)
+	assert.Equal(t, int64(2), *update.ExpectedVersion)
+}
+
+func TestPointerHelpers(t *testing.T) {
+	assert.Equal(t, "title", *testsupport.Ptr("title"))
+	assert.Equal(t, int64(3), *testsupport.Ptr(int64(3)))
+
+	ts := testsupport.TimestamptzPtr(testsupport.FixedTime)
+	assert.True(t, ts.Valid)
+	assert.Equal(t, testsupport.FixedTime, ts.Time)
+}
@@ -0,0 +1,8 @@
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS status       TEXT        NOT NULL DEFAULT 'published' CHECK (status IN ('draft','published')),
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+UPDATE posts SET published_at = created_at WHERE status = 'published' AND published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_status
+    ON posts(status);
@@ -0,0 +1,2 @@
+-- Merged tags and the original spelling of names cannot be restored.
+SELECT 1;
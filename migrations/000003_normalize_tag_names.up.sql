@@ -0,0 +1,29 @@
+-- Tag names are stored normalized: trimmed, lowercased, whitespace runs replaced by '-'.
+-- Merge tags that collapse to the same name into the one with the lowest id.
+BEGIN;
+
+CREATE TEMP TABLE tag_canonical AS
+SELECT id,
+       regexp_replace(lower(btrim(name)), '\s+', '-', 'g') AS canonical,
+       min(id) OVER (PARTITION BY regexp_replace(lower(btrim(name)), '\s+', '-', 'g')) AS keeper
+FROM tags;
+
+INSERT INTO posts_tags (post_id, tag_id)
+SELECT pt.post_id, tc.keeper
+FROM posts_tags pt
+JOIN tag_canonical tc ON tc.id = pt.tag_id
+WHERE tc.id <> tc.keeper
+ON CONFLICT DO NOTHING;
+
+DELETE FROM tags t
+USING tag_canonical tc
+WHERE t.id = tc.id AND (tc.id <> tc.keeper OR tc.canonical = '');
+
+UPDATE tags t
+SET name = tc.canonical
+FROM tag_canonical tc
+WHERE t.id = tc.id AND t.name <> tc.canonical;
+
+DROP TABLE tag_canonical;
+
+COMMIT;
@@ -0,0 +1,5 @@
+ALTER TABLE post_media
+    DROP COLUMN IF EXISTS alt_text,
+    DROP COLUMN IF EXISTS size_bytes,
+    DROP COLUMN IF EXISTS height,
+    DROP COLUMN IF EXISTS width;
@@ -0,0 +1,5 @@
+ALTER TABLE post_media
+    ADD COLUMN IF NOT EXISTS width      INTEGER CHECK (width >= 0),
+    ADD COLUMN IF NOT EXISTS height     INTEGER CHECK (height >= 0),
+    ADD COLUMN IF NOT EXISTS size_bytes BIGINT  CHECK (size_bytes >= 0),
+    ADD COLUMN IF NOT EXISTS alt_text   TEXT    CHECK (char_length(alt_text) <= 500);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_created_at;
+
+DROP TABLE IF EXISTS posts_tags_archive;
+DROP TABLE IF EXISTS post_media_archive;
+DROP TABLE IF EXISTS posts_archive;
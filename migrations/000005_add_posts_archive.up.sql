@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS posts_archive (
+    id           bigint      PRIMARY KEY,
+    author_id    bigint      NOT NULL,
+    title        TEXT        NOT NULL,
+    content      TEXT,
+    status       TEXT        NOT NULL CHECK (status IN ('draft','published')),
+    published_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL,
+    archived_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS post_media_archive (
+    id         bigint      PRIMARY KEY,
+    post_id    bigint      NOT NULL REFERENCES posts_archive(id) ON DELETE CASCADE,
+    url        TEXT        NOT NULL,
+    type       TEXT        NOT NULL CHECK (type IN ('image','video')),
+    position   SMALLINT    NOT NULL CHECK (position BETWEEN 1 AND 9),
+    width      INTEGER     CHECK (width >= 0),
+    height     INTEGER     CHECK (height >= 0),
+    size_bytes BIGINT      CHECK (size_bytes >= 0),
+    alt_text   TEXT        CHECK (char_length(alt_text) <= 500),
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS posts_tags_archive (
+    post_id bigint NOT NULL REFERENCES posts_archive(id) ON DELETE CASCADE,
+    tag_id  bigint NOT NULL REFERENCES tags(id)          ON DELETE CASCADE,
+    PRIMARY KEY (post_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_posts_archive_author_id
+    ON posts_archive(author_id);
+
+CREATE INDEX IF NOT EXISTS idx_post_media_archive_post_id_position
+    ON post_media_archive(post_id, position);
+
+CREATE INDEX IF NOT EXISTS idx_posts_tags_archive_tag_id
+    ON posts_tags_archive(tag_id);
+
+-- The archiver picks the oldest posts first.
+CREATE INDEX IF NOT EXISTS idx_posts_created_at
+    ON posts(created_at);
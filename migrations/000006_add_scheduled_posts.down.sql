@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_posts_scheduled_at;
+
+UPDATE posts SET status = 'draft' WHERE status = 'scheduled';
+
+ALTER TABLE posts
+    DROP CONSTRAINT IF EXISTS posts_scheduled_at_check,
+    DROP COLUMN IF EXISTS scheduled_at,
+    DROP CONSTRAINT IF EXISTS posts_status_check,
+    ADD CONSTRAINT posts_status_check CHECK (status IN ('draft','published'));
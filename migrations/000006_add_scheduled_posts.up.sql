@@ -0,0 +1,9 @@
+ALTER TABLE posts
+    DROP CONSTRAINT IF EXISTS posts_status_check,
+    ADD CONSTRAINT posts_status_check CHECK (status IN ('draft','published','scheduled')),
+    ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMPTZ,
+    ADD CONSTRAINT posts_scheduled_at_check CHECK ((status = 'scheduled') = (scheduled_at IS NOT NULL));
+
+-- The scheduler picks due posts in schedule order.
+CREATE INDEX IF NOT EXISTS idx_posts_scheduled_at
+    ON posts(scheduled_at) WHERE status = 'scheduled';
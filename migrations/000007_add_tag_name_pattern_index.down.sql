@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_tags_name_pattern;
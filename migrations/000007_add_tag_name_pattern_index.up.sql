@@ -0,0 +1,3 @@
+-- Serves prefix matches (name LIKE 'go%') for tag suggestions; the default btree index on
+-- name only does so under the C collation.
+CREATE INDEX IF NOT EXISTS idx_tags_name_pattern ON tags (name text_pattern_ops);
@@ -0,0 +1,11 @@
+-- Removed duplicates and the original positions cannot be restored.
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_post_media_post_id_position
+    ON post_media(post_id, position);
+
+ALTER TABLE post_media
+    DROP CONSTRAINT IF EXISTS post_media_post_id_url_key,
+    DROP CONSTRAINT IF EXISTS post_media_post_id_position_key;
+
+COMMIT;
@@ -0,0 +1,43 @@
+-- A post's media have distinct positions and distinct urls.
+-- Drop repeated urls, keeping the lowest id. A post can then still hold more than 9 rows
+-- when positions repeat, so drop the surplus repeats: rows that are not the lowest id at
+-- their position go first. At most 9 rows are left per post, and renumbering posts whose
+-- positions collide keeps every position within 1..9.
+BEGIN;
+
+DELETE FROM post_media pm
+USING post_media keeper
+WHERE pm.post_id = keeper.post_id AND pm.url = keeper.url AND pm.id > keeper.id;
+
+DELETE FROM post_media pm
+USING (
+    SELECT id, row_number() OVER (PARTITION BY post_id ORDER BY repeat > 1, position, id) AS rn
+    FROM (
+        SELECT id, post_id, position,
+               row_number() OVER (PARTITION BY post_id, position ORDER BY id) AS repeat
+        FROM post_media
+    ) positioned
+) ranked
+WHERE pm.id = ranked.id AND ranked.rn > 9;
+
+UPDATE post_media pm
+SET position = ranked.rn
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY post_id ORDER BY position, id) AS rn
+    FROM post_media
+    WHERE post_id IN (
+        SELECT post_id FROM post_media GROUP BY post_id, position HAVING count(*) > 1
+    )
+) ranked
+WHERE pm.id = ranked.id AND pm.position <> ranked.rn;
+
+-- Deferrable so that a single UPDATE can swap positions; uniqueness is still checked at the
+-- end of every statement.
+ALTER TABLE post_media
+    ADD CONSTRAINT post_media_post_id_position_key UNIQUE (post_id, position) DEFERRABLE INITIALLY IMMEDIATE,
+    ADD CONSTRAINT post_media_post_id_url_key UNIQUE (post_id, url);
+
+-- The unique constraint's index covers (post_id, position) lookups.
+DROP INDEX IF EXISTS idx_post_media_post_id_position;
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- moderation_log outlives the posts it refers to, so post_id has no foreign key.
+CREATE TABLE IF NOT EXISTS moderation_log (
+    id         bigint      GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    action     TEXT        NOT NULL CHECK (action IN ('force_delete')),
+    post_id    bigint      NOT NULL,
+    author_id  bigint      NOT NULL,
+    actor_id   bigint      NOT NULL,
+    reason     TEXT        NOT NULL CHECK (char_length(reason) BETWEEN 5 AND 500),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_log_post_id
+    ON moderation_log(post_id);
@@ -0,0 +1,5 @@
+ALTER TABLE posts_archive
+    DROP COLUMN IF EXISTS visibility;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,6 @@
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('public','unlisted','private'));
+
+-- Archived posts keep their visibility so the archive fallback enforces it too.
+ALTER TABLE posts_archive
+    ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'public';
@@ -0,0 +1,3 @@
+-- Bumped by every write to a post; UpdatePost with an expected version compares against it.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
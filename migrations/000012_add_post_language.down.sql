@@ -0,0 +1,7 @@
+ALTER TABLE posts_archive
+    DROP COLUMN IF EXISTS lang;
+
+DROP INDEX IF EXISTS idx_posts_lang_created_at;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS lang;
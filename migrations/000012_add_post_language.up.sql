@@ -0,0 +1,10 @@
+-- BCP-47 tag of the language a post is written in; NULL for posts without one.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS lang TEXT;
+
+-- ListPosts with a language filter; posts without a language are never looked up by it.
+CREATE INDEX IF NOT EXISTS idx_posts_lang_created_at
+    ON posts(lang, created_at DESC) WHERE lang IS NOT NULL;
+
+ALTER TABLE posts_archive
+    ADD COLUMN IF NOT EXISTS lang TEXT;
@@ -0,0 +1,4 @@
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS edit_count;
+
+DROP TABLE IF EXISTS post_revisions;
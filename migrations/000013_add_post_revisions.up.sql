@@ -0,0 +1,15 @@
+-- The title and content a post had before each edit that changed them. Older revisions are
+-- pruned, so edit_count on posts, bumped with every revision written, keeps the full count.
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id         bigint      GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    post_id    bigint      NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision   bigint      NOT NULL,
+    title      TEXT        NOT NULL,
+    content    TEXT,
+    editor_id  bigint      NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (post_id, revision)
+);
+
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS edit_count BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- The constraints belong to the initial schema, so they are kept.
+SELECT 1;
@@ -0,0 +1,25 @@
+-- post_media and posts_tags reference their post and tag with ON DELETE CASCADE since the
+-- initial schema, but databases edited by hand may have lost the constraints. Restore the
+-- missing ones NOT VALID: new rows are checked right away, while rows already orphaned are
+-- left to the orphan reconciliation (-reconcile-orphans) instead of failing this migration.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint
+                   WHERE conrelid = 'post_media'::regclass AND contype = 'f' AND confrelid = 'posts'::regclass) THEN
+        ALTER TABLE post_media
+            ADD CONSTRAINT post_media_post_id_fkey FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE NOT VALID;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint
+                   WHERE conrelid = 'posts_tags'::regclass AND contype = 'f' AND confrelid = 'posts'::regclass) THEN
+        ALTER TABLE posts_tags
+            ADD CONSTRAINT posts_tags_post_id_fkey FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE NOT VALID;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint
+                   WHERE conrelid = 'posts_tags'::regclass AND contype = 'f' AND confrelid = 'tags'::regclass) THEN
+        ALTER TABLE posts_tags
+            ADD CONSTRAINT posts_tags_tag_id_fkey FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE NOT VALID;
+    END IF;
+END
+$$;
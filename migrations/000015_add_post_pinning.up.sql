@@ -0,0 +1,7 @@
+-- The post an author pinned to the top of their profile. The partial unique index keeps it
+-- to one per author and finds it when the author's posts are listed.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_author_pinned
+    ON posts (author_id) WHERE pinned_at IS NOT NULL;
@@ -0,0 +1,8 @@
+ALTER TABLE posts_archive
+    DROP COLUMN IF EXISTS rejection_reason,
+    DROP COLUMN IF EXISTS moderation_status;
+
+ALTER TABLE posts
+    DROP CONSTRAINT IF EXISTS posts_rejection_reason_check,
+    DROP COLUMN IF EXISTS rejection_reason,
+    DROP COLUMN IF EXISTS moderation_status;
@@ -0,0 +1,12 @@
+-- Where a post stands with trust & safety. A rejected post stays in place for its author and
+-- is redacted for everyone else, so it carries the machine-readable reason clients render
+-- their placeholder from; no other status has a reason.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS moderation_status TEXT NOT NULL DEFAULT 'approved' CHECK (moderation_status IN ('approved','under_review','rejected')),
+    ADD COLUMN IF NOT EXISTS rejection_reason  TEXT,
+    ADD CONSTRAINT posts_rejection_reason_check CHECK ((moderation_status = 'rejected') = (rejection_reason IS NOT NULL));
+
+-- Archived posts keep their moderation status so the archive fallback redacts them too.
+ALTER TABLE posts_archive
+    ADD COLUMN IF NOT EXISTS moderation_status TEXT NOT NULL DEFAULT 'approved',
+    ADD COLUMN IF NOT EXISTS rejection_reason  TEXT;
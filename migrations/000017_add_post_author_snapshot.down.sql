@@ -0,0 +1,4 @@
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS author_snapshot_at,
+    DROP COLUMN IF EXISTS author_avatar_url,
+    DROP COLUMN IF EXISTS author_username;
@@ -0,0 +1,7 @@
+-- A copy of the author's display data, taken when the post is created and refreshed in the
+-- background, so reads can build the author without calling the user service. Posts created
+-- before this migration have no snapshot and fall back to the user service.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS author_username    TEXT,
+    ADD COLUMN IF NOT EXISTS author_avatar_url  TEXT,
+    ADD COLUMN IF NOT EXISTS author_snapshot_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+-- The constraint belongs to the initial schema, so it is kept.
+SELECT 1;
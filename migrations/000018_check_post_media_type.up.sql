@@ -0,0 +1,13 @@
+-- post_media.type is limited to the model.MediaTypes values since the initial schema, but
+-- databases edited by hand may have lost the check, letting types like 'img' in. Restore it
+-- NOT VALID: new rows are checked right away, while rows already written are left for a
+-- manual fix instead of failing this migration. A new media type replaces this constraint.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint
+                   WHERE conrelid = 'post_media'::regclass AND conname = 'post_media_type_check') THEN
+        ALTER TABLE post_media
+            ADD CONSTRAINT post_media_type_check CHECK (type IN ('image','video')) NOT VALID;
+    END IF;
+END
+$$;
@@ -0,0 +1,7 @@
+ALTER TABLE posts_archive
+    DROP COLUMN IF EXISTS source;
+
+DROP INDEX IF EXISTS idx_posts_source_created_at;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS source;
@@ -0,0 +1,12 @@
+-- The client a post was created from (web, ios, android, import, ...), for analytics. Posts
+-- created before it was recorded, and by clients that send none or one off the allowlist,
+-- are 'unknown'.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'unknown' CHECK (char_length(source) BETWEEN 1 AND 32);
+
+-- ListPosts with a source filter.
+CREATE INDEX IF NOT EXISTS idx_posts_source_created_at
+    ON posts(source, created_at DESC);
+
+ALTER TABLE posts_archive
+    ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'unknown';
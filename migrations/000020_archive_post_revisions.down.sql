@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS post_revisions_archive;
+
+ALTER TABLE posts_archive
+    DROP COLUMN IF EXISTS edit_count;
@@ -0,0 +1,15 @@
+-- Archived posts keep their edit history: the archiver copies a post's revisions here before
+-- deleting the post cascades them away, and its edit_count with the post.
+ALTER TABLE posts_archive
+    ADD COLUMN IF NOT EXISTS edit_count BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS post_revisions_archive (
+    id         bigint      PRIMARY KEY,
+    post_id    bigint      NOT NULL REFERENCES posts_archive(id) ON DELETE CASCADE,
+    revision   bigint      NOT NULL,
+    title      TEXT        NOT NULL,
+    content    TEXT,
+    editor_id  bigint      NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (post_id, revision)
+);
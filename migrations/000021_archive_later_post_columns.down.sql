@@ -0,0 +1,7 @@
+ALTER TABLE posts_archive
+    DROP COLUMN IF EXISTS author_snapshot_at,
+    DROP COLUMN IF EXISTS author_avatar_url,
+    DROP COLUMN IF EXISTS author_username,
+    DROP COLUMN IF EXISTS pinned_at,
+    DROP COLUMN IF EXISTS scheduled_at,
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,9 @@
+-- The post columns added after posts_archive, so an archived post reads back with its version,
+-- schedule, pin and author snapshot.
+ALTER TABLE posts_archive
+    ADD COLUMN IF NOT EXISTS version            BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS scheduled_at       TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS pinned_at          TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS author_username    TEXT,
+    ADD COLUMN IF NOT EXISTS author_avatar_url  TEXT,
+    ADD COLUMN IF NOT EXISTS author_snapshot_at TIMESTAMPTZ;
@@ -0,0 +1,11 @@
+// Package migrations embeds the SQL schema migrations, so the binaries can apply them
+// without the files next to them. A migration is a NNNNNN_name.up.sql and
+// NNNNNN_name.down.sql pair; every schema change ships as the next pair.
+package migrations
+
+import "embed"
+
+// FS holds the migration files at its root.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -71,6 +71,53 @@ func (_c *Repository_Attach_Call) RunAndReturn(run func(context.Context, int64,
 	return _c
 }
 
+// AttachMany provides a mock function with given fields: ctx, media
+func (_m *Repository) AttachMany(ctx context.Context, media []*model.PostMedia) error {
+	ret := _m.Called(ctx, media)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachMany")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.PostMedia) error); ok {
+		r0 = rf(ctx, media)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_AttachMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttachMany'
+type Repository_AttachMany_Call struct {
+	*mock.Call
+}
+
+// AttachMany is a helper method to define mock.On call
+//   - ctx context.Context
+//   - media []*model.PostMedia
+func (_e *Repository_Expecter) AttachMany(ctx interface{}, media interface{}) *Repository_AttachMany_Call {
+	return &Repository_AttachMany_Call{Call: _e.mock.On("AttachMany", ctx, media)}
+}
+
+func (_c *Repository_AttachMany_Call) Run(run func(ctx context.Context, media []*model.PostMedia)) *Repository_AttachMany_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*model.PostMedia))
+	})
+	return _c
+}
+
+func (_c *Repository_AttachMany_Call) Return(_a0 error) *Repository_AttachMany_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_AttachMany_Call) RunAndReturn(run func(context.Context, []*model.PostMedia) error) *Repository_AttachMany_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Detach provides a mock function with given fields: ctx, mediaIDs
 func (_m *Repository) Detach(ctx context.Context, mediaIDs []int64) error {
 	ret := _m.Called(ctx, mediaIDs)
@@ -236,6 +283,74 @@ func (_c *Repository_GetByPosts_Call) RunAndReturn(run func(context.Context, []i
 	return _c
 }
 
+// GetPageByPost provides a mock function with given fields: ctx, postID, limit, offset
+func (_m *Repository) GetPageByPost(ctx context.Context, postID int64, limit int, offset int) ([]*model.PostMedia, int, error) {
+	ret := _m.Called(ctx, postID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPageByPost")
+	}
+
+	var r0 []*model.PostMedia
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]*model.PostMedia, int, error)); ok {
+		return rf(ctx, postID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []*model.PostMedia); ok {
+		r0 = rf(ctx, postID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PostMedia)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) int); ok {
+		r1 = rf(ctx, postID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, int, int) error); ok {
+		r2 = rf(ctx, postID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Repository_GetPageByPost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPageByPost'
+type Repository_GetPageByPost_Call struct {
+	*mock.Call
+}
+
+// GetPageByPost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postID int64
+//   - limit int
+//   - offset int
+func (_e *Repository_Expecter) GetPageByPost(ctx interface{}, postID interface{}, limit interface{}, offset interface{}) *Repository_GetPageByPost_Call {
+	return &Repository_GetPageByPost_Call{Call: _e.mock.On("GetPageByPost", ctx, postID, limit, offset)}
+}
+
+func (_c *Repository_GetPageByPost_Call) Run(run func(ctx context.Context, postID int64, limit int, offset int)) *Repository_GetPageByPost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *Repository_GetPageByPost_Call) Return(_a0 []*model.PostMedia, _a1 int, _a2 error) *Repository_GetPageByPost_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *Repository_GetPageByPost_Call) RunAndReturn(run func(context.Context, int64, int, int) ([]*model.PostMedia, int, error)) *Repository_GetPageByPost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Reorder provides a mock function with given fields: ctx, postID, newPositions
 func (_m *Repository) Reorder(ctx context.Context, postID int64, newPositions map[int64]int) error {
 	ret := _m.Called(ctx, postID, newPositions)
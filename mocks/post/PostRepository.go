@@ -7,6 +7,8 @@ import (
 	model "pinstack-post-service/internal/domain/models"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -22,6 +24,122 @@ func (_m *Repository) EXPECT() *Repository_Expecter {
 	return &Repository_Expecter{mock: &_m.Mock}
 }
 
+// CancelSchedule provides a mock function with given fields: ctx, id
+func (_m *Repository) CancelSchedule(ctx context.Context, id int64) (*model.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelSchedule")
+	}
+
+	var r0 *model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_CancelSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelSchedule'
+type Repository_CancelSchedule_Call struct {
+	*mock.Call
+}
+
+// CancelSchedule is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Repository_Expecter) CancelSchedule(ctx interface{}, id interface{}) *Repository_CancelSchedule_Call {
+	return &Repository_CancelSchedule_Call{Call: _e.mock.On("CancelSchedule", ctx, id)}
+}
+
+func (_c *Repository_CancelSchedule_Call) Run(run func(ctx context.Context, id int64)) *Repository_CancelSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_CancelSchedule_Call) Return(_a0 *model.Post, _a1 error) *Repository_CancelSchedule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_CancelSchedule_Call) RunAndReturn(run func(context.Context, int64) (*model.Post, error)) *Repository_CancelSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountPublishedByAuthor provides a mock function with given fields: ctx, authorID
+func (_m *Repository) CountPublishedByAuthor(ctx context.Context, authorID int64) (int64, error) {
+	ret := _m.Called(ctx, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountPublishedByAuthor")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_CountPublishedByAuthor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountPublishedByAuthor'
+type Repository_CountPublishedByAuthor_Call struct {
+	*mock.Call
+}
+
+// CountPublishedByAuthor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorID int64
+func (_e *Repository_Expecter) CountPublishedByAuthor(ctx interface{}, authorID interface{}) *Repository_CountPublishedByAuthor_Call {
+	return &Repository_CountPublishedByAuthor_Call{Call: _e.mock.On("CountPublishedByAuthor", ctx, authorID)}
+}
+
+func (_c *Repository_CountPublishedByAuthor_Call) Run(run func(ctx context.Context, authorID int64)) *Repository_CountPublishedByAuthor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_CountPublishedByAuthor_Call) Return(_a0 int64, _a1 error) *Repository_CountPublishedByAuthor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_CountPublishedByAuthor_Call) RunAndReturn(run func(context.Context, int64) (int64, error)) *Repository_CountPublishedByAuthor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, post
 func (_m *Repository) Create(ctx context.Context, post *model.Post) (*model.Post, error) {
 	ret := _m.Called(ctx, post)
@@ -81,6 +199,65 @@ func (_c *Repository_Create_Call) RunAndReturn(run func(context.Context, *model.
 	return _c
 }
 
+// CreateMany provides a mock function with given fields: ctx, posts
+func (_m *Repository) CreateMany(ctx context.Context, posts []*model.Post) ([]*model.Post, error) {
+	ret := _m.Called(ctx, posts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMany")
+	}
+
+	var r0 []*model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Post) ([]*model.Post, error)); ok {
+		return rf(ctx, posts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Post) []*model.Post); ok {
+		r0 = rf(ctx, posts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*model.Post) error); ok {
+		r1 = rf(ctx, posts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_CreateMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMany'
+type Repository_CreateMany_Call struct {
+	*mock.Call
+}
+
+// CreateMany is a helper method to define mock.On call
+//   - ctx context.Context
+//   - posts []*model.Post
+func (_e *Repository_Expecter) CreateMany(ctx interface{}, posts interface{}) *Repository_CreateMany_Call {
+	return &Repository_CreateMany_Call{Call: _e.mock.On("CreateMany", ctx, posts)}
+}
+
+func (_c *Repository_CreateMany_Call) Run(run func(ctx context.Context, posts []*model.Post)) *Repository_CreateMany_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*model.Post))
+	})
+	return _c
+}
+
+func (_c *Repository_CreateMany_Call) Return(_a0 []*model.Post, _a1 error) *Repository_CreateMany_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_CreateMany_Call) RunAndReturn(run func(context.Context, []*model.Post) ([]*model.Post, error)) *Repository_CreateMany_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *Repository) Delete(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -187,6 +364,67 @@ func (_c *Repository_GetByAuthor_Call) RunAndReturn(run func(context.Context, in
 	return _c
 }
 
+// GetByAuthorAfter provides a mock function with given fields: ctx, authorID, afterID, limit
+func (_m *Repository) GetByAuthorAfter(ctx context.Context, authorID int64, afterID int64, limit int) ([]*model.Post, error) {
+	ret := _m.Called(ctx, authorID, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAuthorAfter")
+	}
+
+	var r0 []*model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int) ([]*model.Post, error)); ok {
+		return rf(ctx, authorID, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int) []*model.Post); ok {
+		r0 = rf(ctx, authorID, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int) error); ok {
+		r1 = rf(ctx, authorID, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_GetByAuthorAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByAuthorAfter'
+type Repository_GetByAuthorAfter_Call struct {
+	*mock.Call
+}
+
+// GetByAuthorAfter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorID int64
+//   - afterID int64
+//   - limit int
+func (_e *Repository_Expecter) GetByAuthorAfter(ctx interface{}, authorID interface{}, afterID interface{}, limit interface{}) *Repository_GetByAuthorAfter_Call {
+	return &Repository_GetByAuthorAfter_Call{Call: _e.mock.On("GetByAuthorAfter", ctx, authorID, afterID, limit)}
+}
+
+func (_c *Repository_GetByAuthorAfter_Call) Run(run func(ctx context.Context, authorID int64, afterID int64, limit int)) *Repository_GetByAuthorAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *Repository_GetByAuthorAfter_Call) Return(_a0 []*model.Post, _a1 error) *Repository_GetByAuthorAfter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_GetByAuthorAfter_Call) RunAndReturn(run func(context.Context, int64, int64, int) ([]*model.Post, error)) *Repository_GetByAuthorAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *Repository) GetByID(ctx context.Context, id int64) (*model.Post, error) {
 	ret := _m.Called(ctx, id)
@@ -246,68 +484,781 @@ func (_c *Repository_GetByID_Call) RunAndReturn(run func(context.Context, int64)
 	return _c
 }
 
-// List provides a mock function with given fields: ctx, filters
-func (_m *Repository) List(ctx context.Context, filters model.PostFilters) ([]*model.Post, int, error) {
-	ret := _m.Called(ctx, filters)
+// GetByIDForUpdate provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByIDForUpdate(ctx context.Context, id int64) (*model.Post, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for List")
+		panic("no return value specified for GetByIDForUpdate")
 	}
 
-	var r0 []*model.Post
-	var r1 int
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.PostFilters) ([]*model.Post, int, error)); ok {
-		return rf(ctx, filters)
+	var r0 *model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Post, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, model.PostFilters) []*model.Post); ok {
-		r0 = rf(ctx, filters)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Post); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Post)
+			r0 = ret.Get(0).(*model.Post)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, model.PostFilters) int); ok {
-		r1 = rf(ctx, filters)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
-		r1 = ret.Get(1).(int)
+		r1 = ret.Error(1)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, model.PostFilters) error); ok {
-		r2 = rf(ctx, filters)
+	return r0, r1
+}
+
+// Repository_GetByIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByIDForUpdate'
+type Repository_GetByIDForUpdate_Call struct {
+	*mock.Call
+}
+
+// GetByIDForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Repository_Expecter) GetByIDForUpdate(ctx interface{}, id interface{}) *Repository_GetByIDForUpdate_Call {
+	return &Repository_GetByIDForUpdate_Call{Call: _e.mock.On("GetByIDForUpdate", ctx, id)}
+}
+
+func (_c *Repository_GetByIDForUpdate_Call) Run(run func(ctx context.Context, id int64)) *Repository_GetByIDForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_GetByIDForUpdate_Call) Return(_a0 *model.Post, _a1 error) *Repository_GetByIDForUpdate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_GetByIDForUpdate_Call) RunAndReturn(run func(context.Context, int64) (*model.Post, error)) *Repository_GetByIDForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByIDs provides a mock function with given fields: ctx, ids
+func (_m *Repository) GetByIDs(ctx context.Context, ids []int64) ([]*model.Post, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDs")
+	}
+
+	var r0 []*model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]*model.Post, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*model.Post); ok {
+		r0 = rf(ctx, ids)
 	} else {
-		r2 = ret.Error(2)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Post)
+		}
 	}
 
-	return r0, r1, r2
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// Repository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
-type Repository_List_Call struct {
+// Repository_GetByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByIDs'
+type Repository_GetByIDs_Call struct {
 	*mock.Call
 }
 
-// List is a helper method to define mock.On call
+// GetByIDs is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filters model.PostFilters
-func (_e *Repository_Expecter) List(ctx interface{}, filters interface{}) *Repository_List_Call {
-	return &Repository_List_Call{Call: _e.mock.On("List", ctx, filters)}
+//   - ids []int64
+func (_e *Repository_Expecter) GetByIDs(ctx interface{}, ids interface{}) *Repository_GetByIDs_Call {
+	return &Repository_GetByIDs_Call{Call: _e.mock.On("GetByIDs", ctx, ids)}
 }
 
-func (_c *Repository_List_Call) Run(run func(ctx context.Context, filters model.PostFilters)) *Repository_List_Call {
+func (_c *Repository_GetByIDs_Call) Run(run func(ctx context.Context, ids []int64)) *Repository_GetByIDs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(model.PostFilters))
+		run(args[0].(context.Context), args[1].([]int64))
 	})
 	return _c
 }
 
-func (_c *Repository_List_Call) Return(_a0 []*model.Post, _a1 int, _a2 error) *Repository_List_Call {
-	_c.Call.Return(_a0, _a1, _a2)
+func (_c *Repository_GetByIDs_Call) Return(_a0 []*model.Post, _a1 error) *Repository_GetByIDs_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Repository_List_Call) RunAndReturn(run func(context.Context, model.PostFilters) ([]*model.Post, int, error)) *Repository_List_Call {
+func (_c *Repository_GetByIDs_Call) RunAndReturn(run func(context.Context, []int64) ([]*model.Post, error)) *Repository_GetByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDetailedByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetDetailedByID(ctx context.Context, id int64) (*model.PostDetailed, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDetailedByID")
+	}
+
+	var r0 *model.PostDetailed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.PostDetailed, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.PostDetailed); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PostDetailed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_GetDetailedByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDetailedByID'
+type Repository_GetDetailedByID_Call struct {
+	*mock.Call
+}
+
+// GetDetailedByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Repository_Expecter) GetDetailedByID(ctx interface{}, id interface{}) *Repository_GetDetailedByID_Call {
+	return &Repository_GetDetailedByID_Call{Call: _e.mock.On("GetDetailedByID", ctx, id)}
+}
+
+func (_c *Repository_GetDetailedByID_Call) Run(run func(ctx context.Context, id int64)) *Repository_GetDetailedByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_GetDetailedByID_Call) Return(_a0 *model.PostDetailed, _a1 error) *Repository_GetDetailedByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_GetDetailedByID_Call) RunAndReturn(run func(context.Context, int64) (*model.PostDetailed, error)) *Repository_GetDetailedByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, filters
+func (_m *Repository) List(ctx context.Context, filters model.PostFilters) ([]*model.Post, int, error) {
+	ret := _m.Called(ctx, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*model.Post
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.PostFilters) ([]*model.Post, int, error)); ok {
+		return rf(ctx, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.PostFilters) []*model.Post); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.PostFilters) int); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, model.PostFilters) error); ok {
+		r2 = rf(ctx, filters)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Repository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type Repository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filters model.PostFilters
+func (_e *Repository_Expecter) List(ctx interface{}, filters interface{}) *Repository_List_Call {
+	return &Repository_List_Call{Call: _e.mock.On("List", ctx, filters)}
+}
+
+func (_c *Repository_List_Call) Run(run func(ctx context.Context, filters model.PostFilters)) *Repository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.PostFilters))
+	})
+	return _c
+}
+
+func (_c *Repository_List_Call) Return(_a0 []*model.Post, _a1 int, _a2 error) *Repository_List_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *Repository_List_Call) RunAndReturn(run func(context.Context, model.PostFilters) ([]*model.Post, int, error)) *Repository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewestByTagsSince provides a mock function with given fields: ctx, tagNames, since
+func (_m *Repository) NewestByTagsSince(ctx context.Context, tagNames []string, since time.Time) (map[string]time.Time, error) {
+	ret := _m.Called(ctx, tagNames, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewestByTagsSince")
+	}
+
+	var r0 map[string]time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time) (map[string]time.Time, error)); ok {
+		return rf(ctx, tagNames, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time) map[string]time.Time); ok {
+		r0 = rf(ctx, tagNames, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, time.Time) error); ok {
+		r1 = rf(ctx, tagNames, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_NewestByTagsSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewestByTagsSince'
+type Repository_NewestByTagsSince_Call struct {
+	*mock.Call
+}
+
+// NewestByTagsSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tagNames []string
+//   - since time.Time
+func (_e *Repository_Expecter) NewestByTagsSince(ctx interface{}, tagNames interface{}, since interface{}) *Repository_NewestByTagsSince_Call {
+	return &Repository_NewestByTagsSince_Call{Call: _e.mock.On("NewestByTagsSince", ctx, tagNames, since)}
+}
+
+func (_c *Repository_NewestByTagsSince_Call) Run(run func(ctx context.Context, tagNames []string, since time.Time)) *Repository_NewestByTagsSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Repository_NewestByTagsSince_Call) Return(_a0 map[string]time.Time, _a1 error) *Repository_NewestByTagsSince_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_NewestByTagsSince_Call) RunAndReturn(run func(context.Context, []string, time.Time) (map[string]time.Time, error)) *Repository_NewestByTagsSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Pin provides a mock function with given fields: ctx, id, now
+func (_m *Repository) Pin(ctx context.Context, id int64, now time.Time) (*model.Post, error) {
+	ret := _m.Called(ctx, id, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pin")
+	}
+
+	var r0 *model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (*model.Post, error)); ok {
+		return rf(ctx, id, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) *model.Post); ok {
+		r0 = rf(ctx, id, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, id, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_Pin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Pin'
+type Repository_Pin_Call struct {
+	*mock.Call
+}
+
+// Pin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - now time.Time
+func (_e *Repository_Expecter) Pin(ctx interface{}, id interface{}, now interface{}) *Repository_Pin_Call {
+	return &Repository_Pin_Call{Call: _e.mock.On("Pin", ctx, id, now)}
+}
+
+func (_c *Repository_Pin_Call) Run(run func(ctx context.Context, id int64, now time.Time)) *Repository_Pin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Repository_Pin_Call) Return(_a0 *model.Post, _a1 error) *Repository_Pin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_Pin_Call) RunAndReturn(run func(context.Context, int64, time.Time) (*model.Post, error)) *Repository_Pin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Publish provides a mock function with given fields: ctx, id
+func (_m *Repository) Publish(ctx context.Context, id int64) (*model.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 *model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type Repository_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Repository_Expecter) Publish(ctx interface{}, id interface{}) *Repository_Publish_Call {
+	return &Repository_Publish_Call{Call: _e.mock.On("Publish", ctx, id)}
+}
+
+func (_c *Repository_Publish_Call) Run(run func(ctx context.Context, id int64)) *Repository_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_Publish_Call) Return(_a0 *model.Post, _a1 error) *Repository_Publish_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_Publish_Call) RunAndReturn(run func(context.Context, int64) (*model.Post, error)) *Repository_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PublishDue provides a mock function with given fields: ctx, now, limit
+func (_m *Repository) PublishDue(ctx context.Context, now time.Time, limit int) ([]*model.Post, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishDue")
+	}
+
+	var r0 []*model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*model.Post, error)); ok {
+		return rf(ctx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*model.Post); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_PublishDue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishDue'
+type Repository_PublishDue_Call struct {
+	*mock.Call
+}
+
+// PublishDue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - limit int
+func (_e *Repository_Expecter) PublishDue(ctx interface{}, now interface{}, limit interface{}) *Repository_PublishDue_Call {
+	return &Repository_PublishDue_Call{Call: _e.mock.On("PublishDue", ctx, now, limit)}
+}
+
+func (_c *Repository_PublishDue_Call) Run(run func(ctx context.Context, now time.Time, limit int)) *Repository_PublishDue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Repository_PublishDue_Call) Return(_a0 []*model.Post, _a1 error) *Repository_PublishDue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_PublishDue_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]*model.Post, error)) *Repository_PublishDue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RefreshAuthorSnapshot provides a mock function with given fields: ctx, author, at
+func (_m *Repository) RefreshAuthorSnapshot(ctx context.Context, author *model.User, at time.Time) (int64, error) {
+	ret := _m.Called(ctx, author, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshAuthorSnapshot")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, time.Time) (int64, error)); ok {
+		return rf(ctx, author, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User, time.Time) int64); ok {
+		r0 = rf(ctx, author, at)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User, time.Time) error); ok {
+		r1 = rf(ctx, author, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_RefreshAuthorSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshAuthorSnapshot'
+type Repository_RefreshAuthorSnapshot_Call struct {
+	*mock.Call
+}
+
+// RefreshAuthorSnapshot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - author *model.User
+//   - at time.Time
+func (_e *Repository_Expecter) RefreshAuthorSnapshot(ctx interface{}, author interface{}, at interface{}) *Repository_RefreshAuthorSnapshot_Call {
+	return &Repository_RefreshAuthorSnapshot_Call{Call: _e.mock.On("RefreshAuthorSnapshot", ctx, author, at)}
+}
+
+func (_c *Repository_RefreshAuthorSnapshot_Call) Run(run func(ctx context.Context, author *model.User, at time.Time)) *Repository_RefreshAuthorSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.User), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Repository_RefreshAuthorSnapshot_Call) Return(_a0 int64, _a1 error) *Repository_RefreshAuthorSnapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_RefreshAuthorSnapshot_Call) RunAndReturn(run func(context.Context, *model.User, time.Time) (int64, error)) *Repository_RefreshAuthorSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetModerationStatus provides a mock function with given fields: ctx, id, status, reason, now
+func (_m *Repository) SetModerationStatus(ctx context.Context, id int64, status model.ModerationStatus, reason *model.RejectionReason, now time.Time) (*model.Post, error) {
+	ret := _m.Called(ctx, id, status, reason, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetModerationStatus")
+	}
+
+	var r0 *model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.ModerationStatus, *model.RejectionReason, time.Time) (*model.Post, error)); ok {
+		return rf(ctx, id, status, reason, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, model.ModerationStatus, *model.RejectionReason, time.Time) *model.Post); ok {
+		r0 = rf(ctx, id, status, reason, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, model.ModerationStatus, *model.RejectionReason, time.Time) error); ok {
+		r1 = rf(ctx, id, status, reason, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_SetModerationStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetModerationStatus'
+type Repository_SetModerationStatus_Call struct {
+	*mock.Call
+}
+
+// SetModerationStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - status model.ModerationStatus
+//   - reason *model.RejectionReason
+//   - now time.Time
+func (_e *Repository_Expecter) SetModerationStatus(ctx interface{}, id interface{}, status interface{}, reason interface{}, now interface{}) *Repository_SetModerationStatus_Call {
+	return &Repository_SetModerationStatus_Call{Call: _e.mock.On("SetModerationStatus", ctx, id, status, reason, now)}
+}
+
+func (_c *Repository_SetModerationStatus_Call) Run(run func(ctx context.Context, id int64, status model.ModerationStatus, reason *model.RejectionReason, now time.Time)) *Repository_SetModerationStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(model.ModerationStatus), args[3].(*model.RejectionReason), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Repository_SetModerationStatus_Call) Return(_a0 *model.Post, _a1 error) *Repository_SetModerationStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_SetModerationStatus_Call) RunAndReturn(run func(context.Context, int64, model.ModerationStatus, *model.RejectionReason, time.Time) (*model.Post, error)) *Repository_SetModerationStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Touch provides a mock function with given fields: ctx, id
+func (_m *Repository) Touch(ctx context.Context, id int64) (*model.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Touch")
+	}
+
+	var r0 *model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_Touch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Touch'
+type Repository_Touch_Call struct {
+	*mock.Call
+}
+
+// Touch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Repository_Expecter) Touch(ctx interface{}, id interface{}) *Repository_Touch_Call {
+	return &Repository_Touch_Call{Call: _e.mock.On("Touch", ctx, id)}
+}
+
+func (_c *Repository_Touch_Call) Run(run func(ctx context.Context, id int64)) *Repository_Touch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_Touch_Call) Return(_a0 *model.Post, _a1 error) *Repository_Touch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_Touch_Call) RunAndReturn(run func(context.Context, int64) (*model.Post, error)) *Repository_Touch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unpin provides a mock function with given fields: ctx, id
+func (_m *Repository) Unpin(ctx context.Context, id int64) (*model.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unpin")
+	}
+
+	var r0 *model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_Unpin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unpin'
+type Repository_Unpin_Call struct {
+	*mock.Call
+}
+
+// Unpin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Repository_Expecter) Unpin(ctx interface{}, id interface{}) *Repository_Unpin_Call {
+	return &Repository_Unpin_Call{Call: _e.mock.On("Unpin", ctx, id)}
+}
+
+func (_c *Repository_Unpin_Call) Run(run func(ctx context.Context, id int64)) *Repository_Unpin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_Unpin_Call) Return(_a0 *model.Post, _a1 error) *Repository_Unpin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_Unpin_Call) RunAndReturn(run func(context.Context, int64) (*model.Post, error)) *Repository_Unpin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnpinAuthor provides a mock function with given fields: ctx, authorID
+func (_m *Repository) UnpinAuthor(ctx context.Context, authorID int64) ([]int64, error) {
+	ret := _m.Called(ctx, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnpinAuthor")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]int64, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []int64); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_UnpinAuthor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnpinAuthor'
+type Repository_UnpinAuthor_Call struct {
+	*mock.Call
+}
+
+// UnpinAuthor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorID int64
+func (_e *Repository_Expecter) UnpinAuthor(ctx interface{}, authorID interface{}) *Repository_UnpinAuthor_Call {
+	return &Repository_UnpinAuthor_Call{Call: _e.mock.On("UnpinAuthor", ctx, authorID)}
+}
+
+func (_c *Repository_UnpinAuthor_Call) Run(run func(ctx context.Context, authorID int64)) *Repository_UnpinAuthor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_UnpinAuthor_Call) Return(_a0 []int64, _a1 error) *Repository_UnpinAuthor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_UnpinAuthor_Call) RunAndReturn(run func(context.Context, int64) ([]int64, error)) *Repository_UnpinAuthor_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -7,6 +7,8 @@ import (
 	model "pinstack-post-service/internal/domain/models"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Service is an autogenerated mock type for the Service type
@@ -22,6 +24,126 @@ func (_m *Service) EXPECT() *Service_Expecter {
 	return &Service_Expecter{mock: &_m.Mock}
 }
 
+// BulkCreatePosts provides a mock function with given fields: ctx, actorID, posts
+func (_m *Service) BulkCreatePosts(ctx context.Context, actorID int64, posts []*model.CreatePostDTO) (*model.BulkCreateResult, error) {
+	ret := _m.Called(ctx, actorID, posts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkCreatePosts")
+	}
+
+	var r0 *model.BulkCreateResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []*model.CreatePostDTO) (*model.BulkCreateResult, error)); ok {
+		return rf(ctx, actorID, posts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []*model.CreatePostDTO) *model.BulkCreateResult); ok {
+		r0 = rf(ctx, actorID, posts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BulkCreateResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []*model.CreatePostDTO) error); ok {
+		r1 = rf(ctx, actorID, posts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_BulkCreatePosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkCreatePosts'
+type Service_BulkCreatePosts_Call struct {
+	*mock.Call
+}
+
+// BulkCreatePosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID int64
+//   - posts []*model.CreatePostDTO
+func (_e *Service_Expecter) BulkCreatePosts(ctx interface{}, actorID interface{}, posts interface{}) *Service_BulkCreatePosts_Call {
+	return &Service_BulkCreatePosts_Call{Call: _e.mock.On("BulkCreatePosts", ctx, actorID, posts)}
+}
+
+func (_c *Service_BulkCreatePosts_Call) Run(run func(ctx context.Context, actorID int64, posts []*model.CreatePostDTO)) *Service_BulkCreatePosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].([]*model.CreatePostDTO))
+	})
+	return _c
+}
+
+func (_c *Service_BulkCreatePosts_Call) Return(_a0 *model.BulkCreateResult, _a1 error) *Service_BulkCreatePosts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_BulkCreatePosts_Call) RunAndReturn(run func(context.Context, int64, []*model.CreatePostDTO) (*model.BulkCreateResult, error)) *Service_BulkCreatePosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelScheduledPost provides a mock function with given fields: ctx, userID, id
+func (_m *Service) CancelScheduledPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelScheduledPost")
+	}
+
+	var r0 *model.PostDetailed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*model.PostDetailed, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *model.PostDetailed); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PostDetailed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_CancelScheduledPost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelScheduledPost'
+type Service_CancelScheduledPost_Call struct {
+	*mock.Call
+}
+
+// CancelScheduledPost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - id int64
+func (_e *Service_Expecter) CancelScheduledPost(ctx interface{}, userID interface{}, id interface{}) *Service_CancelScheduledPost_Call {
+	return &Service_CancelScheduledPost_Call{Call: _e.mock.On("CancelScheduledPost", ctx, userID, id)}
+}
+
+func (_c *Service_CancelScheduledPost_Call) Run(run func(ctx context.Context, userID int64, id int64)) *Service_CancelScheduledPost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Service_CancelScheduledPost_Call) Return(_a0 *model.PostDetailed, _a1 error) *Service_CancelScheduledPost_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_CancelScheduledPost_Call) RunAndReturn(run func(context.Context, int64, int64) (*model.PostDetailed, error)) *Service_CancelScheduledPost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreatePost provides a mock function with given fields: ctx, post
 func (_m *Service) CreatePost(ctx context.Context, post *model.CreatePostDTO) (*model.PostDetailed, error) {
 	ret := _m.Called(ctx, post)
@@ -129,9 +251,177 @@ func (_c *Service_DeletePost_Call) RunAndReturn(run func(context.Context, int64,
 	return _c
 }
 
-// GetPostByID provides a mock function with given fields: ctx, id
-func (_m *Service) GetPostByID(ctx context.Context, id int64) (*model.PostDetailed, error) {
-	ret := _m.Called(ctx, id)
+// ExportAuthorPosts provides a mock function with given fields: ctx, authorID, send
+func (_m *Service) ExportAuthorPosts(ctx context.Context, authorID int64, send func(*model.PostDetailed) error) error {
+	ret := _m.Called(ctx, authorID, send)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportAuthorPosts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, func(*model.PostDetailed) error) error); ok {
+		r0 = rf(ctx, authorID, send)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_ExportAuthorPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportAuthorPosts'
+type Service_ExportAuthorPosts_Call struct {
+	*mock.Call
+}
+
+// ExportAuthorPosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorID int64
+//   - send func(*model.PostDetailed) error
+func (_e *Service_Expecter) ExportAuthorPosts(ctx interface{}, authorID interface{}, send interface{}) *Service_ExportAuthorPosts_Call {
+	return &Service_ExportAuthorPosts_Call{Call: _e.mock.On("ExportAuthorPosts", ctx, authorID, send)}
+}
+
+func (_c *Service_ExportAuthorPosts_Call) Run(run func(ctx context.Context, authorID int64, send func(*model.PostDetailed) error)) *Service_ExportAuthorPosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(func(*model.PostDetailed) error))
+	})
+	return _c
+}
+
+func (_c *Service_ExportAuthorPosts_Call) Return(_a0 error) *Service_ExportAuthorPosts_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_ExportAuthorPosts_Call) RunAndReturn(run func(context.Context, int64, func(*model.PostDetailed) error) error) *Service_ExportAuthorPosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForceDeletePost provides a mock function with given fields: ctx, actorID, postID, reason
+func (_m *Service) ForceDeletePost(ctx context.Context, actorID int64, postID int64, reason string) (*model.ModerationAction, error) {
+	ret := _m.Called(ctx, actorID, postID, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForceDeletePost")
+	}
+
+	var r0 *model.ModerationAction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) (*model.ModerationAction, error)); ok {
+		return rf(ctx, actorID, postID, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) *model.ModerationAction); ok {
+		r0 = rf(ctx, actorID, postID, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ModerationAction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string) error); ok {
+		r1 = rf(ctx, actorID, postID, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_ForceDeletePost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForceDeletePost'
+type Service_ForceDeletePost_Call struct {
+	*mock.Call
+}
+
+// ForceDeletePost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID int64
+//   - postID int64
+//   - reason string
+func (_e *Service_Expecter) ForceDeletePost(ctx interface{}, actorID interface{}, postID interface{}, reason interface{}) *Service_ForceDeletePost_Call {
+	return &Service_ForceDeletePost_Call{Call: _e.mock.On("ForceDeletePost", ctx, actorID, postID, reason)}
+}
+
+func (_c *Service_ForceDeletePost_Call) Run(run func(ctx context.Context, actorID int64, postID int64, reason string)) *Service_ForceDeletePost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Service_ForceDeletePost_Call) Return(_a0 *model.ModerationAction, _a1 error) *Service_ForceDeletePost_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_ForceDeletePost_Call) RunAndReturn(run func(context.Context, int64, int64, string) (*model.ModerationAction, error)) *Service_ForceDeletePost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAuthorPostCount provides a mock function with given fields: ctx, authorID
+func (_m *Service) GetAuthorPostCount(ctx context.Context, authorID int64) (*model.AuthorPostCount, error) {
+	ret := _m.Called(ctx, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuthorPostCount")
+	}
+
+	var r0 *model.AuthorPostCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.AuthorPostCount, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.AuthorPostCount); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthorPostCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetAuthorPostCount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuthorPostCount'
+type Service_GetAuthorPostCount_Call struct {
+	*mock.Call
+}
+
+// GetAuthorPostCount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authorID int64
+func (_e *Service_Expecter) GetAuthorPostCount(ctx interface{}, authorID interface{}) *Service_GetAuthorPostCount_Call {
+	return &Service_GetAuthorPostCount_Call{Call: _e.mock.On("GetAuthorPostCount", ctx, authorID)}
+}
+
+func (_c *Service_GetAuthorPostCount_Call) Run(run func(ctx context.Context, authorID int64)) *Service_GetAuthorPostCount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Service_GetAuthorPostCount_Call) Return(_a0 *model.AuthorPostCount, _a1 error) *Service_GetAuthorPostCount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetAuthorPostCount_Call) RunAndReturn(run func(context.Context, int64) (*model.AuthorPostCount, error)) *Service_GetAuthorPostCount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPostByID provides a mock function with given fields: ctx, id, requesterID
+func (_m *Service) GetPostByID(ctx context.Context, id int64, requesterID *int64) (*model.PostDetailed, error) {
+	ret := _m.Called(ctx, id, requesterID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetPostByID")
@@ -139,19 +429,19 @@ func (_m *Service) GetPostByID(ctx context.Context, id int64) (*model.PostDetail
 
 	var r0 *model.PostDetailed
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.PostDetailed, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *int64) (*model.PostDetailed, error)); ok {
+		return rf(ctx, id, requesterID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.PostDetailed); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *int64) *model.PostDetailed); ok {
+		r0 = rf(ctx, id, requesterID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.PostDetailed)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *int64) error); ok {
+		r1 = rf(ctx, id, requesterID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -167,13 +457,14 @@ type Service_GetPostByID_Call struct {
 // GetPostByID is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id int64
-func (_e *Service_Expecter) GetPostByID(ctx interface{}, id interface{}) *Service_GetPostByID_Call {
-	return &Service_GetPostByID_Call{Call: _e.mock.On("GetPostByID", ctx, id)}
+//   - requesterID *int64
+func (_e *Service_Expecter) GetPostByID(ctx interface{}, id interface{}, requesterID interface{}) *Service_GetPostByID_Call {
+	return &Service_GetPostByID_Call{Call: _e.mock.On("GetPostByID", ctx, id, requesterID)}
 }
 
-func (_c *Service_GetPostByID_Call) Run(run func(ctx context.Context, id int64)) *Service_GetPostByID_Call {
+func (_c *Service_GetPostByID_Call) Run(run func(ctx context.Context, id int64, requesterID *int64)) *Service_GetPostByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(*int64))
 	})
 	return _c
 }
@@ -183,41 +474,41 @@ func (_c *Service_GetPostByID_Call) Return(_a0 *model.PostDetailed, _a1 error) *
 	return _c
 }
 
-func (_c *Service_GetPostByID_Call) RunAndReturn(run func(context.Context, int64) (*model.PostDetailed, error)) *Service_GetPostByID_Call {
+func (_c *Service_GetPostByID_Call) RunAndReturn(run func(context.Context, int64, *int64) (*model.PostDetailed, error)) *Service_GetPostByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListPosts provides a mock function with given fields: ctx, filters
-func (_m *Service) ListPosts(ctx context.Context, filters *model.PostFilters) ([]*model.PostDetailed, int, error) {
-	ret := _m.Called(ctx, filters)
+// GetPostMedia provides a mock function with given fields: ctx, postID, requesterID, limit, offset
+func (_m *Service) GetPostMedia(ctx context.Context, postID int64, requesterID *int64, limit int, offset int) ([]*model.PostMedia, int, error) {
+	ret := _m.Called(ctx, postID, requesterID, limit, offset)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListPosts")
+		panic("no return value specified for GetPostMedia")
 	}
 
-	var r0 []*model.PostDetailed
+	var r0 []*model.PostMedia
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.PostFilters) ([]*model.PostDetailed, int, error)); ok {
-		return rf(ctx, filters)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *int64, int, int) ([]*model.PostMedia, int, error)); ok {
+		return rf(ctx, postID, requesterID, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *model.PostFilters) []*model.PostDetailed); ok {
-		r0 = rf(ctx, filters)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *int64, int, int) []*model.PostMedia); ok {
+		r0 = rf(ctx, postID, requesterID, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.PostDetailed)
+			r0 = ret.Get(0).([]*model.PostMedia)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *model.PostFilters) int); ok {
-		r1 = rf(ctx, filters)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *int64, int, int) int); ok {
+		r1 = rf(ctx, postID, requesterID, limit, offset)
 	} else {
 		r1 = ret.Get(1).(int)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, *model.PostFilters) error); ok {
-		r2 = rf(ctx, filters)
+	if rf, ok := ret.Get(2).(func(context.Context, int64, *int64, int, int) error); ok {
+		r2 = rf(ctx, postID, requesterID, limit, offset)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -225,80 +516,833 @@ func (_m *Service) ListPosts(ctx context.Context, filters *model.PostFilters) ([
 	return r0, r1, r2
 }
 
-// Service_ListPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPosts'
-type Service_ListPosts_Call struct {
+// Service_GetPostMedia_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPostMedia'
+type Service_GetPostMedia_Call struct {
 	*mock.Call
 }
 
-// ListPosts is a helper method to define mock.On call
+// GetPostMedia is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filters *model.PostFilters
-func (_e *Service_Expecter) ListPosts(ctx interface{}, filters interface{}) *Service_ListPosts_Call {
-	return &Service_ListPosts_Call{Call: _e.mock.On("ListPosts", ctx, filters)}
+//   - postID int64
+//   - requesterID *int64
+//   - limit int
+//   - offset int
+func (_e *Service_Expecter) GetPostMedia(ctx interface{}, postID interface{}, requesterID interface{}, limit interface{}, offset interface{}) *Service_GetPostMedia_Call {
+	return &Service_GetPostMedia_Call{Call: _e.mock.On("GetPostMedia", ctx, postID, requesterID, limit, offset)}
 }
 
-func (_c *Service_ListPosts_Call) Run(run func(ctx context.Context, filters *model.PostFilters)) *Service_ListPosts_Call {
+func (_c *Service_GetPostMedia_Call) Run(run func(ctx context.Context, postID int64, requesterID *int64, limit int, offset int)) *Service_GetPostMedia_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*model.PostFilters))
+		run(args[0].(context.Context), args[1].(int64), args[2].(*int64), args[3].(int), args[4].(int))
 	})
 	return _c
 }
 
-func (_c *Service_ListPosts_Call) Return(_a0 []*model.PostDetailed, _a1 int, _a2 error) *Service_ListPosts_Call {
+func (_c *Service_GetPostMedia_Call) Return(_a0 []*model.PostMedia, _a1 int, _a2 error) *Service_GetPostMedia_Call {
 	_c.Call.Return(_a0, _a1, _a2)
 	return _c
 }
 
-func (_c *Service_ListPosts_Call) RunAndReturn(run func(context.Context, *model.PostFilters) ([]*model.PostDetailed, int, error)) *Service_ListPosts_Call {
+func (_c *Service_GetPostMedia_Call) RunAndReturn(run func(context.Context, int64, *int64, int, int) ([]*model.PostMedia, int, error)) *Service_GetPostMedia_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdatePost provides a mock function with given fields: ctx, userID, id, post
-func (_m *Service) UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) error {
-	ret := _m.Called(ctx, userID, id, post)
+// GetPostRevisions provides a mock function with given fields: ctx, userID, postID, limit, offset
+func (_m *Service) GetPostRevisions(ctx context.Context, userID int64, postID int64, limit int, offset int) ([]*model.PostRevision, int, error) {
+	ret := _m.Called(ctx, userID, postID, limit, offset)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdatePost")
+		panic("no return value specified for GetPostRevisions")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, *model.UpdatePostDTO) error); ok {
-		r0 = rf(ctx, userID, id, post)
+	var r0 []*model.PostRevision
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int, int) ([]*model.PostRevision, int, error)); ok {
+		return rf(ctx, userID, postID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int, int) []*model.PostRevision); ok {
+		r0 = rf(ctx, userID, postID, limit, offset)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PostRevision)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int, int) int); ok {
+		r1 = rf(ctx, userID, postID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, int64, int, int) error); ok {
+		r2 = rf(ctx, userID, postID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// Service_UpdatePost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePost'
-type Service_UpdatePost_Call struct {
+// Service_GetPostRevisions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPostRevisions'
+type Service_GetPostRevisions_Call struct {
 	*mock.Call
 }
 
-// UpdatePost is a helper method to define mock.On call
+// GetPostRevisions is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userID int64
-//   - id int64
-//   - post *model.UpdatePostDTO
-func (_e *Service_Expecter) UpdatePost(ctx interface{}, userID interface{}, id interface{}, post interface{}) *Service_UpdatePost_Call {
-	return &Service_UpdatePost_Call{Call: _e.mock.On("UpdatePost", ctx, userID, id, post)}
+//   - postID int64
+//   - limit int
+//   - offset int
+func (_e *Service_Expecter) GetPostRevisions(ctx interface{}, userID interface{}, postID interface{}, limit interface{}, offset interface{}) *Service_GetPostRevisions_Call {
+	return &Service_GetPostRevisions_Call{Call: _e.mock.On("GetPostRevisions", ctx, userID, postID, limit, offset)}
 }
 
-func (_c *Service_UpdatePost_Call) Run(run func(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO)) *Service_UpdatePost_Call {
+func (_c *Service_GetPostRevisions_Call) Run(run func(ctx context.Context, userID int64, postID int64, limit int, offset int)) *Service_GetPostRevisions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(*model.UpdatePostDTO))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(int), args[4].(int))
 	})
 	return _c
 }
 
-func (_c *Service_UpdatePost_Call) Return(_a0 error) *Service_UpdatePost_Call {
-	_c.Call.Return(_a0)
+func (_c *Service_GetPostRevisions_Call) Return(_a0 []*model.PostRevision, _a1 int, _a2 error) *Service_GetPostRevisions_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *Service_GetPostRevisions_Call) RunAndReturn(run func(context.Context, int64, int64, int, int) ([]*model.PostRevision, int, error)) *Service_GetPostRevisions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPostTags provides a mock function with given fields: ctx, postID, requesterID, includeCounts
+func (_m *Service) GetPostTags(ctx context.Context, postID int64, requesterID *int64, includeCounts bool) ([]*model.Tag, error) {
+	ret := _m.Called(ctx, postID, requesterID, includeCounts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPostTags")
+	}
+
+	var r0 []*model.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *int64, bool) ([]*model.Tag, error)); ok {
+		return rf(ctx, postID, requesterID, includeCounts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *int64, bool) []*model.Tag); ok {
+		r0 = rf(ctx, postID, requesterID, includeCounts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *int64, bool) error); ok {
+		r1 = rf(ctx, postID, requesterID, includeCounts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetPostTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPostTags'
+type Service_GetPostTags_Call struct {
+	*mock.Call
+}
+
+// GetPostTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postID int64
+//   - requesterID *int64
+//   - includeCounts bool
+func (_e *Service_Expecter) GetPostTags(ctx interface{}, postID interface{}, requesterID interface{}, includeCounts interface{}) *Service_GetPostTags_Call {
+	return &Service_GetPostTags_Call{Call: _e.mock.On("GetPostTags", ctx, postID, requesterID, includeCounts)}
+}
+
+func (_c *Service_GetPostTags_Call) Run(run func(ctx context.Context, postID int64, requesterID *int64, includeCounts bool)) *Service_GetPostTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(*int64), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *Service_GetPostTags_Call) Return(_a0 []*model.Tag, _a1 error) *Service_GetPostTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetPostTags_Call) RunAndReturn(run func(context.Context, int64, *int64, bool) ([]*model.Tag, error)) *Service_GetPostTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPostsByIDs provides a mock function with given fields: ctx, ids
+func (_m *Service) GetPostsByIDs(ctx context.Context, ids []int64) ([]*model.PostDetailed, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPostsByIDs")
+	}
+
+	var r0 []*model.PostDetailed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]*model.PostDetailed, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []*model.PostDetailed); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PostDetailed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetPostsByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPostsByIDs'
+type Service_GetPostsByIDs_Call struct {
+	*mock.Call
+}
+
+// GetPostsByIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int64
+func (_e *Service_Expecter) GetPostsByIDs(ctx interface{}, ids interface{}) *Service_GetPostsByIDs_Call {
+	return &Service_GetPostsByIDs_Call{Call: _e.mock.On("GetPostsByIDs", ctx, ids)}
+}
+
+func (_c *Service_GetPostsByIDs_Call) Run(run func(ctx context.Context, ids []int64)) *Service_GetPostsByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64))
+	})
+	return _c
+}
+
+func (_c *Service_GetPostsByIDs_Call) Return(_a0 []*model.PostDetailed, _a1 error) *Service_GetPostsByIDs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetPostsByIDs_Call) RunAndReturn(run func(context.Context, []int64) ([]*model.PostDetailed, error)) *Service_GetPostsByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HasPostsSince provides a mock function with given fields: ctx, tagNames, since
+func (_m *Service) HasPostsSince(ctx context.Context, tagNames []string, since time.Time) (*model.TagActivity, error) {
+	ret := _m.Called(ctx, tagNames, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasPostsSince")
+	}
+
+	var r0 *model.TagActivity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time) (*model.TagActivity, error)); ok {
+		return rf(ctx, tagNames, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time) *model.TagActivity); ok {
+		r0 = rf(ctx, tagNames, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TagActivity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, time.Time) error); ok {
+		r1 = rf(ctx, tagNames, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_HasPostsSince_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasPostsSince'
+type Service_HasPostsSince_Call struct {
+	*mock.Call
+}
+
+// HasPostsSince is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tagNames []string
+//   - since time.Time
+func (_e *Service_Expecter) HasPostsSince(ctx interface{}, tagNames interface{}, since interface{}) *Service_HasPostsSince_Call {
+	return &Service_HasPostsSince_Call{Call: _e.mock.On("HasPostsSince", ctx, tagNames, since)}
+}
+
+func (_c *Service_HasPostsSince_Call) Run(run func(ctx context.Context, tagNames []string, since time.Time)) *Service_HasPostsSince_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Service_HasPostsSince_Call) Return(_a0 *model.TagActivity, _a1 error) *Service_HasPostsSince_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_HasPostsSince_Call) RunAndReturn(run func(context.Context, []string, time.Time) (*model.TagActivity, error)) *Service_HasPostsSince_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPosts provides a mock function with given fields: ctx, filters
+func (_m *Service) ListPosts(ctx context.Context, filters *model.PostFilters) ([]*model.PostDetailed, int, error) {
+	ret := _m.Called(ctx, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPosts")
+	}
+
+	var r0 []*model.PostDetailed
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.PostFilters) ([]*model.PostDetailed, int, error)); ok {
+		return rf(ctx, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.PostFilters) []*model.PostDetailed); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PostDetailed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.PostFilters) int); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *model.PostFilters) error); ok {
+		r2 = rf(ctx, filters)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Service_ListPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPosts'
+type Service_ListPosts_Call struct {
+	*mock.Call
+}
+
+// ListPosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filters *model.PostFilters
+func (_e *Service_Expecter) ListPosts(ctx interface{}, filters interface{}) *Service_ListPosts_Call {
+	return &Service_ListPosts_Call{Call: _e.mock.On("ListPosts", ctx, filters)}
+}
+
+func (_c *Service_ListPosts_Call) Run(run func(ctx context.Context, filters *model.PostFilters)) *Service_ListPosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.PostFilters))
+	})
+	return _c
+}
+
+func (_c *Service_ListPosts_Call) Return(_a0 []*model.PostDetailed, _a1 int, _a2 error) *Service_ListPosts_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *Service_ListPosts_Call) RunAndReturn(run func(context.Context, *model.PostFilters) ([]*model.PostDetailed, int, error)) *Service_ListPosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MergeTags provides a mock function with given fields: ctx, sourceIDs, destID
+func (_m *Service) MergeTags(ctx context.Context, sourceIDs []int64, destID int64) (*model.TagChange, error) {
+	ret := _m.Called(ctx, sourceIDs, destID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeTags")
+	}
+
+	var r0 *model.TagChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, int64) (*model.TagChange, error)); ok {
+		return rf(ctx, sourceIDs, destID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, int64) *model.TagChange); ok {
+		r0 = rf(ctx, sourceIDs, destID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TagChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64, int64) error); ok {
+		r1 = rf(ctx, sourceIDs, destID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_MergeTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergeTags'
+type Service_MergeTags_Call struct {
+	*mock.Call
+}
+
+// MergeTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sourceIDs []int64
+//   - destID int64
+func (_e *Service_Expecter) MergeTags(ctx interface{}, sourceIDs interface{}, destID interface{}) *Service_MergeTags_Call {
+	return &Service_MergeTags_Call{Call: _e.mock.On("MergeTags", ctx, sourceIDs, destID)}
+}
+
+func (_c *Service_MergeTags_Call) Run(run func(ctx context.Context, sourceIDs []int64, destID int64)) *Service_MergeTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Service_MergeTags_Call) Return(_a0 *model.TagChange, _a1 error) *Service_MergeTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_MergeTags_Call) RunAndReturn(run func(context.Context, []int64, int64) (*model.TagChange, error)) *Service_MergeTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PinPost provides a mock function with given fields: ctx, userID, id
+func (_m *Service) PinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PinPost")
+	}
+
+	var r0 *model.PinChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*model.PinChange, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *model.PinChange); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PinChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_PinPost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PinPost'
+type Service_PinPost_Call struct {
+	*mock.Call
+}
+
+// PinPost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - id int64
+func (_e *Service_Expecter) PinPost(ctx interface{}, userID interface{}, id interface{}) *Service_PinPost_Call {
+	return &Service_PinPost_Call{Call: _e.mock.On("PinPost", ctx, userID, id)}
+}
+
+func (_c *Service_PinPost_Call) Run(run func(ctx context.Context, userID int64, id int64)) *Service_PinPost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Service_PinPost_Call) Return(_a0 *model.PinChange, _a1 error) *Service_PinPost_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_PinPost_Call) RunAndReturn(run func(context.Context, int64, int64) (*model.PinChange, error)) *Service_PinPost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PublishPost provides a mock function with given fields: ctx, userID, id
+func (_m *Service) PublishPost(ctx context.Context, userID int64, id int64) (*model.PostDetailed, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishPost")
+	}
+
+	var r0 *model.PostDetailed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*model.PostDetailed, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *model.PostDetailed); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PostDetailed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_PublishPost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishPost'
+type Service_PublishPost_Call struct {
+	*mock.Call
+}
+
+// PublishPost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - id int64
+func (_e *Service_Expecter) PublishPost(ctx interface{}, userID interface{}, id interface{}) *Service_PublishPost_Call {
+	return &Service_PublishPost_Call{Call: _e.mock.On("PublishPost", ctx, userID, id)}
+}
+
+func (_c *Service_PublishPost_Call) Run(run func(ctx context.Context, userID int64, id int64)) *Service_PublishPost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Service_PublishPost_Call) Return(_a0 *model.PostDetailed, _a1 error) *Service_PublishPost_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_PublishPost_Call) RunAndReturn(run func(context.Context, int64, int64) (*model.PostDetailed, error)) *Service_PublishPost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenameTag provides a mock function with given fields: ctx, tagID, name
+func (_m *Service) RenameTag(ctx context.Context, tagID int64, name string) (*model.TagChange, error) {
+	ret := _m.Called(ctx, tagID, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameTag")
+	}
+
+	var r0 *model.TagChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*model.TagChange, error)); ok {
+		return rf(ctx, tagID, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *model.TagChange); ok {
+		r0 = rf(ctx, tagID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TagChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, tagID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_RenameTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenameTag'
+type Service_RenameTag_Call struct {
+	*mock.Call
+}
+
+// RenameTag is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tagID int64
+//   - name string
+func (_e *Service_Expecter) RenameTag(ctx interface{}, tagID interface{}, name interface{}) *Service_RenameTag_Call {
+	return &Service_RenameTag_Call{Call: _e.mock.On("RenameTag", ctx, tagID, name)}
+}
+
+func (_c *Service_RenameTag_Call) Run(run func(ctx context.Context, tagID int64, name string)) *Service_RenameTag_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Service_RenameTag_Call) Return(_a0 *model.TagChange, _a1 error) *Service_RenameTag_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_RenameTag_Call) RunAndReturn(run func(context.Context, int64, string) (*model.TagChange, error)) *Service_RenameTag_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetModerationStatus provides a mock function with given fields: ctx, actorID, postID, status, reason
+func (_m *Service) SetModerationStatus(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason) (*model.Post, error) {
+	ret := _m.Called(ctx, actorID, postID, status, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetModerationStatus")
+	}
+
+	var r0 *model.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, model.ModerationStatus, model.RejectionReason) (*model.Post, error)); ok {
+		return rf(ctx, actorID, postID, status, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, model.ModerationStatus, model.RejectionReason) *model.Post); ok {
+		r0 = rf(ctx, actorID, postID, status, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, model.ModerationStatus, model.RejectionReason) error); ok {
+		r1 = rf(ctx, actorID, postID, status, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_SetModerationStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetModerationStatus'
+type Service_SetModerationStatus_Call struct {
+	*mock.Call
+}
+
+// SetModerationStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID int64
+//   - postID int64
+//   - status model.ModerationStatus
+//   - reason model.RejectionReason
+func (_e *Service_Expecter) SetModerationStatus(ctx interface{}, actorID interface{}, postID interface{}, status interface{}, reason interface{}) *Service_SetModerationStatus_Call {
+	return &Service_SetModerationStatus_Call{Call: _e.mock.On("SetModerationStatus", ctx, actorID, postID, status, reason)}
+}
+
+func (_c *Service_SetModerationStatus_Call) Run(run func(ctx context.Context, actorID int64, postID int64, status model.ModerationStatus, reason model.RejectionReason)) *Service_SetModerationStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(model.ModerationStatus), args[4].(model.RejectionReason))
+	})
+	return _c
+}
+
+func (_c *Service_SetModerationStatus_Call) Return(_a0 *model.Post, _a1 error) *Service_SetModerationStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_SetModerationStatus_Call) RunAndReturn(run func(context.Context, int64, int64, model.ModerationStatus, model.RejectionReason) (*model.Post, error)) *Service_SetModerationStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SuggestTags provides a mock function with given fields: ctx, prefix, limit, authorID
+func (_m *Service) SuggestTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error) {
+	ret := _m.Called(ctx, prefix, limit, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SuggestTags")
+	}
+
+	var r0 []*model.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *int64) ([]*model.Tag, error)); ok {
+		return rf(ctx, prefix, limit, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *int64) []*model.Tag); ok {
+		r0 = rf(ctx, prefix, limit, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, *int64) error); ok {
+		r1 = rf(ctx, prefix, limit, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_SuggestTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SuggestTags'
+type Service_SuggestTags_Call struct {
+	*mock.Call
+}
+
+// SuggestTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+//   - limit int
+//   - authorID *int64
+func (_e *Service_Expecter) SuggestTags(ctx interface{}, prefix interface{}, limit interface{}, authorID interface{}) *Service_SuggestTags_Call {
+	return &Service_SuggestTags_Call{Call: _e.mock.On("SuggestTags", ctx, prefix, limit, authorID)}
+}
+
+func (_c *Service_SuggestTags_Call) Run(run func(ctx context.Context, prefix string, limit int, authorID *int64)) *Service_SuggestTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(*int64))
+	})
+	return _c
+}
+
+func (_c *Service_SuggestTags_Call) Return(_a0 []*model.Tag, _a1 error) *Service_SuggestTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_SuggestTags_Call) RunAndReturn(run func(context.Context, string, int, *int64) ([]*model.Tag, error)) *Service_SuggestTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnpinPost provides a mock function with given fields: ctx, userID, id
+func (_m *Service) UnpinPost(ctx context.Context, userID int64, id int64) (*model.PinChange, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnpinPost")
+	}
+
+	var r0 *model.PinChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*model.PinChange, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *model.PinChange); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PinChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_UnpinPost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnpinPost'
+type Service_UnpinPost_Call struct {
+	*mock.Call
+}
+
+// UnpinPost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - id int64
+func (_e *Service_Expecter) UnpinPost(ctx interface{}, userID interface{}, id interface{}) *Service_UnpinPost_Call {
+	return &Service_UnpinPost_Call{Call: _e.mock.On("UnpinPost", ctx, userID, id)}
+}
+
+func (_c *Service_UnpinPost_Call) Run(run func(ctx context.Context, userID int64, id int64)) *Service_UnpinPost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Service_UnpinPost_Call) Return(_a0 *model.PinChange, _a1 error) *Service_UnpinPost_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_UnpinPost_Call) RunAndReturn(run func(context.Context, int64, int64) (*model.PinChange, error)) *Service_UnpinPost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePost provides a mock function with given fields: ctx, userID, id, post
+func (_m *Service) UpdatePost(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO) (*model.PostDetailed, error) {
+	ret := _m.Called(ctx, userID, id, post)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePost")
+	}
+
+	var r0 *model.PostDetailed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, *model.UpdatePostDTO) (*model.PostDetailed, error)); ok {
+		return rf(ctx, userID, id, post)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, *model.UpdatePostDTO) *model.PostDetailed); ok {
+		r0 = rf(ctx, userID, id, post)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PostDetailed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, *model.UpdatePostDTO) error); ok {
+		r1 = rf(ctx, userID, id, post)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_UpdatePost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePost'
+type Service_UpdatePost_Call struct {
+	*mock.Call
+}
+
+// UpdatePost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - id int64
+//   - post *model.UpdatePostDTO
+func (_e *Service_Expecter) UpdatePost(ctx interface{}, userID interface{}, id interface{}, post interface{}) *Service_UpdatePost_Call {
+	return &Service_UpdatePost_Call{Call: _e.mock.On("UpdatePost", ctx, userID, id, post)}
+}
+
+func (_c *Service_UpdatePost_Call) Run(run func(ctx context.Context, userID int64, id int64, post *model.UpdatePostDTO)) *Service_UpdatePost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(*model.UpdatePostDTO))
+	})
+	return _c
+}
+
+func (_c *Service_UpdatePost_Call) Return(_a0 *model.PostDetailed, _a1 error) *Service_UpdatePost_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Service_UpdatePost_Call) RunAndReturn(run func(context.Context, int64, int64, *model.UpdatePostDTO) error) *Service_UpdatePost_Call {
+func (_c *Service_UpdatePost_Call) RunAndReturn(run func(context.Context, int64, int64, *model.UpdatePostDTO) (*model.PostDetailed, error)) *Service_UpdatePost_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -3,13 +3,20 @@
 package postgres
 
 import (
+	archive_repository "pinstack-post-service/internal/domain/ports/output/archive"
+
 	context "context"
+
 	media_repository "pinstack-post-service/internal/domain/ports/output/media"
 
 	mock "github.com/stretchr/testify/mock"
 
+	moderation_repository "pinstack-post-service/internal/domain/ports/output/moderation"
+
 	post_repository "pinstack-post-service/internal/domain/ports/output/post"
 
+	revision_repository "pinstack-post-service/internal/domain/ports/output/revision"
+
 	tag_repository "pinstack-post-service/internal/domain/ports/output/tag"
 )
 
@@ -26,6 +33,53 @@ func (_m *Transaction) EXPECT() *Transaction_Expecter {
 	return &Transaction_Expecter{mock: &_m.Mock}
 }
 
+// ArchiveRepository provides a mock function with no fields
+func (_m *Transaction) ArchiveRepository() archive_repository.Repository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchiveRepository")
+	}
+
+	var r0 archive_repository.Repository
+	if rf, ok := ret.Get(0).(func() archive_repository.Repository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(archive_repository.Repository)
+		}
+	}
+
+	return r0
+}
+
+// Transaction_ArchiveRepository_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ArchiveRepository'
+type Transaction_ArchiveRepository_Call struct {
+	*mock.Call
+}
+
+// ArchiveRepository is a helper method to define mock.On call
+func (_e *Transaction_Expecter) ArchiveRepository() *Transaction_ArchiveRepository_Call {
+	return &Transaction_ArchiveRepository_Call{Call: _e.mock.On("ArchiveRepository")}
+}
+
+func (_c *Transaction_ArchiveRepository_Call) Run(run func()) *Transaction_ArchiveRepository_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Transaction_ArchiveRepository_Call) Return(_a0 archive_repository.Repository) *Transaction_ArchiveRepository_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Transaction_ArchiveRepository_Call) RunAndReturn(run func() archive_repository.Repository) *Transaction_ArchiveRepository_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Commit provides a mock function with given fields: ctx
 func (_m *Transaction) Commit(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -119,6 +173,53 @@ func (_c *Transaction_MediaRepository_Call) RunAndReturn(run func() media_reposi
 	return _c
 }
 
+// ModerationRepository provides a mock function with no fields
+func (_m *Transaction) ModerationRepository() moderation_repository.Repository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ModerationRepository")
+	}
+
+	var r0 moderation_repository.Repository
+	if rf, ok := ret.Get(0).(func() moderation_repository.Repository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(moderation_repository.Repository)
+		}
+	}
+
+	return r0
+}
+
+// Transaction_ModerationRepository_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ModerationRepository'
+type Transaction_ModerationRepository_Call struct {
+	*mock.Call
+}
+
+// ModerationRepository is a helper method to define mock.On call
+func (_e *Transaction_Expecter) ModerationRepository() *Transaction_ModerationRepository_Call {
+	return &Transaction_ModerationRepository_Call{Call: _e.mock.On("ModerationRepository")}
+}
+
+func (_c *Transaction_ModerationRepository_Call) Run(run func()) *Transaction_ModerationRepository_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Transaction_ModerationRepository_Call) Return(_a0 moderation_repository.Repository) *Transaction_ModerationRepository_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Transaction_ModerationRepository_Call) RunAndReturn(run func() moderation_repository.Repository) *Transaction_ModerationRepository_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PostRepository provides a mock function with no fields
 func (_m *Transaction) PostRepository() post_repository.Repository {
 	ret := _m.Called()
@@ -166,6 +267,53 @@ func (_c *Transaction_PostRepository_Call) RunAndReturn(run func() post_reposito
 	return _c
 }
 
+// RevisionRepository provides a mock function with no fields
+func (_m *Transaction) RevisionRepository() revision_repository.Repository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevisionRepository")
+	}
+
+	var r0 revision_repository.Repository
+	if rf, ok := ret.Get(0).(func() revision_repository.Repository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(revision_repository.Repository)
+		}
+	}
+
+	return r0
+}
+
+// Transaction_RevisionRepository_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevisionRepository'
+type Transaction_RevisionRepository_Call struct {
+	*mock.Call
+}
+
+// RevisionRepository is a helper method to define mock.On call
+func (_e *Transaction_Expecter) RevisionRepository() *Transaction_RevisionRepository_Call {
+	return &Transaction_RevisionRepository_Call{Call: _e.mock.On("RevisionRepository")}
+}
+
+func (_c *Transaction_RevisionRepository_Call) Run(run func()) *Transaction_RevisionRepository_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Transaction_RevisionRepository_Call) Return(_a0 revision_repository.Repository) *Transaction_RevisionRepository_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Transaction_RevisionRepository_Call) RunAndReturn(run func() revision_repository.Repository) *Transaction_RevisionRepository_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Rollback provides a mock function with given fields: ctx
 func (_m *Transaction) Rollback(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -80,6 +80,65 @@ func (_c *UnitOfWork_Begin_Call) RunAndReturn(run func(context.Context) (postgre
 	return _c
 }
 
+// BeginWithOptions provides a mock function with given fields: ctx, opts
+func (_m *UnitOfWork) BeginWithOptions(ctx context.Context, opts postgres.TxOptions) (postgres.Transaction, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginWithOptions")
+	}
+
+	var r0 postgres.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, postgres.TxOptions) (postgres.Transaction, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, postgres.TxOptions) postgres.Transaction); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(postgres.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, postgres.TxOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnitOfWork_BeginWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BeginWithOptions'
+type UnitOfWork_BeginWithOptions_Call struct {
+	*mock.Call
+}
+
+// BeginWithOptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts postgres.TxOptions
+func (_e *UnitOfWork_Expecter) BeginWithOptions(ctx interface{}, opts interface{}) *UnitOfWork_BeginWithOptions_Call {
+	return &UnitOfWork_BeginWithOptions_Call{Call: _e.mock.On("BeginWithOptions", ctx, opts)}
+}
+
+func (_c *UnitOfWork_BeginWithOptions_Call) Run(run func(ctx context.Context, opts postgres.TxOptions)) *UnitOfWork_BeginWithOptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(postgres.TxOptions))
+	})
+	return _c
+}
+
+func (_c *UnitOfWork_BeginWithOptions_Call) Return(_a0 postgres.Transaction, _a1 error) *UnitOfWork_BeginWithOptions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UnitOfWork_BeginWithOptions_Call) RunAndReturn(run func(context.Context, postgres.TxOptions) (postgres.Transaction, error)) *UnitOfWork_BeginWithOptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewUnitOfWork creates a new instance of UnitOfWork. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUnitOfWork(t interface {
@@ -22,6 +22,65 @@ func (_m *Repository) EXPECT() *Repository_Expecter {
 	return &Repository_Expecter{mock: &_m.Mock}
 }
 
+// CountPosts provides a mock function with given fields: ctx, tagIDs
+func (_m *Repository) CountPosts(ctx context.Context, tagIDs []int64) (map[int64]int64, error) {
+	ret := _m.Called(ctx, tagIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountPosts")
+	}
+
+	var r0 map[int64]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) (map[int64]int64, error)); ok {
+		return rf(ctx, tagIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64]int64); ok {
+		r0 = rf(ctx, tagIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, tagIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_CountPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountPosts'
+type Repository_CountPosts_Call struct {
+	*mock.Call
+}
+
+// CountPosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tagIDs []int64
+func (_e *Repository_Expecter) CountPosts(ctx interface{}, tagIDs interface{}) *Repository_CountPosts_Call {
+	return &Repository_CountPosts_Call{Call: _e.mock.On("CountPosts", ctx, tagIDs)}
+}
+
+func (_c *Repository_CountPosts_Call) Run(run func(ctx context.Context, tagIDs []int64)) *Repository_CountPosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64))
+	})
+	return _c
+}
+
+func (_c *Repository_CountPosts_Call) Return(_a0 map[int64]int64, _a1 error) *Repository_CountPosts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_CountPosts_Call) RunAndReturn(run func(context.Context, []int64) (map[int64]int64, error)) *Repository_CountPosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, name
 func (_m *Repository) Create(ctx context.Context, name string) (*model.Tag, error) {
 	ret := _m.Called(ctx, name)
@@ -81,6 +140,65 @@ func (_c *Repository_Create_Call) RunAndReturn(run func(context.Context, string)
 	return _c
 }
 
+// CreateMany provides a mock function with given fields: ctx, names
+func (_m *Repository) CreateMany(ctx context.Context, names []string) ([]*model.Tag, error) {
+	ret := _m.Called(ctx, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMany")
+	}
+
+	var r0 []*model.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*model.Tag, error)); ok {
+		return rf(ctx, names)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*model.Tag); ok {
+		r0 = rf(ctx, names)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, names)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_CreateMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMany'
+type Repository_CreateMany_Call struct {
+	*mock.Call
+}
+
+// CreateMany is a helper method to define mock.On call
+//   - ctx context.Context
+//   - names []string
+func (_e *Repository_Expecter) CreateMany(ctx interface{}, names interface{}) *Repository_CreateMany_Call {
+	return &Repository_CreateMany_Call{Call: _e.mock.On("CreateMany", ctx, names)}
+}
+
+func (_c *Repository_CreateMany_Call) Run(run func(ctx context.Context, names []string)) *Repository_CreateMany_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *Repository_CreateMany_Call) Return(_a0 []*model.Tag, _a1 error) *Repository_CreateMany_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_CreateMany_Call) RunAndReturn(run func(context.Context, []string) ([]*model.Tag, error)) *Repository_CreateMany_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteUnused provides a mock function with given fields: ctx
 func (_m *Repository) DeleteUnused(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -245,6 +363,291 @@ func (_c *Repository_FindByPost_Call) RunAndReturn(run func(context.Context, int
 	return _c
 }
 
+// FindByPosts provides a mock function with given fields: ctx, postIDs
+func (_m *Repository) FindByPosts(ctx context.Context, postIDs []int64) (map[int64][]*model.Tag, error) {
+	ret := _m.Called(ctx, postIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByPosts")
+	}
+
+	var r0 map[int64][]*model.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) (map[int64][]*model.Tag, error)); ok {
+		return rf(ctx, postIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64][]*model.Tag); ok {
+		r0 = rf(ctx, postIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64][]*model.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, postIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_FindByPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByPosts'
+type Repository_FindByPosts_Call struct {
+	*mock.Call
+}
+
+// FindByPosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postIDs []int64
+func (_e *Repository_Expecter) FindByPosts(ctx interface{}, postIDs interface{}) *Repository_FindByPosts_Call {
+	return &Repository_FindByPosts_Call{Call: _e.mock.On("FindByPosts", ctx, postIDs)}
+}
+
+func (_c *Repository_FindByPosts_Call) Run(run func(ctx context.Context, postIDs []int64)) *Repository_FindByPosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64))
+	})
+	return _c
+}
+
+func (_c *Repository_FindByPosts_Call) Return(_a0 map[int64][]*model.Tag, _a1 error) *Repository_FindByPosts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_FindByPosts_Call) RunAndReturn(run func(context.Context, []int64) (map[int64][]*model.Tag, error)) *Repository_FindByPosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPostIDsByTags provides a mock function with given fields: ctx, tagIDs
+func (_m *Repository) FindPostIDsByTags(ctx context.Context, tagIDs []int64) ([]int64, error) {
+	ret := _m.Called(ctx, tagIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPostIDsByTags")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]int64, error)); ok {
+		return rf(ctx, tagIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []int64); ok {
+		r0 = rf(ctx, tagIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, tagIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_FindPostIDsByTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPostIDsByTags'
+type Repository_FindPostIDsByTags_Call struct {
+	*mock.Call
+}
+
+// FindPostIDsByTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tagIDs []int64
+func (_e *Repository_Expecter) FindPostIDsByTags(ctx interface{}, tagIDs interface{}) *Repository_FindPostIDsByTags_Call {
+	return &Repository_FindPostIDsByTags_Call{Call: _e.mock.On("FindPostIDsByTags", ctx, tagIDs)}
+}
+
+func (_c *Repository_FindPostIDsByTags_Call) Run(run func(ctx context.Context, tagIDs []int64)) *Repository_FindPostIDsByTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64))
+	})
+	return _c
+}
+
+func (_c *Repository_FindPostIDsByTags_Call) Return(_a0 []int64, _a1 error) *Repository_FindPostIDsByTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_FindPostIDsByTags_Call) RunAndReturn(run func(context.Context, []int64) ([]int64, error)) *Repository_FindPostIDsByTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LinkPosts provides a mock function with given fields: ctx, links
+func (_m *Repository) LinkPosts(ctx context.Context, links []*model.PostTag) error {
+	ret := _m.Called(ctx, links)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkPosts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.PostTag) error); ok {
+		r0 = rf(ctx, links)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_LinkPosts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkPosts'
+type Repository_LinkPosts_Call struct {
+	*mock.Call
+}
+
+// LinkPosts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - links []*model.PostTag
+func (_e *Repository_Expecter) LinkPosts(ctx interface{}, links interface{}) *Repository_LinkPosts_Call {
+	return &Repository_LinkPosts_Call{Call: _e.mock.On("LinkPosts", ctx, links)}
+}
+
+func (_c *Repository_LinkPosts_Call) Run(run func(ctx context.Context, links []*model.PostTag)) *Repository_LinkPosts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*model.PostTag))
+	})
+	return _c
+}
+
+func (_c *Repository_LinkPosts_Call) Return(_a0 error) *Repository_LinkPosts_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_LinkPosts_Call) RunAndReturn(run func(context.Context, []*model.PostTag) error) *Repository_LinkPosts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Merge provides a mock function with given fields: ctx, sourceIDs, destID
+func (_m *Repository) Merge(ctx context.Context, sourceIDs []int64, destID int64) (*model.Tag, error) {
+	ret := _m.Called(ctx, sourceIDs, destID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 *model.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, int64) (*model.Tag, error)); ok {
+		return rf(ctx, sourceIDs, destID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, int64) *model.Tag); ok {
+		r0 = rf(ctx, sourceIDs, destID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64, int64) error); ok {
+		r1 = rf(ctx, sourceIDs, destID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_Merge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Merge'
+type Repository_Merge_Call struct {
+	*mock.Call
+}
+
+// Merge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sourceIDs []int64
+//   - destID int64
+func (_e *Repository_Expecter) Merge(ctx interface{}, sourceIDs interface{}, destID interface{}) *Repository_Merge_Call {
+	return &Repository_Merge_Call{Call: _e.mock.On("Merge", ctx, sourceIDs, destID)}
+}
+
+func (_c *Repository_Merge_Call) Run(run func(ctx context.Context, sourceIDs []int64, destID int64)) *Repository_Merge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_Merge_Call) Return(_a0 *model.Tag, _a1 error) *Repository_Merge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_Merge_Call) RunAndReturn(run func(context.Context, []int64, int64) (*model.Tag, error)) *Repository_Merge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Rename provides a mock function with given fields: ctx, id, name
+func (_m *Repository) Rename(ctx context.Context, id int64, name string) (*model.Tag, error) {
+	ret := _m.Called(ctx, id, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rename")
+	}
+
+	var r0 *model.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*model.Tag, error)); ok {
+		return rf(ctx, id, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *model.Tag); ok {
+		r0 = rf(ctx, id, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, id, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_Rename_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rename'
+type Repository_Rename_Call struct {
+	*mock.Call
+}
+
+// Rename is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - name string
+func (_e *Repository_Expecter) Rename(ctx interface{}, id interface{}, name interface{}) *Repository_Rename_Call {
+	return &Repository_Rename_Call{Call: _e.mock.On("Rename", ctx, id, name)}
+}
+
+func (_c *Repository_Rename_Call) Run(run func(ctx context.Context, id int64, name string)) *Repository_Rename_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_Rename_Call) Return(_a0 *model.Tag, _a1 error) *Repository_Rename_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_Rename_Call) RunAndReturn(run func(context.Context, int64, string) (*model.Tag, error)) *Repository_Rename_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ReplacePostTags provides a mock function with given fields: ctx, postID, newTags
 func (_m *Repository) ReplacePostTags(ctx context.Context, postID int64, newTags []string) error {
 	ret := _m.Called(ctx, postID, newTags)
@@ -293,6 +696,67 @@ func (_c *Repository_ReplacePostTags_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// SearchTags provides a mock function with given fields: ctx, prefix, limit, authorID
+func (_m *Repository) SearchTags(ctx context.Context, prefix string, limit int, authorID *int64) ([]*model.Tag, error) {
+	ret := _m.Called(ctx, prefix, limit, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTags")
+	}
+
+	var r0 []*model.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *int64) ([]*model.Tag, error)); ok {
+		return rf(ctx, prefix, limit, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, *int64) []*model.Tag); ok {
+		r0 = rf(ctx, prefix, limit, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, *int64) error); ok {
+		r1 = rf(ctx, prefix, limit, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_SearchTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchTags'
+type Repository_SearchTags_Call struct {
+	*mock.Call
+}
+
+// SearchTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+//   - limit int
+//   - authorID *int64
+func (_e *Repository_Expecter) SearchTags(ctx interface{}, prefix interface{}, limit interface{}, authorID interface{}) *Repository_SearchTags_Call {
+	return &Repository_SearchTags_Call{Call: _e.mock.On("SearchTags", ctx, prefix, limit, authorID)}
+}
+
+func (_c *Repository_SearchTags_Call) Run(run func(ctx context.Context, prefix string, limit int, authorID *int64)) *Repository_SearchTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(*int64))
+	})
+	return _c
+}
+
+func (_c *Repository_SearchTags_Call) Return(_a0 []*model.Tag, _a1 error) *Repository_SearchTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_SearchTags_Call) RunAndReturn(run func(context.Context, string, int, *int64) ([]*model.Tag, error)) *Repository_SearchTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // TagPost provides a mock function with given fields: ctx, postID, tagNames
 func (_m *Repository) TagPost(ctx context.Context, postID int64, tagNames []string) error {
 	ret := _m.Called(ctx, postID, tagNames)
@@ -341,6 +805,66 @@ func (_c *Repository_TagPost_Call) RunAndReturn(run func(context.Context, int64,
 	return _c
 }
 
+// TagPostExisting provides a mock function with given fields: ctx, postID, tagNames
+func (_m *Repository) TagPostExisting(ctx context.Context, postID int64, tagNames []string) ([]string, error) {
+	ret := _m.Called(ctx, postID, tagNames)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TagPostExisting")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) ([]string, error)); ok {
+		return rf(ctx, postID, tagNames)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) []string); ok {
+		r0 = rf(ctx, postID, tagNames)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []string) error); ok {
+		r1 = rf(ctx, postID, tagNames)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_TagPostExisting_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TagPostExisting'
+type Repository_TagPostExisting_Call struct {
+	*mock.Call
+}
+
+// TagPostExisting is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postID int64
+//   - tagNames []string
+func (_e *Repository_Expecter) TagPostExisting(ctx interface{}, postID interface{}, tagNames interface{}) *Repository_TagPostExisting_Call {
+	return &Repository_TagPostExisting_Call{Call: _e.mock.On("TagPostExisting", ctx, postID, tagNames)}
+}
+
+func (_c *Repository_TagPostExisting_Call) Run(run func(ctx context.Context, postID int64, tagNames []string)) *Repository_TagPostExisting_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *Repository_TagPostExisting_Call) Return(_a0 []string, _a1 error) *Repository_TagPostExisting_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_TagPostExisting_Call) RunAndReturn(run func(context.Context, int64, []string) ([]string, error)) *Repository_TagPostExisting_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UntagPost provides a mock function with given fields: ctx, postID, tagNames
 func (_m *Repository) UntagPost(ctx context.Context, postID int64, tagNames []string) error {
 	ret := _m.Called(ctx, postID, tagNames)
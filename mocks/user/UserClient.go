@@ -22,6 +22,65 @@ func (_m *Client) EXPECT() *Client_Expecter {
 	return &Client_Expecter{mock: &_m.Mock}
 }
 
+// GetBlockedUsers provides a mock function with given fields: ctx, userID
+func (_m *Client) GetBlockedUsers(ctx context.Context, userID int64) ([]int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockedUsers")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_GetBlockedUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBlockedUsers'
+type Client_GetBlockedUsers_Call struct {
+	*mock.Call
+}
+
+// GetBlockedUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *Client_Expecter) GetBlockedUsers(ctx interface{}, userID interface{}) *Client_GetBlockedUsers_Call {
+	return &Client_GetBlockedUsers_Call{Call: _e.mock.On("GetBlockedUsers", ctx, userID)}
+}
+
+func (_c *Client_GetBlockedUsers_Call) Run(run func(ctx context.Context, userID int64)) *Client_GetBlockedUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Client_GetBlockedUsers_Call) Return(_a0 []int64, _a1 error) *Client_GetBlockedUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_GetBlockedUsers_Call) RunAndReturn(run func(context.Context, int64) ([]int64, error)) *Client_GetBlockedUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetUser provides a mock function with given fields: ctx, id
 func (_m *Client) GetUser(ctx context.Context, id int64) (*model.User, error) {
 	ret := _m.Called(ctx, id)